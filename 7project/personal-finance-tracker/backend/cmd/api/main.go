@@ -3,90 +3,836 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 
+	"pft/internal/alerts"
+	"pft/internal/apidoc"
+	"pft/internal/backup"
+	"pft/internal/blob"
+	"pft/internal/cache"
+	"pft/internal/chathook"
+	"pft/internal/crypt"
+	"pft/internal/errtrack"
+	"pft/internal/exports"
+	"pft/internal/flags"
+	"pft/internal/gql"
 	"pft/internal/handler"
+	"pft/internal/imports"
+	"pft/internal/ingest"
+	"pft/internal/jobs"
+	"pft/internal/mailer"
+	"pft/internal/metrics"
+	"pft/internal/outbox"
 	"pft/internal/platform"
+	"pft/internal/push"
+	"pft/internal/ratelimit"
+	"pft/internal/rates"
 	"pft/internal/repo"
+	"pft/internal/telegram"
+	"pft/internal/usage"
+	"pft/internal/webhook"
 )
 
 func main() {
-	// Load config (PORT, DB_DSN, JWT_SECRET)
-	cfg := platform.Load()
+	// Load config (CONFIG_FILE merged with PORT, DB_DSN, JWT_SECRET, ...)
+	cfg, err := platform.Load()
+	if err != nil {
+		fatal("config", err)
+	}
 
-	// --- DB pool ---
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	// --- Structured logging ---
+	logger := platform.NewLogger(os.Stdout, cfg.LogLevel, cfg.LogFormat)
+	slog.SetDefault(logger)
+
+	// --- Error reporting (Sentry) ---
+	var errs *errtrack.Client
+	if cfg.SentryDSN != "" {
+		if errs, err = errtrack.New(cfg.SentryDSN, cfg.SentryEnvironment, platform.Version); err != nil {
+			fatal("config", err)
+		}
+	}
 
+	// --- DB pool ---
 	pcfg, err := pgxpool.ParseConfig(cfg.DB_DSN)
 	if err != nil {
-		log.Fatalf("pgx parse config: %v", err)
+		fatal("pgx parse config", err)
+	}
+	// Log failed and slow queries with the request ID of the originating HTTP request,
+	// and record query durations in the metrics.
+	slow, _ := time.ParseDuration(cfg.SlowQueryThreshold)
+	tracer := &repo.QueryLogger{Logger: logger, SlowThreshold: slow}
+	pcfg.ConnConfig.Tracer = tracer
+	// Pool sizing from config; validated by platform.Load, zero values keep the pgxpool defaults.
+	maxConns, _ := strconv.ParseInt(cfg.DBMaxConns, 10, 32)
+	minConns, _ := strconv.ParseInt(cfg.DBMinConns, 10, 32)
+	lifetime, _ := time.ParseDuration(cfg.DBMaxConnLifetime)
+	healthCheck, _ := time.ParseDuration(cfg.DBHealthCheckPeriod)
+	statementTimeout, _ := time.ParseDuration(cfg.DBStatementTimeout)
+	poolOpts := platform.PoolOptions{
+		MaxConns: int32(maxConns), MinConns: int32(minConns), MaxConnLifetime: lifetime, HealthCheckPeriod: healthCheck,
+		StatementTimeout: statementTimeout,
+	}
+	poolOpts.Apply(pcfg)
+	// Row-level security: each query runs with app.user_id set to the requesting user.
+	rowSecurity, _ := strconv.ParseBool(cfg.DBRowSecurity)
+	if rowSecurity {
+		pcfg.PrepareConn = repo.PrepareTenant
 	}
 
-	pool, err := pgxpool.NewWithConfig(ctx, pcfg)
+	// Postgres may still be starting (e.g. under docker-compose); retry with backoff.
+	attempts, _ := strconv.Atoi(cfg.DBConnectAttempts)
+	backoff, _ := time.ParseDuration(cfg.DBConnectBackoff)
+	pool, err := platform.ConnectDB(context.Background(), pcfg, attempts, backoff)
 	if err != nil {
-		log.Fatalf("pgxpool new: %v", err)
+		fatal("db connect", err)
 	}
 	defer pool.Close()
+	if explain, _ := strconv.ParseBool(cfg.DBExplainSlow); explain {
+		tracer.ExplainPool = pool
+		logger.Warn("EXPLAIN of slow queries enabled")
+	}
 
-	if err := pool.Ping(ctx); err != nil {
-		log.Fatalf("db ping: %v", err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// --- Read replica (optional; falls back to the primary whenever it is unreachable) ---
+	var replica *pgxpool.Pool
+	if cfg.DBReadDSN != "" {
+		rcfg, err := pgxpool.ParseConfig(cfg.DBReadDSN)
+		if err != nil {
+			fatal("pgx parse read config", err)
+		}
+		rcfg.ConnConfig.Tracer = pcfg.ConnConfig.Tracer
+		rcfg.PrepareConn = pcfg.PrepareConn
+		poolOpts.Apply(rcfg)
+		if replica, err = pgxpool.NewWithConfig(context.Background(), rcfg); err != nil {
+			fatal("read replica", err)
+		}
+		defer replica.Close()
 	}
 
 	// --- Migrations ---
-	if err := platform.RunMigrations(ctx, pool, "/migrations"); err != nil {
-		log.Fatalf("migrate: %v", err)
+	// Replicas starting together take turns: the others wait (up to MIGRATE_LOCK_TIMEOUT)
+	// for the first to finish, then find nothing left to apply.
+	lockTimeout, _ := time.ParseDuration(cfg.MigrateLockTimeout)
+	migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), lockTimeout)
+	err = platform.RunMigrations(migrateCtx, pool, "/migrations")
+	cancelMigrate()
+	if err != nil {
+		fatal("migrate", err)
+	}
+
+	if rowSecurity {
+		if bypass, err := repo.New(pool).BypassesRowSecurity(ctx); err == nil && bypass {
+			logger.Warn("database role is a superuser or has BYPASSRLS; row-level security is not enforced")
+		}
+	}
+
+	// --- Redis (optional; shared state across instances) ---
+	var rdb *redis.Client
+	if cfg.RedisURL != "" {
+		opts, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			fatal("redis parse url", err)
+		}
+		rdb = redis.NewClient(opts)
+		defer rdb.Close()
+		if err := rdb.Ping(ctx).Err(); err != nil {
+			fatal("redis ping", err)
+		}
 	}
 
 	// --- Dependencies ---
 	store := repo.New(pool)
+	if replica != nil {
+		store.UseReplica(replica)
+	}
+	switch {
+	case cfg.EncryptionKey != "":
+		key, err := crypt.NewMasterKey(cfg.EncryptionKey)
+		if err != nil {
+			fatal("config", err)
+		}
+		store.UseEncryption(key)
+	case cfg.KMSKeyID != "":
+		store.UseEncryption(&crypt.KMS{KeyID: cfg.KMSKeyID, Region: cfg.KMSRegion, AccessKeyID: cfg.KMSAccessKeyID,
+			SecretAccessKey: cfg.KMSSecretAccessKey})
+	}
 	api := handler.New(store, cfg.JWTSecret)
+	api.Flags = &flags.Set{Store: store.FlagRepo()}
+
+	// Response cache for read-heavy aggregates; any successful write by a user drops their
+	// entries: HTTP writes through InvalidateCache, the others through invalidate.
+	var respCache cache.Store
+	if ttl, _ := time.ParseDuration(cfg.CacheTTL); rdb != nil && ttl > 0 {
+		respCache = &cache.Redis{Client: rdb, Prefix: "pft:cache:", TTL: ttl}
+	}
+	invalidate := handler.Invalidator(respCache)
+
+	// --- Blob storage (exports, backups) ---
+	var blobs, backups blob.Store = &blob.Local{Dir: cfg.BlobDir}, &blob.Local{Dir: cfg.BackupDir}
+	if cfg.BlobStore == "s3" {
+		pathStyle, _ := strconv.ParseBool(cfg.S3PathStyle)
+		signed, _ := strconv.ParseBool(cfg.S3SignedURLs)
+		s3 := &blob.S3{Endpoint: cfg.S3Endpoint, Region: cfg.S3Region, Bucket: cfg.S3Bucket, Prefix: cfg.S3Prefix,
+			AccessKeyID: cfg.S3AccessKeyID, SecretAccessKey: cfg.S3SecretAccessKey,
+			PathStyle: pathStyle, SignedURLs: signed}
+		bs := *s3
+		bs.Prefix += "backups/"
+		blobs, backups = s3, &bs
+		logger.Info("blob storage", "store", s3.String())
+	}
+	api.Blobs = blobs
+
+	// --- Background jobs ---
+	// The worker runs queued jobs in-process; JOBS_CONCURRENCY=0 leaves the queue to
+	// other instances, and periodic work then falls back to plain in-process loops.
+	// Periodic jobs are enqueued on cron schedules (UTC), which JOB_SCHEDULES can override.
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	concurrency, _ := strconv.Atoi(cfg.JobsConcurrency)
+	worker := &jobs.Worker{Store: store.JobRepo(), Concurrency: concurrency, Errors: errs}
+	overrides, _ := platform.JobSchedules(cfg.JobSchedules)
+	scheduler := &jobs.Scheduler{Store: store.JobRepo(), Overrides: overrides}
+	schedule := func(kind, spec string) {
+		if err := scheduler.Add(kind, spec); err != nil {
+			fatal("config", err)
+		}
+	}
+
+	var ratesJob *rates.Job // set when a worker refreshes rates, for backfills
+	if cfg.RatesProvider == "none" {
+		logger.Info("exchange rate refresh disabled")
+	} else {
+		provider, err := rates.New(cfg.RatesProvider, cfg.RatesAPIKey, cfg.RatesFile)
+		if err != nil {
+			fatal("config", err)
+		}
+		job := &rates.Job{Provider: provider, Store: store.RateRepo(), Base: cfg.RatesBase}
+		if concurrency > 0 {
+			worker.Register("rates.refresh", func(ctx context.Context, _ *repo.Job) error { return job.Refresh(ctx) })
+			schedule("rates.refresh", "0 6 * * *")
+			worker.Register(rates.BackfillJobKind, job.HandleBackfill)
+			ratesJob = job
+		} else {
+			go job.Run(jobsCtx)
+		}
+	}
+
+	// Monthly transaction partitions for the coming year; writes create missing ones on
+	// demand, so without a worker nothing else is needed.
+	if concurrency > 0 {
+		worker.Register("transactions.partitions", func(ctx context.Context, _ *repo.Job) error {
+			n, err := store.TransactionRepo().EnsurePartitions(ctx, time.Now())
+			if n > 0 {
+				logger.Info("created transaction partitions", "count", n)
+			}
+			return err
+		})
+		schedule("transactions.partitions", "0 1 * * *")
+	}
+
+	// Active recurring rules create their transactions once a day; each run catches up on
+	// every occurrence since the last, so missed days are filled in.
+	if concurrency > 0 {
+		worker.Register("recurring.generate", func(ctx context.Context, _ *repo.Job) error {
+			created, err := store.GenerateRecurring(ctx, time.Now())
+			n := 0
+			for userID, count := range created {
+				invalidate(ctx, userID)
+				n += count
+			}
+			if n > 0 {
+				logger.Info("generated recurring transactions", "count", n)
+			}
+			return err
+		})
+		schedule("recurring.generate", "5 0 * * *")
+	}
+
+	// Descriptions stored before encryption was enabled are encrypted in batches; the
+	// daily run also catches rows written by instances still running without a key.
+	if concurrency > 0 && (cfg.EncryptionKey != "" || cfg.KMSKeyID != "") {
+		worker.Register("transactions.encrypt", func(ctx context.Context, _ *repo.Job) error {
+			total := 0
+			for {
+				n, err := store.TransactionRepo().EncryptPlaintext(ctx, 500)
+				total += n
+				if err != nil || n == 0 {
+					if total > 0 {
+						logger.Info("encrypted transaction descriptions", "count", total)
+					}
+					return err
+				}
+			}
+		})
+		schedule("transactions.encrypt", "0 3 * * *")
+	}
+
+	// Purge soft-deleted categories and budgets once they are past the restore window.
+	if retention, _ := time.ParseDuration(cfg.SoftDeleteRetention); concurrency > 0 && retention > 0 {
+		worker.Register("trash.purge", func(ctx context.Context, _ *repo.Job) error {
+			n, err := store.TrashRepo().Purge(ctx, time.Now().Add(-retention))
+			if err == nil && n["budgets"]+n["categories"] > 0 {
+				logger.Info("purged deleted rows", "budgets", n["budgets"], "categories", n["categories"])
+			}
+			return err
+		})
+		schedule("trash.purge", "0 4 * * *")
+	}
+
+	// Retention policies set by users and the operator delete old data once a day.
+	if concurrency > 0 {
+		worker.Register("retention.apply", func(ctx context.Context, _ *repo.Job) error {
+			policies, err := store.RetentionRepo().All(ctx)
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			for _, p := range policies {
+				res, err := store.RetentionRepo().Apply(ctx, p, now, false)
+				if err != nil {
+					return err
+				}
+				// Operator policies only purge trash, drafts and jobs, which no cached response reads.
+				if res.Rows > 0 && p.UserID != nil {
+					invalidate(ctx, *p.UserID)
+					logger.Info("applied retention policy", "target", p.Target, "user_id", *p.UserID, "rows", res.Rows)
+				} else if res.Rows > 0 {
+					logger.Info("applied operator retention policy", "target", p.Target, "rows", res.Rows)
+				}
+			}
+			return nil
+		})
+		schedule("retention.apply", "15 5 * * *")
+	}
+
+	// Outgoing email is queued as jobs and sent by the worker, which retries failures.
+	var sender mailer.Sender
+	switch cfg.MailProvider {
+	case "smtp":
+		smtp, err := mailer.ParseSMTPURL(cfg.SMTPURL)
+		if err != nil {
+			fatal("config", err)
+		}
+		sender = smtp
+	case "ses":
+		sender = &mailer.SES{Region: cfg.SESRegion, AccessKeyID: cfg.SESAccessKeyID, SecretAccessKey: cfg.SESSecretAccessKey}
+	case "sendgrid":
+		sender = &mailer.SendGrid{APIKey: cfg.SendGridAPIKey}
+	default:
+		sender = mailer.Log{}
+		logger.Info("email is logged, not sent", "mail_provider", cfg.MailProvider)
+	}
+	mail := &mailer.Mailer{Sender: sender, From: cfg.MailFrom, Queue: store.JobRepo()}
+	if concurrency > 0 {
+		worker.Register(mailer.JobKind, mail.Handle)
+	}
+
+	// Change events queued by the database are delivered by the outbox dispatcher,
+	// which runs alongside the worker; delivered events are kept a week.
+	dispatcher := &outbox.Dispatcher{Store: store.OutboxRepo()}
+	if concurrency > 0 {
+		worker.Register("outbox.cleanup", func(ctx context.Context, _ *repo.Job) error {
+			_, err := store.OutboxRepo().Cleanup(ctx, time.Now().Add(-7*24*time.Hour))
+			return err
+		})
+		schedule("outbox.cleanup", "30 4 * * *")
+	}
+
+	// Change events also go to users' webhook endpoints: the dispatcher queues a
+	// delivery per endpoint and the deliverer sends, signs and retries them. Delivery
+	// logs, dead letters included, are kept 30 days.
+	deliverer := &webhook.Deliverer{Store: store.WebhookRepo()}
+	if concurrency > 0 {
+		dispatcher.Subscribe("webhooks", store.WebhookRepo().Enqueue)
+		worker.Register("webhooks.cleanup", func(ctx context.Context, _ *repo.Job) error {
+			_, err := store.WebhookRepo().Cleanup(ctx, time.Now().AddDate(0, 0, -30))
+			return err
+		})
+		schedule("webhooks.cleanup", "45 4 * * *")
+	}
+
+	// CSV imports are queued by the API and run by the worker; finished ones raise an
+	// alert (see below).
+	var importer *imports.Importer
+	if concurrency > 0 {
+		importer = &imports.Importer{Store: store.ImportRepo(), Categories: store.CategoryRepo(),
+			Transactions: store.TransactionRepo(), Drafts: store.DraftRepo(),
+			Tx: func(ctx context.Context, fn func(imports.Store, imports.Transactions, imports.Drafts) error) error {
+				return store.WithTx(ctx, func(tx *repo.Store) error { return fn(tx.ImportRepo(), tx.TransactionRepo(), tx.DraftRepo()) })
+			}, OnWrite: invalidate}
+		worker.Register(imports.JobKind, importer.Handle)
+	}
+
+	// Exports are built by the worker and kept for a day for download.
+	if concurrency > 0 {
+		exporter := &exports.Exporter{Store: store.ExportRepo(), Dumps: store.BackupRepo(), Blobs: blobs}
+		worker.Register(exports.JobKind, exporter.Handle)
+		worker.Register("exports.cleanup", func(ctx context.Context, _ *repo.Job) error {
+			return exporter.Cleanup(ctx, time.Now().Add(-24*time.Hour))
+		})
+		schedule("exports.cleanup", "@hourly")
+	}
+
+	// Language of texts sent to a user outside a request (alerts, bot replies).
+	userLocale := func(ctx context.Context, userID int64) string {
+		if p, err := store.UserRepo().GetPreferences(ctx, userID); err == nil && p != nil && p.Locale != nil {
+			return *p.Locale
+		}
+		return ""
+	}
+	var tg *telegram.Client
+	if cfg.TelegramBotToken != "" {
+		tg = &telegram.Client{Token: cfg.TelegramBotToken}
+	}
+
+	// Alerts (budget thresholds, unusual expenses, bill reminders, weekly summaries,
+	// finished imports) are raised from outbox events, a daily job and the importer,
+	// filed in the in-app notification center, mailed, and pushed to registered devices,
+	// linked Telegram chats and Slack/Discord webhooks, as each user chose and outside
+	// their quiet hours. Platforms without credentials only log their pushes.
+	if concurrency > 0 {
+		senders := map[string]push.Sender{"fcm": push.Log{}, "apns": push.Log{}}
+		if cfg.FCMCredentialsFile != "" {
+			fcm, err := push.LoadFCM(cfg.FCMCredentialsFile)
+			if err != nil {
+				fatal("config", err)
+			}
+			senders["fcm"] = fcm
+		}
+		if cfg.APNsKeyFile != "" {
+			key, err := push.LoadAPNsKey(cfg.APNsKeyFile)
+			if err != nil {
+				fatal("config", err)
+			}
+			sandbox, _ := strconv.ParseBool(cfg.APNsSandbox)
+			senders["apns"] = &push.APNs{KeyID: cfg.APNsKeyID, TeamID: cfg.APNsTeamID, Topic: cfg.APNsTopic, Key: key, Sandbox: sandbox}
+		}
+		notifier := &alerts.Notifier{Store: store.AlertRepo(), Queue: store.JobRepo()}
+		notifier.AddChannel(&alerts.Inbox{Notifications: store.NotificationRepo()})
+		notifier.AddChannel(&mailer.Channel{Users: store.UserRepo(), Mailer: mail, Locale: userLocale})
+		notifier.AddChannel(&push.Channel{Devices: store.DeviceRepo(), Senders: senders})
+		if tg != nil {
+			notifier.AddChannel(&telegram.Channel{Links: store.TelegramRepo(), Sender: tg})
+		}
+		for _, kind := range chathook.Kinds {
+			notifier.AddChannel(&chathook.Channel{Kind: kind, Hooks: store.ChatWebhookRepo()})
+		}
+		detector := &alerts.Detector{Source: store.AlertRepo(), Notifier: notifier, Locale: userLocale}
+		dispatcher.Subscribe("alerts", detector.OnEvent)
+		importer.OnFinish = detector.ImportFinished
+		worker.Register(alerts.JobKind, notifier.Handle)
+		worker.Register("alerts.daily", func(ctx context.Context, _ *repo.Job) error {
+			if err := detector.BillReminders(ctx, time.Now().UTC()); err != nil {
+				return err
+			}
+			if err := detector.WeeklySummaries(ctx, time.Now().UTC()); err != nil {
+				return err
+			}
+			if _, err := store.NotificationRepo().Cleanup(ctx, time.Now().AddDate(-1, 0, 0)); err != nil {
+				return err
+			}
+			_, err := store.AlertRepo().CleanupSent(ctx, time.Now().AddDate(-1, 0, 0))
+			return err
+		})
+		schedule("alerts.daily", "0 8 * * *")
+	}
+
+	// Telegram bot: updates arrive at the webhook when one is configured, otherwise
+	// this instance polls for them.
+	var bot *telegram.Bot
+	if tg != nil {
+		bot = &telegram.Bot{Sender: tg, Links: store.TelegramRepo(), Categories: store.CategoryRepo(),
+			Transactions: store.TransactionRepo(), Locale: userLocale, OnWrite: invalidate}
+		if cfg.TelegramWebhookURL != "" {
+			if err := tg.SetWebhook(ctx, cfg.TelegramWebhookURL, cfg.TelegramWebhookSecret); err != nil {
+				logger.Error("telegram webhook registration failed", "error", err.Error())
+			}
+		} else {
+			go bot.Poll(jobsCtx, tg)
+		}
+		api.TelegramBot = cfg.TelegramBotName
+	}
+
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		if concurrency > 0 {
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				dispatcher.Run(jobsCtx)
+			}()
+			go func() {
+				defer wg.Done()
+				deliverer.Run(jobsCtx)
+			}()
+			worker.Run(jobsCtx)
+			wg.Wait()
+		}
+	}()
 
 	// --- HTTP server (Gin) ---
 	r := gin.New()
-	r.Use(gin.Logger(), gin.Recovery())
+	r.Use(handler.RequestID(), handler.AccessLog(logger), handler.Recovery(logger, errs), handler.ReportErrors(errs))
 	_ = r.SetTrustedProxies(nil)
+	// Settings below were validated by platform.Load, so parse errors are impossible.
+	if on, _ := strconv.ParseBool(cfg.SecurityHeaders); on {
+		hsts, _ := strconv.Atoi(cfg.HSTSMaxAge)
+		r.Use(handler.SecurityHeaders(hsts))
+	}
+	// Cache-Control per route class; see handler.RouteCache.
+	privateMaxAge, _ := time.ParseDuration(cfg.HTTPCacheMaxAge)
+	r.Use(handler.CacheControl(handler.CachePolicy{PrivateMaxAge: privateMaxAge, PublicMaxAge: 5 * time.Minute}))
+	// Bound each request's queries; past the deadline they are cancelled and it answers 504.
+	requestTimeout, _ := time.ParseDuration(cfg.RequestTimeout)
+	r.Use(handler.RequestTimeout(requestTimeout))
+	if minSize, _ := strconv.Atoi(cfg.CompressMinBytes); minSize > 0 {
+		r.Use(handler.Compress(minSize))
+	}
+	if maxBody, _ := strconv.ParseInt(cfg.MaxBodyBytes, 10, 64); maxBody > 0 {
+		r.Use(handler.LimitBody(maxBody))
+	}
+	if on, _ := strconv.ParseBool(cfg.RequireJSON); on {
+		r.Use(handler.RequireJSON("/api/mail/inbound"))
+	}
+	if origins := platform.SplitList(cfg.CORSOrigins); len(origins) > 0 {
+		credentials, _ := strconv.ParseBool(cfg.CORSCredentials)
+		r.Use(handler.CORS(handler.CORSConfig{
+			AllowedOrigins:   origins,
+			AllowedMethods:   platform.SplitList(cfg.CORSMethods),
+			AllowedHeaders:   platform.SplitList(cfg.CORSHeaders),
+			AllowCredentials: credentials,
+			MaxAge:           10 * time.Minute,
+		}))
+	}
+
+	// Maintenance mode: 503 for everything but probes, metrics and the admin API.
+	forceMaintenance, _ := strconv.ParseBool(cfg.MaintenanceMode)
+	maintenance := &handler.MaintenanceSwitch{Store: store.MaintenanceRepo(), Force: forceMaintenance}
+	r.Use(handler.Maintenance(maintenance))
+	if forceMaintenance {
+		logger.Warn("maintenance mode forced by configuration")
+	}
 
 	// Public endpoints
 	r.GET("/api/healthz", api.Healthz)
+	r.GET("/api/readyz", api.Readyz)
+	r.GET("/api/version", api.Version)
+	if on, _ := strconv.ParseBool(cfg.MetricsEnabled); on {
+		r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	}
 	r.POST("/api/register", api.Register)
 	r.POST("/api/login", api.Login)
+	r.POST("/api/oauth/token", api.OAuthToken)
+	r.GET("/api/exports/:id/download", api.DownloadExport)
+	if bot != nil && cfg.TelegramWebhookURL != "" {
+		r.POST("/api/telegram/webhook", handler.TelegramWebhook(bot, cfg.TelegramWebhookSecret))
+	}
+	// Email-in: the mail provider posts messages sent to users' ingest addresses.
+	if cfg.IngestDomain != "" {
+		api.IngestDomain = cfg.IngestDomain
+		recv := &ingest.Receiver{Domain: api.IngestDomain, Addresses: store.IngestRepo(), Drafts: store.DraftRepo()}
+		r.POST("/api/mail/inbound", handler.MailInbound(recv, cfg.IngestSecret))
+	}
+
+	// API usage per user is counted in memory and stored every minute; counts older
+	// than a year are deleted.
+	usageRec := &usage.Recorder{Store: store.UsageRepo()}
+	go usageRec.Run(jobsCtx)
+	if concurrency > 0 {
+		worker.Register("usage.cleanup", func(ctx context.Context, _ *repo.Job) error {
+			_, err := store.UsageRepo().Cleanup(ctx, time.Now().AddDate(-1, 0, 0))
+			return err
+		})
+		schedule("usage.cleanup", "45 4 * * *")
+		go scheduler.Run(jobsCtx)
+	}
 
 	// Authenticated endpoints
 	authMw := handler.JWTMiddleware(handler.AuthConfig{JWTSecret: cfg.JWTSecret})
-	auth := r.Group("/api", authMw)
+	keyAuth := handler.APIKeyMiddleware(store.APIKeyRepo())
+	// Third-party apps call the API with OAuth access tokens, limited to their scopes like API keys.
+	authMw = handler.OAuthOrJWT(store.OAuthRepo(), authMw)
+	auth := r.Group("/api", handler.KeyOrJWT(keyAuth, authMw), handler.TrackUsage(usageRec))
+	// Polling triggers for Zapier/IFTTT, authenticated by API key instead of JWT.
+	zapier := r.Group("/api/zapier", keyAuth, handler.TrackUsage(usageRec))
+	if perMinute, _ := strconv.Atoi(cfg.RateLimitPerMinute); perMinute > 0 {
+		var limiter ratelimit.Limiter = ratelimit.NewMemory()
+		if rdb != nil {
+			limiter = &ratelimit.Redis{Client: rdb, Prefix: "pft:ratelimit:"}
+		}
+		auth.Use(handler.RateLimit(limiter, perMinute))
+		zapier.Use(handler.RateLimit(limiter, perMinute))
+	}
+	zapier.GET("/me", api.ZapierMe)
+	zapier.GET("/triggers/new-transaction", api.NewTransactionsTrigger)
+	zapier.GET("/triggers/budget-exceeded", api.BudgetExceededTrigger)
+
+	// Label writes with the user and request ID for the audit log.
+	auth.Use(handler.Audit(""))
+	auth.Use(handler.InvalidateCache(respCache))
+	cached := handler.CacheResponses(respCache)
+	// ETags let polling clients revalidate with If-None-Match and get 304 when nothing changed.
+	etag := handler.ConditionalGET()
 
 	// Me
 	auth.GET("/me", api.Me)
+	auth.GET("/me/preferences", api.GetPreferences)
+	auth.PUT("/me/preferences", api.UpdatePreferences)
+	auth.GET("/me/features", api.Features)
+	auth.GET("/me/usage", api.GetUsage)
+	auth.GET("/me/notifications", api.GetNotificationPrefs)
+	auth.PUT("/me/notifications", api.UpdateNotificationPrefs)
+	auth.GET("/me/notifications/quiet-hours", api.GetQuietHours)
+	auth.PUT("/me/notifications/quiet-hours", api.UpdateQuietHours)
+	auth.GET("/devices", api.ListDevices)
+	auth.POST("/devices", api.RegisterDevice)
+	auth.DELETE("/devices/:id", api.DeleteDevice)
+	auth.GET("/notifications", api.ListNotifications)
+	auth.GET("/notifications/unread", api.UnreadNotifications)
+	auth.PATCH("/notifications", api.MarkNotifications)
+	auth.PATCH("/notifications/:id", api.MarkNotification)
+	auth.GET("/me/telegram", api.GetTelegram)
+	auth.POST("/me/telegram/link", api.CreateTelegramLink)
+	auth.DELETE("/me/telegram", api.DeleteTelegram)
+	auth.GET("/me/api-keys", api.ListAPIKeys)
+	auth.POST("/me/api-keys", api.CreateAPIKey)
+	auth.DELETE("/me/api-keys/:id", api.DeleteAPIKey)
+	auth.GET("/me/oauth/clients", api.ListOAuthClients)
+	auth.POST("/me/oauth/clients", api.CreateOAuthClient)
+	auth.DELETE("/me/oauth/clients/:id", api.DeleteOAuthClient)
+	auth.GET("/me/oauth/grants", api.ListOAuthGrants)
+	auth.DELETE("/me/oauth/grants/:client_id", api.RevokeOAuthGrant)
+	// Consent screen: the frontend shows what the app asks for, then posts the user's answer.
+	auth.GET("/oauth/authorize", api.OAuthConsent)
+	auth.POST("/oauth/authorize", api.OAuthAuthorize)
+	auth.GET("/me/ingest-address", api.GetIngestAddress)
+	auth.POST("/me/ingest-address", api.CreateIngestAddress)
+	auth.GET("/chat-webhooks", api.ListChatWebhooks)
+	auth.POST("/chat-webhooks", api.CreateChatWebhook)
+	auth.PUT("/chat-webhooks/:id", api.UpdateChatWebhook)
+	auth.DELETE("/chat-webhooks/:id", api.DeleteChatWebhook)
+	auth.POST("/chat-webhooks/:id/test", api.TestChatWebhook)
+	auth.GET("/webhooks", api.ListWebhooks)
+	auth.POST("/webhooks", api.CreateWebhook)
+	auth.GET("/webhooks/dead-letters", api.ListWebhookDeadLetters)
+	auth.PUT("/webhooks/:id", api.UpdateWebhook)
+	auth.DELETE("/webhooks/:id", api.DeleteWebhook)
+	auth.GET("/webhooks/:id/deliveries", api.ListWebhookDeliveries)
+	auth.POST("/webhooks/:id/deliveries/:delivery_id/replay", api.ReplayWebhookDelivery)
+	auth.GET("/audit", api.ListAudit)
+	auth.GET("/retention-policies", api.ListRetentionPolicies)
+	auth.GET("/retention-policies/preview", api.PreviewRetention)
+	auth.PUT("/retention-policies/:target", api.PutRetentionPolicy)
+	auth.DELETE("/retention-policies/:target", api.DeleteRetentionPolicy)
 
 	// Categories
-	auth.GET("/categories", api.ListCategories)
+	auth.GET("/categories", etag, api.ListCategories)
 	auth.POST("/categories", api.CreateCategory)
 	auth.PUT("/categories/:id", api.UpdateCategory)
 	auth.DELETE("/categories/:id", api.DeleteCategory)
+	auth.POST("/categories/:id/restore", api.RestoreCategory)
+	auth.GET("/category-groups", api.ListCategoryGroups)
+	auth.POST("/category-groups", api.CreateCategoryGroup)
+	auth.PUT("/category-groups/:id", api.RenameCategoryGroup)
+	auth.DELETE("/category-groups/:id", api.DeleteCategoryGroup)
+
+	// Several API calls in one round trip, optionally atomic
+	auth.POST("/batch", handler.Batch(r, store))
+
+	// Delta sync for offline clients
+	auth.GET("/sync", api.Sync)
+	auth.POST("/sync", api.SyncWrite)
 
 	// Transactions
 	auth.GET("/transactions", api.ListTransactions)
 	auth.POST("/transactions", api.CreateTransaction)
+	auth.POST("/transactions/parse", api.ParseTransaction)
 	auth.PUT("/transactions/:id", api.UpdateTransaction)
 	auth.DELETE("/transactions/:id", api.DeleteTransaction)
+	auth.GET("/accounts", api.ListAccounts)
+	auth.POST("/accounts", api.CreateAccount)
+	auth.PUT("/accounts/:id", api.UpdateAccount)
+	auth.DELETE("/accounts/:id", api.DeleteAccount)
+	auth.GET("/accounts/:id/statement", api.AccountStatement)
+	auth.GET("/drafts", api.ListDrafts)
+	auth.PUT("/drafts/:id", api.UpdateDraft)
+	auth.POST("/drafts/:id/approve", api.ApproveDraft)
+	auth.POST("/drafts/approve", api.ApproveDrafts)
+	auth.DELETE("/drafts/:id", api.DiscardDraft)
+	auth.POST("/imports", api.CreateImport)
+	auth.GET("/imports/:id", api.GetImport)
+	auth.POST("/imports/:id/cancel", api.CancelImport)
+	auth.POST("/exports", api.CreateExport)
+	auth.GET("/exports/:id", api.GetExport)
+
+	// Shared expenses
+	auth.GET("/contacts", api.ListContacts)
+	auth.POST("/contacts", api.CreateContact)
+	auth.DELETE("/contacts/:id", api.DeleteContact)
+	auth.GET("/transactions/:id/split", api.GetSplit)
+	auth.PUT("/transactions/:id/split", api.PutSplit)
+	auth.DELETE("/transactions/:id/split", api.DeleteSplit)
+	auth.GET("/splits/balances", api.SplitBalances)
+	auth.POST("/settlements", api.CreateSettlement)
 
 	// Budgets
-	auth.GET("/budgets", api.ListBudgets)
+	auth.GET("/budgets", etag, api.ListBudgets)
 	auth.POST("/budgets", api.CreateBudget)
 	auth.PUT("/budgets/:id", api.UpdateBudget)
 	auth.DELETE("/budgets/:id", api.DeleteBudget)
+	auth.POST("/budgets/:id/restore", api.RestoreBudget)
+
+	// Period locking
+	auth.GET("/periods/closed", api.ListClosedPeriods)
+	auth.POST("/periods/:month/close", api.ClosePeriod)
+	auth.POST("/periods/:month/reopen", api.ReopenPeriod)
+
+	// Loans
+	auth.GET("/loans", api.ListLoans)
+	auth.POST("/loans", api.CreateLoan)
+	auth.PUT("/loans/:id", api.UpdateLoan)
+	auth.DELETE("/loans/:id", api.DeleteLoan)
+	auth.GET("/loans/:id/schedule", api.LoanSchedule)
+	auth.GET("/loans/:id/payoff", api.LoanPayoff)
+	auth.GET("/loans/:id/payments", api.ListLoanPayments)
+	auth.POST("/loans/:id/payments", api.AddLoanPayment)
+	auth.DELETE("/loans/:id/payments/:txid", api.RemoveLoanPayment)
+
+	// Bills
+	auth.GET("/bills", api.ListBills)
+	auth.GET("/bills/upcoming", api.UpcomingBills)
+	auth.POST("/bills", api.CreateBill)
+	auth.PUT("/bills/:id", api.UpdateBill)
+	auth.DELETE("/bills/:id", api.DeleteBill)
+
+	// Subscriptions
+	auth.GET("/subscriptions", api.ListSubscriptions)
+	auth.POST("/subscriptions", api.CreateSubscription)
+	auth.POST("/subscriptions/detect", api.ImportDetectedSubscriptions)
+	auth.PUT("/subscriptions/:id", api.UpdateSubscription)
+	auth.DELETE("/subscriptions/:id", api.DeleteSubscription)
+
+	// Wishlist
+	auth.GET("/wishlist", api.ListWishlist)
+	auth.POST("/wishlist", api.CreateWishlistItem)
+	auth.PUT("/wishlist/:id", api.UpdateWishlistItem)
+	auth.DELETE("/wishlist/:id", api.DeleteWishlistItem)
+	auth.GET("/wishlist/:id/affordability", api.WishlistAffordability)
+	auth.POST("/wishlist/:id/purchase", api.PurchaseWishlistItem)
+
+	// Income sources
+	auth.GET("/income-sources", api.ListIncomeSources)
+	auth.POST("/income-sources", api.CreateIncomeSource)
+	auth.PUT("/income-sources/:id", api.UpdateIncomeSource)
+	auth.DELETE("/income-sources/:id", api.DeleteIncomeSource)
+	auth.GET("/income/projection", cached, api.IncomeProjection)
+
+	// Search
+	auth.GET("/search", api.Search)
+
+	// Recurring transactions
+	auth.GET("/recurring", api.ListRecurringRules)
+	auth.POST("/recurring", api.CreateRecurringRule)
+	auth.PUT("/recurring/:id", api.UpdateRecurringRule)
+	auth.DELETE("/recurring/:id", api.DeleteRecurringRule)
+	auth.GET("/recurring/:id/preview", api.PreviewRecurringRule)
+
+	// Emergency fund
+	auth.GET("/emergency-fund", api.EmergencyFund)
+	auth.PUT("/emergency-fund", api.SetEmergencyFundTarget)
+	auth.POST("/emergency-fund/accounts", api.CreateFundAccount)
+	auth.PUT("/emergency-fund/accounts/:id", api.UpdateFundAccount)
+	auth.DELETE("/emergency-fund/accounts/:id", api.DeleteFundAccount)
+
+	// Reimbursements
+	auth.GET("/claims", api.ListClaims)
+	auth.POST("/claims", api.CreateClaim)
+	auth.GET("/claims/:id", api.GetClaim)
+	auth.DELETE("/claims/:id", api.DeleteClaim)
+	auth.PUT("/claims/:id/status", api.SetClaimStatus)
+	auth.POST("/claims/:id/items", api.AddClaimItems)
+	auth.DELETE("/claims/:id/items/:txid", api.RemoveClaimItem)
 
 	// Dashboard
-	auth.GET("/dashboard/summary", api.MonthSummary)
+	auth.GET("/dashboard/summary", etag, cached, api.MonthSummary)
+	auth.GET("/dashboard/summary/week", etag, cached, api.WeekSummary)
+	auth.GET("/dashboard/daily", etag, cached, api.DailySpend)
+	auth.GET("/dashboard/top", etag, cached, api.TopExpenses)
+	auth.GET("/dashboard/projection", etag, cached, api.Projection)
+	auth.GET("/dashboard/score", etag, cached, api.HealthScore)
+	auth.GET("/dashboard/score/breakdown", etag, cached, api.HealthScoreBreakdown)
+	auth.GET("/dashboard/layout", etag, api.GetDashboardLayout)
+	auth.PUT("/dashboard/layout", api.PutDashboardLayout)
+
+	// Reports
+	auth.GET("/reports/compare", cached, api.ComparePeriods)
+	auth.GET("/reports/recurring", cached, api.RecurringCharges)
+	auth.GET("/reports/yoy", cached, api.YearOverYear)
+	auth.GET("/reports/averages", cached, api.SpendAverages)
+	auth.GET("/reports/flows", cached, api.Flows)
+	auth.GET("/reports/tax", cached, api.TaxReport)
+
+	// Exchange rates
+	auth.GET("/rates", api.ListRates)
+
+	// GraphQL (read-only; dashboard data in one round trip)
+	auth.POST("/graphql", handler.GraphQL(gql.NewSchema(store)))
+
+	// Development helpers
+	if dev, _ := strconv.ParseBool(cfg.DevEndpoints); dev {
+		auth.POST("/dev/seed", api.SeedDemoData)
+		logger.Warn("development endpoints enabled")
+	}
+
+	// Operator endpoints, authenticated with ADMIN_TOKEN rather than a user JWT
+	if cfg.AdminToken != "" {
+		adm := &handler.Admin{Repos: store, Backups: &backup.Dir{Blobs: backups}, Flags: api.Flags,
+			Maintenance: maintenance, Mailer: mail, Scheduler: scheduler, Rates: ratesJob, Cache: respCache}
+		admin := r.Group("/api/admin", handler.AdminAuth(cfg.AdminToken), handler.Audit("admin"))
+		admin.GET("/audit", adm.ListAudit)
+		admin.GET("/backups", adm.ListBackups)
+		admin.POST("/backups", adm.CreateBackup)
+		admin.GET("/backups/:name", adm.DownloadBackup)
+		admin.POST("/backups/:name/restore", adm.RestoreBackup)
+		admin.GET("/maintenance", adm.GetMaintenance)
+		admin.PUT("/maintenance", adm.SetMaintenance)
+		admin.GET("/flags", adm.ListFlags)
+		admin.PUT("/flags/:key", adm.PutFlag)
+		admin.DELETE("/flags/:key", adm.DeleteFlag)
+		admin.PUT("/flags/:key/users/:user_id", adm.PutFlagOverride)
+		admin.DELETE("/flags/:key/users/:user_id", adm.DeleteFlagOverride)
+		admin.POST("/mail/test", adm.SendTestMail)
+		admin.GET("/usage", adm.Usage)
+		admin.GET("/jobs", adm.JobsStatus)
+		admin.GET("/retention", adm.ListRetention)
+		admin.GET("/retention/preview", adm.PreviewRetention)
+		admin.PUT("/retention/:target", adm.PutRetention)
+		admin.DELETE("/retention/:target", adm.DeleteRetention)
+		admin.POST("/rates/backfill", adm.BackfillRates)
+		if cfg.DebugEndpoints == "admin" {
+			admin.GET("/debug/*path", gin.WrapH(http.StripPrefix("/api/admin", handler.Debug())))
+			logger.Warn("debug endpoints enabled", "path", "/api/admin/debug/pprof/")
+		}
+	}
+
+	// API documentation (must come after all other routes)
+	apidoc.Register(r, apidoc.Info{Title: "Personal Finance Tracker API", Version: "1.0"}, handler.Docs,
+		"/api/healthz", "/api/readyz", "/api/version", "/api/register", "/api/login", "/api/telegram/webhook", "/api/mail/inbound", "/api/exports/:id/download", "/metrics")
 
 	// HTTP server + graceful shutdown
 	srv := &http.Server{
@@ -95,22 +841,51 @@ func main() {
 	}
 
 	go func() {
-		log.Printf("listening on :%s", cfg.Port)
+		logger.Info("listening", "port", cfg.Port)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("listen: %v", err)
+			fatal("listen", err)
 		}
 	}()
 
+	// Profiling on a loopback-only listener, reachable only from the host (or the
+	// container, e.g. via kubectl port-forward); validated by platform.Load.
+	if cfg.DebugEndpoints == "localhost" {
+		dbg := &http.Server{Addr: cfg.DebugAddr, Handler: handler.Debug()}
+		go func() {
+			logger.Warn("debug endpoints enabled", "addr", cfg.DebugAddr)
+			if err := dbg.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("debug listener", "error", err.Error())
+			}
+		}()
+		defer dbg.Close()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("shutting down server...")
+	logger.Info("shutting down server")
+	stopJobs()
 	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancelShutdown()
 
 	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Printf("server shutdown error: %v", err)
+		logger.Error("server shutdown error", "error", err.Error())
+	}
+	if err := usageRec.Flush(shutdownCtx); err != nil {
+		logger.Warn("usage counts lost at shutdown", "error", err.Error())
 	}
-	log.Println("server stopped cleanly")
+	select {
+	case <-workerDone:
+	case <-shutdownCtx.Done():
+		logger.Warn("background jobs still running at shutdown; they will be retried after their lease")
+	}
+	errs.Close(shutdownCtx)
+	logger.Info("server stopped cleanly")
+}
+
+// fatal logs err as the reason startup (or serving) failed and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err.Error())
+	os.Exit(1)
 }
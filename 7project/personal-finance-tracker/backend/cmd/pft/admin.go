@@ -0,0 +1,167 @@
+// backend/cmd/pft/admin.go
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const adminUsage = `usage: pft admin [-token TOKEN] <command> [flags]
+
+The token is the server's ADMIN_TOKEN (default $PFT_ADMIN_TOKEN).
+
+commands:
+  backup    write a backup archive on the server (-user ID for one user, -o FILE to download it)
+  backups   list archives stored on the server
+  download  save an archive locally: download NAME [FILE]
+  restore   replace a user's data from an archive: restore -user ID NAME
+`
+
+type backupInfo struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// cmdAdmin runs the operator commands against /api/admin, authenticated with the
+// admin token instead of the stored login.
+func cmdAdmin(base string, args []string) error {
+	fs := flag.NewFlagSet("admin", flag.ExitOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, adminUsage) }
+	token := fs.String("token", os.Getenv("PFT_ADMIN_TOKEN"), "admin token")
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if *token == "" {
+		return fmt.Errorf("admin token required: pass -token or set PFT_ADMIN_TOKEN")
+	}
+	c := newClient(base, *token)
+
+	var err error
+	switch cmd, rest := fs.Arg(0), fs.Args()[1:]; cmd {
+	case "backup":
+		err = cmdBackup(c, rest)
+	case "backups":
+		err = cmdBackups(c)
+	case "download":
+		if len(rest) < 1 || len(rest) > 2 {
+			return fmt.Errorf("usage: pft admin download NAME [FILE]")
+		}
+		file := rest[0]
+		if len(rest) == 2 {
+			file = rest[1]
+		}
+		err = downloadBackup(c, rest[0], file)
+	case "restore":
+		err = cmdRestore(c, rest)
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+	var ae *apiError
+	if errors.As(err, &ae) && ae.Code == "unauthorized" {
+		return fmt.Errorf("admin token rejected (or ADMIN_TOKEN is not set on the server)")
+	}
+	return err
+}
+
+func cmdBackup(c *client, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	user := fs.Int64("user", 0, "back up only this user id")
+	out := fs.String("o", "", "also download the archive to this file")
+	_ = fs.Parse(args)
+
+	q := url.Values{}
+	if *user > 0 {
+		q.Set("user_id", strconv.FormatInt(*user, 10))
+	}
+	var info backupInfo
+	if err := c.do(http.MethodPost, "/admin/backups", q, nil, &info); err != nil {
+		return err
+	}
+	fmt.Printf("created %s (%d bytes)\n", info.Name, info.Size)
+	if *out == "" {
+		return nil
+	}
+	return downloadBackup(c, info.Name, *out)
+}
+
+func cmdBackups(c *client) error {
+	var list []backupInfo
+	if err := c.do(http.MethodGet, "/admin/backups", nil, nil, &list); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSIZE\tCREATED")
+	for _, b := range list {
+		fmt.Fprintf(w, "%s\t%d\t%s\n", b.Name, b.Size, b.CreatedAt.Local().Format("2006-01-02 15:04"))
+	}
+	return w.Flush()
+}
+
+func cmdRestore(c *client, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	user := fs.Int64("user", 0, "user id to restore (required)")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 || *user <= 0 {
+		return fmt.Errorf("usage: pft admin restore -user ID NAME")
+	}
+	var res struct {
+		Rows map[string]int64 `json:"rows"`
+	}
+	if err := c.do(http.MethodPost, "/admin/backups/"+url.PathEscape(fs.Arg(0))+"/restore", nil,
+		map[string]int64{"user_id": *user}, &res); err != nil {
+		return err
+	}
+	var total int64
+	for _, n := range res.Rows {
+		total += n
+	}
+	fmt.Printf("restored user %d from %s: %d rows (%d transactions)\n", *user, fs.Arg(0), total, res.Rows["transactions"])
+	return nil
+}
+
+// downloadBackup saves the raw archive to file.
+func downloadBackup(c *client, name, file string) error {
+	req, err := http.NewRequest(http.MethodGet, c.base+"/admin/backups/"+url.PathEscape(name), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		e := &apiError{}
+		_ = json.NewDecoder(res.Body).Decode(e)
+		e.Status = res.StatusCode
+		return e
+	}
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, res.Body); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Println("saved", file)
+	return nil
+}
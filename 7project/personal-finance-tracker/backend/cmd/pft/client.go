@@ -0,0 +1,178 @@
+// backend/cmd/pft/client.go
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// credentials are persisted after login so later commands are authenticated.
+type credentials struct {
+	API   string `json:"api"`
+	Token string `json:"token"`
+}
+
+// credentialsPath is $XDG_CONFIG_HOME/pft/credentials.json (or the OS equivalent).
+func credentialsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pft", "credentials.json"), nil
+}
+
+func loadCredentials() (credentials, error) {
+	var c credentials
+	p, err := credentialsPath()
+	if err != nil {
+		return c, err
+	}
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	return c, json.Unmarshal(b, &c)
+}
+
+// saveCredentials writes the token readable by the current user only.
+func saveCredentials(c credentials) error {
+	p, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+	b, _ := json.MarshalIndent(c, "", "  ")
+	return os.WriteFile(p, b, 0o600)
+}
+
+// client is a minimal JSON client for the REST API.
+type client struct {
+	base  string
+	token string
+	http  *http.Client
+}
+
+func newClient(base, token string) *client {
+	return &client{base: strings.TrimRight(base, "/"), token: token, http: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// apiError is a non-2xx response, decoded from the API's problem+json body.
+type apiError struct {
+	Status int    `json:"status"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+	Errors []struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (e *apiError) Error() string {
+	if e.Code == "unauthorized" {
+		return "not logged in or session expired; run: pft login"
+	}
+	if len(e.Errors) > 0 {
+		msgs := make([]string, len(e.Errors))
+		for i, fe := range e.Errors {
+			msgs[i] = fe.Field + " " + fe.Message
+		}
+		return "api: " + strings.Join(msgs, "; ")
+	}
+	if e.Detail != "" {
+		return fmt.Sprintf("api: %s (%s)", e.Detail, e.Code)
+	}
+	return fmt.Sprintf("api: %d %s", e.Status, e.Code)
+}
+
+// do sends in (if non-nil) as JSON and decodes the response into out (if non-nil).
+func (c *client) do(method, path string, query url.Values, in, out any) error {
+	u := c.base + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		e := &apiError{}
+		_ = json.NewDecoder(res.Body).Decode(e)
+		e.Status = res.StatusCode
+		return e
+	}
+	if out == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+type category struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type transaction struct {
+	ID          int64     `json:"id"`
+	CategoryID  *int64    `json:"category_id"`
+	Amount      float64   `json:"amount"`
+	Type        string    `json:"type"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+}
+
+type newTransaction struct {
+	CategoryID  int64   `json:"category_id"`
+	Amount      float64 `json:"amount"`
+	Type        string  `json:"type"`
+	Date        string  `json:"date"`
+	Description string  `json:"description"`
+}
+
+// categoryIDs maps lower-cased category names to IDs.
+func (c *client) categoryIDs() (map[string]category, error) {
+	var list []category
+	if err := c.do(http.MethodGet, "/categories", nil, nil, &list); err != nil {
+		return nil, err
+	}
+	out := make(map[string]category, len(list))
+	for _, cat := range list {
+		out[strings.ToLower(cat.Name)] = cat
+	}
+	return out, nil
+}
@@ -0,0 +1,81 @@
+// backend/cmd/pft/csv.go
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseCSV reads transactions with a header row naming the columns
+// date, amount, category and optionally type and description (any order).
+// A missing type means expense; a negative amount is taken as an expense of its absolute value.
+// All rows are validated before anything is sent so a bad file imports nothing.
+func parseCSV(r io.Reader, cats map[string]category) ([]newTransaction, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, req := range []string{"date", "amount", "category"} {
+		if _, ok := col[req]; !ok {
+			return nil, fmt.Errorf("missing column %q", req)
+		}
+	}
+	get := func(rec []string, name string) string {
+		if i, ok := col[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+
+	var out []newTransaction
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		date := get(rec, "date")
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, fmt.Errorf("line %d: date %q is not YYYY-MM-DD", line, date)
+		}
+		amount, err := strconv.ParseFloat(get(rec, "amount"), 64)
+		if err != nil || amount == 0 {
+			return nil, fmt.Errorf("line %d: invalid amount %q", line, get(rec, "amount"))
+		}
+		typ := strings.ToLower(get(rec, "type"))
+		switch {
+		case typ == "" || amount < 0:
+			typ = "expense"
+		case typ != "income" && typ != "expense":
+			return nil, fmt.Errorf("line %d: type %q must be income or expense", line, typ)
+		}
+		cat, ok := cats[strings.ToLower(get(rec, "category"))]
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown category %q", line, get(rec, "category"))
+		}
+		out = append(out, newTransaction{
+			CategoryID: cat.ID, Amount: abs(amount), Type: typ, Date: date, Description: get(rec, "description"),
+		})
+	}
+	return out, nil
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,41 @@
+// backend/cmd/pft/csv_test.go
+//
+// Purpose:
+//   Verify CSV import parsing: flexible column order, defaults for type, category
+//   lookup, and line-numbered errors for bad rows.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	cats := map[string]category{"groceries": {ID: 3, Name: "Groceries"}, "salary": {ID: 1, Name: "Salary"}}
+
+	rows, err := parseCSV(strings.NewReader(
+		"Category,Date,Amount,Type,Description\n"+
+			"Groceries,2025-01-02,23.40,,Weekly shop\n"+
+			"salary,2025-01-25,3200,income,\n"+
+			"groceries,2025-01-09,-12.5,,Bank export sign\n"), cats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0].CategoryID != 3 || rows[0].Type != "expense" ||
+		rows[1].Type != "income" || rows[2].Amount != 12.5 || rows[2].Type != "expense" {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+
+	for _, bad := range []string{
+		"date,amount\n2025-01-02,1\n",
+		"date,amount,category\n02/01/2025,1,groceries\n",
+		"date,amount,category\n2025-01-02,abc,groceries\n",
+		"date,amount,category\n2025-01-02,1,rent\n",
+		"date,amount,category,type\n2025-01-02,1,groceries,transfer\n",
+	} {
+		if _, err := parseCSV(strings.NewReader(bad), cats); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// backend/cmd/pft/main.go
+//
+// pft is a command-line client for the Personal Finance Tracker API.
+//
+//	pft login -email me@example.com          # prompts for the password, stores the token
+//	pft add -category Groceries 23.40 "Weekly shop"
+//	pft add -income -category Salary 3200
+//	pft list -from 2025-01-01 -limit 20
+//	pft import statement.csv                 # columns: date,amount,type,category,description
+//	pft summary -month 2025-01
+//	pft logout
+//	pft admin backup -o pft.json.gz          # server backup; needs the server's ADMIN_TOKEN
+//
+// The API base URL comes from -api, $PFT_API, the stored login, or defaults to
+// http://localhost:8080/api (the docker-compose dev stack).
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const defaultAPI = "http://localhost:8080/api"
+
+const usage = `usage: pft [-api URL] <command> [flags]
+
+commands:
+  login     authenticate and store the token
+  logout    forget the stored token
+  add       record an expense (or income with -income)
+  list      list transactions
+  import    create transactions from a CSV file
+  summary   show the monthly summary
+  admin     backups and restores (see pft admin -h)
+`
+
+func main() {
+	global := flag.NewFlagSet("pft", flag.ExitOnError)
+	global.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	apiFlag := global.String("api", "", "API base URL")
+	_ = global.Parse(os.Args[1:])
+	if global.NArg() == 0 {
+		global.Usage()
+		os.Exit(2)
+	}
+
+	creds, err := loadCredentials()
+	if err != nil {
+		fail(err)
+	}
+	base := firstNonEmpty(*apiFlag, os.Getenv("PFT_API"), creds.API, defaultAPI)
+	c := newClient(base, creds.Token)
+
+	cmd, args := global.Arg(0), global.Args()[1:]
+	switch cmd {
+	case "login":
+		err = cmdLogin(c, args)
+	case "logout":
+		err = saveCredentials(credentials{API: creds.API})
+	case "add":
+		err = cmdAdd(c, args)
+	case "list":
+		err = cmdList(c, args)
+	case "import":
+		err = cmdImport(c, args)
+	case "summary":
+		err = cmdSummary(c, args)
+	case "admin":
+		err = cmdAdmin(base, args)
+	default:
+		global.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func cmdLogin(c *client, args []string) error {
+	fs := flag.NewFlagSet("login", flag.ExitOnError)
+	email := fs.String("email", "", "account email (required)")
+	password := fs.String("password", "", "password (prompted when omitted)")
+	_ = fs.Parse(args)
+	if *email == "" {
+		return fmt.Errorf("-email is required")
+	}
+	if *password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return err
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+	var res struct {
+		Token string `json:"token"`
+	}
+	if err := c.do(http.MethodPost, "/login", nil, map[string]string{"email": *email, "password": *password}, &res); err != nil {
+		return err
+	}
+	if err := saveCredentials(credentials{API: c.base, Token: res.Token}); err != nil {
+		return err
+	}
+	fmt.Println("logged in as", *email)
+	return nil
+}
+
+func cmdAdd(c *client, args []string) error {
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	income := fs.Bool("income", false, "record income instead of an expense")
+	cat := fs.String("category", "", "category name (required)")
+	date := fs.String("date", time.Now().Format("2006-01-02"), "date YYYY-MM-DD")
+	_ = fs.Parse(args)
+	if fs.NArg() < 1 || *cat == "" {
+		return fmt.Errorf("usage: pft add [-income] -category NAME [-date YYYY-MM-DD] AMOUNT [DESCRIPTION...]")
+	}
+	amount, err := strconv.ParseFloat(fs.Arg(0), 64)
+	if err != nil || amount <= 0 {
+		return fmt.Errorf("amount must be a positive number, got %q", fs.Arg(0))
+	}
+	typ := "expense"
+	if *income {
+		typ = "income"
+	}
+	cats, err := c.categoryIDs()
+	if err != nil {
+		return err
+	}
+	found, ok := cats[strings.ToLower(*cat)]
+	if !ok {
+		return fmt.Errorf("unknown category %q", *cat)
+	}
+	var out transaction
+	err = c.do(http.MethodPost, "/transactions", nil, newTransaction{
+		CategoryID: found.ID, Amount: amount, Type: typ, Date: *date, Description: strings.Join(fs.Args()[1:], " "),
+	}, &out)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("added %s #%d: %.2f on %s (%s)\n", typ, out.ID, out.Amount, out.Date.Format("2006-01-02"), found.Name)
+	return nil
+}
+
+func cmdList(c *client, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	from := fs.String("from", "", "start date YYYY-MM-DD")
+	to := fs.String("to", "", "end date YYYY-MM-DD")
+	typ := fs.String("type", "", "income or expense")
+	limit := fs.Int("limit", 50, "maximum rows")
+	_ = fs.Parse(args)
+
+	q := url.Values{"limit": {strconv.Itoa(*limit)}}
+	for k, v := range map[string]string{"from": *from, "to": *to, "type": *typ} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	var list []transaction
+	if err := c.do(http.MethodGet, "/transactions", q, nil, &list); err != nil {
+		return err
+	}
+	cats, err := c.categoryIDs()
+	if err != nil {
+		return err
+	}
+	names := map[int64]string{}
+	for _, cat := range cats {
+		names[cat.ID] = cat.Name
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "ID\tDATE\tTYPE\tAMOUNT\tCATEGORY\t DESCRIPTION\t")
+	for _, t := range list {
+		name := ""
+		if t.CategoryID != nil {
+			name = names[*t.CategoryID]
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%.2f\t%s\t %s\t\n", t.ID, t.Date.Format("2006-01-02"), t.Type, t.Amount, name, t.Description)
+	}
+	return w.Flush()
+}
+
+func cmdImport(c *client, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "validate the file without creating transactions")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: pft import [-dry-run] FILE.csv")
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cats, err := c.categoryIDs()
+	if err != nil {
+		return err
+	}
+	rows, err := parseCSV(f, cats)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		fmt.Printf("%d rows OK\n", len(rows))
+		return nil
+	}
+	for i, r := range rows {
+		if err := c.do(http.MethodPost, "/transactions", nil, r, nil); err != nil {
+			return fmt.Errorf("row %d: %w (%d rows imported before the failure)", i+2, err, i)
+		}
+	}
+	fmt.Printf("imported %d transactions\n", len(rows))
+	return nil
+}
+
+func cmdSummary(c *client, args []string) error {
+	fs := flag.NewFlagSet("summary", flag.ExitOnError)
+	month := fs.String("month", time.Now().Format("2006-01"), "month YYYY-MM")
+	_ = fs.Parse(args)
+
+	var s struct {
+		Month        string   `json:"month"`
+		IncomeTotal  float64  `json:"income_total"`
+		ExpenseTotal float64  `json:"expense_total"`
+		SavingsRate  *float64 `json:"savings_rate"`
+	}
+	if err := c.do(http.MethodGet, "/dashboard/summary", url.Values{"month": {*month}}, nil, &s); err != nil {
+		return err
+	}
+	fmt.Printf("Month:    %s\nIncome:   %10.2f\nExpenses: %10.2f\nNet:      %10.2f\n",
+		s.Month, s.IncomeTotal, s.ExpenseTotal, s.IncomeTotal-s.ExpenseTotal)
+	if s.SavingsRate != nil {
+		fmt.Printf("Savings:  %9.1f%%\n", *s.SavingsRate*100)
+	}
+	return nil
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "pft:", err)
+	os.Exit(1)
+}
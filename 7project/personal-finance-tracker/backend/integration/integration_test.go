@@ -3,54 +3,86 @@ package integration
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"pft/internal/handler"
 	"pft/internal/platform"
 	"pft/internal/repo"
+	"pft/internal/webhook"
 )
 
-func setup(t *testing.T) (*gin.Engine, func()) {
+// env is a migrated test database and a router serving the public routes; tests
+// mount the routes they exercise on r.
+type env struct {
+	r     *gin.Engine
+	pool  *pgxpool.Pool
+	store *repo.Store
+}
+
+// setup connects to PG_TEST_DSN, skipping the test when it is not set, with opts
+// applied to the pool, and runs the migrations. The pool is closed when the test ends.
+func setup(t *testing.T, opts ...platform.PoolOptions) *env {
 	t.Helper()
 	dsn := os.Getenv("PG_TEST_DSN")
 	if dsn == "" {
 		t.Skip("PG_TEST_DSN not set")
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	t.Cleanup(cancel)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	pcfg, _ := pgxpool.ParseConfig(dsn)
+	pcfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range opts {
+		o.Apply(pcfg)
+	}
 	pool, err := pgxpool.NewWithConfig(ctx, pcfg)
 	if err != nil {
 		t.Fatal(err)
 	}
-
-	// migrate
+	t.Cleanup(pool.Close)
 	if err := platform.RunMigrations(ctx, pool, "../../migrations"); err != nil {
 		t.Fatal(err)
 	}
 
-	api := handler.New(repo.New(pool), "testsecret")
+	store := repo.New(pool)
+	api := handler.New(store, "testsecret")
 	r := gin.New()
 	r.Use(gin.Recovery())
 	r.POST("/api/register", api.Register)
 	r.POST("/api/login", api.Login)
 	r.GET("/api/healthz", api.Healthz)
+	return &env{r: r, pool: pool, store: store}
+}
 
-	// clean tables between tests? You can add TRUNCATE here if needed
-
-	return r, func() { pool.Close() }
+// user creates a user with a unique email, so tests can be rerun on the same database.
+func (e *env) user(t *testing.T, name string) *repo.User {
+	t.Helper()
+	u, err := e.store.UserRepo().Create(context.Background(), name, name+"-"+time.Now().Format("150405.000000")+"@e.com", "hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
 }
 
 func TestRegisterLogin(t *testing.T) {
-	r, _ := setup(t)
+	r := setup(t).r
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest("POST", "/api/register", strings.NewReader(`{"name":"t","email":"t@e.com","password":"secret123"}`))
@@ -68,3 +100,1123 @@ func TestRegisterLogin(t *testing.T) {
 		t.Fatalf("login got %d: %s", w.Code, w.Body.String())
 	}
 }
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	store := e.store
+	email := "tx-" + time.Now().Format("150405.000000") + "@e.com"
+
+	// An error rolls back every repo's writes, including those of a nested WithTx.
+	errStop := errors.New("stop")
+	err := store.WithTx(ctx, func(tx *repo.Store) error {
+		if _, err := tx.UserRepo().Create(ctx, "tx", email, "hash"); err != nil {
+			return err
+		}
+		return tx.WithTx(ctx, func(inner *repo.Store) error {
+			if u, err := inner.UserRepo().GetByEmail(ctx, email); err != nil || u == nil {
+				t.Errorf("user not visible inside the transaction: %v", err)
+			}
+			return errStop
+		})
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("WithTx = %v, want errStop", err)
+	}
+	if u, _ := store.UserRepo().GetByEmail(ctx, email); u != nil {
+		t.Fatal("rolled back user was stored")
+	}
+
+	if err := store.WithTx(ctx, func(tx *repo.Store) error {
+		_, err := tx.UserRepo().Create(ctx, "tx", email, "hash")
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if u, _ := store.UserRepo().GetByEmail(ctx, email); u == nil {
+		t.Fatal("committed user missing")
+	}
+}
+
+func TestConcurrentMigrations(t *testing.T) {
+	dsn := os.Getenv("PG_TEST_DSN")
+	if dsn == "" {
+		t.Skip("PG_TEST_DSN not set")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// Instances booting together serialise on the migration lock; all succeed.
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			pool, err := pgxpool.New(ctx, dsn)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer pool.Close()
+			errs <- platform.RunMigrations(ctx, pool, "../../migrations")
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAPIKeyTriggers(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	store := e.store
+	u := e.user(t, "zap")
+	key := "pft_test" + time.Now().Format("150405000000")
+	if _, err := store.APIKeyRepo().Create(ctx, u.ID, "Zapier", key, []string{"transactions:read"}); err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, amount := range []float64{5, 7} {
+		txn, err := store.TransactionRepo().Create(ctx, &repo.Transaction{UserID: u.ID, Amount: amount, Type: "expense", Date: time.Now().UTC()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, txn.ID)
+	}
+
+	api := handler.New(store, "testsecret")
+	r := gin.New()
+	r.GET("/api/zapier/triggers/new-transaction", handler.APIKeyMiddleware(store.APIKeyRepo()), api.NewTransactionsTrigger)
+	get := func(target, key string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", target, nil)
+		req.Header.Set("X-API-Key", key)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("/api/zapier/triggers/new-transaction", "pft_unknown"); w.Code != 401 {
+		t.Fatalf("unknown key got %d", w.Code)
+	}
+	w := get("/api/zapier/triggers/new-transaction?since_id="+strconv.FormatInt(ids[0], 10), key)
+	if w.Code != 200 || strings.Count(w.Body.String(), `"id"`) != 1 || !strings.Contains(w.Body.String(), `"amount":7`) {
+		t.Fatalf("since_id got %d: %s", w.Code, w.Body)
+	}
+
+	// Scopes: the key reads transactions through the regular API but cannot write
+	// them, read budgets or reach routes reserved for the user's own sessions.
+	keyOrJWT := handler.KeyOrJWT(handler.APIKeyMiddleware(store.APIKeyRepo()), handler.JWTMiddleware(handler.AuthConfig{JWTSecret: "testsecret"}))
+	r.GET("/api/transactions", keyOrJWT, api.ListTransactions)
+	r.POST("/api/transactions", keyOrJWT, api.CreateTransaction)
+	r.GET("/api/zapier/triggers/budget-exceeded", handler.APIKeyMiddleware(store.APIKeyRepo()), api.BudgetExceededTrigger)
+	r.GET("/api/me/api-keys", keyOrJWT, api.ListAPIKeys)
+	if w := get("/api/transactions", key); w.Code != 200 || !strings.Contains(w.Body.String(), `"amount":5`) {
+		t.Fatalf("transactions:read got %d: %s", w.Code, w.Body)
+	}
+	for _, target := range []string{"/api/zapier/triggers/budget-exceeded", "/api/me/api-keys"} {
+		if w := get(target, key); w.Code != 403 || !strings.Contains(w.Body.String(), "insufficient_scope") {
+			t.Fatalf("%s got %d: %s", target, w.Code, w.Body)
+		}
+	}
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/transactions", strings.NewReader(`{"category_id":1,"amount":1,"type":"expense","date":"2026-10-01"}`))
+	req.Header.Set("X-API-Key", key)
+	r.ServeHTTP(w, req)
+	if w.Code != 403 {
+		t.Fatalf("write without transactions:write got %d: %s", w.Code, w.Body)
+	}
+}
+
+func TestAccountStatement(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	store := e.store
+	u := e.user(t, "acct")
+	a, err := store.AccountRepo().Create(ctx, &repo.Account{UserID: u.ID, Name: "Checking", OpeningBalance: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(s string) time.Time { d, _ := time.Parse("2006-01-02", s); return d }
+	for _, txn := range []repo.Transaction{
+		{Amount: 30, Type: "expense", Date: day("2026-09-15"), AccountID: &a.ID},
+		{Amount: 500, Type: "income", Date: day("2026-10-01"), AccountID: &a.ID},
+		{Amount: 45.5, Type: "expense", Date: day("2026-10-20"), AccountID: &a.ID},
+		{Amount: 999, Type: "expense", Date: day("2026-10-05")}, // no account
+	} {
+		txn.UserID = u.ID
+		if _, err := store.TransactionRepo().Create(ctx, &txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s, err := store.AccountRepo().Statement(ctx, u.ID, a.ID, day("2026-10-01"), day("2026-10-31"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.OpeningBalance != 70 || len(s.Transactions) != 2 || s.Transactions[0].Balance != 570 || s.ClosingBalance != 524.5 {
+		t.Fatalf("statement = %+v", s)
+	}
+	if s, err := store.AccountRepo().Statement(ctx, u.ID+1, a.ID, day("2026-10-01"), day("2026-10-31")); err != nil || s != nil {
+		t.Fatalf("other user's account: %+v, %v", s, err)
+	}
+}
+
+func TestRetentionPolicies(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	store := e.store
+	u := e.user(t, "ret")
+	now := time.Now().UTC()
+	for _, age := range []int{500, 400, 10} {
+		txn, err := store.TransactionRepo().Create(ctx, &repo.Transaction{UserID: u.ID, Amount: 1, Type: "expense", Date: now.AddDate(0, 0, -age)})
+		if err != nil || txn == nil {
+			t.Fatal(err)
+		}
+	}
+	// The oldest transaction's month is closed, so it is kept.
+	if _, err := store.PeriodRepo().Close(ctx, u.ID, now.AddDate(0, 0, -500).Format("2006-01")); err != nil {
+		t.Fatal(err)
+	}
+	p, err := store.RetentionRepo().Set(ctx, &u.ID, "transactions", 365)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := func() int {
+		txs, err := store.TransactionRepo().List(ctx, u.ID, repo.TxnListFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(txs)
+	}
+
+	if res, err := store.RetentionRepo().Apply(ctx, *p, now, true); err != nil || res.Rows != 1 || count() != 3 {
+		t.Fatalf("dry run = %+v, %v; %d transactions left", res, err, count())
+	}
+	if res, err := store.RetentionRepo().Apply(ctx, *p, now, false); err != nil || res.Rows != 1 || count() != 2 {
+		t.Fatalf("apply = %+v, %v; %d transactions left", res, err, count())
+	}
+	if _, err := store.RetentionRepo().Apply(ctx, repo.RetentionPolicy{UserID: &u.ID, Target: "jobs", KeepDays: 1}, now, true); err == nil {
+		t.Fatal("expected a user jobs policy to be rejected")
+	}
+	if _, err := store.RetentionRepo().Apply(ctx, repo.RetentionPolicy{Target: "transactions", KeepDays: 1}, now, true); err == nil {
+		t.Fatal("expected an operator transactions policy to be rejected")
+	}
+}
+
+func TestOAuthFlow(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	store := e.store
+	stamp := time.Now().Format("150405000000")
+	u := e.user(t, "oauth")
+	cl, err := store.OAuthRepo().CreateClient(ctx, u.ID, "Budget app", "pftc_"+stamp, "pfts_"+stamp, []string{"https://app.example/cb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.TransactionRepo().Create(ctx, &repo.Transaction{UserID: u.ID, Amount: 9, Type: "expense", Date: time.Now().UTC()}); err != nil {
+		t.Fatal(err)
+	}
+
+	api := handler.New(store, "testsecret")
+	r := gin.New()
+	session := func(c *gin.Context) { c.Set("uid", u.ID); c.Next() }
+	r.POST("/api/oauth/authorize", session, api.OAuthAuthorize)
+	r.POST("/api/oauth/token", api.OAuthToken)
+	tokenAuth := handler.OAuthOrJWT(store.OAuthRepo(), handler.JWTMiddleware(handler.AuthConfig{JWTSecret: "testsecret"}))
+	r.GET("/api/transactions", tokenAuth, api.ListTransactions)
+	r.POST("/api/transactions", tokenAuth, api.CreateTransaction)
+	do := func(method, target, body, contentType, bearer string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+	token := func(form string) *httptest.ResponseRecorder {
+		return do("POST", "/api/oauth/token", form+"&client_id="+cl.ClientID+"&client_secret=pfts_"+stamp, "application/x-www-form-urlencoded", "")
+	}
+
+	// The user approves read access to transactions; the app exchanges the code once.
+	w := do("POST", "/api/oauth/authorize", `{"response_type":"code","client_id":"`+cl.ClientID+`","scope":"transactions:read","state":"xyz","approve":true}`, "application/json", "")
+	if w.Code != 200 || !strings.Contains(w.Body.String(), "state=xyz") {
+		t.Fatalf("authorize got %d: %s", w.Code, w.Body)
+	}
+	var consent struct {
+		RedirectURI string `json:"redirect_uri"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &consent); err != nil {
+		t.Fatal(err)
+	}
+	redirect, err := url.Parse(consent.RedirectURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := redirect.Query().Get("code")
+	exchange := "grant_type=authorization_code&redirect_uri=https://app.example/cb&code=" + code
+	w = token(exchange)
+	if w.Code != 200 || !strings.Contains(w.Body.String(), `"scope":"transactions:read"`) {
+		t.Fatalf("token got %d: %s", w.Code, w.Body)
+	}
+	if again := token(exchange); again.Code != 400 || !strings.Contains(again.Body.String(), "invalid_grant") {
+		t.Fatalf("reused code got %d: %s", again.Code, again.Body)
+	}
+	field := func(body, name string) string {
+		var m map[string]any
+		_ = json.Unmarshal([]byte(body), &m)
+		s, _ := m[name].(string)
+		return s
+	}
+	access, refresh := field(w.Body.String(), "access_token"), field(w.Body.String(), "refresh_token")
+
+	// The token reads but cannot write.
+	if w := do("GET", "/api/transactions", "", "", access); w.Code != 200 || !strings.Contains(w.Body.String(), `"amount":9`) {
+		t.Fatalf("read with token got %d: %s", w.Code, w.Body)
+	}
+	if w := do("POST", "/api/transactions", `{"amount":1,"type":"expense","date":"2026-10-01"}`, "application/json", access); w.Code != 403 {
+		t.Fatalf("write with read token got %d: %s", w.Code, w.Body)
+	}
+
+	// Refreshing rotates both tokens; revoking the grant ends access.
+	w = token("grant_type=refresh_token&refresh_token=" + refresh)
+	if w.Code != 200 {
+		t.Fatalf("refresh got %d: %s", w.Code, w.Body)
+	}
+	if w := do("GET", "/api/transactions", "", "", access); w.Code != 401 {
+		t.Fatalf("old access token got %d", w.Code)
+	}
+	access = field(w.Body.String(), "access_token")
+	if ok, err := store.OAuthRepo().RevokeGrants(ctx, u.ID, cl.ClientID); err != nil || !ok {
+		t.Fatalf("revoke = %v, %v", ok, err)
+	}
+	if w := do("GET", "/api/transactions", "", "", access); w.Code != 401 {
+		t.Fatalf("revoked access token got %d", w.Code)
+	}
+}
+
+func TestDeltaSync(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	store := e.store
+	u := e.user(t, "sync")
+	cat, err := store.CategoryRepo().Create(ctx, &repo.Category{UserID: u.ID, Name: "Food", Type: "expense"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var txns []*repo.Transaction
+	for _, amount := range []float64{3, 4} {
+		txn, err := store.TransactionRepo().Create(ctx, &repo.Transaction{UserID: u.ID, CategoryID: &cat.ID, Amount: amount, Type: "expense", Date: time.Now().UTC()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		txns = append(txns, txn)
+	}
+
+	// sync pages through every change from cur, one per page.
+	sync := func(cur repo.SyncCursor) ([]repo.SyncChange, repo.SyncCursor) {
+		var all []repo.SyncChange
+		for {
+			page, err := store.SyncRepo().Changes(ctx, u.ID, cur, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			all, cur = append(all, page.Changes...), page.Next
+			if !page.HasMore {
+				return all, cur
+			}
+		}
+	}
+	changes, cur := sync(repo.SyncCursor{})
+	if len(changes) != 3 || changes[0].Entity != "categories" || changes[2].Op != "upsert" {
+		t.Fatalf("first sync = %+v", changes)
+	}
+	if again, _ := sync(cur); len(again) != 0 {
+		t.Fatalf("nothing changed, got %+v", again)
+	}
+
+	// A deleted transaction comes back as a tombstone, a soft-deleted category too.
+	if _, err := store.TransactionRepo().Delete(ctx, u.ID, txns[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CategoryRepo().Delete(ctx, u.ID, cat.ID); err != nil {
+		t.Fatal(err)
+	}
+	changes, _ = sync(cur)
+	ops := map[string]string{}
+	for _, ch := range changes {
+		ops[ch.Entity+":"+strconv.FormatInt(ch.ID, 10)] = ch.Op
+	}
+	if ops["transactions:"+strconv.FormatInt(txns[0].ID, 10)] != "delete" || ops["categories:"+strconv.FormatInt(cat.ID, 10)] != "delete" {
+		t.Fatalf("delta sync = %+v", changes)
+	}
+}
+
+func TestSyncWrite(t *testing.T) {
+	e := setup(t)
+	store := e.store
+	u := e.user(t, "syncw")
+	api := handler.New(store, "testsecret")
+	r := gin.New()
+	r.POST("/api/sync", func(c *gin.Context) { c.Set("uid", u.ID); c.Next() }, api.SyncWrite)
+	type result struct {
+		ClientRef string `json:"client_ref"`
+		Status    string `json:"status"`
+		Code      string `json:"code"`
+		State     *struct {
+			ID      int64          `json:"id"`
+			Op      string         `json:"op"`
+			Version int64          `json:"version"`
+			Data    map[string]any `json:"data"`
+		} `json:"state"`
+	}
+	write := func(body string) []result {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/api/sync", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		var out struct{ Results []result }
+		if w.Code != 200 || json.Unmarshal(w.Body.Bytes(), &out) != nil {
+			t.Fatalf("sync write got %d: %s", w.Code, w.Body)
+		}
+		return out.Results
+	}
+
+	// Creates map client refs to ids; an invalid edit is rejected without stopping the batch.
+	res := write(`{"changes":[
+		{"entity":"accounts","op":"upsert","client_ref":"a1","data":{"name":"Wallet","opening_balance":20}},
+		{"entity":"accounts","op":"upsert","client_ref":"a2","data":{"opening_balance":5}}]}`)
+	if len(res) != 2 || res[0].Status != "accepted" || res[0].ClientRef != "a1" || res[1].Status != "rejected" || res[1].Code != "invalid" {
+		t.Fatalf("creates = %+v", res)
+	}
+	id, v1 := strconv.FormatInt(res[0].State.ID, 10), res[0].State.Version
+
+	// Two devices edit the same version: the first wins, the second gets the server's row.
+	edit := func(name string) result {
+		return write(`{"changes":[{"entity":"accounts","id":` + id + `,"op":"upsert","base_version":` +
+			strconv.FormatInt(v1, 10) + `,"data":{"name":"` + name + `","opening_balance":20}}]}`)[0]
+	}
+	if first := edit("Cash"); first.Status != "accepted" || first.State.Version <= v1 {
+		t.Fatalf("first edit = %+v", first)
+	}
+	second := edit("Purse")
+	if second.Status != "conflict" || second.State.Data["name"] != "Cash" {
+		t.Fatalf("second edit = %+v", second)
+	}
+
+	// Deleting on the current version works, and deleting again is accepted.
+	del := `{"changes":[{"entity":"accounts","id":` + id + `,"op":"delete","base_version":` + strconv.FormatInt(second.State.Version, 10) + `}]}`
+	for i := 0; i < 2; i++ {
+		if res := write(del); res[0].Status != "accepted" || res[0].State.Op != "delete" {
+			t.Fatalf("delete %d = %+v", i, res[0])
+		}
+	}
+}
+
+func TestAtomicBatch(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	store := e.store
+	u := e.user(t, "batch")
+	api := handler.New(store, "testsecret")
+	r := gin.New()
+	auth := r.Group("/api", func(c *gin.Context) {
+		c.Set("uid", u.ID)
+		c.Request = c.Request.WithContext(repo.WithTenant(c.Request.Context(), u.ID))
+	})
+	auth.POST("/accounts", api.CreateAccount)
+	auth.GET("/accounts", api.ListAccounts)
+	auth.POST("/batch", handler.Batch(r, store))
+	batch := func(body string) map[string]any {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/api/batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		var out map[string]any
+		if w.Code != 200 || json.Unmarshal(w.Body.Bytes(), &out) != nil {
+			t.Fatalf("batch got %d: %s", w.Code, w.Body)
+		}
+		return out
+	}
+	count := func() int {
+		list, err := store.AccountRepo().List(ctx, u.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(list)
+	}
+
+	// The second create is invalid: the first is rolled back and the third never runs.
+	out := batch(`{"atomic":true,"requests":[
+		{"method":"POST","path":"/api/accounts","body":{"name":"Wallet"}},
+		{"method":"POST","path":"/api/accounts","body":{}},
+		{"method":"POST","path":"/api/accounts","body":{"name":"Card"}}]}`)
+	results := out["results"].([]any)
+	if out["committed"] != false || results[0].(map[string]any)["status"] != float64(201) || results[2].(map[string]any)["status"] != float64(424) {
+		t.Fatalf("failed batch = %v", out)
+	}
+	if n := count(); n != 0 {
+		t.Fatalf("%d accounts after rollback", n)
+	}
+
+	// Reads inside the batch see its earlier writes.
+	out = batch(`{"atomic":true,"requests":[
+		{"method":"POST","path":"/api/accounts","body":{"name":"Wallet"}},
+		{"method":"GET","path":"/api/accounts"}]}`)
+	list := out["results"].([]any)[1].(map[string]any)["body"].([]any)
+	if out["committed"] != true || len(list) != 1 || count() != 1 {
+		t.Fatalf("committed batch = %v", out)
+	}
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	store := e.store
+	u := e.user(t, "hooks")
+	var up atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !webhook.Verify("whsec_it", r.Header.Get(webhook.SignatureHeader), body, time.Now(), time.Minute) || !up.Load() {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	hooks := store.WebhookRepo()
+	ep, err := hooks.CreateEndpoint(ctx, &repo.WebhookEndpoint{UserID: u.ID, URL: srv.URL, Secret: "whsec_it", Topics: []string{"budgets.created"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only subscribed topics are queued, each event once however often it is dispatched.
+	for _, e := range []repo.OutboxEvent{
+		{ID: 1, UserID: u.ID, Topic: "budgets.created", Payload: []byte(`{"entity_id":"1"}`)},
+		{ID: 1, UserID: u.ID, Topic: "budgets.created", Payload: []byte(`{"entity_id":"1"}`)},
+		{ID: 2, UserID: u.ID, Topic: "transactions.created", Payload: []byte(`{"entity_id":"2"}`)},
+	} {
+		if err := hooks.Enqueue(ctx, &e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	log := func(status string) []repo.WebhookDelivery {
+		out, err := hooks.ListDeliveries(ctx, u.ID, ep.ID, status, 50, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	if got := log(""); len(got) != 1 || got[0].EventID != 1 || got[0].Status != "pending" {
+		t.Fatalf("queued %+v", got)
+	}
+
+	// The endpoint is down and the only attempt fails: a dead letter.
+	up.Store(false)
+	d := &webhook.Deliverer{Store: hooks, Client: srv.Client(), MaxAttempts: 1}
+	if _, err := d.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	dead, err := hooks.ListDead(ctx, u.ID, 50, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 1 || len(dead[0].Log) != 1 || *dead[0].Log[0].StatusCode != 503 || dead[0].Log[0].ResponseBody != "down\n" {
+		t.Fatalf("dead letters %+v", dead)
+	}
+
+	// Replayed once the endpoint is back, it is delivered, with both attempts logged.
+	up.Store(true)
+	if ok, err := hooks.Replay(ctx, u.ID, ep.ID, dead[0].ID); err != nil || !ok {
+		t.Fatalf("Replay = %v, %v", ok, err)
+	}
+	if _, err := d.RunOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := log("delivered"); len(got) != 1 || len(got[0].Log) != 2 || got[0].Log[1].Error != nil {
+		t.Fatalf("delivery log %+v", got)
+	}
+}
+
+func TestStatementTimeout(t *testing.T) {
+	// Migrations (run by setup) lift the timeout for their session only.
+	pool := setup(t, platform.PoolOptions{MaxConns: 1, StatementTimeout: time.Second}).pool
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	var setting string
+	if err := pool.QueryRow(ctx, `SHOW statement_timeout`).Scan(&setting); err != nil || setting != "1s" {
+		t.Fatalf("statement_timeout after migrating = %q, %v", setting, err)
+	}
+
+	if _, err := pool.Exec(ctx, `SELECT pg_sleep(2)`); !repo.IsTimeout(err) {
+		t.Fatalf("statement_timeout: %v", err)
+	}
+	short, cancelShort := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancelShort()
+	if _, err := pool.Exec(short, `SELECT pg_sleep(0.5)`); !repo.IsTimeout(err) {
+		t.Fatalf("context deadline: %v", err)
+	}
+}
+
+func TestStreamOutlastsStatementTimeout(t *testing.T) {
+	e := setup(t, platform.PoolOptions{StatementTimeout: 200 * time.Millisecond})
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	u := e.user(t, "stream")
+	d := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := e.store.TransactionRepo().Create(ctx, &repo.Transaction{UserID: u.ID, Amount: 1, Type: "expense", Date: d}); err != nil {
+		t.Fatal(err)
+	}
+	// Enough rows that the server blocks on a full socket while the client dawdles.
+	if _, err := e.pool.Exec(ctx, `INSERT INTO transactions (user_id, amount, type, date, description)
+	                               SELECT $1, 1, 'expense', $2, repeat('x', 200) FROM generate_series(1, 20000)`, u.ID, d); err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	err := e.store.TransactionRepo().Each(ctx, u.ID, repo.TxnListFilter{}, func(*repo.Transaction) error {
+		if n++; n == 1 {
+			time.Sleep(time.Second)
+		}
+		return nil
+	})
+	if err != nil || n != 20001 {
+		t.Fatalf("streamed %d rows: %v", n, err)
+	}
+	var setting string
+	if err := e.pool.QueryRow(ctx, `SHOW statement_timeout`).Scan(&setting); err != nil || setting != "200ms" {
+		t.Fatalf("statement_timeout after streaming = %q, %v", setting, err)
+	}
+}
+
+func TestCategoryUsage(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	store := e.store
+	u := e.user(t, "usage")
+	food, err := store.CategoryRepo().Create(ctx, &repo.Category{UserID: u.ID, Name: "Food", Type: "expense"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CategoryRepo().Create(ctx, &repo.Category{UserID: u.ID, Name: "Unused", Type: "expense"}); err != nil {
+		t.Fatal(err)
+	}
+	last := time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC)
+	for _, d := range []time.Time{last.AddDate(0, -2, 0), last, last.AddDate(0, -1, 0)} {
+		if _, err := store.TransactionRepo().Create(ctx, &repo.Transaction{UserID: u.ID, CategoryID: &food.ID, Amount: 5, Type: "expense", Date: d}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cats, err := store.CategoryRepo().ListWithUsage(ctx, u.ID, false)
+	if err != nil || len(cats) != 2 {
+		t.Fatalf("ListWithUsage = %+v, %v", cats, err)
+	}
+	if us := cats[0].Usage; us.Transactions != 3 || us.LastUsed == nil || !us.LastUsed.Equal(last) {
+		t.Errorf("Food usage %+v, want 3 transactions, last on %s", us, last)
+	}
+	if us := cats[1].Usage; us.Transactions != 0 || us.LastUsed != nil {
+		t.Errorf("Unused usage %+v", us)
+	}
+}
+
+func TestCategoryGroupBudget(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	store := e.store
+	u := e.user(t, "groups")
+	other := e.user(t, "groups2")
+	g, err := store.CategoryGroupRepo().Create(ctx, u.ID, "Lifestyle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var members []*repo.Category
+	for _, name := range []string{"Dining", "Shopping"} {
+		c, err := store.CategoryRepo().Create(ctx, &repo.Category{UserID: u.ID, Name: name, Type: "expense", GroupID: &g.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		members = append(members, c)
+	}
+	rent, err := store.CategoryRepo().Create(ctx, &repo.Category{UserID: u.ID, Name: "Rent", Type: "expense"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)
+	for _, c := range []*repo.Category{members[0], members[1], rent} {
+		if _, err := store.TransactionRepo().Create(ctx, &repo.Transaction{UserID: u.ID, CategoryID: &c.ID, Amount: 60, Type: "expense", Date: day}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := store.BudgetRepo().Create(ctx, &repo.Budget{UserID: u.ID, GroupID: &g.ID, PeriodMonth: "2025-04", LimitAmount: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	over, err := store.AlertRepo().OverBudget(ctx, u.ID, "2025-04")
+	if err != nil || len(over) != 1 || over[0].BudgetID != b.ID || over[0].Spent != 120 || *over[0].CategoryName != "Lifestyle" {
+		t.Fatalf("OverBudget = %+v, %v; want the group budget with 120 spent", over, err)
+	}
+	usage, err := store.AlertRepo().BudgetUsage(ctx, u.ID, "2025-04", &members[1].ID)
+	if err != nil || len(usage) != 1 || usage[0].BudgetID != b.ID {
+		t.Errorf("BudgetUsage for a member = %+v, %v", usage, err)
+	}
+	if usage, _ := store.AlertRepo().BudgetUsage(ctx, u.ID, "2025-04", &rent.ID); len(usage) != 0 {
+		t.Errorf("BudgetUsage for a non-member = %+v", usage)
+	}
+
+	if _, err := store.BudgetRepo().Create(ctx, &repo.Budget{UserID: other.ID, GroupID: &g.ID, PeriodMonth: "2025-04", LimitAmount: 1}); !errors.Is(err, repo.ErrUnknownGroup) {
+		t.Errorf("budget on another user's group: err = %v, want ErrUnknownGroup", err)
+	}
+	if _, err := store.CategoryGroupRepo().Delete(ctx, u.ID, g.ID); !errors.Is(err, repo.ErrFKConflict) {
+		t.Errorf("deleting a budgeted group: err = %v, want ErrFKConflict", err)
+	}
+	if _, err := store.BudgetRepo().Delete(ctx, u.ID, b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := store.CategoryGroupRepo().Delete(ctx, u.ID, g.ID); err != nil || !ok {
+		t.Fatalf("Delete group = %v, %v", ok, err)
+	}
+	if c, err := store.CategoryRepo().Get(ctx, u.ID, members[0].ID); err != nil || c.GroupID != nil {
+		t.Errorf("member after deleting its group = %+v, %v; want ungrouped", c, err)
+	}
+}
+
+func TestNotifications(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	store := e.store
+	u := e.user(t, "inbox")
+	nr := store.NotificationRepo()
+	for _, n := range []repo.Notification{
+		{Event: "budget_alert", Key: "budget:1:warning", Title: "Food budget almost used up", Data: map[string]string{"budget_id": "1"}},
+		{Event: "budget_alert", Key: "budget:1:exceeded", Title: "Food budget exceeded"},
+		{Event: "import_finished", Key: "import:9", Title: "Import finished"},
+		{Event: "import_finished", Key: "import:9", Title: "Import finished (again)"},
+	} {
+		n.UserID = u.ID
+		if _, err := nr.Add(ctx, &n); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := nr.List(ctx, u.ID, repo.NotificationFilter{})
+	if err != nil || len(all) != 3 || all[2].Data["budget_id"] != "1" || all[0].Data == nil {
+		t.Fatalf("List = %+v, %v; want 3, newest first", all, err)
+	}
+	if unread, err := nr.Unread(ctx, u.ID); err != nil || unread["budget_alert"] != 2 || unread["import_finished"] != 1 {
+		t.Fatalf("Unread = %v, %v", unread, err)
+	}
+
+	n, err := nr.SetRead(ctx, u.ID, all[2].ID, true)
+	if err != nil || n == nil || n.ReadAt == nil {
+		t.Fatalf("SetRead = %+v, %v", n, err)
+	}
+	if n, _ := nr.SetRead(ctx, u.ID+1000000, all[2].ID, true); n != nil {
+		t.Error("SetRead marked another user's notification")
+	}
+	if changed, err := nr.SetReadAll(ctx, u.ID, nil, "budget_alert", true); err != nil || changed != 1 {
+		t.Fatalf("SetReadAll(budget_alert) = %d, %v; want the one still unread", changed, err)
+	}
+	if unread, _ := nr.List(ctx, u.ID, repo.NotificationFilter{Unread: true}); len(unread) != 1 || unread[0].Event != "import_finished" {
+		t.Errorf("unread after marking budget alerts = %+v", unread)
+	}
+	if changed, err := nr.SetReadAll(ctx, u.ID, []int64{all[2].ID}, "", false); err != nil || changed != 1 {
+		t.Fatalf("SetReadAll(unread) = %d, %v", changed, err)
+	}
+
+	ar := store.AlertRepo()
+	want := &repo.QuietHours{Start: 22 * 60, End: 7 * 60, Timezone: "Europe/Berlin"}
+	if err := ar.SetQuietHours(ctx, u.ID, want); err != nil {
+		t.Fatal(err)
+	}
+	if q, err := ar.QuietHours(ctx, u.ID); err != nil || q == nil || *q != *want {
+		t.Fatalf("QuietHours = %+v, %v", q, err)
+	}
+	if err := ar.SetQuietHours(ctx, u.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if q, err := ar.QuietHours(ctx, u.ID); err != nil || q != nil {
+		t.Fatalf("QuietHours after removing = %+v, %v", q, err)
+	}
+}
+
+func TestDisplayCurrency(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	pool, store := e.pool, e.store
+	u := e.user(t, "fx")
+
+	// XTS is the ISO 4217 code reserved for testing, so no provider stores it.
+	jan := time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2001, 2, 1, 0, 0, 0, 0, time.UTC)
+	if err := store.RateRepo().SaveRates(ctx, jan, "EUR", map[string]float64{"XTS": 2}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RateRepo().SaveRates(ctx, feb, "EUR", map[string]float64{"XTS": 4}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		from, to string
+		on       time.Time
+		want     float64
+	}{
+		{"EUR", "XTS", jan.AddDate(0, 0, 20), 2},
+		{"EUR", "XTS", feb.AddDate(0, 0, 5), 4},
+		{"XTS", "EUR", feb, 0.25},
+		{"EUR", "XTS", jan.AddDate(-1, 0, 0), 2}, // before any stored day: the earliest one
+		{"XTS", "XTS", jan, 1},
+	} {
+		var got float64
+		if err := pool.QueryRow(ctx, `SELECT fx_rate($1, $2, $3::date)::float8`, tc.from, tc.to, tc.on).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("fx_rate(%s, %s, %s) = %v, want %v", tc.from, tc.to, tc.on.Format("2006-01-02"), got, tc.want)
+		}
+	}
+
+	for _, d := range []time.Time{jan.AddDate(0, 0, 9), feb.AddDate(0, 0, 9)} {
+		if _, err := store.TransactionRepo().Create(ctx, &repo.Transaction{UserID: u.ID, Amount: 10, Type: "expense", Date: d}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	conv, ok := repo.NewConversion("EUR", "XTS")
+	if !ok || !conv.Active() {
+		t.Fatalf("NewConversion = %+v, %v", conv, ok)
+	}
+	cmp, err := store.ReportRepo().In(conv).Compare(ctx, u.ID, "2001-01", "2001-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp.ExpenseA != 20 || cmp.ExpenseB != 40 {
+		t.Errorf("converted expenses = %v, %v; want 20, 40 at each month's rate", cmp.ExpenseA, cmp.ExpenseB)
+	}
+	plain, err := store.ReportRepo().Compare(ctx, u.ID, "2001-01", "2001-02")
+	if err != nil || plain.ExpenseA != 10 || plain.ExpenseB != 10 {
+		t.Errorf("unconverted comparison = %+v, %v", plain, err)
+	}
+	week, err := store.DashboardRepo().In(conv).WeekSummary(ctx, u.ID, feb.AddDate(0, 0, 7))
+	if err != nil || week.ExpenseTotal != 40 {
+		t.Errorf("converted week = %+v, %v; want 40", week, err)
+	}
+	top, err := store.DashboardRepo().In(conv).TopExpenses(ctx, u.ID, "2001-02", 5)
+	if err != nil || len(top) != 1 || top[0].Amount != 40 {
+		t.Errorf("converted top expenses = %+v, %v; want one of 40", top, err)
+	}
+	acct, err := store.AccountRepo().Create(ctx, &repo.Account{UserID: u.ID, Name: "Cash", OpeningBalance: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []time.Time{jan.AddDate(0, 0, 9), feb.AddDate(0, 0, 9)} {
+		if _, err := store.TransactionRepo().Create(ctx, &repo.Transaction{UserID: u.ID, Amount: 10, Type: "expense", Date: d, AccountID: &acct.ID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The opening balance converts at February's rate, January's expense at January's.
+	st, err := store.AccountRepo().In(conv).Statement(ctx, u.ID, acct.ID, feb, feb.AddDate(0, 0, 27))
+	if err != nil || st.OpeningBalance != 380 || len(st.Transactions) != 1 || st.Transactions[0].Amount != 40 || st.ClosingBalance != 340 {
+		t.Errorf("converted statement = %+v, %v; want 380 - 40 = 340", st, err)
+	}
+
+	if p, err := store.UserRepo().GetPreferences(ctx, u.ID); err != nil || p.BaseCurrency != "EUR" {
+		t.Fatalf("default base currency = %+v, %v", p, err)
+	}
+	p, err := store.UserRepo().UpdatePreferences(ctx, u.ID, &repo.Preferences{WeekStart: time.Monday, BaseCurrency: "XTS"})
+	if err != nil || p.BaseCurrency != "XTS" {
+		t.Fatalf("UpdatePreferences = %+v, %v", p, err)
+	}
+	if p, _ := store.UserRepo().UpdatePreferences(ctx, u.ID, &repo.Preferences{WeekStart: time.Monday}); p.BaseCurrency != "XTS" {
+		t.Errorf("empty base currency replaced it: %+v", p)
+	}
+}
+
+func TestCategoryTxnLimit(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	store := e.store
+	u := e.user(t, "lim")
+	limited := func(name string, limit float64, mode string) int64 {
+		cat, err := store.CategoryRepo().Create(ctx, &repo.Category{UserID: u.ID, Name: name, Type: "expense", TxnLimit: &limit, TxnLimitMode: &mode})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cat.ID
+	}
+	blocked, warned := limited("Gadgets", 50, "block"), limited("Eating out", 20, "warn")
+
+	api := handler.New(store, "testsecret")
+	r := gin.New()
+	r.POST("/api/transactions", func(c *gin.Context) { c.Set("uid", u.ID); c.Next() }, api.CreateTransaction)
+	post := func(category int64, amount string, extra string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		body := `{"category_id":` + strconv.FormatInt(category, 10) + `,"amount":` + amount + `,"type":"expense","date":"2026-10-01"` + extra + `}`
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/api/transactions", strings.NewReader(body)))
+		return w
+	}
+
+	if w := post(blocked, "80", ""); w.Code != 409 || !strings.Contains(w.Body.String(), "category_limit_exceeded") {
+		t.Fatalf("over a block limit got %d: %s", w.Code, w.Body)
+	}
+	if w := post(blocked, "50", ""); w.Code != 201 || strings.Contains(w.Body.String(), "limit_warning") {
+		t.Fatalf("at the limit got %d: %s", w.Code, w.Body)
+	}
+	if w := post(blocked, "80", `,"override_limit":true`); w.Code != 201 || !strings.Contains(w.Body.String(), `"mode":"block"`) {
+		t.Fatalf("overridden block got %d: %s", w.Code, w.Body)
+	}
+	if w := post(warned, "25", ""); w.Code != 201 || !strings.Contains(w.Body.String(), `"limit_warning"`) {
+		t.Fatalf("over a warn limit got %d: %s", w.Code, w.Body)
+	}
+
+	// The limit is enforced by the repo, so every write path gets it, and an edit is
+	// only checked again when it changes what the limit is about.
+	txs := store.TransactionRepo()
+	d := time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC)
+	over, err := txs.Create(ctx, &repo.Transaction{UserID: u.ID, CategoryID: &blocked, Amount: 80, Type: "expense", Date: d, OverrideLimit: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	over.Description = "renamed"
+	over.OverrideLimit = false
+	if _, err := txs.Update(ctx, u.ID, over.ID, over); err != nil {
+		t.Fatalf("a description-only edit was checked against the limit: %v", err)
+	}
+	over.Amount = 90
+	var limitErr *repo.LimitError
+	if _, err := txs.Update(ctx, u.ID, over.ID, over); !errors.As(err, &limitErr) || limitErr.Limit != 50 {
+		t.Fatalf("raising the amount got %v", err)
+	}
+
+	draft, err := store.DraftRepo().Create(ctx, &repo.Draft{UserID: u.ID, Source: repo.DraftSourceEmail, CategoryID: &blocked, Type: "expense", Amount: 70, Date: d})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.ApproveDraft(ctx, u.ID, draft.ID, nil, false); !errors.As(err, &limitErr) {
+		t.Fatalf("approving a draft over the limit got %v", err)
+	}
+	if out, err := store.ApproveDraft(ctx, u.ID, draft.ID, nil, true); err != nil || out.LimitWarning == nil {
+		t.Fatalf("overridden draft approval = %+v, %v", out, err)
+	}
+
+	item, err := store.WishlistRepo().Create(ctx, &repo.WishlistItem{UserID: u.ID, CategoryID: &blocked, Name: "Headphones", EstimatedPrice: 120})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buy := &repo.Transaction{UserID: u.ID, CategoryID: &blocked, Amount: 120, Date: d, Description: "Headphones"}
+	if _, err := store.WishlistRepo().Purchase(ctx, u.ID, item.ID, buy); !errors.As(err, &limitErr) {
+		t.Fatalf("buying a wishlist item over the limit got %v", err)
+	}
+	buy.OverrideLimit = true
+	if got, err := store.WishlistRepo().Purchase(ctx, u.ID, item.ID, buy); err != nil || got == nil || got.TransactionID == nil {
+		t.Fatalf("overridden purchase = %+v, %v", got, err)
+	}
+}
+
+func TestParseTransaction(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	store := e.store
+	u := e.user(t, "qe")
+	food, err := store.CategoryRepo().Create(ctx, &repo.Category{UserID: u.ID, Name: "Eating out", Type: "expense"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	salary, err := store.CategoryRepo().Create(ctx, &repo.Category{UserID: u.ID, Name: "Salary", Type: "income"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cash, err := store.AccountRepo().Create(ctx, &repo.Account{UserID: u.ID, Name: "Cash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := handler.New(store, "testsecret")
+	r := gin.New()
+	r.POST("/api/transactions/parse", func(c *gin.Context) { c.Set("uid", u.ID); c.Next() }, api.ParseTransaction)
+	parse := func(text string) map[string]any {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("POST", "/api/transactions/parse", strings.NewReader(`{"text":"`+text+`","today":"2026-10-16"}`)))
+		var out map[string]any
+		if w.Code != 200 || json.Unmarshal(w.Body.Bytes(), &out) != nil {
+			t.Fatalf("parse %q got %d: %s", text, w.Code, w.Body)
+		}
+		return out
+	}
+
+	got := parse("eating out 12.50 yesterday, cash")
+	if got["amount"] != 12.5 || got["date"] != "2026-10-15" || got["type"] != "expense" || got["description"] != "eating out" ||
+		got["category_id"] != float64(food.ID) || got["account_id"] != float64(cash.ID) {
+		t.Errorf("expense draft = %v", got)
+	}
+	if got := parse("salary 3200"); got["type"] != "income" || got["category_id"] != float64(salary.ID) || got["account_id"] != nil {
+		t.Errorf("income draft = %v", got)
+	}
+}
+
+func TestSavingsRateReimbursed(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	u := e.user(t, "savings")
+	sep := time.Date(2026, 9, 10, 0, 0, 0, 0, time.UTC)
+	var claimed int64
+	for _, txn := range []repo.Transaction{
+		{Amount: 1000, Type: "income"},
+		{Amount: 200, Type: "expense"},
+		{Amount: 300, Type: "expense", Reimbursable: true},
+	} {
+		txn.UserID, txn.Date = u.ID, sep
+		out, err := e.store.TransactionRepo().Create(ctx, &txn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		claimed = out.ID
+	}
+	// The reimbursable expense was paid back: it no longer counts as spending.
+	if _, err := e.pool.Exec(ctx, `UPDATE transactions SET reimbursed=TRUE WHERE id=$1`, claimed); err != nil {
+		t.Fatal(err)
+	}
+	m, err := e.store.DashboardRepo().Summary(ctx, u.ID, "2026-09")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.ExpenseTotal != 200 || m.SavingsRate == nil || *m.SavingsRate != 0.8 {
+		t.Errorf("summary = %+v (savings rate %v), want expenses 200 and rate 0.8", m, m.SavingsRate)
+	}
+}
+
+func TestClaimClosedPeriod(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	u := e.user(t, "claimclosed")
+	txn, err := e.store.TransactionRepo().Create(ctx, &repo.Transaction{UserID: u.ID, Amount: 80, Type: "expense",
+		Date: time.Date(2026, 8, 12, 0, 0, 0, 0, time.UTC), Reimbursable: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := e.store.ClaimRepo()
+	claim, err := claims.Create(ctx, u.ID, "Conference", []int64{txn.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := claims.SetStatus(ctx, u.ID, claim.ID, repo.ClaimSubmitted, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.store.PeriodRepo().Close(ctx, u.ID, "2026-08"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Paying or deleting the claim would change August's spending.
+	if _, err := claims.SetStatus(ctx, u.ID, claim.ID, repo.ClaimPaid, time.Now()); !errors.Is(err, repo.ErrPeriodClosed) {
+		t.Errorf("pay claim in closed month: err = %v, want ErrPeriodClosed", err)
+	}
+	if _, err := claims.Delete(ctx, u.ID, claim.ID); !errors.Is(err, repo.ErrPeriodClosed) {
+		t.Errorf("delete claim in closed month: err = %v, want ErrPeriodClosed", err)
+	}
+	var reimbursed bool
+	if err := e.pool.QueryRow(ctx, `SELECT reimbursed FROM transactions WHERE id=$1`, txn.ID).Scan(&reimbursed); err != nil {
+		t.Fatal(err)
+	}
+	if reimbursed {
+		t.Error("transaction in closed month was marked reimbursed")
+	}
+
+	if _, err := e.store.PeriodRepo().Reopen(ctx, u.ID, "2026-08"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := claims.SetStatus(ctx, u.ID, claim.ID, repo.ClaimPaid, time.Now()); err != nil {
+		t.Errorf("pay claim after reopening: %v", err)
+	}
+}
+
+func TestClosedPeriodRace(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	u := e.user(t, "closerace")
+	d := time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC)
+	txn, err := e.store.TransactionRepo().Create(ctx, &repo.Transaction{UserID: u.ID, Amount: 10, Type: "expense", Date: d})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A write in flight holds the user row, so closing its month waits for it.
+	tx, err := e.pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if _, err := tx.Exec(ctx, `INSERT INTO transactions (user_id, amount, type, date) VALUES ($1, 20, 'expense', $2)`, u.ID, d); err != nil {
+		t.Fatal(err)
+	}
+	closed := make(chan error, 1)
+	go func() {
+		_, err := e.store.PeriodRepo().Close(ctx, u.ID, "2026-07")
+		closed <- err
+	}()
+	select {
+	case err := <-closed:
+		t.Fatalf("Close returned before the write in flight committed: %v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-closed; err != nil {
+		t.Fatal(err)
+	}
+
+	// Past the repos' own check, the trigger still refuses changes to the month's totals.
+	var pgerr *pgconn.PgError
+	if _, err := e.pool.Exec(ctx, `UPDATE transactions SET amount=1 WHERE id=$1`, txn.ID); !errors.As(err, &pgerr) || pgerr.ConstraintName != "closed_period" {
+		t.Errorf("amount change in a closed month: %v", err)
+	}
+	if _, err := e.pool.Exec(ctx, `DELETE FROM transactions WHERE id=$1`, txn.ID); !errors.As(err, &pgerr) || pgerr.ConstraintName != "closed_period" {
+		t.Errorf("delete in a closed month: %v", err)
+	}
+	if _, err := e.pool.Exec(ctx, `INSERT INTO transactions (user_id, amount, type, date) VALUES ($1, 5, 'expense', $2)`, u.ID, d); !errors.As(err, &pgerr) {
+		t.Errorf("insert in a closed month: %v", err)
+	}
+	if _, err := e.pool.Exec(ctx, `UPDATE transactions SET description='renamed' WHERE id=$1`, txn.ID); err != nil {
+		t.Errorf("description-only change in a closed month: %v", err)
+	}
+}
+
+func TestProjectionFixedCosts(t *testing.T) {
+	ctx := context.Background()
+	e := setup(t)
+	u := e.user(t, "projection")
+	now := time.Now().UTC().Truncate(24 * time.Hour)
+	rule, err := e.store.RecurringRuleRepo().Create(ctx, &repo.RecurringRule{UserID: u.ID, Type: "expense", Amount: 500,
+		Description: "Rent", Interval: "weekly", StartDate: now.AddDate(0, 0, -60), Active: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := e.store.GenerateRecurring(ctx, now); err != nil || n[u.ID] == 0 {
+		t.Fatalf("GenerateRecurring = %v, %v", n, err)
+	}
+	if _, err := e.store.TransactionRepo().Create(ctx, &repo.Transaction{UserID: u.ID, Amount: 90, Type: "expense", Date: now.AddDate(0, 0, -10)}); err != nil {
+		t.Fatal(err)
+	}
+	txs, err := e.store.TransactionRepo().List(ctx, u.ID, repo.TxnListFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, txn := range txs {
+		if txn.Amount == 500 && (txn.RuleID == nil || *txn.RuleID != rule.ID) {
+			t.Errorf("generated transaction %d has rule %v, want %d", txn.ID, txn.RuleID, rule.ID)
+		}
+	}
+
+	p, err := e.store.DashboardRepo().Projection(ctx, u.ID, now.Format("2006-01"), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the 90 spent outside the rule count towards the average.
+	if p.AvgDailySpend != 1 {
+		t.Errorf("avg daily spend = %v, want 1", p.AvgDailySpend)
+	}
+	// A week or more ahead always holds an occurrence of the weekly rule.
+	if p.RemainingDays >= 7 && p.ExpenseRecurring < 500 {
+		t.Errorf("expense recurring = %v with %d days left, want the rule's coming occurrences", p.ExpenseRecurring, p.RemainingDays)
+	}
+}
@@ -0,0 +1,191 @@
+// backend/internal/alerts/alerts.go
+
+// Package alerts raises notifications about a user's money (budget thresholds, bill
+// reminders, unusual expenses) and finished imports, and delivers them over
+// notification channels: the in-app notification center (Inbox), email, mobile push,
+// Telegram and Slack or Discord webhooks. Each alert is raised once (see repo.AlertRepo.MarkSent)
+// and delivered by one "alerts.deliver" job per channel the user has not switched
+// off, so a failing channel is retried on its own. During the user's quiet hours
+// those jobs are scheduled for when they end, except the in-app one.
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"pft/internal/jobs"
+	"pft/internal/repo"
+)
+
+// Alert events, the unit of notification preferences.
+const (
+	EventBudget  = "budget_alert"
+	EventBill    = "bill_reminder"
+	EventAnomaly = "anomaly"
+	EventWeekly  = "weekly_summary"
+	EventImport  = "import_finished"
+)
+
+// Events lists every alert event.
+var Events = []string{EventBudget, EventBill, EventAnomaly, EventWeekly, EventImport}
+
+// Notification channels.
+const (
+	ChannelInApp    = "inapp"
+	ChannelEmail    = "email"
+	ChannelPush     = "push"
+	ChannelTelegram = "telegram"
+	ChannelSlack    = "slack"
+	ChannelDiscord  = "discord"
+)
+
+// Channels lists every notification channel users can choose.
+var Channels = []string{ChannelInApp, ChannelEmail, ChannelPush, ChannelTelegram, ChannelSlack, ChannelDiscord}
+
+// DefaultEnabled reports whether event is delivered on channel for users who made no
+// choice: everything is, except weekly summaries on phones, finished imports anywhere
+// but in the app, and anything but weekly summaries by email.
+func DefaultEnabled(event, channel string) bool {
+	if channel == ChannelEmail {
+		return event == EventWeekly
+	}
+	switch event {
+	case EventWeekly:
+		return channel != ChannelPush
+	case EventImport:
+		return channel == ChannelInApp
+	}
+	return true
+}
+
+// JobKind is the job kind delivering one alert over one channel.
+const JobKind = "alerts.deliver"
+
+// Alert is one notification, already worded in the user's language.
+// - Key: identifies the alert per user, so it is raised once (e.g. "bill:5:2026-10-20")
+// - Data: machine-readable details for apps (ids, dates), passed along with pushes
+type Alert struct {
+	UserID int64             `json:"user_id"`
+	Event  string            `json:"event"`
+	Key    string            `json:"key"`
+	Title  string            `json:"title"`
+	Body   string            `json:"body"`
+	Data   map[string]string `json:"data,omitempty"`
+}
+
+// Channel delivers alerts to users, e.g. as push notifications.
+type Channel interface {
+	// Name is the channel's entry in Channels.
+	Name() string
+	// Deliver sends a to its user. Errors wrapped with jobs.Permanent are not retried.
+	Deliver(ctx context.Context, a *Alert) error
+}
+
+// Store records raised alerts and holds notification preferences; implemented by repo.AlertRepo.
+type Store interface {
+	MarkSent(ctx context.Context, userID int64, key string) (bool, error)
+	UnmarkSent(ctx context.Context, userID int64, key string) error
+	NotificationEnabled(ctx context.Context, userID int64, event, channel string, def bool) (bool, error)
+	QuietHours(ctx context.Context, userID int64) (*repo.QuietHours, error)
+}
+
+// Queue holds delivery jobs; implemented by repo.JobRepo.
+type Queue interface {
+	Enqueue(ctx context.Context, j *repo.Job) (*repo.Job, error)
+}
+
+// Notifier raises alerts and delivers them over its channels.
+type Notifier struct {
+	Store Store
+	Queue Queue
+
+	channels map[string]Channel
+	now      func() time.Time
+}
+
+// AddChannel makes c available for delivery. Call before raising alerts.
+func (n *Notifier) AddChannel(c Channel) {
+	if n.channels == nil {
+		n.channels = map[string]Channel{}
+	}
+	n.channels[c.Name()] = c
+}
+
+// delivery is the payload of a JobKind job.
+type delivery struct {
+	Channel string `json:"channel"`
+	Alert   *Alert `json:"alert"`
+}
+
+// Raise queues a for delivery on every channel the user wants its event on.
+// An alert whose key was raised before is ignored.
+func (n *Notifier) Raise(ctx context.Context, a *Alert) error {
+	fresh, err := n.Store.MarkSent(ctx, a.UserID, a.Key)
+	if err != nil || !fresh {
+		return err
+	}
+	if err := n.queue(ctx, a); err != nil {
+		// Raise it again next time rather than lose it.
+		if uerr := n.Store.UnmarkSent(context.WithoutCancel(ctx), a.UserID, a.Key); uerr != nil {
+			return fmt.Errorf("%w (and unmarking failed: %v)", err, uerr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (n *Notifier) queue(ctx context.Context, a *Alert) error {
+	quiet, err := n.Store.QuietHours(ctx, a.UserID)
+	if err != nil {
+		return err
+	}
+	now := time.Now
+	if n.now != nil {
+		now = n.now
+	}
+	hold := quietUntil(quiet, now())
+	for _, name := range Channels {
+		if _, ok := n.channels[name]; !ok {
+			continue
+		}
+		on, err := n.Store.NotificationEnabled(ctx, a.UserID, a.Event, name, DefaultEnabled(a.Event, name))
+		if err != nil {
+			return err
+		}
+		if !on {
+			continue
+		}
+		payload, err := json.Marshal(delivery{Channel: name, Alert: a})
+		if err != nil {
+			return err
+		}
+		uid := a.UserID
+		j := &repo.Job{Kind: JobKind, UserID: &uid, Payload: payload}
+		if name != ChannelInApp {
+			j.RunAt = hold
+		}
+		if _, err := n.Queue.Enqueue(ctx, j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handle is the jobs.HandlerFunc for JobKind.
+func (n *Notifier) Handle(ctx context.Context, j *repo.Job) error {
+	var d delivery
+	if err := json.Unmarshal(j.Payload, &d); err != nil {
+		return jobs.Permanent(fmt.Errorf("decode alert: %w", err))
+	}
+	if d.Alert == nil {
+		return jobs.Permanent(errors.New("payload has no alert"))
+	}
+	c, ok := n.channels[d.Channel]
+	if !ok {
+		return jobs.Permanent(fmt.Errorf("channel %q is not configured", d.Channel))
+	}
+	return c.Deliver(ctx, d.Alert)
+}
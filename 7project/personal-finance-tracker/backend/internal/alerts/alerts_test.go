@@ -0,0 +1,280 @@
+// backend/internal/alerts/alerts_test.go
+//
+// Purpose:
+//   Verify that expenses raise budget and anomaly alerts once, in the user's
+//   language, that bill reminders and weekly summaries are raised per occurrence,
+//   that finished imports are reported in the app only by default, that the in-app
+//   channel files each alert once, that alerts are queued only for channels the
+//   user keeps enabled, and that quiet hours hold all but the in-app delivery.
+
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"pft/internal/repo"
+)
+
+type fakeStore struct {
+	sent     map[string]bool
+	disabled map[string]bool // "event/channel"
+	quiet    *repo.QuietHours
+}
+
+func (s *fakeStore) MarkSent(_ context.Context, userID int64, key string) (bool, error) {
+	if s.sent[key] {
+		return false, nil
+	}
+	s.sent[key] = true
+	return true, nil
+}
+
+func (s *fakeStore) UnmarkSent(_ context.Context, _ int64, key string) error {
+	delete(s.sent, key)
+	return nil
+}
+
+func (s *fakeStore) NotificationEnabled(_ context.Context, _ int64, event, channel string, def bool) (bool, error) {
+	return def && !s.disabled[event+"/"+channel], nil
+}
+
+func (s *fakeStore) QuietHours(context.Context, int64) (*repo.QuietHours, error) {
+	return s.quiet, nil
+}
+
+type fakeQueue struct{ jobs []*repo.Job }
+
+func (q *fakeQueue) Enqueue(_ context.Context, j *repo.Job) (*repo.Job, error) {
+	q.jobs = append(q.jobs, j)
+	return j, nil
+}
+
+type fakeChannel struct {
+	name string
+	got  []*Alert
+}
+
+func (c *fakeChannel) Name() string { return c.name }
+func (c *fakeChannel) Deliver(_ context.Context, a *Alert) error {
+	c.got = append(c.got, a)
+	return nil
+}
+
+type fakeSource struct {
+	usage []repo.BudgetUsage
+	stats repo.ExpenseStats
+	bills []repo.UpcomingBill
+	weeks []repo.WeeklyTotals
+}
+
+func (s *fakeSource) BudgetUsage(context.Context, int64, string, *int64) ([]repo.BudgetUsage, error) {
+	return s.usage, nil
+}
+func (s *fakeSource) ExpenseStats(context.Context, int64, *int64, time.Time, time.Time, int64) (*repo.ExpenseStats, error) {
+	return &s.stats, nil
+}
+func (s *fakeSource) BillReminders(context.Context, time.Time) ([]repo.UpcomingBill, error) {
+	return s.bills, nil
+}
+func (s *fakeSource) WeeklySummaries(context.Context, time.Time) ([]repo.WeeklyTotals, error) {
+	return s.weeks, nil
+}
+
+func setup() (*Detector, *fakeSource, *fakeStore, *fakeQueue, *fakeChannel) {
+	st, q, ch := &fakeStore{sent: map[string]bool{}, disabled: map[string]bool{}}, &fakeQueue{}, &fakeChannel{name: ChannelPush}
+	n := &Notifier{Store: st, Queue: q}
+	n.AddChannel(ch)
+	src := &fakeSource{}
+	d := &Detector{Source: src, Notifier: n, Locale: func(context.Context, int64) string { return "de" }}
+	return d, src, st, q, ch
+}
+
+// deliver runs the queued jobs through the notifier.
+func deliver(t *testing.T, d *Detector, q *fakeQueue) {
+	t.Helper()
+	for _, j := range q.jobs {
+		if err := d.Notifier.Handle(context.Background(), j); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.jobs = nil
+}
+
+func expense(action string, id int64, amount float64) *repo.OutboxEvent {
+	p, _ := json.Marshal(map[string]any{"action": action, "data": map[string]any{
+		"id": id, "user_id": 7, "category_id": 3, "amount": amount, "type": "expense", "date": "2026-10-12", "description": "TV",
+	}})
+	return &repo.OutboxEvent{UserID: 7, Topic: "transactions." + action + "d", Payload: p}
+}
+
+func TestDetector_Budgets(t *testing.T) {
+	d, src, _, q, ch := setup()
+	food := "Food"
+	src.usage = []repo.BudgetUsage{{BudgetID: 1, CategoryName: &food, Limit: 100, Spent: 50}}
+	if err := d.OnEvent(context.Background(), expense("create", 10, 20)); err != nil || len(q.jobs) != 0 {
+		t.Fatalf("under the threshold: jobs %d, %v", len(q.jobs), err)
+	}
+
+	src.usage[0].Spent = 85
+	_ = d.OnEvent(context.Background(), expense("create", 11, 35))
+	_ = d.OnEvent(context.Background(), expense("update", 11, 35))
+	deliver(t, d, q)
+	if len(ch.got) != 1 || ch.got[0].Key != "budget:1:warning" || !strings.Contains(ch.got[0].Body, "85 %") {
+		t.Fatalf("warning once in German, got %+v", ch.got)
+	}
+
+	src.usage[0].Spent = 120
+	_ = d.OnEvent(context.Background(), expense("create", 12, 35))
+	deliver(t, d, q)
+	if len(ch.got) != 2 || ch.got[1].Title != "Budget Food überschritten" {
+		t.Fatalf("exceeded alert, got %+v", ch.got)
+	}
+}
+
+func TestDetector_Anomaly(t *testing.T) {
+	d, src, _, q, ch := setup()
+	src.stats = repo.ExpenseStats{Count: 10, Mean: 40, StdDev: 10}
+	_ = d.OnEvent(context.Background(), expense("create", 20, 75))
+	if len(q.jobs) != 0 {
+		t.Fatal("75 is within 3 standard deviations of 40 and should not alert")
+	}
+	_ = d.OnEvent(context.Background(), expense("update", 21, 500))
+	if len(q.jobs) != 0 {
+		t.Fatal("edited expenses should not alert as unusual")
+	}
+	_ = d.OnEvent(context.Background(), expense("create", 21, 500))
+	deliver(t, d, q)
+	if len(ch.got) != 1 || ch.got[0].Event != EventAnomaly || ch.got[0].Data["transaction_id"] != "21" {
+		t.Fatalf("anomaly alert, got %+v", ch.got)
+	}
+
+	src.stats.Count = 3
+	_ = d.OnEvent(context.Background(), expense("create", 22, 500))
+	if len(q.jobs) != 0 {
+		t.Fatal("too little history should not alert")
+	}
+}
+
+func TestDetector_BillRemindersAndPreferences(t *testing.T) {
+	d, src, st, q, ch := setup()
+	bill := func(days int, date string) repo.UpcomingBill {
+		return repo.UpcomingBill{Bill: repo.Bill{ID: 5, UserID: 7, Name: "Rent", Amount: 900}, DueDate: date, DaysLeft: days, Remind: true}
+	}
+	src.bills = []repo.UpcomingBill{bill(2, "2026-10-14")}
+	_ = d.BillReminders(context.Background(), time.Now())
+	_ = d.BillReminders(context.Background(), time.Now())
+	deliver(t, d, q)
+	if len(ch.got) != 1 || ch.got[0].Body != "Rent (900.00) ist am 2026-10-14 fällig." {
+		t.Fatalf("one reminder per occurrence, got %+v", ch.got)
+	}
+
+	st.disabled[EventBill+"/"+ChannelPush] = true
+	src.bills = []repo.UpcomingBill{bill(0, "2026-11-14")}
+	_ = d.BillReminders(context.Background(), time.Now())
+	if len(q.jobs) != 0 {
+		t.Fatal("disabled channel should not be queued")
+	}
+}
+
+func TestDetector_WeeklySummaries(t *testing.T) {
+	d, src, _, q, push := setup()
+	slack := &fakeChannel{name: ChannelSlack}
+	d.Notifier.AddChannel(slack)
+	food := "Food"
+	src.weeks = []repo.WeeklyTotals{{UserID: 7, WeekStart: time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC),
+		Income: 1000, Expense: 420.5, TopCategory: &food, TopAmount: 180}}
+	_ = d.WeeklySummaries(context.Background(), time.Now())
+	_ = d.WeeklySummaries(context.Background(), time.Now())
+	deliver(t, d, q)
+	if len(push.got) != 0 {
+		t.Fatal("weekly summaries should not go to phones by default")
+	}
+	want := "Einnahmen 1000.00, Ausgaben 420.50, netto 579.50. Am meisten ausgegeben für Food (180.00)."
+	if len(slack.got) != 1 || slack.got[0].Body != want || slack.got[0].Title != "Deine Woche vom 2026-10-05 bis 2026-10-11" {
+		t.Fatalf("one summary on Slack, got %+v", slack.got)
+	}
+}
+
+type fakeNotifications struct{ filed map[string]repo.Notification }
+
+func (f *fakeNotifications) Add(_ context.Context, n *repo.Notification) (bool, error) {
+	if _, ok := f.filed[n.Key]; ok {
+		return false, nil
+	}
+	f.filed[n.Key] = *n
+	return true, nil
+}
+
+func TestDetector_ImportFinishedInbox(t *testing.T) {
+	d, _, _, q, push := setup()
+	inbox := &fakeNotifications{filed: map[string]repo.Notification{}}
+	d.Notifier.AddChannel(&Inbox{Notifications: inbox})
+	imp := &repo.Import{ID: 4, UserID: 7, Filename: "bank.csv", Status: "done", RowsTotal: 10, RowsImported: 9, RowsFailed: 1}
+	_ = d.ImportFinished(context.Background(), imp)
+	_ = d.ImportFinished(context.Background(), &repo.Import{ID: 5, UserID: 7, Status: "cancelled"})
+	deliver(t, d, q)
+	if len(push.got) != 0 {
+		t.Fatal("finished imports should not go to phones by default")
+	}
+	n, ok := inbox.filed["import:4"]
+	if len(inbox.filed) != 1 || !ok || n.Event != EventImport || n.Body != "bank.csv: 9 von 10 Zeilen importiert, 1 fehlgeschlagen." {
+		t.Fatalf("one import notification in the inbox, got %+v", inbox.filed)
+	}
+
+	// A redelivered alert is filed once.
+	if err := (&Inbox{Notifications: inbox}).Deliver(context.Background(), &Alert{UserID: 7, Key: "import:4"}); err != nil || len(inbox.filed) != 1 {
+		t.Fatalf("redelivery filed %d, %v", len(inbox.filed), err)
+	}
+}
+
+func TestQuietUntil(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	night := &repo.QuietHours{Start: 22 * 60, End: 7 * 60, Timezone: "Europe/Berlin"}
+	lunch := &repo.QuietHours{Start: 12 * 60, End: 13*60 + 30, Timezone: "Nowhere/Unknown"}
+	at := func(loc *time.Location, d, h, m int) time.Time { return time.Date(2026, 10, d, h, m, 0, 0, loc) }
+	cases := []struct {
+		q    *repo.QuietHours
+		now  time.Time
+		want time.Time
+	}{
+		{nil, at(berlin, 12, 23, 0), time.Time{}},
+		{night, at(berlin, 12, 23, 30), at(berlin, 13, 7, 0)},
+		{night, at(berlin, 13, 6, 59), at(berlin, 13, 7, 0)},
+		{night, at(berlin, 13, 7, 0), time.Time{}},
+		{night, at(time.UTC, 12, 20, 30), at(berlin, 13, 7, 0)}, // 22:30 in Berlin
+		{night, at(berlin, 24, 23, 0), at(berlin, 25, 7, 0)},    // clocks go back overnight
+		{lunch, at(time.UTC, 12, 12, 15), at(time.UTC, 12, 13, 30)},
+		{lunch, at(time.UTC, 12, 11, 59), time.Time{}},
+	}
+	for _, tc := range cases {
+		if got := quietUntil(tc.q, tc.now); !got.Equal(tc.want) {
+			t.Errorf("quietUntil(%+v, %s) = %s, want %s", tc.q, tc.now, got, tc.want)
+		}
+	}
+}
+
+func TestNotifier_QuietHours(t *testing.T) {
+	d, src, st, q, _ := setup()
+	d.Notifier.AddChannel(&Inbox{Notifications: &fakeNotifications{filed: map[string]repo.Notification{}}})
+	d.Notifier.AddChannel(&fakeChannel{name: ChannelEmail})
+	st.quiet = &repo.QuietHours{Start: 22 * 60, End: 7 * 60, Timezone: "UTC"}
+	d.Notifier.now = func() time.Time { return time.Date(2026, 10, 12, 23, 0, 0, 0, time.UTC) }
+	src.bills = []repo.UpcomingBill{{Bill: repo.Bill{ID: 5, UserID: 7, Name: "Rent", Amount: 900}, DueDate: "2026-10-14", DaysLeft: 2, Remind: true}}
+	if err := d.BillReminders(context.Background(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	morning := time.Date(2026, 10, 13, 7, 0, 0, 0, time.UTC)
+	runAt := map[string]time.Time{}
+	for _, j := range q.jobs {
+		var dl delivery
+		_ = json.Unmarshal(j.Payload, &dl)
+		runAt[dl.Channel] = j.RunAt
+	}
+	if len(runAt) != 2 || !runAt[ChannelInApp].IsZero() || !runAt[ChannelPush].Equal(morning) {
+		t.Fatalf("run at %v; want in-app now and push at %s (bill reminders are not mailed by default)", runAt, morning)
+	}
+}
@@ -0,0 +1,249 @@
+// backend/internal/alerts/detect.go
+
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"pft/internal/i18n"
+	"pft/internal/repo"
+)
+
+// Source is the data alerts are raised from; implemented by repo.AlertRepo.
+type Source interface {
+	BudgetUsage(ctx context.Context, userID int64, month string, categoryID *int64) ([]repo.BudgetUsage, error)
+	ExpenseStats(ctx context.Context, userID int64, categoryID *int64, from, to time.Time, exclude int64) (*repo.ExpenseStats, error)
+	BillReminders(ctx context.Context, today time.Time) ([]repo.UpcomingBill, error)
+	WeeklySummaries(ctx context.Context, today time.Time) ([]repo.WeeklyTotals, error)
+}
+
+// Detector raises alerts as expenses are recorded (an outbox subscriber), for bills
+// coming due and finished weeks (a daily job), and as imports finish (ImportFinished).
+// - Locale: the user's language; nil or "" means English
+// - WarnAt: share of a budget that triggers the early warning (default 0.8)
+// - AnomalyFactor: multiple of its category's 90-day average an expense must exceed to be unusual (default 2)
+type Detector struct {
+	Source        Source
+	Notifier      *Notifier
+	Locale        func(ctx context.Context, userID int64) string
+	WarnAt        float64
+	AnomalyFactor float64
+}
+
+// txnEvent is the part of a transaction row carried in outbox payloads.
+type txnEvent struct {
+	Action string `json:"action"`
+	Data   struct {
+		ID          int64   `json:"id"`
+		UserID      int64   `json:"user_id"`
+		CategoryID  *int64  `json:"category_id"`
+		Amount      float64 `json:"amount"`
+		Type        string  `json:"type"`
+		Date        string  `json:"date"`
+		Description string  `json:"description"`
+	} `json:"data"`
+}
+
+// OnEvent is an outbox.Subscriber: recorded or changed expenses may push a budget
+// over its thresholds, and a new one may be unusually large.
+func (d *Detector) OnEvent(ctx context.Context, e *repo.OutboxEvent) error {
+	if e.Topic != "transactions.created" && e.Topic != "transactions.updated" {
+		return nil
+	}
+	var ev txnEvent
+	if err := json.Unmarshal(e.Payload, &ev); err != nil {
+		return fmt.Errorf("decode %s event: %w", e.Topic, err)
+	}
+	t := ev.Data
+	date, err := time.Parse("2006-01-02", t.Date)
+	if t.Type != "expense" || err != nil {
+		return nil
+	}
+	lang := d.locale(ctx, e.UserID)
+	if err := d.checkBudgets(ctx, lang, e.UserID, date.Format("2006-01"), t.CategoryID); err != nil {
+		return err
+	}
+	if ev.Action != "create" {
+		return nil
+	}
+
+	stats, err := d.Source.ExpenseStats(ctx, e.UserID, t.CategoryID, date.AddDate(0, 0, -90), date.AddDate(0, 0, 1), t.ID)
+	if err != nil {
+		return err
+	}
+	factor := d.AnomalyFactor
+	if factor <= 0 {
+		factor = 2
+	}
+	if stats.Count < 5 || t.Amount <= stats.Mean*factor || t.Amount <= stats.Mean+3*stats.StdDev {
+		return nil
+	}
+	desc := strings.TrimSpace(t.Description)
+	if desc == "" {
+		desc = "—"
+	}
+	return d.Notifier.Raise(ctx, &Alert{
+		UserID: e.UserID,
+		Event:  EventAnomaly,
+		Key:    "anomaly:" + strconv.FormatInt(t.ID, 10),
+		Title:  i18n.T(lang, "alert.anomaly.title"),
+		Body: i18n.T(lang, "alert.anomaly.body", "amount", money(t.Amount), "description", desc,
+			"average", money(stats.Mean)),
+		Data: map[string]string{"transaction_id": strconv.FormatInt(t.ID, 10)},
+	})
+}
+
+// checkBudgets raises the warning or the exceeded alert for each budget of month
+// that expenses in categoryID count against. A budget that jumps straight past its
+// limit gets only the exceeded alert.
+func (d *Detector) checkBudgets(ctx context.Context, lang string, userID int64, month string, categoryID *int64) error {
+	usage, err := d.Source.BudgetUsage(ctx, userID, month, categoryID)
+	if err != nil {
+		return err
+	}
+	warnAt := d.WarnAt
+	if warnAt <= 0 {
+		warnAt = 0.8
+	}
+	for _, u := range usage {
+		if u.Limit <= 0 {
+			continue
+		}
+		ratio := u.Spent / u.Limit
+		level := ""
+		switch {
+		case ratio >= 1:
+			level = "exceeded"
+		case ratio >= warnAt:
+			level = "warning"
+		default:
+			continue
+		}
+		name := i18n.T(lang, "alert.budget.overall")
+		if u.CategoryName != nil {
+			name = *u.CategoryName
+		}
+		err := d.Notifier.Raise(ctx, &Alert{
+			UserID: userID,
+			Event:  EventBudget,
+			Key:    fmt.Sprintf("budget:%d:%s", u.BudgetID, level),
+			Title:  i18n.T(lang, "alert.budget."+level+".title", "budget", name),
+			Body: i18n.T(lang, "alert.budget."+level+".body", "budget", name, "month", month,
+				"spent", money(u.Spent), "limit", money(u.Limit), "percent", strconv.Itoa(int(ratio*100))),
+			Data: map[string]string{"budget_id": strconv.FormatInt(u.BudgetID, 10), "month": month},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BillReminders raises a reminder for every bill occurrence entering its reminder
+// window on today (UTC).
+func (d *Detector) BillReminders(ctx context.Context, today time.Time) error {
+	due, err := d.Source.BillReminders(ctx, today)
+	if err != nil {
+		return err
+	}
+	langs := map[int64]string{}
+	for _, b := range due {
+		lang, ok := langs[b.UserID]
+		if !ok {
+			lang = d.locale(ctx, b.UserID)
+			langs[b.UserID] = lang
+		}
+		body := "alert.bill.body"
+		if b.DaysLeft == 0 {
+			body = "alert.bill.body.today"
+		}
+		err := d.Notifier.Raise(repo.WithTenant(ctx, b.UserID), &Alert{
+			UserID: b.UserID,
+			Event:  EventBill,
+			Key:    fmt.Sprintf("bill:%d:%s", b.ID, b.DueDate),
+			Title:  i18n.T(lang, "alert.bill.title", "name", b.Name),
+			Body:   i18n.T(lang, body, "name", b.Name, "amount", money(b.Amount), "date", b.DueDate),
+			Data:   map[string]string{"bill_id": strconv.FormatInt(b.ID, 10), "due_date": b.DueDate},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WeeklySummaries raises the summary of the week that ended yesterday for every
+// user whose week begins on today (UTC).
+func (d *Detector) WeeklySummaries(ctx context.Context, today time.Time) error {
+	weeks, err := d.Source.WeeklySummaries(ctx, today)
+	if err != nil {
+		return err
+	}
+	for _, w := range weeks {
+		lang := d.locale(ctx, w.UserID)
+		start, end := w.WeekStart.Format("2006-01-02"), w.WeekStart.AddDate(0, 0, 6).Format("2006-01-02")
+		body := i18n.T(lang, "alert.weekly.body", "income", money(w.Income), "expense", money(w.Expense),
+			"net", money(w.Income-w.Expense))
+		if w.TopCategory != nil {
+			body += " " + i18n.T(lang, "alert.weekly.top", "category", *w.TopCategory, "amount", money(w.TopAmount))
+		}
+		err := d.Notifier.Raise(repo.WithTenant(ctx, w.UserID), &Alert{
+			UserID: w.UserID,
+			Event:  EventWeekly,
+			Key:    "weekly:" + start,
+			Title:  i18n.T(lang, "alert.weekly.title", "start", start, "end", end),
+			Body:   body,
+			Data:   map[string]string{"week_start": start, "week_end": end},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportFinished raises the alert for an import that is done or failed; cancelled
+// ones were stopped by the user and raise none. It is an imports.Importer's OnFinish.
+func (d *Detector) ImportFinished(ctx context.Context, imp *repo.Import) error {
+	lang := d.locale(ctx, imp.UserID)
+	a := &Alert{
+		UserID: imp.UserID,
+		Event:  EventImport,
+		Key:    "import:" + strconv.FormatInt(imp.ID, 10),
+		Data:   map[string]string{"import_id": strconv.FormatInt(imp.ID, 10), "status": imp.Status},
+	}
+	switch imp.Status {
+	case "done":
+		a.Title = i18n.T(lang, "alert.import.done.title")
+		a.Body = i18n.T(lang, "alert.import.done.body", "filename", imp.Filename,
+			"imported", strconv.Itoa(imp.RowsImported), "total", strconv.Itoa(imp.RowsTotal),
+			"failed", strconv.Itoa(imp.RowsFailed))
+	case "failed":
+		msg := "—"
+		if imp.Error != nil {
+			msg = *imp.Error
+		}
+		a.Title = i18n.T(lang, "alert.import.failed.title")
+		a.Body = i18n.T(lang, "alert.import.failed.body", "filename", imp.Filename, "error", msg)
+	default:
+		return nil
+	}
+	return d.Notifier.Raise(repo.WithTenant(ctx, imp.UserID), a)
+}
+
+func (d *Detector) locale(ctx context.Context, userID int64) string {
+	if d.Locale == nil {
+		return i18n.Default
+	}
+	if l := d.Locale(ctx, userID); i18n.Supports(l) {
+		return l
+	}
+	return i18n.Default
+}
+
+func money(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
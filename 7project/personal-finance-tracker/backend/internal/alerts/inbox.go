@@ -0,0 +1,31 @@
+// backend/internal/alerts/inbox.go
+
+package alerts
+
+import (
+	"context"
+
+	"pft/internal/repo"
+)
+
+// Notifications files in-app notifications; implemented by repo.NotificationRepo.
+type Notifications interface {
+	Add(ctx context.Context, n *repo.Notification) (bool, error)
+}
+
+// Inbox is the in-app channel: it files alerts in the user's notification center,
+// which clients read from GET /api/notifications.
+type Inbox struct {
+	Notifications Notifications
+}
+
+// Name implements Channel.
+func (i *Inbox) Name() string { return ChannelInApp }
+
+// Deliver implements Channel. An alert delivered again is filed once.
+func (i *Inbox) Deliver(ctx context.Context, a *Alert) error {
+	_, err := i.Notifications.Add(ctx, &repo.Notification{
+		UserID: a.UserID, Event: a.Event, Key: a.Key, Title: a.Title, Body: a.Body, Data: a.Data,
+	})
+	return err
+}
@@ -0,0 +1,37 @@
+// backend/internal/alerts/quiet.go
+
+package alerts
+
+import (
+	"time"
+
+	"pft/internal/repo"
+)
+
+// quietUntil returns when the quiet hours q around now end, or the zero time when now
+// is outside them (or q is nil). An unknown time zone is taken as UTC.
+func quietUntil(q *repo.QuietHours, now time.Time) time.Time {
+	if q == nil || q.Start == q.End {
+		return time.Time{}
+	}
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	var quiet bool
+	if q.Start < q.End {
+		quiet = minute >= q.Start && minute < q.End
+	} else {
+		quiet = minute >= q.Start || minute < q.End // wraps midnight
+	}
+	if !quiet {
+		return time.Time{}
+	}
+	end := time.Date(local.Year(), local.Month(), local.Day(), q.End/60, q.End%60, 0, 0, loc)
+	if !end.After(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
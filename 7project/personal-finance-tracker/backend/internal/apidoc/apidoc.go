@@ -0,0 +1,323 @@
+// backend/internal/apidoc/apidoc.go
+
+// Package apidoc generates an OpenAPI 3 document from the Gin route table and
+// serves it together with a Swagger UI page.
+//
+// Operations are derived from the registered routes: the handler method name becomes
+// the operationId and summary ("ListTransactions" → "List transactions"), the first
+// path segment after /api becomes the tag, and ":param" segments become path parameters.
+// Handlers of another type than API are qualified by it ("AdminListAudit"), and a name
+// still shared by several routes is replaced by one made of method and path.
+//
+// Query parameters, request and response bodies come from a Doc per route, whose Go
+// types are turned into JSON schemas (see schema.go).
+package apidoc
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Info describes the API in the generated document.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Spec is the subset of an OpenAPI 3.0 document produced by Build.
+type Spec struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+// Doc describes the payloads of one route, keyed by method and route path as in
+// "GET /api/transactions". Query, Body and Response hold values of the Go types the
+// handler reads and writes; only their types are used.
+// - Query: struct whose `form` tags name the query parameters
+// - Body: the JSON request body
+// - Form: struct whose `form` tags name the fields of a form-encoded request body
+// - Response: the success body; nil when there is none
+// - Status: the success status, 200 when zero
+// - Summary: replaces the summary derived from the handler name
+type Doc struct {
+	Query    any
+	Body     any
+	Form     any
+	Response any
+	Status   int
+	Summary  string
+}
+
+// Operation is a single method on a path.
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+}
+
+// Parameter is a path parameter derived from a ":name" route segment, or a query
+// parameter from Doc.Query.
+type Parameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required"`
+	Schema      map[string]any `json:"schema"`
+}
+
+// RequestBody is the JSON body an operation reads.
+type RequestBody struct {
+	Required bool                      `json:"required"`
+	Content  map[string]map[string]any `json:"content"`
+}
+
+// Response references a shared response body.
+type Response struct {
+	Description string                    `json:"description"`
+	Content     map[string]map[string]any `json:"content,omitempty"`
+}
+
+// Components holds shared schemas and security schemes.
+type Components struct {
+	Schemas         map[string]any `json:"schemas"`
+	SecuritySchemes map[string]any `json:"securitySchemes"`
+}
+
+// apiKeyPaths prefixes the routes authenticated by API key rather than bearer token.
+const apiKeyPaths = "/api/zapier/"
+
+// Build creates the document for routes, described further by docs. Paths listed in
+// public are documented without the bearer token requirement; those under apiKeyPaths
+// require an API key.
+func Build(info Info, routes gin.RoutesInfo, docs map[string]Doc, public ...string) *Spec {
+	open := map[string]bool{}
+	for _, p := range public {
+		open[p] = true
+	}
+	errBody := map[string]map[string]any{
+		"application/problem+json": {"schema": map[string]string{"$ref": "#/components/schemas/Problem"}},
+	}
+
+	s := &Spec{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   map[string]map[string]*Operation{},
+		Components: Components{
+			Schemas: map[string]any{
+				"Problem": map[string]any{
+					"type":        "object",
+					"description": "RFC 7807 problem details; code is the stable machine-readable identifier.",
+					"properties": map[string]any{
+						"type":       map[string]string{"type": "string"},
+						"title":      map[string]string{"type": "string"},
+						"status":     map[string]string{"type": "integer"},
+						"detail":     map[string]string{"type": "string"},
+						"instance":   map[string]string{"type": "string"},
+						"code":       map[string]string{"type": "string"},
+						"request_id": map[string]string{"type": "string"},
+						"errors": map[string]any{
+							"type": "array",
+							"items": map[string]any{
+								"type": "object",
+								"properties": map[string]any{
+									"field":   map[string]string{"type": "string"},
+									"message": map[string]string{"type": "string"},
+								},
+							},
+						},
+					},
+					"required": []string{"type", "title", "status", "code"},
+				},
+			},
+			SecuritySchemes: map[string]any{
+				"bearerAuth": map[string]string{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"apiKeyAuth": map[string]string{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+	}
+
+	sorted := append(gin.RoutesInfo(nil), routes...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+	uses := map[string]int{}
+	for _, rt := range sorted {
+		uses[handlerName(rt.Handler)]++
+	}
+	schemas := newSchemaSet(s.Components.Schemas)
+	for _, rt := range sorted {
+		path, params := openAPIPath(rt.Path)
+		name := handlerName(rt.Handler)
+		d := docs[rt.Method+" "+rt.Path]
+		op := &Operation{
+			OperationID: name,
+			Summary:     summary(name),
+			Tags:        []string{tag(rt.Path)},
+			Parameters:  append(params, schemas.query(d.Query)...),
+			Responses:   map[string]Response{"default": {Description: "Error", Content: errBody}},
+		}
+		if uses[name] > 1 {
+			op.OperationID = methodPathID(rt.Method, rt.Path)
+		}
+		if d.Summary != "" {
+			op.Summary = d.Summary
+		}
+		switch {
+		case d.Body != nil:
+			op.RequestBody = &RequestBody{Required: true, Content: map[string]map[string]any{
+				"application/json": {"schema": schemas.of(reflect.TypeOf(d.Body))},
+			}}
+		case d.Form != nil:
+			op.RequestBody = &RequestBody{Required: true, Content: map[string]map[string]any{
+				"application/x-www-form-urlencoded": {"schema": schemas.form(d.Form)},
+			}}
+		}
+		status := d.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		res := Response{Description: http.StatusText(status)}
+		if d.Response != nil {
+			res.Content = map[string]map[string]any{"application/json": {"schema": schemas.of(reflect.TypeOf(d.Response))}}
+		}
+		op.Responses[strconv.Itoa(status)] = res
+		switch {
+		case open[rt.Path]:
+		case strings.HasPrefix(rt.Path, apiKeyPaths):
+			op.Security = []map[string][]string{{"apiKeyAuth": {}}}
+		default:
+			op.Security = []map[string][]string{{"bearerAuth": {}}}
+		}
+		if s.Paths[path] == nil {
+			s.Paths[path] = map[string]*Operation{}
+		}
+		s.Paths[path][strings.ToLower(rt.Method)] = op
+	}
+	return s
+}
+
+// Register serves the document for every route registered on r so far at
+// /api/docs/openapi.json and a Swagger UI page at /api/docs. Call it after all
+// other routes have been added.
+func Register(r *gin.Engine, info Info, docs map[string]Doc, public ...string) {
+	spec := Build(info, r.Routes(), docs, public...)
+	r.GET("/api/docs/openapi.json", func(c *gin.Context) { c.JSON(http.StatusOK, spec) })
+	r.GET("/api/docs", func(c *gin.Context) {
+		// The UI is fetched from a CDN and bootstrapped by an inline script.
+		c.Header("Content-Security-Policy", uiCSP)
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUI))
+	})
+}
+
+// uiCSP relaxes the API's default-deny CSP for the Swagger UI page only.
+const uiCSP = "default-src 'self'; script-src 'self' 'unsafe-inline' https://unpkg.com; " +
+	"style-src 'self' 'unsafe-inline' https://unpkg.com; img-src 'self' data: https:; frame-ancestors 'none'"
+
+// openAPIPath converts "/api/loans/:id" to "/api/loans/{id}" and lists its path parameters.
+func openAPIPath(p string) (string, []Parameter) {
+	segs := strings.Split(p, "/")
+	var params []Parameter
+	for i, s := range segs {
+		if len(s) > 1 && (s[0] == ':' || s[0] == '*') {
+			name := s[1:]
+			segs[i] = "{" + name + "}"
+			params = append(params, Parameter{
+				Name:     name,
+				In:       "path",
+				Required: true,
+				Schema:   map[string]any{"type": "string"},
+			})
+		}
+	}
+	return strings.Join(segs, "/"), params
+}
+
+// handlerName extracts the method name from a Gin handler name such as
+// "pft/internal/handler.(*API).ListTransactions-fm", qualified by the receiver type
+// unless it is API: "pft/internal/handler.(*Admin).ListAudit-fm" → "AdminListAudit".
+func handlerName(h string) string {
+	h = strings.TrimSuffix(h, "-fm")
+	receiver := ""
+	if i := strings.Index(h, ".(*"); i >= 0 {
+		if j := strings.Index(h[i:], ")."); j >= 0 {
+			receiver = h[i+3 : i+j]
+		}
+	}
+	if receiver == "API" {
+		receiver = ""
+	}
+	// Closures returned by handler constructors are named "pkg.Ctor.func1".
+	for {
+		i := strings.LastIndex(h, ".func")
+		if i < 0 || strings.Trim(h[i+5:], "0123456789") != "" {
+			break
+		}
+		h = h[:i]
+	}
+	if i := strings.LastIndex(h, "."); i >= 0 {
+		h = h[i+1:]
+	}
+	return receiver + h
+}
+
+// methodPathID names an operation by method and path when its handler name is shared:
+// "GET /api/admin/debug/*path" → "get_api_admin_debug_path".
+func methodPathID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, seg := range strings.Split(path, "/") {
+		if seg = strings.Trim(seg, ":*"); seg != "" {
+			id += "_" + strings.ReplaceAll(seg, "-", "_")
+		}
+	}
+	return id
+}
+
+// summary turns a CamelCase name into a sentence: "ListTransactions" → "List transactions".
+func summary(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte(' ')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// tag groups operations by the first path segment after /api.
+func tag(p string) string {
+	p = strings.TrimPrefix(p, "/api/")
+	if i := strings.IndexByte(p, '/'); i >= 0 {
+		p = p[:i]
+	}
+	return p
+}
+
+// swaggerUI renders the spec with the Swagger UI bundle from a public CDN.
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Personal Finance Tracker API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/api/docs/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
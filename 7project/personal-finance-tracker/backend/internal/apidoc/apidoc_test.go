@@ -0,0 +1,171 @@
+// backend/internal/apidoc/apidoc_test.go
+//
+// Purpose:
+//   Verify that the OpenAPI document mirrors the Gin route table: path parameter
+//   conversion, operation naming, tagging, and bearer (or API key) security on
+//   private routes. Verify that a Doc adds query parameters, request and response
+//   schemas and the success status, and that operationIds stay unique when a method
+//   name is mounted twice.
+
+package apidoc
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeAPI struct{}
+
+func (fakeAPI) Healthz(*gin.Context)           {}
+func (fakeAPI) UpdateTransaction(*gin.Context) {}
+func (fakeAPI) ZapierMe(*gin.Context)          {}
+
+func TestBuild(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var api fakeAPI
+	r := gin.New()
+	r.GET("/api/healthz", api.Healthz)
+	r.PUT("/api/transactions/:id", api.UpdateTransaction)
+	r.GET("/api/zapier/me", api.ZapierMe)
+
+	s := Build(Info{Title: "test", Version: "1"}, r.Routes(), nil, "/api/healthz")
+
+	health := s.Paths["/api/healthz"]["get"]
+	if health == nil || health.Security != nil {
+		t.Fatalf("expected public healthz operation, got %+v", health)
+	}
+
+	op := s.Paths["/api/transactions/{id}"]["put"]
+	if op == nil {
+		t.Fatalf("missing converted path, got %v", s.Paths)
+	}
+	if op.OperationID != "UpdateTransaction" || op.Summary != "Update transaction" {
+		t.Fatalf("unexpected naming: %+v", op)
+	}
+	if len(op.Tags) != 1 || op.Tags[0] != "transactions" {
+		t.Fatalf("unexpected tags: %v", op.Tags)
+	}
+	if len(op.Parameters) != 1 || op.Parameters[0].Name != "id" || op.Parameters[0].In != "path" {
+		t.Fatalf("unexpected parameters: %+v", op.Parameters)
+	}
+	if len(op.Security) != 1 || op.Security[0]["bearerAuth"] == nil {
+		t.Fatalf("expected bearer security on private route")
+	}
+	if zap := s.Paths["/api/zapier/me"]["get"]; zap == nil || len(zap.Security) != 1 || zap.Security[0]["apiKeyAuth"] == nil {
+		t.Fatalf("expected API key security on zapier route, got %+v", zap)
+	}
+}
+
+type API struct{}
+
+func (*API) ListAudit(*gin.Context)  {}
+func (*API) CreateLoan(*gin.Context) {}
+
+type Admin struct{}
+
+func (*Admin) ListAudit(*gin.Context) {}
+
+type loanReq struct {
+	Name   string  `json:"name" binding:"required"`
+	Kind   string  `json:"kind" binding:"omitempty,oneof=fixed annuity"`
+	Amount float64 `json:"amount"`
+	Note   *string `json:"note"`
+	secret string
+}
+
+type Loan struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Next      *Loan     `json:"next,omitempty"`
+}
+
+func TestBuildDocs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api, admin := &API{}, &Admin{}
+	r := gin.New()
+	r.GET("/api/audit", api.ListAudit)
+	r.GET("/api/admin/audit", admin.ListAudit)
+	r.POST("/api/loans", api.CreateLoan)
+	r.GET("/api/debug/*path", gin.WrapH(http.NotFoundHandler()))
+	r.GET("/metrics", gin.WrapH(http.NotFoundHandler()))
+
+	s := Build(Info{Title: "test", Version: "1"}, r.Routes(), map[string]Doc{
+		"GET /api/audit": {Query: struct {
+			Action string `form:"action" binding:"omitempty,oneof=create delete"`
+			Limit  int    `form:"limit" doc:"default 100"`
+		}{}},
+		"POST /api/loans": {Body: loanReq{}, Status: http.StatusCreated, Response: Loan{}},
+		"GET /metrics":    {Summary: "Metrics"},
+	})
+
+	ids := map[string]bool{}
+	for _, ops := range s.Paths {
+		for _, op := range ops {
+			if ids[op.OperationID] {
+				t.Errorf("duplicate operationId %q", op.OperationID)
+			}
+			ids[op.OperationID] = true
+		}
+	}
+	if s.Paths["/api/admin/audit"]["get"].OperationID != "AdminListAudit" {
+		t.Errorf("admin operationId = %q", s.Paths["/api/admin/audit"]["get"].OperationID)
+	}
+	if id := s.Paths["/api/debug/{path}"]["get"].OperationID; id != "get_api_debug_path" {
+		t.Errorf("shared handler name not replaced: %q", id)
+	}
+	if sum := s.Paths["/metrics"]["get"].Summary; sum != "Metrics" {
+		t.Errorf("summary override = %q", sum)
+	}
+
+	audit := s.Paths["/api/audit"]["get"]
+	if len(audit.Parameters) != 2 || audit.Parameters[0].In != "query" || audit.Parameters[1].Description != "default 100" {
+		t.Fatalf("unexpected query parameters: %+v", audit.Parameters)
+	}
+	if enum := audit.Parameters[0].Schema["enum"]; !reflect.DeepEqual(enum, []string{"create", "delete"}) {
+		t.Errorf("action enum = %v", enum)
+	}
+	if _, ok := audit.Responses["200"]; !ok {
+		t.Errorf("expected a 200 response, got %v", audit.Responses)
+	}
+
+	create := s.Paths["/api/loans"]["post"]
+	if create.RequestBody == nil {
+		t.Fatal("missing request body")
+	}
+	ref := create.RequestBody.Content["application/json"]["schema"].(map[string]any)["$ref"]
+	if ref != "#/components/schemas/LoanReq" {
+		t.Errorf("request schema = %v", ref)
+	}
+	req := s.Components.Schemas["LoanReq"].(map[string]any)
+	props := req["properties"].(map[string]any)
+	if len(props) != 4 || !reflect.DeepEqual(req["required"], []string{"name"}) {
+		t.Errorf("unexpected LoanReq schema: %v", req)
+	}
+	if props["note"].(map[string]any)["nullable"] != true || !reflect.DeepEqual(props["kind"].(map[string]any)["enum"], []string{"fixed", "annuity"}) {
+		t.Errorf("unexpected LoanReq fields: %v", props)
+	}
+	if _, ok := create.Responses["201"].Content["application/json"]; !ok {
+		t.Errorf("expected a 201 JSON response, got %v", create.Responses)
+	}
+	loan := s.Components.Schemas["Loan"].(map[string]any)["properties"].(map[string]any)
+	if loan["created_at"].(map[string]any)["format"] != "date-time" || loan["next"].(map[string]any)["nullable"] != true {
+		t.Errorf("unexpected Loan schema: %v", loan)
+	}
+}
+
+func TestHandlerName(t *testing.T) {
+	cases := map[string]string{
+		"pft/internal/handler.(*API).ListLoans-fm":   "ListLoans",
+		"pft/internal/handler.(*Admin).ListAudit-fm": "AdminListAudit",
+		"pft/internal/handler.GraphQL.func1":         "GraphQL",
+	}
+	for in, want := range cases {
+		if got := handlerName(in); got != want {
+			t.Errorf("handlerName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
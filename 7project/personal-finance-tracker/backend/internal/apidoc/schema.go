@@ -0,0 +1,246 @@
+// backend/internal/apidoc/schema.go
+
+package apidoc
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rawJSONType = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// schemaSet turns Go types into JSON schemas the way encoding/json would marshal
+// them. Named struct types are added to the document's components once and referenced
+// by name; anonymous structs are written inline.
+// - json tags name fields; "-" and unexported fields are left out, embedded structs are flattened
+// - `binding:"required"` marks a field required and `binding:"oneof=a b"` lists its values
+// - `doc:"..."` describes a field
+type schemaSet struct {
+	components map[string]any
+	names      map[reflect.Type]string
+}
+
+func newSchemaSet(components map[string]any) *schemaSet {
+	return &schemaSet{components: components, names: map[reflect.Type]string{}}
+}
+
+// of returns the schema of t, a reference for named struct types.
+func (s *schemaSet) of(t reflect.Type) map[string]any {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t, nullable = t.Elem(), true
+	}
+	out := s.bare(t)
+	if nullable {
+		if _, ref := out["$ref"]; ref {
+			return map[string]any{"allOf": []any{out}, "nullable": true}
+		}
+		out["nullable"] = true
+	}
+	return out
+}
+
+func (s *schemaSet) bare(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == rawJSONType:
+		return map[string]any{}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": s.of(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + s.component(t)}
+	}
+	return map[string]any{} // interfaces: any value
+}
+
+// component adds named struct type t to the components, unless it is there already,
+// and returns its name there.
+func (s *schemaSet) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	name := schemaName(t)
+	if _, taken := s.components[name]; taken {
+		name = schemaName(t) + "_" + pkgName(t)
+	}
+	s.names[t] = name
+	s.components[name] = nil // reserved while its fields refer back to it
+	s.components[name] = s.object(t)
+	return name
+}
+
+// object is the schema of struct type t.
+func (s *schemaSet) object(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	var required []string
+	s.fields(t, func(f reflect.StructField, name string) {
+		p := s.of(f.Type)
+		if d := f.Tag.Get("doc"); d != "" {
+			p = describe(p, d)
+		}
+		if values := oneOf(f.Tag.Get("binding")); values != nil {
+			p["enum"] = values
+		}
+		props[name] = p
+		if isRequired(f) {
+			required = append(required, name)
+		}
+	})
+	out := map[string]any{"type": "object", "properties": props}
+	if required != nil {
+		out["required"] = required
+	}
+	return out
+}
+
+// query lists the query parameters of struct v, named by their `form` tags.
+func (s *schemaSet) query(v any) []Parameter {
+	if v == nil {
+		return nil
+	}
+	var out []Parameter
+	s.formFields(reflect.TypeOf(v), func(f reflect.StructField, name string, p map[string]any) {
+		out = append(out, Parameter{Name: name, In: "query", Description: f.Tag.Get("doc"), Required: isRequired(f), Schema: p})
+	})
+	return out
+}
+
+// form is the schema of a form-encoded body with the fields of struct v.
+func (s *schemaSet) form(v any) map[string]any {
+	props := map[string]any{}
+	var required []string
+	s.formFields(reflect.TypeOf(v), func(f reflect.StructField, name string, p map[string]any) {
+		if d := f.Tag.Get("doc"); d != "" {
+			p["description"] = d
+		}
+		props[name] = p
+		if isRequired(f) {
+			required = append(required, name)
+		}
+	})
+	out := map[string]any{"type": "object", "properties": props}
+	if required != nil {
+		out["required"] = required
+	}
+	return out
+}
+
+// formFields calls fn for each field of struct t with a `form` tag, flattening
+// embedded structs, with its name and schema.
+func (s *schemaSet) formFields(t reflect.Type, fn func(f reflect.StructField, name string, p map[string]any)) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("form"), ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			s.formFields(f.Type, fn)
+			continue
+		}
+		if name == "" || name == "-" {
+			continue
+		}
+		p := s.of(f.Type)
+		if values := oneOf(f.Tag.Get("binding")); values != nil {
+			p["enum"] = values
+		}
+		fn(f, name, p)
+	}
+}
+
+// fields calls fn for each field of struct t that encoding/json writes, with its name.
+func (s *schemaSet) fields(t reflect.Type, fn func(f reflect.StructField, name string)) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				s.fields(ft, fn)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fn(f, name)
+	}
+}
+
+// describe adds a description to p; references cannot carry one and are wrapped.
+func describe(p map[string]any, d string) map[string]any {
+	if _, ref := p["$ref"]; ref {
+		p = map[string]any{"allOf": []any{p}}
+	}
+	p["description"] = d
+	return p
+}
+
+func isRequired(f reflect.StructField) bool {
+	for _, rule := range strings.Split(f.Tag.Get("binding"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// oneOf returns the values of a "oneof=a b" binding rule, nil without one.
+func oneOf(binding string) []string {
+	for _, rule := range strings.Split(binding, ",") {
+		if v, ok := strings.CutPrefix(rule, "oneof="); ok {
+			return strings.Fields(v)
+		}
+	}
+	return nil
+}
+
+// schemaName is the exported form of t's name: "txnCreateReq" → "TxnCreateReq".
+// Type arguments of generic types are left out.
+func schemaName(t reflect.Type) string {
+	name, _, _ := strings.Cut(t.Name(), "[")
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// pkgName is the last element of t's package path.
+func pkgName(t reflect.Type) string {
+	p := t.PkgPath()
+	return p[strings.LastIndexByte(p, '/')+1:]
+}
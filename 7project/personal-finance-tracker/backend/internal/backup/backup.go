@@ -0,0 +1,189 @@
+// backend/internal/backup/backup.go
+
+// Package backup writes logical backups of user data to blob storage and reads them
+// back for restore. An archive is gzip-compressed JSON holding one repo.UserDump
+// per user, so it can be restored table by table into any schema at the same migration
+// or later, and inspected with standard tools (zcat | jq).
+//
+// Archives contain password hashes and every financial record; keep the directory
+// private (and copy archives off the host when it is a local directory) for disaster
+// recovery.
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"time"
+
+	"pft/internal/blob"
+	"pft/internal/repo"
+)
+
+// Format is the archive format version written by Create.
+const Format = 1
+
+// Archive is the decoded content of a backup file.
+// - Migration: latest applied migration when the backup was taken
+// - Users: one dump per user, in id order
+type Archive struct {
+	Format    int             `json:"format"`
+	CreatedAt time.Time       `json:"created_at"`
+	Migration string          `json:"migration"`
+	Users     []repo.UserDump `json:"users"`
+}
+
+// Info describes a stored archive.
+type Info struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ErrNotFound is returned for archive names that are not stored.
+var ErrNotFound = fmt.Errorf("backup: %w", repo.ErrNotFound)
+
+// namePattern matches archive names produced by Create; anything else is rejected
+// before touching the store.
+var namePattern = regexp.MustCompile(`^pft-\d{8}T\d{6}Z(-u\d+)?\.json\.gz$`)
+
+// Dir stores archives in a blob store, named by Create and kept at its top level.
+type Dir struct {
+	Blobs blob.Store
+}
+
+// Create dumps the given users (every user when ids is empty) and stores the archive.
+// migration is recorded for reference; see platform.MigrationVersion.
+func (d *Dir) Create(ctx context.Context, store *repo.BackupRepo, migration string, ids ...int64) (*Info, error) {
+	now := time.Now().UTC()
+	name := "pft-" + now.Format("20060102T150405Z")
+	if len(ids) == 0 {
+		all, err := store.UserIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ids = all
+	} else if len(ids) == 1 {
+		name += fmt.Sprintf("-u%d", ids[0])
+	}
+	name += ".json.gz"
+
+	a := Archive{Format: Format, CreatedAt: now, Migration: migration, Users: []repo.UserDump{}}
+	for _, id := range ids {
+		u, err := store.Export(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("user %d: %w", id, err)
+		}
+		if u == nil {
+			return nil, fmt.Errorf("user %d: %w", id, repo.ErrNotFound)
+		}
+		a.Users = append(a.Users, *u)
+	}
+
+	// Encode in full first so a failed backup never leaves a truncated archive.
+	var buf bytes.Buffer
+	if err := Write(&buf, &a); err != nil {
+		return nil, err
+	}
+	if err := d.Blobs.Put(ctx, name, bytes.NewReader(buf.Bytes()), int64(buf.Len()), "application/gzip"); err != nil {
+		return nil, err
+	}
+	return d.Stat(ctx, name)
+}
+
+// List returns the stored archives, newest first.
+func (d *Dir) List(ctx context.Context) ([]Info, error) {
+	blobs, err := d.Blobs.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	out := []Info{}
+	for _, b := range blobs {
+		if namePattern.MatchString(b.Key) {
+			out = append(out, Info{Name: b.Key, Size: b.Size, CreatedAt: b.ModTime})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name > out[j].Name })
+	return out, nil
+}
+
+// Stat describes one archive. Returns ErrNotFound for unknown or malformed names.
+func (d *Dir) Stat(ctx context.Context, name string) (*Info, error) {
+	if !namePattern.MatchString(name) {
+		return nil, ErrNotFound
+	}
+	b, err := d.Blobs.Stat(ctx, name)
+	if err == blob.ErrNotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Info{Name: name, Size: b.Size, CreatedAt: b.ModTime}, nil
+}
+
+// Open returns the raw (compressed) archive for download; the caller closes it.
+func (d *Dir) Open(ctx context.Context, name string) (io.ReadCloser, *Info, error) {
+	if !namePattern.MatchString(name) {
+		return nil, nil, ErrNotFound
+	}
+	rc, b, err := d.Blobs.Open(ctx, name)
+	if err == blob.ErrNotFound {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return rc, &Info{Name: name, Size: b.Size, CreatedAt: b.ModTime}, nil
+}
+
+// Load reads and decodes a stored archive.
+func (d *Dir) Load(ctx context.Context, name string) (*Archive, error) {
+	rc, _, err := d.Open(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return Read(rc)
+}
+
+// Write encodes a as gzip-compressed JSON.
+func Write(w io.Writer, a *Archive) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(a); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Read decodes an archive written by Write and rejects newer formats.
+func Read(r io.Reader) (*Archive, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("backup: %w", err)
+	}
+	defer zr.Close()
+	var a Archive
+	if err := json.NewDecoder(zr).Decode(&a); err != nil {
+		return nil, fmt.Errorf("backup: %w", err)
+	}
+	if a.Format < 1 || a.Format > Format {
+		return nil, fmt.Errorf("backup: unsupported format %d", a.Format)
+	}
+	return &a, nil
+}
+
+// User returns the dump for userID, or nil if the archive does not contain it.
+func (a *Archive) User(userID int64) *repo.UserDump {
+	for i := range a.Users {
+		if a.Users[i].UserID == userID {
+			return &a.Users[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// backend/internal/backup/backup_test.go
+//
+// Purpose:
+//   Round-trip archives through Write/Read and check that Dir only serves names it
+//   could have produced, newest first, from a local blob store.
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pft/internal/blob"
+	"pft/internal/repo"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	in := &Archive{Format: Format, CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Migration: "024_x.sql",
+		Users: []repo.UserDump{{UserID: 7, Tables: map[string]json.RawMessage{"users": json.RawMessage(`[{"id":7}]`)}}}}
+	var buf bytes.Buffer
+	if err := Write(&buf, in); err != nil {
+		t.Fatal(err)
+	}
+	out, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Migration != in.Migration || out.User(7) == nil || string(out.User(7).Tables["users"]) != `[{"id":7}]` {
+		t.Fatalf("archive changed in transit: %+v", out)
+	}
+	if out.User(8) != nil {
+		t.Fatal("unexpected user 8")
+	}
+}
+
+func TestReadRejectsNewerFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, &Archive{Format: Format + 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(&buf); err == nil {
+		t.Fatal("expected unsupported format error")
+	}
+}
+
+func TestDirNames(t *testing.T) {
+	dir := t.TempDir()
+	d := &Dir{Blobs: &blob.Local{Dir: dir}}
+	for _, n := range []string{"pft-20260101T000000Z.json.gz", "pft-20260301T000000Z-u4.json.gz", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, n), []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := d.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Name != "pft-20260301T000000Z-u4.json.gz" {
+		t.Fatalf("unexpected listing: %+v", list)
+	}
+	for _, n := range []string{"notes.txt", "../pft-20260101T000000Z.json.gz", "pft-20260102T000000Z.json.gz"} {
+		if _, err := d.Stat(context.Background(), n); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Stat(%q) = %v, want ErrNotFound", n, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// backend/internal/blob/blob.go
+
+// Package blob stores files such as exports and backup archives. Store is
+// implemented by Local (a directory) and S3 (an Amazon S3 bucket or an
+// S3-compatible server like MinIO), selected by BLOB_STORE.
+//
+// Keys are slash-separated paths like "exports/7/12.xlsx" whose segments start with a
+// letter or digit and use only letters, digits, '.', '_' and '-'; other keys are
+// rejected, which also rules out path traversal.
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"pft/internal/repo"
+)
+
+// Store keeps blobs by key.
+type Store interface {
+	// Put stores size bytes read from r under key, replacing any existing blob.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns the blob's content; the caller closes it. ErrNotFound if missing.
+	Open(ctx context.Context, key string) (io.ReadCloser, *Info, error)
+	// Stat describes a blob. ErrNotFound if missing.
+	Stat(ctx context.Context, key string) (*Info, error)
+	// List describes the blobs whose keys start with prefix, in key order.
+	List(ctx context.Context, prefix string) ([]Info, error)
+	// Delete removes a blob; deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that downloads the blob as filename without further
+	// credentials until ttl has passed, or "" when the store does not hand out URLs
+	// (serve the blob through Open instead).
+	SignedURL(ctx context.Context, key, filename string, ttl time.Duration) (string, error)
+}
+
+// Info describes a stored blob.
+type Info struct {
+	Key         string    `json:"key"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	ModTime     time.Time `json:"modified_at"`
+}
+
+// ErrNotFound is returned for keys that are not stored (or not valid).
+var ErrNotFound = fmt.Errorf("blob: %w", repo.ErrNotFound)
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*(/[A-Za-z0-9][A-Za-z0-9._-]*)*$`)
+
+// ValidKey reports whether key may be stored.
+func ValidKey(key string) bool { return keyPattern.MatchString(key) }
+
+// ValidPrefix reports whether prefix may be used with List or as S3.Prefix: empty,
+// or a valid key optionally followed by a slash.
+func ValidPrefix(prefix string) bool {
+	if prefix == "" {
+		return true
+	}
+	if prefix[len(prefix)-1] == '/' {
+		prefix = prefix[:len(prefix)-1]
+	}
+	return ValidKey(prefix)
+}
@@ -0,0 +1,175 @@
+// backend/internal/blob/blob_test.go
+//
+// Purpose:
+//   Run the same round trip (put, stat, open, list by prefix, delete) against Local
+//   and against S3 talking to an in-memory S3 stand-in, and check key validation
+//   and presigned URLs.
+
+package blob
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func roundTrip(t *testing.T, s Store) {
+	t.Helper()
+	ctx := context.Background()
+	for _, key := range []string{"exports/7/1.xlsx", "exports/7/2.json.gz", "exports/8/3.xlsx", "pft-1.json.gz"} {
+		if err := s.Put(ctx, key, strings.NewReader("content of "+key), int64(len("content of "+key)), ""); err != nil {
+			t.Fatalf("Put(%s): %v", key, err)
+		}
+	}
+	if err := s.Put(ctx, "../escape", strings.NewReader("x"), 1, ""); err == nil {
+		t.Error("Put accepted ../escape")
+	}
+
+	rc, info, err := s.Open(ctx, "exports/7/1.xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(b) != "content of exports/7/1.xlsx" || info.Size != int64(len(b)) {
+		t.Fatalf("Open = %q, %+v", b, info)
+	}
+	for _, key := range []string{"exports/7/9.xlsx", "../exports/7/1.xlsx", "exports//7"} {
+		if _, err := s.Stat(ctx, key); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Stat(%q) = %v, want ErrNotFound", key, err)
+		}
+	}
+
+	list, err := s.List(ctx, "exports/7/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Key != "exports/7/1.xlsx" || list[1].Key != "exports/7/2.json.gz" {
+		t.Fatalf("List(exports/7/) = %+v", list)
+	}
+	if all, _ := s.List(ctx, ""); len(all) != 4 {
+		t.Fatalf("List() = %+v", all)
+	}
+
+	if err := s.Delete(ctx, "exports/7/1.xlsx"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "exports/7/1.xlsx"); err != nil {
+		t.Fatalf("deleting a missing blob: %v", err)
+	}
+	if _, err := s.Stat(ctx, "exports/7/1.xlsx"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Stat after Delete = %v", err)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	roundTrip(t, &Local{Dir: t.TempDir()})
+}
+
+// fakeS3 serves the subset of the S3 API used by S3 for one path-style bucket,
+// returning at most two keys per list page.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		var keys []string
+		for k := range f.objects {
+			if strings.HasPrefix(k, r.URL.Query().Get("prefix")) && k > r.URL.Query().Get("continuation-token") {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		type content struct {
+			Key          string
+			Size         int
+			LastModified time.Time
+		}
+		var page struct {
+			XMLName               xml.Name `xml:"ListBucketResult"`
+			IsTruncated           bool
+			NextContinuationToken string
+			Contents              []content
+		}
+		for i, k := range keys {
+			if i == 2 {
+				page.IsTruncated, page.NextContinuationToken = true, keys[1]
+				break
+			}
+			page.Contents = append(page.Contents, content{Key: k, Size: len(f.objects[k]), LastModified: time.Now()})
+		}
+		_ = xml.NewEncoder(w).Encode(page)
+	case r.Method == http.MethodPut:
+		b, _ := io.ReadAll(r.Body)
+		f.objects[key] = string(b)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		v, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(v)))
+		if r.Method == http.MethodGet {
+			_, _ = io.WriteString(w, v)
+		}
+	}
+}
+
+func TestS3(t *testing.T) {
+	fake := &fakeS3{objects: map[string]string{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	s := &S3{Endpoint: srv.URL, Region: "us-east-1", Bucket: "bucket", Prefix: "pft/", AccessKeyID: "AKID",
+		SecretAccessKey: "secret", PathStyle: true}
+	roundTrip(t, s)
+	if _, ok := fake.objects["pft/exports/7/2.json.gz"]; !ok {
+		t.Fatalf("keys not stored below the prefix: %v", fake.objects)
+	}
+
+	if u, err := s.SignedURL(context.Background(), "exports/7/2.json.gz", "export.json.gz", time.Minute); err != nil || u != "" {
+		t.Fatalf("SignedURL without SignedURLs = %q, %v", u, err)
+	}
+	s.SignedURLs = true
+	u, err := s.SignedURL(context.Background(), "exports/7/2.json.gz", "export.json.gz", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _ := url.Parse(u)
+	q := parsed.Query()
+	if parsed.Path != "/bucket/pft/exports/7/2.json.gz" || q.Get("X-Amz-Expires") != "60" || q.Get("X-Amz-Signature") == "" ||
+		q.Get("response-content-disposition") != `attachment; filename="export.json.gz"` {
+		t.Fatalf("SignedURL = %s", u)
+	}
+}
+
+func TestValidPrefix(t *testing.T) {
+	for p, want := range map[string]bool{"": true, "exports/": true, "exports/7": true, "/exports": false, "a//": false, "../": false} {
+		if ValidPrefix(p) != want {
+			t.Errorf("ValidPrefix(%q) = %v", p, !want)
+		}
+	}
+}
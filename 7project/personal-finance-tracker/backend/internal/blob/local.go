@@ -0,0 +1,142 @@
+// backend/internal/blob/local.go
+
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Local stores blobs as files below Dir (created on first write). Content types are
+// derived from the key's extension.
+type Local struct {
+	Dir string
+}
+
+// Put implements Store. The blob is written to a temporary file first, so readers
+// never see a partial one.
+func (l *Local) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	if !ValidKey(key) {
+		return fmt.Errorf("blob: invalid key %q", key)
+	}
+	p := filepath.Join(l.Dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), ".blob-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
+
+// Open implements Store.
+func (l *Local) Open(ctx context.Context, key string) (io.ReadCloser, *Info, error) {
+	info, err := l.Stat(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(filepath.Join(l.Dir, filepath.FromSlash(key)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, info, nil
+}
+
+// Stat implements Store.
+func (l *Local) Stat(_ context.Context, key string) (*Info, error) {
+	if !ValidKey(key) {
+		return nil, ErrNotFound
+	}
+	fi, err := os.Stat(filepath.Join(l.Dir, filepath.FromSlash(key)))
+	if errors.Is(err, os.ErrNotExist) || (err == nil && fi.IsDir()) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return localInfo(key, fi), nil
+}
+
+// List implements Store.
+func (l *Local) List(_ context.Context, prefix string) ([]Info, error) {
+	out := []Info{}
+	if !ValidPrefix(prefix) {
+		return out, nil
+	}
+	// Walk only the directory the prefix points into.
+	root := path.Dir(prefix + "x")
+	err := filepath.WalkDir(filepath.Join(l.Dir, filepath.FromSlash(root)), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(l.Dir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !ValidKey(key) || !strings.HasPrefix(key, prefix) {
+			return nil // temporary files and foreign names
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		out = append(out, *localInfo(key, fi))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out, nil
+}
+
+// Delete implements Store.
+func (l *Local) Delete(_ context.Context, key string) error {
+	if !ValidKey(key) {
+		return nil
+	}
+	err := os.Remove(filepath.Join(l.Dir, filepath.FromSlash(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// SignedURL implements Store; local blobs are always served through Open.
+func (l *Local) SignedURL(context.Context, string, string, time.Duration) (string, error) {
+	return "", nil
+}
+
+func localInfo(key string, fi fs.FileInfo) *Info {
+	ct := mime.TypeByExtension(path.Ext(key))
+	if ct == "" {
+		ct = "application/octet-stream"
+	}
+	return &Info{Key: key, Size: fi.Size(), ContentType: ct, ModTime: fi.ModTime().UTC()}
+}
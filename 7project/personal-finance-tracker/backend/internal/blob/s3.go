@@ -0,0 +1,230 @@
+// backend/internal/blob/s3.go
+
+package blob
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pft/internal/awssig"
+)
+
+// S3 stores blobs in a bucket of Amazon S3 or an S3-compatible server, signing
+// requests with AWS Signature Version 4.
+// - Endpoint: API base URL (default https://s3.<region>.amazonaws.com); e.g. http://minio:9000
+// - Region: bucket region (MinIO accepts any, usually "us-east-1")
+// - Bucket/Prefix: where blobs go; keys are stored as Prefix+key
+// - PathStyle: address the bucket as <endpoint>/<bucket> instead of <bucket>.<host> (MinIO)
+// - SignedURLs: hand out presigned download URLs; leave off when clients cannot reach Endpoint
+type S3 struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool
+	SignedURLs      bool
+	Client          *http.Client // defaults to a client with a 5 minute timeout
+}
+
+// objectURL returns the URL of the bucket (key "") or one of its objects.
+func (s *S3) objectURL(key string) (*url.URL, error) {
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + s.Region + ".amazonaws.com"
+	}
+	u, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("blob: S3 endpoint: %w", err)
+	}
+	if s.PathStyle {
+		u.Path += "/" + s.Bucket + "/" + key
+	} else {
+		u.Host = s.Bucket + "." + u.Host
+		u.Path += "/" + key
+	}
+	return u, nil
+}
+
+func (s *S3) credentials() awssig.Credentials {
+	return awssig.Credentials{Region: s.Region, Service: "s3", AccessKeyID: s.AccessKeyID, SecretAccessKey: s.SecretAccessKey}
+}
+
+// do signs and sends a request to the object at key (the bucket for ""), turning
+// 404 into ErrNotFound and other non-2xx replies into errors. The caller closes the
+// body of the returned response.
+func (s *S3) do(ctx context.Context, method, key string, query url.Values, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("X-Amz-Content-Sha256", awssig.UnsignedPayload)
+	awssig.Sign(req, awssig.UnsignedPayload, s.credentials(), time.Now())
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Minute}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	var e struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	_ = xml.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&e)
+	return nil, fmt.Errorf("blob: S3 %s answered %d %s: %s", method, resp.StatusCode, e.Code, e.Message)
+}
+
+// Put implements Store.
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if !ValidKey(key) {
+		return fmt.Errorf("blob: invalid key %q", key)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	resp, err := s.do(ctx, http.MethodPut, s.Prefix+key, nil, r, size, http.Header{"Content-Type": {contentType}})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Open implements Store.
+func (s *S3) Open(ctx context.Context, key string) (io.ReadCloser, *Info, error) {
+	if !ValidKey(key) {
+		return nil, nil, ErrNotFound
+	}
+	resp, err := s.do(ctx, http.MethodGet, s.Prefix+key, nil, nil, 0, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Body, headerInfo(key, resp), nil
+}
+
+// Stat implements Store.
+func (s *S3) Stat(ctx context.Context, key string) (*Info, error) {
+	if !ValidKey(key) {
+		return nil, ErrNotFound
+	}
+	resp, err := s.do(ctx, http.MethodHead, s.Prefix+key, nil, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return headerInfo(key, resp), nil
+}
+
+func headerInfo(key string, resp *http.Response) *Info {
+	info := &Info{Key: key, Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}
+	info.ModTime, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+	return info
+}
+
+// List implements Store, following continuation tokens until every page was read.
+func (s *S3) List(ctx context.Context, prefix string) ([]Info, error) {
+	out := []Info{}
+	if !ValidPrefix(prefix) {
+		return out, nil
+	}
+	q := url.Values{"list-type": {"2"}, "prefix": {s.Prefix + prefix}}
+	for {
+		resp, err := s.do(ctx, http.MethodGet, "", q, nil, 0, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+			Contents              []struct {
+				Key          string    `xml:"Key"`
+				Size         int64     `xml:"Size"`
+				LastModified time.Time `xml:"LastModified"`
+			} `xml:"Contents"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("blob: S3 list: %w", err)
+		}
+		for _, c := range page.Contents {
+			key := strings.TrimPrefix(c.Key, s.Prefix)
+			if ValidKey(key) {
+				out = append(out, Info{Key: key, Size: c.Size, ModTime: c.LastModified.UTC()})
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return out, nil
+		}
+		q.Set("continuation-token", page.NextContinuationToken)
+	}
+}
+
+// Delete implements Store.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if !ValidKey(key) {
+		return nil
+	}
+	resp, err := s.do(ctx, http.MethodDelete, s.Prefix+key, nil, nil, 0, nil)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// SignedURL implements Store with a presigned GET when SignedURLs is set. S3 limits
+// presigned URLs to a week.
+func (s *S3) SignedURL(_ context.Context, key, filename string, ttl time.Duration) (string, error) {
+	if !s.SignedURLs {
+		return "", nil
+	}
+	if !ValidKey(key) {
+		return "", ErrNotFound
+	}
+	u, err := s.objectURL(s.Prefix + key)
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{}
+	if filename != "" {
+		q.Set("response-content-disposition", `attachment; filename="`+filename+`"`)
+	}
+	u.RawQuery = strings.ReplaceAll(q.Encode(), "+", "%20")
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	awssig.Presign(req, s.credentials(), time.Now(), min(ttl, 7*24*time.Hour))
+	return req.URL.String(), nil
+}
+
+// String describes the store for logs, without credentials.
+func (s *S3) String() string { return "s3://" + s.Bucket + "/" + s.Prefix }
@@ -0,0 +1,76 @@
+// backend/internal/cache/cache.go
+
+// Package cache stores rendered read-model responses per user.
+// Entries are namespaced by a per-user generation number: invalidating a user bumps
+// the generation, which orphans all of that user's entries until their TTL expires.
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store caches values per user.
+type Store interface {
+	Get(ctx context.Context, userID int64, key string) ([]byte, bool, error)
+	Set(ctx context.Context, userID int64, key string, val []byte) error
+	Invalidate(ctx context.Context, userID int64) error
+}
+
+// Redis is a Store shared by every API instance using the same Redis.
+// - Prefix: namespace for all keys (e.g. "pft:cache:")
+// - TTL: lifetime of each entry; bounds staleness for time-dependent views
+type Redis struct {
+	Client redis.UniversalClient
+	Prefix string
+	TTL    time.Duration
+}
+
+func (r *Redis) genKey(userID int64) string {
+	return r.Prefix + "gen:" + strconv.FormatInt(userID, 10)
+}
+
+// entryKey resolves the current generation of the user and builds the entry key.
+func (r *Redis) entryKey(ctx context.Context, userID int64, key string) (string, error) {
+	gen, err := r.Client.Get(ctx, r.genKey(userID)).Result()
+	if errors.Is(err, redis.Nil) {
+		gen = "0"
+	} else if err != nil {
+		return "", err
+	}
+	return r.Prefix + strconv.FormatInt(userID, 10) + ":" + gen + ":" + key, nil
+}
+
+// Get implements Store. A miss is reported as (nil, false, nil).
+func (r *Redis) Get(ctx context.Context, userID int64, key string) ([]byte, bool, error) {
+	k, err := r.entryKey(ctx, userID, key)
+	if err != nil {
+		return nil, false, err
+	}
+	val, err := r.Client.Get(ctx, k).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return val, true, nil
+}
+
+// Set implements Store.
+func (r *Redis) Set(ctx context.Context, userID int64, key string, val []byte) error {
+	k, err := r.entryKey(ctx, userID, key)
+	if err != nil {
+		return err
+	}
+	return r.Client.Set(ctx, k, val, r.TTL).Err()
+}
+
+// Invalidate implements Store.
+func (r *Redis) Invalidate(ctx context.Context, userID int64) error {
+	return r.Client.Incr(ctx, r.genKey(userID)).Err()
+}
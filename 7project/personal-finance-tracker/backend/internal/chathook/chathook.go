@@ -0,0 +1,162 @@
+// backend/internal/chathook/chathook.go
+
+// Package chathook posts alerts to chat services through incoming webhooks users
+// add to their account. Each service is a Format (how an alert looks in its
+// payload) and a URL check; Channel delivers to every webhook of one service, so
+// each is a notification channel of its own with its own preferences.
+package chathook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"pft/internal/alerts"
+	"pft/internal/jobs"
+	"pft/internal/repo"
+)
+
+// Format renders alerts as one service's webhook payload.
+type Format interface {
+	// Payload is the JSON body posted for a.
+	Payload(a *alerts.Alert) any
+	// ValidURL reports whether u is one of the service's webhook URLs. Only those are
+	// accepted, so users cannot point the server at arbitrary hosts.
+	ValidURL(u *url.URL) bool
+}
+
+// Formats maps each supported service (a notification channel) to its format.
+var Formats = map[string]Format{
+	alerts.ChannelSlack:   Slack{},
+	alerts.ChannelDiscord: Discord{},
+}
+
+// Kinds lists the supported services.
+var Kinds = []string{alerts.ChannelSlack, alerts.ChannelDiscord}
+
+// ValidURL reports whether raw is a webhook URL of the service kind.
+func ValidURL(kind, raw string) bool {
+	f, ok := Formats[kind]
+	if !ok {
+		return false
+	}
+	u, err := url.Parse(raw)
+	return err == nil && u.Scheme == "https" && u.User == nil && f.ValidURL(u)
+}
+
+// Hooks lists webhooks and records deliveries; implemented by repo.ChatWebhookRepo.
+type Hooks interface {
+	ListEnabled(ctx context.Context, userID int64, kind string) ([]repo.ChatWebhook, error)
+	RecordDelivery(ctx context.Context, userID, id int64, failure *string, disable bool) error
+}
+
+// Channel is the alerts.Channel posting to a user's webhooks of one service.
+type Channel struct {
+	Kind   string // key of Formats
+	Hooks  Hooks
+	Client *http.Client // defaults to a client with a 15s timeout
+}
+
+// Name implements alerts.Channel.
+func (c *Channel) Name() string { return c.Kind }
+
+// Deliver implements alerts.Channel. Webhooks the service reports gone are disabled;
+// rate limits and server errors fail the delivery so it is retried (webhooks that
+// succeeded may then see the alert twice).
+func (c *Channel) Deliver(ctx context.Context, a *alerts.Alert) error {
+	hooks, err := c.Hooks.ListEnabled(ctx, a.UserID, c.Kind)
+	if err != nil {
+		return err
+	}
+	var retry error
+	for _, h := range hooks {
+		err := Post(ctx, c.Client, c.Kind, h.URL, a)
+		var failure *string
+		disable := false
+		if err != nil {
+			msg := err.Error()
+			failure = &msg
+			disable = isGone(err)
+			if disable {
+				slog.Info("disabling chat webhook", "webhook_id", h.ID, "kind", c.Kind, "error", msg)
+			} else if retryable(err) {
+				retry = err
+			}
+		}
+		if rerr := c.Hooks.RecordDelivery(ctx, a.UserID, h.ID, failure, disable); rerr != nil {
+			return rerr
+		}
+	}
+	return retry
+}
+
+// StatusError is a webhook reply other than 2xx.
+type StatusError struct {
+	Status int
+	Body   string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook answered %d: %s", e.Status, e.Body)
+}
+
+// isGone reports whether the webhook was deleted or its channel archived.
+func isGone(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && (se.Status == http.StatusNotFound || se.Status == http.StatusGone)
+}
+
+// retryable reports whether posting again later may succeed: network errors, rate
+// limits and server errors.
+func retryable(err error) bool {
+	var se *StatusError
+	return !errors.As(err, &se) || se.Status == http.StatusTooManyRequests || se.Status >= 500
+}
+
+// Post sends a to the webhook at rawURL of service kind. Replies other than 2xx are
+// returned as *StatusError; non-retryable ones are wrapped with jobs.Permanent.
+func Post(ctx context.Context, client *http.Client, kind, rawURL string, a *alerts.Alert) error {
+	f, ok := Formats[kind]
+	if !ok {
+		return jobs.Permanent(fmt.Errorf("unknown webhook kind %q", kind))
+	}
+	b, err := json.Marshal(f.Payload(a))
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(b))
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The URL is the webhook's secret; keep it out of errors and logs.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return fmt.Errorf("post to %s webhook: %w", kind, uerr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	reply, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	serr := &StatusError{Status: resp.StatusCode, Body: strings.TrimSpace(string(reply))}
+	if !retryable(serr) {
+		return jobs.Permanent(serr)
+	}
+	return serr
+}
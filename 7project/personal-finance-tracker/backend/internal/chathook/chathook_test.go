@@ -0,0 +1,103 @@
+// backend/internal/chathook/chathook_test.go
+//
+// Purpose:
+//   Verify that only Slack and Discord webhook URLs are accepted, that alerts are
+//   posted in each service's payload shape, and that webhooks reported gone are
+//   disabled while rate limits fail the delivery for a retry.
+
+package chathook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pft/internal/alerts"
+	"pft/internal/repo"
+)
+
+func TestValidURL(t *testing.T) {
+	cases := []struct {
+		kind, url string
+		want      bool
+	}{
+		{"slack", "https://hooks.slack.com/services/T000/B000/XXXX", true},
+		{"slack", "http://hooks.slack.com/services/T000/B000/XXXX", false},
+		{"slack", "https://hooks.slack.com.evil.example/services/T000", false},
+		{"slack", "https://discord.com/api/webhooks/1/abc", false},
+		{"discord", "https://discord.com/api/webhooks/1/abc", true},
+		{"discord", "https://discordapp.com/api/webhooks/1/abc", true},
+		{"discord", "https://user@discord.com/api/webhooks/1/abc", false},
+		{"discord", "https://169.254.169.254/latest/meta-data", false},
+		{"teams", "https://example.webhook.office.com/x", false},
+	}
+	for _, c := range cases {
+		if got := ValidURL(c.kind, c.url); got != c.want {
+			t.Errorf("ValidURL(%q, %q) = %v, want %v", c.kind, c.url, got, c.want)
+		}
+	}
+}
+
+type delivery struct {
+	id      int64
+	failure *string
+	disable bool
+}
+
+type fakeHooks struct {
+	hooks []repo.ChatWebhook
+	got   []delivery
+}
+
+func (f *fakeHooks) ListEnabled(context.Context, int64, string) ([]repo.ChatWebhook, error) {
+	return f.hooks, nil
+}
+func (f *fakeHooks) RecordDelivery(_ context.Context, _, id int64, failure *string, disable bool) error {
+	f.got = append(f.got, delivery{id, failure, disable})
+	return nil
+}
+
+func TestChannel_Deliver(t *testing.T) {
+	var bodies []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		switch r.URL.Path {
+		case "/gone":
+			http.Error(w, `{"message": "Unknown Webhook", "code": 10015}`, http.StatusNotFound)
+		case "/busy":
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	hooks := &fakeHooks{hooks: []repo.ChatWebhook{{ID: 1, URL: srv.URL + "/ok"}, {ID: 2, URL: srv.URL + "/gone"}}}
+	ch := &Channel{Kind: alerts.ChannelDiscord, Hooks: hooks}
+	a := &alerts.Alert{UserID: 7, Event: alerts.EventBudget, Title: "Food budget exceeded", Body: "@everyone spent 120.00"}
+	if err := ch.Deliver(context.Background(), a); err != nil {
+		t.Fatal(err)
+	}
+	embed := bodies[0]["embeds"].([]any)[0].(map[string]any)
+	if embed["title"] != a.Title || embed["color"] != float64(0xE67E22) || bodies[0]["allowed_mentions"] == nil {
+		t.Fatalf("discord payload %v", bodies[0])
+	}
+	if len(hooks.got) != 2 || hooks.got[0].failure != nil || !hooks.got[1].disable || !strings.Contains(*hooks.got[1].failure, "404") {
+		t.Fatalf("deliveries %+v", hooks.got)
+	}
+
+	bodies, hooks.got = nil, nil
+	hooks.hooks = []repo.ChatWebhook{{ID: 3, URL: srv.URL + "/busy"}}
+	ch.Kind = alerts.ChannelSlack
+	if err := ch.Deliver(context.Background(), a); err == nil {
+		t.Fatal("rate-limited delivery should fail for a retry")
+	}
+	if bodies[0]["text"] != "Food budget exceeded: @everyone spent 120.00" || hooks.got[0].disable {
+		t.Fatalf("slack payload %v, deliveries %+v", bodies[0], hooks.got)
+	}
+}
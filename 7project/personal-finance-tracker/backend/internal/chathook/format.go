@@ -0,0 +1,78 @@
+// backend/internal/chathook/format.go
+
+package chathook
+
+import (
+	"net/url"
+	"strings"
+
+	"pft/internal/alerts"
+)
+
+// Slack formats alerts for Slack incoming webhooks
+// (https://hooks.slack.com/services/...), as a bold title over the body.
+type Slack struct{}
+
+// Payload implements Format.
+func (Slack) Payload(a *alerts.Alert) any {
+	return map[string]any{
+		"text": a.Title + ": " + a.Body, // shown in notifications
+		"blocks": []any{map[string]any{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": "*" + slackEscape(a.Title) + "*\n" + slackEscape(a.Body)},
+		}},
+	}
+}
+
+// ValidURL implements Format.
+func (Slack) ValidURL(u *url.URL) bool {
+	return u.Host == "hooks.slack.com" && strings.HasPrefix(u.Path, "/services/")
+}
+
+// slackEscape escapes the characters Slack's mrkdwn treats as control sequences.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// Discord formats alerts for Discord webhooks (https://discord.com/api/webhooks/...),
+// as an embed colored by event.
+type Discord struct{}
+
+// discordColors are embed colors per alert event.
+var discordColors = map[string]int{
+	alerts.EventBudget:  0xE67E22, // orange
+	alerts.EventBill:    0x3498DB, // blue
+	alerts.EventAnomaly: 0xE74C3C, // red
+	alerts.EventWeekly:  0x2ECC71, // green
+}
+
+// Payload implements Format. Mentions in alert texts (e.g. "@everyone" in a
+// description) are not resolved.
+func (Discord) Payload(a *alerts.Alert) any {
+	return map[string]any{
+		"username":         "Personal Finance Tracker",
+		"allowed_mentions": map[string]any{"parse": []string{}},
+		"embeds": []any{map[string]any{
+			"title":       truncate(a.Title, 256),
+			"description": truncate(a.Body, 4096),
+			"color":       discordColors[a.Event],
+		}},
+	}
+}
+
+// ValidURL implements Format.
+func (Discord) ValidURL(u *url.URL) bool {
+	switch u.Host {
+	case "discord.com", "discordapp.com", "canary.discord.com", "ptb.discord.com":
+		return strings.HasPrefix(u.Path, "/api/webhooks/")
+	}
+	return false
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,69 @@
+// backend/internal/cron/cron_test.go
+//
+// Purpose:
+//   Verify expression parsing (ranges, lists, steps, names, shorthands, errors) and
+//   that Next and Prev find the right occurrences, including cron's day-field rule.
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestNextPrev(t *testing.T) {
+	cases := []struct {
+		spec, from, next, prev string
+	}{
+		{"*/15 * * * *", "2026-10-16 10:07", "2026-10-16 10:15", "2026-10-16 10:00"},
+		{"@daily", "2026-10-16 10:07", "2026-10-17 00:00", "2026-10-16 00:00"},
+		{"30 6 * * mon-fri", "2026-10-16 10:07", "2026-10-19 06:30", "2026-10-16 06:30"}, // Fri → Mon
+		{"0 0 1 jan,jul *", "2026-10-16 10:07", "2027-01-01 00:00", "2026-07-01 00:00"},
+		{"0 12 13 * 5", "2026-10-16 10:07", "2026-10-16 12:00", "2026-10-13 12:00"}, // 13th or any Friday
+		{"0 0 29 2 *", "2026-10-16 10:07", "2028-02-29 00:00", "2024-02-29 00:00"},
+		{"0 9 * * 7", "2026-10-16 10:07", "2026-10-18 09:00", "2026-10-11 09:00"}, // 7 is Sunday
+	}
+	for _, tc := range cases {
+		s, err := Parse(tc.spec)
+		if err != nil {
+			t.Errorf("%s: %v", tc.spec, err)
+			continue
+		}
+		if got := s.Next(at(tc.from)); !got.Equal(at(tc.next)) {
+			t.Errorf("%s: Next = %s, want %s", tc.spec, got, tc.next)
+		}
+		if got := s.Prev(at(tc.from)); !got.Equal(at(tc.prev)) {
+			t.Errorf("%s: Prev = %s, want %s", tc.spec, got, tc.prev)
+		}
+	}
+
+	// An occurrence is its own Prev but not its own Next.
+	s, _ := Parse("0 * * * *")
+	if got := s.Prev(at("2026-10-16 10:00")); !got.Equal(at("2026-10-16 10:00")) {
+		t.Errorf("Prev at occurrence = %s", got)
+	}
+	if got := s.Next(at("2026-10-16 10:00")); !got.Equal(at("2026-10-16 11:00")) {
+		t.Errorf("Next at occurrence = %s", got)
+	}
+
+	never, _ := Parse("0 0 31 2 *")
+	if !never.Next(at("2026-10-16 10:00")).IsZero() {
+		t.Error("expected no occurrence for Feb 31")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *", "@often"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("expected %q to be rejected", spec)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// backend/internal/crypt/crypt.go
+
+// Package crypt encrypts sensitive text fields at rest with envelope encryption:
+// each user has a random data key (DEK) that encrypts their fields with AES-256-GCM,
+// and the DEK is stored only wrapped by a master key (a Wrapper: MasterKey from
+// config or a key in AWS KMS). Losing the master key loses the data.
+//
+// Encrypted values are text, so they fit the existing columns: "enc1:" followed by
+// base64 of the nonce and ciphertext. Values without the prefix are plaintext written
+// before encryption was enabled and are returned unchanged by Decrypt.
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the length of data and master keys (AES-256).
+const KeySize = 32
+
+const prefix = "enc1:"
+
+// NewKey returns a random data key.
+func NewKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Encrypted reports whether value was produced by Encrypt.
+func Encrypted(value string) bool { return strings.HasPrefix(value, prefix) }
+
+// Encrypt seals plaintext with key. aad (e.g. the owning user) is authenticated but
+// not stored: the value only decrypts with the same aad, so it cannot be copied to
+// another user's row.
+func Encrypt(key []byte, plaintext, aad string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), []byte(aad))
+	return prefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value sealed by Encrypt; values that are not encrypted are
+// returned as they are.
+func Decrypt(key []byte, value, aad string) (string, error) {
+	enc, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return value, nil
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(enc)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", errors.New("crypt: malformed value")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(aad))
+	if err != nil {
+		return "", errors.New("crypt: value does not decrypt with this key")
+	}
+	return string(plain), nil
+}
+
+// BlindIndex returns a keyed hash of plaintext, ignoring case and surrounding blanks,
+// so encrypted values can still be matched for equality without decrypting them.
+// It reveals which of a user's values are equal, nothing else.
+func BlindIndex(key []byte, plaintext string) string {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte("blind-index"))
+	m = hmac.New(sha256.New, m.Sum(nil))
+	m.Write([]byte(strings.ToLower(strings.TrimSpace(plaintext))))
+	return hex.EncodeToString(m.Sum(nil)[:16])
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("crypt: key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
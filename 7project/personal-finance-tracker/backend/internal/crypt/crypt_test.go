@@ -0,0 +1,113 @@
+// backend/internal/crypt/crypt_test.go
+//
+// Purpose:
+//   Check that values round-trip only with the right key and context, that plaintext
+//   passes through, that blind indexes ignore case, and that both wrappers return the
+//   data key they were given.
+
+package crypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	key, _ := NewKey()
+	other, _ := NewKey()
+	v, err := Encrypt(key, "Coffee at Joe's", "user:7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Encrypted(v) || strings.Contains(v, "Coffee") {
+		t.Fatalf("not encrypted: %s", v)
+	}
+	if again, _ := Encrypt(key, "Coffee at Joe's", "user:7"); again == v {
+		t.Error("equal plaintexts encrypt to equal values")
+	}
+	if got, err := Decrypt(key, v, "user:7"); err != nil || got != "Coffee at Joe's" {
+		t.Fatalf("Decrypt = %q, %v", got, err)
+	}
+	if _, err := Decrypt(key, v, "user:8"); err == nil {
+		t.Error("decrypted for another user")
+	}
+	if _, err := Decrypt(other, v, "user:7"); err == nil {
+		t.Error("decrypted with another key")
+	}
+	if got, err := Decrypt(key, "written before encryption", "user:7"); err != nil || got != "written before encryption" {
+		t.Errorf("plaintext = %q, %v", got, err)
+	}
+	if BlindIndex(key, " Coffee ") != BlindIndex(key, "coffee") || BlindIndex(key, "coffee") == BlindIndex(other, "coffee") {
+		t.Error("blind index should ignore case and depend on the key")
+	}
+}
+
+func TestMasterKey(t *testing.T) {
+	if _, err := NewMasterKey("c2hvcnQ="); err == nil {
+		t.Fatal("accepted a short master key")
+	}
+	m, err := NewMasterKey("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dek, _ := NewKey()
+	wrapped, err := m.Wrap(context.Background(), dek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := m.Unwrap(context.Background(), wrapped); err != nil || !bytes.Equal(got, dek) {
+		t.Fatalf("Unwrap = %x, %v", got, err)
+	}
+}
+
+func TestKMS(t *testing.T) {
+	// The stand-in "encrypts" by reversing the bytes, which is enough to check the protocol.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-amz-json-1.1" ||
+			!strings.Contains(r.Header.Get("Authorization"), "/us-east-1/kms/aws4_request") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var in struct {
+			KeyId          string
+			Plaintext      []byte
+			CiphertextBlob []byte
+		}
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		reverse := func(b []byte) []byte {
+			out := make([]byte, len(b))
+			for i := range b {
+				out[len(b)-1-i] = b[i]
+			}
+			return out
+		}
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			_ = json.NewEncoder(w).Encode(map[string]any{"CiphertextBlob": reverse(in.Plaintext), "KeyId": in.KeyId})
+		case "TrentService.Decrypt":
+			_ = json.NewEncoder(w).Encode(map[string]any{"Plaintext": reverse(in.CiphertextBlob)})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"UnknownOperationException","message":"nope"}`))
+		}
+	}))
+	defer srv.Close()
+
+	k := &KMS{KeyID: "alias/pft", Region: "us-east-1", Endpoint: srv.URL, AccessKeyID: "AKID", SecretAccessKey: "secret"}
+	dek := []byte("0123456789abcdef0123456789abcdef")
+	wrapped, err := k.Wrap(context.Background(), dek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(wrapped, dek) {
+		t.Fatal("Wrap returned the plaintext key")
+	}
+	if got, err := k.Unwrap(context.Background(), wrapped); err != nil || !bytes.Equal(got, dek) {
+		t.Fatalf("Unwrap = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,123 @@
+// backend/internal/crypt/wrap.go
+
+package crypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"pft/internal/awssig"
+)
+
+// Wrapper protects data keys with a master key that never leaves it.
+type Wrapper interface {
+	Wrap(ctx context.Context, dek []byte) ([]byte, error)
+	Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// MasterKey wraps data keys locally with AES-256-GCM under a key from config.
+type MasterKey struct {
+	key []byte
+}
+
+// NewMasterKey decodes a base64 master key of KeySize bytes (see ENCRYPTION_KEY;
+// generate one with `openssl rand -base64 32`).
+func NewMasterKey(b64 string) (*MasterKey, error) {
+	key, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil || len(key) != KeySize {
+		return nil, fmt.Errorf("crypt: master key must be %d bytes of base64", KeySize)
+	}
+	return &MasterKey{key: key}, nil
+}
+
+// Wrap implements Wrapper.
+func (m *MasterKey) Wrap(_ context.Context, dek []byte) ([]byte, error) {
+	s, err := Encrypt(m.key, string(dek), "dek")
+	return []byte(s), err
+}
+
+// Unwrap implements Wrapper.
+func (m *MasterKey) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) {
+	if !Encrypted(string(wrapped)) {
+		return nil, errors.New("crypt: data key was not wrapped by a master key")
+	}
+	s, err := Decrypt(m.key, string(wrapped), "dek")
+	return []byte(s), err
+}
+
+// KMS wraps data keys with a symmetric key in AWS KMS (Encrypt/Decrypt calls signed
+// with Signature Version 4), so the master key itself is never in the config.
+// - KeyID: key ID, ARN or alias ("alias/pft")
+// - Endpoint: API URL; defaults to https://kms.<region>.amazonaws.com
+type KMS struct {
+	KeyID           string
+	Region          string
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	Client          *http.Client // defaults to a client with a 10 second timeout
+}
+
+// Wrap implements Wrapper.
+func (k *KMS) Wrap(ctx context.Context, dek []byte) ([]byte, error) {
+	var out struct {
+		CiphertextBlob []byte
+	}
+	err := k.call(ctx, "Encrypt", map[string]any{"KeyId": k.KeyID, "Plaintext": dek}, &out)
+	return out.CiphertextBlob, err
+}
+
+// Unwrap implements Wrapper.
+func (k *KMS) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var out struct {
+		Plaintext []byte
+	}
+	err := k.call(ctx, "Decrypt", map[string]any{"KeyId": k.KeyID, "CiphertextBlob": wrapped}, &out)
+	return out.Plaintext, err
+}
+
+// call invokes a KMS action; []byte fields travel as base64 as the API expects.
+func (k *KMS) call(ctx context.Context, action string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	endpoint := k.Endpoint
+	if endpoint == "" {
+		endpoint = "https://kms." + k.Region + ".amazonaws.com"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	awssig.Sign(req, awssig.HashPayload(body), awssig.Credentials{Region: k.Region, Service: "kms",
+		AccessKeyID: k.AccessKeyID, SecretAccessKey: k.SecretAccessKey}, time.Now())
+
+	client := k.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&e)
+		return fmt.Errorf("crypt: KMS %s answered %d %s: %s", action, resp.StatusCode, e.Type, e.Message)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
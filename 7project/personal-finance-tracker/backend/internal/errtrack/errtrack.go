@@ -0,0 +1,210 @@
+// backend/internal/errtrack/errtrack.go
+
+// Package errtrack reports server errors to Sentry (or a Sentry-compatible service
+// such as GlitchTip) so failures that clients only see as "server" can be debugged
+// remotely. Events carry the request ID, route and user ID, never request bodies.
+//
+// Events are sent in the background; when the service is slow or down they are
+// dropped rather than delaying requests. A nil *Client reports nothing.
+package errtrack
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event describes one error.
+// - Err: the error; its message and type name are reported
+// - Panic: set when Err was recovered from a panic; the stack of the caller is attached
+// - UserID/RequestID/Method/Route: request context, when there was a request
+// - Tags: extra searchable values such as the job kind
+type Event struct {
+	Err       error
+	Panic     bool
+	UserID    int64
+	RequestID string
+	Method    string
+	Route     string
+	Tags      map[string]string
+}
+
+// Client sends events to the project of a DSN.
+type Client struct {
+	endpoint    string
+	auth        string
+	environment string
+	release     string
+	http        *http.Client
+
+	mu     sync.RWMutex // guards closed against Capture racing Close
+	closed bool
+	queue  chan []byte
+	wg     sync.WaitGroup
+}
+
+// queueSize bounds the events waiting to be sent; more are dropped.
+const queueSize = 100
+
+// New parses dsn ("https://<key>@<host>/<project>") and starts the sender.
+// environment and release are attached to every event.
+func New(dsn, environment, release string) (*Client, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil || u.User.Username() == "" || u.Host == "" {
+		return nil, fmt.Errorf("errtrack: malformed DSN")
+	}
+	path, project := "", strings.TrimPrefix(u.Path, "/")
+	if i := strings.LastIndex(project, "/"); i >= 0 {
+		path, project = "/"+project[:i], project[i+1:]
+	}
+	if _, err := strconv.Atoi(project); err != nil {
+		return nil, fmt.Errorf("errtrack: DSN lacks a project id")
+	}
+	c := &Client{
+		endpoint:    u.Scheme + "://" + u.Host + path + "/api/" + project + "/store/",
+		auth:        "Sentry sentry_version=7, sentry_client=pft/" + release + ", sentry_key=" + u.User.Username(),
+		environment: environment,
+		release:     release,
+		http:        &http.Client{Timeout: 10 * time.Second},
+		queue:       make(chan []byte, queueSize),
+	}
+	c.wg.Add(1)
+	go c.send()
+	return c, nil
+}
+
+// Capture queues e for sending.
+func (c *Client) Capture(e Event) {
+	if c == nil || e.Err == nil {
+		return
+	}
+	b, err := json.Marshal(c.payload(e))
+	if err != nil {
+		return
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.closed {
+		return
+	}
+	select {
+	case c.queue <- b:
+	default:
+		slog.Warn("error report dropped: queue full")
+	}
+}
+
+// Close sends the queued events, waiting at most until ctx is done.
+func (c *Client) Close(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.queue)
+	}
+	c.mu.Unlock()
+	done := make(chan struct{})
+	go func() { c.wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+func (c *Client) send() {
+	defer c.wg.Done()
+	for b := range c.queue {
+		req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(b))
+		if err != nil {
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Sentry-Auth", c.auth)
+		resp, err := c.http.Do(req)
+		if err != nil {
+			slog.Warn("error report failed", "error", err.Error())
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			slog.Warn("error report rejected", "status", resp.StatusCode)
+		}
+	}
+}
+
+// payload builds the event in Sentry's store format.
+func (c *Client) payload(e Event) map[string]any {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	host, _ := os.Hostname()
+	exception := map[string]any{"type": fmt.Sprintf("%T", e.Err), "value": e.Err.Error()}
+	if e.Panic {
+		exception["type"] = "panic"
+		exception["stacktrace"] = map[string]any{"frames": stack(4)}
+	}
+	tags := map[string]string{}
+	for k, v := range e.Tags {
+		tags[k] = v
+	}
+	p := map[string]any{
+		"event_id":    hex.EncodeToString(id[:]),
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		"level":       "error",
+		"platform":    "go",
+		"server_name": host,
+		"environment": c.environment,
+		"release":     c.release,
+		"exception":   map[string]any{"values": []any{exception}},
+		"tags":        tags,
+	}
+	if e.RequestID != "" {
+		tags["request_id"] = e.RequestID
+	}
+	if e.Route != "" {
+		p["transaction"] = e.Method + " " + e.Route
+		p["request"] = map[string]any{"method": e.Method, "url": e.Route}
+	}
+	if e.UserID != 0 {
+		p["user"] = map[string]any{"id": strconv.FormatInt(e.UserID, 10)}
+	}
+	return p
+}
+
+// stack returns the caller's frames, outermost first as Sentry expects, skipping
+// the innermost skip frames (runtime and errtrack itself).
+func stack(skip int) []map[string]any {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var out []map[string]any
+	for {
+		f, more := frames.Next()
+		out = append(out, map[string]any{
+			"function": f.Function,
+			"abs_path": f.File,
+			"lineno":   f.Line,
+			"in_app":   strings.HasPrefix(f.Function, "pft/"),
+		})
+		if !more {
+			break
+		}
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+// backend/internal/errtrack/errtrack_test.go
+//
+// Purpose:
+//   Check DSN parsing and that a captured event reaches the store endpoint with the
+//   auth header, the error, the user and the request ID; and that a nil Client is
+//   safe to use.
+
+package errtrack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew_DSN(t *testing.T) {
+	for _, dsn := range []string{"", "https://sentry.example.com/1", "https://key@sentry.example.com/", "https://key@sentry.example.com/abc"} {
+		if _, err := New(dsn, "test", "dev"); err == nil {
+			t.Errorf("New(%q) accepted a malformed DSN", dsn)
+		}
+	}
+	c, err := New("https://pub@sentry.example.com/sub/42", "test", "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close(context.Background())
+	if c.endpoint != "https://sentry.example.com/sub/api/42/store/" || !strings.Contains(c.auth, "sentry_key=pub") {
+		t.Fatalf("endpoint %q, auth %q", c.endpoint, c.auth)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	got := make(chan map[string]any, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/7/store/" || !strings.Contains(r.Header.Get("X-Sentry-Auth"), "sentry_key=pub") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		var p map[string]any
+		_ = json.Unmarshal(b, &p)
+		got <- p
+	}))
+	defer srv.Close()
+
+	c, err := New(strings.Replace(srv.URL, "://", "://pub@", 1)+"/7", "staging", "1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Capture(Event{Err: errors.New("db down"), UserID: 5, RequestID: "req-1", Method: "GET", Route: "/api/transactions"})
+	c.Close(context.Background())
+
+	select {
+	case p := <-got:
+		b, _ := json.Marshal(p)
+		for _, want := range []string{`"value":"db down"`, `"id":"5"`, `"request_id":"req-1"`,
+			`"transaction":"GET /api/transactions"`, `"environment":"staging"`, `"release":"1.2.3"`} {
+			if !strings.Contains(string(b), want) {
+				t.Errorf("event lacks %s: %s", want, b)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+	}
+
+	c.Capture(Event{Err: errors.New("after close")}) // dropped, must not panic
+}
+
+func TestNilClient(t *testing.T) {
+	var c *Client
+	c.Capture(Event{Err: errors.New("x")})
+	c.Close(context.Background())
+}
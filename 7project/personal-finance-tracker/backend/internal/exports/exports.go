@@ -0,0 +1,196 @@
+// backend/internal/exports/exports.go
+
+// Package exports builds large downloads in background jobs instead of request
+// handlers: a user's full data archive and a year of transactions as an XLSX
+// workbook. The handler queues a JobKind job, which puts the file in blob storage;
+// clients poll the export and, once it is done, fetch it from a signed URL that
+// expires (see SignURL), so the download needs no Authorization header and can be
+// handed to a browser.
+package exports
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"pft/internal/blob"
+	"pft/internal/jobs"
+	"pft/internal/repo"
+)
+
+// JobKind is the job kind of exports; its payload is {"export_id": N}.
+const JobKind = "exports.run"
+
+// Kinds of exports.
+const (
+	KindArchive = "archive"
+	KindXLSX    = "xlsx"
+)
+
+// Store keeps exports and reads the transactions of XLSX exports; implemented by
+// repo.ExportRepo.
+type Store interface {
+	Start(ctx context.Context, id int64) (*repo.Export, error)
+	Finish(ctx context.Context, id int64, filename, contentType, key string, size int64) error
+	Fail(ctx context.Context, id int64, msg string) error
+	YearTransactions(ctx context.Context, userID int64, year int) ([]repo.ExportTransaction, error)
+	Cleanup(ctx context.Context, cutoff time.Time) ([]string, error)
+}
+
+// Dumps reads all of a user's data, decrypted; implemented by repo.BackupRepo.
+type Dumps interface {
+	ExportReadable(ctx context.Context, userID int64) (*repo.UserDump, error)
+}
+
+// Exporter runs export jobs, keeping files in Blobs under "exports/<user>/<id>.<ext>".
+type Exporter struct {
+	Store Store
+	Dumps Dumps
+	Blobs blob.Store
+}
+
+// Handle is the jobs.HandlerFunc for JobKind.
+func (x *Exporter) Handle(ctx context.Context, j *repo.Job) error {
+	var p struct {
+		ExportID int64 `json:"export_id"`
+	}
+	if err := json.Unmarshal(j.Payload, &p); err != nil || p.ExportID == 0 {
+		return jobs.Permanent(fmt.Errorf("decode payload: %s", j.Payload))
+	}
+	e, err := x.Store.Start(ctx, p.ExportID)
+	if err != nil || e == nil {
+		return err
+	}
+	var buf bytes.Buffer
+	var filename, contentType, ext string
+	switch {
+	case e.Kind == KindArchive:
+		filename, contentType, ext = "pft-export-"+time.Now().UTC().Format("20060102")+".json.gz", "application/gzip", ".json.gz"
+		err = x.archive(ctx, &buf, e.UserID)
+	case e.Kind == KindXLSX && e.Year != nil:
+		filename, ext = fmt.Sprintf("transactions-%d.xlsx", *e.Year), ".xlsx"
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		err = x.workbook(ctx, &buf, e.UserID, *e.Year)
+	default:
+		return x.Store.Fail(ctx, e.ID, fmt.Sprintf("unknown export kind %q", e.Kind))
+	}
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("exports/%d/%d%s", e.UserID, e.ID, ext)
+	if err := x.Blobs.Put(ctx, key, bytes.NewReader(buf.Bytes()), int64(buf.Len()), contentType); err != nil {
+		return err
+	}
+	return x.Store.Finish(ctx, e.ID, filename, contentType, key, int64(buf.Len()))
+}
+
+// Cleanup deletes exports created before cutoff together with their files. A file
+// that cannot be deleted does not stop the others from being deleted.
+func (x *Exporter) Cleanup(ctx context.Context, cutoff time.Time) error {
+	keys, err := x.Store.Cleanup(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, key := range keys {
+		if err := x.Blobs.Delete(ctx, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// archive writes the user's rows of every backed-up table as gzipped JSON, without
+// the password hash.
+func (x *Exporter) archive(ctx context.Context, w *bytes.Buffer, userID int64) error {
+	d, err := x.Dumps.ExportReadable(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return jobs.Permanent(fmt.Errorf("user %d not found", userID))
+	}
+	var users []map[string]json.RawMessage
+	if err := json.Unmarshal(d.Tables["users"], &users); err != nil {
+		return err
+	}
+	for _, u := range users {
+		delete(u, "password_hash")
+	}
+	if d.Tables["users"], err = json.Marshal(users); err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(map[string]any{
+		"exported_at": time.Now().UTC(),
+		"tables":      d.Tables,
+	}); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// workbook writes the year's transactions and monthly totals as XLSX.
+func (x *Exporter) workbook(ctx context.Context, w *bytes.Buffer, userID int64, year int) error {
+	txs, err := x.Store.YearTransactions(ctx, userID, year)
+	if err != nil {
+		return err
+	}
+	return WriteXLSX(w, Workbook(year, txs))
+}
+
+// Workbook lays out an XLSX export: one sheet listing the transactions and one with
+// income, expenses and net per month.
+func Workbook(year int, txs []repo.ExportTransaction) []Sheet {
+	list := Sheet{Name: "Transactions", Rows: [][]any{{"Date", "Type", "Category", "Description", "Amount"}}}
+	var income, expense [12]float64
+	for _, t := range txs {
+		cat := ""
+		if t.Category != nil {
+			cat = *t.Category
+		}
+		list.Rows = append(list.Rows, []any{t.Date, t.Type, cat, t.Description, t.Amount})
+		if t.Type == "income" {
+			income[t.Date.Month()-1] += t.Amount
+		} else {
+			expense[t.Date.Month()-1] += t.Amount
+		}
+	}
+	months := Sheet{Name: "Months", Rows: [][]any{{"Month", "Income", "Expenses", "Net"}}}
+	for m := range 12 {
+		label := time.Date(year, time.Month(m+1), 1, 0, 0, 0, 0, time.UTC).Format("2006-01")
+		months.Rows = append(months.Rows, []any{label, income[m], expense[m], income[m] - expense[m]})
+	}
+	return []Sheet{list, months}
+}
+
+// SignURL returns the query string authorizing a download of export id until expires.
+func SignURL(secret []byte, id int64, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	return url.Values{"expires": {exp}, "sig": {signature(secret, id, exp)}}.Encode()
+}
+
+// VerifyURL reports whether expires and sig, taken from a download URL, authorize a
+// download of export id at now.
+func VerifyURL(secret []byte, id int64, expires, sig string, now time.Time) bool {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > exp {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(signature(secret, id, expires)))
+}
+
+func signature(secret []byte, id int64, expires string) string {
+	m := hmac.New(sha256.New, secret)
+	fmt.Fprintf(m, "export:%d:%s", id, expires)
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
@@ -0,0 +1,156 @@
+// backend/internal/exports/exports_test.go
+//
+// Purpose:
+//   Verify that XLSX workbooks contain the expected parts and cells, that archives
+//   leave out the password hash and are stored and cleaned up in blob storage, and
+//   that download URLs only work unaltered and before they expire.
+
+package exports
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"pft/internal/blob"
+	"pft/internal/repo"
+)
+
+func TestWriteXLSX(t *testing.T) {
+	food := "Food & Drink"
+	txs := []repo.ExportTransaction{
+		{Date: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Type: "income", Description: "salary", Amount: 3000},
+		{Date: time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), Type: "expense", Category: &food, Description: "<lunch>", Amount: 12.5},
+	}
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, Workbook(2025, txs)); err != nil {
+		t.Fatal(err)
+	}
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]string{}
+	for _, f := range z.File {
+		rc, _ := f.Open()
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(b)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Fatalf("missing part %s", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="Months" sheetId="2" r:id="rId2"/>`) {
+		t.Fatalf("workbook %s", parts["xl/workbook.xml"])
+	}
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" t="inlineStr" s="1"><is><t xml:space="preserve">Date</t></is></c>`,
+		`<c r="A2" s="2"><v>45672</v></c>`, // 2025-01-15
+		`<t xml:space="preserve">Food &amp; Drink</t>`,
+		`<t xml:space="preserve">&lt;lunch&gt;</t>`,
+		`<c r="E3" s="3"><v>12.5</v></c>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet1 lacks %s", want)
+		}
+	}
+	if months := parts["xl/worksheets/sheet2.xml"]; !strings.Contains(months, `<c r="D2" s="3"><v>3000</v></c>`) ||
+		!strings.Contains(months, `<c r="D4" s="3"><v>-12.5</v></c>`) || !strings.Contains(months, ">2025-12<") {
+		t.Errorf("months sheet %s", months)
+	}
+	if colName(0) != "A" || colName(25) != "Z" || colName(26) != "AA" || colName(701) != "ZZ" {
+		t.Error("colName")
+	}
+}
+
+type fakeStore struct {
+	export   repo.Export
+	filename string
+	key      string
+}
+
+func (f *fakeStore) Start(context.Context, int64) (*repo.Export, error) { return &f.export, nil }
+func (f *fakeStore) Finish(_ context.Context, _ int64, filename, _, key string, _ int64) error {
+	f.filename, f.key = filename, key
+	return nil
+}
+func (f *fakeStore) Cleanup(context.Context, time.Time) ([]string, error) {
+	return []string{f.key}, nil
+}
+func (f *fakeStore) Fail(context.Context, int64, string) error { return nil }
+func (f *fakeStore) YearTransactions(context.Context, int64, int) ([]repo.ExportTransaction, error) {
+	return nil, nil
+}
+
+type fakeDumps struct{}
+
+func (fakeDumps) ExportReadable(_ context.Context, userID int64) (*repo.UserDump, error) {
+	return &repo.UserDump{UserID: userID, Tables: map[string]json.RawMessage{
+		"users":        json.RawMessage(`[{"id": 7, "email": "a@example.com", "password_hash": "$2a$10$secret"}]`),
+		"transactions": json.RawMessage(`[{"id": 1, "amount": 12.5}]`),
+	}}, nil
+}
+
+func TestExporter_Archive(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{export: repo.Export{ID: 1, UserID: 7, Kind: KindArchive}}
+	blobs := &blob.Local{Dir: t.TempDir()}
+	x := &Exporter{Store: store, Dumps: fakeDumps{}, Blobs: blobs}
+	if err := x.Handle(ctx, &repo.Job{Kind: JobKind, Payload: json.RawMessage(`{"export_id": 1}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if store.key != "exports/7/1.json.gz" {
+		t.Fatalf("stored as %q", store.key)
+	}
+	rc, _, err := blobs.Open(ctx, store.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(zr)
+	rc.Close()
+	if !strings.HasSuffix(store.filename, ".json.gz") || strings.Contains(string(b), "password_hash") ||
+		!strings.Contains(string(b), `"email":"a@example.com"`) || !strings.Contains(string(b), `"amount":12.5`) {
+		t.Fatalf("archive %s: %s", store.filename, b)
+	}
+
+	if err := x.Cleanup(ctx, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := blobs.Stat(ctx, store.key); !errors.Is(err, blob.ErrNotFound) {
+		t.Fatalf("file left after cleanup: %v", err)
+	}
+}
+
+func TestSignURL(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Unix(1_800_000_000, 0)
+	q, _ := url.ParseQuery(SignURL(secret, 5, now.Add(15*time.Minute)))
+	exp, sig := q.Get("expires"), q.Get("sig")
+	if !VerifyURL(secret, 5, exp, sig, now) {
+		t.Fatal("fresh URL rejected")
+	}
+	if VerifyURL(secret, 5, exp, sig, now.Add(16*time.Minute)) {
+		t.Error("expired URL accepted")
+	}
+	if VerifyURL(secret, 6, exp, sig, now) || VerifyURL([]byte("other"), 5, exp, sig, now) {
+		t.Error("URL accepted for another export or key")
+	}
+	if VerifyURL(secret, 5, "1900000000", sig, now) {
+		t.Error("extended expiry accepted")
+	}
+}
@@ -0,0 +1,109 @@
+// backend/internal/flags/flags.go
+
+// Package flags evaluates feature flags for gradual rollouts. Definitions live in the
+// database (repo.FlagRepo) and are cached in memory, so checking a flag on every
+// request costs no query; changes made through another instance apply within TTL.
+package flags
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"pft/internal/repo"
+)
+
+// Loader returns every flag with its overrides; *repo.FlagRepo satisfies it.
+type Loader interface {
+	List(ctx context.Context) ([]repo.Flag, error)
+}
+
+// Set is a cached view of all flags.
+// - Store: source of flag definitions
+// - TTL: how long a loaded snapshot is used (default 30s)
+type Set struct {
+	Store Loader
+	TTL   time.Duration
+
+	mu       sync.Mutex
+	flags    map[string]repo.Flag
+	loadedAt time.Time
+}
+
+// Enabled reports whether the flag is on for userID. Unknown flags are off.
+func (s *Set) Enabled(ctx context.Context, key string, userID int64) bool {
+	f, ok := s.snapshot(ctx)[key]
+	return ok && Evaluate(f, userID)
+}
+
+// ForUser evaluates every flag for userID.
+func (s *Set) ForUser(ctx context.Context, userID int64) map[string]bool {
+	flags := s.snapshot(ctx)
+	out := make(map[string]bool, len(flags))
+	for k, f := range flags {
+		out[k] = Evaluate(f, userID)
+	}
+	return out
+}
+
+// Invalidate drops the cached snapshot so the next check reloads it.
+func (s *Set) Invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// snapshot returns the cached flags, reloading them once TTL has passed. If loading
+// fails the previous snapshot stays in use for another TTL (all flags off if there is none).
+func (s *Set) snapshot(ctx context.Context) map[string]repo.Flag {
+	ttl := s.TTL
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.flags != nil && time.Since(s.loadedAt) < ttl {
+		return s.flags
+	}
+	// Flags are global: load every user's overrides, and finish even if the request is gone.
+	list, err := s.Store.List(repo.WithoutTenant(context.WithoutCancel(ctx)))
+	if err != nil {
+		// Retry after another TTL rather than on every check while the database is down.
+		slog.Warn("loading feature flags failed", "error", err.Error())
+		if s.flags == nil {
+			s.flags = map[string]repo.Flag{}
+		}
+		s.loadedAt = time.Now()
+		return s.flags
+	}
+	m := make(map[string]repo.Flag, len(list))
+	for _, f := range list {
+		m[f.Key] = f
+	}
+	s.flags, s.loadedAt = m, time.Now()
+	return m
+}
+
+// Evaluate decides a flag for one user: an override wins, then the global switch,
+// then the rollout percentage. A user's bucket depends on the flag key, so each
+// rollout reaches a different subset of users, and raising the percentage only
+// ever adds users.
+func Evaluate(f repo.Flag, userID int64) bool {
+	if on, ok := f.Overrides[userID]; ok {
+		return on
+	}
+	if f.Enabled {
+		return true
+	}
+	return f.RolloutPercent > 0 && bucket(f.Key, userID) < f.RolloutPercent
+}
+
+// bucket maps (key, userID) to 0..99.
+func bucket(key string, userID int64) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key + ":" + strconv.FormatInt(userID, 10)))
+	return int(h.Sum32() % 100)
+}
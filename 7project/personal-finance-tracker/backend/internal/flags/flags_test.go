@@ -0,0 +1,82 @@
+// backend/internal/flags/flags_test.go
+//
+// Purpose:
+//   Check flag evaluation precedence, rollout stability, and snapshot caching.
+
+package flags
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pft/internal/repo"
+)
+
+type fakeLoader struct {
+	flags []repo.Flag
+	err   error
+	calls int
+}
+
+func (f *fakeLoader) List(context.Context) ([]repo.Flag, error) {
+	f.calls++
+	return f.flags, f.err
+}
+
+func TestEvaluate_Precedence(t *testing.T) {
+	f := repo.Flag{Key: "x", Enabled: true, Overrides: map[int64]bool{1: false, 2: true}}
+	if Evaluate(f, 1) || !Evaluate(f, 2) || !Evaluate(f, 3) {
+		t.Fatal("override should win over the global switch")
+	}
+	f.Enabled = false
+	if Evaluate(f, 3) {
+		t.Fatal("disabled flag without rollout should be off")
+	}
+}
+
+func TestEvaluate_RolloutIsStableAndMonotonic(t *testing.T) {
+	at := func(p int) map[int64]bool {
+		on := map[int64]bool{}
+		for id := int64(1); id <= 1000; id++ {
+			if Evaluate(repo.Flag{Key: "envelopes", RolloutPercent: p}, id) {
+				on[id] = true
+			}
+		}
+		return on
+	}
+	ten, fifty := at(10), at(50)
+	if len(ten) < 50 || len(ten) > 150 {
+		t.Fatalf("10%% rollout reached %d of 1000 users", len(ten))
+	}
+	for id := range ten {
+		if !fifty[id] {
+			t.Fatalf("user %d lost the feature when the rollout grew", id)
+		}
+	}
+	if len(at(100)) != 1000 || len(at(0)) != 0 {
+		t.Fatal("0% and 100% should be exact")
+	}
+}
+
+func TestSet_CachesAndKeepsStaleOnError(t *testing.T) {
+	l := &fakeLoader{flags: []repo.Flag{{Key: "a", Enabled: true}}}
+	s := &Set{Store: l, TTL: time.Hour}
+	ctx := context.Background()
+	if !s.Enabled(ctx, "a", 1) || s.Enabled(ctx, "missing", 1) {
+		t.Fatal("unexpected evaluation")
+	}
+	_ = s.ForUser(ctx, 1)
+	if l.calls != 1 {
+		t.Fatalf("expected one load, got %d", l.calls)
+	}
+	s.Invalidate()
+	l.err = errors.New("db down")
+	if !s.Enabled(ctx, "a", 1) {
+		t.Fatal("a failed reload should keep the previous snapshot")
+	}
+	if l.calls != 2 {
+		t.Fatalf("expected a reload after Invalidate, got %d loads", l.calls)
+	}
+}
@@ -0,0 +1,145 @@
+// backend/internal/gql/gql.go
+
+// Package gql serves a read-only GraphQL API over the repo layer so clients can
+// fetch a whole dashboard (transactions, categories, budgets, summary) in one request.
+package gql
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+var (
+	// errInvalidArgument is reported for malformed dates, months, IDs or currency codes.
+	errInvalidArgument = errors.New("invalid_argument")
+	// errRatesUnavailable is reported when no stored exchange rates cover a conversion.
+	errRatesUnavailable = errors.New("rates_unavailable")
+)
+
+// NewSchema parses the schema and binds it to resolvers backed by store.
+// Query depth is capped to keep nested selections cheap.
+func NewSchema(store *repo.Store) *graphql.Schema {
+	return graphql.MustParseSchema(schemaSDL, &Resolver{store: store}, graphql.MaxDepth(6))
+}
+
+type ctxKey struct{}
+
+// request carries the caller and per-request caches through resolver calls.
+type request struct {
+	userID int64
+
+	once       sync.Once
+	categories map[int64]*repo.Category
+	catErr     error
+}
+
+// WithUser scopes ctx to the authenticated user; every resolver reads data for that user only.
+func WithUser(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &request{userID: userID})
+}
+
+func reqFrom(ctx context.Context) *request {
+	r, _ := ctx.Value(ctxKey{}).(*request)
+	if r == nil {
+		panic("gql: missing user in context")
+	}
+	return r
+}
+
+// category resolves a category by id, loading all of the user's categories once per
+// request so lists of transactions or budgets do not issue one query per row.
+// Soft-deleted categories are included: past transactions still refer to them.
+func category(ctx context.Context, store *repo.Store, id *int64) (*CategoryResolver, error) {
+	if id == nil {
+		return nil, nil
+	}
+	r := reqFrom(ctx)
+	r.once.Do(func() {
+		list, err := store.CategoryRepo().List(ctx, r.userID)
+		if err != nil {
+			r.catErr = err
+			return
+		}
+		deleted, err := store.CategoryRepo().ListDeleted(ctx, r.userID)
+		if err != nil {
+			r.catErr = err
+			return
+		}
+		list = append(list, deleted...)
+		r.categories = make(map[int64]*repo.Category, len(list))
+		for i := range list {
+			r.categories[list[i].ID] = &list[i]
+		}
+	})
+	if r.catErr != nil {
+		return nil, r.catErr
+	}
+	if c := r.categories[*id]; c != nil {
+		return &CategoryResolver{c}, nil
+	}
+	return nil, nil
+}
+
+// conversion returns the conversion of amounts from the user's base currency into
+// display, checked like the REST parameter display_currency; none when display is unset.
+func conversion(ctx context.Context, store *repo.Store, display *string) (repo.Conversion, error) {
+	if display == nil || *display == "" {
+		return repo.Conversion{}, nil
+	}
+	code := strings.ToUpper(*display)
+	if _, ok := repo.NewConversion(code, code); !ok {
+		return repo.Conversion{}, errInvalidArgument
+	}
+	prefs, err := store.UserRepo().GetPreferences(ctx, reqFrom(ctx).userID)
+	if err != nil || prefs == nil {
+		return repo.Conversion{}, err
+	}
+	conv, ok := repo.NewConversion(prefs.BaseCurrency, code)
+	if !ok {
+		return repo.Conversion{}, errInvalidArgument
+	}
+	if !conv.Active() {
+		return conv, nil
+	}
+	// fx_rate falls back to the nearest stored day, so one day covering the pair is enough.
+	rates, err := store.RateRepo().Rates(ctx, code, time.Now().UTC())
+	if err != nil {
+		return repo.Conversion{}, err
+	}
+	if rates == nil || rates.Rates[prefs.BaseCurrency] <= 0 {
+		return repo.Conversion{}, errRatesUnavailable
+	}
+	return conv, nil
+}
+
+func id(v int64) graphql.ID { return graphql.ID(strconv.FormatInt(v, 10)) }
+
+func parseDate(s *string) (*time.Time, error) {
+	if s == nil || *s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", *s)
+	if err != nil {
+		return nil, errInvalidArgument
+	}
+	return &t, nil
+}
+
+func parseMonth(s string) error {
+	if _, err := time.Parse("2006-01", s); err != nil {
+		return errInvalidArgument
+	}
+	return nil
+}
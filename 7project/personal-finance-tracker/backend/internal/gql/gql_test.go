@@ -0,0 +1,35 @@
+// backend/internal/gql/gql_test.go
+//
+// Purpose:
+//   Verify the schema binds to the resolvers (MustParseSchema checks every field has a
+//   matching method) and that malformed arguments are rejected before any query runs.
+
+package gql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSchema_ValidatesArguments(t *testing.T) {
+	schema := NewSchema(nil) // argument errors are reported before the store is touched
+	ctx := WithUser(context.Background(), 1)
+
+	for _, q := range []string{
+		`{ summary(month: "2025-13") { month } }`,
+		`{ budgets(month: "January") { id } }`,
+		`{ transactions(from: "01/02/2025") { id } }`,
+		`{ transactions(type: "transfer") { id } }`,
+		`{ summary(month: "2025-01", displayCurrency: "euro") { month } }`,
+	} {
+		res := schema.Exec(ctx, q, "", nil)
+		if len(res.Errors) != 1 || res.Errors[0].Message != errInvalidArgument.Error() {
+			t.Errorf("%s: expected invalid_argument, got %v", q, res.Errors)
+		}
+	}
+
+	res := schema.Exec(ctx, `{ nope }`, "", nil)
+	if len(res.Errors) == 0 {
+		t.Fatalf("expected validation error for unknown field")
+	}
+}
@@ -0,0 +1,197 @@
+// backend/internal/gql/resolvers.go
+
+package gql
+
+import (
+	"context"
+	"strconv"
+
+	"pft/internal/repo"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// Resolver is the root Query resolver.
+type Resolver struct{ store *repo.Store }
+
+// Me returns the authenticated user.
+func (r *Resolver) Me(ctx context.Context) (*UserResolver, error) {
+	u, err := r.store.UserRepo().GetByID(ctx, reqFrom(ctx).userID)
+	if err != nil {
+		return nil, err
+	}
+	return &UserResolver{u}, nil
+}
+
+// Categories lists the user's categories.
+func (r *Resolver) Categories(ctx context.Context) ([]*CategoryResolver, error) {
+	list, err := r.store.CategoryRepo().List(ctx, reqFrom(ctx).userID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*CategoryResolver, len(list))
+	for i := range list {
+		out[i] = &CategoryResolver{&list[i]}
+	}
+	return out, nil
+}
+
+type transactionsArgs struct {
+	From, To   *string
+	CategoryID *graphql.ID
+	Type       *string
+	Limit      *int32
+	Offset     *int32
+}
+
+// Transactions lists transactions with the same filters and paging as GET /api/transactions.
+func (r *Resolver) Transactions(ctx context.Context, args transactionsArgs) ([]*TransactionResolver, error) {
+	var f repo.TxnListFilter
+	var err error
+	if f.From, err = parseDate(args.From); err != nil {
+		return nil, err
+	}
+	if f.To, err = parseDate(args.To); err != nil {
+		return nil, err
+	}
+	if args.CategoryID != nil {
+		cid, err := strconv.ParseInt(string(*args.CategoryID), 10, 64)
+		if err != nil {
+			return nil, errInvalidArgument
+		}
+		f.CategoryID = &cid
+	}
+	if args.Type != nil {
+		if *args.Type != "income" && *args.Type != "expense" {
+			return nil, errInvalidArgument
+		}
+		f.Type = args.Type
+	}
+	if args.Limit != nil {
+		f.Limit = int(*args.Limit)
+	}
+	if args.Offset != nil {
+		f.Offset = int(*args.Offset)
+	}
+	list, err := r.store.TransactionRepo().List(ctx, reqFrom(ctx).userID, f)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*TransactionResolver, len(list))
+	for i := range list {
+		out[i] = &TransactionResolver{r.store, &list[i]}
+	}
+	return out, nil
+}
+
+// Budgets lists the budgets of a month (YYYY-MM).
+func (r *Resolver) Budgets(ctx context.Context, args struct{ Month string }) ([]*BudgetResolver, error) {
+	if err := parseMonth(args.Month); err != nil {
+		return nil, err
+	}
+	list, err := r.store.BudgetRepo().ListByMonth(ctx, reqFrom(ctx).userID, args.Month)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*BudgetResolver, len(list))
+	for i := range list {
+		out[i] = &BudgetResolver{r.store, &list[i]}
+	}
+	return out, nil
+}
+
+type summaryArgs struct {
+	Month           string
+	DisplayCurrency *string
+}
+
+// Summary returns the dashboard summary of a month (YYYY-MM), converted into
+// displayCurrency when given.
+func (r *Resolver) Summary(ctx context.Context, args summaryArgs) (*SummaryResolver, error) {
+	if err := parseMonth(args.Month); err != nil {
+		return nil, err
+	}
+	conv, err := conversion(ctx, r.store, args.DisplayCurrency)
+	if err != nil {
+		return nil, err
+	}
+	s, err := r.store.DashboardRepo().In(conv).Summary(ctx, reqFrom(ctx).userID, args.Month)
+	if err != nil {
+		return nil, err
+	}
+	return &SummaryResolver{s}, nil
+}
+
+// UserResolver resolves User.
+type UserResolver struct{ u *repo.User }
+
+func (r *UserResolver) ID() graphql.ID { return id(r.u.ID) }
+func (r *UserResolver) Name() string   { return r.u.Name }
+func (r *UserResolver) Email() string  { return r.u.Email }
+
+// CategoryResolver resolves Category.
+type CategoryResolver struct{ c *repo.Category }
+
+func (r *CategoryResolver) ID() graphql.ID      { return id(r.c.ID) }
+func (r *CategoryResolver) Name() string        { return r.c.Name }
+func (r *CategoryResolver) Type() string        { return r.c.Type }
+func (r *CategoryResolver) TaxDeductible() bool { return r.c.TaxDeductible }
+func (r *CategoryResolver) TaxCategory() string { return r.c.TaxCategory }
+
+// TransactionResolver resolves Transaction.
+type TransactionResolver struct {
+	store *repo.Store
+	t     *repo.Transaction
+}
+
+func (r *TransactionResolver) ID() graphql.ID      { return id(r.t.ID) }
+func (r *TransactionResolver) Amount() float64     { return r.t.Amount }
+func (r *TransactionResolver) Type() string        { return r.t.Type }
+func (r *TransactionResolver) Date() string        { return r.t.Date.Format("2006-01-02") }
+func (r *TransactionResolver) Description() string { return r.t.Description }
+func (r *TransactionResolver) Reimbursable() bool  { return r.t.Reimbursable }
+func (r *TransactionResolver) Reimbursed() bool    { return r.t.Reimbursed }
+
+func (r *TransactionResolver) Category(ctx context.Context) (*CategoryResolver, error) {
+	return category(ctx, r.store, r.t.CategoryID)
+}
+
+// BudgetResolver resolves Budget.
+type BudgetResolver struct {
+	store *repo.Store
+	b     *repo.Budget
+}
+
+func (r *BudgetResolver) ID() graphql.ID       { return id(r.b.ID) }
+func (r *BudgetResolver) Month() string        { return r.b.PeriodMonth }
+func (r *BudgetResolver) LimitAmount() float64 { return r.b.LimitAmount }
+
+func (r *BudgetResolver) Category(ctx context.Context) (*CategoryResolver, error) {
+	return category(ctx, r.store, r.b.CategoryID)
+}
+
+// SummaryResolver resolves MonthSummary.
+type SummaryResolver struct{ s *repo.MonthSummary }
+
+func (r *SummaryResolver) Month() string                 { return r.s.Month }
+func (r *SummaryResolver) IncomeTotal() float64          { return r.s.IncomeTotal }
+func (r *SummaryResolver) ExpenseTotal() float64         { return r.s.ExpenseTotal }
+func (r *SummaryResolver) SavingsRate() *float64         { return r.s.SavingsRate }
+func (r *SummaryResolver) SavingsRateAvg3m() *float64    { return r.s.SavingsRateAvg3 }
+func (r *SummaryResolver) SavingsRateAvg6m() *float64    { return r.s.SavingsRateAvg6 }
+func (r *SummaryResolver) SubscriptionsMonthly() float64 { return r.s.SubscriptionsMonthly }
+
+func (r *SummaryResolver) BudgetAdherence() *AdherenceResolver {
+	if r.s.BudgetAdherence == nil {
+		return nil
+	}
+	return &AdherenceResolver{r.s.BudgetAdherence}
+}
+
+// AdherenceResolver resolves BudgetAdherence.
+type AdherenceResolver struct{ a *repo.BudgetAdherence }
+
+func (r *AdherenceResolver) Score() float64        { return r.a.Score }
+func (r *AdherenceResolver) Respected() int32      { return int32(r.a.Respected) }
+func (r *AdherenceResolver) Total() int32          { return int32(r.a.Total) }
+func (r *AdherenceResolver) AvgMarginPct() float64 { return r.a.AvgMarginPct }
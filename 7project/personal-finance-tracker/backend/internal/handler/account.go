@@ -0,0 +1,145 @@
+// backend/internal/handler/account.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// accountReq is the payload for creating or updating an account.
+// - OpeningBalance: balance before the first transaction booked to the account; may be negative (cards, overdrafts)
+type accountReq struct {
+	Name           string  `json:"name" binding:"required,min=1,max=100"`
+	OpeningBalance float64 `json:"opening_balance"`
+}
+
+// ListAccounts returns the user's accounts.
+func (api *API) ListAccounts(c *gin.Context) {
+	out, err := api.Repos.AccountRepo().List(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateAccount adds an account transactions can be booked to.
+func (api *API) CreateAccount(c *gin.Context) {
+	var req accountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	out, err := api.Repos.AccountRepo().Create(c.Request.Context(),
+		&repo.Account{UserID: MustUserID(c), Name: req.Name, OpeningBalance: req.OpeningBalance})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// UpdateAccount renames an account or corrects its opening balance.
+func (api *API) UpdateAccount(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req accountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	out, err := api.Repos.AccountRepo().Update(c.Request.Context(), MustUserID(c), id,
+		&repo.Account{Name: req.Name, OpeningBalance: req.OpeningBalance})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteAccount removes an account. Its transactions are kept without an account.
+func (api *API) DeleteAccount(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.AccountRepo().Delete(c.Request.Context(), MustUserID(c), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AccountStatement returns the statement of account :id for ?from= to ?to=
+// (YYYY-MM-DD, inclusive): the opening balance, the transactions booked to the account
+// in date order with the running balance after each, and the closing balance.
+// to defaults to today and from to the first day of to's month. Optional
+// "display_currency" converts amounts (see displayConversion).
+func (api *API) AccountStatement(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if s := c.Query("to"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			problem(c, http.StatusBadRequest, "invalid_date")
+			return
+		}
+		to = d
+	}
+	from := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if s := c.Query("from"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			problem(c, http.StatusBadRequest, "invalid_date")
+			return
+		}
+		from = d
+	}
+	if from.After(to) {
+		problemDetail(c, http.StatusBadRequest, "invalid_range", "from must not be after to.")
+		return
+	}
+	conv, ok := api.displayConversion(c)
+	if !ok {
+		return
+	}
+	out, err := api.Repos.AccountRepo().In(conv).Statement(c.Request.Context(), MustUserID(c), id, from, to)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// checkAccount reports whether accountID (when set) names one of the user's accounts,
+// writing a 400 response when it does not.
+func (api *API) checkAccount(c *gin.Context, userID int64, accountID *int64) bool {
+	if accountID == nil {
+		return true
+	}
+	a, err := api.Repos.AccountRepo().Get(c.Request.Context(), userID, *accountID)
+	if err != nil {
+		fail(c, err)
+		return false
+	}
+	if a == nil {
+		problem(c, http.StatusBadRequest, "invalid_account")
+		return false
+	}
+	return true
+}
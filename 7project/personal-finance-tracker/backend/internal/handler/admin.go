@@ -0,0 +1,139 @@
+// backend/internal/handler/admin.go
+
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pft/internal/backup"
+	"pft/internal/cache"
+	"pft/internal/flags"
+	"pft/internal/jobs"
+	"pft/internal/mailer"
+	"pft/internal/platform"
+	"pft/internal/rates"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Admin serves operator endpoints for self-hosters. They are not tied to a user
+// account and are guarded by AdminAuth instead of the JWT middleware.
+// - Repos: data access layer
+// - Backups: where backup archives are stored
+// - Flags: the API's flag cache, refreshed when flags are edited
+// - Maintenance: this instance's maintenance switch
+// - Mailer: outgoing email
+// - Scheduler: this instance's periodic job schedules
+// - Rates: the exchange rate refresh, for backfills; nil unless a job worker runs it
+// - Cache: the response cache, cleared for a user whose data is restored; nil if disabled
+type Admin struct {
+	Repos       *repo.Store
+	Backups     *backup.Dir
+	Flags       *flags.Set
+	Maintenance *MaintenanceSwitch
+	Mailer      *mailer.Mailer
+	Scheduler   *jobs.Scheduler
+	Rates       *rates.Job
+	Cache       cache.Store
+}
+
+// AdminAuth requires "Authorization: Bearer <token>" with the configured admin token.
+// Routes using it should only be registered when a token is set.
+func AdminAuth(token string) gin.HandlerFunc {
+	want := []byte(token)
+	return func(c *gin.Context) {
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(got)), want) != 1 {
+			problemDetail(c, http.StatusUnauthorized, "unauthorized", "The admin token is missing or wrong.")
+			return
+		}
+		c.Next()
+	}
+}
+
+// CreateBackup writes a new archive of every user, or of one user with ?user_id=,
+// and answers 201 with its backup.Info.
+func (a *Admin) CreateBackup(c *gin.Context) {
+	var ids []int64
+	if s := c.Query("user_id"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			problem(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		ids = append(ids, id)
+	}
+	ctx := c.Request.Context()
+	migration, err := platform.MigrationVersion(ctx, a.Repos.Pool)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	info, err := a.Backups.Create(ctx, a.Repos.BackupRepo(), migration, ids...)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, info)
+}
+
+// ListBackups returns the stored archives, newest first.
+func (a *Admin) ListBackups(c *gin.Context) {
+	out, err := a.Backups.List(c.Request.Context())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DownloadBackup streams an archive as application/gzip for off-host storage.
+func (a *Admin) DownloadBackup(c *gin.Context) {
+	rc, info, err := a.Backups.Open(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	defer rc.Close()
+	c.DataFromReader(http.StatusOK, info.Size, "application/gzip", rc, map[string]string{
+		"Content-Disposition": `attachment; filename="` + info.Name + `"`,
+	})
+}
+
+// restoreRequest selects the user to restore from an archive.
+type restoreRequest struct {
+	UserID int64 `json:"user_id" binding:"required"`
+}
+
+// RestoreBackup replaces one user's data with their copy in a stored archive.
+// Archives from another host can be restored after copying them into the backup
+// store. Answers 404 if the archive or the user in it does not exist, and 409 if
+// another account now uses the archived email address.
+func (a *Admin) RestoreBackup(c *gin.Context) {
+	var req restoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	archive, err := a.Backups.Load(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	dump := archive.User(req.UserID)
+	if dump == nil {
+		problemDetail(c, http.StatusNotFound, "not_found", "The archive does not contain this user.")
+		return
+	}
+	counts, err := a.Repos.BackupRepo().Restore(c.Request.Context(), dump)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	Invalidator(a.Cache)(c.Request.Context(), req.UserID)
+	c.JSON(http.StatusOK, gin.H{"user_id": req.UserID, "rows": counts})
+}
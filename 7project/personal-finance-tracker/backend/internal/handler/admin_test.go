@@ -0,0 +1,71 @@
+// backend/internal/handler/admin_test.go
+//
+// Purpose:
+//   Verify that admin routes require the exact admin token, and that rate backfills
+//   are refused for bad ranges and providers without history.
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pft/internal/handler"
+	"pft/internal/rates"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/admin/x", handler.AdminAuth("0123456789abcdef"), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	for auth, want := range map[string]int{
+		"":                         http.StatusUnauthorized,
+		"Bearer wrong":             http.StatusUnauthorized,
+		"Bearer 0123456789abcdef0": http.StatusUnauthorized,
+		"Basic 0123456789abcdef":   http.StatusUnauthorized,
+		"Bearer 0123456789abcdef":  http.StatusNoContent,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/x", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("Authorization %q: got %d, want %d", auth, w.Code, want)
+		}
+	}
+}
+
+func TestBackfillRates_Refused(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	manual := &rates.Job{Provider: &rates.Manual{}, Base: "EUR"}
+	for _, tc := range []struct {
+		job  *rates.Job
+		body string
+		code string
+	}{
+		{manual, `{}`, "invalid"},
+		{manual, `{"from":"2024-13-01"}`, "invalid_date"},
+		{manual, `{"from":"2024-03-01","to":"2024-02-01"}`, "invalid_range"},
+		{manual, `{"from":"2024-03-01","to":"2999-01-01"}`, "invalid_range"},
+		{nil, `{"from":"2024-01-01"}`, "backfill_unavailable"},
+		{manual, `{"from":"2024-01-01"}`, "history_unsupported"},
+	} {
+		adm := &handler.Admin{Rates: tc.job} // refused before the job queue is used
+		r := gin.New()
+		r.POST("/api/admin/rates/backfill", adm.BackfillRates)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/rates/backfill", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		if w.Code < 400 || !strings.Contains(w.Body.String(), tc.code) {
+			t.Errorf("%s: got %d %s, want %s", tc.body, w.Code, w.Body, tc.code)
+		}
+	}
+}
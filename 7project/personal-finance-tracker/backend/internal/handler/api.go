@@ -2,9 +2,15 @@
 package handler
 
 import (
+	"context"
 	"net/http"
+	"runtime"
 	"strconv"
+	"time"
 
+	"pft/internal/blob"
+	"pft/internal/flags"
+	"pft/internal/platform"
 	"pft/internal/repo"
 
 	"github.com/gin-gonic/gin"
@@ -13,9 +19,17 @@ import (
 // API groups HTTP handlers with their required dependencies.
 // - Repos: data access layer for persistence operations
 // - JWTSecret: symmetric key used by middleware/handlers for JWT validation or signing
+// - Flags: feature flags for gradual rollouts; nil treats every flag as off
+// - TelegramBot: username of the Telegram bot users link chats to; empty disables linking
+// - Blobs: file storage for exports
+// - IngestDomain: mail domain of receipt ingest addresses; empty disables email-in
 type API struct {
-	Repos     *repo.Store
-	JWTSecret string
+	Repos        *repo.Store
+	JWTSecret    string
+	Flags        *flags.Set
+	TelegramBot  string
+	Blobs        blob.Store
+	IngestDomain string
 }
 
 // New constructs an API instance with injected dependencies.
@@ -26,12 +40,84 @@ func New(repos *repo.Store, jwtSecret string) *API {
 
 // --- existing handlers already present elsewhere ---
 
-// Healthz exposes a simple health endpoint intended for readiness/liveness probes.
-// Returns HTTP 200 with a minimal JSON payload when the process is responsive.
+// Healthz is the liveness probe. It always answers 200 while the process is responsive,
+// and reports enough detail for monitoring to tell "up but unhealthy" apart:
+// - version/commit/uptime_seconds: build and process information
+// - database: reachability and ping latency
+// - migration: latest applied migration file
+// - queue: background job counts (see repo.JobStats)
+// "status" is "degraded" when a dependency check fails; Readyz remains the gate for traffic.
 func (api *API) Healthz(c *gin.Context) {
+	body := gin.H{
+		"ok":             true,
+		"status":         "ok",
+		"version":        platform.Version,
+		"commit":         platform.Commit,
+		"uptime_seconds": int64(time.Since(platform.StartedAt).Seconds()),
+	}
+	if api.Repos == nil || api.Repos.Pool == nil {
+		c.JSON(http.StatusOK, body)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+	degrade := func(err error) gin.H {
+		body["status"] = "degraded"
+		return gin.H{"ok": false, "error": err.Error()}
+	}
+
+	start := time.Now()
+	if err := api.Repos.Pool.Ping(ctx); err != nil {
+		body["database"] = degrade(err)
+		c.JSON(http.StatusOK, body)
+		return
+	}
+	body["database"] = gin.H{"ok": true, "latency_ms": float64(time.Since(start).Microseconds()) / 1000}
+
+	if v, err := platform.MigrationVersion(ctx, api.Repos.Pool); err != nil {
+		body["migration"] = degrade(err)
+	} else {
+		body["migration"] = v
+	}
+	if s, err := api.Repos.JobRepo().Stats(ctx); err != nil {
+		body["queue"] = degrade(err)
+	} else {
+		body["queue"] = s
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// Readyz reports whether the API can serve traffic: it pings the database and
+// answers 503 {"ok": false, "error": "db_unavailable"} when Postgres is unreachable.
+func (api *API) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+	if err := api.Repos.Pool.Ping(ctx); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"ok": false, "error": "db_unavailable"})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"ok": true})
 }
 
+// Version reports the deployed build, without authentication, so clients and support
+// can confirm what is running:
+// - version: semantic version ("1.4.0"), or "dev" for unstamped builds
+// - commit/build_date: git revision and build time (RFC 3339), or "unknown"
+// - go_version: toolchain the binary was built with
+// Last-Modified is the build time, when known.
+func (api *API) Version(c *gin.Context) {
+	if t, err := time.Parse(time.RFC3339, platform.BuildDate); err == nil && notModified(c, t) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"version":    platform.Version,
+		"commit":     platform.Commit,
+		"build_date": platform.BuildDate,
+		"go_version": runtime.Version(),
+	})
+}
+
 // Register, Login, Me ... (present in other files)
 
 // --- helpers ---
@@ -0,0 +1,135 @@
+// backend/internal/handler/apikey.go
+
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiKeyPrefix starts every API key, so leaked keys are easy to recognize in scans.
+const apiKeyPrefix = "pft_"
+
+// createAPIKeyReq names a new API key after the integration using it.
+// - Scopes: what the key may do (see Scopes); omitted grants read-only access (ReadScopes)
+type createAPIKeyReq struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"omitempty,dive,oneof=transactions:read transactions:write budgets:read budgets:write categories:read categories:write reports:read"`
+}
+
+// randomToken returns prefix followed by 160 random bits in lowercase base32.
+func randomToken(prefix string) (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)), nil
+}
+
+// ListAPIKeys returns the user's API keys without the keys themselves.
+func (api *API) ListAPIKeys(c *gin.Context) {
+	out, err := api.Repos.APIKeyRepo().List(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateAPIKey creates an API key and answers 201 with it under "key". This is the
+// only time the key is shown; only its hash is stored.
+func (api *API) CreateAPIKey(c *gin.Context) {
+	var req createAPIKeyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	key, err := randomToken(apiKeyPrefix)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = ReadScopes
+	}
+	slices.Sort(scopes)
+	k, err := api.Repos.APIKeyRepo().Create(c.Request.Context(), MustUserID(c), req.Name, key, slices.Compact(scopes))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, struct {
+		*repo.APIKey
+		Key string `json:"key"`
+	}{k, key})
+}
+
+// DeleteAPIKey revokes API key :id; requests using it fail from then on.
+func (api *API) DeleteAPIKey(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.APIKeyRepo().Delete(c.Request.Context(), MustUserID(c), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// APIKeyMiddleware authenticates requests by API key, sent in the X-API-Key header or
+// as ?api_key= (for tools that cannot set headers), and scopes them to the key's user
+// like JWTMiddleware. Aborts with 401 when the key is missing or unknown and with 403
+// when the route is not covered by the key's scopes (see RouteScope).
+func APIKeyMiddleware(keys *repo.APIKeyRepo) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-API-Key")
+		if key == "" {
+			key = c.Query("api_key")
+		}
+		if !strings.HasPrefix(key, apiKeyPrefix) {
+			problemDetail(c, http.StatusUnauthorized, "unauthorized", "An API key is required in the X-API-Key header.")
+			return
+		}
+		k, err := keys.Authenticate(c.Request.Context(), key)
+		if err != nil {
+			fail(c, err)
+			return
+		}
+		if k == nil {
+			problemDetail(c, http.StatusUnauthorized, "unauthorized", "The API key is invalid or was revoked.")
+			return
+		}
+		if !checkScope(c, k.Scopes) {
+			return
+		}
+		c.Set("uid", k.UserID)
+		c.Set("scopes", k.Scopes)
+		c.Request = c.Request.WithContext(repo.WithTenant(c.Request.Context(), k.UserID))
+		c.Next()
+	}
+}
+
+// KeyOrJWT authenticates requests carrying an API key (X-API-Key or ?api_key=) with
+// keyAuth and all others with jwtAuth, so routes covered by API key scopes also serve
+// third-party tools.
+func KeyOrJWT(keyAuth, jwtAuth gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("X-API-Key") != "" || c.Query("api_key") != "" {
+			keyAuth(c)
+			return
+		}
+		jwtAuth(c)
+	}
+}
@@ -0,0 +1,113 @@
+// backend/internal/handler/audit.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Audit attributes the database writes of a request to actor, or to the authenticated
+// user ("user:<id>") when actor is empty, together with the request ID. Database
+// triggers record every change in the audit log (migration 029); this only labels them.
+// Read requests are left alone so their connections need no extra setup.
+// With an empty actor it must run after the JWT middleware.
+func Audit(actor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		name := actor
+		if name == "" {
+			name = "user:" + strconv.FormatInt(MustUserID(c), 10)
+		}
+		c.Request = c.Request.WithContext(repo.WithActor(c.Request.Context(), name, c.GetString("request_id")))
+		c.Next()
+	}
+}
+
+// auditFilter reads the listing filters shared by the user and admin views:
+// entity, entity_id, action, actor, since/until (RFC 3339 or YYYY-MM-DD) and limit/offset.
+// Writes a 400 problem and returns false for malformed values.
+func auditFilter(c *gin.Context) (repo.AuditFilter, bool) {
+	f := repo.AuditFilter{
+		Entity:   c.Query("entity"),
+		EntityID: c.Query("entity_id"),
+		Action:   c.Query("action"),
+		Actor:    c.Query("actor"),
+		Limit:    asInt(c.Query("limit"), 100),
+		Offset:   asInt(c.Query("offset"), 0),
+	}
+	switch f.Action {
+	case "", "create", "update", "delete":
+	default:
+		problemDetail(c, http.StatusBadRequest, "invalid_action", "action must be one of: create, update, delete.")
+		return f, false
+	}
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{{"since", &f.Since}, {"until", &f.Until}} {
+		s := c.Query(p.name)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", s); err != nil {
+				problemDetail(c, http.StatusBadRequest, "invalid_date",
+					p.name+" must be an RFC 3339 timestamp or a YYYY-MM-DD date.")
+				return f, false
+			}
+		}
+		*p.dst = &t
+	}
+	return f, true
+}
+
+// ListAudit returns the audit trail of the authenticated user's data, newest first.
+// Optional filters: entity, entity_id, action, actor, since, until, limit, offset.
+func (api *API) ListAudit(c *gin.Context) {
+	userID := MustUserID(c)
+	f, ok := auditFilter(c)
+	if !ok {
+		return
+	}
+	f.UserID = &userID
+	out, err := api.Repos.AuditRepo().List(c.Request.Context(), f)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ListAudit returns the audit log across all users, newest first. Accepts the same
+// filters as the user view plus user_id.
+func (a *Admin) ListAudit(c *gin.Context) {
+	f, ok := auditFilter(c)
+	if !ok {
+		return
+	}
+	if s := c.Query("user_id"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			problem(c, http.StatusBadRequest, "invalid_user_id")
+			return
+		}
+		f.UserID = &id
+	}
+	out, err := a.Repos.AuditRepo().List(c.Request.Context(), f)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
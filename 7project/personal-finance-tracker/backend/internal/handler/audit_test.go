@@ -0,0 +1,45 @@
+// backend/internal/handler/audit_test.go
+//
+// Purpose:
+//   Verify that audit log listings reject malformed filters before querying.
+
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestListAudit_InvalidFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api := handler.New(nil, "s") // validation fails before the repository is used
+	r := gin.New()
+	r.GET("/api/audit", func(c *gin.Context) { c.Set("uid", int64(1)) }, api.ListAudit)
+
+	cases := map[string]string{
+		"/api/audit?action=rename":          "invalid_action",
+		"/api/audit?since=yesterday":        "invalid_date",
+		"/api/audit?until=2025-13-01":       "invalid_date",
+		"/api/audit?since=2025-01-02T10:00": "invalid_date",
+	}
+	for url, code := range cases {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d: %s", url, rec.Code, rec.Body)
+		}
+		var p map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+			t.Fatal(err)
+		}
+		if p["code"] != code {
+			t.Errorf("%s: code %v, want %s", url, p["code"], code)
+		}
+	}
+}
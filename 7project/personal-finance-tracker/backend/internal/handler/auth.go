@@ -31,14 +31,14 @@ type registerReq struct {
 func (api *API) Register(c *gin.Context) {
 	var req registerReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"})
+		invalidRequest(c, err)
 		return
 	}
 
 	// Hash the plaintext password; bcrypt cost 12 balances security and performance.
 	hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), 12)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 
@@ -49,17 +49,17 @@ func (api *API) Register(c *gin.Context) {
 		// Handle PostgreSQL unique constraint violation (SQLSTATE 23505).
 		var pgerr *pgconn.PgError
 		if errors.As(err, &pgerr) && pgerr.Code == "23505" {
-			c.JSON(http.StatusConflict, gin.H{"error": "email_in_use"})
+			problem(c, http.StatusConflict, "email_in_use")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 
 	// Issue a JWT bound to the created user ID with a 24h TTL.
 	tok, err := makeToken(api.JWTSecret, u.ID, 24*time.Hour)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"id": u.ID, "token": tok})
@@ -80,25 +80,25 @@ type loginReq struct {
 func (api *API) Login(c *gin.Context) {
 	var req loginReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"})
+		invalidRequest(c, err)
 		return
 	}
 
 	u, err := api.Repos.UserRepo().GetByEmail(c.Request.Context(), strings.ToLower(req.Email))
 	if err != nil || u == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_credentials"})
+		problem(c, http.StatusUnauthorized, "invalid_credentials")
 		return
 	}
 	// CompareHashAndPassword returns nil on success; any error indicates mismatch or invalid hash.
 	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(req.Password)) != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_credentials"})
+		problem(c, http.StatusUnauthorized, "invalid_credentials")
 		return
 	}
 
 	// Issue a JWT with a 24h TTL for the authenticated user.
 	tok, err := makeToken(api.JWTSecret, u.ID, 24*time.Hour)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"id": u.ID, "token": tok})
@@ -0,0 +1,150 @@
+// backend/internal/handler/batch.go
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errBatchFailed rolls back an atomic batch after a sub-request failed.
+var errBatchFailed = errors.New("batch sub-request failed")
+
+// batchReq is a list of at most 20 API calls to make in order.
+// - Atomic: apply every write or none; the batch stops at the first failing sub-request
+type batchReq struct {
+	Requests []batchItem `json:"requests" binding:"required,min=1,max=20,dive"`
+	Atomic   bool        `json:"atomic"`
+}
+
+// batchItem is one API call, as it would be made on its own.
+// - Path: an /api/ path with any query string, e.g. "/api/transactions?limit=10"
+// - Body: JSON request body, if any
+type batchItem struct {
+	Method string          `json:"method" binding:"required,oneof=GET POST PUT PATCH DELETE"`
+	Path   string          `json:"path" binding:"required,startswith=/api/"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// batchResult is the response to one batchItem. Body is the response's JSON, or its
+// text when it is not JSON; Status is 424 for sub-requests skipped after an atomic
+// batch failed.
+type batchResult struct {
+	Status int `json:"status"`
+	Body   any `json:"body,omitempty"`
+}
+
+// Batch serves POST /api/batch: it runs each sub-request through engine in order,
+// authenticated with the batch request's own credentials, so every route, scope check
+// and rate limit applies as if it were sent separately, and answers 200 with a result
+// per sub-request. Without "atomic" every sub-request runs whatever the others' status.
+// With "atomic" they share one database transaction of store (see
+// repo.Store.WithContextTx): the batch stops at the first response of 400 or above
+// and rolls back, and "committed" reports the outcome. Batches cannot be nested.
+func Batch(engine http.Handler, store *repo.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req batchReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			invalidRequest(c, err)
+			return
+		}
+		for _, it := range req.Requests {
+			if p, _, _ := strings.Cut(it.Path, "?"); p == c.Request.URL.Path {
+				problemDetail(c, http.StatusBadRequest, "invalid_batch", "Batches cannot contain batches.")
+				return
+			}
+		}
+		results := make([]batchResult, 0, len(req.Requests))
+		run := func(ctx context.Context) error {
+			for i, it := range req.Requests {
+				res := serveBatchItem(ctx, engine, c.Request, c.GetString("request_id")+"."+strconv.Itoa(i+1), it)
+				results = append(results, res)
+				if req.Atomic && res.Status >= 400 {
+					return errBatchFailed
+				}
+			}
+			return nil
+		}
+		if !req.Atomic {
+			_ = run(c.Request.Context())
+			c.JSON(http.StatusOK, gin.H{"results": results})
+			return
+		}
+		err := store.WithContextTx(c.Request.Context(), run)
+		if err != nil && !errors.Is(err, errBatchFailed) {
+			fail(c, err)
+			return
+		}
+		for len(results) < len(req.Requests) {
+			results = append(results, batchResult{Status: http.StatusFailedDependency})
+		}
+		c.JSON(http.StatusOK, gin.H{"results": results, "committed": err == nil})
+	}
+}
+
+// serveBatchItem makes it as a request of its own carrying outer's credentials.
+func serveBatchItem(ctx context.Context, engine http.Handler, outer *http.Request, requestID string, it batchItem) batchResult {
+	sub, err := http.NewRequestWithContext(ctx, it.Method, it.Path, bytes.NewReader(it.Body))
+	if err != nil {
+		return batchResult{Status: http.StatusBadRequest}
+	}
+	for _, h := range []string{"Authorization", "X-API-Key", "Accept-Language"} {
+		if v := outer.Header.Get(h); v != "" {
+			sub.Header.Set(h, v)
+		}
+	}
+	if len(it.Body) > 0 {
+		sub.Header.Set("Content-Type", "application/json")
+	}
+	sub.Header.Set(RequestIDHeader, requestID)
+	sub.RemoteAddr = outer.RemoteAddr
+
+	w := &batchWriter{header: http.Header{}}
+	engine.ServeHTTP(w, sub)
+	res := batchResult{Status: w.status}
+	if res.Status == 0 {
+		res.Status = http.StatusOK
+	}
+	if body := w.body.Bytes(); json.Valid(body) {
+		res.Body = json.RawMessage(body)
+	} else if len(body) > 0 {
+		res.Body = string(body)
+	}
+	return res
+}
+
+// batchWriter buffers a sub-request's response.
+type batchWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *batchWriter) Header() http.Header { return w.header }
+
+func (w *batchWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *batchWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// Flush is a no-op, so streaming handlers work inside a batch.
+func (w *batchWriter) Flush() {}
+
+var _ http.Flusher = (*batchWriter)(nil)
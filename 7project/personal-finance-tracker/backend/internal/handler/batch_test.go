@@ -0,0 +1,83 @@
+// backend/internal/handler/batch_test.go
+//
+// Purpose:
+//   Verify that a batch runs its sub-requests in order through the router with the
+//   batch's credentials, reports each status and body, and refuses nested batches.
+
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	var seen []string
+	r.GET("/api/echo", func(c *gin.Context) {
+		seen = append(seen, c.Request.URL.RequestURI()+" "+c.GetHeader("Authorization"))
+		c.JSON(http.StatusOK, gin.H{"q": c.Query("q")})
+	})
+	r.POST("/api/echo", func(c *gin.Context) {
+		var body map[string]any
+		_ = c.ShouldBindJSON(&body)
+		seen = append(seen, "POST "+c.GetHeader("Content-Type"))
+		c.JSON(http.StatusCreated, body)
+	})
+	r.DELETE("/api/echo", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	r.POST("/api/batch", handler.Batch(r, nil))
+
+	do := func(body string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/batch", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer t")
+		r.ServeHTTP(rec, req)
+		var out map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &out)
+		return rec.Code, out
+	}
+
+	code, out := do(`{"requests":[
+		{"method":"GET","path":"/api/echo?q=1"},
+		{"method":"POST","path":"/api/echo","body":{"a":2}},
+		{"method":"GET","path":"/api/missing"},
+		{"method":"DELETE","path":"/api/echo"}]}`)
+	if code != http.StatusOK {
+		t.Fatalf("batch: %d %v", code, out)
+	}
+	results, _ := out["results"].([]any)
+	want := []float64{200, 201, 404, 204}
+	if len(results) != len(want) {
+		t.Fatalf("results = %v", results)
+	}
+	for i, w := range want {
+		if got := results[i].(map[string]any)["status"]; got != w {
+			t.Errorf("result %d: status %v, want %v", i, got, w)
+		}
+	}
+	if body := results[1].(map[string]any)["body"].(map[string]any); body["a"] != float64(2) {
+		t.Errorf("POST body = %v", body)
+	}
+	if len(seen) != 2 || seen[0] != "/api/echo?q=1 Bearer t" || seen[1] != "POST application/json" {
+		t.Errorf("sub-requests = %q", seen)
+	}
+
+	for _, body := range []string{
+		`{"requests":[{"method":"POST","path":"/api/batch?x=1","body":{}}]}`,
+		`{"requests":[{"method":"GET","path":"/metrics"}]}`,
+		`{"requests":[]}`,
+	} {
+		if code, _ := do(body); code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", body, code)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// backend/internal/handler/bill.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// billReq is the payload for creating or updating a bill.
+// - DueDay: 1..31; days beyond a month's end fall on its last day
+// - RemindDays: optional, defaults to 3 days before the due date
+type billReq struct {
+	CategoryID *int64  `json:"category_id"`
+	Name       string  `json:"name" binding:"required,min=1,max=100"`
+	Amount     float64 `json:"amount" binding:"gte=0"`
+	DueDay     int     `json:"due_day" binding:"required,min=1,max=31"`
+	Autopay    bool    `json:"autopay"`
+	RemindDays *int    `json:"remind_days" binding:"omitempty,min=0,max=31"`
+}
+
+// toBill converts the request into a repo.Bill, applying defaults.
+func (req billReq) toBill() *repo.Bill {
+	remind := 3
+	if req.RemindDays != nil {
+		remind = *req.RemindDays
+	}
+	return &repo.Bill{
+		CategoryID: req.CategoryID,
+		Name:       req.Name,
+		Amount:     req.Amount,
+		DueDay:     req.DueDay,
+		Autopay:    req.Autopay,
+		RemindDays: remind,
+	}
+}
+
+// ListBills returns all bills of the authenticated user.
+func (api *API) ListBills(c *gin.Context) {
+	userID := MustUserID(c)
+	out, err := api.Repos.BillRepo().List(c.Request.Context(), userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateBill registers a new monthly bill.
+func (api *API) CreateBill(c *gin.Context) {
+	userID := MustUserID(c)
+	var req billReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	b := req.toBill()
+	b.UserID = userID
+	out, err := api.Repos.BillRepo().Create(c.Request.Context(), b)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// UpdateBill modifies a bill identified by :id. Returns 404 if it does not exist.
+func (api *API) UpdateBill(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req billReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	out, err := api.Repos.BillRepo().Update(c.Request.Context(), userID, id, req.toBill())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteBill removes a bill by ID.
+func (api *API) DeleteBill(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.BillRepo().Delete(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// UpcomingBills lists bill occurrences due in the next "days" days (default 30, max 366),
+// flagging those whose reminder window has started.
+func (api *API) UpcomingBills(c *gin.Context) {
+	userID := MustUserID(c)
+	days := asInt(c.Query("days"), 30)
+	if days < 0 {
+		days = 30
+	}
+	if days > 366 {
+		days = 366
+	}
+	out, err := api.Repos.BillRepo().Upcoming(c.Request.Context(), userID, time.Now().UTC(), days)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
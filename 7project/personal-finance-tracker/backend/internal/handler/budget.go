@@ -14,11 +14,13 @@ import (
 
 // budgetCreateReq models the payload for creating or updating a budget.
 // - CategoryID: optional category scoping (nil means a global/monthly budget)
+// - GroupID: optional category group instead, counting the expenses of all its categories
 // - PeriodMonth: target period in YYYY-MM format
 // - LimitAmount: allowed spending limit for the period/category
 type budgetCreateReq struct {
-	CategoryID  *int64  `json:"category_id"`                     // nullable
-	PeriodMonth string  `json:"period_month" binding:"required"` // YYYY-MM
+	CategoryID  *int64  `json:"category_id"`                                 // nullable
+	GroupID     *int64  `json:"group_id" binding:"excluded_with=CategoryID"` // nullable
+	PeriodMonth string  `json:"period_month" binding:"required"`             // YYYY-MM
 	LimitAmount float64 `json:"limit_amount" binding:"required"`
 }
 
@@ -26,17 +28,27 @@ type budgetCreateReq struct {
 type budgetUpdateReq = budgetCreateReq
 
 // ListBudgets retrieves all budgets for the authenticated user limited to a single month.
-// Requires the "month" query parameter in YYYY-MM format.
+// Requires the "month" query parameter in YYYY-MM format, except with ?deleted=true,
+// which lists the soft-deleted budgets of every month instead.
 func (api *API) ListBudgets(c *gin.Context) {
 	userID := MustUserID(c)
+	if deleted, _ := strconv.ParseBool(c.Query("deleted")); deleted {
+		out, err := api.Repos.BudgetRepo().ListDeleted(c.Request.Context(), userID)
+		if err != nil {
+			fail(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, out)
+		return
+	}
 	month := c.Query("month")
 	if month == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "month_required"})
+		problem(c, http.StatusBadRequest, "month_required")
 		return
 	}
 	out, err := api.Repos.BudgetRepo().ListByMonth(c.Request.Context(), userID, month)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, out)
@@ -49,12 +61,13 @@ func (api *API) CreateBudget(c *gin.Context) {
 	userID := MustUserID(c)
 	var req budgetCreateReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"})
+		invalidRequest(c, err)
 		return
 	}
 	b := &repo.Budget{
 		UserID:      userID,
 		CategoryID:  req.CategoryID,
+		GroupID:     req.GroupID,
 		PeriodMonth: req.PeriodMonth,
 		LimitAmount: req.LimitAmount,
 	}
@@ -63,10 +76,10 @@ func (api *API) CreateBudget(c *gin.Context) {
 		// Handle unique-constraint violations (SQLSTATE 23505),
 		// which indicate a budget already exists for the given key.
 		if pgerr, ok := err.(*pgconn.PgError); ok && pgerr.Code == "23505" {
-			c.JSON(http.StatusConflict, gin.H{"error": "budget_exists"})
+			problem(c, http.StatusConflict, "budget_exists")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, out)
@@ -80,11 +93,12 @@ func (api *API) UpdateBudget(c *gin.Context) {
 
 	var req budgetUpdateReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"})
+		invalidRequest(c, err)
 		return
 	}
 	b := &repo.Budget{
 		CategoryID:  req.CategoryID,
+		GroupID:     req.GroupID,
 		PeriodMonth: req.PeriodMonth,
 		LimitAmount: req.LimitAmount,
 	}
@@ -92,16 +106,16 @@ func (api *API) UpdateBudget(c *gin.Context) {
 	if err != nil {
 		// Preserve unique constraint handling for conflicting keys.
 		if pgerr, ok := err.(*pgconn.PgError); ok && pgerr.Code == "23505" {
-			c.JSON(http.StatusConflict, gin.H{"error": "budget_exists"})
+			problem(c, http.StatusConflict, "budget_exists")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, out)
 }
 
-// DeleteBudget removes a budget by ID for the authenticated user.
+// DeleteBudget soft-deletes a budget by ID for the authenticated user; see RestoreBudget.
 // Returns 204 on success, 404 if the budget does not exist or is not owned by the user.
 func (api *API) DeleteBudget(c *gin.Context) {
 	userID := MustUserID(c)
@@ -109,12 +123,35 @@ func (api *API) DeleteBudget(c *gin.Context) {
 
 	ok, err := api.Repos.BudgetRepo().Delete(c.Request.Context(), userID, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+		problem(c, http.StatusNotFound, "not_found")
 		return
 	}
 	c.Status(http.StatusNoContent)
 }
+
+// RestoreBudget undeletes a soft-deleted budget.
+// Returns 404 if no deleted budget with this ID exists, 409 if a live budget now
+// covers the same month and category.
+func (api *API) RestoreBudget(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	out, err := api.Repos.BudgetRepo().Restore(c.Request.Context(), userID, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			problem(c, http.StatusConflict, "budget_exists")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,128 @@
+// backend/internal/handler/cache.go
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+
+	"pft/internal/cache"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheResponses serves a GET route from store, keyed by user, request URI and language.
+// Only 200 responses are cached; X-Cache reports HIT or MISS. A nil store disables caching.
+// Cache failures are logged and the request is served normally. Requests inside an
+// atomic batch bypass the cache, as they may read writes that are rolled back.
+func CacheResponses(store cache.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if store == nil || c.Request.Method != http.MethodGet || repo.InContextTx(c.Request.Context()) {
+			c.Next()
+			return
+		}
+		ctx := c.Request.Context()
+		userID := MustUserID(c)
+		// Some responses are translated (see localeOf), so each language is cached apart.
+		key := c.Request.URL.RequestURI() + "#" + localeOf(c)
+
+		val, ok, err := store.Get(ctx, userID, key)
+		if err != nil {
+			slog.Warn("cache get failed", "request_id", c.GetString("request_id"), "error", err.Error())
+		}
+		if ok {
+			// Entries are stored as "<content type>\n<body>".
+			if ctype, body, found := bytes.Cut(val, []byte("\n")); found {
+				c.Header("X-Cache", "HIT")
+				c.Data(http.StatusOK, string(ctype), body)
+				c.Abort()
+				return
+			}
+		}
+
+		w := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Header("X-Cache", "MISS")
+		c.Next()
+
+		if w.Status() == http.StatusOK {
+			entry := append([]byte(w.Header().Get("Content-Type")+"\n"), w.buf.Bytes()...)
+			if err := store.Set(ctx, userID, key, entry); err != nil {
+				slog.Warn("cache set failed", "request_id", c.GetString("request_id"), "error", err.Error())
+			}
+		}
+	}
+}
+
+// captureWriter copies the response body while it is written to the client.
+type captureWriter struct {
+	gin.ResponseWriter
+	buf bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.buf.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.buf.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// InvalidateCache drops the user's cached responses when a write request succeeds.
+// Invalidation happens as the status is set, before the response reaches the client,
+// so a read issued after a write never sees pre-write data. A nil store is a no-op.
+func InvalidateCache(store cache.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if store == nil {
+			c.Next()
+			return
+		}
+		c.Writer = &invalidatingWriter{ResponseWriter: c.Writer, c: c, store: store}
+		c.Next()
+	}
+}
+
+// Invalidator returns a func dropping a user's cached responses, for writes made
+// outside the user's own requests (background jobs, the Telegram bot, admin restores),
+// which InvalidateCache does not see. Failures are logged. A nil store gives a no-op.
+func Invalidator(store cache.Store) func(ctx context.Context, userID int64) {
+	return func(ctx context.Context, userID int64) {
+		if store == nil {
+			return
+		}
+		if err := store.Invalidate(ctx, userID); err != nil {
+			slog.Warn("cache invalidate failed", "user_id", userID, "error", err.Error())
+		}
+	}
+}
+
+// invalidatingWriter invalidates the cache once when a success status is written.
+type invalidatingWriter struct {
+	gin.ResponseWriter
+	c     *gin.Context
+	store cache.Store
+	done  bool
+}
+
+func (w *invalidatingWriter) WriteHeader(code int) {
+	if !w.done && code < http.StatusBadRequest {
+		w.done = true
+		if _, ok := w.c.Get("uid"); ok {
+			if err := w.store.Invalidate(w.c.Request.Context(), MustUserID(w.c)); err != nil {
+				slog.Warn("cache invalidate failed", "request_id", w.c.GetString("request_id"),
+					"method", w.c.Request.Method, "path", w.c.FullPath(), "error", err.Error())
+			}
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
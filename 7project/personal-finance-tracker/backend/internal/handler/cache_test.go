@@ -0,0 +1,134 @@
+// backend/internal/handler/cache_test.go
+//
+// Purpose:
+//   Verify cached GET responses are served per user, that a successful write by
+//   the user invalidates them while failed writes do not, and that a write made in
+//   the background shows up on the next GET once it invalidates them.
+
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memCache is a minimal cache.Store using the same generation scheme as the Redis store.
+type memCache struct {
+	gen     map[int64]int
+	entries map[string][]byte
+}
+
+func (m *memCache) k(userID int64, key string) string {
+	return strconv.FormatInt(userID, 10) + ":" + strconv.Itoa(m.gen[userID]) + ":" + key
+}
+
+func (m *memCache) Get(_ context.Context, userID int64, key string) ([]byte, bool, error) {
+	v, ok := m.entries[m.k(userID, key)]
+	return v, ok, nil
+}
+
+func (m *memCache) Set(_ context.Context, userID int64, key string, val []byte) error {
+	m.entries[m.k(userID, key)] = val
+	return nil
+}
+
+func (m *memCache) Invalidate(_ context.Context, userID int64) error {
+	m.gen[userID]++
+	return nil
+}
+
+func TestCacheResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &memCache{gen: map[int64]int{}, entries: map[string][]byte{}}
+	computed := 0
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("uid", int64(1)); c.Next() })
+	r.Use(handler.InvalidateCache(store))
+	r.GET("/api/dashboard/summary", handler.CacheResponses(store), func(c *gin.Context) {
+		computed++
+		c.JSON(http.StatusOK, gin.H{"n": computed})
+	})
+	r.POST("/api/transactions", func(c *gin.Context) {
+		if c.Query("fail") != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/dashboard/summary?month=2025-01", nil))
+		return w
+	}
+	post := func(q string) {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/transactions"+q, nil))
+	}
+
+	if w := get(); w.Header().Get("X-Cache") != "MISS" || w.Body.String() != `{"n":1}` {
+		t.Fatalf("expected miss, got %s %s", w.Header().Get("X-Cache"), w.Body.String())
+	}
+	if w := get(); w.Header().Get("X-Cache") != "HIT" || w.Body.String() != `{"n":1}` ||
+		w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Fatalf("expected hit with cached body, got %s %s", w.Header().Get("X-Cache"), w.Body.String())
+	}
+
+	post("?fail=1")
+	if w := get(); w.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("failed write must not invalidate")
+	}
+	post("")
+	if w := get(); w.Header().Get("X-Cache") != "MISS" || w.Body.String() != `{"n":2}` {
+		t.Fatalf("expected recompute after write, got %s %s", w.Header().Get("X-Cache"), w.Body.String())
+	}
+}
+
+func TestInvalidator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &memCache{gen: map[int64]int{}, entries: map[string][]byte{}}
+	var mu sync.Mutex
+	total := 10.0
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("uid", int64(1)); c.Next() })
+	r.GET("/api/dashboard/summary", handler.CacheResponses(store), func(c *gin.Context) {
+		mu.Lock()
+		defer mu.Unlock()
+		c.JSON(http.StatusOK, gin.H{"expense_total": total})
+	})
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/dashboard/summary", nil))
+		return w
+	}
+
+	get()
+	// A background job records an expense without going through an HTTP write.
+	invalidate := handler.Invalidator(store)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mu.Lock()
+		total += 5
+		mu.Unlock()
+		invalidate(context.Background(), 1)
+	}()
+	<-done
+	if w := get(); w.Header().Get("X-Cache") != "MISS" || w.Body.String() != `{"expense_total":15}` {
+		t.Fatalf("background write not visible: %s %s", w.Header().Get("X-Cache"), w.Body.String())
+	}
+	if w := get(); w.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("expected the recomputed response to be cached, got %s", w.Header().Get("X-Cache"))
+	}
+
+	handler.Invalidator(nil)(context.Background(), 1) // caching disabled: a no-op
+}
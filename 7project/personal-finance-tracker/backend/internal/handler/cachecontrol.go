@@ -0,0 +1,162 @@
+// backend/internal/handler/cachecontrol.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cache classes of API routes; CacheControl answers each with its own Cache-Control.
+const (
+	// CacheNoStore: credentials, tokens, signed URLs and anything not a successful
+	// read. Never stored by browsers or shared caches.
+	CacheNoStore = "no-store"
+	// CachePrivate: a user's lists and reports. The browser may reuse them for
+	// CachePolicy.PrivateMaxAge; shared caches and CDNs never keep them.
+	CachePrivate = "private"
+	// CacheRevalidate: a user's data that changes on its own (job progress, sync,
+	// delivery logs, polled triggers). Kept by the browser but revalidated on every use.
+	CacheRevalidate = "revalidate"
+	// CachePublic: the same for everyone; CDNs may keep it for CachePolicy.PublicMaxAge.
+	CachePublic = "public"
+)
+
+// routeCache assigns routes a class other than the default, keyed by method and route
+// path like routeScopes. Other GET routes are CachePrivate, routes under /api/admin
+// and every other method CacheNoStore.
+var routeCache = map[string]string{
+	"GET /api/version":                         CachePublic,
+	"GET /api/docs":                            CachePublic,
+	"GET /api/docs/openapi.json":               CachePublic,
+	"GET /api/healthz":                         CacheNoStore,
+	"GET /api/readyz":                          CacheNoStore,
+	"GET /metrics":                             CacheNoStore,
+	"GET /api/oauth/authorize":                 CacheNoStore,
+	"GET /api/me/ingest-address":               CacheNoStore,
+	"GET /api/exports/:id":                     CacheNoStore, // carries a signed download URL
+	"GET /api/me":                              CacheRevalidate,
+	"GET /api/me/usage":                        CacheRevalidate,
+	"GET /api/me/features":                     CacheRevalidate,
+	"GET /api/sync":                            CacheRevalidate,
+	"GET /api/imports/:id":                     CacheRevalidate,
+	"GET /api/audit":                           CacheRevalidate,
+	"GET /api/webhooks/dead-letters":           CacheRevalidate,
+	"GET /api/webhooks/:id/deliveries":         CacheRevalidate,
+	"GET /api/zapier/triggers/new-transaction": CacheRevalidate,
+	"GET /api/zapier/triggers/budget-exceeded": CacheRevalidate,
+}
+
+// RouteCache returns the cache class of method and route path.
+func RouteCache(method, path string) string {
+	if class, ok := routeCache[method+" "+path]; ok {
+		return class
+	}
+	if (method != http.MethodGet && method != http.MethodHead) || path == "" || strings.HasPrefix(path, "/api/admin/") {
+		return CacheNoStore
+	}
+	return CachePrivate
+}
+
+// CachePolicy sets how long each class may be reused without asking the server.
+// - PrivateMaxAge: for CachePrivate; 0 revalidates every time, like CacheRevalidate
+// - PublicMaxAge: for CachePublic
+type CachePolicy struct {
+	PrivateMaxAge time.Duration
+	PublicMaxAge  time.Duration
+}
+
+// header returns the Cache-Control value of class.
+func (p CachePolicy) header(class string) string {
+	switch class {
+	case CachePrivate:
+		if p.PrivateMaxAge > 0 {
+			return "private, max-age=" + strconv.Itoa(int(p.PrivateMaxAge.Seconds()))
+		}
+		return "private, no-cache"
+	case CacheRevalidate:
+		return "private, no-cache"
+	case CachePublic:
+		return "public, max-age=" + strconv.Itoa(int(p.PublicMaxAge.Seconds()))
+	}
+	return "no-store"
+}
+
+// CacheControl sets Cache-Control on every response from its route's class (see
+// RouteCache) unless the handler set one itself. Only 200 and 304 answers are
+// cacheable; errors and redirects are no-store whatever the class. Private responses
+// vary by credentials, so a browser shared by two users never mixes their data.
+// It must run after routing (as any middleware added with Use does).
+func CacheControl(p CachePolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		class := RouteCache(c.Request.Method, c.FullPath())
+		c.Writer = &cacheControlWriter{ResponseWriter: c.Writer, class: class, value: p.header(class)}
+		c.Next()
+	}
+}
+
+// cacheControlWriter adds the headers once the status is known.
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	class, value string
+	done         bool
+}
+
+func (w *cacheControlWriter) apply(status int) {
+	h := w.Header()
+	if w.done || h.Get("Cache-Control") != "" {
+		return
+	}
+	w.done = true
+	if status != http.StatusOK && status != http.StatusNotModified {
+		h.Set("Cache-Control", "no-store")
+		return
+	}
+	h.Set("Cache-Control", w.value)
+	if w.class == CachePrivate || w.class == CacheRevalidate {
+		h.Add("Vary", "Authorization, X-API-Key")
+	}
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	w.apply(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheControlWriter) WriteHeaderNow() {
+	w.apply(w.Status())
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheControlWriter) Write(b []byte) (int, error) {
+	w.apply(w.Status())
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *cacheControlWriter) WriteString(s string) (int, error) {
+	w.apply(w.Status())
+	return w.ResponseWriter.WriteString(s)
+}
+
+// notModified sets Last-Modified to t and answers 304 when the request's
+// If-Modified-Since is not older than t, returning true. If-None-Match takes
+// precedence (RFC 9110), so requests carrying it are left to ConditionalGET.
+func notModified(c *gin.Context, t time.Time) bool {
+	t = t.UTC().Truncate(time.Second)
+	c.Header("Last-Modified", t.Format(http.TimeFormat))
+	if c.GetHeader("If-None-Match") != "" {
+		return false
+	}
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil || t.After(since) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+	c.Abort()
+	return true
+}
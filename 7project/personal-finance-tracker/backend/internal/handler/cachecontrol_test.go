@@ -0,0 +1,90 @@
+// backend/internal/handler/cachecontrol_test.go
+//
+// Purpose:
+//   Verify each route class gets its Cache-Control, that only successful reads are
+//   cacheable, that handlers can set their own, and that Last-Modified answers
+//   If-Modified-Since with 304.
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pft/internal/handler"
+	"pft/internal/platform"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCacheControl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	platform.BuildDate = "2026-01-02T03:04:05Z"
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) }
+	r := gin.New()
+	r.Use(handler.CacheControl(handler.CachePolicy{PrivateMaxAge: 15 * time.Second, PublicMaxAge: 5 * time.Minute}))
+	r.GET("/api/version", handler.New(nil, "s").Version)
+	r.GET("/api/categories", handler.ConditionalGET(), ok)
+	r.POST("/api/categories", func(c *gin.Context) { c.JSON(http.StatusCreated, gin.H{"id": 1}) })
+	r.GET("/api/sync", ok)
+	r.GET("/api/claims/:id", func(c *gin.Context) { c.JSON(http.StatusNotFound, gin.H{"code": "not_found"}) })
+	r.GET("/api/admin/jobs", ok)
+	r.GET("/api/exports/:id/download", func(c *gin.Context) {
+		c.Header("Cache-Control", "private, no-store")
+		c.String(http.StatusOK, "csv")
+	})
+
+	do := func(method, target string, header ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		for i := 0; i+1 < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	cases := []struct {
+		method, target string
+		status         int
+		want           string
+	}{
+		{"GET", "/api/categories", 200, "private, max-age=15"},
+		{"POST", "/api/categories", 201, "no-store"},
+		{"GET", "/api/sync", 200, "private, no-cache"},
+		{"GET", "/api/claims/7", 404, "no-store"},
+		{"GET", "/api/admin/jobs", 200, "no-store"},
+		{"GET", "/api/exports/1/download", 200, "private, no-store"},
+		{"GET", "/api/unknown", 404, "no-store"},
+		{"GET", "/api/version", 200, "public, max-age=300"},
+	}
+	for _, tc := range cases {
+		w := do(tc.method, tc.target)
+		if w.Code != tc.status || w.Header().Get("Cache-Control") != tc.want {
+			t.Errorf("%s %s: %d %q, want %d %q", tc.method, tc.target, w.Code, w.Header().Get("Cache-Control"), tc.status, tc.want)
+		}
+	}
+	if v := do("GET", "/api/sync").Header().Values("Vary"); !strings.Contains(strings.Join(v, ","), "Authorization") {
+		t.Errorf("private responses should vary by credentials, got %v", v)
+	}
+
+	// Revalidation keeps the class on the 304.
+	tag := do("GET", "/api/categories").Header().Get("ETag")
+	if w := do("GET", "/api/categories", "If-None-Match", tag); w.Code != 304 || w.Header().Get("Cache-Control") != "private, max-age=15" {
+		t.Errorf("If-None-Match: %d %q", w.Code, w.Header().Get("Cache-Control"))
+	}
+
+	w := do("GET", "/api/version")
+	lm := w.Header().Get("Last-Modified")
+	if lm != "Fri, 02 Jan 2026 03:04:05 GMT" {
+		t.Fatalf("Last-Modified = %q", lm)
+	}
+	if w := do("GET", "/api/version", "If-Modified-Since", lm); w.Code != 304 || w.Body.Len() != 0 {
+		t.Errorf("If-Modified-Since the build: %d %s, want 304", w.Code, w.Body)
+	}
+	if w := do("GET", "/api/version", "If-Modified-Since", "Thu, 01 Jan 2026 00:00:00 GMT"); w.Code != 200 {
+		t.Errorf("If-Modified-Since before the build: %d, want 200", w.Code)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -16,23 +17,70 @@ import (
 // categoryCreateReq represents the payload for creating a category.
 // - Name: human-readable category label
 // - Type: constrained to "income" or "expense"
+// - TaxDeductible: default tax flag for transactions in this category
+// - TaxCategory: optional label grouping the category in the tax report
+// - GroupID: optional category group it belongs to; omitted on update ungroups it
+// - TxnLimit/TxnLimitMode: optional cap on a single expense, "warn" or "block" (enforced by the repo)
+//
+// The limit and its mode come together; omitting them on update removes the limit.
 type categoryCreateReq struct {
-	Name string `json:"name" binding:"required,min=1,max=100"`
-	Type string `json:"type" binding:"required,oneof=income expense"`
+	Name          string   `json:"name" binding:"required,min=1,max=100"`
+	Type          string   `json:"type" binding:"required,oneof=income expense"`
+	TaxDeductible bool     `json:"tax_deductible"`
+	TaxCategory   string   `json:"tax_category" binding:"max=100"`
+	GroupID       *int64   `json:"group_id"`
+	TxnLimit      *float64 `json:"txn_limit" binding:"omitempty,gt=0,lt=10000000000"`
+	TxnLimitMode  *string  `json:"txn_limit_mode" binding:"required_with=TxnLimit,excluded_without=TxnLimit,omitempty,oneof=warn block"`
 }
 
 // categoryUpdateReq mirrors creation fields for updates.
-type categoryUpdateReq struct {
-	Name string `json:"name" binding:"required,min=1,max=100"`
-	Type string `json:"type" binding:"required,oneof=income expense"`
+type categoryUpdateReq = categoryCreateReq
+
+// model converts the request into a repo DTO owned by userID.
+func (r categoryCreateReq) model(userID int64) *repo.Category {
+	return &repo.Category{
+		UserID:        userID,
+		Name:          r.Name,
+		Type:          r.Type,
+		TaxDeductible: r.TaxDeductible,
+		TaxCategory:   strings.TrimSpace(r.TaxCategory),
+		GroupID:       r.GroupID,
+		TxnLimit:      r.TxnLimit,
+		TxnLimitMode:  r.TxnLimitMode,
+	}
 }
 
 // ListCategories returns all categories owned by the authenticated user.
+// With ?deleted=true it lists the soft-deleted ones instead, which can be restored.
+// With ?include=usage each category also carries "usage": its number of transactions
+// and the date of the latest, null when it was never used.
 func (api *API) ListCategories(c *gin.Context) {
 	userID := MustUserID(c)
-	cats, err := api.Repos.CategoryRepo().List(c.Request.Context(), userID)
+	deleted, _ := strconv.ParseBool(c.Query("deleted"))
+	var usage bool
+	for _, inc := range strings.Split(c.Query("include"), ",") {
+		switch strings.TrimSpace(inc) {
+		case "":
+		case "usage":
+			usage = true
+		default:
+			problemDetail(c, http.StatusBadRequest, "invalid_include", "include may only list: usage.")
+			return
+		}
+	}
+	ctx, cr := c.Request.Context(), api.Repos.CategoryRepo()
+	var cats []repo.Category
+	var err error
+	switch {
+	case usage:
+		cats, err = cr.ListWithUsage(ctx, userID, deleted)
+	case deleted:
+		cats, err = cr.ListDeleted(ctx, userID)
+	default:
+		cats, err = cr.List(ctx, userID)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, cats)
@@ -44,17 +92,17 @@ func (api *API) CreateCategory(c *gin.Context) {
 	userID := MustUserID(c)
 	var req categoryCreateReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"})
+		invalidRequest(c, err)
 		return
 	}
-	cat, err := api.Repos.CategoryRepo().Create(c.Request.Context(), userID, req.Name, req.Type)
+	cat, err := api.Repos.CategoryRepo().Create(c.Request.Context(), req.model(userID))
 	if err != nil {
 		// Map unique violation (SQLSTATE 23505) to a conflict response.
 		if pgerr, ok := err.(*pgconn.PgError); ok && pgerr.Code == "23505" {
-			c.JSON(http.StatusConflict, gin.H{"error": "category_exists"})
+			problem(c, http.StatusConflict, "category_exists")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, cat)
@@ -68,28 +116,28 @@ func (api *API) UpdateCategory(c *gin.Context) {
 
 	var req categoryUpdateReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"})
+		invalidRequest(c, err)
 		return
 	}
-	cat, err := api.Repos.CategoryRepo().Update(c.Request.Context(), userID, id, req.Name, req.Type)
+	cat, err := api.Repos.CategoryRepo().Update(c.Request.Context(), userID, id, req.model(userID))
 	if err != nil {
 		// Handle duplicate name/type combinations as a conflict.
 		if pgerr, ok := err.(*pgconn.PgError); ok && pgerr.Code == "23505" {
-			c.JSON(http.StatusConflict, gin.H{"error": "category_exists"})
+			problem(c, http.StatusConflict, "category_exists")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 	if cat == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+		problem(c, http.StatusNotFound, "not_found")
 		return
 	}
 	c.JSON(http.StatusOK, cat)
 }
 
-// DeleteCategory removes a category by ID.
-// - 409 if a foreign key constraint prevents deletion (e.g., related budgets)
+// DeleteCategory soft-deletes a category by ID; see RestoreCategory.
+// - 409 if budgets still use the category
 // - 404 if not found
 // - 204 on successful deletion
 func (api *API) DeleteCategory(c *gin.Context) {
@@ -100,27 +148,46 @@ func (api *API) DeleteCategory(c *gin.Context) {
 	if err != nil {
 		// Primary path: sentinel value from repository indicating FK conflict.
 		if errors.Is(err, repo.ErrFKConflict) {
-			c.JSON(http.StatusConflict, gin.H{
-				"error": "category_has_budgets",
-				"msg":   "Delete or reassign budgets for this category before deleting it.",
-			})
+			problemDetail(c, http.StatusConflict, "category_has_budgets",
+				"Delete or reassign budgets for this category before deleting it.")
 			return
 		}
 		// Fallback: direct inspection of PostgreSQL FK violation (SQLSTATE 23503).
 		var pgerr *pgconn.PgError
 		if errors.As(err, &pgerr) && pgerr.Code == "23503" {
-			c.JSON(http.StatusConflict, gin.H{
-				"error": "category_has_budgets",
-				"msg":   "Delete or reassign budgets for this category before deleting it.",
-			})
+			problemDetail(c, http.StatusConflict, "category_has_budgets",
+				"Delete or reassign budgets for this category before deleting it.")
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+		problem(c, http.StatusNotFound, "not_found")
 		return
 	}
 	c.Status(http.StatusNoContent)
 }
+
+// RestoreCategory undeletes a soft-deleted category.
+// - 404 if no deleted category with this ID exists (or it was purged)
+// - 409 if a live category now has the same name
+func (api *API) RestoreCategory(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+
+	cat, err := api.Repos.CategoryRepo().Restore(c.Request.Context(), userID, id)
+	if err != nil {
+		if isUniqueViolation(err) {
+			problem(c, http.StatusConflict, "category_exists")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	if cat == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, cat)
+}
@@ -0,0 +1,97 @@
+// backend/internal/handler/category_group.go
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// categoryGroupReq names a category group; categories join it through their group_id.
+type categoryGroupReq struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+// ListCategoryGroups returns the user's category groups with their member categories.
+func (api *API) ListCategoryGroups(c *gin.Context) {
+	groups, err := api.Repos.CategoryGroupRepo().List(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, groups)
+}
+
+// CreateCategoryGroup adds a group; 409 if the user already has one by that name.
+func (api *API) CreateCategoryGroup(c *gin.Context) {
+	var req categoryGroupReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	g, err := api.Repos.CategoryGroupRepo().Create(c.Request.Context(), MustUserID(c), strings.TrimSpace(req.Name))
+	if err != nil {
+		if isUniqueViolation(err) {
+			problem(c, http.StatusConflict, "group_exists")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, g)
+}
+
+// RenameCategoryGroup renames a group.
+// - 404 if not found
+// - 409 if another group has the name
+func (api *API) RenameCategoryGroup(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req categoryGroupReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	g, err := api.Repos.CategoryGroupRepo().Rename(c.Request.Context(), MustUserID(c), id, strings.TrimSpace(req.Name))
+	if err != nil {
+		if isUniqueViolation(err) {
+			problem(c, http.StatusConflict, "group_exists")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	if g == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, g)
+}
+
+// DeleteCategoryGroup deletes a group; its categories become ungrouped.
+// - 409 if budgets still target the group
+// - 404 if not found
+// - 204 on successful deletion
+func (api *API) DeleteCategoryGroup(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.CategoryGroupRepo().Delete(c.Request.Context(), MustUserID(c), id)
+	if err != nil {
+		if errors.Is(err, repo.ErrFKConflict) {
+			problemDetail(c, http.StatusConflict, "group_has_budgets",
+				"Delete the budgets for this group before deleting it.")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,145 @@
+// backend/internal/handler/chat_webhook.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"pft/internal/alerts"
+	"pft/internal/chathook"
+	"pft/internal/i18n"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// chatWebhookReq adds or replaces a Slack or Discord webhook alerts are posted to.
+// - Kind: "slack" or "discord"; which alerts each receives is set in /me/notifications
+// - URL: the incoming webhook URL created in the Slack app or Discord channel settings
+// - Name: optional label, e.g. "#family-budget"
+type chatWebhookReq struct {
+	Kind string `json:"kind" binding:"required,oneof=slack discord"`
+	URL  string `json:"url" binding:"required,max=2048"`
+	Name string `json:"name" binding:"max=100"`
+}
+
+// chatWebhookURLs describes the accepted URLs per kind for error details.
+var chatWebhookURLs = map[string]string{
+	alerts.ChannelSlack:   "url must be a Slack incoming webhook URL (https://hooks.slack.com/services/...).",
+	alerts.ChannelDiscord: "url must be a Discord webhook URL (https://discord.com/api/webhooks/...).",
+}
+
+// bind reads the request and checks the URL belongs to the chosen service.
+func (req *chatWebhookReq) bind(c *gin.Context) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		invalidRequest(c, err)
+		return false
+	}
+	if !chathook.ValidURL(req.Kind, req.URL) {
+		problemDetail(c, http.StatusBadRequest, "invalid_webhook_url", chatWebhookURLs[req.Kind])
+		return false
+	}
+	return true
+}
+
+// ListChatWebhooks returns the user's Slack and Discord webhooks (URLs abbreviated).
+func (api *API) ListChatWebhooks(c *gin.Context) {
+	out, err := api.Repos.ChatWebhookRepo().List(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateChatWebhook adds a webhook; 409 if the user already added its URL.
+func (api *API) CreateChatWebhook(c *gin.Context) {
+	var req chatWebhookReq
+	if !req.bind(c) {
+		return
+	}
+	out, err := api.Repos.ChatWebhookRepo().Create(c.Request.Context(), &repo.ChatWebhook{
+		UserID: MustUserID(c), Kind: req.Kind, URL: req.URL, Name: req.Name,
+	})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// UpdateChatWebhook replaces a webhook identified by :id and re-enables it if it was
+// disabled after failing.
+func (api *API) UpdateChatWebhook(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req chatWebhookReq
+	if !req.bind(c) {
+		return
+	}
+	out, err := api.Repos.ChatWebhookRepo().Update(c.Request.Context(), MustUserID(c), id, &repo.ChatWebhook{
+		Kind: req.Kind, URL: req.URL, Name: req.Name,
+	})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteChatWebhook removes a webhook identified by :id.
+func (api *API) DeleteChatWebhook(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.ChatWebhookRepo().Delete(c.Request.Context(), MustUserID(c), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// TestChatWebhook posts a test message to the webhook identified by :id right away.
+// Answers 204 on success and 502 "webhook_failed" with the service's reply otherwise;
+// either way the outcome is recorded on the webhook.
+func (api *API) TestChatWebhook(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ctx := c.Request.Context()
+	h, err := api.Repos.ChatWebhookRepo().Get(ctx, userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if h == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	lang := localeOf(c)
+	perr := chathook.Post(ctx, nil, h.Kind, h.URL, &alerts.Alert{
+		UserID: userID,
+		Title:  i18n.T(lang, "alert.test.title"),
+		Body:   i18n.T(lang, "alert.test.body"),
+	})
+	var failure *string
+	if perr != nil {
+		msg := perr.Error()
+		failure = &msg
+	}
+	if err := api.Repos.ChatWebhookRepo().RecordDelivery(ctx, userID, id, failure, false); err != nil {
+		fail(c, err)
+		return
+	}
+	if perr != nil {
+		problemDetail(c, http.StatusBadGateway, "webhook_failed", perr.Error())
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,186 @@
+// backend/internal/handler/claim.go
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// claimCreateReq opens a reimbursement claim.
+// - TransactionIDs: optional reimbursable, unclaimed expenses to attach right away
+type claimCreateReq struct {
+	Title          string  `json:"title" binding:"required,min=1,max=200"`
+	TransactionIDs []int64 `json:"transaction_ids"`
+}
+
+// claimItemsReq attaches more expenses to a draft claim.
+type claimItemsReq struct {
+	TransactionIDs []int64 `json:"transaction_ids" binding:"required,min=1"`
+}
+
+// claimStatusReq moves a claim forward in its workflow.
+// - Date: when the claim was submitted/paid (YYYY-MM-DD), defaults to today
+type claimStatusReq struct {
+	Status string `json:"status" binding:"required,oneof=submitted paid"`
+	Date   string `json:"date"`
+}
+
+// claimError maps claim repository errors to responses; returns false when err is unhandled.
+func claimError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		problem(c, http.StatusNotFound, "not_found")
+	case errors.Is(err, repo.ErrClaimLocked):
+		problem(c, http.StatusConflict, "claim_locked")
+	case errors.Is(err, repo.ErrInvalidTransition):
+		problem(c, http.StatusConflict, "invalid_transition")
+	case errors.Is(err, repo.ErrInvalidClaimItems):
+		problem(c, http.StatusBadRequest, "invalid_claim_items")
+	default:
+		return false
+	}
+	return true
+}
+
+// ListClaims returns the user's reimbursement claims with totals.
+func (api *API) ListClaims(c *gin.Context) {
+	userID := MustUserID(c)
+	out, err := api.Repos.ClaimRepo().List(c.Request.Context(), userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// GetClaim returns a claim with its attached transactions.
+func (api *API) GetClaim(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	out, err := api.Repos.ClaimRepo().Get(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateClaim opens a draft claim, optionally attaching transactions.
+func (api *API) CreateClaim(c *gin.Context) {
+	userID := MustUserID(c)
+	var req claimCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	out, err := api.Repos.ClaimRepo().Create(c.Request.Context(), userID, req.Title, req.TransactionIDs)
+	if err != nil {
+		if claimError(c, err) {
+			return
+		}
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// AddClaimItems attaches reimbursable expenses to a draft claim.
+func (api *API) AddClaimItems(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req claimItemsReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	out, err := api.Repos.ClaimRepo().AddItems(c.Request.Context(), userID, id, req.TransactionIDs)
+	if err != nil {
+		if claimError(c, err) {
+			return
+		}
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// RemoveClaimItem detaches transaction :txid from a draft claim.
+func (api *API) RemoveClaimItem(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	txID, _ := strconv.ParseInt(c.Param("txid"), 10, 64)
+	ok, err := api.Repos.ClaimRepo().RemoveItem(c.Request.Context(), userID, id, txID)
+	if err != nil {
+		if claimError(c, err) {
+			return
+		}
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SetClaimStatus marks a claim submitted or paid. Statuses only move forward;
+// paying a claim nets its expenses out of spending reports.
+func (api *API) SetClaimStatus(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req claimStatusReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	on := time.Now().UTC()
+	if req.Date != "" {
+		d, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			problem(c, http.StatusBadRequest, "invalid_date")
+			return
+		}
+		on = d
+	}
+	out, err := api.Repos.ClaimRepo().SetStatus(c.Request.Context(), userID, id, req.Status, on)
+	if err != nil {
+		if claimError(c, err) {
+			return
+		}
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteClaim removes a claim and releases its transactions.
+func (api *API) DeleteClaim(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.ClaimRepo().Delete(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
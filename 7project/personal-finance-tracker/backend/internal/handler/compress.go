@@ -0,0 +1,182 @@
+// backend/internal/handler/compress.go
+
+package handler
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// Compress encodes text responses (JSON, CSV, HTML, ...) with brotli or gzip, whichever the
+// client prefers via Accept-Encoding, once the body reaches minSize bytes. Smaller bodies are
+// sent as-is because the encoding overhead outweighs the saving. Streaming handlers that
+// flush before minSize is reached are compressed from the first flush.
+func Compress(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		enc := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if enc == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+		orig := c.Writer
+		w := &compressWriter{ResponseWriter: orig, encoding: enc, minSize: minSize, status: http.StatusOK}
+		c.Writer = w
+		// On panic the buffered response is dropped so Recovery can still answer 500.
+		defer func() { c.Writer = orig }()
+		c.Next()
+		w.close()
+	}
+}
+
+// negotiateEncoding picks "br" or "gzip" from an Accept-Encoding header, honouring q=0
+// and the client's weights; "" means send the body unencoded.
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		// Prefer brotli on equal weights; it is smaller for JSON.
+		if (name == "br" || name == "gzip") && (q > bestQ || (q == bestQ && name == "br")) {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// compressible reports whether a Content-Type is worth encoding; images and archives are not.
+func compressible(ctype string) bool {
+	ctype, _, _ = strings.Cut(ctype, ";")
+	return strings.HasPrefix(ctype, "text/") || strings.HasSuffix(ctype, "json") ||
+		strings.HasSuffix(ctype, "xml") || ctype == "application/javascript"
+}
+
+var (
+	gzipPool   = sync.Pool{New: func() any { w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression); return w }}
+	brotliPool = sync.Pool{New: func() any { return brotli.NewWriterLevel(nil, 4) }}
+)
+
+// compressWriter buffers the start of the body until it knows whether compression pays off.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+	status   int
+	buf      []byte
+	enc      io.WriteCloser // set once compressing
+	started  bool           // status and headers sent
+	size     int
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if !w.started {
+		w.status = code
+	}
+}
+
+func (w *compressWriter) WriteHeaderNow() {}
+
+func (w *compressWriter) Status() int {
+	if w.started {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *compressWriter) Size() int     { return w.size }
+func (w *compressWriter) Written() bool { return w.started || len(w.buf) > 0 }
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	w.size += len(b)
+	if !w.started {
+		w.buf = append(w.buf, b...)
+		if len(w.buf) >= w.minSize {
+			if err := w.start(true); err != nil {
+				return 0, err
+			}
+		}
+		return len(b), nil
+	}
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) { return w.Write([]byte(s)) }
+
+// Flush pushes buffered bytes to the client; a stream is compressed if its type allows.
+func (w *compressWriter) Flush() {
+	if !w.started {
+		_ = w.start(true)
+	}
+	if f, ok := w.enc.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// start sends the status and headers, choosing the encoding, then writes any buffered body.
+func (w *compressWriter) start(wantCompress bool) error {
+	w.started = true
+	h := w.Header()
+	if wantCompress && h.Get("Content-Encoding") == "" && compressible(h.Get("Content-Type")) &&
+		w.status != http.StatusNoContent && w.status != http.StatusNotModified {
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+		if w.encoding == "br" {
+			bw := brotliPool.Get().(*brotli.Writer)
+			bw.Reset(w.ResponseWriter)
+			w.enc = bw
+		} else {
+			gw := gzipPool.Get().(*gzip.Writer)
+			gw.Reset(w.ResponseWriter)
+			w.enc = gw
+		}
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if w.enc != nil {
+		_, err := w.enc.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// close finishes the response: a small body goes out unencoded, an encoder is flushed and pooled.
+func (w *compressWriter) close() {
+	if !w.started {
+		_ = w.start(false)
+		return
+	}
+	if w.enc == nil {
+		return
+	}
+	_ = w.enc.Close()
+	switch e := w.enc.(type) {
+	case *gzip.Writer:
+		gzipPool.Put(e)
+	case *brotli.Writer:
+		brotliPool.Put(e)
+	}
+	w.enc = nil
+}
@@ -0,0 +1,78 @@
+// backend/internal/handler/compress_test.go
+//
+// Purpose:
+//   Verify response compression: encoding negotiation, the size threshold,
+//   skipping non-text content, and that the compressed body round-trips.
+
+package handler_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+func TestCompress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	big := strings.Repeat(`{"amount":12.5,"type":"expense"},`, 100)
+	r := gin.New()
+	r.Use(handler.Compress(1024))
+	r.GET("/big", func(c *gin.Context) { c.Data(http.StatusOK, "application/json", []byte(big)) })
+	r.GET("/small", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) })
+	r.GET("/png", func(c *gin.Context) { c.Data(http.StatusOK, "image/png", []byte(big)) })
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", accept)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	cases := []struct {
+		path, accept, encoding string
+	}{
+		{"/big", "gzip, deflate", "gzip"},
+		{"/big", "gzip;q=0.5, br", "br"},
+		{"/big", "br;q=0, gzip", "gzip"},
+		{"/big", "identity", ""},
+		{"/small", "gzip, br", ""},
+		{"/png", "gzip, br", ""},
+	}
+	for _, tc := range cases {
+		rec := get(tc.path, tc.accept)
+		if got := rec.Header().Get("Content-Encoding"); got != tc.encoding {
+			t.Errorf("%s with %q: Content-Encoding = %q, want %q", tc.path, tc.accept, got, tc.encoding)
+			continue
+		}
+		if !strings.Contains(rec.Header().Get("Vary"), "Accept-Encoding") {
+			t.Errorf("%s: missing Vary: Accept-Encoding", tc.path)
+		}
+		var body io.Reader = rec.Body
+		switch tc.encoding {
+		case "gzip":
+			zr, err := gzip.NewReader(rec.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body = zr
+		case "br":
+			body = brotli.NewReader(rec.Body)
+		}
+		b, err := io.ReadAll(body)
+		if err != nil {
+			t.Fatalf("%s with %q: %v", tc.path, tc.accept, err)
+		}
+		if tc.path == "/big" && string(b) != big {
+			t.Errorf("%s with %q: body did not round-trip", tc.path, tc.accept)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// backend/internal/handler/cors.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig configures cross-origin access for browser clients hosted elsewhere.
+// - AllowedOrigins: exact origins (scheme://host[:port]) or "*" for any
+// - AllowedMethods/AllowedHeaders: returned on preflight requests
+// - AllowCredentials: allow cookies and Authorization headers cross-origin (incompatible with "*")
+// - MaxAge: how long browsers may cache a preflight result
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORS answers preflight requests and adds Access-Control-* headers for allowed origins.
+// Requests without an Origin header (same-origin, curl, ...) pass through untouched.
+// Preflights from origins that are not allowed are rejected with 403.
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	wildcard := false
+	origins := make(map[string]bool, len(cfg.AllowedOrigins))
+	for _, o := range cfg.AllowedOrigins {
+		if o == "*" {
+			wildcard = true
+		}
+		origins[strings.TrimRight(o, "/")] = true
+	}
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		c.Writer.Header().Add("Vary", "Origin")
+		if !wildcard && !origins[origin] {
+			if preflight {
+				problem(c, http.StatusForbidden, "origin_not_allowed")
+				return
+			}
+			c.Next()
+			return
+		}
+
+		// With credentials the wildcard is not honoured by browsers, so always echo the origin.
+		if wildcard && !cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+		c.Header("Access-Control-Expose-Headers", RequestIDHeader+", ETag")
+
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", methods)
+			c.Header("Access-Control-Allow-Headers", headers)
+			if cfg.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,82 @@
+// backend/internal/handler/cors_test.go
+//
+// Purpose:
+//   Verify the CORS middleware: preflight answers for allowed origins, 403 for
+//   unknown ones, and pass-through for requests without an Origin header.
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func corsRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(handler.CORS(handler.CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           time.Minute,
+	}))
+	r.GET("/api/x", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func TestCORS_PreflightAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodOptions, "/api/x", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	w := httptest.NewRecorder()
+	corsRouter().ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", w.Code)
+	}
+	h := w.Header()
+	if h.Get("Access-Control-Allow-Origin") != "https://app.example.com" ||
+		h.Get("Access-Control-Allow-Credentials") != "true" ||
+		h.Get("Access-Control-Allow-Methods") != "GET, POST" ||
+		h.Get("Access-Control-Max-Age") != "60" {
+		t.Fatalf("unexpected preflight headers: %v", h)
+	}
+}
+
+func TestCORS_PreflightDisallowedOrigin(t *testing.T) {
+	req := httptest.NewRequest(http.MethodOptions, "/api/x", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	w := httptest.NewRecorder()
+	corsRouter().ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("expected 403 without allow-origin, got %d %v", w.Code, w.Header())
+	}
+}
+
+func TestCORS_SimpleRequests(t *testing.T) {
+	r := corsRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/x", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Fatalf("expected allowed origin echoed, got %d %v", w.Code, w.Header())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/x", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("expected untouched same-origin request, got %d %v", w.Code, w.Header())
+	}
+}
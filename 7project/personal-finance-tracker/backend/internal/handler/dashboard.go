@@ -4,23 +4,115 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 // MonthSummary returns an aggregate view for a given month.
-// Expects query parameter "month" in YYYY-MM format.
+// Expects query parameter "month" in YYYY-MM format; optional "display_currency"
+// converts amounts (see displayConversion).
 // Responds with 400 if the month is missing, 500 on repository errors, and 200 with the summary payload on success.
 func (api *API) MonthSummary(c *gin.Context) {
 	userID := MustUserID(c)
 	month := c.Query("month")
 	if month == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "month_required"})
+		problem(c, http.StatusBadRequest, "month_required")
 		return
 	}
-	out, err := api.Repos.DashboardRepo().Summary(c.Request.Context(), userID, month)
+	conv, ok := api.displayConversion(c)
+	if !ok {
+		return
+	}
+	out, err := api.Repos.DashboardRepo().In(conv).Summary(c.Request.Context(), userID, month)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DailySpend returns total expenses per day for a month, suitable for a calendar heatmap.
+// Expects query parameter "month" in YYYY-MM format; every day of the month is present in the result.
+// Optional "display_currency" converts amounts.
+func (api *API) DailySpend(c *gin.Context) {
+	userID := MustUserID(c)
+	month := c.Query("month")
+	if month == "" {
+		problem(c, http.StatusBadRequest, "month_required")
+		return
+	}
+	if !validMonth(month) {
+		problem(c, http.StatusBadRequest, "invalid_month")
+		return
+	}
+	conv, ok := api.displayConversion(c)
+	if !ok {
+		return
+	}
+	out, err := api.Repos.DashboardRepo().In(conv).Daily(c.Request.Context(), userID, month)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// TopExpenses returns the largest expenses of a month with category info.
+// Query parameters:
+// - month: required, YYYY-MM
+// - n: number of rows to return (default 10, clamped to 1..100)
+// - display_currency: converts amounts (see displayConversion); rows are ranked by the converted amount
+func (api *API) TopExpenses(c *gin.Context) {
+	userID := MustUserID(c)
+	month := c.Query("month")
+	if month == "" {
+		problem(c, http.StatusBadRequest, "month_required")
+		return
+	}
+	if !validMonth(month) {
+		problem(c, http.StatusBadRequest, "invalid_month")
+		return
+	}
+	n := asInt(c.Query("n"), 10)
+	if n <= 0 {
+		n = 10
+	}
+	if n > 100 {
+		n = 100
+	}
+	conv, ok := api.displayConversion(c)
+	if !ok {
+		return
+	}
+	out, err := api.Repos.DashboardRepo().In(conv).TopExpenses(c.Request.Context(), userID, month, n)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// Projection forecasts the end-of-month net for a month.
+// Optional query parameter "month" in YYYY-MM format defaults to the current month;
+// "display_currency" converts amounts. The forecast combines month-to-date actuals, future-dated (scheduled) transactions,
+// payments expected from income sources, coming occurrences of recurring rules and bills,
+// and the average daily discretionary spend over the last 90 days for the remaining days.
+func (api *API) Projection(c *gin.Context) {
+	userID := MustUserID(c)
+	now := time.Now().UTC()
+	month := c.DefaultQuery("month", now.Format("2006-01"))
+	if !validMonth(month) {
+		problem(c, http.StatusBadRequest, "invalid_month")
+		return
+	}
+	conv, ok := api.displayConversion(c)
+	if !ok {
+		return
+	}
+	out, err := api.Repos.DashboardRepo().In(conv).Projection(c.Request.Context(), userID, month, now)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, out)
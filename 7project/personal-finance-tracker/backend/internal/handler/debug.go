@@ -0,0 +1,75 @@
+// backend/internal/handler/debug.go
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+
+	"pft/internal/platform"
+)
+
+// Debug serves the Go profiler and runtime statistics for diagnosing CPU and memory
+// problems in production. It exposes internals, so mount it only behind AdminAuth or
+// on a loopback-only listener (DEBUG_ENDPOINTS):
+// - GET /debug/pprof/: profile index; e.g. `go tool pprof http://127.0.0.1:6060/debug/pprof/heap`
+// - GET /debug/pprof/profile?seconds=30: CPU profile; /debug/pprof/trace: execution trace
+// - GET /debug/runtime: goroutine, memory and GC figures as JSON
+//
+// Paths are fixed; when mounting below a prefix, strip it first (http.StripPrefix).
+func Debug() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/runtime", runtimeStats)
+	return mux
+}
+
+// runtimeStats answers a snapshot of the runtime; byte counts are in bytes, pauses in ms.
+func runtimeStats(w http.ResponseWriter, _ *http.Request) {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	var lastGC *time.Time
+	if m.LastGC > 0 {
+		t := time.Unix(0, int64(m.LastGC)).UTC()
+		lastGC = &t
+	}
+	body := map[string]any{
+		"version":        platform.Version,
+		"commit":         platform.Commit,
+		"go_version":     runtime.Version(),
+		"uptime_seconds": int64(time.Since(platform.StartedAt).Seconds()),
+		"goroutines":     runtime.NumGoroutine(),
+		"gomaxprocs":     runtime.GOMAXPROCS(0),
+		"num_cpu":        runtime.NumCPU(),
+		"memory": map[string]uint64{
+			"heap_alloc":    m.HeapAlloc,
+			"heap_inuse":    m.HeapInuse,
+			"heap_idle":     m.HeapIdle,
+			"heap_released": m.HeapReleased,
+			"heap_objects":  m.HeapObjects,
+			"stack_inuse":   m.StackInuse,
+			"sys":           m.Sys,
+			"total_alloc":   m.TotalAlloc,
+			"mallocs":       m.Mallocs,
+			"frees":         m.Frees,
+		},
+		"gc": map[string]any{
+			"num_gc":         m.NumGC,
+			"last_gc":        lastGC,
+			"pause_total_ms": float64(m.PauseTotalNs) / 1e6,
+			"last_pause_ms":  float64(m.PauseNs[(m.NumGC+255)%256]) / 1e6,
+			"next_gc":        m.NextGC,
+			"cpu_fraction":   m.GCCPUFraction,
+		},
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(body)
+}
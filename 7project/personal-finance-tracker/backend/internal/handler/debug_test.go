@@ -0,0 +1,55 @@
+// backend/internal/handler/debug_test.go
+//
+// Purpose:
+//   Verify that the debug handler serves the pprof index and runtime stats when
+//   mounted below the admin prefix, behind AdminAuth.
+
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDebug(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	admin := r.Group("/api/admin", handler.AdminAuth("0123456789abcdef"))
+	admin.GET("/debug/*path", gin.WrapH(http.StripPrefix("/api/admin", handler.Debug())))
+
+	get := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("/api/admin/debug/pprof/", ""); w.Code != http.StatusUnauthorized {
+		t.Fatalf("without token: got %d", w.Code)
+	}
+	if w := get("/api/admin/debug/pprof/", "0123456789abcdef"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "goroutine") {
+		t.Fatalf("pprof index: got %d %s", w.Code, w.Body.String())
+	}
+	if w := get("/api/admin/debug/pprof/heap?debug=1", "0123456789abcdef"); w.Code != http.StatusOK {
+		t.Fatalf("heap profile: got %d", w.Code)
+	}
+
+	w := get("/api/admin/debug/runtime", "0123456789abcdef")
+	var stats struct {
+		Goroutines int               `json:"goroutines"`
+		Memory     map[string]uint64 `json:"memory"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil || stats.Goroutines == 0 || stats.Memory["heap_alloc"] == 0 {
+		t.Fatalf("runtime stats: %d %s (%v)", w.Code, w.Body.String(), err)
+	}
+}
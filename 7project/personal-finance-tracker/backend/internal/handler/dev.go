@@ -0,0 +1,51 @@
+// backend/internal/handler/dev.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"pft/internal/seed"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SeedDemoData fills the current user's account with demo categories, budgets and
+// transactions. Development only: the route is registered when DEV_ENDPOINTS is enabled.
+// Query:
+// - months: history length, 1..36 (default 12)
+// - seed: random seed (default 1)
+// - scale: multiplier of day-to-day expenses, above 0 and at most 20 (default 1)
+// - mix: per-category multipliers, e.g. "Dining Out=2,Shopping=0.5"
+// - seasonal: "true" varies spending by month
+func (api *API) SeedDemoData(c *gin.Context) {
+	userID := MustUserID(c)
+	months, err := strconv.Atoi(c.DefaultQuery("months", "12"))
+	if err != nil || months < 1 || months > 36 {
+		problem(c, http.StatusBadRequest, "invalid_months")
+		return
+	}
+	rngSeed, err := strconv.ParseInt(c.DefaultQuery("seed", "1"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid")
+		return
+	}
+	scale, err := strconv.ParseFloat(c.DefaultQuery("scale", "1"), 64)
+	if err != nil || scale <= 0 || scale > 20 {
+		problem(c, http.StatusBadRequest, "invalid_scale")
+		return
+	}
+	mix, err := seed.ParseMix(c.Query("mix"))
+	if err != nil {
+		problemDetail(c, http.StatusBadRequest, "invalid_mix", err.Error())
+		return
+	}
+	opt := seed.Options{Months: months, Seed: rngSeed, Scale: scale, Mix: mix, Seasonal: c.Query("seasonal") == "true"}
+	res, err := seed.Apply(c.Request.Context(), api.Repos, userID, seed.Plan(opt))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, res)
+}
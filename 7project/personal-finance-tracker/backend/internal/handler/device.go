@@ -0,0 +1,196 @@
+// backend/internal/handler/device.go
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"pft/internal/alerts"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// deviceReq registers a device for push notifications.
+// - Platform: "fcm" for Android and web (Firebase), "apns" for iOS
+// - Token: the registration token the app obtained from the platform
+// - Name: optional label shown in device lists, e.g. "Pixel 8"
+type deviceReq struct {
+	Platform string `json:"platform" binding:"required,oneof=fcm apns"`
+	Token    string `json:"token" binding:"required,min=8,max=4096"`
+	Name     string `json:"name" binding:"max=100"`
+}
+
+// ListDevices returns the user's registered push devices.
+func (api *API) ListDevices(c *gin.Context) {
+	out, err := api.Repos.DeviceRepo().List(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// RegisterDevice stores a push device, or refreshes it if the token is already
+// registered. Apps call it on every start and whenever their token rotates.
+func (api *API) RegisterDevice(c *gin.Context) {
+	var req deviceReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	out, err := api.Repos.DeviceRepo().Register(c.Request.Context(), &repo.Device{
+		UserID: MustUserID(c), Platform: req.Platform, Token: req.Token, Name: req.Name,
+	})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteDevice unregisters a device identified by :id; apps call it on sign-out.
+func (api *API) DeleteDevice(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.DeviceRepo().Delete(c.Request.Context(), MustUserID(c), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetNotificationPrefs returns, for every alert event, which channels deliver it:
+// {"budget_alert": {"inapp": true, "email": false, "push": true, ...}, "bill_reminder": {...}, ...}.
+func (api *API) GetNotificationPrefs(c *gin.Context) {
+	stored, err := api.Repos.AlertRepo().NotificationPrefs(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, notificationMatrix(stored))
+}
+
+// UpdateNotificationPrefs switches channels on or off per event; events and
+// channels left out keep their setting. Answers with the full matrix.
+func (api *API) UpdateNotificationPrefs(c *gin.Context) {
+	var req map[string]map[string]bool
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	for event, channels := range req {
+		if !slices.Contains(alerts.Events, event) {
+			problemDetail(c, http.StatusBadRequest, "invalid_notification_preference",
+				"event must be one of: "+strings.Join(alerts.Events, ", ")+".")
+			return
+		}
+		for channel := range channels {
+			if !slices.Contains(alerts.Channels, channel) {
+				problemDetail(c, http.StatusBadRequest, "invalid_notification_preference",
+					"channel must be one of: "+strings.Join(alerts.Channels, ", ")+".")
+				return
+			}
+		}
+	}
+	userID := MustUserID(c)
+	ctx := c.Request.Context()
+	if err := api.Repos.AlertRepo().SetNotificationPrefs(ctx, userID, req); err != nil {
+		fail(c, err)
+		return
+	}
+	stored, err := api.Repos.AlertRepo().NotificationPrefs(ctx, userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, notificationMatrix(stored))
+}
+
+// notificationMatrix fills in the defaults for choices not stored.
+func notificationMatrix(stored map[string]map[string]bool) map[string]map[string]bool {
+	out := make(map[string]map[string]bool, len(alerts.Events))
+	for _, e := range alerts.Events {
+		out[e] = make(map[string]bool, len(alerts.Channels))
+		for _, ch := range alerts.Channels {
+			on, ok := stored[e][ch]
+			if !ok {
+				on = alerts.DefaultEnabled(e, ch)
+			}
+			out[e][ch] = on
+		}
+	}
+	return out
+}
+
+// quietHoursReq sets the daily window during which alerts are only filed in the app;
+// other channels deliver them when it ends.
+// - Enabled: false removes the quiet hours (the other fields are then ignored)
+// - Start/End: local times as HH:MM; the window may wrap midnight (22:00 to 07:00)
+// - Timezone: IANA time zone the times are in, e.g. "Europe/Berlin" (default UTC)
+type quietHoursReq struct {
+	Enabled  bool   `json:"enabled"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone" binding:"max=64"`
+}
+
+// quietHoursJSON renders stored quiet hours; nil means none.
+func quietHoursJSON(q *repo.QuietHours) quietHoursReq {
+	if q == nil {
+		return quietHoursReq{Timezone: "UTC"}
+	}
+	clock := func(m int) string { return fmt.Sprintf("%02d:%02d", m/60, m%60) }
+	return quietHoursReq{Enabled: true, Start: clock(q.Start), End: clock(q.End), Timezone: q.Timezone}
+}
+
+// GetQuietHours returns the user's quiet hours:
+// {"enabled": true, "start": "22:00", "end": "07:00", "timezone": "Europe/Berlin"}.
+func (api *API) GetQuietHours(c *gin.Context) {
+	q, err := api.Repos.AlertRepo().QuietHours(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, quietHoursJSON(q))
+}
+
+// UpdateQuietHours sets or removes the user's quiet hours and answers with them.
+func (api *API) UpdateQuietHours(c *gin.Context) {
+	var req quietHoursReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	var q *repo.QuietHours
+	if req.Enabled {
+		start, err1 := time.Parse("15:04", req.Start)
+		end, err2 := time.Parse("15:04", req.End)
+		if err1 != nil || err2 != nil || start.Equal(end) {
+			problemDetail(c, http.StatusBadRequest, "invalid_quiet_hours", "start and end must be different times as HH:MM.")
+			return
+		}
+		if req.Timezone == "" {
+			req.Timezone = "UTC"
+		}
+		if _, err := time.LoadLocation(req.Timezone); err != nil || req.Timezone == "Local" {
+			problemDetail(c, http.StatusBadRequest, "invalid_timezone", "timezone must be an IANA time zone name, e.g. Europe/Berlin.")
+			return
+		}
+		q = &repo.QuietHours{Start: start.Hour()*60 + start.Minute(), End: end.Hour()*60 + end.Minute(), Timezone: req.Timezone}
+	}
+	if err := api.Repos.AlertRepo().SetQuietHours(c.Request.Context(), MustUserID(c), q); err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, quietHoursJSON(q))
+}
@@ -0,0 +1,525 @@
+// backend/internal/handler/docs.go
+
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"pft/internal/apidoc"
+	"pft/internal/backup"
+	"pft/internal/jobs"
+	"pft/internal/repo"
+	"pft/internal/seed"
+	"pft/internal/telegram"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// The query parameters below mirror what the handlers read with c.Query; the types
+// exist only to describe them in the API document.
+
+type monthQuery struct {
+	Month string `form:"month" doc:"YYYY-MM"`
+}
+
+type displayQuery struct {
+	DisplayCurrency string `form:"display_currency" doc:"ISO 4217 code to convert amounts to (see displayConversion)"`
+}
+
+type pageQuery struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+}
+
+type auditQuery struct {
+	Entity   string `form:"entity"`
+	EntityID string `form:"entity_id"`
+	Action   string `form:"action" binding:"omitempty,oneof=create update delete"`
+	Actor    string `form:"actor"`
+	Since    string `form:"since" doc:"RFC 3339 timestamp or YYYY-MM-DD"`
+	Until    string `form:"until" doc:"RFC 3339 timestamp or YYYY-MM-DD"`
+	pageQuery
+}
+
+// Response bodies the handlers build with gin.H.
+
+type authResp struct {
+	ID    int64  `json:"id"`
+	Token string `json:"token"`
+}
+
+type readyResp struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type healthResp struct {
+	OK            bool   `json:"ok"`
+	Status        string `json:"status" doc:"ok or degraded"`
+	Version       string `json:"version"`
+	Commit        string `json:"commit"`
+	UptimeSeconds int64  `json:"uptime_seconds"`
+	Database      any    `json:"database"`
+	Migration     any    `json:"migration"`
+	Queue         any    `json:"queue"`
+}
+
+type versionResp struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+}
+
+type usageResp struct {
+	From  string          `json:"from"`
+	To    string          `json:"to"`
+	Total repo.Usage      `json:"total"`
+	Days  []repo.UsageDay `json:"days"`
+}
+
+type adminUsageResp struct {
+	From  string           `json:"from"`
+	To    string           `json:"to"`
+	Total repo.Usage       `json:"total"`
+	Users []repo.UserUsage `json:"users"`
+}
+
+type layoutResp struct {
+	Widgets []repo.Widget `json:"widgets"`
+}
+
+type tokenForm struct {
+	GrantType    string `form:"grant_type" binding:"required,oneof=authorization_code refresh_token"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	ClientID     string `form:"client_id" doc:"unless sent with HTTP Basic"`
+	ClientSecret string `form:"client_secret" doc:"unless sent with HTTP Basic"`
+}
+
+type tokenResp struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	Scope        string `json:"scope"`
+}
+
+type retentionPoliciesResp struct {
+	Policies         []repo.RetentionPolicy `json:"policies"`
+	OperatorPolicies []repo.RetentionPolicy `json:"operator_policies"`
+}
+
+// Docs describes the request and response of each route for the API document,
+// keyed like routeScopes. Routes missing here are documented without payloads.
+var Docs = map[string]apidoc.Doc{
+	// Public
+	"GET /api/healthz":      {Response: healthResp{}},
+	"GET /api/readyz":       {Response: readyResp{}},
+	"GET /api/version":      {Response: versionResp{}},
+	"GET /metrics":          {Summary: "Prometheus metrics"},
+	"POST /api/register":    {Body: registerReq{}, Response: authResp{}},
+	"POST /api/login":       {Body: loginReq{}, Response: authResp{}},
+	"POST /api/oauth/token": {Form: tokenForm{}, Response: tokenResp{}},
+	"GET /api/exports/:id/download": {Summary: "Download export", Query: struct {
+		Expires string `form:"expires" binding:"required"`
+		Sig     string `form:"sig" binding:"required"`
+	}{}},
+	"POST /api/telegram/webhook": {Body: telegram.Update{}},
+	"POST /api/mail/inbound": {Summary: "Inbound email", Body: postmarkInbound{}, Query: struct {
+		Secret string `form:"secret" doc:"unless sent as the basic auth password"`
+	}{}, Response: struct {
+		Stored bool   `json:"stored"`
+		Reason string `json:"reason,omitempty"`
+	}{}},
+
+	// Zapier
+	"GET /api/zapier/me": {Response: struct {
+		ID    int64  `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}{}},
+	"GET /api/zapier/triggers/new-transaction": {Response: []repo.Transaction{}, Query: struct {
+		Limit   int   `form:"limit" doc:"default and maximum 100"`
+		SinceID int64 `form:"since_id"`
+	}{}},
+	"GET /api/zapier/triggers/budget-exceeded": {Query: monthQuery{}, Response: []budgetExceeded{}},
+
+	// Account settings
+	"GET /api/me": {Response: struct {
+		ID int64 `json:"id"`
+	}{}},
+	"GET /api/me/preferences": {Response: preferencesDTO{}},
+	"PUT /api/me/preferences": {Body: preferencesDTO{}, Response: preferencesDTO{}},
+	"GET /api/me/features":    {Response: map[string]bool{}},
+	"GET /api/me/usage": {Query: struct {
+		Days int `form:"days"`
+	}{}, Response: usageResp{}},
+	"GET /api/me/notifications":             {Response: map[string]map[string]bool{}},
+	"PUT /api/me/notifications":             {Body: map[string]map[string]bool{}, Response: map[string]map[string]bool{}},
+	"GET /api/me/notifications/quiet-hours": {Response: quietHoursReq{}},
+	"PUT /api/me/notifications/quiet-hours": {Body: quietHoursReq{}, Response: quietHoursReq{}},
+	"GET /api/devices":                      {Response: []repo.Device{}},
+	"POST /api/devices":                     {Body: deviceReq{}, Response: repo.Device{}},
+	"DELETE /api/devices/:id":               {Status: http.StatusNoContent},
+	"GET /api/me/telegram": {Response: struct {
+		Enabled  bool       `json:"enabled"`
+		Linked   bool       `json:"linked"`
+		Username string     `json:"username,omitempty"`
+		LinkedAt *time.Time `json:"linked_at,omitempty"`
+	}{}},
+	"POST /api/me/telegram/link": {Status: http.StatusCreated, Response: struct {
+		Code      string    `json:"code"`
+		URL       string    `json:"url"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}{}},
+	"DELETE /api/me/telegram": {Status: http.StatusNoContent},
+	"GET /api/me/api-keys":    {Response: []repo.APIKey{}},
+	"POST /api/me/api-keys": {Body: createAPIKeyReq{}, Status: http.StatusCreated, Response: struct {
+		*repo.APIKey
+		Key string `json:"key"`
+	}{}},
+	"DELETE /api/me/api-keys/:id": {Status: http.StatusNoContent},
+	"GET /api/me/oauth/clients":   {Response: []repo.OAuthClient{}},
+	"POST /api/me/oauth/clients": {Body: createOAuthClientReq{}, Status: http.StatusCreated, Response: struct {
+		*repo.OAuthClient
+		Secret string `json:"client_secret"`
+	}{}},
+	"DELETE /api/me/oauth/clients/:id":       {Status: http.StatusNoContent},
+	"GET /api/me/oauth/grants":               {Response: []repo.OAuthGrant{}},
+	"DELETE /api/me/oauth/grants/:client_id": {Status: http.StatusNoContent},
+	"GET /api/oauth/authorize": {Query: authorizeReq{}, Response: struct {
+		Client struct {
+			ClientID string `json:"client_id"`
+			Name     string `json:"name"`
+		} `json:"client"`
+		RedirectURI string   `json:"redirect_uri"`
+		Scopes      []string `json:"scopes"`
+		State       string   `json:"state"`
+	}{}},
+	"POST /api/oauth/authorize": {Body: authorizeReq{}, Response: struct {
+		RedirectURI string `json:"redirect_uri"`
+	}{}},
+	"GET /api/me/ingest-address": {Response: struct {
+		Enabled bool    `json:"enabled"`
+		Address *string `json:"address"`
+	}{}},
+	"POST /api/me/ingest-address": {Status: http.StatusCreated, Response: struct {
+		Address string `json:"address"`
+	}{}},
+
+	// Notifications
+	"GET /api/notifications": {Response: []repo.Notification{}, Query: struct {
+		Unread bool   `form:"unread"`
+		Event  string `form:"event"`
+		pageQuery
+	}{}},
+	"GET /api/notifications/unread": {Response: struct {
+		Total  int            `json:"total"`
+		Events map[string]int `json:"events"`
+	}{}},
+	"PATCH /api/notifications": {Body: notificationsReadReq{}, Response: struct {
+		Updated int64 `json:"updated"`
+	}{}},
+	"PATCH /api/notifications/:id": {Body: notificationReadReq{}, Response: repo.Notification{}},
+
+	// Integrations
+	"GET /api/chat-webhooks":           {Response: []repo.ChatWebhook{}},
+	"POST /api/chat-webhooks":          {Body: chatWebhookReq{}, Status: http.StatusCreated, Response: repo.ChatWebhook{}},
+	"PUT /api/chat-webhooks/:id":       {Body: chatWebhookReq{}, Response: repo.ChatWebhook{}},
+	"DELETE /api/chat-webhooks/:id":    {Status: http.StatusNoContent},
+	"POST /api/chat-webhooks/:id/test": {Status: http.StatusNoContent},
+	"GET /api/webhooks":                {Response: []repo.WebhookEndpoint{}},
+	"POST /api/webhooks": {Body: webhookReq{}, Status: http.StatusCreated, Response: struct {
+		*repo.WebhookEndpoint
+		Secret string `json:"secret"`
+	}{}},
+	"GET /api/webhooks/dead-letters": {Query: pageQuery{}, Response: []repo.WebhookDelivery{}},
+	"PUT /api/webhooks/:id":          {Body: webhookReq{}, Response: repo.WebhookEndpoint{}},
+	"DELETE /api/webhooks/:id":       {Status: http.StatusNoContent},
+	"GET /api/webhooks/:id/deliveries": {Response: []repo.WebhookDelivery{}, Query: struct {
+		Status string `form:"status" binding:"omitempty,oneof=pending delivered dead"`
+		pageQuery
+	}{}},
+	"POST /api/webhooks/:id/deliveries/:delivery_id/replay": {Status: http.StatusAccepted},
+
+	// Audit and retention
+	"GET /api/audit":                         {Query: auditQuery{}, Response: []repo.AuditEntry{}},
+	"GET /api/retention-policies":            {Response: retentionPoliciesResp{}},
+	"GET /api/retention-policies/preview":    {Response: []repo.RetentionResult{}},
+	"PUT /api/retention-policies/:target":    {Body: retentionReq{}, Response: repo.RetentionPolicy{}},
+	"DELETE /api/retention-policies/:target": {Status: http.StatusNoContent},
+
+	// Categories
+	"GET /api/categories": {Response: []repo.Category{}, Query: struct {
+		Deleted bool   `form:"deleted"`
+		Include string `form:"include" doc:"usage: add each category's usage"`
+	}{}},
+	"POST /api/categories":             {Body: categoryCreateReq{}, Status: http.StatusCreated, Response: repo.Category{}},
+	"PUT /api/categories/:id":          {Body: categoryUpdateReq{}, Response: repo.Category{}},
+	"DELETE /api/categories/:id":       {Status: http.StatusNoContent},
+	"POST /api/categories/:id/restore": {Response: repo.Category{}},
+	"GET /api/category-groups":         {Response: []repo.CategoryGroup{}},
+	"POST /api/category-groups":        {Body: categoryGroupReq{}, Status: http.StatusCreated, Response: repo.CategoryGroup{}},
+	"PUT /api/category-groups/:id":     {Body: categoryGroupReq{}, Response: repo.CategoryGroup{}},
+	"DELETE /api/category-groups/:id":  {Status: http.StatusNoContent},
+
+	// Batch and sync
+	"POST /api/batch": {Body: batchReq{}, Response: struct {
+		Results   []batchResult `json:"results"`
+		Committed *bool         `json:"committed,omitempty"`
+	}{}},
+	"GET /api/sync": {Query: struct {
+		Since string `form:"since" doc:"sync token from the previous response's next"`
+		Limit int    `form:"limit"`
+	}{}, Response: struct {
+		Changes []repo.SyncChange `json:"changes"`
+		Next    string            `json:"next"`
+		HasMore bool              `json:"has_more"`
+	}{}},
+	"POST /api/sync": {Body: syncWriteReq{}, Response: struct {
+		Results []syncWriteResult `json:"results"`
+	}{}},
+
+	// Transactions
+	"GET /api/transactions": {Response: []repo.Transaction{}, Query: struct {
+		From         string `form:"from" doc:"YYYY-MM-DD"`
+		To           string `form:"to" doc:"YYYY-MM-DD"`
+		Type         string `form:"type" binding:"omitempty,oneof=income expense"`
+		CategoryID   int64  `form:"category_id"`
+		Reimbursable bool   `form:"reimbursable"`
+		pageQuery
+	}{}},
+	"POST /api/transactions":       {Body: txnCreateReq{}, Status: http.StatusCreated, Response: repo.Transaction{}},
+	"POST /api/transactions/parse": {Body: parseTxnReq{}, Response: parsedTxn{}},
+	"PUT /api/transactions/:id":    {Body: txnUpdateReq{}, Response: repo.Transaction{}},
+	"DELETE /api/transactions/:id": {Status: http.StatusNoContent},
+	"GET /api/accounts":            {Response: []repo.Account{}},
+	"POST /api/accounts":           {Body: accountReq{}, Status: http.StatusCreated, Response: repo.Account{}},
+	"PUT /api/accounts/:id":        {Body: accountReq{}, Response: repo.Account{}},
+	"DELETE /api/accounts/:id":     {Status: http.StatusNoContent},
+	"GET /api/accounts/:id/statement": {Response: repo.Statement{}, Query: struct {
+		From string `form:"from" doc:"YYYY-MM-DD, default the first day of to's month"`
+		To   string `form:"to" doc:"YYYY-MM-DD, default today"`
+		displayQuery
+	}{}},
+	"GET /api/drafts": {Query: struct {
+		Source string `form:"source"`
+	}{}, Response: []repo.Draft{}},
+	"PUT /api/drafts/:id":          {Body: draftReq{}, Response: repo.Draft{}},
+	"POST /api/drafts/:id/approve": {Body: approveDraftReq{}, Status: http.StatusCreated, Response: repo.Transaction{}},
+	"POST /api/drafts/approve": {Body: approveDraftsReq{}, Response: struct {
+		Approved []*repo.Transaction `json:"approved"`
+		Failed   []draftFailure      `json:"failed"`
+	}{}},
+	"DELETE /api/drafts/:id":       {Status: http.StatusNoContent},
+	"POST /api/imports":            {Body: importReq{}, Status: http.StatusAccepted, Response: repo.Import{}},
+	"GET /api/imports/:id":         {Response: repo.Import{}},
+	"POST /api/imports/:id/cancel": {Response: repo.Import{}},
+	"POST /api/exports":            {Body: exportReq{}, Status: http.StatusAccepted, Response: exportResp{}},
+	"GET /api/exports/:id":         {Response: exportResp{}},
+
+	// Splits
+	"GET /api/contacts":                  {Response: []repo.Contact{}},
+	"POST /api/contacts":                 {Body: contactReq{}, Status: http.StatusCreated, Response: repo.Contact{}},
+	"DELETE /api/contacts/:id":           {Status: http.StatusNoContent},
+	"GET /api/transactions/:id/split":    {Response: repo.Split{}},
+	"PUT /api/transactions/:id/split":    {Body: splitReq{}, Response: repo.Split{}},
+	"DELETE /api/transactions/:id/split": {Status: http.StatusNoContent},
+	"GET /api/splits/balances": {Response: struct {
+		Balances    []repo.ContactBalance `json:"balances"`
+		Suggestions []repo.Transfer       `json:"suggestions"`
+	}{}},
+	"POST /api/settlements": {Body: settlementReq{}, Status: http.StatusCreated, Response: repo.Settlement{}},
+
+	// Budgets and periods
+	"GET /api/budgets": {Response: []repo.Budget{}, Query: struct {
+		Month   string `form:"month" doc:"YYYY-MM, required unless deleted=true"`
+		Deleted bool   `form:"deleted"`
+	}{}},
+	"POST /api/budgets":               {Body: budgetCreateReq{}, Status: http.StatusCreated, Response: repo.Budget{}},
+	"PUT /api/budgets/:id":            {Body: budgetUpdateReq{}, Response: repo.Budget{}},
+	"DELETE /api/budgets/:id":         {Status: http.StatusNoContent},
+	"POST /api/budgets/:id/restore":   {Response: repo.Budget{}},
+	"GET /api/periods/closed":         {Response: []repo.ClosedPeriod{}},
+	"POST /api/periods/:month/close":  {Response: repo.ClosedPeriod{}},
+	"POST /api/periods/:month/reopen": {Status: http.StatusNoContent},
+
+	// Loans
+	"GET /api/loans":        {Response: []repo.Loan{}},
+	"POST /api/loans":       {Body: loanReq{}, Status: http.StatusCreated, Response: repo.Loan{}},
+	"PUT /api/loans/:id":    {Body: loanReq{}, Response: repo.Loan{}},
+	"DELETE /api/loans/:id": {Status: http.StatusNoContent},
+	"GET /api/loans/:id/schedule": {Response: struct {
+		MonthlyPayment float64            `json:"monthly_payment"`
+		Installments   []repo.Installment `json:"installments"`
+	}{}},
+	"GET /api/loans/:id/payoff":            {Response: repo.Payoff{}},
+	"GET /api/loans/:id/payments":          {Response: []repo.Transaction{}},
+	"POST /api/loans/:id/payments":         {Body: loanPaymentReq{}, Status: http.StatusNoContent},
+	"DELETE /api/loans/:id/payments/:txid": {Status: http.StatusNoContent},
+
+	// Bills, subscriptions, wishlist
+	"GET /api/bills": {Response: []repo.Bill{}},
+	"GET /api/bills/upcoming": {Query: struct {
+		Days int `form:"days" doc:"default 30"`
+	}{}, Response: []repo.UpcomingBill{}},
+	"POST /api/bills":       {Body: billReq{}, Status: http.StatusCreated, Response: repo.Bill{}},
+	"PUT /api/bills/:id":    {Body: billReq{}, Response: repo.Bill{}},
+	"DELETE /api/bills/:id": {Status: http.StatusNoContent},
+	"GET /api/subscriptions": {Response: struct {
+		Subscriptions []repo.Subscription `json:"subscriptions"`
+		MonthlyTotal  float64             `json:"monthly_total"`
+	}{}},
+	"POST /api/subscriptions":             {Body: subscriptionReq{}, Status: http.StatusCreated, Response: repo.Subscription{}},
+	"POST /api/subscriptions/detect":      {Response: []repo.Subscription{}},
+	"PUT /api/subscriptions/:id":          {Body: subscriptionReq{}, Response: repo.Subscription{}},
+	"DELETE /api/subscriptions/:id":       {Status: http.StatusNoContent},
+	"GET /api/wishlist":                   {Response: []repo.WishlistItem{}},
+	"POST /api/wishlist":                  {Body: wishlistReq{}, Status: http.StatusCreated, Response: repo.WishlistItem{}},
+	"PUT /api/wishlist/:id":               {Body: wishlistReq{}, Response: repo.WishlistItem{}},
+	"DELETE /api/wishlist/:id":            {Status: http.StatusNoContent},
+	"GET /api/wishlist/:id/affordability": {Query: monthQuery{}, Response: repo.Affordability{}},
+	"POST /api/wishlist/:id/purchase":     {Body: purchaseReq{}, Response: repo.WishlistItem{}},
+
+	// Income, recurring rules, emergency fund, claims
+	"GET /api/income-sources":        {Response: []repo.IncomeSource{}},
+	"POST /api/income-sources":       {Body: incomeSourceReq{}, Status: http.StatusCreated, Response: repo.IncomeSource{}},
+	"PUT /api/income-sources/:id":    {Body: incomeSourceReq{}, Response: repo.IncomeSource{}},
+	"DELETE /api/income-sources/:id": {Status: http.StatusNoContent},
+	"GET /api/income/projection": {Query: struct {
+		Months int `form:"months" doc:"default 6, 1..24"`
+		displayQuery
+	}{}, Response: repo.IncomeProjection{}},
+	"GET /api/search": {Response: repo.SearchResults{}, Query: struct {
+		Q     string `form:"q" binding:"required"`
+		Limit int    `form:"limit" doc:"default 20, at most 50"`
+	}{}},
+	"GET /api/recurring":        {Response: []repo.RecurringRule{}},
+	"POST /api/recurring":       {Body: recurringRuleReq{}, Status: http.StatusCreated, Response: repo.RecurringRule{}},
+	"PUT /api/recurring/:id":    {Body: recurringRuleReq{}, Response: repo.RecurringRule{}},
+	"DELETE /api/recurring/:id": {Status: http.StatusNoContent},
+	"GET /api/recurring/:id/preview": {Query: struct {
+		Until string `form:"until" doc:"YYYY-MM-DD, default three months from today"`
+	}{}, Response: repo.RulePreview{}},
+	"GET /api/emergency-fund":                 {Response: repo.EmergencyFund{}},
+	"PUT /api/emergency-fund":                 {Body: fundTargetReq{}, Response: repo.EmergencyFund{}},
+	"POST /api/emergency-fund/accounts":       {Body: fundAccountReq{}, Status: http.StatusCreated, Response: repo.FundAccount{}},
+	"PUT /api/emergency-fund/accounts/:id":    {Body: fundAccountReq{}, Response: repo.FundAccount{}},
+	"DELETE /api/emergency-fund/accounts/:id": {Status: http.StatusNoContent},
+	"GET /api/claims":                         {Response: []repo.Claim{}},
+	"POST /api/claims":                        {Body: claimCreateReq{}, Status: http.StatusCreated, Response: repo.Claim{}},
+	"GET /api/claims/:id":                     {Response: repo.Claim{}},
+	"DELETE /api/claims/:id":                  {Status: http.StatusNoContent},
+	"PUT /api/claims/:id/status":              {Body: claimStatusReq{}, Response: repo.Claim{}},
+	"POST /api/claims/:id/items":              {Body: claimItemsReq{}, Response: repo.Claim{}},
+	"DELETE /api/claims/:id/items/:txid":      {Status: http.StatusNoContent},
+
+	// Dashboard and reports
+	"GET /api/dashboard/summary": {Query: struct {
+		Month string `form:"month" binding:"required" doc:"YYYY-MM"`
+		displayQuery
+	}{}, Response: repo.MonthSummary{}},
+	"GET /api/dashboard/summary/week": {Query: struct {
+		Week string `form:"week" doc:"ISO week YYYY-Www"`
+		Date string `form:"date" doc:"YYYY-MM-DD, any day of the week"`
+		displayQuery
+	}{}, Response: repo.WeekSummary{}},
+	"GET /api/dashboard/daily": {Query: struct {
+		Month string `form:"month" binding:"required" doc:"YYYY-MM"`
+		displayQuery
+	}{}, Response: []repo.DailySpend{}},
+	"GET /api/dashboard/top": {Query: struct {
+		Month string `form:"month" binding:"required" doc:"YYYY-MM"`
+		N     int    `form:"n" doc:"default 10, 1..100"`
+		displayQuery
+	}{}, Response: []repo.TopExpense{}},
+	"GET /api/dashboard/projection": {Query: struct {
+		monthQuery
+		displayQuery
+	}{}, Response: repo.Projection{}},
+	"GET /api/dashboard/score":           {Query: monthQuery{}, Response: repo.HealthScore{}},
+	"GET /api/dashboard/score/breakdown": {Query: monthQuery{}, Response: repo.HealthScore{}},
+	"GET /api/dashboard/layout":          {Response: layoutResp{}},
+	"PUT /api/dashboard/layout":          {Body: layoutReq{}, Response: layoutResp{}},
+	"GET /api/reports/compare": {Query: struct {
+		A string `form:"a" binding:"required" doc:"YYYY-MM"`
+		B string `form:"b" binding:"required" doc:"YYYY-MM"`
+		displayQuery
+	}{}, Response: repo.PeriodComparison{}},
+	"GET /api/reports/recurring": {Query: struct {
+		Months int `form:"months" doc:"default 12, 3..36"`
+		displayQuery
+	}{}, Response: []repo.RecurringCharge{}},
+	"GET /api/reports/yoy": {Query: struct {
+		Year string `form:"year" doc:"YYYY, default the current year"`
+		displayQuery
+	}{}, Response: repo.YearOverYear{}},
+	"GET /api/reports/averages": {Query: struct {
+		Months int `form:"months" doc:"default 6, 1..36"`
+		displayQuery
+	}{}, Response: repo.SpendAverages{}},
+	"GET /api/reports/flows": {Query: struct {
+		Month string `form:"month" binding:"required" doc:"YYYY-MM"`
+		displayQuery
+	}{}, Response: repo.Flows{}},
+	"GET /api/reports/tax": {Query: struct {
+		Year   string `form:"year" doc:"YYYY, default the current year"`
+		Format string `form:"format" binding:"omitempty,oneof=csv" doc:"csv downloads the report instead"`
+		displayQuery
+	}{}, Response: repo.TaxReport{}},
+	"GET /api/rates": {Query: struct {
+		Base string `form:"base" doc:"ISO 4217, default EUR"`
+		Date string `form:"date" doc:"YYYY-MM-DD, default today"`
+	}{}, Response: repo.RateTable{}},
+	"POST /api/graphql": {Body: graphqlReq{}, Response: graphql.Response{}},
+	"POST /api/dev/seed": {Status: http.StatusCreated, Response: seed.Result{}, Query: struct {
+		Months   int     `form:"months" doc:"1..36, default 12"`
+		Seed     int64   `form:"seed" doc:"default 1"`
+		Scale    float64 `form:"scale" doc:"default 1, at most 20"`
+		Mix      string  `form:"mix" doc:"per-category multipliers, e.g. Dining Out=2,Shopping=0.5"`
+		Seasonal bool    `form:"seasonal"`
+	}{}},
+
+	// Admin
+	"GET /api/admin/audit": {Query: struct {
+		auditQuery
+		UserID int64 `form:"user_id"`
+	}{}, Response: []repo.AuditEntry{}},
+	"GET /api/admin/backups": {Response: []backup.Info{}},
+	"POST /api/admin/backups": {Status: http.StatusCreated, Response: backup.Info{}, Query: struct {
+		UserID int64 `form:"user_id" doc:"back up only this user"`
+	}{}},
+	"GET /api/admin/backups/:name": {Summary: "Download backup"},
+	"POST /api/admin/backups/:name/restore": {Body: restoreRequest{}, Response: struct {
+		UserID int64            `json:"user_id"`
+		Rows   map[string]int64 `json:"rows"`
+	}{}},
+	"GET /api/admin/maintenance":   {Response: repo.Maintenance{}},
+	"PUT /api/admin/maintenance":   {Body: maintenanceRequest{}, Response: repo.Maintenance{}},
+	"GET /api/admin/flags":         {Response: []repo.Flag{}},
+	"PUT /api/admin/flags/:key":    {Body: flagRequest{}, Response: repo.Flag{}},
+	"DELETE /api/admin/flags/:key": {Status: http.StatusNoContent},
+	"PUT /api/admin/flags/:key/users/:user_id": {Status: http.StatusNoContent, Body: struct {
+		Enabled *bool `json:"enabled" binding:"required"`
+	}{}},
+	"DELETE /api/admin/flags/:key/users/:user_id": {Status: http.StatusNoContent},
+	"POST /api/admin/mail/test":                   {Body: testMailRequest{}, Status: http.StatusAccepted, Response: repo.Job{}},
+	"GET /api/admin/usage": {Query: struct {
+		Days  int `form:"days" doc:"default 30"`
+		Limit int `form:"limit" doc:"default 50, at most 500"`
+	}{}, Response: adminUsageResp{}},
+	"GET /api/admin/jobs": {Response: struct {
+		Schedules []jobs.ScheduleStatus `json:"schedules"`
+		Queue     *repo.JobStats        `json:"queue"`
+	}{}},
+	"GET /api/admin/retention":            {Response: []repo.RetentionPolicy{}},
+	"GET /api/admin/retention/preview":    {Response: []repo.RetentionResult{}},
+	"PUT /api/admin/retention/:target":    {Body: retentionReq{}, Response: repo.RetentionPolicy{}},
+	"DELETE /api/admin/retention/:target": {Status: http.StatusNoContent},
+	"POST /api/admin/rates/backfill":      {Body: rateBackfillReq{}, Status: http.StatusAccepted, Response: repo.Job{}},
+	"GET /api/admin/debug/*path":          {Summary: "Profiler and runtime statistics"},
+}
@@ -0,0 +1,158 @@
+// backend/internal/handler/draft.go
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The drafts inbox holds transactions created by email-in, imports made as drafts
+// and other integrations until the user approves, edits or discards them; drafts do
+// not count in balances or reports.
+
+// approveDraftReq optionally files the approved transaction under another category.
+type approveDraftReq struct {
+	CategoryID    *int64 `json:"category_id"`
+	OverrideLimit bool   `json:"override_limit"`
+}
+
+// draftReq edits a draft; the fields mirror a transaction's.
+type draftReq struct {
+	CategoryID  *int64  `json:"category_id"`
+	Type        string  `json:"type" binding:"required,oneof=income expense"`
+	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	Date        string  `json:"date" binding:"required"`
+	Description string  `json:"description" binding:"max=255"`
+}
+
+// approveDraftsReq approves several drafts at once.
+type approveDraftsReq struct {
+	IDs []int64 `json:"ids" binding:"required,min=1,max=500"`
+}
+
+// draftFailure is a draft that ApproveDrafts could not approve, with the reason's code.
+type draftFailure struct {
+	ID   int64  `json:"id"`
+	Code string `json:"code"`
+}
+
+// ListDrafts returns the user's draft transactions awaiting review, oldest first;
+// ?source= (e.g. "email", "import") limits them to one source.
+func (api *API) ListDrafts(c *gin.Context) {
+	out, err := api.Repos.DraftRepo().List(c.Request.Context(), MustUserID(c), c.Query("source"))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// UpdateDraft corrects draft :id (amount, date, type, category, description) before
+// it is approved.
+func (api *API) UpdateDraft(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req draftReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	d, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid_date")
+		return
+	}
+	out, err := api.Repos.DraftRepo().Update(c.Request.Context(), MustUserID(c), id, &repo.Draft{
+		CategoryID: req.CategoryID, Type: req.Type, Amount: req.Amount, Date: d, Description: req.Description,
+	})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ApproveDraft turns draft :id into a transaction and answers 201 with it. The body is
+// optional: {"category_id": N} overrides the draft's category. Responds with 409 when
+// the draft's month is closed; the draft is kept.
+func (api *API) ApproveDraft(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req approveDraftReq
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			invalidRequest(c, err)
+			return
+		}
+	}
+	out, err := api.Repos.ApproveDraft(c.Request.Context(), MustUserID(c), id, req.CategoryID, req.OverrideLimit)
+	if err != nil {
+		if errors.Is(err, repo.ErrPeriodClosed) {
+			problem(c, http.StatusConflict, "period_closed")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// ApproveDrafts approves the drafts listed in {"ids": [...]} one by one, for reviewing
+// an import in bulk. Answers 200 with {"approved": [transactions], "failed": [{"id",
+// "code"}]}; a draft fails with "not_found", "period_closed" or "category_limit_exceeded"
+// (approve it alone with override_limit) and is then kept.
+func (api *API) ApproveDrafts(c *gin.Context) {
+	var req approveDraftsReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	userID := MustUserID(c)
+	approved := []*repo.Transaction{}
+	failed := []draftFailure{}
+	for _, id := range req.IDs {
+		t, err := api.Repos.ApproveDraft(c.Request.Context(), userID, id, nil, false)
+		switch {
+		case errors.Is(err, repo.ErrPeriodClosed):
+			failed = append(failed, draftFailure{ID: id, Code: "period_closed"})
+		case errors.As(err, new(*repo.LimitError)):
+			failed = append(failed, draftFailure{ID: id, Code: "category_limit_exceeded"})
+		case err != nil:
+			fail(c, err)
+			return
+		case t == nil:
+			failed = append(failed, draftFailure{ID: id, Code: "not_found"})
+		default:
+			approved = append(approved, t)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"approved": approved, "failed": failed})
+}
+
+// DiscardDraft deletes draft :id without creating a transaction.
+func (api *API) DiscardDraft(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.DraftRepo().Delete(c.Request.Context(), MustUserID(c), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
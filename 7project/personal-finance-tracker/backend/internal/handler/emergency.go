@@ -0,0 +1,120 @@
+// backend/internal/handler/emergency.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fundTargetReq sets the emergency fund goal in months of average expenses.
+type fundTargetReq struct {
+	TargetMonths float64 `json:"target_months" binding:"required,gt=0,lte=60"`
+}
+
+// fundAccountReq is the payload for creating or updating a designated account.
+type fundAccountReq struct {
+	Name    string  `json:"name" binding:"required,min=1,max=100"`
+	Balance float64 `json:"balance" binding:"gte=0"`
+}
+
+// EmergencyFund reports current coverage of the emergency fund in months of
+// average expenses, alongside the target and the designated accounts.
+func (api *API) EmergencyFund(c *gin.Context) {
+	userID := MustUserID(c)
+	out, err := api.Repos.EmergencyFundRepo().Coverage(c.Request.Context(), userID, time.Now().UTC())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// SetEmergencyFundTarget updates the target and returns the recomputed coverage.
+func (api *API) SetEmergencyFundTarget(c *gin.Context) {
+	userID := MustUserID(c)
+	var req fundTargetReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	ok, err := api.Repos.EmergencyFundRepo().SetTarget(c.Request.Context(), userID, req.TargetMonths)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	api.EmergencyFund(c)
+}
+
+// CreateFundAccount designates an account for the emergency fund.
+func (api *API) CreateFundAccount(c *gin.Context) {
+	userID := MustUserID(c)
+	var req fundAccountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	out, err := api.Repos.EmergencyFundRepo().CreateAccount(c.Request.Context(), &repo.FundAccount{
+		UserID:  userID,
+		Name:    req.Name,
+		Balance: req.Balance,
+	})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// UpdateFundAccount renames an account or records its current balance.
+func (api *API) UpdateFundAccount(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req fundAccountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	out, err := api.Repos.EmergencyFundRepo().UpdateAccount(c.Request.Context(), userID, id, &repo.FundAccount{
+		Name:    req.Name,
+		Balance: req.Balance,
+	})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteFundAccount removes an account from the emergency fund.
+func (api *API) DeleteFundAccount(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.EmergencyFundRepo().DeleteAccount(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
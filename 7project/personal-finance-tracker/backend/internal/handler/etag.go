@@ -0,0 +1,82 @@
+// backend/internal/handler/etag.go
+
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConditionalGET adds an ETag to successful GET responses and answers 304 Not Modified
+// when the request's If-None-Match already names it. The tag is a hash of the body, so
+// it changes exactly when the data does; clients that poll save the transfer, not the query.
+// Tags are weak (W/) because the same JSON may be sent with different content encodings.
+func ConditionalGET() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+		orig := c.Writer
+		w := &bufferWriter{ResponseWriter: orig, status: http.StatusOK}
+		c.Writer = w
+		defer func() { c.Writer = orig }() // lets Recovery write directly if a handler panics
+		c.Next()
+
+		if w.status != http.StatusOK {
+			orig.WriteHeader(w.status)
+			_, _ = orig.Write(w.buf.Bytes())
+			return
+		}
+		sum := sha256.Sum256(w.buf.Bytes())
+		tag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+		orig.Header().Set("ETag", tag)
+		if etagMatch(c.GetHeader("If-None-Match"), tag) {
+			// 304 carries no body or content headers.
+			orig.Header().Del("Content-Type")
+			orig.Header().Del("Content-Length")
+			orig.WriteHeader(http.StatusNotModified)
+			orig.WriteHeaderNow()
+			return
+		}
+		orig.WriteHeader(http.StatusOK)
+		_, _ = orig.Write(w.buf.Bytes())
+	}
+}
+
+// etagMatch applies the weak comparison of RFC 9110: the W/ prefix is ignored.
+func etagMatch(header, tag string) bool {
+	if header == "" {
+		return false
+	}
+	want := strings.TrimPrefix(tag, "W/")
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferWriter holds the status and body back until the wrapping middleware decides what to send.
+type bufferWriter struct {
+	gin.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (w *bufferWriter) WriteHeader(code int) { w.status = code }
+func (w *bufferWriter) WriteHeaderNow()      {}
+func (w *bufferWriter) Status() int          { return w.status }
+func (w *bufferWriter) Size() int            { return w.buf.Len() }
+func (w *bufferWriter) Written() bool        { return w.buf.Len() > 0 }
+
+func (w *bufferWriter) Write(b []byte) (int, error) { return w.buf.Write(b) }
+
+func (w *bufferWriter) WriteString(s string) (int, error) { return w.buf.WriteString(s) }
@@ -0,0 +1,55 @@
+// backend/internal/handler/etag_test.go
+//
+// Purpose:
+//   Verify ETag generation and If-None-Match handling: 304 for a matching tag,
+//   a fresh body when the data changed, and no tag on error responses.
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConditionalGET(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	data := "v1"
+	r := gin.New()
+	r.GET("/items", handler.ConditionalGET(), func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"data": data}) })
+	r.GET("/missing", handler.ConditionalGET(), func(c *gin.Context) { c.JSON(http.StatusNotFound, gin.H{"code": "not_found"}) })
+
+	get := func(path, inm string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if inm != "" {
+			req.Header.Set("If-None-Match", inm)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("/items", "")
+	tag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || tag == "" || first.Body.String() != `{"data":"v1"}` {
+		t.Fatalf("unexpected first response: %d %q %s", first.Code, tag, first.Body)
+	}
+
+	if rec := get("/items", `"other", `+tag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Fatalf("expected empty 304, got %d %q", rec.Code, rec.Body)
+	}
+
+	data = "v2"
+	changed := get("/items", tag)
+	if changed.Code != http.StatusOK || changed.Header().Get("ETag") == tag || changed.Body.String() != `{"data":"v2"}` {
+		t.Fatalf("expected fresh 200 after change, got %d %s", changed.Code, changed.Body)
+	}
+
+	if rec := get("/missing", "*"); rec.Code != http.StatusNotFound || rec.Header().Get("ETag") != "" {
+		t.Fatalf("errors must pass through untagged, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
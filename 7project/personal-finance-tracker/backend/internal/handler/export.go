@@ -0,0 +1,120 @@
+// backend/internal/handler/export.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/exports"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportURLTTL is how long a download URL handed out by GetExport stays valid.
+const exportURLTTL = 15 * time.Minute
+
+// exportReq requests an export.
+// - Kind: "archive" (all your data as gzipped JSON) or "xlsx" (a year of transactions)
+// - Year: the year of an XLSX export; defaults to the current year
+type exportReq struct {
+	Kind string `json:"kind" binding:"required,oneof=archive xlsx"`
+	Year int    `json:"year" binding:"omitempty,min=1900,max=2999"`
+}
+
+// exportResp is an export with, once it is done, a signed download URL.
+type exportResp struct {
+	*repo.Export
+	DownloadURL  *string    `json:"download_url"`
+	URLExpiresAt *time.Time `json:"url_expires_at"`
+}
+
+// CreateExport queues an export and answers 202 with it right away; poll
+// GET /exports/:id until its status is "done" for the download URL.
+func (api *API) CreateExport(c *gin.Context) {
+	var req exportReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	e := &repo.Export{UserID: MustUserID(c), Kind: req.Kind}
+	if req.Kind == exports.KindXLSX {
+		year := req.Year
+		if year == 0 {
+			year = time.Now().UTC().Year()
+		}
+		e.Year = &year
+	}
+	out, err := api.Repos.ExportRepo().Create(c.Request.Context(), e, exports.JobKind)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, exportResp{Export: out})
+}
+
+// GetExport reports the status of the export identified by :id. Once done, it carries
+// a download_url valid for 15 minutes (ask again for a fresh one); exports themselves
+// are deleted a day after they were requested.
+func (api *API) GetExport(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	e, err := api.Repos.ExportRepo().Get(c.Request.Context(), MustUserID(c), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if e == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	out := exportResp{Export: e}
+	if e.Status == "done" {
+		expires := time.Now().Add(exportURLTTL).UTC().Truncate(time.Second)
+		u := "/api/exports/" + strconv.FormatInt(e.ID, 10) + "/download?" + exports.SignURL([]byte(api.JWTSecret), e.ID, expires)
+		out.DownloadURL, out.URLExpiresAt = &u, &expires
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DownloadExport serves a finished export to whoever holds a valid download URL; no
+// token is needed. Expired or tampered URLs get 404 like unknown exports. When blob
+// storage hands out its own signed URLs (S3_SIGNED_URLS), the client is redirected
+// there instead of the file passing through the API.
+func (api *API) DownloadExport(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if !exports.VerifyURL([]byte(api.JWTSecret), id, c.Query("expires"), c.Query("sig"), time.Now()) {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	e, err := api.Repos.ExportRepo().Download(c.Request.Context(), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if e == nil || e.Filename == nil || e.ContentType == nil || e.BlobKey == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	ctx := c.Request.Context()
+	c.Header("Cache-Control", "private, no-store")
+	u, err := api.Blobs.SignedURL(ctx, *e.BlobKey, *e.Filename, exportURLTTL)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if u != "" {
+		c.Redirect(http.StatusFound, u)
+		return
+	}
+	rc, info, err := api.Blobs.Open(ctx, *e.BlobKey)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	defer rc.Close()
+	c.DataFromReader(http.StatusOK, info.Size, *e.ContentType, rc, map[string]string{
+		"Content-Disposition": `attachment; filename="` + *e.Filename + `"`,
+	})
+}
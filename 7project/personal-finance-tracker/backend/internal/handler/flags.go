@@ -0,0 +1,137 @@
+// backend/internal/handler/flags.go
+
+package handler
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireFlag hides a route (404) from users for whom the feature flag is off, so
+// unfinished features can ship dark and be rolled out gradually.
+// Must run after the JWT middleware.
+func (api *API) RequireFlag(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if api.Flags == nil || !api.Flags.Enabled(c.Request.Context(), key, MustUserID(c)) {
+			problem(c, http.StatusNotFound, "not_found")
+			return
+		}
+		c.Next()
+	}
+}
+
+// Features returns every feature flag evaluated for the current user, e.g.
+// {"envelope_budgets": true}, so clients can show or hide the matching UI.
+func (api *API) Features(c *gin.Context) {
+	out := map[string]bool{}
+	if api.Flags != nil {
+		out = api.Flags.ForUser(c.Request.Context(), MustUserID(c))
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// flagRequest is the body of PUT /admin/flags/:key.
+type flagRequest struct {
+	Description    string `json:"description"`
+	Enabled        bool   `json:"enabled"`
+	RolloutPercent int    `json:"rollout_percent" binding:"min=0,max=100"`
+}
+
+// flagKey mirrors the key check in migration 026.
+var flagKey = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+// ListFlags returns every flag with its per-user overrides.
+func (a *Admin) ListFlags(c *gin.Context) {
+	out, err := a.Repos.FlagRepo().List(c.Request.Context())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// PutFlag creates or updates a flag. Keys are lower-case letters, digits, '.', '_' and '-'.
+func (a *Admin) PutFlag(c *gin.Context) {
+	if !flagKey.MatchString(c.Param("key")) {
+		problem(c, http.StatusBadRequest, "invalid_key")
+		return
+	}
+	var req flagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	f, err := a.Repos.FlagRepo().Upsert(c.Request.Context(), &repo.Flag{
+		Key: c.Param("key"), Description: req.Description, Enabled: req.Enabled, RolloutPercent: req.RolloutPercent,
+	})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	a.invalidateFlags()
+	c.JSON(http.StatusOK, f)
+}
+
+// DeleteFlag removes a flag and its overrides; checks for it then report it off.
+func (a *Admin) DeleteFlag(c *gin.Context) {
+	ok, err := a.Repos.FlagRepo().Delete(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	a.invalidateFlags()
+	c.Status(http.StatusNoContent)
+}
+
+// PutFlagOverride forces a flag on or off for one user: body {"enabled": true|false}.
+func (a *Admin) PutFlagOverride(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid_user_id")
+		return
+	}
+	var req struct {
+		Enabled *bool `json:"enabled" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	if err := a.Repos.FlagRepo().SetOverride(c.Request.Context(), c.Param("key"), userID, req.Enabled); err != nil {
+		fail(c, err)
+		return
+	}
+	a.invalidateFlags()
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteFlagOverride returns a user to the flag's global and rollout settings.
+func (a *Admin) DeleteFlagOverride(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid_user_id")
+		return
+	}
+	if err := a.Repos.FlagRepo().SetOverride(c.Request.Context(), c.Param("key"), userID, nil); err != nil {
+		fail(c, err)
+		return
+	}
+	a.invalidateFlags()
+	c.Status(http.StatusNoContent)
+}
+
+// invalidateFlags applies a change on this instance immediately; others pick it up within the cache TTL.
+func (a *Admin) invalidateFlags() {
+	if a.Flags != nil {
+		a.Flags.Invalidate()
+	}
+}
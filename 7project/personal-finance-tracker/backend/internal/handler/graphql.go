@@ -0,0 +1,34 @@
+// backend/internal/handler/graphql.go
+
+package handler
+
+import (
+	"net/http"
+
+	"pft/internal/gql"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// graphqlReq is a standard GraphQL-over-HTTP request body.
+type graphqlReq struct {
+	Query         string         `json:"query" binding:"required"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// GraphQL executes a query against schema for the authenticated user.
+// Responds 200 with {"data": ..., "errors": [...]} per the GraphQL convention;
+// only a malformed request body yields 400.
+func GraphQL(schema *graphql.Schema) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req graphqlReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			invalidRequest(c, err)
+			return
+		}
+		ctx := gql.WithUser(c.Request.Context(), MustUserID(c))
+		c.JSON(http.StatusOK, schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+	}
+}
@@ -18,6 +18,8 @@
 // Acceptance Criteria:
 //   - Status code MUST be 200.
 //   - Body MUST be parseable JSON containing a boolean field `ok` set to true.
+//   - Without a database the body still reports status, version, commit and uptime.
+//   - `/api/version` reports version, commit, build date and Go version.
 
 package handler_test
 
@@ -62,4 +64,30 @@ func TestHealthz_OK(t *testing.T) {
 	if !ok {
 		t.Fatalf(`expected body to contain {"ok": true}, got: %s`, rec.Body.String())
 	}
+
+	// Assert: build/process details are present even without dependencies.
+	if body["status"] != "ok" || body["version"] == "" || body["commit"] == "" {
+		t.Fatalf("expected status/version/commit, got: %s", rec.Body.String())
+	}
+	if _, ok := body["uptime_seconds"].(float64); !ok {
+		t.Fatalf("expected numeric uptime_seconds, got: %s", rec.Body.String())
+	}
+}
+
+func TestVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/version", handler.New(nil, "testsecret").Version)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/version", nil))
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 JSON, got %d %s", rec.Code, rec.Body.String())
+	}
+	for _, k := range []string{"version", "commit", "build_date", "go_version"} {
+		if body[k] == "" {
+			t.Errorf("expected %s, got: %s", k, rec.Body.String())
+		}
+	}
 }
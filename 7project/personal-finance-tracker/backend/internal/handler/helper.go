@@ -4,6 +4,7 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,7 +16,7 @@ import (
 func getUID(c *gin.Context) int64 {
 	v, ok := c.Get("uid")
 	if !ok {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		problem(c, http.StatusUnauthorized, "unauthorized")
 		return 0
 	}
 	switch id := v.(type) {
@@ -28,13 +29,19 @@ func getUID(c *gin.Context) int64 {
 		// Returning zero keeps behavior consistent with unauthorized/invalid UID handling.
 		return 0
 	default:
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		problem(c, http.StatusUnauthorized, "unauthorized")
 		return 0
 	}
 }
 
-// bad sends a standardized 400 Bad Request response with the provided error message.
+// bad sends a 400 "invalid" problem whose detail is the provided error message.
 // Useful for input validation failures and similar client-side error conditions.
 func bad(c *gin.Context, err error) {
-	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	problemDetail(c, http.StatusBadRequest, "invalid", err.Error())
+}
+
+// validMonth reports whether s is a period in YYYY-MM format.
+func validMonth(s string) bool {
+	_, err := time.Parse("2006-01", s)
+	return err == nil
 }
@@ -0,0 +1,114 @@
+// backend/internal/handler/import.go
+
+package handler
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+
+	"pft/internal/imports"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importReq uploads a CSV file of transactions (see imports.Parse for the columns)
+// or a bank statement PDF.
+// - Filename: optional, shown back in the import's status
+// - CSV: the file's content
+// - PDF: base64 of a statement PDF, instead of CSV
+// - Profile: the regional format of the CSV, e.g. "eu" for 31.12.2026 and 1.234,56 (see imports.ParseProfile), or the statement layout of the PDF, detected when empty (see imports.Profiles)
+// - Drafts: put the rows into the drafts inbox for review instead of creating transactions
+type importReq struct {
+	Filename string `json:"filename" binding:"max=255"`
+	CSV      string `json:"csv" binding:"required_without=PDF"`
+	PDF      string `json:"pdf" binding:"required_without=CSV"`
+	Profile  string `json:"profile" binding:"max=50"`
+	Drafts   bool   `json:"drafts"`
+}
+
+// CreateImport queues an import of the uploaded CSV and answers 202 with the import
+// right away; poll GET /imports/:id for its progress. Files whose header cannot be
+// read are rejected with 400 "invalid_csv" up front; malformed rows are skipped and
+// listed in the import's errors. A statement PDF is converted to CSV first (rows
+// without a category), answering 400 "invalid_pdf" when no transactions can be read
+// from it; the converted CSV is what the import stores. An unknown profile is also
+// answered with invalid_csv or invalid_pdf.
+func (api *API) CreateImport(c *gin.Context) {
+	var req importReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	if req.PDF != "" {
+		data, err := base64.StdEncoding.DecodeString(req.PDF)
+		if err != nil {
+			problemDetail(c, http.StatusBadRequest, "invalid_pdf", "pdf must be base64 encoded")
+			return
+		}
+		rows, err := imports.ParsePDF(data, req.Profile)
+		if err != nil {
+			problemDetail(c, http.StatusBadRequest, "invalid_pdf", err.Error())
+			return
+		}
+		// The converted CSV is in the canonical format, whatever the statement's was.
+		req.CSV, req.Profile = string(imports.FormatCSV(rows)), ""
+	}
+	if _, err := imports.ParseProfile([]byte(req.CSV), req.Profile); err != nil {
+		problemDetail(c, http.StatusBadRequest, "invalid_csv", err.Error())
+		return
+	}
+	out, err := api.Repos.ImportRepo().Create(c.Request.Context(), &repo.Import{
+		UserID: MustUserID(c), Filename: req.Filename, Content: []byte(req.CSV), AsDrafts: req.Drafts, Profile: req.Profile,
+	}, imports.JobKind)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, out)
+}
+
+// GetImport reports the status of the import identified by :id: rows processed of
+// the total, rows imported and failed, and the first row errors.
+func (api *API) GetImport(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	out, err := api.Repos.ImportRepo().Get(c.Request.Context(), MustUserID(c), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CancelImport stops the import identified by :id; transactions it already created
+// are kept. A queued import is cancelled right away, a running one shortly after
+// (cancel_requested is set until then). 409 "import_finished" if it already ended.
+func (api *API) CancelImport(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ctx := c.Request.Context()
+	out, err := api.Repos.ImportRepo().Cancel(ctx, userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		cur, err := api.Repos.ImportRepo().Get(ctx, userID, id)
+		if err != nil {
+			fail(c, err)
+			return
+		}
+		if cur == nil {
+			problem(c, http.StatusNotFound, "not_found")
+			return
+		}
+		problemDetail(c, http.StatusConflict, "import_finished", "The import has already finished with status "+cur.Status+".")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,148 @@
+// backend/internal/handler/income.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// incomeSourceReq is the payload for creating or updating an income source.
+// - StartDate/EndDate: YYYY-MM-DD; EndDate is optional
+// - Kind: optional, defaults to "salary"
+type incomeSourceReq struct {
+	CategoryID  *int64  `json:"category_id"`
+	Name        string  `json:"name" binding:"required,min=1,max=100"`
+	Kind        string  `json:"kind" binding:"omitempty,oneof=salary freelance other"`
+	Amount      float64 `json:"amount" binding:"gte=0"`
+	PayInterval string  `json:"pay_interval" binding:"required,oneof=weekly biweekly monthly quarterly yearly"`
+	StartDate   string  `json:"start_date" binding:"required"`
+	EndDate     *string `json:"end_date"`
+}
+
+// bindIncomeSource validates the request body and converts it into a repo.IncomeSource.
+// Writes a 400 response and returns nil on failure.
+func bindIncomeSource(c *gin.Context) *repo.IncomeSource {
+	var req incomeSourceReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return nil
+	}
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid_date")
+		return nil
+	}
+	s := &repo.IncomeSource{
+		CategoryID:  req.CategoryID,
+		Name:        req.Name,
+		Kind:        req.Kind,
+		Amount:      req.Amount,
+		PayInterval: req.PayInterval,
+		StartDate:   start,
+	}
+	if s.Kind == "" {
+		s.Kind = "salary"
+	}
+	if req.EndDate != nil && *req.EndDate != "" {
+		d, err := time.Parse("2006-01-02", *req.EndDate)
+		if err != nil || d.Before(start) {
+			problem(c, http.StatusBadRequest, "invalid_date")
+			return nil
+		}
+		s.EndDate = &d
+	}
+	return s
+}
+
+// ListIncomeSources returns the user's recurring income definitions.
+func (api *API) ListIncomeSources(c *gin.Context) {
+	userID := MustUserID(c)
+	out, err := api.Repos.IncomeRepo().List(c.Request.Context(), userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateIncomeSource adds a recurring income definition.
+func (api *API) CreateIncomeSource(c *gin.Context) {
+	userID := MustUserID(c)
+	s := bindIncomeSource(c)
+	if s == nil {
+		return
+	}
+	s.UserID = userID
+	out, err := api.Repos.IncomeRepo().Create(c.Request.Context(), s)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// UpdateIncomeSource modifies an income source identified by :id.
+func (api *API) UpdateIncomeSource(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	s := bindIncomeSource(c)
+	if s == nil {
+		return
+	}
+	out, err := api.Repos.IncomeRepo().Update(c.Request.Context(), userID, id, s)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteIncomeSource removes an income source.
+func (api *API) DeleteIncomeSource(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.IncomeRepo().Delete(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// IncomeProjection returns expected income per month starting with the current one.
+// Optional query parameter "months" sets the horizon (default 6, clamped to 1..24);
+// "display_currency" converts amounts (see displayConversion).
+func (api *API) IncomeProjection(c *gin.Context) {
+	userID := MustUserID(c)
+	months := asInt(c.Query("months"), 6)
+	if months < 1 {
+		months = 1
+	}
+	if months > 24 {
+		months = 24
+	}
+	conv, ok := api.displayConversion(c)
+	if !ok {
+		return
+	}
+	out, err := api.Repos.IncomeRepo().In(conv).Project(c.Request.Context(), userID, time.Now().UTC(), months)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
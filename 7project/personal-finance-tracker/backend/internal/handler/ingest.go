@@ -0,0 +1,166 @@
+// backend/internal/handler/ingest.go
+
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"pft/internal/ingest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ingestTokenEncoding spells ingest tokens in lowercase letters and digits, which
+// survive mail systems that change the case of addresses.
+var ingestTokenEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// GetIngestAddress reports the user's receipt ingest address:
+// {"enabled": true, "address": "k3...@in.example.com"}. address is null until one is
+// created with POST; enabled is false when the server has no ingest domain.
+func (api *API) GetIngestAddress(c *gin.Context) {
+	out := gin.H{"enabled": api.IngestDomain != "", "address": nil}
+	if api.IngestDomain == "" {
+		c.JSON(http.StatusOK, out)
+		return
+	}
+	token, err := api.Repos.IngestRepo().Token(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if token != "" {
+		out["address"] = token + "@" + api.IngestDomain
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateIngestAddress gives the user a new ingest address and answers 201 with
+// {"address"}. An existing address is replaced, so a leaked one can be retired; mail
+// sent to it afterwards is ignored. 404 when the server has no ingest domain.
+func (api *API) CreateIngestAddress(c *gin.Context) {
+	if api.IngestDomain == "" {
+		problemDetail(c, http.StatusNotFound, "ingest_disabled", "Email-in is not configured on this server.")
+		return
+	}
+	b := make([]byte, 15)
+	if _, err := rand.Read(b); err != nil {
+		fail(c, err)
+		return
+	}
+	token := ingestTokenEncoding.EncodeToString(b)
+	if err := api.Repos.IngestRepo().SetToken(c.Request.Context(), MustUserID(c), token); err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"address": token + "@" + api.IngestDomain})
+}
+
+// postmarkInbound is the part of Postmark's inbound JSON that is used.
+type postmarkInbound struct {
+	From              string `json:"From"`
+	To                string `json:"To"`
+	OriginalRecipient string `json:"OriginalRecipient"`
+	Subject           string `json:"Subject"`
+	TextBody          string `json:"TextBody"`
+	HtmlBody          string `json:"HtmlBody"`
+	MessageID         string `json:"MessageID"`
+	Date              string `json:"Date"`
+}
+
+// MailInbound receives inbound email from the mail provider and stores receipts as
+// drafts (see ingest.Receiver). The provider authenticates with secret, either as
+// ?secret= or as the basic auth password. Postmark's JSON and the form posts of
+// SendGrid Inbound Parse and Mailgun routes are understood. Messages for no user or
+// without a total are logged and answered 200, since redelivering them cannot help;
+// storage failures answer 500 so the provider retries, and the Message-ID keeps a
+// retried message from being stored twice. Large attachments may exceed MAX_BODY_BYTES;
+// turn them off at the provider.
+func MailInbound(recv *ingest.Receiver, secret string) gin.HandlerFunc {
+	want := []byte(secret)
+	return func(c *gin.Context) {
+		got := c.Query("secret")
+		if _, pw, ok := c.Request.BasicAuth(); ok {
+			got = pw
+		}
+		if subtle.ConstantTimeCompare([]byte(got), want) != 1 {
+			problemDetail(c, http.StatusUnauthorized, "unauthorized", "The inbound mail secret is missing or wrong.")
+			return
+		}
+		m, err := inboundMessage(c)
+		if err != nil {
+			invalidRequest(c, err)
+			return
+		}
+		d, err := recv.Receive(c.Request.Context(), m)
+		switch {
+		case errors.Is(err, ingest.ErrUnknownRecipient), errors.Is(err, ingest.ErrNoReceipt):
+			slog.Info("inbound mail ignored", "request_id", c.GetString("request_id"), "message_id", m.MessageID, "reason", err.Error())
+			c.JSON(http.StatusOK, gin.H{"stored": false, "reason": err.Error()})
+		case err != nil:
+			fail(c, err)
+		default:
+			c.JSON(http.StatusOK, gin.H{"stored": d != nil})
+		}
+	}
+}
+
+// inboundMessage decodes the provider's post into a message.
+func inboundMessage(c *gin.Context) (*ingest.Message, error) {
+	m := &ingest.Message{}
+	var date string
+	if c.ContentType() == gin.MIMEJSON {
+		var p postmarkInbound
+		if err := c.ShouldBindJSON(&p); err != nil {
+			return nil, err
+		}
+		m.From, m.To, m.Subject, m.Text, m.HTML, m.MessageID = p.From, p.To, p.Subject, p.TextBody, p.HtmlBody, p.MessageID
+		if p.OriginalRecipient != "" {
+			m.To = p.OriginalRecipient
+		}
+		date = p.Date
+	} else {
+		// SendGrid: to, from, subject, text, html, headers; Mailgun: recipient, from,
+		// subject, body-plain, body-html, Message-Id, Date.
+		first := func(keys ...string) string {
+			for _, k := range keys {
+				if v := c.PostForm(k); v != "" {
+					return v
+				}
+			}
+			return ""
+		}
+		m.To = first("recipient", "to")
+		m.From = first("from", "sender")
+		m.Subject = first("subject")
+		m.Text = first("body-plain", "text")
+		m.HTML = first("body-html", "html")
+		m.MessageID = first("Message-Id", "message-id")
+		date = first("Date")
+		if h := c.PostForm("headers"); h != "" {
+			if msg, err := mail.ReadMessage(strings.NewReader(strings.ReplaceAll(h, "\r\n", "\n") + "\n\n")); err == nil {
+				if m.MessageID == "" {
+					m.MessageID = msg.Header.Get("Message-Id")
+				}
+				if date == "" {
+					date = msg.Header.Get("Date")
+				}
+			}
+		}
+	}
+	if m.To == "" {
+		return nil, errors.New("the message has no recipient")
+	}
+	m.Received = time.Now().UTC()
+	if d, err := mail.ParseDate(date); err == nil {
+		m.Received = d
+	}
+	return m, nil
+}
@@ -0,0 +1,73 @@
+// backend/internal/handler/ingest_test.go
+//
+// Purpose:
+//   Verify that the inbound-mail webhook checks its secret, decodes form (SendGrid,
+//   Mailgun) and JSON (Postmark) posts, and answers 200 for mail it cannot use.
+
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"pft/internal/handler"
+	"pft/internal/ingest"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ingestUsers map[string]int64
+
+func (u ingestUsers) UserByToken(_ context.Context, token string) (int64, error) {
+	return u[token], nil
+}
+
+type ingestDrafts struct{ got []repo.Draft }
+
+func (d *ingestDrafts) Create(_ context.Context, dr *repo.Draft) (*repo.Draft, error) {
+	d.got = append(d.got, *dr)
+	return dr, nil
+}
+
+func TestMailInbound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	drafts := &ingestDrafts{}
+	recv := &ingest.Receiver{Domain: "in.example.com", Addresses: ingestUsers{"tok": 3}, Drafts: drafts}
+	r := gin.New()
+	r.Use(handler.RequireJSON("/api/mail/inbound"))
+	r.POST("/api/mail/inbound", handler.MailInbound(recv, "0123456789abcdef"))
+
+	post := func(target, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	form := url.Values{"to": {"tok@in.example.com"}, "from": {"Shop <s@shop.example>"}, "subject": {"Receipt"},
+		"text": {"Total: $9.50"}, "headers": {"Message-ID: <m1@shop.example>\nDate: Mon, 5 Oct 2026 10:00:00 +0000"}}
+	if w := post("/api/mail/inbound?secret=wrong", "application/x-www-form-urlencoded", form.Encode()); w.Code != http.StatusUnauthorized {
+		t.Fatalf("wrong secret: status %d", w.Code)
+	}
+	if w := post("/api/mail/inbound?secret=0123456789abcdef", "application/x-www-form-urlencoded", form.Encode()); w.Code != http.StatusOK ||
+		!strings.Contains(w.Body.String(), `"stored":true`) {
+		t.Fatalf("form post: %d %s", w.Code, w.Body)
+	}
+	if len(drafts.got) != 1 || drafts.got[0].Amount != 9.5 || *drafts.got[0].ExternalID != "m1@shop.example" ||
+		drafts.got[0].Date.Format("2006-01-02") != "2026-10-05" {
+		t.Fatalf("drafts = %+v", drafts.got)
+	}
+
+	postmark := `{"From":"s@shop.example","To":"x@other.example","OriginalRecipient":"tok@in.example.com",` +
+		`"Subject":"Hi","TextBody":"nothing to see"}`
+	if w := post("/api/mail/inbound?secret=0123456789abcdef", "application/json", postmark); w.Code != http.StatusOK ||
+		!strings.Contains(w.Body.String(), `"stored":false`) {
+		t.Fatalf("postmark post without a total: %d %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,28 @@
+// backend/internal/handler/jobs.go
+
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobsStatus lists this instance's periodic job schedules, each with its next run and
+// the state of its last job (finished_at, attempts, last_error), and the queue depth.
+// Schedules are empty on instances running without a worker.
+func (a *Admin) JobsStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+	schedules, err := a.Scheduler.Status(ctx, time.Now().UTC())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	queue, err := a.Repos.JobRepo().Stats(ctx)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"schedules": schedules, "queue": queue})
+}
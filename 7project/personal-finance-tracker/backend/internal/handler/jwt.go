@@ -4,9 +4,12 @@ package handler
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
+	"pft/internal/repo"
+
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -30,7 +33,7 @@ func JWTMiddleware(cfg AuthConfig) gin.HandlerFunc {
 		// Expect a Bearer token in the Authorization header.
 		ah := c.GetHeader("Authorization")
 		if !strings.HasPrefix(ah, "Bearer ") {
-			c.AbortWithStatusJSON(401, gin.H{"error": "missing bearer token"})
+			problemDetail(c, http.StatusUnauthorized, "unauthorized", "A Bearer token is required in the Authorization header.")
 			return
 		}
 		tokenStr := strings.TrimSpace(strings.TrimPrefix(ah, "Bearer "))
@@ -44,24 +47,26 @@ func JWTMiddleware(cfg AuthConfig) gin.HandlerFunc {
 			return secret, nil
 		})
 		if err != nil || !tok.Valid {
-			c.AbortWithStatusJSON(401, gin.H{"error": "invalid token"})
+			problemDetail(c, http.StatusUnauthorized, "unauthorized", "The token is invalid or expired.")
 			return
 		}
 
 		// Extract claims as a generic map; expect "uid" to be present.
 		claims, ok := tok.Claims.(jwt.MapClaims)
 		if !ok {
-			c.AbortWithStatusJSON(401, gin.H{"error": "invalid claims"})
+			problemDetail(c, http.StatusUnauthorized, "unauthorized", "The token claims are malformed.")
 			return
 		}
 		// JSON numbers decode to float64; convert to int64 for application use.
 		uidF, ok := claims["uid"].(float64)
 		if !ok {
-			c.AbortWithStatusJSON(401, gin.H{"error": "uid missing"})
+			problemDetail(c, http.StatusUnauthorized, "unauthorized", "The token does not identify a user.")
 			return
 		}
-		// Store the user ID in the Gin context for later retrieval.
+		// Store the user ID in the Gin context for later retrieval, and scope the
+		// request's database access to it (row-level security, see repo.WithTenant).
 		c.Set("uid", int64(uidF))
+		c.Request = c.Request.WithContext(repo.WithTenant(c.Request.Context(), int64(uidF)))
 		c.Next()
 	}
 }
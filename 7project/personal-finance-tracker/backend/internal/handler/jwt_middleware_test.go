@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"pft/internal/handler"
+	"pft/internal/repo"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -42,7 +43,8 @@ func TestJWTMiddleware_AllowsValidToken(t *testing.T) {
 	r.Use(handler.JWTMiddleware(handler.AuthConfig{JWTSecret: secret}))
 	r.GET("/protected", func(c *gin.Context) {
 		uidAny, _ := c.Get("uid")
-		c.JSON(200, gin.H{"uid": uidAny})
+		tenant, _ := repo.TenantFrom(c.Request.Context())
+		c.JSON(200, gin.H{"uid": uidAny, "tenant": tenant})
 	})
 
 	w := httptest.NewRecorder()
@@ -58,6 +60,9 @@ func TestJWTMiddleware_AllowsValidToken(t *testing.T) {
 	if got := int(body["uid"].(float64)); got != 99 {
 		t.Fatalf("expected uid=99, got %v", body["uid"])
 	}
+	if got := int(body["tenant"].(float64)); got != 99 {
+		t.Fatalf("expected database access scoped to user 99, got %v", body["tenant"])
+	}
 }
 
 func TestJWTMiddleware_RejectsMissingToken(t *testing.T) {
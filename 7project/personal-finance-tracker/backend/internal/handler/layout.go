@@ -0,0 +1,58 @@
+// backend/internal/handler/layout.go
+
+package handler
+
+import (
+	"net/http"
+	"slices"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// layoutReq is the payload for replacing the dashboard layout.
+// Widgets are listed in display order; each known widget may appear at most once.
+type layoutReq struct {
+	Widgets []repo.Widget `json:"widgets" binding:"required"`
+}
+
+// GetDashboardLayout returns the authenticated user's widget layout (or the default one).
+func (api *API) GetDashboardLayout(c *gin.Context) {
+	userID := MustUserID(c)
+	ws, err := api.Repos.DashboardRepo().GetLayout(c.Request.Context(), userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"widgets": ws})
+}
+
+// PutDashboardLayout replaces the authenticated user's widget layout.
+// Responds with 400 for unknown or duplicated widget IDs.
+func (api *API) PutDashboardLayout(c *gin.Context) {
+	userID := MustUserID(c)
+	var req layoutReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	seen := map[string]bool{}
+	for _, w := range req.Widgets {
+		if !slices.Contains(repo.DashboardWidgets, w.ID) {
+			writeProblem(c, Problem{Status: http.StatusBadRequest, Code: "unknown_widget", Extra: map[string]any{"widget": w.ID}})
+			return
+		}
+		if seen[w.ID] {
+			writeProblem(c, Problem{Status: http.StatusBadRequest, Code: "duplicate_widget", Extra: map[string]any{"widget": w.ID}})
+			return
+		}
+		seen[w.ID] = true
+	}
+	ws, err := api.Repos.DashboardRepo().SaveLayout(c.Request.Context(), userID, req.Widgets)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"widgets": ws})
+}
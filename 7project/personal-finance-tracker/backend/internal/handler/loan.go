@@ -0,0 +1,210 @@
+// backend/internal/handler/loan.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loanReq is the payload for creating or updating a loan.
+// - AnnualRate: yearly interest in percent (0 allowed for interest-free debts)
+// - TermMonths: number of monthly installments
+// - StartDate: YYYY-MM-DD; the first installment is due one month later
+type loanReq struct {
+	Name       string  `json:"name" binding:"required,min=1,max=100"`
+	Principal  float64 `json:"principal" binding:"required,gt=0"`
+	AnnualRate float64 `json:"annual_rate" binding:"gte=0,lte=100"`
+	TermMonths int     `json:"term_months" binding:"required,gt=0,lte=1200"`
+	StartDate  string  `json:"start_date" binding:"required"`
+}
+
+// loanPaymentReq links an existing transaction to a loan as a payment.
+type loanPaymentReq struct {
+	TransactionID int64 `json:"transaction_id" binding:"required"`
+}
+
+// bindLoan validates the request body and converts it into a repo.Loan.
+// Writes a 400 response and returns nil on failure.
+func bindLoan(c *gin.Context) *repo.Loan {
+	var req loanReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return nil
+	}
+	d, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid_date")
+		return nil
+	}
+	return &repo.Loan{
+		Name:       req.Name,
+		Principal:  req.Principal,
+		AnnualRate: req.AnnualRate,
+		TermMonths: req.TermMonths,
+		StartDate:  d,
+	}
+}
+
+// loadLoan fetches the loan referenced by the :id path parameter.
+// Writes a 404/500 response and returns nil when it cannot be loaded.
+func (api *API) loadLoan(c *gin.Context, userID int64) *repo.Loan {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	l, err := api.Repos.LoanRepo().Get(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return nil
+	}
+	if l == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return nil
+	}
+	return l
+}
+
+// ListLoans returns all loans of the authenticated user.
+func (api *API) ListLoans(c *gin.Context) {
+	userID := MustUserID(c)
+	out, err := api.Repos.LoanRepo().List(c.Request.Context(), userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateLoan registers a new loan for the authenticated user.
+func (api *API) CreateLoan(c *gin.Context) {
+	userID := MustUserID(c)
+	l := bindLoan(c)
+	if l == nil {
+		return
+	}
+	l.UserID = userID
+	out, err := api.Repos.LoanRepo().Create(c.Request.Context(), l)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// UpdateLoan modifies a loan identified by :id. Returns 404 if it does not exist.
+func (api *API) UpdateLoan(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	l := bindLoan(c)
+	if l == nil {
+		return
+	}
+	out, err := api.Repos.LoanRepo().Update(c.Request.Context(), userID, id, l)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteLoan removes a loan; linked transactions are kept, only the links are dropped.
+func (api *API) DeleteLoan(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.LoanRepo().Delete(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// LoanSchedule returns the full amortization schedule of a loan.
+func (api *API) LoanSchedule(c *gin.Context) {
+	userID := MustUserID(c)
+	l := api.loadLoan(c, userID)
+	if l == nil {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"monthly_payment": repo.MonthlyPayment(l.Principal, l.AnnualRate, l.TermMonths),
+		"installments":    repo.Amortize(l),
+	})
+}
+
+// LoanPayoff projects the payoff date and remaining interest from recorded payments.
+func (api *API) LoanPayoff(c *gin.Context) {
+	userID := MustUserID(c)
+	l := api.loadLoan(c, userID)
+	if l == nil {
+		return
+	}
+	payments, err := api.Repos.LoanRepo().Payments(c.Request.Context(), userID, l.ID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, repo.ProjectPayoff(l, payments))
+}
+
+// ListLoanPayments returns the transactions recorded as payments towards a loan.
+func (api *API) ListLoanPayments(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	out, err := api.Repos.LoanRepo().Payments(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// AddLoanPayment links an existing transaction to a loan.
+// Returns 404 if the loan or transaction does not belong to the user.
+func (api *API) AddLoanPayment(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req loanPaymentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	ok, err := api.Repos.LoanRepo().LinkPayment(c.Request.Context(), userID, id, req.TransactionID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveLoanPayment unlinks a transaction (:txid) from a loan (:id).
+func (api *API) RemoveLoanPayment(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	txid, _ := strconv.ParseInt(c.Param("txid"), 10, 64)
+	ok, err := api.Repos.LoanRepo().UnlinkPayment(c.Request.Context(), userID, id, txid)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,23 @@
+// backend/internal/handler/locale.go
+
+package handler
+
+import (
+	"pft/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// localeOf returns the language for user-facing strings in the response, negotiated
+// from Accept-Language once per request. Responses whose content depends on it are
+// marked Vary: Accept-Language so shared caches keep the translations apart.
+func localeOf(c *gin.Context) string {
+	if lang := c.GetString("locale"); lang != "" {
+		return lang
+	}
+	lang := i18n.Match(c.GetHeader("Accept-Language"))
+	c.Set("locale", lang)
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	c.Header("Content-Language", lang)
+	return lang
+}
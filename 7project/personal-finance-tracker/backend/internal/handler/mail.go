@@ -0,0 +1,43 @@
+// backend/internal/handler/mail.go
+
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"pft/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testMailRequest is the body of POST /admin/mail/test.
+type testMailRequest struct {
+	To     string `json:"to" binding:"required,email"`
+	Locale string `json:"locale"`
+}
+
+// SendTestMail queues the "test" email to check the mail configuration and answers
+// 202 with the queued job; the job's state and last_error tell whether it went out.
+func (a *Admin) SendTestMail(c *gin.Context) {
+	var req testMailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	if req.Locale == "" {
+		req.Locale = i18n.Default
+	}
+	if !i18n.Supports(req.Locale) {
+		problemDetail(c, http.StatusBadRequest, "invalid_locale",
+			"locale must be one of: "+strings.Join(i18n.Supported(), ", ")+".")
+		return
+	}
+	data := map[string]string{"Sender": a.Mailer.Sender.Name()}
+	j, err := a.Mailer.Send(c.Request.Context(), nil, req.To, req.Locale, "test", data)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, j)
+}
@@ -0,0 +1,153 @@
+// backend/internal/handler/maintenance.go
+
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceStore loads the shared maintenance setting; *repo.MaintenanceRepo satisfies it.
+type MaintenanceStore interface {
+	Get(ctx context.Context) (*repo.Maintenance, error)
+}
+
+// MaintenanceSwitch is the cached maintenance state of this instance.
+// - Store: shared setting toggled through the admin API (may be nil)
+// - Force: maintenance regardless of Store (MAINTENANCE_MODE), for when the database itself is unavailable
+// - TTL: how long a loaded setting is used (default 5s)
+type MaintenanceSwitch struct {
+	Store MaintenanceStore
+	Force bool
+	TTL   time.Duration
+
+	mu       sync.Mutex
+	cur      repo.Maintenance
+	loadedAt time.Time
+	loading  bool
+	gen      int
+}
+
+// Current returns the effective setting. One request at a time reloads a stale
+// setting, outside the lock; the others keep using the previous one meanwhile, as
+// they do when a load fails.
+func (m *MaintenanceSwitch) Current(ctx context.Context) repo.Maintenance {
+	ttl := m.TTL
+	if ttl <= 0 {
+		ttl = 5 * time.Second
+	}
+	m.mu.Lock()
+	load := m.Store != nil && !m.loading && time.Since(m.loadedAt) >= ttl
+	gen := m.gen
+	m.loading = m.loading || load
+	m.mu.Unlock()
+	if load {
+		cur, err := m.Store.Get(context.WithoutCancel(ctx))
+		if err != nil {
+			slog.Warn("loading maintenance mode failed", "error", err.Error())
+		}
+		m.mu.Lock()
+		if err == nil {
+			m.cur = *cur
+		}
+		if gen == m.gen { // not invalidated during the load
+			m.loadedAt = time.Now()
+		}
+		m.loading = false
+		m.mu.Unlock()
+	}
+	m.mu.Lock()
+	cur := m.cur
+	m.mu.Unlock()
+	if m.Force {
+		cur.Enabled = true
+	}
+	return cur
+}
+
+// Invalidate makes the next request reload the setting.
+func (m *MaintenanceSwitch) Invalidate() {
+	m.mu.Lock()
+	m.loadedAt = time.Time{}
+	m.gen++
+	m.mu.Unlock()
+}
+
+// maintenanceExempt lists routes that keep working during maintenance: probes,
+// metrics, the version and the admin API used to run the maintenance and switch it off.
+func maintenanceExempt(path string) bool {
+	switch path {
+	case "/api/healthz", "/api/readyz", "/api/version", "/metrics":
+		return true
+	}
+	return strings.HasPrefix(path, "/api/admin/")
+}
+
+// Maintenance answers 503 "maintenance" for every non-exempt route while maintenance
+// mode is on. The problem carries the operator's message as detail and, when an end
+// time is known, an "until" member and a Retry-After header.
+func Maintenance(m *MaintenanceSwitch) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if maintenanceExempt(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+		cur := m.Current(c.Request.Context())
+		if !cur.Enabled {
+			c.Next()
+			return
+		}
+		p := Problem{Status: http.StatusServiceUnavailable, Code: "maintenance", Detail: cur.Message}
+		if cur.Until != nil {
+			p.Extra = map[string]any{"until": cur.Until.UTC()}
+			if wait := time.Until(*cur.Until); wait > 0 {
+				c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			}
+		}
+		writeProblem(c, p)
+	}
+}
+
+// GetMaintenance returns the effective maintenance setting.
+func (a *Admin) GetMaintenance(c *gin.Context) {
+	c.JSON(http.StatusOK, a.Maintenance.Current(c.Request.Context()))
+}
+
+// maintenanceRequest is the body of PUT /admin/maintenance.
+type maintenanceRequest struct {
+	Enabled *bool      `json:"enabled" binding:"required"`
+	Message string     `json:"message" binding:"max=500"`
+	Until   *time.Time `json:"until"`
+}
+
+// SetMaintenance switches maintenance mode for every instance (each picks it up
+// within a few seconds). It cannot lift MAINTENANCE_MODE set in the environment.
+func (a *Admin) SetMaintenance(c *gin.Context) {
+	var req maintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	out, err := a.Repos.MaintenanceRepo().Set(c.Request.Context(), &repo.Maintenance{
+		Enabled: *req.Enabled, Message: req.Message, Until: req.Until,
+	})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	a.Maintenance.Invalidate()
+	if a.Maintenance.Force {
+		out.Enabled = true
+	}
+	c.JSON(http.StatusOK, out)
+}
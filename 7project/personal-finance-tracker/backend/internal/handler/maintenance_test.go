@@ -0,0 +1,106 @@
+// backend/internal/handler/maintenance_test.go
+//
+// Purpose:
+//   Verify that maintenance mode blocks regular routes with a 503 problem while
+//   probes and the admin API keep working, and that a slow load of the setting
+//   does not hold up other requests.
+
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pft/internal/handler"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fakeMaintenance struct{ m repo.Maintenance }
+
+func (f *fakeMaintenance) Get(context.Context) (*repo.Maintenance, error) { return &f.m, nil }
+
+func TestMaintenance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	until := time.Now().Add(90 * time.Second)
+	store := &fakeMaintenance{m: repo.Maintenance{Enabled: true, Message: "Upgrading the database", Until: &until}}
+	sw := &handler.MaintenanceSwitch{Store: store}
+	r := gin.New()
+	r.Use(handler.Maintenance(sw))
+	for _, p := range []string{"/api/transactions", "/api/healthz", "/api/admin/maintenance"} {
+		r.GET(p, func(c *gin.Context) { c.Status(http.StatusOK) })
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/api/transactions")
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 503 with Retry-After, got %d %v", w.Code, w.Header())
+	}
+	var body map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	if body["code"] != "maintenance" || body["detail"] != "Upgrading the database" || body["until"] == nil {
+		t.Fatalf("unexpected problem body: %s", w.Body)
+	}
+	for _, p := range []string{"/api/healthz", "/api/admin/maintenance"} {
+		if w := get(p); w.Code != http.StatusOK {
+			t.Errorf("%s should stay available, got %d", p, w.Code)
+		}
+	}
+
+	store.m.Enabled = false
+	sw.Invalidate()
+	if w := get("/api/transactions"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 after maintenance ended, got %d", w.Code)
+	}
+	sw.Force = true
+	if w := get("/api/transactions"); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("forced maintenance should block, got %d", w.Code)
+	}
+}
+
+type slowMaintenance struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *slowMaintenance) Get(context.Context) (*repo.Maintenance, error) {
+	close(s.started)
+	<-s.release
+	return &repo.Maintenance{Enabled: true}, nil
+}
+
+func TestMaintenance_SlowLoad(t *testing.T) {
+	store := &slowMaintenance{started: make(chan struct{}), release: make(chan struct{})}
+	sw := &handler.MaintenanceSwitch{Store: store}
+	loaded := make(chan repo.Maintenance)
+	go func() { loaded <- sw.Current(context.Background()) }()
+	<-store.started
+
+	done := make(chan repo.Maintenance)
+	go func() { done <- sw.Current(context.Background()) }()
+	select {
+	case cur := <-done:
+		if cur.Enabled {
+			t.Errorf("expected the previous setting while loading, got %+v", cur)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Current blocked on another request's load")
+	}
+
+	close(store.release)
+	if cur := <-loaded; !cur.Enabled {
+		t.Errorf("expected the loaded setting, got %+v", cur)
+	}
+	if cur := sw.Current(context.Background()); !cur.Enabled {
+		t.Errorf("expected the cached setting, got %+v", cur)
+	}
+}
@@ -0,0 +1,133 @@
+// backend/internal/handler/middleware.go
+
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"pft/internal/errtrack"
+	"pft/internal/platform"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request correlation ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID assigns every request a correlation ID. A well-formed incoming
+// X-Request-ID (e.g. from a proxy) is reused; otherwise a random one is generated.
+// The ID is echoed in the response header, stored under "request_id" in the Gin
+// context, and attached to the request context so the repo layer can log it.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set("request_id", id)
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(platform.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// validRequestID accepts up to 128 visible ASCII characters.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns 16 random bytes, hex-encoded.
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// AccessLog writes one structured log line per request with method, path, status,
+// latency, client IP, request ID and (for authenticated routes) the user ID.
+func AccessLog(l *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		c.Next()
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", path,
+			"status", c.Writer.Status(),
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"client_ip", c.ClientIP(),
+			"request_id", c.GetString("request_id"),
+		}
+		if uid, ok := c.Get("uid"); ok {
+			attrs = append(attrs, "user_id", uid)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+		level := slog.LevelInfo
+		switch {
+		case c.Writer.Status() >= 500:
+			level = slog.LevelError
+		case c.Writer.Status() >= 400:
+			level = slog.LevelWarn
+		}
+		l.Log(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// Recovery converts panics into a 500 response and logs them with the request ID and
+// stack. With a non-nil rep, panics are also reported with the request context.
+func Recovery(l *slog.Logger, rep *errtrack.Client) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, rec any) {
+		l.Error("panic recovered",
+			"request_id", c.GetString("request_id"),
+			"panic", rec,
+			"stack", string(debug.Stack()),
+		)
+		e := trackEvent(c, fmt.Errorf("%v", rec))
+		e.Panic = true
+		rep.Capture(e)
+		problem(c, http.StatusInternalServerError, "server")
+	})
+}
+
+// ReportErrors reports the last error recorded by fail (usually from the repo layer)
+// for requests answered with a 5xx status. Client errors are not reported.
+func ReportErrors(rep *errtrack.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if c.Writer.Status() < 500 || len(c.Errors) == 0 {
+			return
+		}
+		rep.Capture(trackEvent(c, c.Errors.Last().Err))
+	}
+}
+
+// trackEvent describes err with the request it occurred in.
+func trackEvent(c *gin.Context, err error) errtrack.Event {
+	uid, _ := c.Get("uid")
+	id, _ := uid.(int64)
+	return errtrack.Event{
+		Err:       err,
+		UserID:    id,
+		RequestID: c.GetString("request_id"),
+		Method:    c.Request.Method,
+		Route:     c.FullPath(),
+	}
+}
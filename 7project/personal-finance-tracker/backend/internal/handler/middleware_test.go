@@ -0,0 +1,46 @@
+// backend/internal/handler/middleware_test.go
+//
+// Purpose:
+//   Verify that the RequestID middleware reuses a well-formed incoming ID,
+//   generates one otherwise, and exposes it to the request context.
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pft/internal/handler"
+	"pft/internal/platform"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(handler.RequestID())
+	r.GET("/ping", func(c *gin.Context) {
+		c.String(http.StatusOK, platform.RequestID(c.Request.Context()))
+	})
+
+	// Incoming ID is propagated.
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(handler.RequestIDHeader, "abc-123")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if got := w.Header().Get(handler.RequestIDHeader); got != "abc-123" || w.Body.String() != "abc-123" {
+		t.Fatalf("expected propagated id, got header %q body %q", got, w.Body.String())
+	}
+
+	// Missing or malformed IDs are replaced.
+	req = httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(handler.RequestIDHeader, "bad id\n")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	got := w.Header().Get(handler.RequestIDHeader)
+	if len(got) != 32 || w.Body.String() != got {
+		t.Fatalf("expected generated 32-char id, got header %q body %q", got, w.Body.String())
+	}
+}
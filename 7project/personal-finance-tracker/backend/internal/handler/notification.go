@@ -0,0 +1,113 @@
+// backend/internal/handler/notification.go
+
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"pft/internal/alerts"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// notificationReadReq marks one notification read or unread.
+type notificationReadReq struct {
+	Read *bool `json:"read" binding:"required"`
+}
+
+// notificationsReadReq marks several notifications read or unread.
+// - IDs: the notifications to mark; empty means all of them
+// - Event: optional; only notifications of this alert event
+type notificationsReadReq struct {
+	Read  *bool   `json:"read" binding:"required"`
+	IDs   []int64 `json:"ids" binding:"max=1000"`
+	Event string  `json:"event"`
+}
+
+// validEvent writes a 400 problem and returns false unless event is "" or an alert event.
+func validEvent(c *gin.Context, event string) bool {
+	if event == "" || slices.Contains(alerts.Events, event) {
+		return true
+	}
+	problemDetail(c, http.StatusBadRequest, "invalid_event", "event must be one of: "+strings.Join(alerts.Events, ", ")+".")
+	return false
+}
+
+// ListNotifications returns the user's in-app notifications, newest first.
+// Optional filters: unread=true, event, limit (default 50, at most 200), offset.
+func (api *API) ListNotifications(c *gin.Context) {
+	unread, _ := strconv.ParseBool(c.Query("unread"))
+	f := repo.NotificationFilter{
+		Unread: unread,
+		Event:  c.Query("event"),
+		Limit:  asInt(c.Query("limit"), 50),
+		Offset: asInt(c.Query("offset"), 0),
+	}
+	if !validEvent(c, f.Event) {
+		return
+	}
+	out, err := api.Repos.NotificationRepo().List(c.Request.Context(), MustUserID(c), f)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// UnreadNotifications counts the user's unread notifications, in total and by event:
+// {"total": 3, "events": {"budget_alert": 2, "import_finished": 1}}.
+func (api *API) UnreadNotifications(c *gin.Context) {
+	byEvent, err := api.Repos.NotificationRepo().Unread(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	total := 0
+	for _, n := range byEvent {
+		total += n
+	}
+	c.JSON(http.StatusOK, gin.H{"total": total, "events": byEvent})
+}
+
+// MarkNotification marks the notification :id read or unread; 404 if not found.
+func (api *API) MarkNotification(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req notificationReadReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	n, err := api.Repos.NotificationRepo().SetRead(c.Request.Context(), MustUserID(c), id, *req.Read)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if n == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, n)
+}
+
+// MarkNotifications marks the listed notifications, or all of them, read or unread,
+// e.g. {"read": true} for "mark all as read". Answers with how many changed.
+func (api *API) MarkNotifications(c *gin.Context) {
+	var req notificationsReadReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	if !validEvent(c, req.Event) {
+		return
+	}
+	n, err := api.Repos.NotificationRepo().SetReadAll(c.Request.Context(), MustUserID(c), req.IDs, req.Event, *req.Read)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"updated": n})
+}
@@ -0,0 +1,391 @@
+// backend/internal/handler/oauth.go
+
+package handler
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Prefixes of OAuth identifiers and secrets, so each kind is recognizable in logs and
+// leak scans, and access tokens can be told apart from session JWTs.
+const (
+	oauthClientPrefix  = "pftc_"
+	oauthSecretPrefix  = "pfts_"
+	oauthAccessPrefix  = "pfto_"
+	oauthRefreshPrefix = "pftr_"
+)
+
+// Lifetimes of authorization codes and access tokens; refresh tokens last until the
+// user revokes the grant.
+const (
+	oauthCodeTTL   = 10 * time.Minute
+	oauthAccessTTL = time.Hour
+)
+
+// createOAuthClientReq registers an app.
+// - RedirectURIs: https URLs, or http ones on a loopback host for local development
+type createOAuthClientReq struct {
+	Name         string   `json:"name" binding:"required,max=100"`
+	RedirectURIs []string `json:"redirect_uris" binding:"required,min=1,max=10,dive,url"`
+}
+
+// authorizeReq is an authorization request as the app sends it, forwarded by the
+// consent screen. Approve is only read when the user answers (POST).
+// - Scope: space-separated scopes (see Scopes); omitted asks for ReadScopes
+// - CodeChallenge: PKCE challenge; CodeChallengeMethod must then be "S256"
+type authorizeReq struct {
+	ResponseType        string `form:"response_type" json:"response_type" binding:"required"`
+	ClientID            string `form:"client_id" json:"client_id" binding:"required"`
+	RedirectURI         string `form:"redirect_uri" json:"redirect_uri"`
+	Scope               string `form:"scope" json:"scope"`
+	State               string `form:"state" json:"state"`
+	CodeChallenge       string `form:"code_challenge" json:"code_challenge"`
+	CodeChallengeMethod string `form:"code_challenge_method" json:"code_challenge_method"`
+	Approve             bool   `json:"approve"`
+}
+
+// ListOAuthClients returns the apps the user registered, without their secrets.
+func (api *API) ListOAuthClients(c *gin.Context) {
+	out, err := api.Repos.OAuthRepo().ListClients(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateOAuthClient registers an app and answers 201 with its secret under
+// "client_secret". This is the only time the secret is shown; only its hash is stored.
+func (api *API) CreateOAuthClient(c *gin.Context) {
+	var req createOAuthClientReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	for _, u := range req.RedirectURIs {
+		if !validRedirectURI(u) {
+			problemDetail(c, http.StatusBadRequest, "invalid_redirect_uri", u+" must use https, or http on localhost.")
+			return
+		}
+	}
+	clientID, err := randomToken(oauthClientPrefix)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	secret, err := randomToken(oauthSecretPrefix)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	cl, err := api.Repos.OAuthRepo().CreateClient(c.Request.Context(), MustUserID(c), req.Name, clientID, secret, req.RedirectURIs)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, struct {
+		*repo.OAuthClient
+		Secret string `json:"client_secret"`
+	}{cl, secret})
+}
+
+// DeleteOAuthClient removes app :id; every grant to it is revoked.
+func (api *API) DeleteOAuthClient(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.OAuthRepo().DeleteClient(c.Request.Context(), MustUserID(c), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListOAuthGrants returns the apps the user has authorized and what they may do.
+func (api *API) ListOAuthGrants(c *gin.Context) {
+	out, err := api.Repos.OAuthRepo().ListGrants(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// RevokeOAuthGrant revokes the user's grants to app :client_id; its tokens stop
+// working immediately.
+func (api *API) RevokeOAuthGrant(c *gin.Context) {
+	ok, err := api.Repos.OAuthRepo().RevokeGrants(c.Request.Context(), MustUserID(c), c.Param("client_id"))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// OAuthConsent validates the authorization request in the query string for the
+// consent screen and returns the app's name, the redirect URI and the scopes asked
+// for. Invalid requests are answered with 400 rather than redirected, as the app or
+// its redirect URI may not be trusted.
+func (api *API) OAuthConsent(c *gin.Context) {
+	var req authorizeReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	cl, redirect, scopes, ok := api.checkAuthorize(c, &req)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"client":       gin.H{"client_id": cl.ClientID, "name": cl.Name},
+		"redirect_uri": redirect,
+		"scopes":       scopes,
+		"state":        req.State,
+	})
+}
+
+// OAuthAuthorize records the user's answer to the consent screen. It returns the URL
+// to send the user back to: with a single-use code when they approved, with
+// error=access_denied otherwise.
+func (api *API) OAuthAuthorize(c *gin.Context) {
+	var req authorizeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	cl, redirect, scopes, ok := api.checkAuthorize(c, &req)
+	if !ok {
+		return
+	}
+	q := url.Values{}
+	if req.Approve {
+		code, err := randomToken("")
+		if err != nil {
+			fail(c, err)
+			return
+		}
+		a := repo.OAuthCode{ClientID: cl.ID, UserID: MustUserID(c), RedirectURI: redirect, Scopes: scopes}
+		if req.CodeChallenge != "" {
+			a.CodeChallenge = &req.CodeChallenge
+		}
+		if err := api.Repos.OAuthRepo().CreateCode(c.Request.Context(), code, a, oauthCodeTTL); err != nil {
+			fail(c, err)
+			return
+		}
+		q.Set("code", code)
+	} else {
+		q.Set("error", "access_denied")
+	}
+	if req.State != "" {
+		q.Set("state", req.State)
+	}
+	sep := "?"
+	if strings.Contains(redirect, "?") {
+		sep = "&"
+	}
+	c.JSON(http.StatusOK, gin.H{"redirect_uri": redirect + sep + q.Encode()})
+}
+
+// checkAuthorize validates an authorization request, answering 400 when it is not
+// one the app may make, and returns the app, the redirect URI to use and the scopes.
+func (api *API) checkAuthorize(c *gin.Context, req *authorizeReq) (*repo.OAuthClient, string, []string, bool) {
+	if req.ResponseType != "code" {
+		problemDetail(c, http.StatusBadRequest, "unsupported_response_type", "Only response_type=code is supported.")
+		return nil, "", nil, false
+	}
+	cl, err := api.Repos.OAuthRepo().Client(c.Request.Context(), req.ClientID)
+	if err != nil {
+		fail(c, err)
+		return nil, "", nil, false
+	}
+	if cl == nil {
+		problem(c, http.StatusBadRequest, "invalid_client")
+		return nil, "", nil, false
+	}
+	redirect := req.RedirectURI
+	if redirect == "" && len(cl.RedirectURIs) == 1 {
+		redirect = cl.RedirectURIs[0]
+	}
+	if !slices.Contains(cl.RedirectURIs, redirect) {
+		problemDetail(c, http.StatusBadRequest, "invalid_redirect_uri", "redirect_uri is not registered for this app.")
+		return nil, "", nil, false
+	}
+	scopes, ok := parseScope(req.Scope)
+	if !ok {
+		problem(c, http.StatusBadRequest, "invalid_scope")
+		return nil, "", nil, false
+	}
+	if req.CodeChallenge != "" && req.CodeChallengeMethod != "S256" {
+		problemDetail(c, http.StatusBadRequest, "invalid_request", "code_challenge_method must be S256.")
+		return nil, "", nil, false
+	}
+	return cl, redirect, scopes, true
+}
+
+// OAuthToken is the token endpoint (RFC 6749 §3.2). It exchanges an authorization
+// code (grant_type=authorization_code) or a refresh token (grant_type=refresh_token)
+// for an access token valid for an hour and a new refresh token. The app authenticates
+// with HTTP Basic or client_id/client_secret form fields. Errors use the OAuth format
+// ({"error": ...}) that client libraries expect rather than problem details.
+func (api *API) OAuthToken(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	clientID, secret, ok := c.Request.BasicAuth()
+	if !ok {
+		clientID, secret = c.PostForm("client_id"), c.PostForm("client_secret")
+	}
+	ctx := c.Request.Context()
+	oauth := api.Repos.OAuthRepo()
+	cl, err := oauth.VerifyClient(ctx, clientID, secret)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if cl == nil {
+		oauthError(c, http.StatusUnauthorized, "invalid_client", "Unknown client or wrong secret.")
+		return
+	}
+
+	access, err := randomToken(oauthAccessPrefix)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	refresh, err := randomToken(oauthRefreshPrefix)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	var tok *repo.OAuthToken
+	switch c.PostForm("grant_type") {
+	case "authorization_code":
+		a, err := oauth.TakeCode(ctx, c.PostForm("code"))
+		if err != nil {
+			fail(c, err)
+			return
+		}
+		if a == nil || a.ClientID != cl.ID || a.RedirectURI != c.PostForm("redirect_uri") {
+			oauthError(c, http.StatusBadRequest, "invalid_grant", "The code is invalid, expired or was issued to another client or redirect URI.")
+			return
+		}
+		if a.CodeChallenge != nil && !verifyPKCE(*a.CodeChallenge, c.PostForm("code_verifier")) {
+			oauthError(c, http.StatusBadRequest, "invalid_grant", "code_verifier does not match the code_challenge.")
+			return
+		}
+		tok, err = oauth.CreateToken(ctx, cl.ID, a.UserID, a.Scopes, access, refresh, oauthAccessTTL)
+		if err != nil {
+			fail(c, err)
+			return
+		}
+	case "refresh_token":
+		tok, err = oauth.Refresh(ctx, cl.ID, c.PostForm("refresh_token"), access, refresh, oauthAccessTTL)
+		if err != nil {
+			fail(c, err)
+			return
+		}
+		if tok == nil {
+			oauthError(c, http.StatusBadRequest, "invalid_grant", "The refresh token is invalid or was revoked.")
+			return
+		}
+	default:
+		oauthError(c, http.StatusBadRequest, "unsupported_grant_type", "grant_type must be authorization_code or refresh_token.")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":  access,
+		"token_type":    "Bearer",
+		"expires_in":    int(oauthAccessTTL.Seconds()),
+		"refresh_token": refresh,
+		"scope":         strings.Join(tok.Scopes, " "),
+	})
+}
+
+// OAuthOrJWT authenticates Bearer OAuth access tokens against tokens, limiting them to
+// the routes their scopes cover like API keys (see RouteScope), and passes any other
+// Bearer token on to jwtAuth.
+func OAuthOrJWT(tokens *repo.OAuthRepo, jwtAuth gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		access, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "+oauthAccessPrefix)
+		if !ok {
+			jwtAuth(c)
+			return
+		}
+		t, err := tokens.Authenticate(c.Request.Context(), oauthAccessPrefix+access)
+		if err != nil {
+			fail(c, err)
+			return
+		}
+		if t == nil {
+			problemDetail(c, http.StatusUnauthorized, "unauthorized", "The access token is invalid, expired or was revoked.")
+			return
+		}
+		if !checkScope(c, t.Scopes) {
+			return
+		}
+		c.Set("uid", t.UserID)
+		c.Set("scopes", t.Scopes)
+		c.Request = c.Request.WithContext(repo.WithTenant(c.Request.Context(), t.UserID))
+		c.Next()
+	}
+}
+
+// parseScope splits a space-separated scope parameter into sorted known scopes,
+// defaulting to ReadScopes; false when a scope is unknown.
+func parseScope(s string) ([]string, bool) {
+	scopes := strings.Fields(s)
+	if len(scopes) == 0 {
+		return ReadScopes, true
+	}
+	for _, sc := range scopes {
+		if !slices.Contains(Scopes, sc) {
+			return nil, false
+		}
+	}
+	slices.Sort(scopes)
+	return slices.Compact(scopes), true
+}
+
+// validRedirectURI accepts https URLs and http ones on a loopback host, without a
+// fragment (RFC 6749 §3.1.2).
+func validRedirectURI(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" || u.Fragment != "" {
+		return false
+	}
+	if u.Scheme == "https" {
+		return true
+	}
+	host := u.Hostname()
+	ip := net.ParseIP(host)
+	return u.Scheme == "http" && (host == "localhost" || (ip != nil && ip.IsLoopback()))
+}
+
+// verifyPKCE reports whether verifier hashes to challenge (RFC 7636, S256).
+func verifyPKCE(challenge, verifier string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	return subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(sum[:])), []byte(challenge)) == 1
+}
+
+// oauthError answers in the OAuth error format (RFC 6749 §5.2).
+func oauthError(c *gin.Context, status int, code, desc string) {
+	c.AbortWithStatusJSON(status, gin.H{"error": code, "error_description": desc})
+}
@@ -0,0 +1,65 @@
+// backend/internal/handler/oauth_test.go
+//
+// Purpose:
+//   Verify that apps cannot register redirect URIs that would leak codes over plain
+//   http or fragments, and that only the authorization-code flow is offered.
+
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateOAuthClient_Invalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api := handler.New(nil, "s") // validation fails before the repository is used
+	r := gin.New()
+	r.POST("/api/me/oauth/clients", func(c *gin.Context) { c.Set("uid", int64(1)) }, api.CreateOAuthClient)
+
+	cases := []struct{ body, code string }{
+		{`{"name":"App","redirect_uris":["http://app.example/cb"]}`, "invalid_redirect_uri"},
+		{`{"name":"App","redirect_uris":["https://app.example/cb#x"]}`, "invalid_redirect_uri"},
+		{`{"name":"App","redirect_uris":["http://localhost:3000/cb","http://evil.example"]}`, "invalid_redirect_uri"},
+		{`{"name":"App","redirect_uris":[]}`, "invalid"},
+		{`{"redirect_uris":["https://app.example/cb"]}`, "invalid"},
+	}
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/me/oauth/clients", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(rec, req)
+		var p map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &p)
+		if rec.Code != http.StatusBadRequest || p["code"] != tc.code {
+			t.Errorf("%s: %d %s, want 400 %s", tc.body, rec.Code, rec.Body, tc.code)
+		}
+	}
+}
+
+func TestOAuthConsent_ResponseType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api := handler.New(nil, "s")
+	r := gin.New()
+	r.GET("/api/oauth/authorize", func(c *gin.Context) { c.Set("uid", int64(1)) }, api.OAuthConsent)
+
+	for target, code := range map[string]string{
+		"/api/oauth/authorize?response_type=token&client_id=pftc_x": "unsupported_response_type", // implicit flow
+		"/api/oauth/authorize?client_id=pftc_x":                     "invalid",
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		var p map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &p)
+		if rec.Code != http.StatusBadRequest || p["code"] != code {
+			t.Errorf("%s: %d %s, want 400 %s", target, rec.Code, rec.Body, code)
+		}
+	}
+}
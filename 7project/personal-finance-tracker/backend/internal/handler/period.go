@@ -0,0 +1,57 @@
+// backend/internal/handler/period.go
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListClosedPeriods returns the months the user has closed.
+func (api *API) ListClosedPeriods(c *gin.Context) {
+	userID := MustUserID(c)
+	out, err := api.Repos.PeriodRepo().List(c.Request.Context(), userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ClosePeriod locks the month given by path parameter :month (YYYY-MM); transactions
+// dated in it can no longer be created, edited or deleted until it is reopened.
+func (api *API) ClosePeriod(c *gin.Context) {
+	userID := MustUserID(c)
+	month := c.Param("month")
+	if !validMonth(month) {
+		problem(c, http.StatusBadRequest, "invalid_month")
+		return
+	}
+	out, err := api.Repos.PeriodRepo().Close(c.Request.Context(), userID, month)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ReopenPeriod unlocks a closed month. Responds with 404 if the month was not closed.
+func (api *API) ReopenPeriod(c *gin.Context) {
+	userID := MustUserID(c)
+	month := c.Param("month")
+	if !validMonth(month) {
+		problem(c, http.StatusBadRequest, "invalid_month")
+		return
+	}
+	ok, err := api.Repos.PeriodRepo().Reopen(c.Request.Context(), userID, month)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,92 @@
+// backend/internal/handler/preferences.go
+
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"pft/internal/i18n"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// preferencesDTO is the JSON shape of user preferences.
+// - WeekStart: lowercase English weekday name, e.g. "monday"
+// - Locale: language of emails and notifications, one of i18n.Supported(); null for the default
+// - BaseCurrency: ISO 4217 code amounts are recorded in; omitted on update to keep it
+type preferencesDTO struct {
+	WeekStart    string  `json:"week_start" binding:"required"`
+	Locale       *string `json:"locale"`
+	BaseCurrency string  `json:"base_currency"`
+}
+
+// parseWeekday maps a lowercase English weekday name to time.Weekday.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), s) {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// toPreferencesDTO converts repository preferences into the API representation.
+func toPreferencesDTO(p *repo.Preferences) preferencesDTO {
+	return preferencesDTO{WeekStart: strings.ToLower(p.WeekStart.String()), Locale: p.Locale, BaseCurrency: p.BaseCurrency}
+}
+
+// GetPreferences returns the authenticated user's preferences.
+func (api *API) GetPreferences(c *gin.Context) {
+	userID := MustUserID(c)
+	p, err := api.Repos.UserRepo().GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if p == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, toPreferencesDTO(p))
+}
+
+// UpdatePreferences replaces the authenticated user's preferences.
+// Responds with 400 if week_start is not a weekday name, locale is not supported, or
+// base_currency is not a currency code.
+func (api *API) UpdatePreferences(c *gin.Context) {
+	userID := MustUserID(c)
+	var req preferencesDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	ws, ok := parseWeekday(req.WeekStart)
+	if !ok {
+		problem(c, http.StatusBadRequest, "invalid_week_start")
+		return
+	}
+	if req.Locale != nil && !i18n.Supports(*req.Locale) {
+		problemDetail(c, http.StatusBadRequest, "invalid_locale",
+			"locale must be one of: "+strings.Join(i18n.Supported(), ", ")+".")
+		return
+	}
+	req.BaseCurrency = strings.ToUpper(req.BaseCurrency)
+	if req.BaseCurrency != "" && !validCurrency(req.BaseCurrency) {
+		problem(c, http.StatusBadRequest, "invalid_currency")
+		return
+	}
+	p, err := api.Repos.UserRepo().UpdatePreferences(c.Request.Context(), userID,
+		&repo.Preferences{WeekStart: ws, Locale: req.Locale, BaseCurrency: req.BaseCurrency})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if p == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, toPreferencesDTO(p))
+}
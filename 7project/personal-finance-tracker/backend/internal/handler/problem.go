@@ -0,0 +1,148 @@
+// backend/internal/handler/problem.go
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"pft/internal/i18n"
+	"pft/internal/repo"
+)
+
+// ProblemContentType is the media type of error responses (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// Problem is the body of every error response (RFC 7807 problem details).
+// - Type: URI reference identifying the problem kind, "/problems/<code>"
+// - Title: short summary of the HTTP status
+// - Status: HTTP status code, repeated for clients that only see the body
+// - Detail: human-readable explanation of this occurrence
+// - Instance: request path the problem occurred on
+// - Code: stable machine-readable identifier (e.g. "not_found", "period_closed")
+// - RequestID: correlation ID, also sent in the X-Request-ID header
+// - Errors: per-field validation problems, when the request body was invalid
+// - Extra: problem-specific extension members merged into the top-level object
+type Problem struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Detail    string         `json:"detail,omitempty"`
+	Instance  string         `json:"instance,omitempty"`
+	Code      string         `json:"code"`
+	RequestID string         `json:"request_id,omitempty"`
+	Errors    []FieldError   `json:"errors,omitempty"`
+	Extra     map[string]any `json:"-"`
+}
+
+// FieldError describes one invalid request field.
+// - Field: JSON name of the field (dotted path for nested values)
+// - Message: why the value was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// MarshalJSON flattens Extra into the object as RFC 7807 extension members.
+// Standard members win over extensions with the same name.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type plain Problem
+	b, err := json.Marshal(plain(p))
+	if err != nil || len(p.Extra) == 0 {
+		return b, err
+	}
+	m := make(map[string]any, len(p.Extra)+8)
+	for k, v := range p.Extra {
+		m[k] = v
+	}
+	var std map[string]any
+	if err := json.Unmarshal(b, &std); err != nil {
+		return nil, err
+	}
+	for k, v := range std {
+		m[k] = v
+	}
+	return json.Marshal(m)
+}
+
+// problem aborts the request with a problem response for code, using the default detail
+// for it ("problem.<code>" in the i18n catalogs) in the client's language.
+func problem(c *gin.Context, status int, code string) {
+	writeProblem(c, Problem{Status: status, Code: code})
+}
+
+// problemDetail is problem with an explicit human-readable detail.
+func problemDetail(c *gin.Context, status int, code, detail string) {
+	writeProblem(c, Problem{Status: status, Code: code, Detail: detail})
+}
+
+// writeProblem fills in the derived members of p and aborts the request with it.
+func writeProblem(c *gin.Context, p Problem) {
+	if p.Code == "" {
+		p.Code = "server"
+	}
+	if p.Type == "" {
+		p.Type = "/problems/" + p.Code
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	if key := "problem." + p.Code; p.Detail == "" && i18n.Has(key) {
+		p.Detail = i18n.T(localeOf(c), key)
+	}
+	if p.Instance == "" {
+		p.Instance = c.Request.URL.Path
+	}
+	if p.RequestID == "" {
+		p.RequestID = c.GetString("request_id")
+	}
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// fail maps an error from the repo layer to a problem response via errorStatus.
+// The error is recorded on the context so AccessLog reports it; it is never sent to the
+// client, except that a category limit is explained so the client can offer an override.
+func fail(c *gin.Context, err error) {
+	_ = c.Error(err)
+	var limit *repo.LimitError
+	if errors.As(err, &limit) {
+		writeProblem(c, Problem{
+			Status: http.StatusConflict,
+			Code:   "category_limit_exceeded",
+			Detail: fmt.Sprintf("Expenses in %q are limited to %.2f each; send override_limit to save it anyway.", limit.Category, limit.Limit),
+			Extra:  map[string]any{"category_id": limit.CategoryID, "limit": limit.Limit},
+		})
+		return
+	}
+	status, code := errorStatus(err)
+	problem(c, status, code)
+}
+
+// errorStatus is the single mapping from repository and database errors to HTTP status and code.
+// Handlers check for more specific conditions first (e.g. which unique constraint failed)
+// and fall back to fail for everything else.
+func errorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return http.StatusNotFound, "not_found"
+	case errors.Is(err, repo.ErrPeriodClosed):
+		return http.StatusConflict, "period_closed"
+	case errors.As(err, new(*repo.LimitError)):
+		return http.StatusConflict, "category_limit_exceeded"
+	case errors.Is(err, repo.ErrFKConflict):
+		return http.StatusConflict, "in_use"
+	case errors.Is(err, repo.ErrUnknownGroup):
+		return http.StatusBadRequest, "unknown_group"
+	case isUniqueViolation(err):
+		return http.StatusConflict, "conflict"
+	case repo.IsTimeout(err):
+		return http.StatusGatewayTimeout, "timeout"
+	default:
+		return http.StatusInternalServerError, "server"
+	}
+}
@@ -0,0 +1,66 @@
+// backend/internal/handler/problem_test.go
+//
+// Purpose:
+//   Verify that error responses are RFC 7807 problem documents and that
+//   extension members are flattened into the top-level object.
+
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestProblem_ResponseShape(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(handler.RequestID())
+	r.GET("/api/me", handler.JWTMiddleware(handler.AuthConfig{JWTSecret: "s"}), func(c *gin.Context) {})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/me", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != handler.ProblemContentType {
+		t.Fatalf("expected %s, got %q", handler.ProblemContentType, ct)
+	}
+	var p map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"type": "/problems/unauthorized", "title": "Unauthorized", "status": float64(401),
+		"code": "unauthorized", "instance": "/api/me", "request_id": rec.Header().Get(handler.RequestIDHeader),
+	}
+	for k, v := range want {
+		if p[k] != v {
+			t.Errorf("%s = %v, want %v", k, p[k], v)
+		}
+	}
+	if p["detail"] == "" {
+		t.Error("expected a detail message")
+	}
+}
+
+func TestProblem_MarshalExtensions(t *testing.T) {
+	b, err := json.Marshal(handler.Problem{
+		Type: "/problems/shares_mismatch", Status: 400, Code: "shares_mismatch",
+		Extra: map[string]any{"expected": 10.5, "code": "ignored"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p map[string]any
+	_ = json.Unmarshal(b, &p)
+	if p["expected"] != 10.5 || p["code"] != "shares_mismatch" {
+		t.Fatalf("unexpected problem JSON: %s", b)
+	}
+}
@@ -0,0 +1,138 @@
+// backend/internal/handler/quickentry.go
+
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"pft/internal/quickentry"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseTxnReq is a line of free text to read a transaction from.
+// - Today: the client's date (YYYY-MM-DD) that "yesterday" or "friday" count from; today in UTC when empty
+type parseTxnReq struct {
+	Text  string `json:"text" binding:"required,max=500"`
+	Today string `json:"today"`
+}
+
+// parsedTxn is a draft transaction read from text, shaped like txnCreateReq so the
+// client can review it and post it to CreateTransaction. CategoryID and AccountID
+// are nil when none matched.
+type parsedTxn struct {
+	CategoryID  *int64  `json:"category_id"`
+	Amount      float64 `json:"amount"`
+	Type        string  `json:"type"`
+	Date        string  `json:"date"`
+	Description string  `json:"description"`
+	AccountID   *int64  `json:"account_id"`
+}
+
+// ParseTransaction reads a draft transaction from free text such as
+// "lunch 12.50 yesterday, cash" for quick-entry fields and chat bots; nothing is
+// saved. The amount and date are read by quickentry.Parse. An account whose name
+// appears in the text is taken out of the description. The category is the one
+// named by a "#tag", else one whose name appears in the text, else the one the user
+// last filed the same description under; the type follows the category unless the
+// amount was written with a "+" (income). Text without an amount gets 422 no_amount.
+func (api *API) ParseTransaction(c *gin.Context) {
+	var req parseTxnReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.Today != "" {
+		t, err := time.Parse("2006-01-02", req.Today)
+		if err != nil {
+			problem(c, http.StatusBadRequest, "invalid_date")
+			return
+		}
+		today = t
+	}
+	d, ok := quickentry.Parse(req.Text, today)
+	if !ok {
+		problemDetail(c, http.StatusUnprocessableEntity, "no_amount", `The text names no amount, e.g. "lunch 12.50 yesterday".`)
+		return
+	}
+
+	ctx, userID := c.Request.Context(), MustUserID(c)
+	out := parsedTxn{Amount: d.Amount, Type: d.Type, Date: d.Date.Format("2006-01-02")}
+	accounts, err := api.Repos.AccountRepo().List(ctx, userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	names := make([]string, len(accounts))
+	for i, a := range accounts {
+		names[i] = a.Name
+	}
+	if i := d.Take(names); i >= 0 {
+		out.AccountID = &accounts[i].ID
+	}
+	out.Description = d.Description()
+
+	cat, err := api.parsedCategory(ctx, userID, &d)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if cat != nil {
+		out.CategoryID, out.Type = &cat.ID, cat.Type
+	}
+	if out.Type == "" {
+		out.Type = "expense"
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// parsedCategory picks the category of d among the user's categories of d.Type (any
+// type when unknown); nil when nothing matches.
+func (api *API) parsedCategory(ctx context.Context, userID int64, d *quickentry.Draft) (*repo.Category, error) {
+	all, err := api.Repos.CategoryRepo().List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var cats []repo.Category
+	for _, c := range all {
+		if d.Type == "" || c.Type == d.Type {
+			cats = append(cats, c)
+		}
+	}
+	if d.Tag != "" {
+		for i, c := range cats {
+			if strings.EqualFold(c.Name, d.Tag) {
+				return &cats[i], nil
+			}
+		}
+	}
+	names := make([]string, len(cats))
+	for i, c := range cats {
+		names[i] = c.Name
+	}
+	if i := d.Mentions(names); i >= 0 {
+		return &cats[i], nil
+	}
+	if d.Description() == "" {
+		return nil, nil
+	}
+	typ := d.Type
+	if typ == "" {
+		typ = "expense"
+	}
+	id, err := api.Repos.TransactionRepo().RecentCategory(ctx, userID, typ, d.Description())
+	if err != nil || id == nil {
+		return nil, err
+	}
+	for i, c := range cats {
+		if c.ID == *id {
+			return &cats[i], nil
+		}
+	}
+	return nil, nil
+}
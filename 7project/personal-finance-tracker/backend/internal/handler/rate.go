@@ -0,0 +1,156 @@
+// backend/internal/handler/rate.go
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"pft/internal/rates"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currencyRe matches ISO 4217 alphabetic codes.
+var currencyRe = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// validCurrency reports whether s is a three-letter uppercase currency code.
+func validCurrency(s string) bool { return currencyRe.MatchString(s) }
+
+// ListRates returns exchange rates for a base currency.
+// Optional query parameters:
+// - base: ISO 4217 code (default "EUR")
+// - date: YYYY-MM-DD; the latest rates on or before this day are returned (default today)
+func (api *API) ListRates(c *gin.Context) {
+	base := strings.ToUpper(c.DefaultQuery("base", "EUR"))
+	if !validCurrency(base) {
+		problem(c, http.StatusBadRequest, "invalid_currency")
+		return
+	}
+	on := time.Now().UTC()
+	if ds := c.Query("date"); ds != "" {
+		d, err := time.Parse("2006-01-02", ds)
+		if err != nil {
+			problem(c, http.StatusBadRequest, "invalid_date")
+			return
+		}
+		on = d
+	}
+	out, err := api.Repos.RateRepo().Rates(c.Request.Context(), base, on)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "rates_unavailable")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// displayConversion reads the optional query parameter "display_currency" and returns
+// the conversion of report amounts from the user's base currency into it; without the
+// parameter amounts stay in the base currency. Writes a problem and returns false when
+// the code is malformed (400 invalid_currency) or no stored exchange rates cover both
+// currencies (400 rates_unavailable).
+func (api *API) displayConversion(c *gin.Context) (repo.Conversion, bool) {
+	display := strings.ToUpper(c.Query("display_currency"))
+	if display == "" {
+		return repo.Conversion{}, true
+	}
+	if !validCurrency(display) {
+		problem(c, http.StatusBadRequest, "invalid_currency")
+		return repo.Conversion{}, false
+	}
+	ctx := c.Request.Context()
+	prefs, err := api.Repos.UserRepo().GetPreferences(ctx, MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return repo.Conversion{}, false
+	}
+	if prefs == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return repo.Conversion{}, false
+	}
+	conv, ok := repo.NewConversion(prefs.BaseCurrency, display)
+	if !ok {
+		problem(c, http.StatusBadRequest, "invalid_currency")
+		return repo.Conversion{}, false
+	}
+	if !conv.Active() {
+		return conv, true
+	}
+	// fx_rate falls back to the nearest stored day, so one day covering the pair is enough.
+	rates, err := api.Repos.RateRepo().Rates(ctx, display, time.Now().UTC())
+	if err != nil {
+		fail(c, err)
+		return repo.Conversion{}, false
+	}
+	if rates == nil || rates.Rates[prefs.BaseCurrency] <= 0 {
+		problemDetail(c, http.StatusBadRequest, "rates_unavailable",
+			"No exchange rates between "+prefs.BaseCurrency+" and "+display+" are available.")
+		return repo.Conversion{}, false
+	}
+	return conv, true
+}
+
+// rateBackfillReq is the body of POST /admin/rates/backfill.
+// - From/To: days in YYYY-MM-DD, both included; To defaults to yesterday
+type rateBackfillReq struct {
+	From string `json:"from" binding:"required"`
+	To   string `json:"to"`
+}
+
+// BackfillRates queues a job fetching the provider's rates for every past day of a
+// range, so historical transactions convert at the rate of their own date. Answers
+// 202 with the queued job; its state and last_error tell how it went.
+// - 409 backfill_unavailable when rates are not refreshed by a job worker here
+// - 400 history_unsupported when the provider only publishes current rates
+// - 409 backfill_pending while an earlier backfill is queued or running
+func (a *Admin) BackfillRates(c *gin.Context) {
+	var req rateBackfillReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.To == "" {
+		req.To = today.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	from, err1 := time.Parse("2006-01-02", req.From)
+	to, err2 := time.Parse("2006-01-02", req.To)
+	if err1 != nil || err2 != nil {
+		problem(c, http.StatusBadRequest, "invalid_date")
+		return
+	}
+	if to.Before(from) || to.After(today) {
+		problemDetail(c, http.StatusBadRequest, "invalid_range", "from must not be after to, and to not after today.")
+		return
+	}
+	if a.Rates == nil {
+		problemDetail(c, http.StatusConflict, "backfill_unavailable",
+			"Exchange rates are not refreshed by a job worker on this instance.")
+		return
+	}
+	if !a.Rates.CanBackfill() {
+		problemDetail(c, http.StatusBadRequest, "history_unsupported",
+			"The rates provider "+a.Rates.Provider.Name()+" does not publish past rates.")
+		return
+	}
+	payload, _ := json.Marshal(rates.BackfillRange{From: req.From, To: req.To})
+	key := rates.BackfillJobKind
+	j, err := a.Repos.JobRepo().Enqueue(c.Request.Context(), &repo.Job{Kind: rates.BackfillJobKind, Payload: payload, UniqueKey: &key})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if j == nil {
+		problem(c, http.StatusConflict, "backfill_pending")
+		return
+	}
+	c.JSON(http.StatusAccepted, j)
+}
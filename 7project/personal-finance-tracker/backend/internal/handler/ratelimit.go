@@ -0,0 +1,44 @@
+// backend/internal/handler/ratelimit.go
+
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit allows perMinute requests per authenticated user (by client IP before
+// authentication) and answers 429 "rate_limited" beyond that. Every response carries
+// RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset (seconds); rejections add
+// Retry-After. If the limiter backend fails the request is let through and the error logged.
+func RateLimit(l ratelimit.Limiter, perMinute int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if _, ok := c.Get("uid"); ok {
+			key = "user:" + strconv.FormatInt(MustUserID(c), 10)
+		}
+		res, err := l.Allow(c.Request.Context(), key, perMinute, time.Minute)
+		if err != nil {
+			slog.Warn("rate limiter unavailable", "request_id", c.GetString("request_id"), "error", err.Error())
+			c.Next()
+			return
+		}
+
+		reset := strconv.Itoa(int((res.Reset + time.Second - 1) / time.Second))
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", reset)
+		if !res.Allowed {
+			c.Header("Retry-After", reset)
+			problem(c, http.StatusTooManyRequests, "rate_limited")
+			return
+		}
+		c.Next()
+	}
+}
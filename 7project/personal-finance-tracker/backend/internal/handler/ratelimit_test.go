@@ -0,0 +1,49 @@
+// backend/internal/handler/ratelimit_test.go
+//
+// Purpose:
+//   Verify the rate-limit middleware counts per user, sets RateLimit-* headers and
+//   answers 429 with Retry-After once the budget is spent.
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pft/internal/handler"
+	"pft/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimit_PerUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("uid", int64(len(c.GetHeader("X-User")))) // test-only stand-in for JWT auth
+		c.Next()
+	})
+	r.Use(handler.RateLimit(ratelimit.NewMemory(), 2))
+	r.GET("/api/x", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/x", nil)
+		req.Header.Set("X-User", user)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("a"); w.Code != http.StatusOK || w.Header().Get("RateLimit-Remaining") != "1" {
+		t.Fatalf("expected 200 with 1 remaining, got %d %v", w.Code, w.Header())
+	}
+	do("a")
+	w := do("a")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" || w.Header().Get("RateLimit-Limit") != "2" {
+		t.Fatalf("expected 429 with headers, got %d %v", w.Code, w.Header())
+	}
+	if w := do("bb"); w.Code != http.StatusOK {
+		t.Fatalf("another user must have its own budget, got %d", w.Code)
+	}
+}
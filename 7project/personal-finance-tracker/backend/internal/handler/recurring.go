@@ -0,0 +1,159 @@
+// backend/internal/handler/recurring.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recurringRuleReq is the payload for creating or updating a recurring rule.
+// - StartDate/EndDate: YYYY-MM-DD; EndDate is optional
+// - Active: generate transactions as they fall due; preview before turning it on
+type recurringRuleReq struct {
+	CategoryID  *int64  `json:"category_id"`
+	Type        string  `json:"type" binding:"required,oneof=income expense"`
+	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	Description string  `json:"description" binding:"max=255"`
+	Interval    string  `json:"interval" binding:"required,oneof=weekly biweekly monthly quarterly yearly"`
+	StartDate   string  `json:"start_date" binding:"required"`
+	EndDate     *string `json:"end_date"`
+	Active      bool    `json:"active"`
+}
+
+// previewMaxYears bounds how far ahead a preview reaches.
+const previewMaxYears = 5
+
+// bindRecurringRule validates the request body and converts it into a repo.RecurringRule.
+// Writes a 400 response and returns nil on failure.
+func bindRecurringRule(c *gin.Context) *repo.RecurringRule {
+	var req recurringRuleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return nil
+	}
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid_date")
+		return nil
+	}
+	rr := &repo.RecurringRule{
+		CategoryID:  req.CategoryID,
+		Type:        req.Type,
+		Amount:      req.Amount,
+		Description: req.Description,
+		Interval:    req.Interval,
+		StartDate:   start,
+		Active:      req.Active,
+	}
+	if req.EndDate != nil && *req.EndDate != "" {
+		d, err := time.Parse("2006-01-02", *req.EndDate)
+		if err != nil || d.Before(start) {
+			problem(c, http.StatusBadRequest, "invalid_date")
+			return nil
+		}
+		rr.EndDate = &d
+	}
+	return rr
+}
+
+// ListRecurringRules returns the user's recurring transaction rules.
+func (api *API) ListRecurringRules(c *gin.Context) {
+	userID := MustUserID(c)
+	out, err := api.Repos.RecurringRuleRepo().List(c.Request.Context(), userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateRecurringRule adds a recurring transaction rule; it stays inactive unless
+// "active" is set.
+func (api *API) CreateRecurringRule(c *gin.Context) {
+	userID := MustUserID(c)
+	rr := bindRecurringRule(c)
+	if rr == nil {
+		return
+	}
+	rr.UserID = userID
+	out, err := api.Repos.RecurringRuleRepo().Create(c.Request.Context(), rr)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// UpdateRecurringRule modifies a rule identified by :id, including (de)activating it.
+func (api *API) UpdateRecurringRule(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	rr := bindRecurringRule(c)
+	if rr == nil {
+		return
+	}
+	out, err := api.Repos.RecurringRuleRepo().Update(c.Request.Context(), userID, id, rr)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteRecurringRule removes a rule; transactions it already generated are kept.
+func (api *API) DeleteRecurringRule(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.RecurringRuleRepo().Delete(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// PreviewRecurringRule lists the transactions rule :id would generate through ?until=
+// (YYYY-MM-DD, default three months from today, at most five years ahead), starting
+// with the first occurrence not yet generated. Works whether or not the rule is active.
+func (api *API) PreviewRecurringRule(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	until := today.AddDate(0, 3, 0)
+	if s := c.Query("until"); s != "" {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			problem(c, http.StatusBadRequest, "invalid_date")
+			return
+		}
+		until = d
+	}
+	if until.After(today.AddDate(previewMaxYears, 0, 0)) {
+		problemDetail(c, http.StatusBadRequest, "invalid_date", "until may be at most 5 years from today.")
+		return
+	}
+	rr, err := api.Repos.RecurringRuleRepo().Get(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if rr == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, repo.Preview(rr, until))
+}
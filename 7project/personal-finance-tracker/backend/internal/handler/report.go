@@ -0,0 +1,159 @@
+// backend/internal/handler/report.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"pft/internal/i18n"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ComparePeriods returns per-category and total deltas between two months.
+// Expects query parameters "a" and "b" in YYYY-MM format; deltas are computed as b - a.
+// Optional query parameter "display_currency" converts amounts (see displayConversion).
+// Responds with 400 if either period is missing or malformed, 500 on repository errors.
+func (api *API) ComparePeriods(c *gin.Context) {
+	userID := MustUserID(c)
+	a, b := c.Query("a"), c.Query("b")
+	if a == "" || b == "" {
+		problem(c, http.StatusBadRequest, "periods_required")
+		return
+	}
+	if !validMonth(a) || !validMonth(b) {
+		problem(c, http.StatusBadRequest, "invalid_month")
+		return
+	}
+	conv, ok := api.displayConversion(c)
+	if !ok {
+		return
+	}
+	out, err := api.Repos.ReportRepo().In(conv).Compare(c.Request.Context(), userID, a, b)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// RecurringCharges lists likely subscriptions and other repeating expenses.
+// Optional query parameter "months" sets the lookback window (default 12, clamped to 3..36);
+// "display_currency" converts amounts.
+func (api *API) RecurringCharges(c *gin.Context) {
+	userID := MustUserID(c)
+	months := asInt(c.Query("months"), 12)
+	if months < 3 {
+		months = 3
+	}
+	if months > 36 {
+		months = 36
+	}
+	since := time.Now().UTC().AddDate(0, -months, 0)
+	conv, ok := api.displayConversion(c)
+	if !ok {
+		return
+	}
+	out, err := api.Repos.ReportRepo().In(conv).Recurring(c.Request.Context(), userID, since)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// YearOverYear compares each month and category of a year against the previous year.
+// Optional query parameter "year" (YYYY) defaults to the current year;
+// "display_currency" converts amounts.
+func (api *API) YearOverYear(c *gin.Context) {
+	userID := MustUserID(c)
+	year := time.Now().UTC().Year()
+	if ys := c.Query("year"); ys != "" {
+		y, err := strconv.Atoi(ys)
+		if err != nil || y < 1900 || y > 9999 {
+			problem(c, http.StatusBadRequest, "invalid_year")
+			return
+		}
+		year = y
+	}
+	conv, ok := api.displayConversion(c)
+	if !ok {
+		return
+	}
+	out, err := api.Repos.ReportRepo().In(conv).YearOverYear(c.Request.Context(), userID, year)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// SpendAverages returns mean and median monthly spend per category.
+// Optional query parameter "months" sets the lookback window of complete months
+// before the current one (default 6, clamped to 1..36); "display_currency" converts amounts.
+func (api *API) SpendAverages(c *gin.Context) {
+	userID := MustUserID(c)
+	months := asInt(c.Query("months"), 6)
+	if months < 1 {
+		months = 1
+	}
+	if months > 36 {
+		months = 36
+	}
+	conv, ok := api.displayConversion(c)
+	if !ok {
+		return
+	}
+	out, err := api.Repos.ReportRepo().In(conv).Averages(c.Request.Context(), userID, months, time.Now().UTC())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// Flows returns a Sankey-ready nodes/links structure of income sources flowing into
+// expense categories for a month. Expects query parameter "month" in YYYY-MM format;
+// optional "display_currency" converts amounts.
+func (api *API) Flows(c *gin.Context) {
+	userID := MustUserID(c)
+	month := c.Query("month")
+	if month == "" {
+		problem(c, http.StatusBadRequest, "month_required")
+		return
+	}
+	if !validMonth(month) {
+		problem(c, http.StatusBadRequest, "invalid_month")
+		return
+	}
+	conv, ok := api.displayConversion(c)
+	if !ok {
+		return
+	}
+	out, err := api.Repos.ReportRepo().In(conv).Flows(c.Request.Context(), userID, month)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	localizeFlows(localeOf(c), out)
+	c.JSON(http.StatusOK, out)
+}
+
+// localizeFlows translates the names of the nodes the report generates itself;
+// category names are the user's own and stay as they are.
+func localizeFlows(lang string, f *repo.Flows) {
+	for i, n := range f.Nodes {
+		switch {
+		case n.ID == "hub":
+			f.Nodes[i].Name = i18n.T(lang, "report.flows.income")
+		case n.ID == "savings", n.ID == "deficit":
+			f.Nodes[i].Name = i18n.T(lang, "report.flows."+n.ID)
+		case strings.HasSuffix(n.ID, ":uncategorized"):
+			f.Nodes[i].Name = i18n.T(lang, "report.flows.uncategorized")
+		}
+	}
+}
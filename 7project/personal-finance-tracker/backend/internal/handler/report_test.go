@@ -0,0 +1,71 @@
+// backend/internal/handler/report_test.go
+//
+// Purpose:
+//   Verify that report endpoints reject missing or malformed period parameters
+//   before touching the repository layer.
+// Method:
+//   Register the handler behind a stub middleware that injects a uid, with a nil
+//   repo store; any repository access would panic and fail the test.
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// withUID mimics the JWT middleware by storing a fixed user ID in the context.
+func withUID(uid int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("uid", uid)
+		c.Next()
+	}
+}
+
+func TestComparePeriods_RejectsBadInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	api := handler.New(nil, "testsecret")
+	r := gin.New()
+	r.GET("/api/reports/compare", withUID(1), api.ComparePeriods)
+
+	cases := []string{
+		"/api/reports/compare",
+		"/api/reports/compare?a=2024-05",
+		"/api/reports/compare?a=2024-05&b=2024-13",
+		"/api/reports/compare?a=May&b=2024-06",
+	}
+	for _, url := range cases {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d (body: %s)", url, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestTaxReport_RejectsBadInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	api := handler.New(nil, "testsecret")
+	r := gin.New()
+	r.GET("/api/reports/tax", withUID(1), api.TaxReport)
+
+	cases := []string{
+		"/api/reports/tax?year=20x4",
+		"/api/reports/tax?year=99",
+		"/api/reports/tax?year=2024&format=xlsx",
+	}
+	for _, url := range cases {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d (body: %s)", url, w.Code, w.Body.String())
+		}
+	}
+}
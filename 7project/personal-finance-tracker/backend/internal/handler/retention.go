@@ -0,0 +1,152 @@
+// backend/internal/handler/retention.go
+
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// retentionReq is the body of PUT /retention-policies/:target.
+// - KeepDays: rows older than this many days are deleted by the daily retention job
+type retentionReq struct {
+	KeepDays int `json:"keep_days" binding:"required,min=1,max=36500"`
+}
+
+// ListRetentionPolicies returns the user's retention policies and the operator
+// policies that apply to everyone.
+func (api *API) ListRetentionPolicies(c *gin.Context) {
+	uid := MustUserID(c)
+	mine, err := api.Repos.RetentionRepo().List(c.Request.Context(), &uid)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	operator, err := api.Repos.RetentionRepo().List(c.Request.Context(), nil)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"policies": mine, "operator_policies": operator})
+}
+
+// PutRetentionPolicy sets how long the user's data of :target ("trash", "drafts" or
+// "transactions") is kept. Transactions are deleted by date, except in closed months.
+func (api *API) PutRetentionPolicy(c *gin.Context) {
+	uid := MustUserID(c)
+	putRetentionPolicy(c, api.Repos, &uid, repo.RetentionTargets)
+}
+
+// DeleteRetentionPolicy removes the user's policy for :target; their data is then kept
+// unless an operator policy applies.
+func (api *API) DeleteRetentionPolicy(c *gin.Context) {
+	uid := MustUserID(c)
+	deleteRetentionPolicy(c, api.Repos, &uid)
+}
+
+// PreviewRetention is a dry run of every policy covering the user's data, their own and
+// the operator's, reporting how many rows each would delete today.
+func (api *API) PreviewRetention(c *gin.Context) {
+	uid := MustUserID(c)
+	ctx := c.Request.Context()
+	mine, err := api.Repos.RetentionRepo().List(ctx, &uid)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	operator, err := api.Repos.RetentionRepo().List(ctx, nil)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	for _, p := range operator {
+		if slices.Contains(repo.RetentionTargets, p.Target) {
+			p.UserID = &uid // only this user's share of an operator policy
+			mine = append(mine, p)
+		}
+	}
+	previewRetention(c, api.Repos, mine)
+}
+
+// ListRetention returns the operator retention policies.
+func (a *Admin) ListRetention(c *gin.Context) {
+	out, err := a.Repos.RetentionRepo().List(c.Request.Context(), nil)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// PutRetention sets an operator policy for :target, applying to every user; "jobs"
+// (finished background jobs) is available to operators only, "transactions" to users only.
+func (a *Admin) PutRetention(c *gin.Context) {
+	putRetentionPolicy(c, a.Repos, nil, repo.OperatorRetentionTargets)
+}
+
+// DeleteRetention removes the operator policy for :target.
+func (a *Admin) DeleteRetention(c *gin.Context) {
+	deleteRetentionPolicy(c, a.Repos, nil)
+}
+
+// PreviewRetention is a dry run of every policy, operator and user ones, as the
+// daily retention job would apply them today.
+func (a *Admin) PreviewRetention(c *gin.Context) {
+	all, err := a.Repos.RetentionRepo().All(c.Request.Context())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	previewRetention(c, a.Repos, all)
+}
+
+func putRetentionPolicy(c *gin.Context, repos *repo.Store, userID *int64, targets []string) {
+	target := c.Param("target")
+	if !slices.Contains(targets, target) {
+		problem(c, http.StatusBadRequest, "invalid_target")
+		return
+	}
+	var req retentionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	out, err := repos.RetentionRepo().Set(c.Request.Context(), userID, target, req.KeepDays)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func deleteRetentionPolicy(c *gin.Context, repos *repo.Store, userID *int64) {
+	ok, err := repos.RetentionRepo().Delete(c.Request.Context(), userID, c.Param("target"))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func previewRetention(c *gin.Context, repos *repo.Store, policies []repo.RetentionPolicy) {
+	now := time.Now()
+	out := make([]*repo.RetentionResult, 0, len(policies))
+	for _, p := range policies {
+		res, err := repos.RetentionRepo().Apply(c.Request.Context(), p, now, true)
+		if err != nil {
+			fail(c, err)
+			return
+		}
+		out = append(out, res)
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,60 @@
+// backend/internal/handler/retention_test.go
+//
+// Purpose:
+//   Verify that retention policies are rejected for unknown, operator-only or
+//   user-only targets and malformed periods before anything is stored.
+
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPutRetentionPolicy_Invalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api := handler.New(nil, "s") // validation fails before the repository is used
+	r := gin.New()
+	r.PUT("/api/retention-policies/:target", func(c *gin.Context) { c.Set("uid", int64(1)) }, api.PutRetentionPolicy)
+
+	cases := []struct{ target, body, code string }{
+		{"attachments", `{"keep_days":30}`, "invalid_target"},
+		{"jobs", `{"keep_days":30}`, "invalid_target"}, // operators only
+		{"drafts", `{"keep_days":0}`, "invalid"},
+	}
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/api/retention-policies/"+tc.target, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(rec, req)
+		var p map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &p)
+		if rec.Code != http.StatusBadRequest || p["code"] != tc.code {
+			t.Errorf("%s %s: %d %s, want 400 %s", tc.target, tc.body, rec.Code, rec.Body, tc.code)
+		}
+	}
+}
+
+func TestPutRetention_UserOnlyTarget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	adm := &handler.Admin{} // refused before the repository is used
+	r := gin.New()
+	r.PUT("/api/admin/retention/:target", adm.PutRetention)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/admin/retention/transactions", strings.NewReader(`{"keep_days":30}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(rec, req)
+	var p map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &p)
+	if rec.Code != http.StatusBadRequest || p["code"] != "invalid_target" {
+		t.Errorf("operator transactions policy: %d %s, want 400 invalid_target", rec.Code, rec.Body)
+	}
+}
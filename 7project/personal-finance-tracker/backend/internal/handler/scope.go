@@ -0,0 +1,101 @@
+// backend/internal/handler/scope.go
+
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Scopes are the permissions an API key or OAuth app can be granted. A ":write" scope
+// includes the matching ":read" one.
+var Scopes = []string{
+	"transactions:read", "transactions:write",
+	"budgets:read", "budgets:write",
+	"categories:read", "categories:write",
+	"reports:read",
+}
+
+// ReadScopes are granted to API keys created, and OAuth apps authorized, without
+// explicit scopes.
+var ReadScopes = []string{"transactions:read", "budgets:read", "categories:read", "reports:read"}
+
+// routeScopes names the scope each route requires when called with an API key or an
+// OAuth access token, keyed by method and route path. Routes not listed are reserved
+// for the user's own sessions (JWT), so account settings, keys, OAuth consent and
+// exports cannot be reached with either; "" means any valid key will do.
+var routeScopes = map[string]string{
+	"GET /api/zapier/me":                       "",
+	"POST /api/batch":                          "", // each sub-request is checked on its own
+	"GET /api/zapier/triggers/new-transaction": "transactions:read",
+	"GET /api/zapier/triggers/budget-exceeded": "budgets:read",
+	"GET /api/transactions":                    "transactions:read",
+	"POST /api/transactions":                   "transactions:write",
+	"POST /api/transactions/parse":             "transactions:read", // saves nothing
+	"PUT /api/transactions/:id":                "transactions:write",
+	"DELETE /api/transactions/:id":             "transactions:write",
+	"GET /api/transactions/:id/split":          "transactions:read",
+	"PUT /api/transactions/:id/split":          "transactions:write",
+	"DELETE /api/transactions/:id/split":       "transactions:write",
+	"GET /api/categories":                      "categories:read",
+	"POST /api/categories":                     "categories:write",
+	"PUT /api/categories/:id":                  "categories:write",
+	"DELETE /api/categories/:id":               "categories:write",
+	"GET /api/category-groups":                 "categories:read",
+	"POST /api/category-groups":                "categories:write",
+	"PUT /api/category-groups/:id":             "categories:write",
+	"DELETE /api/category-groups/:id":          "categories:write",
+	"GET /api/budgets":                         "budgets:read",
+	"POST /api/budgets":                        "budgets:write",
+	"PUT /api/budgets/:id":                     "budgets:write",
+	"DELETE /api/budgets/:id":                  "budgets:write",
+	"GET /api/dashboard/summary":               "reports:read",
+	"GET /api/dashboard/summary/week":          "reports:read",
+	"GET /api/dashboard/daily":                 "reports:read",
+	"GET /api/dashboard/top":                   "reports:read",
+	"GET /api/dashboard/projection":            "reports:read",
+	"GET /api/dashboard/score":                 "reports:read",
+	"GET /api/dashboard/score/breakdown":       "reports:read",
+	"GET /api/reports/compare":                 "reports:read",
+	"GET /api/reports/recurring":               "reports:read",
+	"GET /api/reports/yoy":                     "reports:read",
+	"GET /api/reports/averages":                "reports:read",
+	"GET /api/reports/flows":                   "reports:read",
+	"GET /api/reports/tax":                     "reports:read",
+	"GET /api/accounts/:id/statement":          "reports:read",
+}
+
+// RouteScope returns the scope an API key needs for method and route path, and false
+// when the route cannot be called with an API key.
+func RouteScope(method, path string) (string, bool) {
+	s, ok := routeScopes[method+" "+path]
+	return s, ok
+}
+
+// hasScope reports whether granted covers need, counting ":write" as ":read" too.
+func hasScope(granted []string, need string) bool {
+	if need == "" || slices.Contains(granted, need) {
+		return true
+	}
+	if res, ok := strings.CutSuffix(need, ":read"); ok {
+		return slices.Contains(granted, res+":write")
+	}
+	return false
+}
+
+// checkScope aborts with 403 unless scopes allow the matched route.
+func checkScope(c *gin.Context, scopes []string) bool {
+	need, ok := RouteScope(c.Request.Method, c.FullPath())
+	if !ok {
+		problemDetail(c, http.StatusForbidden, "insufficient_scope", "This endpoint cannot be called with an API key.")
+		return false
+	}
+	if !hasScope(scopes, need) {
+		problemDetail(c, http.StatusForbidden, "insufficient_scope", "The API key lacks the "+need+" scope.")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+// backend/internal/handler/scope_test.go
+//
+// Purpose:
+//   Verify which routes API keys may call and with what scope, and that KeyOrJWT
+//   picks the authenticator by the credential the request carries.
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRouteScope(t *testing.T) {
+	cases := []struct {
+		method, path, scope string
+		ok                  bool
+	}{
+		{"GET", "/api/transactions", "transactions:read", true},
+		{"POST", "/api/transactions", "transactions:write", true},
+		{"GET", "/api/reports/tax", "reports:read", true},
+		{"GET", "/api/zapier/me", "", true},
+		{"GET", "/api/me/api-keys", "", false}, // sessions only
+		{"POST", "/api/exports", "", false},
+	}
+	for _, tc := range cases {
+		scope, ok := handler.RouteScope(tc.method, tc.path)
+		if scope != tc.scope || ok != tc.ok {
+			t.Errorf("%s %s = %q, %v; want %q, %v", tc.method, tc.path, scope, ok, tc.scope, tc.ok)
+		}
+	}
+}
+
+func TestKeyOrJWT(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var used string
+	mw := handler.KeyOrJWT(func(*gin.Context) { used = "key" }, func(*gin.Context) { used = "jwt" })
+	cases := []struct {
+		target, header, want string
+	}{
+		{"/api/transactions", "", "jwt"},
+		{"/api/transactions?api_key=pft_1", "", "key"},
+		{"/api/transactions", "pft_1", "key"},
+	}
+	for _, tc := range cases {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, tc.target, nil)
+		if tc.header != "" {
+			c.Request.Header.Set("X-API-Key", tc.header)
+		}
+		mw(c)
+		if used != tc.want {
+			t.Errorf("%s (header %q): used %s, want %s", tc.target, tc.header, used, tc.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// backend/internal/handler/score.go
+
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"pft/internal/i18n"
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// healthScore loads the score for the "month" query parameter (default: current month).
+// Writes the error response itself and returns nil on failure.
+func (api *API) healthScore(c *gin.Context) *repo.HealthScore {
+	userID := MustUserID(c)
+	month := c.DefaultQuery("month", time.Now().UTC().Format("2006-01"))
+	if !validMonth(month) {
+		problem(c, http.StatusBadRequest, "invalid_month")
+		return nil
+	}
+	out, err := api.Repos.DashboardRepo().Health(c.Request.Context(), userID, month)
+	if err != nil {
+		fail(c, err)
+		return nil
+	}
+	return out
+}
+
+// HealthScore returns the composite financial health score (0..100) for a month.
+// Optional query parameter "month" in YYYY-MM format defaults to the current month.
+func (api *API) HealthScore(c *gin.Context) {
+	out := api.healthScore(c)
+	if out == nil {
+		return
+	}
+	out.Components = nil
+	c.JSON(http.StatusOK, out)
+}
+
+// HealthScoreBreakdown returns the score together with each component's metric,
+// rating, weight and a short explanation in the client's language.
+func (api *API) HealthScoreBreakdown(c *gin.Context) {
+	out := api.healthScore(c)
+	if out == nil {
+		return
+	}
+	lang := localeOf(c)
+	for i, comp := range out.Components {
+		out.Components[i].Explanation = i18n.T(lang, comp.Message, comp.Args...)
+	}
+	c.JSON(http.StatusOK, out)
+}
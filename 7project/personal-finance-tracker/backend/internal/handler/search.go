@@ -0,0 +1,33 @@
+// backend/internal/handler/search.go
+
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Search looks up ?q= across the user's categories, payees, transactions and notes and
+// returns typed hits ranked by relevance, for command-palette style navigation.
+// ?limit= caps the number of hits (default 20, at most 50).
+func (api *API) Search(c *gin.Context) {
+	userID := MustUserID(c)
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" || utf8.RuneCountInString(q) > 100 {
+		problemDetail(c, http.StatusBadRequest, "invalid_query", "q must be 1 to 100 characters.")
+		return
+	}
+	limit := asInt(c.Query("limit"), 20)
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+	out, err := api.Repos.SearchRepo().Search(c.Request.Context(), userID, q, limit)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
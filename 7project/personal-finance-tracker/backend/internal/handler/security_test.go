@@ -0,0 +1,61 @@
+// backend/internal/handler/security_test.go
+//
+// Purpose:
+//   Verify hardening headers, the request body size limit and the JSON Content-Type check.
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func securityRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(handler.SecurityHeaders(3600), handler.LimitBody(16), handler.RequireJSON())
+	r.POST("/api/x", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	r.GET("/api/x", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	w := httptest.NewRecorder()
+	securityRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/x", nil))
+	h := w.Header()
+	if h.Get("X-Content-Type-Options") != "nosniff" || h.Get("X-Frame-Options") != "DENY" ||
+		h.Get("Strict-Transport-Security") != "max-age=3600; includeSubDomains" {
+		t.Fatalf("missing security headers: %v", h)
+	}
+}
+
+func TestRequestBodyChecks(t *testing.T) {
+	cases := []struct {
+		name, ctype, body string
+		want              int
+	}{
+		{"json ok", "application/json; charset=utf-8", `{"a":1}`, http.StatusNoContent},
+		{"too large", "application/json", `{"a":"0123456789abcdef"}`, http.StatusRequestEntityTooLarge},
+		{"wrong type", "text/plain", `{"a":1}`, http.StatusUnsupportedMediaType},
+		{"missing type", "", `{"a":1}`, http.StatusUnsupportedMediaType},
+		{"no body", "", ``, http.StatusNoContent},
+	}
+	r := securityRouter()
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/x", strings.NewReader(tc.body))
+		if tc.ctype != "" {
+			req.Header.Set("Content-Type", tc.ctype)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.want, w.Code)
+		}
+	}
+}
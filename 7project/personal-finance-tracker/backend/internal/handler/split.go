@@ -0,0 +1,232 @@
+// backend/internal/handler/split.go
+
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contactReq is the payload for creating a contact.
+type contactReq struct {
+	Name  string `json:"name" binding:"required,min=1,max=100"`
+	Email string `json:"email" binding:"omitempty,email"`
+}
+
+// splitReq divides a transaction among participants.
+// - PayerContactID: who paid; omit/null when the user paid
+// - Shares: participants and their portions (null contact_id = the user); must add up to the transaction amount
+type splitReq struct {
+	PayerContactID *int64            `json:"payer_contact_id"`
+	Shares         []repo.SplitShare `json:"shares" binding:"required,min=1"`
+}
+
+// settlementReq records money exchanged with a contact.
+// - Direction: "received" (contact paid the user) or "paid" (user paid the contact)
+type settlementReq struct {
+	ContactID int64   `json:"contact_id" binding:"required"`
+	Amount    float64 `json:"amount" binding:"required,gt=0"`
+	Direction string  `json:"direction" binding:"required,oneof=received paid"`
+	Date      string  `json:"date"` // YYYY-MM-DD, defaults to today
+	Note      string  `json:"note" binding:"max=500"`
+}
+
+// ListContacts returns the user's contacts.
+func (api *API) ListContacts(c *gin.Context) {
+	userID := MustUserID(c)
+	out, err := api.Repos.SplitRepo().ListContacts(c.Request.Context(), userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateContact adds a contact. Responds with 409 for a duplicate name.
+func (api *API) CreateContact(c *gin.Context) {
+	userID := MustUserID(c)
+	var req contactReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	out, err := api.Repos.SplitRepo().CreateContact(c.Request.Context(), userID, req.Name, req.Email)
+	if err != nil {
+		if isUniqueViolation(err) {
+			problem(c, http.StatusConflict, "contact_exists")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// DeleteContact removes a contact. Responds with 409 while splits or settlements reference it.
+func (api *API) DeleteContact(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.SplitRepo().DeleteContact(c.Request.Context(), userID, id)
+	if err != nil {
+		if errors.Is(err, repo.ErrFKConflict) {
+			problem(c, http.StatusConflict, "contact_in_use")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetSplit returns the split of transaction :id, or 404 if it is not split.
+func (api *API) GetSplit(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	out, err := api.Repos.SplitRepo().GetSplit(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// PutSplit creates or replaces the split of transaction :id.
+// Responds with 400 when shares do not add up to the transaction amount.
+func (api *API) PutSplit(c *gin.Context) {
+	userID := MustUserID(c)
+	ctx := c.Request.Context()
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req splitReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	txn, err := api.Repos.TransactionRepo().Get(ctx, userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if txn == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	var sum float64
+	for _, sh := range req.Shares {
+		if sh.Amount < 0 {
+			problem(c, http.StatusBadRequest, "invalid")
+			return
+		}
+		sum += sh.Amount
+	}
+	if math.Abs(sum-txn.Amount) > 0.005 {
+		writeProblem(c, Problem{Status: http.StatusBadRequest, Code: "shares_mismatch",
+			Detail: "Split shares must add up to the transaction amount.",
+			Extra:  map[string]any{"expected": txn.Amount, "got": math.Round(sum*100) / 100}})
+		return
+	}
+
+	out, err := api.Repos.SplitRepo().SetSplit(ctx, userID, &repo.Split{
+		TransactionID:  id,
+		PayerContactID: req.PayerContactID,
+		Shares:         req.Shares,
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			problem(c, http.StatusNotFound, "not_found")
+			return
+		}
+		if isUniqueViolation(err) {
+			problem(c, http.StatusBadRequest, "duplicate_participant")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteSplit removes the split of transaction :id.
+func (api *API) DeleteSplit(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.SplitRepo().DeleteSplit(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// CreateSettlement records a payment received from or made to a contact.
+func (api *API) CreateSettlement(c *gin.Context) {
+	userID := MustUserID(c)
+	var req settlementReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	s := &repo.Settlement{ContactID: req.ContactID, Amount: req.Amount, Date: time.Now().UTC(), Note: req.Note}
+	if req.Direction == "paid" {
+		s.Amount = -s.Amount
+	}
+	if req.Date != "" {
+		d, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			problem(c, http.StatusBadRequest, "invalid_date")
+			return
+		}
+		s.Date = d
+	}
+	out, err := api.Repos.SplitRepo().CreateSettlement(c.Request.Context(), userID, s)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			problem(c, http.StatusNotFound, "not_found")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// SplitBalances returns who owes whom and a minimal set of settlement transfers.
+func (api *API) SplitBalances(c *gin.Context) {
+	userID := MustUserID(c)
+	balances, err := api.Repos.SplitRepo().Balances(c.Request.Context(), userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	// The user's own net position is the mirror image of all contact balances.
+	net := map[string]float64{}
+	var mine float64
+	for _, b := range balances {
+		net[b.Name] -= b.Balance
+		mine += b.Balance
+	}
+	net["me"] = mine
+	c.JSON(http.StatusOK, gin.H{
+		"balances":    balances,
+		"suggestions": repo.SuggestSettlements(net),
+	})
+}
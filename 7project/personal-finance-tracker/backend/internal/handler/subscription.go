@@ -0,0 +1,159 @@
+// backend/internal/handler/subscription.go
+
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// subscriptionReq is the payload for creating or updating a subscription.
+// - RenewalDate/CancelBy: YYYY-MM-DD; CancelBy is optional
+type subscriptionReq struct {
+	CategoryID      *int64  `json:"category_id"`
+	Service         string  `json:"service" binding:"required,min=1,max=100"`
+	Amount          float64 `json:"amount" binding:"gte=0"`
+	BillingInterval string  `json:"billing_interval" binding:"required,oneof=weekly biweekly monthly quarterly yearly"`
+	RenewalDate     string  `json:"renewal_date" binding:"required"`
+	CancelBy        *string `json:"cancel_by"`
+}
+
+// bindSubscription validates the request body and converts it into a repo.Subscription.
+// Writes a 400 response and returns nil on failure.
+func bindSubscription(c *gin.Context) *repo.Subscription {
+	var req subscriptionReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return nil
+	}
+	renewal, err := time.Parse("2006-01-02", req.RenewalDate)
+	if err != nil {
+		problem(c, http.StatusBadRequest, "invalid_date")
+		return nil
+	}
+	s := &repo.Subscription{
+		CategoryID:      req.CategoryID,
+		Service:         req.Service,
+		Amount:          req.Amount,
+		BillingInterval: req.BillingInterval,
+		RenewalDate:     renewal,
+	}
+	if req.CancelBy != nil && *req.CancelBy != "" {
+		d, err := time.Parse("2006-01-02", *req.CancelBy)
+		if err != nil {
+			problem(c, http.StatusBadRequest, "invalid_date")
+			return nil
+		}
+		s.CancelBy = &d
+	}
+	return s
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique constraint violation (SQLSTATE 23505).
+func isUniqueViolation(err error) bool {
+	var pgerr *pgconn.PgError
+	return errors.As(err, &pgerr) && pgerr.Code == "23505"
+}
+
+// ListSubscriptions returns the user's subscriptions with next renewal and monthly cost,
+// plus the combined monthly total.
+func (api *API) ListSubscriptions(c *gin.Context) {
+	userID := MustUserID(c)
+	subs, err := api.Repos.SubscriptionRepo().List(c.Request.Context(), userID, time.Now().UTC())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	var total float64
+	for _, s := range subs {
+		total += s.MonthlyCost
+	}
+	c.JSON(http.StatusOK, gin.H{"subscriptions": subs, "monthly_total": math.Round(total*100) / 100})
+}
+
+// CreateSubscription adds a subscription. Responds with 409 if the service is already tracked.
+func (api *API) CreateSubscription(c *gin.Context) {
+	userID := MustUserID(c)
+	s := bindSubscription(c)
+	if s == nil {
+		return
+	}
+	s.UserID = userID
+	out, err := api.Repos.SubscriptionRepo().Create(c.Request.Context(), s)
+	if err != nil {
+		if isUniqueViolation(err) {
+			problem(c, http.StatusConflict, "subscription_exists")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// UpdateSubscription modifies a subscription identified by :id.
+func (api *API) UpdateSubscription(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	s := bindSubscription(c)
+	if s == nil {
+		return
+	}
+	out, err := api.Repos.SubscriptionRepo().Update(c.Request.Context(), userID, id, s)
+	if err != nil {
+		if isUniqueViolation(err) {
+			problem(c, http.StatusConflict, "subscription_exists")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteSubscription removes a subscription by ID.
+func (api *API) DeleteSubscription(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.SubscriptionRepo().Delete(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ImportDetectedSubscriptions runs the recurring-spend detector over the last 12 months
+// and adds every detected charge not yet tracked as a subscription.
+// Returns the newly created subscriptions.
+func (api *API) ImportDetectedSubscriptions(c *gin.Context) {
+	userID := MustUserID(c)
+	ctx := c.Request.Context()
+	charges, err := api.Repos.ReportRepo().Recurring(ctx, userID, time.Now().UTC().AddDate(-1, 0, 0))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	out, err := api.Repos.SubscriptionRepo().ImportDetected(ctx, userID, charges)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
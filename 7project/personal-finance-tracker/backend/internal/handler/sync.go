@@ -0,0 +1,294 @@
+// backend/internal/handler/sync.go
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// syncMaxItems caps the changes returned per sync request.
+const syncMaxItems = 1000
+
+// Sync returns the categories, budgets, accounts and transactions changed since the
+// sync token ?since= (omitted on a client's first sync, which returns everything),
+// at most ?limit= (default 500, max 1000) per request. Each change carries the row's
+// current state ("upsert") or is a tombstone ("delete"), with the version to base
+// edits on (see SyncWrite). Clients store "next" and pass it as since; while
+// "has_more" is true they should ask again right away, and should apply a page as a
+// whole since rows may refer to rows later in the page.
+func (api *API) Sync(c *gin.Context) {
+	cur, err := repo.ParseSyncCursor(c.Query("since"))
+	if err != nil {
+		problemDetail(c, http.StatusBadRequest, "invalid_sync_token", "since must be a token returned by a previous sync.")
+		return
+	}
+	limit := min(max(asInt(c.Query("limit"), 500), 1), syncMaxItems)
+	page, err := api.Repos.SyncRepo().Changes(c.Request.Context(), MustUserID(c), cur, limit)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"changes": page.Changes, "next": page.Next.String(), "has_more": page.HasMore})
+}
+
+// errSyncRejected rolls back a sync write that was refused; see applySyncWrite.
+var errSyncRejected = errors.New("sync write rejected")
+
+// syncWriteReq is a batch of offline edits, applied in order.
+type syncWriteReq struct {
+	Changes []syncWrite `json:"changes" binding:"required,min=1,max=100,dive"`
+}
+
+// syncWrite is one offline edit.
+// - ID: the row to update or delete; omitted to create one
+// - ClientRef: the client's own id for the edit, echoed in its result to map new rows
+// - Op: "upsert" to create or update with Data, "delete" to delete
+// - BaseVersion: version of the row the edit was made on, from a previous sync
+// - Data: the row as for the entity's REST endpoint (POST/PUT /api/<entity>)
+type syncWrite struct {
+	Entity      string          `json:"entity" binding:"required,oneof=categories budgets accounts transactions"`
+	ID          *int64          `json:"id"`
+	ClientRef   string          `json:"client_ref" binding:"max=100"`
+	Op          string          `json:"op" binding:"required,oneof=upsert delete"`
+	BaseVersion int64           `json:"base_version"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// syncWriteResult is the outcome of one syncWrite, with the row's state on the server
+// afterwards (omitted when a create is rejected). A conflict means the row changed
+// since BaseVersion; State is then the server's row to merge with.
+// - Status: "accepted", "conflict" or "rejected"
+// - Code: why an edit was rejected, as the REST endpoint would answer ("invalid", "not_found", "period_closed", ...)
+type syncWriteResult struct {
+	ClientRef string           `json:"client_ref,omitempty"`
+	Status    string           `json:"status"`
+	Code      string           `json:"code,omitempty"`
+	State     *repo.SyncChange `json:"state,omitempty"`
+}
+
+// SyncWrite applies a batch of edits made offline and answers with a result per edit,
+// in order. Each edit is applied on its own: updates and deletes only when the row is
+// still at the edit's base version, so a client never overwrites a change it has not
+// seen; it gets the row's current state back as a conflict instead. Deleting a row
+// that is already deleted is accepted. A server error stops the batch with 500 after
+// the edits before it were applied; syncing again shows which.
+func (api *API) SyncWrite(c *gin.Context) {
+	var req syncWriteReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	userID := MustUserID(c)
+	out := make([]syncWriteResult, 0, len(req.Changes))
+	for _, w := range req.Changes {
+		res, err := api.applySyncWrite(c.Request.Context(), userID, w)
+		if err != nil {
+			fail(c, err)
+			return
+		}
+		out = append(out, res)
+	}
+	c.JSON(http.StatusOK, gin.H{"results": out})
+}
+
+// applySyncWrite applies w in its own transaction, holding the row's change-log entry
+// locked from the version check to the write. Refused edits roll back, and their
+// result carries the row's state as read afterwards.
+func (api *API) applySyncWrite(ctx context.Context, userID int64, w syncWrite) (syncWriteResult, error) {
+	res := syncWriteResult{ClientRef: w.ClientRef}
+	refuse := func(status, code string) error {
+		res.Status, res.Code = status, code
+		return errSyncRejected
+	}
+	err := api.Repos.WithTx(ctx, func(tx *repo.Store) error {
+		sync := tx.SyncRepo()
+		if w.ID != nil {
+			cur, err := sync.Lock(ctx, userID, w.Entity, *w.ID)
+			if err != nil {
+				return err
+			}
+			switch {
+			case cur == nil:
+				return refuse("rejected", "not_found")
+			case cur.Op == "delete" && w.Op == "delete":
+				res.Status, res.State = "accepted", cur
+				return nil
+			case cur.Version != w.BaseVersion:
+				return refuse("conflict", "")
+			case cur.Op == "delete":
+				return refuse("rejected", "not_found")
+			}
+		}
+		id, code, err := writeSyncEntity(ctx, tx, userID, w)
+		if err != nil {
+			return err
+		}
+		if code != "" {
+			return refuse("rejected", code)
+		}
+		state, err := sync.Current(ctx, userID, w.Entity, id)
+		if err != nil {
+			return err
+		}
+		res.Status, res.State = "accepted", state
+		return nil
+	})
+	if !errors.Is(err, errSyncRejected) {
+		return res, err
+	}
+	if w.ID != nil {
+		res.State, err = api.Repos.SyncRepo().Current(ctx, userID, w.Entity, *w.ID)
+	}
+	return res, err
+}
+
+// writeSyncEntity creates, updates or deletes the row w names, validating Data as the
+// entity's REST endpoint does. It returns the row's ID, or the error code of a write
+// the endpoint would refuse.
+func writeSyncEntity(ctx context.Context, tx *repo.Store, userID int64, w syncWrite) (int64, string, error) {
+	var id int64
+	if w.ID != nil {
+		id = *w.ID
+	}
+	if w.Op == "delete" {
+		if w.ID == nil {
+			return 0, "invalid", nil
+		}
+		var ok bool
+		var err error
+		switch w.Entity {
+		case "categories":
+			ok, err = tx.CategoryRepo().Delete(ctx, userID, id)
+		case "budgets":
+			ok, err = tx.BudgetRepo().Delete(ctx, userID, id)
+		case "accounts":
+			ok, err = tx.AccountRepo().Delete(ctx, userID, id)
+		case "transactions":
+			ok, err = tx.TransactionRepo().Delete(ctx, userID, id)
+		}
+		switch {
+		case errors.Is(err, repo.ErrFKConflict):
+			return id, "category_has_budgets", nil
+		case errors.Is(err, repo.ErrPeriodClosed):
+			return id, "period_closed", nil
+		case err != nil:
+			return id, "", err
+		case !ok:
+			return id, "not_found", nil
+		}
+		return id, "", nil
+	}
+
+	decode := func(req any) bool {
+		return json.Unmarshal(w.Data, req) == nil && binding.Validator.ValidateStruct(req) == nil
+	}
+	var saved bool
+	var err error
+	switch w.Entity {
+	case "categories":
+		var req categoryCreateReq
+		if !decode(&req) {
+			return id, "invalid", nil
+		}
+		var out *repo.Category
+		if w.ID == nil {
+			out, err = tx.CategoryRepo().Create(ctx, req.model(userID))
+		} else {
+			out, err = tx.CategoryRepo().Update(ctx, userID, id, req.model(userID))
+		}
+		if isUniqueViolation(err) {
+			return id, "category_exists", nil
+		}
+		if saved = out != nil; saved {
+			id = out.ID
+		}
+	case "budgets":
+		var req budgetCreateReq
+		if !decode(&req) {
+			return id, "invalid", nil
+		}
+		b := &repo.Budget{UserID: userID, CategoryID: req.CategoryID, GroupID: req.GroupID, PeriodMonth: req.PeriodMonth, LimitAmount: req.LimitAmount}
+		var out *repo.Budget
+		if w.ID == nil {
+			out, err = tx.BudgetRepo().Create(ctx, b)
+		} else {
+			out, err = tx.BudgetRepo().Update(ctx, userID, id, b)
+		}
+		if isUniqueViolation(err) {
+			return id, "budget_exists", nil
+		}
+		if saved = out != nil; saved {
+			id = out.ID
+		}
+	case "accounts":
+		var req accountReq
+		if !decode(&req) {
+			return id, "invalid", nil
+		}
+		a := &repo.Account{UserID: userID, Name: req.Name, OpeningBalance: req.OpeningBalance}
+		var out *repo.Account
+		if w.ID == nil {
+			out, err = tx.AccountRepo().Create(ctx, a)
+		} else {
+			out, err = tx.AccountRepo().Update(ctx, userID, id, a)
+		}
+		if saved = out != nil; saved {
+			id = out.ID
+		}
+	case "transactions":
+		var req txnCreateReq
+		if !decode(&req) {
+			return id, "invalid", nil
+		}
+		d, perr := time.Parse("2006-01-02", req.Date)
+		if perr != nil {
+			return id, "invalid_date", nil
+		}
+		if req.AccountID != nil {
+			a, err := tx.AccountRepo().Get(ctx, userID, *req.AccountID)
+			if err != nil {
+				return id, "", err
+			}
+			if a == nil {
+				return id, "invalid_account", nil
+			}
+		}
+		cid := req.CategoryID
+		t := &repo.Transaction{
+			UserID: userID, CategoryID: &cid, Amount: req.Amount, Type: req.Type, Date: d,
+			Description: req.Description, TaxDeductible: req.TaxDeductible,
+			Reimbursable: req.Reimbursable, AccountID: req.AccountID, OverrideLimit: req.OverrideLimit,
+		}
+		var out *repo.Transaction
+		if w.ID == nil {
+			out, err = tx.TransactionRepo().Create(ctx, t)
+		} else {
+			out, err = tx.TransactionRepo().Update(ctx, userID, id, t)
+		}
+		if errors.Is(err, repo.ErrPeriodClosed) {
+			return id, "period_closed", nil
+		}
+		if errors.As(err, new(*repo.LimitError)) {
+			return id, "category_limit_exceeded", nil
+		}
+		if saved = out != nil; saved {
+			id = out.ID
+		}
+	}
+	if err != nil {
+		return id, "", err
+	}
+	if !saved {
+		return id, "not_found", nil
+	}
+	return id, "", nil
+}
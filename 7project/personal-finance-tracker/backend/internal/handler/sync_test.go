@@ -0,0 +1,47 @@
+// backend/internal/handler/sync_test.go
+//
+// Purpose:
+//   Verify that malformed sync tokens and sync write batches are refused with 400
+//   before anything is read or written.
+
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSync_Invalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api := handler.New(nil, "s") // validation fails before the repository is used
+	r := gin.New()
+	uid := func(c *gin.Context) { c.Set("uid", int64(1)) }
+	r.GET("/api/sync", uid, api.Sync)
+	r.POST("/api/sync", uid, api.SyncWrite)
+
+	cases := []struct{ method, target, body, code string }{
+		{http.MethodGet, "/api/sync?since=abc", "", "invalid_sync_token"},
+		{http.MethodGet, "/api/sync?since=9.3.1", "", "invalid_sync_token"},
+		{http.MethodPost, "/api/sync", `{"changes":[]}`, "invalid"},
+		{http.MethodPost, "/api/sync", `{"changes":[{"entity":"loans","op":"upsert","data":{}}]}`, "invalid"},
+		{http.MethodPost, "/api/sync", `{"changes":[{"entity":"budgets","op":"merge","data":{}}]}`, "invalid"},
+	}
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(rec, req)
+		var p map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &p)
+		if rec.Code != http.StatusBadRequest || p["code"] != tc.code {
+			t.Errorf("%s %s %s: %d %s, want 400 %s", tc.method, tc.target, tc.body, rec.Code, rec.Body, tc.code)
+		}
+	}
+}
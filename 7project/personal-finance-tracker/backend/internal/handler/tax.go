@@ -0,0 +1,59 @@
+// backend/internal/handler/tax.go
+
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TaxReport returns deductible expense totals by tax category for a year.
+// Optional query parameters:
+// - year: YYYY (defaults to the current year)
+// - format: "csv" streams the report as a CSV download instead of JSON
+// - display_currency: converts amounts (see displayConversion)
+func (api *API) TaxReport(c *gin.Context) {
+	userID := MustUserID(c)
+	year := time.Now().UTC().Year()
+	if ys := c.Query("year"); ys != "" {
+		y, err := strconv.Atoi(ys)
+		if err != nil || y < 1900 || y > 9999 {
+			problem(c, http.StatusBadRequest, "invalid_year")
+			return
+		}
+		year = y
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		problem(c, http.StatusBadRequest, "invalid_format")
+		return
+	}
+	conv, ok := api.displayConversion(c)
+	if !ok {
+		return
+	}
+	out, err := api.Repos.ReportRepo().In(conv).Tax(c.Request.Context(), userID, year)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if format == "json" {
+		c.JSON(http.StatusOK, out)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tax-%d.csv"`, year))
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"tax_category", "count", "total"})
+	for _, tc := range out.Categories {
+		_ = w.Write([]string{tc.TaxCategory, strconv.Itoa(tc.Count), strconv.FormatFloat(tc.Total, 'f', 2, 64)})
+	}
+	_ = w.Write([]string{"TOTAL", "", strconv.FormatFloat(out.Total, 'f', 2, 64)})
+	w.Flush()
+}
@@ -0,0 +1,99 @@
+// backend/internal/handler/telegram.go
+
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"pft/internal/telegram"
+
+	"github.com/gin-gonic/gin"
+)
+
+// telegramCodeTTL is how long a link code can be redeemed.
+const telegramCodeTTL = 15 * time.Minute
+
+// GetTelegram reports whether the user linked a Telegram chat:
+// {"enabled": true, "linked": true, "username": "...", "linked_at": "..."}.
+// enabled is false when the server has no bot configured.
+func (api *API) GetTelegram(c *gin.Context) {
+	l, err := api.Repos.TelegramRepo().Get(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	out := gin.H{"enabled": api.TelegramBot != "", "linked": l != nil}
+	if l != nil {
+		out["username"] = l.Username
+		out["linked_at"] = l.LinkedAt
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateTelegramLink hands out a one-time code and the t.me link that sends it to the
+// bot; opening the link in Telegram links that chat. Answers 201 with
+// {"code", "url", "expires_at"}, or 404 when no bot is configured.
+func (api *API) CreateTelegramLink(c *gin.Context) {
+	if api.TelegramBot == "" {
+		problemDetail(c, http.StatusNotFound, "telegram_disabled", "No Telegram bot is configured on this server.")
+		return
+	}
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		fail(c, err)
+		return
+	}
+	code := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)
+	expires := time.Now().Add(telegramCodeTTL).UTC()
+	if err := api.Repos.TelegramRepo().CreateLinkCode(c.Request.Context(), MustUserID(c), code, expires); err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"code":       code,
+		"url":        "https://t.me/" + api.TelegramBot + "?start=" + code,
+		"expires_at": expires,
+	})
+}
+
+// DeleteTelegram unlinks the user's chat.
+func (api *API) DeleteTelegram(c *gin.Context) {
+	ok, err := api.Repos.TelegramRepo().Unlink(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// TelegramWebhook receives bot updates pushed by Telegram, which sends secret in the
+// X-Telegram-Bot-Api-Secret-Token header. Updates are answered 200 even when handling
+// fails (the user was told in the chat), since Telegram would otherwise redeliver them.
+func TelegramWebhook(bot *telegram.Bot, secret string) gin.HandlerFunc {
+	want := []byte(secret)
+	return func(c *gin.Context) {
+		got := c.GetHeader("X-Telegram-Bot-Api-Secret-Token")
+		if subtle.ConstantTimeCompare([]byte(got), want) != 1 {
+			problemDetail(c, http.StatusUnauthorized, "unauthorized", "The webhook secret is missing or wrong.")
+			return
+		}
+		var u telegram.Update
+		if err := c.ShouldBindJSON(&u); err != nil {
+			invalidRequest(c, err)
+			return
+		}
+		if err := bot.HandleUpdate(c.Request.Context(), &u); err != nil {
+			slog.Error("telegram update failed", "request_id", c.GetString("request_id"), "update_id", u.UpdateID, "error", err.Error())
+		}
+		c.Status(http.StatusOK)
+	}
+}
@@ -0,0 +1,46 @@
+// backend/internal/handler/timeout.go
+
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// routeTimeouts gives routes a deadline other than RequestTimeout's, keyed by method
+// and route path like routeCache; 0 means none. Downloads stream for as long as the
+// client takes, and backups read or write every table.
+var routeTimeouts = map[string]time.Duration{
+	"GET /api/exports/:id/download":         0,
+	"GET /api/admin/backups/:name":          0,
+	"POST /api/admin/backups":               10 * time.Minute,
+	"POST /api/admin/backups/:name/restore": 10 * time.Minute,
+	"GET /api/admin/debug/*path":            0, // CPU profiles and traces run for ?seconds=
+}
+
+// RequestTimeout puts a deadline d after the start of each request on its context,
+// so the queries of a pathological request are cancelled and their connections freed
+// instead of held indefinitely; fail answers 504 "timeout" for them. Streamed
+// transaction lists (GET /api/transactions with Accept: application/x-ndjson; the
+// header alone exempts no other route) and routes in routeTimeouts are exempt or
+// bounded differently; DB_STATEMENT_TIMEOUT still bounds each of their
+// statements. d <= 0 disables it. It must run after routing (as any middleware added
+// with Use does).
+func RequestTimeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout, ok := routeTimeouts[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			timeout = d
+		}
+		if timeout <= 0 || streamsTransactions(c) {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
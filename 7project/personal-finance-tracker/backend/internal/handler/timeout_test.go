@@ -0,0 +1,67 @@
+// backend/internal/handler/timeout_test.go
+//
+// Purpose:
+//   Verify requests get the configured deadline, that long-running routes get their
+//   own or none, and that streamed transaction lists, and only they, are left
+//   unbounded.
+
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var left time.Duration
+	var bounded bool
+	record := func(c *gin.Context) {
+		var deadline time.Time
+		deadline, bounded = c.Request.Context().Deadline()
+		left = time.Until(deadline)
+	}
+	r := gin.New()
+	r.Use(handler.RequestTimeout(30 * time.Second))
+	r.GET("/api/reports/yoy", record)
+	r.GET("/api/transactions", record)
+	r.GET("/api/exports/:id/download", record)
+	r.POST("/api/admin/backups", record)
+
+	cases := []struct {
+		method, target, accept string
+		bounded                bool
+		max                    time.Duration
+	}{
+		{"GET", "/api/reports/yoy", "", true, 30 * time.Second},
+		{"GET", "/api/transactions", "application/json", true, 30 * time.Second},
+		{"GET", "/api/transactions", "application/x-ndjson", false, 0},
+		{"GET", "/api/reports/yoy", "application/x-ndjson", true, 30 * time.Second},
+		{"GET", "/api/exports/3/download", "", false, 0},
+		{"POST", "/api/admin/backups", "", true, 10 * time.Minute},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.target, nil)
+		if tc.accept != "" {
+			req.Header.Set("Accept", tc.accept)
+		}
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		if bounded != tc.bounded || (bounded && (left > tc.max || left < tc.max-time.Second)) {
+			t.Errorf("%s %s (%s): deadline %v in %v, want %v within %v", tc.method, tc.target, tc.accept, bounded, left, tc.bounded, tc.max)
+		}
+	}
+
+	r = gin.New()
+	r.Use(handler.RequestTimeout(0))
+	r.GET("/api/reports/yoy", record)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/reports/yoy", nil))
+	if bounded {
+		t.Error("RequestTimeout(0) should not set a deadline")
+	}
+}
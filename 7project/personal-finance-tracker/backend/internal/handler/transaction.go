@@ -3,6 +3,8 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -18,12 +20,20 @@ import (
 // - Type: must be "income" or "expense"
 // - Date: expected in YYYY-MM-DD format
 // - Description: optional free-text note
+// - TaxDeductible: optional override of the category's tax default (null = inherit)
+// - Reimbursable: expense is fronted on someone else's behalf and may be claimed back
+// - AccountID: optional account the money moved through
+// - OverrideLimit: save an expense even though it exceeds its category's "block" limit
 type txnCreateReq struct {
-	CategoryID  int64   `json:"category_id" binding:"required"`
-	Amount      float64 `json:"amount" binding:"required"`
-	Type        string  `json:"type" binding:"required,oneof=income expense"`
-	Date        string  `json:"date" binding:"required"` // YYYY-MM-DD
-	Description string  `json:"description"`
+	CategoryID    int64   `json:"category_id" binding:"required"`
+	Amount        float64 `json:"amount" binding:"required"`
+	Type          string  `json:"type" binding:"required,oneof=income expense"`
+	Date          string  `json:"date" binding:"required"` // YYYY-MM-DD
+	Description   string  `json:"description"`
+	TaxDeductible *bool   `json:"tax_deductible"`
+	Reimbursable  bool    `json:"reimbursable"`
+	AccountID     *int64  `json:"account_id"`
+	OverrideLimit bool    `json:"override_limit"`
 }
 
 // Alias to reuse the same validation and fields for updates.
@@ -34,7 +44,11 @@ type txnUpdateReq = txnCreateReq
 // - from/to: date range in YYYY-MM-DD
 // - type: "income" or "expense"
 // - category_id: integer category filter
+// - reimbursable: "true" or "false"
 // - limit/offset: pagination (offset is a row index, not a page number)
+//
+// With Accept: application/x-ndjson every matching transaction is streamed instead,
+// one JSON object per line (see streamTransactions).
 func (api *API) ListTransactions(c *gin.Context) {
 	userID := MustUserID(c)
 	var (
@@ -42,6 +56,7 @@ func (api *API) ListTransactions(c *gin.Context) {
 		toStr   = c.Query("to")
 		typ     = c.Query("type")
 		cidStr  = c.Query("category_id")
+		reimStr = c.Query("reimbursable")
 	)
 
 	// Parse optional date bounds; ignore invalid formats silently.
@@ -71,6 +86,25 @@ func (api *API) ListTransactions(c *gin.Context) {
 		}
 	}
 
+	// Parse optional reimbursable flag.
+	var reimPtr *bool
+	if v, err := strconv.ParseBool(reimStr); err == nil {
+		reimPtr = &v
+	}
+
+	f := repo.TxnListFilter{
+		From:         from,
+		To:           to,
+		CategoryID:   cidPtr,
+		Type:         typePtr,
+		Reimbursable: reimPtr,
+	}
+	if streamsTransactions(c) {
+		f.Limit, f.Offset = asInt(c.Query("limit"), 0), asInt(c.Query("offset"), 0)
+		api.streamTransactions(c, userID, f)
+		return
+	}
+
 	// --- limit / offset with sane defaults and clamps ---
 	limit := asInt(c.Query("limit"), 500)
 	if limit <= 0 {
@@ -85,97 +119,175 @@ func (api *API) ListTransactions(c *gin.Context) {
 	}
 
 	// Query repository with assembled filters and pagination.
-	list, err := api.Repos.TransactionRepo().List(c.Request.Context(), userID, repo.TxnListFilter{
-		From:       from,
-		To:         to,
-		CategoryID: cidPtr,
-		Type:       typePtr,
-		Limit:      limit,
-		Offset:     offset,
-	})
+	f.Limit, f.Offset = limit, offset
+	list, err := api.Repos.TransactionRepo().List(c.Request.Context(), userID, f)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, list)
 }
 
+const mimeNDJSON = "application/x-ndjson"
+
+// streamsTransactions reports whether c is a transaction list asked for as NDJSON,
+// the only response streamed that way.
+func streamsTransactions(c *gin.Context) bool {
+	return c.Request.Method == http.MethodGet && c.FullPath() == "/api/transactions" &&
+		c.NegotiateFormat(gin.MIMEJSON, mimeNDJSON) == mimeNDJSON
+}
+
+// ndjsonFlushEvery is how many lines are buffered before they are flushed to the client.
+const ndjsonFlushEvery = 100
+
+// streamTransactions writes the matching transactions as newline-delimited JSON while
+// they are read from the database, so integrations can consume a whole history in one
+// request. limit/offset are optional here and unbounded. A failure before the first
+// row is an ordinary problem response; once rows have been sent the status can no
+// longer change, so the stream ends with a line {"error": ..., "request_id": ...}
+// instead, which clients should treat as a truncated result.
+func (api *API) streamTransactions(c *gin.Context, userID int64, f repo.TxnListFilter) {
+	enc := json.NewEncoder(c.Writer)
+	n := 0
+	err := api.Repos.TransactionRepo().Each(c.Request.Context(), userID, f, func(t *repo.Transaction) error {
+		if n == 0 {
+			c.Header("Content-Type", mimeNDJSON)
+			c.Status(http.StatusOK)
+		}
+		if err := enc.Encode(t); err != nil {
+			return err
+		}
+		if n++; n%ndjsonFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	switch {
+	case err != nil && n == 0:
+		fail(c, err)
+		return
+	case err != nil:
+		_ = c.Error(err)
+		_ = enc.Encode(gin.H{"error": "stream_interrupted", "request_id": c.GetString("request_id")})
+	case n == 0:
+		// No rows: an empty body is a valid, empty stream.
+		c.Header("Content-Type", mimeNDJSON)
+		c.Status(http.StatusOK)
+	}
+	c.Writer.Flush()
+}
+
 // CreateTransaction inserts a new transaction row.
 // Validates payload, parses the date, and passes a pointer for CategoryID to support nullable DB columns.
+// Responds with 409 when the date falls in a closed month, or when an expense exceeds
+// its category's "block" limit without override_limit; the response carries
+// limit_warning when it is saved above its category's limit anyway.
 func (api *API) CreateTransaction(c *gin.Context) {
 	userID := MustUserID(c)
 	var req txnCreateReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"})
+		invalidRequest(c, err)
 		return
 	}
 	d, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_date"})
+		problem(c, http.StatusBadRequest, "invalid_date")
 		return
 	}
 	// Use a local variable so a pointer can be passed to the repo model.
 	cid := req.CategoryID
 	t := &repo.Transaction{
-		UserID:      userID,
-		CategoryID:  &cid,
-		Amount:      req.Amount,
-		Type:        req.Type,
-		Date:        d,
-		Description: req.Description,
+		UserID:        userID,
+		CategoryID:    &cid,
+		Amount:        req.Amount,
+		Type:          req.Type,
+		Date:          d,
+		Description:   req.Description,
+		TaxDeductible: req.TaxDeductible,
+		Reimbursable:  req.Reimbursable,
+		AccountID:     req.AccountID,
+		OverrideLimit: req.OverrideLimit,
+	}
+	if !api.checkAccount(c, userID, t.AccountID) {
+		return
 	}
 	out, err := api.Repos.TransactionRepo().Create(c.Request.Context(), t)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		if errors.Is(err, repo.ErrPeriodClosed) {
+			problem(c, http.StatusConflict, "period_closed")
+			return
+		}
+		fail(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, out)
 }
 
 // UpdateTransaction modifies a transaction identified by path parameter :id.
-// Applies the same validation and parsing rules as creation.
+// Applies the same validation and parsing rules as creation; category limits are only
+// checked again when the amount, category, date or type changes.
+// Responds with 409 when the transaction's current or new month is closed.
 func (api *API) UpdateTransaction(c *gin.Context) {
 	userID := MustUserID(c)
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
 
 	var req txnUpdateReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"})
+		invalidRequest(c, err)
 		return
 	}
 	d, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_date"})
+		problem(c, http.StatusBadRequest, "invalid_date")
 		return
 	}
 	cid := req.CategoryID
 	t := &repo.Transaction{
-		CategoryID:  &cid,
-		Amount:      req.Amount,
-		Type:        req.Type,
-		Date:        d,
-		Description: req.Description,
+		CategoryID:    &cid,
+		Amount:        req.Amount,
+		Type:          req.Type,
+		Date:          d,
+		Description:   req.Description,
+		TaxDeductible: req.TaxDeductible,
+		Reimbursable:  req.Reimbursable,
+		AccountID:     req.AccountID,
+		OverrideLimit: req.OverrideLimit,
+	}
+	if !api.checkAccount(c, userID, t.AccountID) {
+		return
 	}
 	out, err := api.Repos.TransactionRepo().Update(c.Request.Context(), userID, id, t)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		if errors.Is(err, repo.ErrPeriodClosed) {
+			problem(c, http.StatusConflict, "period_closed")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
 		return
 	}
 	c.JSON(http.StatusOK, out)
 }
 
 // DeleteTransaction removes a transaction by ID for the authenticated user.
-// Returns 204 on success, 404 if not found, or 500 on repository errors.
+// Returns 204 on success, 404 if not found, 409 if its month is closed, or 500 on repository errors.
 func (api *API) DeleteTransaction(c *gin.Context) {
 	userID := MustUserID(c)
 	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
 	ok, err := api.Repos.TransactionRepo().Delete(c.Request.Context(), userID, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "server"})
+		if errors.Is(err, repo.ErrPeriodClosed) {
+			problem(c, http.StatusConflict, "period_closed")
+			return
+		}
+		fail(c, err)
 		return
 	}
 	if !ok {
-		c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+		problem(c, http.StatusNotFound, "not_found")
 		return
 	}
 	c.Status(http.StatusNoContent)
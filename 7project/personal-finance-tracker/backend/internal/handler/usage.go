@@ -0,0 +1,82 @@
+// backend/internal/handler/usage.go
+
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/repo"
+	"pft/internal/usage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrackUsage counts each authenticated request, its status and body sizes for the
+// user's API usage statistics. A nil rec counts nothing. Register it before RateLimit
+// so rejected requests are counted too.
+func TrackUsage(rec *usage.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		uid, ok := c.Get("uid")
+		if !ok || rec == nil {
+			return
+		}
+		id, _ := uid.(int64)
+		rec.Record(id, c.Writer.Status(), c.Request.ContentLength, int64(c.Writer.Size()))
+	}
+}
+
+// usageRange reads ?days= (1-366, default 30) as the range of UTC days ending today.
+func usageRange(c *gin.Context) (from, to time.Time, ok bool) {
+	days := 30
+	if s := c.Query("days"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > 366 {
+			problemDetail(c, http.StatusBadRequest, "invalid_days", "days must be a number from 1 to 366.")
+			return from, to, false
+		}
+		days = n
+	}
+	to = time.Now().UTC()
+	return to.AddDate(0, 0, 1-days), to, true
+}
+
+// GetUsage returns the authenticated user's API usage per day over the last ?days=
+// (default 30) and its total. Figures lag by up to a minute.
+func (api *API) GetUsage(c *gin.Context) {
+	from, to, ok := usageRange(c)
+	if !ok {
+		return
+	}
+	days, err := api.Repos.UsageRepo().Days(c.Request.Context(), MustUserID(c), from, to)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	var total repo.Usage
+	for _, d := range days {
+		total.Requests += d.Requests
+		total.Errors += d.Errors
+		total.BytesIn += d.BytesIn
+		total.BytesOut += d.BytesOut
+	}
+	c.JSON(http.StatusOK, gin.H{"from": from.Format("2006-01-02"), "to": to.Format("2006-01-02"), "total": total, "days": days})
+}
+
+// Usage returns API usage summed over all users for the last ?days= (default 30) and
+// the ?limit= (default 50, at most 500) heaviest users by requests.
+func (a *Admin) Usage(c *gin.Context) {
+	from, to, ok := usageRange(c)
+	if !ok {
+		return
+	}
+	limit := min(max(asInt(c.Query("limit"), 50), 1), 500)
+	users, total, err := a.Repos.UsageRepo().Top(c.Request.Context(), from, to, limit)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"from": from.Format("2006-01-02"), "to": to.Format("2006-01-02"), "total": total, "users": users})
+}
@@ -0,0 +1,112 @@
+// backend/internal/handler/validation.go
+
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"pft/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Report validation failures with the JSON field names clients send, not Go struct field names.
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				return "" // falls back to the Go field name
+			}
+			return name
+		})
+	}
+}
+
+// invalidRequest answers a failed ShouldBindJSON with a 400 "invalid" problem that lists
+// each offending field and why it was rejected, or explains why the body could not be parsed.
+// Messages are in the client's language (see localeOf).
+func invalidRequest(c *gin.Context, err error) {
+	p := Problem{Status: http.StatusBadRequest, Code: "invalid"}
+	lang := localeOf(c)
+
+	var ve validator.ValidationErrors
+	var te *json.UnmarshalTypeError
+	var se *json.SyntaxError
+	switch {
+	case errors.As(err, &ve):
+		p.Detail = i18n.T(lang, "validation.fields")
+		for _, fe := range ve {
+			p.Errors = append(p.Errors, FieldError{Field: fieldPath(fe), Message: fieldMessage(lang, fe)})
+		}
+	case errors.As(err, &te):
+		p.Detail = i18n.T(lang, "validation.types")
+		p.Errors = []FieldError{{Field: te.Field, Message: i18n.T(lang, "validation.type", "kind", jsonKind(lang, te.Type.Kind()))}}
+	case errors.As(err, &se):
+		p.Detail = i18n.T(lang, "validation.syntax", "offset", strconv.FormatInt(se.Offset, 10))
+	case errors.Is(err, io.EOF):
+		p.Detail = i18n.T(lang, "validation.empty")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		p.Detail = i18n.T(lang, "validation.truncated")
+	}
+	writeProblem(c, p)
+}
+
+// fieldPath drops the struct name from the validator namespace: "txnCreateReq.items[0].amount" -> "items[0].amount".
+func fieldPath(fe validator.FieldError) string {
+	if _, rest, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return rest
+	}
+	return fe.Field()
+}
+
+// fieldMessage renders one validation failure as a short phrase in lang.
+func fieldMessage(lang string, fe validator.FieldError) string {
+	p := fe.Param()
+	var unit string
+	switch fe.Kind() {
+	case reflect.String:
+		unit = i18n.T(lang, "validation.unit.characters")
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = i18n.T(lang, "validation.unit.items")
+	}
+	switch tag := fe.Tag(); tag {
+	case "required", "email":
+		return i18n.T(lang, "validation."+tag)
+	case "min", "max", "len":
+		return i18n.T(lang, "validation."+tag, "param", p, "unit", unit)
+	case "gt", "gte", "lt", "lte":
+		return i18n.T(lang, "validation."+tag, "param", p)
+	case "oneof":
+		return i18n.T(lang, "validation.oneof", "param", strings.Join(strings.Fields(p), ", "))
+	default:
+		return i18n.T(lang, "validation.other", "tag", tag)
+	}
+}
+
+// jsonKind names the JSON type expected for a Go kind, in lang.
+func jsonKind(lang string, k reflect.Kind) string {
+	kind := "object"
+	switch k {
+	case reflect.String:
+		kind = "string"
+	case reflect.Bool:
+		kind = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		kind = "integer"
+	case reflect.Float32, reflect.Float64:
+		kind = "number"
+	case reflect.Slice, reflect.Array:
+		kind = "array"
+	}
+	return i18n.T(lang, "validation.kind."+kind)
+}
@@ -0,0 +1,226 @@
+// backend/internal/handler/validation_test.go
+//
+// Purpose:
+//   Verify that rejected request bodies report which fields are invalid, using
+//   JSON field names and readable messages, and that malformed JSON is explained.
+//   Messages follow the request's Accept-Language. Unknown query options and
+//   notification events, and malformed quiet hours, are refused.
+
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func postCategory(t *testing.T, body string) map[string]any {
+	t.Helper()
+	p, _ := postCategoryLang(t, body, "")
+	return p
+}
+
+func postCategoryLang(t *testing.T, body, lang string) (map[string]any, http.Header) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	api := handler.New(nil, "s") // binding fails before the repository is used
+	r := gin.New()
+	r.POST("/api/categories", func(c *gin.Context) { c.Set("uid", int64(1)) }, api.CreateCategory)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/categories", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if lang != "" {
+		req.Header.Set("Accept-Language", lang)
+	}
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rec.Code, rec.Body)
+	}
+	var p map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	return p, rec.Header()
+}
+
+func TestInvalidRequest_FieldErrors(t *testing.T) {
+	p := postCategory(t, `{"type":"transfer"}`)
+	got := map[string]string{}
+	for _, e := range p["errors"].([]any) {
+		fe := e.(map[string]any)
+		got[fe["field"].(string)] = fe["message"].(string)
+	}
+	if got["name"] != "is required" || got["type"] != "must be one of: income, expense" || len(got) != 2 {
+		t.Fatalf("unexpected field errors: %v", got)
+	}
+}
+
+func TestInvalidRequest_WrongType(t *testing.T) {
+	p := postCategory(t, `{"name":"Food","type":"expense","tax_deductible":"yes"}`)
+	errs, _ := p["errors"].([]any)
+	if len(errs) != 1 || errs[0].(map[string]any)["field"] != "tax_deductible" {
+		t.Fatalf("expected tax_deductible type error, got %v", p)
+	}
+}
+
+func TestInvalidRequest_MalformedJSON(t *testing.T) {
+	p := postCategory(t, `{"name":`)
+	if p["code"] != "invalid" || p["errors"] != nil || p["detail"] == "" {
+		t.Fatalf("expected a detail without field errors, got %v", p)
+	}
+}
+
+func TestInvalidRequest_Localized(t *testing.T) {
+	p, h := postCategoryLang(t, `{"type":"transfer"}`, "de-CH, en;q=0.5")
+	if h.Get("Content-Language") != "de" || h.Get("Vary") != "Accept-Language" {
+		t.Fatalf("unexpected headers: %v", h)
+	}
+	if p["detail"] != "Ein oder mehrere Felder sind ungültig." {
+		t.Fatalf("unexpected detail: %v", p["detail"])
+	}
+	for _, e := range p["errors"].([]any) {
+		if fe := e.(map[string]any); fe["field"] == "name" && fe["message"] != "ist erforderlich" {
+			t.Fatalf("unexpected message: %v", fe)
+		}
+	}
+
+	p, h = postCategoryLang(t, `{"type":"transfer"}`, "ja")
+	if h.Get("Content-Language") != "en" || p["detail"] != "One or more fields are invalid." {
+		t.Fatalf("unsupported language should fall back to English, got %v %v", h, p["detail"])
+	}
+}
+
+func TestListCategories_InvalidInclude(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api := handler.New(nil, "s") // refused before the repository is used
+	r := gin.New()
+	r.GET("/api/categories", func(c *gin.Context) { c.Set("uid", int64(1)) }, api.ListCategories)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/categories?include=usage,budgets", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "invalid_include") {
+		t.Fatalf("expected 400 invalid_include, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestNotifications_Invalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api := handler.New(nil, "s") // refused before the repository is used
+	r := gin.New()
+	uid := func(c *gin.Context) { c.Set("uid", int64(1)) }
+	r.GET("/api/notifications", uid, api.ListNotifications)
+	r.PATCH("/api/notifications", uid, api.MarkNotifications)
+	r.PATCH("/api/notifications/:id", uid, api.MarkNotification)
+
+	cases := []struct{ method, target, body, code string }{
+		{"GET", "/api/notifications?event=login", "", "invalid_event"},
+		{"PATCH", "/api/notifications", `{"read":true,"event":"login"}`, "invalid_event"},
+		{"PATCH", "/api/notifications", `{"ids":[1,2]}`, "invalid"},
+		{"PATCH", "/api/notifications/3", `{}`, "invalid"},
+	}
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(rec, req)
+		var p map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &p)
+		if rec.Code != http.StatusBadRequest || p["code"] != tc.code {
+			t.Errorf("%s %s %s: got %d %s, want 400 %s", tc.method, tc.target, tc.body, rec.Code, rec.Body, tc.code)
+		}
+	}
+}
+
+func TestUpdateQuietHours_Invalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api := handler.New(nil, "s") // refused before the repository is used
+	r := gin.New()
+	r.PUT("/api/me/notifications/quiet-hours", func(c *gin.Context) { c.Set("uid", int64(1)) }, api.UpdateQuietHours)
+
+	for body, code := range map[string]string{
+		`{"enabled":true,"start":"22:00","end":"22:00"}`:                        "invalid_quiet_hours",
+		`{"enabled":true,"start":"25:00","end":"07:00"}`:                        "invalid_quiet_hours",
+		`{"enabled":true,"start":"22:00"}`:                                      "invalid_quiet_hours",
+		`{"enabled":true,"start":"22:00","end":"07:00","timezone":"Mars/Base"}`: "invalid_timezone",
+		`{"enabled":true,"start":"22:00","end":"07:00","timezone":"Local"}`:     "invalid_timezone",
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/api/me/notifications/quiet-hours", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), code) {
+			t.Errorf("%s: got %d %s, want 400 %s", body, rec.Code, rec.Body, code)
+		}
+	}
+}
+
+func TestDisplayCurrency_Invalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api := handler.New(nil, "s") // refused before the repository is used
+	r := gin.New()
+	setUser := func(c *gin.Context) { c.Set("uid", int64(1)) }
+	r.GET("/api/reports/compare", setUser, api.ComparePeriods)
+	r.GET("/api/dashboard/summary", setUser, api.MonthSummary)
+	r.GET("/api/reports/tax", setUser, api.TaxReport)
+
+	for _, url := range []string{
+		"/api/reports/compare?a=2025-01&b=2025-02&display_currency=dollars",
+		"/api/dashboard/summary?month=2025-01&display_currency=US",
+		"/api/reports/tax?display_currency=U5D",
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "invalid_currency") {
+			t.Errorf("%s: got %d %s, want 400 invalid_currency", url, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestCreateCategory_InvalidTxnLimit(t *testing.T) {
+	for body, field := range map[string]string{
+		`{"name":"Fun","type":"expense","txn_limit":50}`:                          "txn_limit_mode",
+		`{"name":"Fun","type":"expense","txn_limit_mode":"warn"}`:                 "txn_limit_mode",
+		`{"name":"Fun","type":"expense","txn_limit":50,"txn_limit_mode":"stop"}`:  "txn_limit_mode",
+		`{"name":"Fun","type":"expense","txn_limit":-5,"txn_limit_mode":"block"}`: "txn_limit",
+	} {
+		p := postCategory(t, body)
+		errs, _ := p["errors"].([]any)
+		if len(errs) != 1 || errs[0].(map[string]any)["field"] != field {
+			t.Errorf("%s: expected one %s error, got %v", body, field, p)
+		}
+	}
+}
+
+func TestParseTransaction_Refused(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api := handler.New(nil, "s") // refused before the repository is used
+	r := gin.New()
+	r.POST("/api/transactions/parse", func(c *gin.Context) { c.Set("uid", int64(1)) }, api.ParseTransaction)
+
+	for _, tc := range []struct {
+		body   string
+		status int
+		code   string
+	}{
+		{`{}`, http.StatusBadRequest, "invalid"},
+		{`{"text":"lunch 12","today":"16.10"}`, http.StatusBadRequest, "invalid_date"},
+		{`{"text":"lunch yesterday"}`, http.StatusUnprocessableEntity, "no_amount"},
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/transactions/parse", strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(rec, req)
+		var p map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &p)
+		if rec.Code != tc.status || p["code"] != tc.code {
+			t.Errorf("%s: got %d %v, want %d %s", tc.body, rec.Code, p["code"], tc.status, tc.code)
+		}
+	}
+}
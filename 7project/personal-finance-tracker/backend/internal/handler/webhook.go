@@ -0,0 +1,199 @@
+// backend/internal/handler/webhook.go
+
+package handler
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"pft/internal/repo"
+	"pft/internal/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// webhookSecretPrefix marks webhook signing secrets.
+const webhookSecretPrefix = "whsec_"
+
+// webhookTopic matches outbox topics, e.g. "transactions.created".
+var webhookTopic = regexp.MustCompile(`^[a-z_]+\.(created|updated|deleted)$`)
+
+// webhookReq registers or replaces a webhook endpoint.
+// - URL: https URL change events are posted to, signed in the X-PFT-Signature header
+// - Topics: e.g. ["transactions.created", "budgets.updated"]; empty for every topic
+// - Enabled: on updates only, false pauses deliveries (default true)
+type webhookReq struct {
+	URL         string   `json:"url" binding:"required,max=2048"`
+	Description string   `json:"description" binding:"max=200"`
+	Topics      []string `json:"topics" binding:"max=50"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+// bind reads the request and checks the URL and topics.
+func (req *webhookReq) bind(c *gin.Context) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		invalidRequest(c, err)
+		return false
+	}
+	if !webhook.ValidURL(req.URL) {
+		problemDetail(c, http.StatusBadRequest, "invalid_webhook_url",
+			"url must be an https URL on a public host, without credentials.")
+		return false
+	}
+	for _, t := range req.Topics {
+		if !webhookTopic.MatchString(t) {
+			problemDetail(c, http.StatusBadRequest, "invalid_topic",
+				"topics must look like \"<entity>.<created|updated|deleted>\", e.g. \"transactions.created\".")
+			return false
+		}
+	}
+	if req.Topics == nil {
+		req.Topics = []string{}
+	}
+	slices.Sort(req.Topics)
+	req.Topics = slices.Compact(req.Topics)
+	return true
+}
+
+// ListWebhooks returns the user's webhook endpoints without their secrets.
+func (api *API) ListWebhooks(c *gin.Context) {
+	out, err := api.Repos.WebhookRepo().ListEndpoints(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateWebhook registers an endpoint and answers 201 with its signing secret under
+// "secret". This is the only time the secret is shown.
+func (api *API) CreateWebhook(c *gin.Context) {
+	var req webhookReq
+	if !req.bind(c) {
+		return
+	}
+	secret, err := randomToken(webhookSecretPrefix)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	w, err := api.Repos.WebhookRepo().CreateEndpoint(c.Request.Context(), &repo.WebhookEndpoint{
+		UserID: MustUserID(c), URL: req.URL, Description: req.Description, Secret: secret, Topics: req.Topics,
+	})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, struct {
+		*repo.WebhookEndpoint
+		Secret string `json:"secret"`
+	}{w, secret})
+}
+
+// UpdateWebhook replaces endpoint :id; its secret stays the same.
+func (api *API) UpdateWebhook(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req webhookReq
+	if !req.bind(c) {
+		return
+	}
+	enabled := req.Enabled == nil || *req.Enabled
+	out, err := api.Repos.WebhookRepo().UpdateEndpoint(c.Request.Context(), MustUserID(c), id, &repo.WebhookEndpoint{
+		URL: req.URL, Description: req.Description, Topics: req.Topics, Enabled: enabled,
+	})
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteWebhook removes endpoint :id with its delivery log.
+func (api *API) DeleteWebhook(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.WebhookRepo().DeleteEndpoint(c.Request.Context(), MustUserID(c), id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries returns the delivery log of endpoint :id, newest first: each
+// event sent or being sent, with every attempt and the endpoint's answer. Optional
+// status (pending, delivered, dead), limit (default 50, at most 200) and offset.
+// Deliveries are kept for 30 days.
+func (api *API) ListWebhookDeliveries(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	status := c.Query("status")
+	switch status {
+	case "", "pending", "delivered", "dead":
+	default:
+		problemDetail(c, http.StatusBadRequest, "invalid_status", "status must be one of: pending, delivered, dead.")
+		return
+	}
+	ctx := c.Request.Context()
+	w, err := api.Repos.WebhookRepo().GetEndpoint(ctx, userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if w == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	limit, offset := webhookPage(c)
+	out, err := api.Repos.WebhookRepo().ListDeliveries(ctx, userID, id, status, limit, offset)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ListWebhookDeadLetters returns deliveries to any of the user's endpoints that ran
+// out of attempts, newest first, with their attempts. Optional limit and offset as
+// for ListWebhookDeliveries.
+func (api *API) ListWebhookDeadLetters(c *gin.Context) {
+	limit, offset := webhookPage(c)
+	out, err := api.Repos.WebhookRepo().ListDead(c.Request.Context(), MustUserID(c), limit, offset)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// webhookPage reads limit (default 50, clamped to 1..200) and offset.
+func webhookPage(c *gin.Context) (int, int) {
+	return min(max(asInt(c.Query("limit"), 50), 1), 200), max(asInt(c.Query("offset"), 0), 0)
+}
+
+// ReplayWebhookDelivery sends delivery :delivery_id of endpoint :id again, e.g. a dead
+// letter once the integrator fixed their server. Answers 202; 404 when there is no
+// such delivery or it is still pending.
+func (api *API) ReplayWebhookDelivery(c *gin.Context) {
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	deliveryID, _ := strconv.ParseInt(c.Param("delivery_id"), 10, 64)
+	ok, err := api.Repos.WebhookRepo().Replay(c.Request.Context(), MustUserID(c), id, deliveryID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
@@ -0,0 +1,49 @@
+// backend/internal/handler/webhook_test.go
+//
+// Purpose:
+//   Verify that webhook endpoints on private hosts or with malformed topics, and
+//   delivery log queries with an unknown status, are refused with 400 before the
+//   repository is used.
+
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pft/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestWebhook_Invalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	api := handler.New(nil, "s") // validation fails before the repository is used
+	r := gin.New()
+	uid := func(c *gin.Context) { c.Set("uid", int64(1)) }
+	r.POST("/api/webhooks", uid, api.CreateWebhook)
+	r.PUT("/api/webhooks/:id", uid, api.UpdateWebhook)
+	r.GET("/api/webhooks/:id/deliveries", uid, api.ListWebhookDeliveries)
+
+	cases := []struct{ method, target, body, code string }{
+		{http.MethodPost, "/api/webhooks", `{}`, "invalid"},
+		{http.MethodPost, "/api/webhooks", `{"url":"http://example.com/hook"}`, "invalid_webhook_url"},
+		{http.MethodPost, "/api/webhooks", `{"url":"https://192.168.1.10/hook"}`, "invalid_webhook_url"},
+		{http.MethodPut, "/api/webhooks/1", `{"url":"https://example.com/hook","topics":["transactions.*"]}`, "invalid_topic"},
+		{http.MethodGet, "/api/webhooks/1/deliveries?status=failed", "", "invalid_status"},
+	}
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(rec, req)
+		var p map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &p)
+		if rec.Code != http.StatusBadRequest || p["code"] != tc.code {
+			t.Errorf("%s %s %s: %d %s, want 400 %s", tc.method, tc.target, tc.body, rec.Code, rec.Body, tc.code)
+		}
+	}
+}
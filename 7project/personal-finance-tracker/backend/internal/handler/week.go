@@ -0,0 +1,83 @@
+// backend/internal/handler/week.go
+
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WeekStartFor returns the first day (midnight UTC) of the week containing d,
+// where weeks begin on firstDay.
+func WeekStartFor(d time.Time, firstDay time.Weekday) time.Time {
+	d = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(d.Weekday()) - int(firstDay) + 7) % 7
+	return d.AddDate(0, 0, -offset)
+}
+
+// ParseISOWeek returns the Monday of an ISO 8601 week given as "YYYY-Www" (e.g. "2024-W21").
+func ParseISOWeek(s string) (time.Time, error) {
+	var year, week int
+	if _, err := fmt.Sscanf(s, "%4d-W%2d", &year, &week); err != nil {
+		return time.Time{}, err
+	}
+	// January 4th always falls in ISO week 1.
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.UTC)
+	monday := WeekStartFor(jan4, time.Monday).AddDate(0, 0, (week-1)*7)
+	if y, w := monday.ISOWeek(); week < 1 || y != year || w != week {
+		return time.Time{}, fmt.Errorf("invalid iso week %q", s)
+	}
+	return monday, nil
+}
+
+// WeekSummary returns an aggregate view for one week, mirroring MonthSummary.
+// The week is selected by one of:
+// - week: ISO week "YYYY-Www"
+// - date: any day (YYYY-MM-DD) within the week
+// Defaults to the current week. Week boundaries follow the user's week_start preference.
+// Optional "display_currency" converts amounts.
+func (api *API) WeekSummary(c *gin.Context) {
+	userID := MustUserID(c)
+	ctx := c.Request.Context()
+
+	day := time.Now().UTC()
+	if ws := c.Query("week"); ws != "" {
+		d, err := ParseISOWeek(ws)
+		if err != nil {
+			problem(c, http.StatusBadRequest, "invalid_week")
+			return
+		}
+		day = d
+	} else if ds := c.Query("date"); ds != "" {
+		d, err := time.Parse("2006-01-02", ds)
+		if err != nil {
+			problem(c, http.StatusBadRequest, "invalid_date")
+			return
+		}
+		day = d
+	}
+
+	conv, ok := api.displayConversion(c)
+	if !ok {
+		return
+	}
+	prefs, err := api.Repos.UserRepo().GetPreferences(ctx, userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	firstDay := time.Monday
+	if prefs != nil {
+		firstDay = prefs.WeekStart
+	}
+
+	out, err := api.Repos.DashboardRepo().In(conv).WeekSummary(ctx, userID, WeekStartFor(day, firstDay))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,45 @@
+// backend/internal/handler/week_test.go
+//
+// Purpose:
+//   Verify week boundary calculations for different first-day-of-week preferences
+//   and ISO week parsing.
+
+package handler_test
+
+import (
+	"testing"
+	"time"
+
+	"pft/internal/handler"
+)
+
+func TestWeekStartFor(t *testing.T) {
+	wed := time.Date(2024, 5, 22, 15, 30, 0, 0, time.UTC) // Wednesday
+	cases := []struct {
+		first time.Weekday
+		want  string
+	}{
+		{time.Monday, "2024-05-20"},
+		{time.Sunday, "2024-05-19"},
+		{time.Wednesday, "2024-05-22"},
+		{time.Thursday, "2024-05-16"},
+	}
+	for _, tc := range cases {
+		if got := handler.WeekStartFor(wed, tc.first).Format("2006-01-02"); got != tc.want {
+			t.Fatalf("first=%v: expected %s, got %s", tc.first, tc.want, got)
+		}
+	}
+}
+
+func TestParseISOWeek(t *testing.T) {
+	got, err := handler.ParseISOWeek("2021-W01")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if s := got.Format("2006-01-02"); s != "2021-01-04" {
+		t.Fatalf("expected 2021-01-04, got %s", s)
+	}
+	if _, err := handler.ParseISOWeek("2021-W54"); err == nil {
+		t.Fatalf("expected error for out-of-range week")
+	}
+}
@@ -0,0 +1,208 @@
+// backend/internal/handler/wishlist.go
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pft/internal/repo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// wishlistReq is the payload for creating or updating a wishlist item.
+// - Priority: optional, 1 (highest) .. 5 (lowest); defaults to 3
+type wishlistReq struct {
+	CategoryID     *int64  `json:"category_id"`
+	Name           string  `json:"name" binding:"required,min=1,max=100"`
+	EstimatedPrice float64 `json:"estimated_price" binding:"gte=0"`
+	Priority       int     `json:"priority" binding:"omitempty,min=1,max=5"`
+	Note           string  `json:"note" binding:"max=1000"`
+}
+
+// toItem converts the request into a repo.WishlistItem, applying defaults.
+func (req wishlistReq) toItem() *repo.WishlistItem {
+	p := req.Priority
+	if p == 0 {
+		p = 3
+	}
+	return &repo.WishlistItem{
+		CategoryID:     req.CategoryID,
+		Name:           req.Name,
+		EstimatedPrice: req.EstimatedPrice,
+		Priority:       p,
+		Note:           req.Note,
+	}
+}
+
+// purchaseReq records the actual purchase of a wishlist item.
+// All fields are optional: amount defaults to the estimated price, date to today,
+// and category to the item's category.
+type purchaseReq struct {
+	Amount        *float64 `json:"amount" binding:"omitempty,gte=0"`
+	Date          string   `json:"date"` // YYYY-MM-DD
+	CategoryID    *int64   `json:"category_id"`
+	OverrideLimit bool     `json:"override_limit"`
+}
+
+// ListWishlist returns the user's planned purchases.
+func (api *API) ListWishlist(c *gin.Context) {
+	userID := MustUserID(c)
+	out, err := api.Repos.WishlistRepo().List(c.Request.Context(), userID)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// CreateWishlistItem adds a planned purchase.
+func (api *API) CreateWishlistItem(c *gin.Context) {
+	userID := MustUserID(c)
+	var req wishlistReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	w := req.toItem()
+	w.UserID = userID
+	out, err := api.Repos.WishlistRepo().Create(c.Request.Context(), w)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+// UpdateWishlistItem modifies an item identified by :id.
+func (api *API) UpdateWishlistItem(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req wishlistReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	out, err := api.Repos.WishlistRepo().Update(c.Request.Context(), userID, id, req.toItem())
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// DeleteWishlistItem removes an item by ID; a recorded purchase transaction is kept.
+func (api *API) DeleteWishlistItem(c *gin.Context) {
+	userID := MustUserID(c)
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	ok, err := api.Repos.WishlistRepo().Delete(c.Request.Context(), userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if !ok {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// WishlistAffordability evaluates whether an item fits this month's budget and projection.
+// Optional query parameter "month" (YYYY-MM) defaults to the current month.
+func (api *API) WishlistAffordability(c *gin.Context) {
+	userID := MustUserID(c)
+	ctx := c.Request.Context()
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	now := time.Now().UTC()
+	month := c.DefaultQuery("month", now.Format("2006-01"))
+	if !validMonth(month) {
+		problem(c, http.StatusBadRequest, "invalid_month")
+		return
+	}
+	w, err := api.Repos.WishlistRepo().Get(ctx, userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if w == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	out, err := api.Repos.WishlistRepo().Affordability(ctx, userID, w, month, now)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// PurchaseWishlistItem converts an item into an expense transaction and marks it purchased.
+// Returns 404 if the item does not exist and 409 if it was already purchased or exceeds
+// its category's "block" limit without override_limit.
+func (api *API) PurchaseWishlistItem(c *gin.Context) {
+	userID := MustUserID(c)
+	ctx := c.Request.Context()
+	id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	var req purchaseReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+	w, err := api.Repos.WishlistRepo().Get(ctx, userID, id)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if w == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	if w.TransactionID != nil {
+		problem(c, http.StatusConflict, "already_purchased")
+		return
+	}
+
+	t := &repo.Transaction{
+		CategoryID:    w.CategoryID,
+		Amount:        w.EstimatedPrice,
+		Date:          time.Now().UTC(),
+		Description:   w.Name,
+		OverrideLimit: req.OverrideLimit,
+	}
+	if req.Amount != nil {
+		t.Amount = *req.Amount
+	}
+	if req.CategoryID != nil {
+		t.CategoryID = req.CategoryID
+	}
+	if req.Date != "" {
+		d, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			problem(c, http.StatusBadRequest, "invalid_date")
+			return
+		}
+		t.Date = d
+	}
+
+	out, err := api.Repos.WishlistRepo().Purchase(ctx, userID, id, t)
+	if err != nil {
+		if errors.Is(err, repo.ErrPeriodClosed) {
+			problem(c, http.StatusConflict, "period_closed")
+			return
+		}
+		fail(c, err)
+		return
+	}
+	if out == nil {
+		problem(c, http.StatusConflict, "already_purchased")
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
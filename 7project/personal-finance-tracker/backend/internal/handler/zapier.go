@@ -0,0 +1,86 @@
+// backend/internal/handler/zapier.go
+
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Polling triggers for Zapier, IFTTT and similar automation platforms, authenticated
+// by API key (see APIKeyMiddleware). Each answers a JSON array, newest first, whose
+// items carry a unique "id" the platform deduplicates on, as Zapier requires.
+
+// zapierMaxItems bounds a trigger's answer.
+const zapierMaxItems = 100
+
+// ZapierMe identifies the key's user, for the platform's connection test and label:
+// {"id", "name", "email"}.
+func (api *API) ZapierMe(c *gin.Context) {
+	u, err := api.Repos.UserRepo().GetByID(c.Request.Context(), MustUserID(c))
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	if u == nil {
+		problem(c, http.StatusNotFound, "not_found")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": u.ID, "name": u.Name, "email": u.Email})
+}
+
+// NewTransactionsTrigger returns the user's latest transactions, at most ?limit= (default
+// and maximum 100), newest first. ?since_id= is the cursor: only transactions created
+// after that one are returned, for clients that keep their own position.
+func (api *API) NewTransactionsTrigger(c *gin.Context) {
+	limit := min(max(asInt(c.Query("limit"), zapierMaxItems), 1), zapierMaxItems)
+	sinceID, _ := strconv.ParseInt(c.Query("since_id"), 10, 64)
+	out, err := api.Repos.TransactionRepo().Since(c.Request.Context(), MustUserID(c), sinceID, limit)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// budgetExceeded is an item of BudgetExceededTrigger; ID is "<budget_id>-<month>", so a
+// budget triggers once per month.
+type budgetExceeded struct {
+	ID         string  `json:"id"`
+	BudgetID   int64   `json:"budget_id"`
+	Month      string  `json:"month"`
+	CategoryID *int64  `json:"category_id"`
+	GroupID    *int64  `json:"group_id"`
+	Category   *string `json:"category"` // the category's or group's name; nil for the overall budget
+	Limit      float64 `json:"limit"`
+	Spent      float64 `json:"spent"`
+	Over       float64 `json:"over"`
+}
+
+// BudgetExceededTrigger returns the budgets of the current month (or ?month=YYYY-MM)
+// whose expenses exceed the limit.
+func (api *API) BudgetExceededTrigger(c *gin.Context) {
+	month := c.DefaultQuery("month", time.Now().UTC().Format("2006-01"))
+	if _, err := time.Parse("2006-01", month); err != nil {
+		problem(c, http.StatusBadRequest, "invalid_month")
+		return
+	}
+	usage, err := api.Repos.AlertRepo().OverBudget(c.Request.Context(), MustUserID(c), month)
+	if err != nil {
+		fail(c, err)
+		return
+	}
+	out := make([]budgetExceeded, 0, len(usage))
+	for i := len(usage) - 1; i >= 0; i-- {
+		u := usage[i]
+		out = append(out, budgetExceeded{
+			ID: strconv.FormatInt(u.BudgetID, 10) + "-" + month, BudgetID: u.BudgetID, Month: month,
+			CategoryID: u.CategoryID, GroupID: u.GroupID, Category: u.CategoryName, Limit: u.Limit, Spent: u.Spent, Over: math.Round((u.Spent-u.Limit)*100) / 100,
+		})
+	}
+	c.JSON(http.StatusOK, out)
+}
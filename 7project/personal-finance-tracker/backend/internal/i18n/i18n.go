@@ -0,0 +1,115 @@
+// backend/internal/i18n/i18n.go
+
+// Package i18n translates user-facing strings: problem details, validation messages,
+// report labels and email text. Catalogs are flat JSON files (locales/<lang>.json)
+// embedded in the binary; a key missing from a catalog falls back to English, and a
+// key missing from English is returned as is.
+//
+// Messages use named placeholders, "{name}", filled from name/value pairs:
+//
+//	i18n.T("de", "validation.min", "param", "3", "unit", " Zeichen")
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Default is the language used when a request states no supported preference.
+const Default = "en"
+
+//go:embed locales/*.json
+var files embed.FS
+
+var (
+	catalogs = map[string]map[string]string{}
+	langs    []string
+	matcher  language.Matcher
+)
+
+func init() {
+	entries, err := files.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	for _, e := range entries {
+		b, err := files.ReadFile("locales/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		m := map[string]string{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			panic("i18n: " + e.Name() + ": " + err.Error())
+		}
+		catalogs[strings.TrimSuffix(e.Name(), path.Ext(e.Name()))] = m
+	}
+	// The matcher prefers the first tag on ties, so the default goes first.
+	langs = []string{Default}
+	for l := range catalogs {
+		if l != Default {
+			langs = append(langs, l)
+		}
+	}
+	sort.Strings(langs[1:])
+	tags := make([]language.Tag, len(langs))
+	for i, l := range langs {
+		tags[i] = language.MustParse(l)
+	}
+	matcher = language.NewMatcher(tags)
+}
+
+// Supported lists the languages with a catalog, Default first.
+func Supported() []string {
+	return append([]string(nil), langs...)
+}
+
+// Supports reports whether lang has a catalog.
+func Supports(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Match picks the supported language that best satisfies an Accept-Language header
+// ("de-CH, de;q=0.9, en;q=0.5"), or Default when nothing matches or the header is
+// empty or malformed.
+func Match(acceptLanguage string) string {
+	prefs, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(prefs) == 0 {
+		return Default
+	}
+	_, i, conf := matcher.Match(prefs...)
+	if conf == language.No {
+		return Default
+	}
+	return langs[i]
+}
+
+// T returns the message for key in lang with its placeholders replaced by args,
+// given as name/value pairs. Unknown placeholders are left in place.
+func T(lang, key string, args ...string) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		if msg, ok = catalogs[Default][key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	pairs := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		pairs = append(pairs, "{"+args[i]+"}", args[i+1])
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
+
+// Has reports whether key exists in the default catalog.
+func Has(key string) bool {
+	_, ok := catalogs[Default][key]
+	return ok
+}
@@ -0,0 +1,85 @@
+// backend/internal/i18n/i18n_test.go
+// Purpose: catalog completeness, Accept-Language matching and placeholder substitution.
+
+package i18n
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	for _, lang := range Supported() {
+		for key := range catalogs[Default] {
+			if _, ok := catalogs[lang][key]; !ok {
+				t.Errorf("%s: missing %q", lang, key)
+			}
+		}
+		for key := range catalogs[lang] {
+			if _, ok := catalogs[Default][key]; !ok {
+				t.Errorf("%s: %q is not in %s", lang, key, Default)
+			}
+		}
+	}
+}
+
+func TestCatalogsKeepPlaceholders(t *testing.T) {
+	for key, en := range catalogs[Default] {
+		want := placeholders(en)
+		for _, lang := range Supported() {
+			if got := placeholders(catalogs[lang][key]); strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("%s %q: placeholders %v, want %v", lang, key, got, want)
+			}
+		}
+	}
+}
+
+func placeholders(s string) []string {
+	var out []string
+	for {
+		i := strings.IndexByte(s, '{')
+		if i < 0 {
+			break
+		}
+		j := strings.IndexByte(s[i:], '}')
+		if j < 0 {
+			break
+		}
+		out = append(out, s[i:i+j+1])
+		s = s[i+j+1:]
+	}
+	sort.Strings(out)
+	return out
+}
+
+func TestMatch(t *testing.T) {
+	cases := map[string]string{
+		"":                          "en",
+		"de":                        "de",
+		"de-CH, fr;q=0.9":           "de",
+		"fr-FR, es;q=0.8, en;q=0.5": "es",
+		"es-MX":                     "es",
+		"fr, ja":                    "en",
+		"en-GB,de;q=0.9":            "en",
+		"*":                         "en",
+		";;;garbage":                "en",
+	}
+	for header, want := range cases {
+		if got := Match(header); got != want {
+			t.Errorf("Match(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T("de", "validation.min", "param", "3", "unit", " Zeichen"); got != "muss mindestens 3 Zeichen sein" {
+		t.Errorf("de validation.min = %q", got)
+	}
+	if got := T("xx", "problem.not_found"); got != catalogs[Default]["problem.not_found"] {
+		t.Errorf("unknown language = %q, want English", got)
+	}
+	if got := T("de", "no.such.key"); got != "no.such.key" {
+		t.Errorf("unknown key = %q", got)
+	}
+}
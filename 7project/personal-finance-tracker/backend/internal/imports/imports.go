@@ -0,0 +1,193 @@
+// backend/internal/imports/imports.go
+
+// Package imports runs CSV transaction imports as background jobs, so large files do
+// not hold a request open. The handler stores the file and queues a JobKind job;
+// Importer.Handle creates the transactions row by row, saving progress after each
+// so clients can poll it, a cancel takes effect at the next row, and a retried job
+// resumes where the previous attempt stopped. With Importer.Tx each row commits
+// together with the progress recording it, so a crash cannot import a row twice.
+// Bank statement PDFs are converted to the same CSV format first (ParsePDF). Imports
+// made as drafts put their rows into the drafts inbox for review instead.
+package imports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"pft/internal/jobs"
+	"pft/internal/repo"
+)
+
+// JobKind is the job kind of imports; its payload is {"import_id": N}.
+const JobKind = "imports.run"
+
+// Store keeps imports and their progress; implemented by repo.ImportRepo.
+type Store interface {
+	Start(ctx context.Context, id int64, rowsTotal int) (*repo.Import, error)
+	Content(ctx context.Context, id int64) (content []byte, profile string, err error)
+	Progress(ctx context.Context, id int64, p repo.ImportProgress) (bool, error)
+	Finish(ctx context.Context, id int64, status string, msg *string) error
+}
+
+// Categories lists a user's categories; implemented by repo.CategoryRepo.
+type Categories interface {
+	List(ctx context.Context, userID int64) ([]repo.Category, error)
+}
+
+// Transactions records imported rows; implemented by repo.TransactionRepo.
+type Transactions interface {
+	Create(ctx context.Context, t *repo.Transaction) (*repo.Transaction, error)
+}
+
+// Drafts records the rows of imports made as drafts; implemented by repo.DraftRepo.
+type Drafts interface {
+	Create(ctx context.Context, d *repo.Draft) (*repo.Draft, error)
+}
+
+// Importer runs import jobs.
+// - Drafts: receives the rows of imports with AsDrafts set
+// - Tx: optional; runs fn with a Store, Transactions and Drafts sharing one database transaction (repo.Store.WithTx)
+// - OnFinish: optional; gets each import about to be recorded as done or failed (again if that fails and the job is retried)
+// - OnWrite: optional; called with the user once an attempt that created transactions ends (e.g. to drop cached responses)
+type Importer struct {
+	Store        Store
+	Categories   Categories
+	Transactions Transactions
+	Drafts       Drafts
+	Tx           func(ctx context.Context, fn func(s Store, t Transactions, d Drafts) error) error
+	OnFinish     func(ctx context.Context, imp *repo.Import) error
+	OnWrite      func(ctx context.Context, userID int64)
+}
+
+// Handle is the jobs.HandlerFunc for JobKind.
+func (im *Importer) Handle(ctx context.Context, j *repo.Job) error {
+	var p struct {
+		ImportID int64 `json:"import_id"`
+	}
+	if err := json.Unmarshal(j.Payload, &p); err != nil || p.ImportID == 0 {
+		return jobs.Permanent(fmt.Errorf("decode payload: %s", j.Payload))
+	}
+	content, profile, err := im.Store.Content(ctx, p.ImportID)
+	if err != nil || content == nil {
+		return err // nil content: finished or cancelled before it started
+	}
+	rows, perr := ParseProfile(content, profile)
+	imp, err := im.Store.Start(ctx, p.ImportID, len(rows))
+	if err != nil || imp == nil {
+		return err
+	}
+	if perr != nil {
+		msg := perr.Error()
+		return im.finish(ctx, imp, "failed", &msg)
+	}
+
+	cats, err := im.Categories.List(ctx, imp.UserID)
+	if err != nil {
+		return err
+	}
+	byName := map[string]int64{}
+	for _, c := range cats {
+		byName[c.Type+"/"+strings.ToLower(c.Name)] = c.ID
+	}
+
+	progress := repo.ImportProgress{Processed: imp.RowsProcessed, Imported: imp.RowsImported, Failed: imp.RowsFailed}
+	if im.OnWrite != nil && !imp.AsDrafts {
+		// However the attempt ends, the rows it committed are there to be read.
+		defer func(imported int) {
+			if progress.Imported > imported {
+				im.OnWrite(ctx, imp.UserID)
+			}
+		}(progress.Imported)
+	}
+	for _, r := range rows[min(imp.RowsProcessed, len(rows)):] {
+		var cancel bool
+		err := im.atomically(ctx, func(s Store, t Transactions, d Drafts) error {
+			if !imp.AsDrafts {
+				d = nil
+			}
+			rowErr, err := importRow(ctx, t, d, imp, byName, r)
+			if err != nil {
+				return err
+			}
+			progress.Processed++
+			progress.Error = nil
+			if rowErr != "" {
+				progress.Failed++
+				progress.Error = &repo.ImportError{Line: r.Line, Message: rowErr}
+			} else {
+				progress.Imported++
+			}
+			cancel, err = s.Progress(ctx, imp.ID, progress)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if cancel {
+			return im.Store.Finish(ctx, imp.ID, "cancelled", nil)
+		}
+	}
+	imp.RowsProcessed, imp.RowsImported, imp.RowsFailed = progress.Processed, progress.Imported, progress.Failed
+	return im.finish(ctx, imp, "done", nil)
+}
+
+// finish records imp as done or failed, after passing it to OnFinish.
+func (im *Importer) finish(ctx context.Context, imp *repo.Import, status string, msg *string) error {
+	if im.OnFinish != nil {
+		done := *imp
+		done.Status, done.Error = status, msg
+		if err := im.OnFinish(ctx, &done); err != nil {
+			return err
+		}
+	}
+	return im.Store.Finish(ctx, imp.ID, status, msg)
+}
+
+// atomically runs fn in Tx when set.
+func (im *Importer) atomically(ctx context.Context, fn func(s Store, t Transactions, d Drafts) error) error {
+	if im.Tx == nil {
+		return fn(im.Store, im.Transactions, im.Drafts)
+	}
+	return im.Tx(ctx, fn)
+}
+
+// importRow creates the transaction of r, or its draft when drafts is not nil.
+// Problems with the row itself are returned as a message; the error is for failures
+// worth retrying the job for.
+func importRow(ctx context.Context, txs Transactions, drafts Drafts, imp *repo.Import, categories map[string]int64, r Row) (string, error) {
+	if r.Err != "" {
+		return r.Err, nil
+	}
+	t := &repo.Transaction{UserID: imp.UserID, Amount: r.Amount, Type: r.Type, Date: r.Date, Description: r.Description}
+	if r.Category != "" {
+		id, ok := categories[r.Type+"/"+strings.ToLower(r.Category)]
+		if !ok {
+			return fmt.Sprintf("unknown %s category %q", r.Type, r.Category), nil
+		}
+		t.CategoryID = &id
+	}
+	if drafts != nil {
+		// Closed periods are checked when the draft is approved.
+		ext := strconv.FormatInt(imp.ID, 10) + ":" + strconv.Itoa(r.Line)
+		_, err := drafts.Create(ctx, &repo.Draft{
+			UserID: imp.UserID, Source: repo.DraftSourceImport, ExternalID: &ext, CategoryID: t.CategoryID,
+			Type: t.Type, Amount: t.Amount, Date: t.Date, Description: t.Description, Note: imp.Filename,
+		})
+		return "", err
+	}
+	if _, err := txs.Create(ctx, t); err != nil {
+		if errors.Is(err, repo.ErrPeriodClosed) {
+			return fmt.Sprintf("%s is in a closed period", r.Date.Format("2006-01")), nil
+		}
+		var limit *repo.LimitError
+		if errors.As(err, &limit) {
+			return fmt.Sprintf("exceeds the %.2f limit of %s", limit.Limit, limit.Category), nil
+		}
+		return "", err
+	}
+	return "", nil
+}
@@ -0,0 +1,270 @@
+// backend/internal/imports/imports_test.go
+//
+// Purpose:
+//   Verify that import files are parsed with per-row errors, in regional formats
+//   when a profile is named, that the job imports rows into the user's categories
+//   while saving progress after each and reporting rows refused by a closed
+//   period or a category limit, resumes a retried import where it stopped,
+//   stops when a cancel is requested, and reports imports as they finish and
+//   attempts that created transactions.
+
+package imports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"pft/internal/repo"
+)
+
+func TestParse(t *testing.T) {
+	rows, err := Parse([]byte("\ufeffDate, Amount ,Category,Type,Description\n" +
+		"2026-10-01,-12.50,Food,,lunch\n" +
+		"2026-10-02,3000,Salary,income,\n" +
+		"01.10.2026,5,Food,,\n" +
+		"2026-10-03,abc,Food,,\n" +
+		"2026-10-03,4,Food,refund,\n" +
+		"2026-10-04,9\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Row{
+		{Line: 2, Date: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), Amount: 12.5, Type: "expense", Category: "Food", Description: "lunch"},
+		{Line: 3, Date: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), Amount: 3000, Type: "income", Category: "Salary"},
+		{Line: 4, Category: "Food", Err: `date "01.10.2026" is not YYYY-MM-DD`},
+		{Line: 5, Category: "Food", Err: `invalid amount "abc"`},
+		{Line: 6, Category: "Food", Err: `type "refund" must be income or expense`},
+		{Line: 7, Date: time.Date(2026, 10, 4, 0, 0, 0, 0, time.UTC), Amount: 9, Type: "expense"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"", "date,amount\n2026-10-01,5\n", "date,amount,category\n\"2026-10-01,5,Food\n"} {
+		if _, err := Parse([]byte(bad)); err == nil {
+			t.Errorf("Parse(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParseProfile(t *testing.T) {
+	rows, err := ParseProfile([]byte("date;amount;category;type;description\n"+
+		"01.10.2026;-1.234,56;Miete;;Oktober\n"+
+		"02.10.26;2.400;Gehalt;income;\n"+
+		"2026-10-03;(12,50);Essen;;\n"+
+		"04.10.2026;7,5-;Essen;;\n"+
+		"05.10.2026;12.50;Essen;;\n"+
+		"10/06/2026;3,00;Essen;;\n"), "eu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"2026-10-01 1234.56 expense Miete",
+		"2026-10-02 2400.00 income Gehalt",
+		"2026-10-03 12.50 expense Essen",
+		"2026-10-04 7.50 expense Essen",
+		`invalid amount "12.50"`,
+		`date "10/06/2026" matches no eu date format`,
+	}
+	for i, r := range rows {
+		got := r.Err
+		if got == "" {
+			got = fmt.Sprintf("%s %.2f %s %s", r.Date.Format("2006-01-02"), r.Amount, r.Type, r.Category)
+		}
+		if i >= len(want) || got != want[i] {
+			t.Errorf("row %d = %q", i, got)
+		}
+	}
+
+	// Thousands separators are only taken as such in groups of three digits.
+	rows, err = ParseProfile([]byte("date,amount,category\n10/05/2026,\"1,234.50\",Food\n10/05/2026,\"12,50\",Food\n10/05/2026,(9),Food\n"), "us")
+	if err != nil || len(rows) != 3 || rows[0].Amount != 1234.5 || rows[1].Err == "" || rows[2].Amount != 9 || rows[2].Type != "expense" {
+		t.Errorf("us rows = %+v, %v", rows, err)
+	}
+
+	if _, err := ParseProfile([]byte("date,amount,category\n"), "mars"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
+
+type fakeStore struct {
+	imp      repo.Import
+	content  []byte
+	progress []repo.ImportProgress
+	cancelAt int // cancel requested once this many rows were processed
+	status   string
+}
+
+func (f *fakeStore) Start(_ context.Context, _ int64, total int) (*repo.Import, error) {
+	f.imp.RowsTotal = total
+	out := f.imp
+	return &out, nil
+}
+func (f *fakeStore) Content(context.Context, int64) ([]byte, string, error) {
+	return f.content, f.imp.Profile, nil
+}
+func (f *fakeStore) Progress(_ context.Context, _ int64, p repo.ImportProgress) (bool, error) {
+	f.progress = append(f.progress, p)
+	f.imp.RowsProcessed, f.imp.RowsImported, f.imp.RowsFailed = p.Processed, p.Imported, p.Failed
+	return f.cancelAt > 0 && p.Processed >= f.cancelAt, nil
+}
+func (f *fakeStore) Finish(_ context.Context, _ int64, status string, _ *string) error {
+	f.status = status
+	return nil
+}
+
+type fakeLedger struct {
+	created []repo.Transaction
+	failAt  int // Create fails with a database error for the n-th call (1-based)
+	calls   int
+}
+
+func (f *fakeLedger) List(context.Context, int64) ([]repo.Category, error) {
+	return []repo.Category{{ID: 1, Name: "Food", Type: "expense"}, {ID: 2, Name: "Salary", Type: "income"}}, nil
+}
+func (f *fakeLedger) Create(_ context.Context, t *repo.Transaction) (*repo.Transaction, error) {
+	f.calls++
+	if f.calls == f.failAt {
+		return nil, errors.New("connection reset")
+	}
+	if t.Date.Format("2006-01") == "2025-12" {
+		return nil, repo.ErrPeriodClosed
+	}
+	if t.Type == "expense" && t.Amount > 1200 {
+		return nil, &repo.LimitError{CategoryID: 1, Category: "Food", Limit: 1200}
+	}
+	f.created = append(f.created, *t)
+	return t, nil
+}
+
+func TestImporter_Handle(t *testing.T) {
+	csv := "date,amount,category,type\n" +
+		"2026-10-01,12.50,food,\n" +
+		"2026-10-02,3000,Salary,income\n" +
+		"2026-10-03,5,Salary,\n" +
+		"2025-12-31,7,Food,\n" +
+		"2026-10-04,bad,Food,\n" +
+		"2026-10-05,9,,\n" +
+		"2026-10-06,1500,Food,\n"
+	store := &fakeStore{imp: repo.Import{ID: 3, UserID: 7}, content: []byte(csv)}
+	ledger := &fakeLedger{failAt: 2}
+	var finished []repo.Import
+	onFinish := func(_ context.Context, imp *repo.Import) error {
+		finished = append(finished, *imp)
+		return nil
+	}
+	var written []int64
+	onWrite := func(_ context.Context, userID int64) { written = append(written, userID) }
+	im := &Importer{Store: store, Categories: ledger, Transactions: ledger, OnFinish: onFinish, OnWrite: onWrite}
+	payload, _ := json.Marshal(map[string]int64{"import_id": 3})
+	job := &repo.Job{Kind: JobKind, Payload: payload}
+
+	if err := im.Handle(context.Background(), job); err == nil || store.status != "" {
+		t.Fatalf("a database error should fail the attempt, got %v (status %q)", err, store.status)
+	}
+	if err := im.Handle(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+	if store.status != "done" || store.imp.RowsTotal != 7 {
+		t.Fatalf("status %q, total %d", store.status, store.imp.RowsTotal)
+	}
+	if got := store.imp; got.RowsProcessed != 7 || got.RowsImported != 3 || got.RowsFailed != 4 {
+		t.Fatalf("progress %+v", got)
+	}
+	var msgs []string
+	for _, p := range store.progress {
+		if p.Error != nil {
+			msgs = append(msgs, p.Error.Message)
+		}
+	}
+	if got := strings.Join(msgs, "; "); got != `unknown expense category "Salary"; 2025-12 is in a closed period; invalid amount "bad"; exceeds the 1200.00 limit of Food` {
+		t.Fatalf("row errors %q", got)
+	}
+	if len(ledger.created) != 3 || *ledger.created[0].CategoryID != 1 || *ledger.created[1].CategoryID != 2 ||
+		ledger.created[2].CategoryID != nil || ledger.created[0].UserID != 7 {
+		t.Fatalf("created %+v", ledger.created)
+	}
+	if len(finished) != 1 || finished[0].Status != "done" || finished[0].RowsImported != 3 || finished[0].RowsFailed != 4 {
+		t.Fatalf("OnFinish got %+v", finished)
+	}
+	// The failed attempt had imported a row before the database error, the retry the rest.
+	if len(written) != 2 || written[0] != 7 || written[1] != 7 {
+		t.Fatalf("OnWrite got %v", written)
+	}
+
+	store = &fakeStore{imp: repo.Import{ID: 3, UserID: 7}, content: []byte(csv), cancelAt: 2}
+	ledger = &fakeLedger{}
+	im = &Importer{Store: store, Categories: ledger, Transactions: ledger, OnFinish: onFinish}
+	if err := im.Handle(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+	if store.status != "cancelled" || len(ledger.created) != 2 {
+		t.Fatalf("cancel: status %q, created %d", store.status, len(ledger.created))
+	}
+	if len(finished) != 1 {
+		t.Errorf("a cancelled import should not be reported, got %+v", finished[1:])
+	}
+
+	store = &fakeStore{imp: repo.Import{ID: 3, UserID: 7}, content: []byte("date,amount\n")}
+	im = &Importer{Store: store, Categories: ledger, Transactions: ledger, OnFinish: onFinish}
+	if err := im.Handle(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+	if store.status != "failed" || len(finished) != 2 || finished[1].Status != "failed" || finished[1].Error == nil {
+		t.Fatalf("unparsable file: status %q, OnFinish got %+v", store.status, finished)
+	}
+
+	// The file is read in the regional format the import was made with.
+	store = &fakeStore{imp: repo.Import{ID: 3, UserID: 7, Profile: "eu"}, content: []byte("date;amount;category\n01.10.2026;1.012,50;Food\n")}
+	ledger = &fakeLedger{}
+	im = &Importer{Store: store, Categories: ledger, Transactions: ledger}
+	if err := im.Handle(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger.created) != 1 || ledger.created[0].Amount != 1012.5 || ledger.created[0].Date.Day() != 1 {
+		t.Fatalf("eu import created %+v", ledger.created)
+	}
+}
+
+type fakeDrafts struct{ created []repo.Draft }
+
+func (f *fakeDrafts) Create(_ context.Context, d *repo.Draft) (*repo.Draft, error) {
+	f.created = append(f.created, *d)
+	return d, nil
+}
+
+func TestImporter_HandleAsDrafts(t *testing.T) {
+	csv := "date,amount,category\n" +
+		"2026-10-01,12.50,food\n" +
+		"2025-12-31,7,Food\n" +
+		"2026-10-04,3,Rent\n"
+	store := &fakeStore{imp: repo.Import{ID: 4, UserID: 7, Filename: "bank.csv", AsDrafts: true}, content: []byte(csv)}
+	ledger := &fakeLedger{}
+	drafts := &fakeDrafts{}
+	im := &Importer{Store: store, Categories: ledger, Transactions: ledger, Drafts: drafts}
+	payload, _ := json.Marshal(map[string]int64{"import_id": 4})
+	if err := im.Handle(context.Background(), &repo.Job{Kind: JobKind, Payload: payload}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger.created) != 0 {
+		t.Fatalf("drafts import created transactions: %+v", ledger.created)
+	}
+	// The closed month only matters once a draft is approved; unknown categories still fail the row.
+	if got := store.imp; got.RowsImported != 2 || got.RowsFailed != 1 || len(drafts.created) != 2 {
+		t.Fatalf("progress %+v, drafts %+v", got, drafts.created)
+	}
+	d := drafts.created[0]
+	if d.Source != repo.DraftSourceImport || *d.ExternalID != "4:2" || *d.CategoryID != 1 || d.Note != "bank.csv" || d.UserID != 7 {
+		t.Fatalf("draft %+v", d)
+	}
+}
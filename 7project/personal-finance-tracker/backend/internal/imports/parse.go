@@ -0,0 +1,150 @@
+// backend/internal/imports/parse.go
+
+package imports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Row is one data row of an import file. Err is set when the row is malformed; such
+// rows are reported and skipped.
+type Row struct {
+	Line        int
+	Date        time.Time
+	Amount      float64
+	Type        string
+	Category    string
+	Description string
+	Err         string
+}
+
+// Parse reads a CSV file with a header row naming the columns date, amount and
+// category and optionally type and description (any order, as for pft import).
+// Dates are YYYY-MM-DD and amounts use a decimal point. A missing type means
+// expense; a negative amount is taken as an expense of its absolute value; an empty
+// category leaves the transaction uncategorized. Errors are returned for files that
+// cannot be read at all (no header, a required column missing, broken quoting);
+// problems with single rows are set on the row.
+func Parse(data []byte) ([]Row, error) {
+	return ParseProfile(data, "")
+}
+
+// ParseProfile is Parse for files exported in the regional format of the named
+// profile (see Profiles), e.g. "eu" for 31.12.2026 and 1.234,56: dates take its
+// layouts as well as YYYY-MM-DD, and amounts its decimal separator, thousands
+// separators grouping three digits, and a minus or parentheses for negatives
+// ("-12,50", "12,50-", "(12,50)"). An empty profile is Parse. Headers with more
+// semicolons than commas are read as semicolon-separated, as spreadsheets in
+// decimal-comma locales write them.
+func ParseProfile(data []byte, profile string) ([]Row, error) {
+	var p *Profile
+	if profile != "" {
+		named, ok := profileNamed(profile)
+		if !ok {
+			return nil, fmt.Errorf("unknown import profile %q", profile)
+		}
+		p = &named
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+	if header, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		cr.Comma = ';'
+	}
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, req := range []string{"date", "amount", "category"} {
+		if _, ok := col[req]; !ok {
+			return nil, fmt.Errorf("missing column %q", req)
+		}
+	}
+
+	var out []Row
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		out = append(out, parseRow(line, p, func(name string) string {
+			if i, ok := col[name]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}))
+	}
+}
+
+// csvAmount matches an amount as a profile writes it in CSV files, keyed by
+// DecimalComma: an optional sign or parentheses, digits with optional thousands
+// separators, optional decimals and an optional trailing minus.
+var csvAmount = map[bool]*regexp.Regexp{
+	false: regexp.MustCompile(`^\(?[-+]?(?:\d{1,3}(?:[, ']\d{3})+|\d+)(?:\.\d+)?\)?-?$`),
+	true:  regexp.MustCompile(`^\(?[-+]?(?:\d{1,3}(?:[. ']\d{3})+|\d+)(?:,\d+)?\)?-?$`),
+}
+
+// parseRow reads one data row in the format of p; the canonical one when p is nil.
+func parseRow(line int, p *Profile, get func(string) string) Row {
+	r := Row{Line: line, Category: get("category"), Description: get("description")}
+	date, err := time.Parse("2006-01-02", get("date"))
+	if err != nil && p != nil {
+		var ok bool
+		if date, ok = p.parseDate(get("date")); ok {
+			err = nil
+		}
+	}
+	if err != nil {
+		r.Err = fmt.Sprintf("date %q is not YYYY-MM-DD", get("date"))
+		if p != nil {
+			r.Err = fmt.Sprintf("date %q matches no %s date format", get("date"), p.Name)
+		}
+		return r
+	}
+	var amount float64
+	if p == nil {
+		amount, err = strconv.ParseFloat(get("amount"), 64)
+	} else if s := get("amount"); csvAmount[p.DecimalComma].MatchString(s) {
+		var sign int
+		amount, sign = p.parseAmount(s)
+		if sign < 0 {
+			amount = -amount
+		}
+	} else {
+		err = errors.New("invalid amount")
+	}
+	if err != nil || amount == 0 {
+		r.Err = fmt.Sprintf("invalid amount %q", get("amount"))
+		return r
+	}
+	typ := strings.ToLower(get("type"))
+	switch {
+	case typ == "" || amount < 0:
+		typ = "expense"
+	case typ != "income" && typ != "expense":
+		r.Err = fmt.Sprintf("type %q must be income or expense", typ)
+		return r
+	}
+	r.Date, r.Amount, r.Type = date, max(amount, -amount), typ
+	return r
+}
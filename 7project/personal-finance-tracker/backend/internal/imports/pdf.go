@@ -0,0 +1,404 @@
+// backend/internal/imports/pdf.go
+
+package imports
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxPDFStream bounds the decompressed size of one content stream.
+const maxPDFStream = 16 << 20
+
+// pdfLines extracts the text of a PDF as lines, page by page from top to bottom, with
+// the text runs of a line joined left to right. It reads the page content streams
+// directly (uncompressed or FlateDecode) rather than the document structure, which is
+// enough for the machine-generated statements banks send. Text drawn in fonts without
+// a byte-per-character encoding (CID fonts) and scanned pages yield no usable text.
+func pdfLines(data []byte) ([]string, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return nil, errors.New("not a PDF file")
+	}
+	if bytes.Contains(data, []byte("/Encrypt")) {
+		return nil, errors.New("encrypted PDFs are not supported")
+	}
+	var lines []string
+	for _, s := range pdfStreams(data) {
+		if bytes.Contains(s, []byte("BT")) {
+			lines = append(lines, textLines(pdfTextRuns(s))...)
+		}
+	}
+	return lines, nil
+}
+
+// streamStart finds the "stream" keyword that follows a stream's dictionary.
+var streamStart = regexp.MustCompile(`>>\s*stream\r?\n`)
+
+// pdfStreams returns the decoded content of every stream that is not an image, font,
+// cross-reference or object stream. Streams with filters other than FlateDecode are skipped.
+func pdfStreams(data []byte) [][]byte {
+	var out [][]byte
+	for _, loc := range streamStart.FindAllIndex(data, -1) {
+		dict := pdfDict(data, loc[0]+2)
+		body := data[loc[1]:]
+		end := bytes.Index(body, []byte("endstream"))
+		if end < 0 {
+			continue
+		}
+		body = bytes.TrimRight(body[:end], "\r\n")
+		if bytes.Contains(dict, []byte("/Subtype/Image")) || bytes.Contains(dict, []byte("/Subtype /Image")) ||
+			bytes.Contains(dict, []byte("/Length1")) || bytes.Contains(dict, []byte("/FontFile")) ||
+			bytes.Contains(dict, []byte("/XRef")) || bytes.Contains(dict, []byte("/ObjStm")) {
+			continue
+		}
+		switch {
+		case bytes.Contains(dict, []byte("/FlateDecode")):
+			zr, err := zlib.NewReader(bytes.NewReader(body))
+			if err != nil {
+				continue
+			}
+			dec, err := io.ReadAll(io.LimitReader(zr, maxPDFStream))
+			if err != nil && len(dec) == 0 {
+				continue
+			}
+			out = append(out, dec)
+		case !bytes.Contains(dict, []byte("/Filter")):
+			out = append(out, body)
+		}
+	}
+	return out
+}
+
+// pdfDict returns the dictionary ending just before end ("<<...>>"), matching nested
+// dictionaries, or nil.
+func pdfDict(data []byte, end int) []byte {
+	depth := 0
+	for i := end - 1; i > 0; i-- {
+		switch {
+		case data[i] == '>' && data[i-1] == '>':
+			depth++
+			i--
+		case data[i] == '<' && data[i-1] == '<':
+			if depth--; depth == 0 {
+				return data[i-1 : end]
+			}
+			i--
+		}
+	}
+	return nil
+}
+
+// textRun is a string drawn at (X, Y) in a font of Size.
+type textRun struct {
+	X, Y, Size float64
+	Text       string
+}
+
+// textState is the part of the PDF text state that positions glyphs. Scaling by the
+// current transformation matrix (cm) is ignored.
+type textState struct {
+	lineX, lineY   float64 // start of the current line (text line matrix)
+	x, y           float64 // where the next glyphs go
+	scaleX, scaleY float64 // from the text matrix
+	leading, size  float64
+	runs           []textRun
+}
+
+// newLine moves to the start of the next line, offset by (tx, ty) in text space.
+func (t *textState) newLine(tx, ty float64) {
+	t.lineX += tx * t.scaleX
+	t.lineY += ty * t.scaleY
+	t.x, t.y = t.lineX, t.lineY
+}
+
+// show records s at the current position and advances past it, estimating glyphs
+// at half the font size since the font's widths are not read.
+func (t *textState) show(s string) {
+	if s == "" {
+		return
+	}
+	t.runs = append(t.runs, textRun{X: t.x, Y: t.y, Size: t.size * t.scaleY, Text: s})
+	t.x += float64(len([]rune(s))) * t.size * t.scaleX * 0.5
+}
+
+// pdfTextRuns interprets the text operators of a content stream (BT, Tm, Td, TD, T*,
+// TL, Tf, Tj, TJ, ' and ").
+func pdfTextRuns(content []byte) []textRun {
+	t := &textState{scaleX: 1, scaleY: 1, size: 10}
+	lex := &pdfLexer{data: content}
+	var ops []pdfToken
+	num := func(i int) float64 {
+		if i < 0 || i >= len(ops) {
+			return 0
+		}
+		return ops[i].num
+	}
+	for {
+		tok, ok := lex.next()
+		if !ok {
+			return t.runs
+		}
+		if tok.kind != tokOp {
+			ops = append(ops, tok)
+			continue
+		}
+		n := len(ops)
+		switch tok.str {
+		case "BT":
+			t.lineX, t.lineY, t.x, t.y, t.scaleX, t.scaleY = 0, 0, 0, 0, 1, 1
+		case "Tf":
+			if s := num(n - 1); s != 0 {
+				t.size = math.Abs(s)
+			}
+		case "TL":
+			t.leading = num(n - 1)
+		case "Tm":
+			t.scaleX, t.scaleY = math.Hypot(num(n-6), num(n-5)), math.Hypot(num(n-4), num(n-3))
+			if t.scaleX == 0 || t.scaleY == 0 {
+				t.scaleX, t.scaleY = 1, 1
+			}
+			t.lineX, t.lineY = num(n-2), num(n-1)
+			t.x, t.y = t.lineX, t.lineY
+		case "Td":
+			t.newLine(num(n-2), num(n-1))
+		case "TD":
+			t.leading = -num(n - 1)
+			t.newLine(num(n-2), num(n-1))
+		case "T*":
+			t.newLine(0, -t.leading)
+		case "Tj", "'", `"`:
+			if tok.str != "Tj" {
+				t.newLine(0, -t.leading)
+			}
+			if n > 0 {
+				t.show(ops[n-1].str)
+			}
+		case "TJ":
+			// Large negative adjustments (in thousandths of the font size) are word gaps.
+			var sb strings.Builder
+			for _, el := range ops {
+				if el.kind == tokString {
+					sb.WriteString(el.str)
+				} else if el.kind == tokNumber && el.num < -200 {
+					sb.WriteByte(' ')
+				}
+			}
+			t.show(sb.String())
+		}
+		ops = ops[:0]
+	}
+}
+
+// textLines groups runs into lines by their baseline, top to bottom, and joins the runs
+// of a line left to right, with a space where they do not touch.
+func textLines(runs []textRun) []string {
+	sort.SliceStable(runs, func(i, j int) bool {
+		if math.Abs(runs[i].Y-runs[j].Y) > 1 {
+			return runs[i].Y > runs[j].Y
+		}
+		return runs[i].X < runs[j].X
+	})
+	var out []string
+	var sb strings.Builder
+	lineY, end := math.Inf(1), 0.0
+	for _, r := range runs {
+		tol := max(1, r.Size*0.3)
+		switch {
+		case math.Abs(r.Y-lineY) > tol:
+			if s := strings.TrimSpace(sb.String()); s != "" {
+				out = append(out, s)
+			}
+			sb.Reset()
+			lineY = r.Y
+		case r.X > end+r.Size*0.15:
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(r.Text)
+		end = r.X + float64(len([]rune(r.Text)))*r.Size*0.5
+	}
+	if s := strings.TrimSpace(sb.String()); s != "" {
+		out = append(out, s)
+	}
+	return out
+}
+
+type pdfTokenKind int
+
+const (
+	tokNumber pdfTokenKind = iota
+	tokString
+	tokName
+	tokOp
+	tokOther
+)
+
+type pdfToken struct {
+	kind pdfTokenKind
+	str  string
+	num  float64
+}
+
+// pdfLexer splits a content stream into operands and operators. Array brackets are
+// dropped, so a TJ operator sees its array's elements as operands.
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func (l *pdfLexer) next() (pdfToken, bool) {
+	d := l.data
+	for l.pos < len(d) {
+		c := d[l.pos]
+		switch {
+		case c == '%':
+			for l.pos < len(d) && d[l.pos] != '\n' && d[l.pos] != '\r' {
+				l.pos++
+			}
+		case isPDFSpace(c) || c == '[' || c == ']' || c == '{' || c == '}':
+			l.pos++
+		case c == '(':
+			return pdfToken{kind: tokString, str: l.literal()}, true
+		case c == '<' && l.pos+1 < len(d) && d[l.pos+1] == '<':
+			l.pos += 2
+			return pdfToken{kind: tokOther}, true
+		case c == '>' && l.pos+1 < len(d) && d[l.pos+1] == '>':
+			l.pos += 2
+			return pdfToken{kind: tokOther}, true
+		case c == '<':
+			return pdfToken{kind: tokString, str: l.hex()}, true
+		case c == '/':
+			start := l.pos
+			l.pos++
+			l.word()
+			return pdfToken{kind: tokName, str: string(d[start:l.pos])}, true
+		default:
+			start := l.pos
+			l.pos++
+			if c != '\'' && c != '"' {
+				l.word()
+			}
+			w := string(d[start:l.pos])
+			if v, err := strconv.ParseFloat(w, 64); err == nil {
+				return pdfToken{kind: tokNumber, num: v}, true
+			}
+			if w == "ID" {
+				l.skipInlineImage()
+				continue
+			}
+			return pdfToken{kind: tokOp, str: w}, true
+		}
+	}
+	return pdfToken{}, false
+}
+
+// word advances past regular characters.
+func (l *pdfLexer) word() {
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !strings.ContainsRune("()<>[]{}/%", rune(l.data[l.pos])) {
+		l.pos++
+	}
+}
+
+// skipInlineImage skips the binary data of an inline image up to its EI operator.
+func (l *pdfLexer) skipInlineImage() {
+	if i := bytes.Index(l.data[l.pos:], []byte("EI")); i >= 0 {
+		l.pos += i + 2
+	} else {
+		l.pos = len(l.data)
+	}
+}
+
+// literal reads a (string) with escapes and balanced parentheses; bytes are taken as
+// Latin-1, which covers the standard and WinAnsi encodings for ASCII text.
+func (l *pdfLexer) literal() string {
+	d := l.data
+	l.pos++ // (
+	var sb strings.Builder
+	depth := 1
+	for l.pos < len(d) {
+		c := d[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return sb.String()
+			}
+		case '\\':
+			if l.pos >= len(d) {
+				return sb.String()
+			}
+			e := d[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				if e == '\r' && l.pos < len(d) && d[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for k := 0; k < 2 && l.pos < len(d) && d[l.pos] >= '0' && d[l.pos] <= '7'; k++ {
+						v = v*8 + int(d[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		sb.WriteRune(rune(c))
+	}
+	return sb.String()
+}
+
+// hex reads a <hex string> as Latin-1 bytes.
+func (l *pdfLexer) hex() string {
+	end := bytes.IndexByte(l.data[l.pos:], '>')
+	if end < 0 {
+		l.pos = len(l.data)
+		return ""
+	}
+	digits := make([]byte, 0, end)
+	for _, c := range l.data[l.pos+1 : l.pos+end] {
+		if !isPDFSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	l.pos += end + 1
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	var sb strings.Builder
+	for i := 0; i < len(digits); i += 2 {
+		v, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			return ""
+		}
+		sb.WriteRune(rune(v))
+	}
+	return sb.String()
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
@@ -0,0 +1,207 @@
+// backend/internal/imports/statement.go
+
+package imports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Profile describes how one bank (or one family of statements) lays out transaction
+// lines: "<date> <description> <amount> [<balance>]". Register bank-specific profiles
+// by appending to Profiles; ParseStatement picks the best one unless told which.
+// CSV files exported in a regional format are read with a profile's date layouts and
+// decimal separator too (ParseProfile).
+// - Detect: optional; when set, the profile is only auto-selected for statements whose text matches
+// - Date: matches the date at the start of a transaction line; DateLayouts parse it (time.Parse layouts, tried in order)
+// - DecimalComma: amounts are written 1.234,56 rather than 1,234.56
+type Profile struct {
+	Name         string
+	Detect       *regexp.Regexp
+	Date         *regexp.Regexp
+	DateLayouts  []string
+	DecimalComma bool
+}
+
+// Profiles are the statement layouts known to ParseStatement. The generic ones cover
+// the common date and number conventions.
+var Profiles = []Profile{
+	{Name: "iso", Date: regexp.MustCompile(`^\d{4}-\d{2}-\d{2}`), DateLayouts: []string{"2006-01-02"}},
+	{Name: "us", Date: regexp.MustCompile(`^\d{1,2}/\d{1,2}/\d{2,4}`), DateLayouts: []string{"1/2/2006", "1/2/06"}},
+	{Name: "uk", Date: regexp.MustCompile(`^(\d{1,2}/\d{1,2}/\d{2,4}|\d{1,2} [A-Z][a-z]{2} \d{4})`),
+		DateLayouts: []string{"2/1/2006", "2/1/06", "2 Jan 2006"}},
+	{Name: "eu", Date: regexp.MustCompile(`^\d{1,2}\.\d{1,2}\.\d{2,4}`), DateLayouts: []string{"2.1.2006", "2.1.06"}, DecimalComma: true},
+}
+
+// profileNamed returns the profile called name.
+func profileNamed(name string) (Profile, bool) {
+	for _, p := range Profiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// statementAmount matches a trailing amount: an optional sign or parentheses, digits
+// with optional thousands separators, two decimals, and an optional CR/DR or - suffix.
+var statementAmount = map[bool]*regexp.Regexp{
+	false: regexp.MustCompile(`\s(\(?[-+]?\d{1,3}(?:[, ']?\d{3})*\.\d{2}\)?(?:\s?(?:CR|DR|Cr|Dr|-))?)$`),
+	true:  regexp.MustCompile(`\s(\(?[-+]?\d{1,3}(?:[. ']?\d{3})*,\d{2}\)?(?:\s?(?:CR|DR|Cr|Dr|H|S|-))?)$`),
+}
+
+// ParsePDF extracts the transactions of a bank statement PDF with the named profile,
+// or the best matching one when profile is "" (see ParseStatement).
+func ParsePDF(data []byte, profile string) ([]Row, error) {
+	lines, err := pdfLines(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("no text found in the PDF; scanned statements and some font encodings are not supported")
+	}
+	return ParseStatement(lines, profile)
+}
+
+// ParseStatement turns statement text lines into rows. Lines that do not start with a
+// date and end with an amount (headers, totals, page footers) are ignored. The sign of
+// an amount is read from a leading -, parentheses or a DR suffix (expense) and + or CR
+// (income); unsigned amounts followed by a running balance take their direction from
+// the change of the balance, and others are expenses. A named profile is used as is;
+// otherwise the profile recognizing the most lines wins, among those whose Detect
+// matches. Rows carry no category and the line number within the text.
+func ParseStatement(lines []string, profile string) ([]Row, error) {
+	candidates := Profiles
+	if profile != "" {
+		p, ok := profileNamed(profile)
+		if !ok {
+			return nil, fmt.Errorf("unknown statement profile %q", profile)
+		}
+		candidates = []Profile{p}
+	}
+	text := strings.Join(lines, "\n")
+	var best []Row
+	for _, p := range candidates {
+		if profile == "" && p.Detect != nil && !p.Detect.MatchString(text) {
+			continue
+		}
+		if rows := p.parse(lines); len(rows) > len(best) {
+			best = rows
+		}
+	}
+	if len(best) == 0 {
+		return nil, errors.New("no transactions recognized in the statement")
+	}
+	return best, nil
+}
+
+// parse applies the profile to every line.
+func (p Profile) parse(lines []string) []Row {
+	var out []Row
+	var balance *float64
+	for i, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		ds := p.Date.FindString(line)
+		if ds == "" {
+			continue
+		}
+		date, ok := p.parseDate(ds)
+		if !ok {
+			continue
+		}
+		rest := strings.TrimSpace(line[len(ds):])
+
+		// One or two trailing amounts: the transaction and possibly the running balance.
+		var amounts []string
+		for len(amounts) < 2 {
+			m := statementAmount[p.DecimalComma].FindStringSubmatchIndex(" " + rest)
+			if m == nil {
+				break
+			}
+			amounts = append([]string{(" " + rest)[m[2]:m[3]]}, amounts...)
+			rest = strings.TrimSpace((" " + rest)[:m[0]])
+		}
+		if len(amounts) == 0 || rest == "" {
+			continue
+		}
+		amount, sign := p.parseAmount(amounts[0])
+		var newBalance *float64
+		if len(amounts) == 2 {
+			b, bsign := p.parseAmount(amounts[1])
+			if bsign < 0 {
+				b = -b
+			}
+			newBalance = &b
+		}
+		typ := "expense"
+		switch {
+		case sign > 0:
+			typ = "income"
+		case sign == 0 && balance != nil && newBalance != nil && *newBalance > *balance:
+			typ = "income"
+		}
+		if newBalance != nil {
+			balance = newBalance
+		}
+		if amount == 0 {
+			continue
+		}
+		out = append(out, Row{Line: i + 1, Date: date, Amount: amount, Type: typ, Description: rest})
+	}
+	return out
+}
+
+func (p Profile) parseDate(s string) (time.Time, bool) {
+	for _, layout := range p.DateLayouts {
+		if d, err := time.Parse(layout, s); err == nil {
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseAmount returns the absolute value of an amount and its explicit direction:
+// -1 (debit), 1 (credit) or 0 (unsigned).
+func (p Profile) parseAmount(s string) (float64, int) {
+	sign := 0
+	switch {
+	case strings.HasPrefix(s, "(") || strings.HasPrefix(s, "-"), strings.HasSuffix(s, "-"),
+		strings.HasSuffix(strings.ToUpper(s), "DR"), p.DecimalComma && strings.HasSuffix(s, "S"):
+		sign = -1
+	case strings.HasPrefix(s, "+"), strings.HasSuffix(strings.ToUpper(s), "CR"), p.DecimalComma && strings.HasSuffix(s, "H"):
+		sign = 1
+	}
+	s = strings.TrimRight(s, "CRDrS H-)")
+	s = strings.TrimLeft(s, "(+-")
+	s = strings.NewReplacer(" ", "", "'", "").Replace(s)
+	if p.DecimalComma {
+		s = strings.ReplaceAll(strings.ReplaceAll(s, ".", ""), ",", ".")
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, 0
+	}
+	return math.Round(v*100) / 100, sign
+}
+
+// FormatCSV renders rows in the import CSV format read by Parse, so a converted
+// statement is imported (and can be inspected) like any uploaded CSV.
+func FormatCSV(rows []Row) []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"date", "amount", "type", "category", "description"})
+	for _, r := range rows {
+		_ = w.Write([]string{r.Date.Format("2006-01-02"), strconv.FormatFloat(r.Amount, 'f', 2, 64), r.Type, r.Category, r.Description})
+	}
+	w.Flush()
+	return buf.Bytes()
+}
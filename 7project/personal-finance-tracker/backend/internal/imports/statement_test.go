@@ -0,0 +1,140 @@
+// backend/internal/imports/statement_test.go
+//
+// Purpose:
+//   Verify that text is extracted from plain and compressed PDF content streams
+//   line by line, and that statement lines become rows with the right profile,
+//   amounts and directions while headers and totals are skipped.
+
+package imports
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildPDF assembles a minimal PDF with one page whose content stream is content.
+func buildPDF(content string, compress bool) []byte {
+	stream, filter := []byte(content), ""
+	if compress {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		zw.Write(stream)
+		zw.Close()
+		stream, filter = buf.Bytes(), " /Filter /FlateDecode"
+	}
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	b.WriteString("1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n")
+	b.WriteString("2 0 obj << /Type /Pages /Kids [3 0 R] /Count 1 >> endobj\n")
+	b.WriteString("3 0 obj << /Type /Page /Parent 2 0 R /Contents 4 0 R >> endobj\n")
+	fmt.Fprintf(&b, "4 0 obj << /Length %d%s >>\nstream\n", len(stream), filter)
+	b.Write(stream)
+	b.WriteString("\nendstream\nendobj\ntrailer << /Root 1 0 R >>\n%%EOF\n")
+	return b.Bytes()
+}
+
+const statementContent = `BT /F1 10 Tf 14 TL
+50 700 Td (Account statement) Tj
+0 -20 Td (2026-10-01) Tj 80 0 Td (Opening balance) Tj
+-80 -14 Td [(2026-10-02)] TJ 80 0 Td (Coffee \(to go\)) Tj 300 0 Td (-3.50) Tj 80 0 Td (996.50) Tj
+-460 -14 Td (2026-10-03) Tj 80 0 Td (Salary ACME) Tj 300 0 Td (2,500.00) Tj 80 0 Td (3,496.50) Tj
+ET`
+
+func TestPDFLines(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		lines, err := pdfLines(buildPDF(statementContent, compress))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{
+			"Account statement",
+			"2026-10-01 Opening balance",
+			"2026-10-02 Coffee (to go) -3.50 996.50",
+			"2026-10-03 Salary ACME 2,500.00 3,496.50",
+		}
+		if strings.Join(lines, "|") != strings.Join(want, "|") {
+			t.Errorf("compress=%v: lines = %q, want %q", compress, lines, want)
+		}
+	}
+
+	if _, err := pdfLines([]byte("date,amount\n")); err == nil {
+		t.Error("expected an error for a non-PDF file")
+	}
+}
+
+func TestParsePDF(t *testing.T) {
+	rows, err := ParsePDF(buildPDF(statementContent, true), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Row{
+		{Line: 3, Date: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), Amount: 3.5, Type: "expense", Description: "Coffee (to go)"},
+		{Line: 4, Date: time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC), Amount: 2500, Type: "income", Description: "Salary ACME"},
+	}
+	if fmt.Sprint(rows) != fmt.Sprint(want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+
+	// The converted CSV is read back by Parse like an uploaded file.
+	back, err := Parse(FormatCSV(rows))
+	if err != nil || len(back) != 2 || back[1].Type != "income" || back[0].Err != "" {
+		t.Errorf("Parse(FormatCSV) = %+v, %v", back, err)
+	}
+
+	if _, err := ParsePDF(buildPDF("BT (no transactions here) Tj ET", false), ""); err == nil {
+		t.Error("expected an error for a statement without transactions")
+	}
+}
+
+func TestParseStatement(t *testing.T) {
+	cases := []struct {
+		name    string
+		lines   []string
+		profile string
+		want    []string // "date amount type description"
+	}{
+		{
+			name: "eu with running balance",
+			lines: []string{
+				"Datum Text Betrag Saldo",
+				"01.10.2026 Miete 850,00 1.150,00",
+				"02.10.2026 Gehalt 2.400,00 3.550,00",
+				"Summe 3.250,00",
+			},
+			want: []string{"2026-10-01 850.00 expense Miete", "2026-10-02 2400.00 income Gehalt"},
+		},
+		{
+			name:  "us with explicit signs",
+			lines: []string{"10/05/2026 REFUND STORE 12.00 CR", "10/06/2026 GROCERY (45.10)", "10/07/2026 FEE 1.00 DR"},
+			want:  []string{"2026-10-05 12.00 income REFUND STORE", "2026-10-06 45.10 expense GROCERY", "2026-10-07 1.00 expense FEE"},
+		},
+		{
+			name:    "named profile reads the day first",
+			lines:   []string{"05/10/2026 Tesco 12.00"},
+			profile: "uk",
+			want:    []string{"2026-10-05 12.00 expense Tesco"},
+		},
+	}
+	for _, tc := range cases {
+		rows, err := ParseStatement(tc.lines, tc.profile)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		var got []string
+		for _, r := range rows {
+			got = append(got, fmt.Sprintf("%s %.2f %s %s", r.Date.Format("2006-01-02"), r.Amount, r.Type, r.Description))
+		}
+		if strings.Join(got, "|") != strings.Join(tc.want, "|") {
+			t.Errorf("%s: rows = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	if _, err := ParseStatement([]string{"2026-10-01 x 1.00"}, "nope"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+}
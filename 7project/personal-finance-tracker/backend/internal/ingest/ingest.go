@@ -0,0 +1,114 @@
+// backend/internal/ingest/ingest.go
+
+// Package ingest is email-in: each user gets a private address (<token>@domain) to
+// forward receipts and order confirmations to. The inbound-mail webhook hands every
+// message to Receiver, which reads the merchant, total and date from it (see
+// ParseReceipt) and stores a draft transaction for the user to approve.
+package ingest
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"pft/internal/repo"
+)
+
+// ErrUnknownRecipient reports a message not addressed to any user's ingest address.
+var ErrUnknownRecipient = errors.New("no ingest address among the recipients")
+
+// ErrNoReceipt reports a message in which no total amount was found.
+var ErrNoReceipt = errors.New("no receipt total found in the message")
+
+// Message is an inbound email as delivered by the mail provider.
+// - To: the recipients (a comma-separated address list); the envelope recipient when known
+// - MessageID: the Message-ID header, so a redelivered message is stored once
+// - Received: the Date header, or the time of delivery
+type Message struct {
+	To        string
+	From      string
+	Subject   string
+	Text      string
+	HTML      string
+	MessageID string
+	Received  time.Time
+}
+
+// Addresses maps ingest tokens to users; implemented by repo.IngestRepo.
+type Addresses interface {
+	UserByToken(ctx context.Context, token string) (int64, error)
+}
+
+// Drafts stores draft transactions; implemented by repo.DraftRepo.
+type Drafts interface {
+	Create(ctx context.Context, d *repo.Draft) (*repo.Draft, error)
+}
+
+// Receiver turns inbound messages into drafts.
+// - Domain: the mail domain of ingest addresses, e.g. "in.example.com"
+type Receiver struct {
+	Domain    string
+	Addresses Addresses
+	Drafts    Drafts
+}
+
+// Receive stores the receipt in m as a draft of the user it was sent to and returns
+// it; (nil, nil) means the message was already stored. Fails with ErrUnknownRecipient
+// or ErrNoReceipt when the message has no user or no total.
+func (r *Receiver) Receive(ctx context.Context, m *Message) (*repo.Draft, error) {
+	token := Token(m.To, r.Domain)
+	if token == "" {
+		return nil, ErrUnknownRecipient
+	}
+	userID, err := r.Addresses.UserByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if userID == 0 {
+		return nil, ErrUnknownRecipient
+	}
+	rc, ok := ParseReceipt(m)
+	if !ok {
+		return nil, ErrNoReceipt
+	}
+	d := &repo.Draft{
+		UserID: userID, Source: repo.DraftSourceEmail, Type: rc.Type, Amount: rc.Amount, Date: rc.Date,
+		Description: rc.Merchant, Note: rc.Note,
+	}
+	if id := strings.Trim(strings.TrimSpace(m.MessageID), "<>"); id != "" {
+		d.ExternalID = &id
+	}
+	// The draft is the user's own, scoped and audited as if made through the API.
+	ctx = repo.WithActor(repo.WithTenant(ctx, userID), "user:"+strconv.FormatInt(userID, 10), "email:"+token)
+	return r.Drafts.Create(ctx, d)
+}
+
+// Token returns the token of the first address in the list to that belongs to domain,
+// ignoring a "+tag" suffix, or "" when there is none.
+func Token(to, domain string) string {
+	if domain == "" {
+		return ""
+	}
+	var addrs []string
+	if list, err := mail.ParseAddressList(to); err == nil {
+		for _, a := range list {
+			addrs = append(addrs, a.Address)
+		}
+	} else {
+		addrs = strings.FieldsFunc(to, func(r rune) bool { return r == ',' || r == ';' || r == ' ' })
+	}
+	for _, a := range addrs {
+		local, host, ok := strings.Cut(strings.Trim(a, "<>\""), "@")
+		if !ok || !strings.EqualFold(host, domain) {
+			continue
+		}
+		local, _, _ = strings.Cut(local, "+")
+		if local != "" {
+			return strings.ToLower(local)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+// backend/internal/ingest/ingest_test.go
+//
+// Purpose:
+//   Verify that receipts are read from plain and HTML emails (total, date, merchant
+//   of the forwarded sender, refunds), that ingest tokens are found among the
+//   recipients, and that messages become drafts of the right user exactly once.
+
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pft/internal/repo"
+)
+
+func TestParseReceipt(t *testing.T) {
+	received := time.Date(2026, 10, 9, 15, 4, 0, 0, time.UTC)
+	cases := []struct {
+		name string
+		m    Message
+		want Receipt
+		ok   bool
+	}{
+		{
+			name: "forwarded text receipt",
+			m: Message{From: "Me <me@example.org>", Subject: "Fwd: Your Amazon.com order #123", Received: received, Text: "" +
+				"---------- Forwarded message ---------\n" +
+				"From: Amazon.com <auto-confirm@amazon.com>\n" +
+				"Order placed: October 5, 2026\n" +
+				"Subtotal: $40.00\n" +
+				"Shipping: $5.99\n" +
+				"Order Total: $45.99\n"},
+			want: Receipt{Merchant: "Amazon.com", Amount: 45.99, Currency: "USD", Type: "expense",
+				Date: time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC), Note: "auto-confirm@amazon.com · Your Amazon.com order #123 · USD"},
+			ok: true,
+		},
+		{
+			name: "html with the total in the next cell and decimal comma",
+			m: Message{From: "noreply@shop.zalando.de", Subject: "Deine Bestellung", Received: received,
+				HTML: `<html><head><style>td{}</style></head><body><table>` +
+					`<tr><td>Zwischensumme</td><td>80,00 €</td></tr>` +
+					`<tr><td>Total</td></tr><tr><td>1.089,90&nbsp;€</td></tr></table></body></html>`},
+			want: Receipt{Merchant: "Zalando", Amount: 1089.9, Currency: "EUR", Type: "expense",
+				Date: time.Date(2026, 10, 9, 0, 0, 0, 0, time.UTC), Note: "noreply@shop.zalando.de · Deine Bestellung · EUR"},
+			ok: true,
+		},
+		{
+			name: "refund without a total label",
+			m:    Message{From: "Store <help@store.example>", Subject: "Your refund", Received: received, Text: "We refunded £12.50 to your card."},
+			want: Receipt{Merchant: "Store", Amount: 12.5, Currency: "GBP", Type: "income",
+				Date: time.Date(2026, 10, 9, 0, 0, 0, 0, time.UTC), Note: "help@store.example · Your refund · GBP"},
+			ok: true,
+		},
+		{
+			name: "no amount",
+			m:    Message{From: "friend@example.org", Subject: "lunch?", Text: "See you at 12"},
+		},
+	}
+	for _, tc := range cases {
+		got, ok := ParseReceipt(&tc.m)
+		if ok != tc.ok || got != tc.want {
+			t.Errorf("%s: ParseReceipt = %+v, %v; want %+v, %v", tc.name, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestToken(t *testing.T) {
+	cases := []struct{ to, want string }{
+		{"Receipts <K3ABC+amazon@In.Example.com>, other@example.com", "k3abc"},
+		{"other@example.com; k3abc@in.example.com", "k3abc"},
+		{"k3abc@example.com", ""},
+	}
+	for _, tc := range cases {
+		if got := Token(tc.to, "in.example.com"); got != tc.want {
+			t.Errorf("Token(%q) = %q, want %q", tc.to, got, tc.want)
+		}
+	}
+}
+
+type fakeAddresses map[string]int64
+
+func (f fakeAddresses) UserByToken(_ context.Context, token string) (int64, error) {
+	return f[token], nil
+}
+
+type fakeDrafts struct{ stored []repo.Draft }
+
+func (f *fakeDrafts) Create(_ context.Context, d *repo.Draft) (*repo.Draft, error) {
+	for _, s := range f.stored {
+		if s.UserID == d.UserID && s.ExternalID != nil && d.ExternalID != nil && *s.ExternalID == *d.ExternalID {
+			return nil, nil
+		}
+	}
+	f.stored = append(f.stored, *d)
+	return d, nil
+}
+
+func TestReceiver_Receive(t *testing.T) {
+	drafts := &fakeDrafts{}
+	r := &Receiver{Domain: "in.example.com", Addresses: fakeAddresses{"k3abc": 7}, Drafts: drafts}
+	m := &Message{To: "k3abc@in.example.com", From: "Shop <shop@example.com>", Subject: "Receipt",
+		Text: "Total: 19.99 EUR", MessageID: "<abc@mail.example.com>", Received: time.Date(2026, 10, 9, 0, 0, 0, 0, time.UTC)}
+
+	d, err := r.Receive(context.Background(), m)
+	if err != nil || d == nil {
+		t.Fatalf("Receive = %v, %v", d, err)
+	}
+	if d.UserID != 7 || d.Source != repo.DraftSourceEmail || d.Amount != 19.99 || d.Description != "Shop" ||
+		d.ExternalID == nil || *d.ExternalID != "abc@mail.example.com" {
+		t.Errorf("draft = %+v", d)
+	}
+	if d, err := r.Receive(context.Background(), m); d != nil || err != nil {
+		t.Errorf("redelivery = %v, %v; want nothing stored", d, err)
+	}
+
+	m.To = "nobody@in.example.com"
+	if _, err := r.Receive(context.Background(), m); !errors.Is(err, ErrUnknownRecipient) {
+		t.Errorf("unknown recipient: err = %v", err)
+	}
+	m.To, m.Text = "k3abc@in.example.com", "thanks!"
+	if _, err := r.Receive(context.Background(), m); !errors.Is(err, ErrNoReceipt) {
+		t.Errorf("no total: err = %v", err)
+	}
+	if len(drafts.stored) != 1 {
+		t.Errorf("stored %d drafts, want 1", len(drafts.stored))
+	}
+}
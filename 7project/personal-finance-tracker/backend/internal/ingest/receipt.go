@@ -0,0 +1,263 @@
+// backend/internal/ingest/receipt.go
+
+package ingest
+
+import (
+	"html"
+	"math"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Receipt is what ParseReceipt read from a message.
+// - Merchant: the sender's name (of the forwarded message when there is one)
+// - Type: "income" for refunds, otherwise "expense"
+// - Date: the order date stated in the message, or the day it was received
+// - Note: the merchant's address and the subject, shown while reviewing the draft
+type Receipt struct {
+	Merchant string
+	Amount   float64
+	Currency string
+	Type     string
+	Date     time.Time
+	Note     string
+}
+
+// totalLabels name the line holding the amount charged, best first. A line matching
+// "subtotal" never counts.
+var totalLabels = []string{
+	"grand total", "order total", "total charged", "amount charged", "amount paid", "total paid",
+	"payment total", "total amount", "amount due", "refund total", "total",
+}
+
+var (
+	// moneyRe matches an amount with an optional currency before or after it:
+	// "$1,234.56", "EUR 12,90", "12.90 €".
+	moneyRe = regexp.MustCompile(`(?i)(?:([$€£¥₹]|usd|eur|gbp|chf|cad|aud|jpy|inr)\s?)?(\d{1,3}(?:[,.' ]\d{3})*[.,]\d{2}|\d+[.,]\d{2}|\d+)(?:\s?([$€£¥₹]|(?:usd|eur|gbp|chf|cad|aud|jpy|inr)\b))?`)
+	// forwardedFromRe matches the From line of a forwarded message's header block.
+	forwardedFromRe = regexp.MustCompile(`(?im)^\s*(?:>\s*)?(?:from|von|de)\s*:\s*(.+)$`)
+	subjectPrefixRe = regexp.MustCompile(`(?i)^\s*((fwd?|fw|wg|tr|re)\s*:\s*)+`)
+	refundRe        = regexp.MustCompile(`(?i)\b(refund|refunded|erstattung|rembourse)`)
+	tagRe           = regexp.MustCompile(`(?s)<(script|style|head)\b.*?</(script|style|head)>|<[^>]*>`)
+	blockTagRe      = regexp.MustCompile(`(?i)<(br|/p|/div|/tr|/li|/h[1-6]|/table)\b[^>]*>`)
+	cellTagRe       = regexp.MustCompile(`(?i)</t[dh]>`)
+)
+
+// currencyCodes maps currency signs to ISO codes.
+var currencyCodes = map[string]string{"$": "USD", "€": "EUR", "£": "GBP", "¥": "JPY", "₹": "INR"}
+
+// receiptDateLayouts are the date formats recognized in a message, tried in order.
+var receiptDateLayouts = []string{
+	"2006-01-02", "January 2, 2006", "January 2 2006", "Jan 2, 2006", "Jan 2 2006", "2 January 2006", "2 Jan 2006",
+	"02.01.2006", "2.1.2006",
+}
+
+var dateRe = regexp.MustCompile(`\d{4}-\d{2}-\d{2}|\d{1,2}\.\d{1,2}\.\d{4}|` +
+	`(?:[A-Z][a-z]+ \d{1,2},? \d{4})|(?:\d{1,2} [A-Z][a-z]+ \d{4})`)
+
+// ParseReceipt reads a receipt from a message: the amount on the line labelled as the
+// total (see totalLabels; the largest amount with a currency when no line is), the
+// date next to a "date" label, and the merchant from the forwarded sender. It reports
+// false when no amount was found.
+func ParseReceipt(m *Message) (Receipt, bool) {
+	body := m.Text
+	if strings.TrimSpace(body) == "" {
+		body = htmlText(m.HTML)
+	}
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+
+	r := Receipt{Type: "expense"}
+	r.Amount, r.Currency = findTotal(lines)
+	if r.Amount <= 0 {
+		return Receipt{}, false
+	}
+
+	subject := strings.TrimSpace(subjectPrefixRe.ReplaceAllString(m.Subject, ""))
+	if refundRe.MatchString(subject) {
+		r.Type = "income"
+	}
+
+	r.Date = m.Received.UTC()
+	if r.Date.IsZero() {
+		r.Date = time.Now().UTC()
+	}
+	if d, ok := findDate(lines); ok {
+		r.Date = d
+	}
+	r.Date = time.Date(r.Date.Year(), r.Date.Month(), r.Date.Day(), 0, 0, 0, 0, time.UTC)
+
+	from := m.From
+	if f := forwardedFromRe.FindStringSubmatch(body); f != nil {
+		from = strings.TrimSpace(f[1])
+	}
+	var addr string
+	r.Merchant, addr = merchant(from)
+	if r.Merchant == "" {
+		r.Merchant = subject
+	}
+	r.Note = strings.Join(nonEmpty(addr, subject, r.Currency), " · ")
+	return r, true
+}
+
+// findTotal returns the amount on the best-labelled total line, looking at the next
+// line when the label stands alone, and falls back to the largest amount carrying a
+// currency.
+func findTotal(lines []string) (float64, string) {
+	best, bestAmount, bestCur := len(totalLabels), 0.0, ""
+	for i, line := range lines {
+		lower := strings.ToLower(line)
+		if strings.Contains(lower, "subtotal") || strings.Contains(lower, "sub-total") {
+			continue
+		}
+		for rank, label := range totalLabels[:best] {
+			if !strings.Contains(lower, label) {
+				continue
+			}
+			after := line[strings.Index(lower, label)+len(label):]
+			v, cur, ok := lastAmount(after)
+			if !ok && i+1 < len(lines) {
+				v, cur, ok = lastAmount(lines[i+1])
+			}
+			if ok {
+				best, bestAmount, bestCur = rank, v, cur
+			}
+			break
+		}
+	}
+	if bestAmount > 0 {
+		return bestAmount, bestCur
+	}
+	for _, line := range lines {
+		for _, m := range moneyRe.FindAllStringSubmatch(line, -1) {
+			if m[1] == "" && m[3] == "" {
+				continue
+			}
+			if v, ok := parseMoney(m[2]); ok && v > bestAmount {
+				bestAmount, bestCur = v, currency(m[1]+m[3])
+			}
+		}
+	}
+	return bestAmount, bestCur
+}
+
+// lastAmount returns the last amount in s. Whole numbers without a currency
+// ("2 items") are ignored.
+func lastAmount(s string) (float64, string, bool) {
+	ms := moneyRe.FindAllStringSubmatch(s, -1)
+	for i := len(ms) - 1; i >= 0; i-- {
+		m := ms[i]
+		if m[1] == "" && m[3] == "" && !strings.ContainsAny(m[2], ".,") {
+			continue
+		}
+		if v, ok := parseMoney(m[2]); ok && v > 0 {
+			return v, currency(m[1] + m[3]), true
+		}
+	}
+	return 0, "", false
+}
+
+// parseMoney parses "1,234.56", "1.234,56", "12,90" or "12".
+func parseMoney(s string) (float64, bool) {
+	s = strings.NewReplacer(" ", "", "'", "").Replace(s)
+	if i := strings.LastIndexAny(s, ".,"); i >= 0 && len(s)-i-1 == 2 {
+		s = strings.NewReplacer(".", "", ",", "").Replace(s[:i]) + "." + s[i+1:]
+	} else {
+		s = strings.NewReplacer(".", "", ",", "").Replace(s)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return math.Round(v*100) / 100, true
+}
+
+func currency(s string) string {
+	if code, ok := currencyCodes[s]; ok {
+		return code
+	}
+	return strings.ToUpper(s)
+}
+
+// findDate returns the first date on a line mentioning a date ("Order date", "Datum"),
+// or else the first date in the message.
+func findDate(lines []string) (time.Time, bool) {
+	var first time.Time
+	for _, line := range lines {
+		for _, s := range dateRe.FindAllString(line, -1) {
+			d, ok := parseDate(s)
+			if !ok {
+				continue
+			}
+			lower := strings.ToLower(line)
+			if strings.Contains(lower, "date") || strings.Contains(lower, "datum") || strings.Contains(lower, "placed") {
+				return d, true
+			}
+			if first.IsZero() {
+				first = d
+			}
+		}
+	}
+	return first, !first.IsZero()
+}
+
+func parseDate(s string) (time.Time, bool) {
+	s = strings.Join(strings.Fields(s), " ")
+	for _, layout := range receiptDateLayouts {
+		if d, err := time.Parse(layout, s); err == nil {
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// merchant returns the display name of a From value ("Amazon.com <ship@amazon.com>")
+// and its address; a bare address yields its domain's main label ("Amazon").
+func merchant(from string) (name, addr string) {
+	a, err := mail.ParseAddress(from)
+	if err != nil {
+		return "", ""
+	}
+	if a.Name != "" {
+		return a.Name, a.Address
+	}
+	_, host, _ := strings.Cut(a.Address, "@")
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return "", a.Address
+	}
+	label := labels[len(labels)-2]
+	if label == "" {
+		return "", a.Address
+	}
+	return strings.ToUpper(label[:1]) + label[1:], a.Address
+}
+
+// htmlText reduces an HTML body to text lines: block ends and table cells become line
+// breaks and spaces, scripts and styles are dropped.
+func htmlText(s string) string {
+	s = blockTagRe.ReplaceAllString(s, "\n")
+	s = cellTagRe.ReplaceAllString(s, " ")
+	s = tagRe.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	lines := strings.Split(s, "\n")
+	out := lines[:0]
+	for _, l := range lines {
+		if l = strings.Join(strings.Fields(l), " "); l != "" {
+			out = append(out, l)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+func nonEmpty(ss ...string) []string {
+	out := ss[:0]
+	for _, s := range ss {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,169 @@
+// backend/internal/jobs/jobs.go
+
+// Package jobs runs background work from the Postgres-backed jobs table.
+// Handlers are registered per job kind; failed jobs are retried with exponential
+// backoff until they run out of attempts.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"pft/internal/errtrack"
+	"pft/internal/repo"
+)
+
+// Store is the queue backing the worker; implemented by repo.JobRepo.
+type Store interface {
+	Enqueue(ctx context.Context, j *repo.Job) (*repo.Job, error)
+	Claim(ctx context.Context, kinds []string, lease time.Duration) (*repo.Job, error)
+	Complete(ctx context.Context, id int64) error
+	Fail(ctx context.Context, id int64, msg string, retryAt *time.Time) error
+}
+
+// HandlerFunc performs one job. Returning an error schedules a retry unless the
+// error is wrapped with Permanent or the job has no attempts left.
+type HandlerFunc func(ctx context.Context, j *repo.Job) error
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying (e.g. malformed payload).
+func Permanent(err error) error { return permanentError{err} }
+
+// Worker polls the queue and dispatches claimed jobs to registered handlers.
+// - Concurrency: parallel jobs (default 2)
+// - PollInterval: wait after finding the queue empty (default 1s)
+// - Lease: time a job may run before another worker may reclaim it (default 10m)
+// - Errors: optional; jobs that fail for good are reported there
+type Worker struct {
+	Store        Store
+	Concurrency  int
+	PollInterval time.Duration
+	Lease        time.Duration
+	Errors       *errtrack.Client
+
+	handlers map[string]HandlerFunc
+	kinds    []string
+}
+
+// Register binds a handler to a job kind. Call before Run.
+func (w *Worker) Register(kind string, h HandlerFunc) {
+	if w.handlers == nil {
+		w.handlers = map[string]HandlerFunc{}
+	}
+	if _, dup := w.handlers[kind]; !dup {
+		w.kinds = append(w.kinds, kind)
+	}
+	w.handlers[kind] = h
+}
+
+// Run processes jobs until ctx is cancelled, then waits for running jobs to finish.
+func (w *Worker) Run(ctx context.Context) {
+	n := w.Concurrency
+	if n <= 0 {
+		n = 2
+	}
+	poll := w.PollInterval
+	if poll <= 0 {
+		poll = time.Second
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				worked, err := w.RunOnce(ctx)
+				if err != nil && ctx.Err() == nil {
+					slog.Error("job queue unavailable", "error", err.Error())
+				}
+				if worked && err == nil {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+				case <-time.After(poll):
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// RunOnce claims and runs a single ready job. Reports whether a job was run;
+// the error covers queue access only, job failures are recorded on the job.
+func (w *Worker) RunOnce(ctx context.Context) (bool, error) {
+	if len(w.kinds) == 0 {
+		return false, nil
+	}
+	lease := w.Lease
+	if lease <= 0 {
+		lease = 10 * time.Minute
+	}
+	j, err := w.Store.Claim(ctx, w.kinds, lease)
+	if err != nil || j == nil {
+		return false, err
+	}
+
+	log := slog.With("job_id", j.ID, "kind", j.Kind, "attempt", j.Attempts)
+	jctx, cancel := context.WithTimeout(ctx, lease)
+	err = w.call(jctx, j)
+	cancel()
+
+	// Settle the job even if ctx was cancelled meanwhile.
+	sctx := context.WithoutCancel(ctx)
+	if err == nil {
+		log.Info("job done")
+		return true, w.Store.Complete(sctx, j.ID)
+	}
+	var retryAt *time.Time
+	var perm permanentError
+	if !errors.As(err, &perm) && j.Attempts < j.MaxAttempts {
+		t := time.Now().Add(Backoff(j.Attempts))
+		retryAt = &t
+	}
+	log.Warn("job failed", "error", err.Error(), "retry", retryAt != nil)
+	if retryAt == nil {
+		w.Errors.Capture(errtrack.Event{Err: err, Tags: map[string]string{
+			"job_kind": j.Kind, "job_id": strconv.FormatInt(j.ID, 10)}})
+	}
+	return true, w.Store.Fail(sctx, j.ID, err.Error(), retryAt)
+}
+
+// call runs the handler, turning panics into errors so one bad job cannot kill the worker.
+func (w *Worker) call(ctx context.Context, j *repo.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	h, ok := w.handlers[j.Kind]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler for kind %q", j.Kind))
+	}
+	if j.UserID != nil {
+		// Jobs run on behalf of a user see only that user's rows (row-level security).
+		ctx = repo.WithTenant(ctx, *j.UserID)
+	}
+	// The audit log attributes the job's writes to its kind.
+	ctx = repo.WithActor(ctx, "job:"+j.Kind, fmt.Sprintf("job-%d", j.ID))
+	return h(ctx, j)
+}
+
+// Backoff returns the delay before retrying after the given attempt:
+// 10s, 20s, 40s, ... capped at one hour.
+func Backoff(attempt int) time.Duration {
+	d := 10 * time.Second
+	for i := 1; i < attempt && d < time.Hour; i++ {
+		d *= 2
+	}
+	return min(d, time.Hour)
+}
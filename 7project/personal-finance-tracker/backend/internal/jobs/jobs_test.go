@@ -0,0 +1,100 @@
+// backend/internal/jobs/jobs_test.go
+//
+// Purpose:
+//   Verify the worker completes successful jobs, schedules retries with backoff,
+//   gives up on permanent errors or exhausted attempts, and survives panics.
+
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pft/internal/repo"
+)
+
+// fakeStore hands out queued jobs in order and records how each was settled.
+type fakeStore struct {
+	queue    []*repo.Job
+	done     []int64
+	failed   map[int64]string
+	retryAts map[int64]*time.Time
+}
+
+func (s *fakeStore) Enqueue(_ context.Context, j *repo.Job) (*repo.Job, error) {
+	s.queue = append(s.queue, j)
+	return j, nil
+}
+
+func (s *fakeStore) Claim(context.Context, []string, time.Duration) (*repo.Job, error) {
+	if len(s.queue) == 0 {
+		return nil, nil
+	}
+	j := s.queue[0]
+	s.queue = s.queue[1:]
+	j.Attempts++
+	return j, nil
+}
+
+func (s *fakeStore) Complete(_ context.Context, id int64) error {
+	s.done = append(s.done, id)
+	return nil
+}
+
+func (s *fakeStore) Fail(_ context.Context, id int64, msg string, retryAt *time.Time) error {
+	s.failed[id] = msg
+	s.retryAts[id] = retryAt
+	return nil
+}
+
+func TestWorker_RunOnce(t *testing.T) {
+	s := &fakeStore{failed: map[int64]string{}, retryAts: map[int64]*time.Time{}}
+	w := &Worker{Store: s}
+	w.Register("ok", func(context.Context, *repo.Job) error { return nil })
+	w.Register("flaky", func(context.Context, *repo.Job) error { return errors.New("timeout") })
+	w.Register("bad", func(context.Context, *repo.Job) error { return Permanent(errors.New("bad payload")) })
+	w.Register("boom", func(context.Context, *repo.Job) error { panic("nil map") })
+
+	for _, j := range []*repo.Job{
+		{ID: 1, Kind: "ok", MaxAttempts: 5},
+		{ID: 2, Kind: "flaky", MaxAttempts: 5},
+		{ID: 3, Kind: "flaky", MaxAttempts: 1},
+		{ID: 4, Kind: "bad", MaxAttempts: 5},
+		{ID: 5, Kind: "boom", MaxAttempts: 5},
+		{ID: 6, Kind: "unknown", MaxAttempts: 5},
+	} {
+		_, _ = s.Enqueue(context.Background(), j)
+	}
+	for {
+		worked, err := w.RunOnce(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !worked {
+			break
+		}
+	}
+
+	if len(s.done) != 1 || s.done[0] != 1 {
+		t.Fatalf("expected job 1 done, got %v", s.done)
+	}
+	if s.retryAts[2] == nil || s.retryAts[5] == nil {
+		t.Fatalf("expected transient failures and panics to be retried: %v", s.retryAts)
+	}
+	for _, id := range []int64{3, 4, 6} {
+		if msg, ok := s.failed[id]; !ok || s.retryAts[id] != nil {
+			t.Fatalf("expected job %d to fail for good, got %q retry=%v", id, msg, s.retryAts[id])
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := map[int]time.Duration{1: 10 * time.Second, 2: 20 * time.Second, 4: 80 * time.Second, 20: time.Hour}
+	for attempt, want := range cases {
+		if got := Backoff(attempt); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// backend/internal/jobs/schedule.go
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"pft/internal/cron"
+	"pft/internal/repo"
+)
+
+// ScheduleStore is the queue the Scheduler feeds; implemented by repo.JobRepo.
+type ScheduleStore interface {
+	Enqueue(ctx context.Context, j *repo.Job) (*repo.Job, error)
+	Latest(ctx context.Context, kind string) (*repo.Job, error)
+}
+
+// Scheduler enqueues periodic jobs on cron schedules (UTC); the worker runs them.
+// Each occurrence is enqueued with the UniqueKey "<kind>:<time>", so instances sharing
+// a schedule do not queue duplicates.
+// - Overrides: cron expression per kind replacing the default passed to Add; "off" disables the kind
+type Scheduler struct {
+	Store     ScheduleStore
+	Overrides map[string]string
+
+	entries []scheduled
+}
+
+type scheduled struct {
+	kind  string
+	sched *cron.Schedule
+}
+
+// ScheduleStatus describes one scheduled kind for operators.
+// - NextRun: next occurrence; nil if the expression never matches again
+// - LastJob: the most recently enqueued job of the kind, if any
+type ScheduleStatus struct {
+	Kind     string     `json:"kind"`
+	Schedule string     `json:"schedule"`
+	NextRun  *time.Time `json:"next_run"`
+	LastJob  *repo.Job  `json:"last_job"`
+}
+
+// Add schedules kind on spec, or on its override. Call before Run.
+func (s *Scheduler) Add(kind, spec string) error {
+	if o, ok := s.Overrides[kind]; ok {
+		spec = o
+	}
+	if spec == "off" {
+		return nil
+	}
+	sched, err := cron.Parse(spec)
+	if err != nil {
+		return fmt.Errorf("schedule %s: %w", kind, err)
+	}
+	s.entries = append(s.entries, scheduled{kind: kind, sched: sched})
+	return nil
+}
+
+// Run enqueues jobs as their occurrences come due until ctx is cancelled. On start,
+// each kind's latest past occurrence is enqueued unless a job of the kind was created
+// since, so runs missed while no instance was up are caught up once.
+func (s *Scheduler) Run(ctx context.Context) {
+	now := time.Now().UTC()
+	for _, e := range s.entries {
+		prev := e.sched.Prev(now)
+		if prev.IsZero() {
+			continue
+		}
+		last, err := s.Store.Latest(ctx, e.kind)
+		if err != nil {
+			slog.Error("read last scheduled job failed", "kind", e.kind, "error", err.Error())
+			continue
+		}
+		if last == nil || last.CreatedAt.Before(prev) {
+			s.enqueue(ctx, e.kind, prev)
+		}
+	}
+	for {
+		now = time.Now().UTC()
+		var due time.Time
+		for _, e := range s.entries {
+			if n := e.sched.Next(now); !n.IsZero() && (due.IsZero() || n.Before(due)) {
+				due = n
+			}
+		}
+		if due.IsZero() {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(due)):
+		}
+		for _, e := range s.entries {
+			if e.sched.Prev(due).Equal(due) {
+				s.enqueue(ctx, e.kind, due)
+			}
+		}
+	}
+}
+
+func (s *Scheduler) enqueue(ctx context.Context, kind string, at time.Time) {
+	key := kind + ":" + at.Format("2006-01-02T15:04")
+	if _, err := s.Store.Enqueue(ctx, &repo.Job{Kind: kind, UniqueKey: &key}); err != nil && ctx.Err() == nil {
+		slog.Error("enqueue scheduled job failed", "kind", kind, "error", err.Error())
+	}
+}
+
+// Status reports every scheduled kind with its next occurrence after now and its last job.
+func (s *Scheduler) Status(ctx context.Context, now time.Time) ([]ScheduleStatus, error) {
+	out := make([]ScheduleStatus, 0, len(s.entries))
+	for _, e := range s.entries {
+		st := ScheduleStatus{Kind: e.kind, Schedule: e.sched.String()}
+		if n := e.sched.Next(now); !n.IsZero() {
+			st.NextRun = &n
+		}
+		last, err := s.Store.Latest(ctx, e.kind)
+		if err != nil {
+			return nil, err
+		}
+		st.LastJob = last
+		out = append(out, st)
+	}
+	return out, nil
+}
@@ -0,0 +1,86 @@
+// backend/internal/jobs/schedule_test.go
+//
+// Purpose:
+//   Verify that schedules honour overrides, that start-up catches up a missed
+//   occurrence only once, and that Status reports the next run and last job.
+
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pft/internal/repo"
+)
+
+// scheduleStore records enqueued jobs and serves the latest job per kind.
+type scheduleStore struct {
+	enqueued []*repo.Job
+	latest   map[string]*repo.Job
+}
+
+func (s *scheduleStore) Enqueue(_ context.Context, j *repo.Job) (*repo.Job, error) {
+	s.enqueued = append(s.enqueued, j)
+	return j, nil
+}
+
+func (s *scheduleStore) Latest(_ context.Context, kind string) (*repo.Job, error) {
+	return s.latest[kind], nil
+}
+
+func TestScheduler_Add(t *testing.T) {
+	s := &Scheduler{Overrides: map[string]string{"b": "off", "c": "*/5 * * * *"}}
+	for kind, spec := range map[string]string{"a": "@daily", "b": "@daily", "c": "@daily"} {
+		if err := s.Add(kind, spec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.entries) != 2 {
+		t.Fatalf("expected b to be disabled, got %+v", s.entries)
+	}
+	for _, e := range s.entries {
+		if e.kind == "c" && e.sched.String() != "*/5 * * * *" {
+			t.Errorf("override not applied: %s", e.sched)
+		}
+	}
+	if err := s.Add("d", "every day"); err == nil {
+		t.Error("expected an invalid expression to be rejected")
+	}
+}
+
+func TestScheduler_RunCatchesUp(t *testing.T) {
+	now := time.Now().UTC()
+	store := &scheduleStore{latest: map[string]*repo.Job{
+		"fresh": {Kind: "fresh", CreatedAt: now},
+		"stale": {Kind: "stale", CreatedAt: now.Add(-48 * time.Hour)},
+	}}
+	s := &Scheduler{Store: store}
+	for _, kind := range []string{"fresh", "stale", "new"} {
+		if err := s.Add(kind, "@daily"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.Run(ctx)
+
+	var kinds []string
+	for _, j := range store.enqueued {
+		kinds = append(kinds, j.Kind)
+		if want := j.Kind + ":" + now.Format("2006-01-02") + "T00:00"; *j.UniqueKey != want {
+			t.Errorf("unique key %q, want %q", *j.UniqueKey, want)
+		}
+	}
+	if len(kinds) != 2 || kinds[0] != "stale" || kinds[1] != "new" {
+		t.Fatalf("caught up %v, want [stale new]", kinds)
+	}
+
+	status, err := s.Status(context.Background(), now)
+	if err != nil || len(status) != 3 {
+		t.Fatalf("Status = %+v, %v", status, err)
+	}
+	if status[0].LastJob == nil || status[2].LastJob != nil || status[0].NextRun == nil || !status[0].NextRun.After(now) {
+		t.Errorf("Status = %+v", status)
+	}
+}
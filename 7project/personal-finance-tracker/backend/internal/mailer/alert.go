@@ -0,0 +1,53 @@
+// backend/internal/mailer/alert.go
+
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"pft/internal/alerts"
+	"pft/internal/jobs"
+	"pft/internal/repo"
+)
+
+// Users looks up alert recipients; implemented by repo.UserRepo.
+type Users interface {
+	GetByID(ctx context.Context, id int64) (*repo.User, error)
+}
+
+// Channel is the alerts.Channel for email: it mails alerts to the user's address.
+// Locale gives the user's language for the message around the alert; nil means English.
+type Channel struct {
+	Users  Users
+	Mailer *Mailer
+	Locale func(ctx context.Context, userID int64) string
+}
+
+// Name implements alerts.Channel.
+func (c *Channel) Name() string { return alerts.ChannelEmail }
+
+// Deliver implements alerts.Channel. The alert's job already retries, so the message
+// is sent right away rather than queued again.
+func (c *Channel) Deliver(ctx context.Context, a *alerts.Alert) error {
+	u, err := c.Users.GetByID(ctx, a.UserID)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return jobs.Permanent(fmt.Errorf("user %d no longer exists", a.UserID))
+	}
+	lang := ""
+	if c.Locale != nil {
+		lang = c.Locale(ctx, a.UserID)
+	}
+	msg, err := Render(lang, "alert", a)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+	msg.To = u.Email
+	if err := c.Mailer.Deliver(ctx, msg); err != nil {
+		return fmt.Errorf("%s: %w", c.Mailer.Sender.Name(), err)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+// backend/internal/mailer/log.go
+
+package mailer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Log writes messages to the log instead of sending them; for development.
+type Log struct{}
+
+// Name implements Sender.
+func (Log) Name() string { return "log" }
+
+// Send implements Sender.
+func (Log) Send(_ context.Context, msg *Message) error {
+	slog.Info("mail not sent (MAIL_PROVIDER=log)", "from", msg.From, "to", msg.To,
+		"subject", msg.Subject, "text", msg.Text)
+	return nil
+}
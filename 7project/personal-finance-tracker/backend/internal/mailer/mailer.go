@@ -0,0 +1,95 @@
+// backend/internal/mailer/mailer.go
+
+// Package mailer sends email: password resets, alerts, reports and invitations.
+// Messages are rendered from embedded templates in the recipient's language and
+// queued as "mail.send" jobs, so a failed delivery is retried with backoff by the
+// jobs worker and survives restarts. Delivery goes through a Sender: SMTP, Amazon
+// SES, SendGrid, or Log, which only logs messages (development).
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+
+	"pft/internal/jobs"
+	"pft/internal/repo"
+)
+
+// JobKind is the job kind delivering one queued message.
+const JobKind = "mail.send"
+
+// Message is one email. From defaults to the Mailer's address; HTML is optional.
+type Message struct {
+	From    string `json:"from,omitempty"`
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html,omitempty"`
+}
+
+// Sender delivers messages through one transport.
+type Sender interface {
+	// Name identifies the transport in logs.
+	Name() string
+	// Send delivers msg. Errors wrapped with jobs.Permanent are not retried.
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Queue stores messages until they are sent; implemented by repo.JobRepo.
+type Queue interface {
+	Enqueue(ctx context.Context, j *repo.Job) (*repo.Job, error)
+}
+
+// Mailer renders and queues messages and delivers them from the queue.
+// - Sender: transport used by Deliver
+// - From: sender address, e.g. "Finance Tracker <no-reply@example.com>"
+// - Queue: where Send queues messages
+type Mailer struct {
+	Sender Sender
+	From   string
+	Queue  Queue
+}
+
+// Send renders template in lang for the recipient and queues it for delivery,
+// returning the job. userID, when set, attributes the job to that user.
+func (m *Mailer) Send(ctx context.Context, userID *int64, to, lang, template string, data any) (*repo.Job, error) {
+	if _, err := mail.ParseAddress(to); err != nil {
+		return nil, fmt.Errorf("mailer: invalid recipient %q", to)
+	}
+	msg, err := Render(lang, template, data)
+	if err != nil {
+		return nil, err
+	}
+	msg.To = to
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return m.Queue.Enqueue(ctx, &repo.Job{Kind: JobKind, UserID: userID, Payload: payload, MaxAttempts: 8})
+}
+
+// Deliver sends msg now through the Sender.
+func (m *Mailer) Deliver(ctx context.Context, msg *Message) error {
+	if msg.From == "" {
+		msg.From = m.From
+	}
+	return m.Sender.Send(ctx, msg)
+}
+
+// Handle is the jobs.HandlerFunc for JobKind.
+func (m *Mailer) Handle(ctx context.Context, j *repo.Job) error {
+	var msg Message
+	if err := json.Unmarshal(j.Payload, &msg); err != nil {
+		return jobs.Permanent(fmt.Errorf("decode message: %w", err))
+	}
+	if msg.To == "" {
+		return jobs.Permanent(errors.New("message has no recipient"))
+	}
+	if err := m.Deliver(ctx, &msg); err != nil {
+		return fmt.Errorf("%s: %w", m.Sender.Name(), err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// backend/internal/mailer/mailer_test.go
+//
+// Purpose:
+//   Verify template rendering per language, queued delivery through a Sender,
+//   alerts mailed to their user, the SendGrid request and status mapping and MIME
+//   formatting.
+
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pft/internal/alerts"
+	"pft/internal/repo"
+)
+
+type fakeQueue struct{ jobs []*repo.Job }
+
+func (q *fakeQueue) Enqueue(_ context.Context, j *repo.Job) (*repo.Job, error) {
+	q.jobs = append(q.jobs, j)
+	return j, nil
+}
+
+type fakeSender struct {
+	sent []*Message
+	err  error
+}
+
+func (s *fakeSender) Name() string { return "fake" }
+func (s *fakeSender) Send(_ context.Context, m *Message) error {
+	s.sent = append(s.sent, m)
+	return s.err
+}
+
+func TestRender(t *testing.T) {
+	data := map[string]string{"Sender": "smtp"}
+	en, err := Render("en", "test", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if en.Subject != "Test email from Personal Finance Tracker" || !strings.Contains(en.Text, "sent through smtp") {
+		t.Errorf("en = %+v", en)
+	}
+	if !strings.Contains(en.HTML, "<p>Email delivery works") {
+		t.Errorf("en HTML = %q", en.HTML)
+	}
+	de, err := Render("de", "test", data)
+	if err != nil || !strings.HasPrefix(de.Subject, "Test-E-Mail") {
+		t.Errorf("de = %+v, %v", de, err)
+	}
+	if fr, err := Render("fr", "test", data); err != nil || fr.Subject != en.Subject {
+		t.Errorf("unsupported language should fall back to English, got %+v, %v", fr, err)
+	}
+	if _, err := Render("en", "nope", data); err == nil {
+		t.Error("unknown template should fail")
+	}
+}
+
+func TestMailer_SendAndHandle(t *testing.T) {
+	q, s := &fakeQueue{}, &fakeSender{}
+	m := &Mailer{Sender: s, From: "Tracker <no-reply@example.com>", Queue: q}
+	uid := int64(7)
+	if _, err := m.Send(context.Background(), &uid, "not an address", "en", "test", nil); err == nil {
+		t.Error("invalid recipient should be rejected before queueing")
+	}
+	j, err := m.Send(context.Background(), &uid, "ann@example.com", "en", "test", map[string]string{"Sender": "log"})
+	if err != nil || j.Kind != JobKind || *j.UserID != 7 {
+		t.Fatalf("Send = %+v, %v", j, err)
+	}
+	if err := m.Handle(context.Background(), j); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.sent) != 1 || s.sent[0].To != "ann@example.com" || s.sent[0].From != m.From {
+		t.Errorf("sent %+v", s.sent)
+	}
+
+	s.err = errors.New("connection reset")
+	if err := m.Handle(context.Background(), j); err == nil || !strings.Contains(err.Error(), "fake: connection reset") {
+		t.Errorf("Handle error = %v", err)
+	}
+}
+
+type fakeUsers map[int64]*repo.User
+
+func (u fakeUsers) GetByID(_ context.Context, id int64) (*repo.User, error) { return u[id], nil }
+
+func TestChannel_Deliver(t *testing.T) {
+	s := &fakeSender{}
+	c := &Channel{
+		Users:  fakeUsers{7: {ID: 7, Email: "ann@example.com"}},
+		Mailer: &Mailer{Sender: s, From: "Tracker <no-reply@example.com>"},
+		Locale: func(context.Context, int64) string { return "de" },
+	}
+	a := &alerts.Alert{UserID: 7, Title: "Budget Food überschritten", Body: "Sie haben 120.00 ausgegeben <viel>."}
+	if err := c.Deliver(context.Background(), a); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.sent) != 1 {
+		t.Fatalf("sent %d messages", len(s.sent))
+	}
+	m := s.sent[0]
+	if m.To != "ann@example.com" || m.Subject != a.Title || !strings.HasPrefix(m.Text, a.Body) ||
+		!strings.Contains(m.Text, "E-Mail-Benachrichtigungen") || !strings.Contains(m.HTML, "&lt;viel&gt;") {
+		t.Errorf("message %+v", m)
+	}
+
+	if err := c.Deliver(context.Background(), &alerts.Alert{UserID: 8}); err == nil || len(s.sent) != 1 {
+		t.Errorf("alert for a deleted user: err = %v, sent %d", err, len(s.sent))
+	}
+}
+
+func TestSendGrid(t *testing.T) {
+	var got sendGridRequest
+	status := http.StatusAccepted
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/mail/send" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("request %s with %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sg := &SendGrid{APIKey: "key", BaseURL: srv.URL}
+	msg := &Message{From: "Tracker <no-reply@example.com>", To: "ann@example.com", Subject: "Hi", Text: "t", HTML: "<p>h</p>"}
+	if err := sg.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if got.From.Name != "Tracker" || got.Personalizations[0].To[0].Email != "ann@example.com" || len(got.Content) != 2 {
+		t.Errorf("request body %+v", got)
+	}
+
+	status = http.StatusBadRequest
+	if err := sg.Send(context.Background(), msg); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("400 should fail, got %v", err)
+	}
+}
+
+func TestBuildMIME(t *testing.T) {
+	b, err := buildMIME(&Message{From: "a@example.com", To: "b@example.com", Subject: "Grüße", Text: "plain", HTML: "<b>html</b>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(b)
+	for _, want := range []string{"Subject: =?utf-8?q?Gr=C3=BC=C3=9Fe?=\r\n", "multipart/alternative; boundary=", "@example.com>\r\n", "text/html; charset=utf-8", "<b>html</b>"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("message lacks %q:\n%s", want, s)
+		}
+	}
+}
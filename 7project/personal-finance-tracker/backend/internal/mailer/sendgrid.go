@@ -0,0 +1,114 @@
+// backend/internal/mailer/sendgrid.go
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"pft/internal/jobs"
+)
+
+// DefaultSendGridURL is the SendGrid v3 API.
+const DefaultSendGridURL = "https://api.sendgrid.com"
+
+// SendGrid delivers messages through the SendGrid v3 mail/send API.
+type SendGrid struct {
+	APIKey  string
+	BaseURL string       // defaults to DefaultSendGridURL
+	Client  *http.Client // defaults to a client with a 15s timeout
+}
+
+// Name implements Sender.
+func (s *SendGrid) Name() string { return "sendgrid" }
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+type sendGridRequest struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+}
+
+// Send implements Sender. Client errors other than 429 are permanent.
+func (s *SendGrid) Send(ctx context.Context, msg *Message) error {
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid sender %q", msg.From))
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid recipient %q", msg.To))
+	}
+	body := sendGridRequest{
+		Personalizations: []sendGridPersonalization{{To: []sendGridAddress{{Email: to.Address, Name: to.Name}}}},
+		From:             sendGridAddress{Email: from.Address, Name: from.Name},
+		Subject:          msg.Subject,
+		Content:          []sendGridContent{{Type: "text/plain", Value: msg.Text}},
+	}
+	if msg.HTML != "" {
+		body.Content = append(body.Content, sendGridContent{Type: "text/html", Value: msg.HTML})
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+
+	base := s.BaseURL
+	if base == "" {
+		base = DefaultSendGridURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(base, "/")+"/v3/mail/send", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+	return doHTTP(s.client(), req)
+}
+
+func (s *SendGrid) client() *http.Client {
+	if s.Client == nil {
+		return &http.Client{Timeout: 15 * time.Second}
+	}
+	return s.Client
+}
+
+// doHTTP sends an API request and maps its status: 2xx is success, 429 and 5xx are
+// retried, and other client errors are permanent.
+func doHTTP(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return jobs.Permanent(err)
+	}
+	return err
+}
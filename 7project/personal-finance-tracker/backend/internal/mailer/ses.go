@@ -0,0 +1,88 @@
+// backend/internal/mailer/ses.go
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"pft/internal/awssig"
+	"pft/internal/jobs"
+)
+
+// SES delivers messages through the Amazon SES v2 SendEmail API, signing requests
+// with AWS Signature Version 4.
+// - Region: SES region, e.g. "eu-west-1"
+// - AccessKeyID/SecretAccessKey: IAM credentials allowed to call ses:SendEmail
+// - Endpoint: API base URL (default https://email.<region>.amazonaws.com)
+type SES struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Endpoint        string
+	Client          *http.Client // defaults to a client with a 15s timeout
+}
+
+// Name implements Sender.
+func (s *SES) Name() string { return "ses" }
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+type sesRequest struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    struct {
+				Text *sesContent `json:"Text,omitempty"`
+				HTML *sesContent `json:"Html,omitempty"`
+			} `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+}
+
+// Send implements Sender. Client errors other than 429 are permanent.
+func (s *SES) Send(ctx context.Context, msg *Message) error {
+	var body sesRequest
+	body.FromEmailAddress = msg.From
+	body.Destination.ToAddresses = []string{msg.To}
+	simple := &body.Content.Simple
+	simple.Subject = sesContent{Data: msg.Subject, Charset: "UTF-8"}
+	simple.Body.Text = &sesContent{Data: msg.Text, Charset: "UTF-8"}
+	if msg.HTML != "" {
+		simple.Body.HTML = &sesContent{Data: msg.HTML, Charset: "UTF-8"}
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = "https://email." + s.Region + ".amazonaws.com"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(endpoint, "/")+"/v2/email/outbound-emails", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	awssig.Sign(req, awssig.HashPayload(b), awssig.Credentials{
+		Region: s.Region, Service: "ses", AccessKeyID: s.AccessKeyID, SecretAccessKey: s.SecretAccessKey,
+	}, time.Now())
+
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	return doHTTP(client, req)
+}
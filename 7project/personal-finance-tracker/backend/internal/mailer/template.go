@@ -0,0 +1,63 @@
+// backend/internal/mailer/template.go
+
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+
+	"pft/internal/i18n"
+)
+
+// Templates live in templates/: <name>.txt defines the "subject" and "text" blocks,
+// and an optional <name>.html the HTML body. Their wording comes from the i18n
+// catalogs through the t function, so one template serves every language:
+//
+//	{{define "subject"}}{{t "mail.test.subject"}}{{end}}
+//	{{define "text"}}{{t "mail.test.body" "name" .Name}}{{end}}
+//
+//go:embed templates/*
+var templateFiles embed.FS
+
+// Render builds the message for template in lang from data; To and From are left empty.
+// Unsupported languages fall back to English.
+func Render(lang, name string, data any) (*Message, error) {
+	if !i18n.Supports(lang) {
+		lang = i18n.Default
+	}
+	t := func(key string, args ...string) string { return i18n.T(lang, key, args...) }
+
+	src, err := templateFiles.ReadFile("templates/" + name + ".txt")
+	if err != nil {
+		return nil, fmt.Errorf("mailer: unknown template %q", name)
+	}
+	tt, err := template.New(name).Funcs(template.FuncMap{"t": t}).Parse(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("mailer: template %s: %w", name, err)
+	}
+	var subject, text bytes.Buffer
+	if err := tt.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("mailer: template %s: %w", name, err)
+	}
+	if err := tt.ExecuteTemplate(&text, "text", data); err != nil {
+		return nil, fmt.Errorf("mailer: template %s: %w", name, err)
+	}
+	msg := &Message{Subject: strings.TrimSpace(subject.String()), Text: strings.TrimSpace(text.String()) + "\n"}
+
+	if src, err := templateFiles.ReadFile("templates/" + name + ".html"); err == nil {
+		ht, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap{"t": t}).Parse(string(src))
+		if err != nil {
+			return nil, fmt.Errorf("mailer: template %s.html: %w", name, err)
+		}
+		var html bytes.Buffer
+		if err := ht.Execute(&html, data); err != nil {
+			return nil, fmt.Errorf("mailer: template %s.html: %w", name, err)
+		}
+		msg.HTML = html.String()
+	}
+	return msg, nil
+}
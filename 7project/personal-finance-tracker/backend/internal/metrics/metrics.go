@@ -0,0 +1,49 @@
+// backend/internal/metrics/metrics.go
+
+// Package metrics holds the Prometheus collectors exported at GET /metrics.
+// Collectors are registered with the default registry at init so any package can
+// record into them without wiring.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DBQueryDuration observes every SQL statement, labelled by statement kind
+// (select, insert, update, delete, other) and outcome (ok, error).
+var DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "pft",
+	Subsystem: "db",
+	Name:      "query_duration_seconds",
+	Help:      "Duration of SQL statements by kind and outcome.",
+	Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+}, []string{"op", "status"})
+
+// DBSlowQueries counts statements that exceeded the slow-query threshold.
+var DBSlowQueries = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "pft",
+	Subsystem: "db",
+	Name:      "slow_queries_total",
+	Help:      "SQL statements slower than the configured threshold, by kind.",
+}, []string{"op"})
+
+// DBTimeouts counts statements cancelled for running out of time, by kind: the
+// request's deadline passed or Postgres's statement_timeout fired.
+var DBTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "pft",
+	Subsystem: "db",
+	Name:      "timeouts_total",
+	Help:      "SQL statements cancelled by a deadline or statement_timeout, by kind.",
+}, []string{"op"})
+
+func init() {
+	prometheus.MustRegister(DBQueryDuration, DBSlowQueries, DBTimeouts)
+}
+
+// Handler serves the default registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,146 @@
+// backend/internal/outbox/outbox.go
+
+// Package outbox delivers change events from the transactional outbox (migration 031)
+// to subscribers such as webhooks, live streams and notifications. Events are written
+// by the database in the same transaction as the change, so none is lost or emitted
+// for a rolled-back write; the Dispatcher then hands each one to every subscriber.
+//
+// Delivery is at least once: an event is retried, to every subscriber, until all of
+// them accept it, so subscribers must ignore event IDs they have already handled.
+// Events are dispatched in ID order, but a retried event may arrive after later ones.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"pft/internal/jobs"
+	"pft/internal/repo"
+)
+
+// Store is the outbox table; implemented by repo.OutboxRepo.
+type Store interface {
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]repo.OutboxEvent, error)
+	Delivered(ctx context.Context, ids []int64) error
+	Fail(ctx context.Context, id int64, msg string, retryAt *time.Time) error
+}
+
+// Subscriber handles one event. Returning an error retries the event later.
+type Subscriber func(ctx context.Context, e *repo.OutboxEvent) error
+
+// Dispatcher polls the outbox and delivers events to subscribers.
+// - Batch: events claimed per poll (default 100)
+// - PollInterval: wait after finding the outbox empty (default 1s)
+// - Lease: time a batch may take before another dispatcher may claim it (default 5m)
+// - MaxAttempts: deliveries tried before an event is given up on (default 10)
+type Dispatcher struct {
+	Store        Store
+	Batch        int
+	PollInterval time.Duration
+	Lease        time.Duration
+	MaxAttempts  int
+
+	subs []subscription
+}
+
+type subscription struct {
+	name string
+	fn   Subscriber
+}
+
+// Subscribe adds a subscriber; name identifies it in logs. Call before Run.
+func (d *Dispatcher) Subscribe(name string, fn Subscriber) {
+	d.subs = append(d.subs, subscription{name: name, fn: fn})
+}
+
+// Run delivers events until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	poll := d.PollInterval
+	if poll <= 0 {
+		poll = time.Second
+	}
+	for ctx.Err() == nil {
+		n, err := d.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("outbox unavailable", "error", err.Error())
+		}
+		if n > 0 && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(poll):
+		}
+	}
+}
+
+// RunOnce claims one batch and delivers it, returning the number of events claimed.
+// The error covers outbox access only; delivery failures are recorded on the events.
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	batch, lease, maxAttempts := d.Batch, d.Lease, d.MaxAttempts
+	if batch <= 0 {
+		batch = 100
+	}
+	if lease <= 0 {
+		lease = 5 * time.Minute
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 10
+	}
+	events, err := d.Store.Claim(ctx, batch, lease)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	dctx, cancel := context.WithTimeout(ctx, lease)
+	defer cancel()
+	// Settle events even if ctx is cancelled meanwhile.
+	sctx := context.WithoutCancel(ctx)
+	var delivered []int64
+	for i := range events {
+		e := &events[i]
+		if err := d.deliver(dctx, e); err != nil {
+			var retryAt *time.Time
+			if e.Attempts < maxAttempts {
+				t := time.Now().Add(jobs.Backoff(e.Attempts))
+				retryAt = &t
+			}
+			slog.Warn("outbox delivery failed", "event_id", e.ID, "topic", e.Topic,
+				"attempt", e.Attempts, "error", err.Error(), "retry", retryAt != nil)
+			if err := d.Store.Fail(sctx, e.ID, err.Error(), retryAt); err != nil {
+				return len(events), err
+			}
+			continue
+		}
+		delivered = append(delivered, e.ID)
+	}
+	if len(delivered) > 0 {
+		if err := d.Store.Delivered(sctx, delivered); err != nil {
+			return len(events), err
+		}
+	}
+	return len(events), nil
+}
+
+// deliver hands e to every subscriber, scoped to the event's user (row-level
+// security), and turns panics into errors so one bad subscriber cannot stop delivery.
+func (d *Dispatcher) deliver(ctx context.Context, e *repo.OutboxEvent) error {
+	ctx = repo.WithTenant(ctx, e.UserID)
+	for _, s := range d.subs {
+		if err := d.call(ctx, s, e); err != nil {
+			return fmt.Errorf("%s: %w", s.name, err)
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) call(ctx context.Context, s subscription, e *repo.OutboxEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return s.fn(ctx, e)
+}
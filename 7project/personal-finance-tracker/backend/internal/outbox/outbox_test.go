@@ -0,0 +1,95 @@
+// backend/internal/outbox/outbox_test.go
+//
+// Purpose:
+//   Verify the dispatcher hands events to every subscriber in order, marks them
+//   delivered, retries failures with backoff and gives up after MaxAttempts.
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pft/internal/repo"
+)
+
+// fakeStore returns the queued events once and records how each was settled.
+type fakeStore struct {
+	queue     []repo.OutboxEvent
+	delivered []int64
+	failed    map[int64]*time.Time
+}
+
+func (s *fakeStore) Claim(_ context.Context, limit int, _ time.Duration) ([]repo.OutboxEvent, error) {
+	n := min(limit, len(s.queue))
+	out := s.queue[:n]
+	s.queue = s.queue[n:]
+	for i := range out {
+		out[i].Attempts++
+	}
+	return out, nil
+}
+
+func (s *fakeStore) Delivered(_ context.Context, ids []int64) error {
+	s.delivered = append(s.delivered, ids...)
+	return nil
+}
+
+func (s *fakeStore) Fail(_ context.Context, id int64, _ string, retryAt *time.Time) error {
+	s.failed[id] = retryAt
+	return nil
+}
+
+func TestDispatcher_RunOnce(t *testing.T) {
+	s := &fakeStore{failed: map[int64]*time.Time{}, queue: []repo.OutboxEvent{
+		{ID: 1, UserID: 7, Topic: "budgets.created"},
+		{ID: 2, UserID: 7, Topic: "transactions.updated"},
+		{ID: 3, UserID: 7, Topic: "transactions.updated", Attempts: 2},
+		{ID: 4, UserID: 8, Topic: "categories.deleted"},
+	}}
+	d := &Dispatcher{Store: s, MaxAttempts: 3}
+
+	var seen []int64
+	d.Subscribe("record", func(ctx context.Context, e *repo.OutboxEvent) error {
+		if uid, ok := repo.TenantFrom(ctx); !ok || uid != e.UserID {
+			t.Errorf("event %d delivered with tenant %d, %v", e.ID, uid, ok)
+		}
+		seen = append(seen, e.ID)
+		return nil
+	})
+	d.Subscribe("flaky", func(_ context.Context, e *repo.OutboxEvent) error {
+		switch e.Topic {
+		case "transactions.updated":
+			return errors.New("connection refused")
+		case "categories.deleted":
+			panic("nil map")
+		}
+		return nil
+	})
+
+	n, err := d.RunOnce(context.Background())
+	if err != nil || n != 4 {
+		t.Fatalf("RunOnce = %d, %v", n, err)
+	}
+	if len(seen) != 4 || seen[0] != 1 || seen[3] != 4 {
+		t.Errorf("first subscriber saw %v, want all events in order", seen)
+	}
+	if len(s.delivered) != 1 || s.delivered[0] != 1 {
+		t.Errorf("delivered %v, want [1]", s.delivered)
+	}
+	if at := s.failed[2]; at == nil || time.Until(*at) < 5*time.Second {
+		t.Errorf("event 2 should be retried with backoff, got %v", at)
+	}
+	if at, ok := s.failed[3]; !ok || at != nil {
+		t.Errorf("event 3 exhausted its attempts and should be given up, got %v", at)
+	}
+	if _, ok := s.failed[4]; !ok {
+		t.Error("a panicking subscriber should fail the event")
+	}
+
+	if n, err := d.RunOnce(context.Background()); n != 0 || err != nil {
+		t.Errorf("empty outbox: RunOnce = %d, %v", n, err)
+	}
+}
@@ -1,14 +1,37 @@
 // backend/internal/platform/config_test.go
 //
 // Purpose:
-//   Exercise getenv() defaulting and must() required-variable behavior.
+//   Exercise getenv() defaulting, config file + environment layering in Load(),
+//   and Validate() reporting every missing or malformed setting at once.
 
 package platform
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
+// clearConfigEnv unsets every variable Load reads so the host environment cannot leak in.
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv(ConfigFileEnv, "")
+	for _, f := range (&Config{}).fields() {
+		t.Setenv(f.env, "")
+	}
+}
+
+func writeFile(t *testing.T, name, body string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(p, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
 func TestGetenv_DefaultApplied(t *testing.T) {
 	t.Setenv("SOME_MISSING_VAR", "")
 	if v := getenv("SOME_MISSING_VAR", "fallback"); v != "fallback" {
@@ -16,19 +39,203 @@ func TestGetenv_DefaultApplied(t *testing.T) {
 	}
 }
 
-func TestMust_PanicsWhenMissing(t *testing.T) {
-	t.Setenv("ABSENT_VAR", "")
-	defer func() {
-		if r := recover(); r == nil {
-			t.Fatalf("expected panic for missing env")
+func TestLoad_EnvOnlyWithDefaults(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("DB_DSN", "postgres://x")
+	t.Setenv("JWT_SECRET", "s")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Port != "8080" || cfg.RatesBase != "EUR" || cfg.LogFormat != "json" {
+		t.Fatalf("defaults not applied: %+v", cfg)
+	}
+}
+
+func TestLoad_FileMergedWithEnv(t *testing.T) {
+	for name, body := range map[string]string{
+		"app.yaml": "port: \"9000\"\ndb_dsn: postgres://file\njwt_secret: filesecret\nlog_level: debug\n",
+		"app.toml": "port = \"9000\"\ndb_dsn = \"postgres://file\"\njwt_secret = \"filesecret\"\nlog_level = \"debug\"\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			clearConfigEnv(t)
+			t.Setenv(ConfigFileEnv, writeFile(t, name, body))
+			t.Setenv("JWT_SECRET", "envsecret")
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.Port != "9000" || cfg.DB_DSN != "postgres://file" || cfg.LogLevel != "debug" {
+				t.Fatalf("file values not applied: %+v", cfg)
+			}
+			if cfg.JWTSecret != "envsecret" {
+				t.Fatalf("env should override file, got %q", cfg.JWTSecret)
+			}
+			if cfg.RatesProvider != "frankfurter" {
+				t.Fatalf("keys absent from the file should keep defaults, got %q", cfg.RatesProvider)
+			}
+		})
+	}
+}
+
+func TestLoad_RejectsBadFiles(t *testing.T) {
+	cases := map[string]string{
+		"app.yaml": "db_dsn: x\nunknown_key: 1\n",
+		"app.toml": "db_dsn = \n",
+		"app.ini":  "db_dsn=x\n",
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			clearConfigEnv(t)
+			t.Setenv(ConfigFileEnv, writeFile(t, name, body))
+			if _, err := Load(); err == nil {
+				t.Fatalf("expected error for %s", name)
+			}
+		})
+	}
+}
+
+func TestValidate_ListsAllProblems(t *testing.T) {
+	err := Config{Port: "http", RatesProvider: "oanda", RatesBase: "eur", LogLevel: "loud", LogFormat: "xml",
+		CORSOrigins: "app.example.com", CORSCredentials: "yes"}.Validate()
+	var ce *ConfigError
+	if !errors.As(err, &ce) {
+		t.Fatalf("expected *ConfigError, got %v", err)
+	}
+	for _, want := range []string{"DB_DSN", "JWT_SECRET", "PORT", "RATES_PROVIDER", "RATES_BASE", "LOG_LEVEL", "LOG_FORMAT",
+		"CORS_ALLOWED_ORIGINS", "CORS_ALLOW_CREDENTIALS"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %s to be reported in:\n%s", want, err)
 		}
-	}()
-	_ = must("ABSENT_VAR")
+	}
+	if len(ce.Problems) != 9 {
+		t.Fatalf("expected 9 problems, got %d", len(ce.Problems))
+	}
+}
+
+func TestValidate_CORSWildcardWithCredentials(t *testing.T) {
+	cfg := Config{DB_DSN: "x", JWTSecret: "s", Port: "8080", RatesProvider: "none", RatesBase: "EUR",
+		LogLevel: "info", LogFormat: "json", CORSOrigins: "*", CORSCredentials: "true"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "CORS_ALLOWED_ORIGINS") {
+		t.Fatalf("expected wildcard+credentials to be rejected, got %v", err)
+	}
+}
+
+func TestValidate_PoolSettings(t *testing.T) {
+	cfg := Config{DB_DSN: "postgres://x", JWTSecret: "s", Port: "8080", RatesProvider: "none", RatesBase: "EUR",
+		LogLevel: "info", LogFormat: "json", DBMaxConns: "4", DBMinConns: "8", DBMaxConnLifetime: "forever"}
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "cannot exceed DB_MAX_CONNS") || !strings.Contains(err.Error(), "DB_MAX_CONN_LIFETIME") {
+		t.Fatalf("expected pool settings to be rejected, got %v", err)
+	}
 }
 
-func TestMust_ReturnsValue(t *testing.T) {
-	t.Setenv("PRESENT_VAR", "value")
-	if v := must("PRESENT_VAR"); v != "value" {
-		t.Fatalf("expected value, got %q", v)
+func TestValidate_MailProvider(t *testing.T) {
+	cfg := Config{DB_DSN: "postgres://x", JWTSecret: "s", Port: "8080", RatesProvider: "none", RatesBase: "EUR",
+		LogLevel: "info", LogFormat: "json", MailProvider: "smtp", SMTPURL: "mail.example.com:587", MailFrom: "nobody"}
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "SMTP_URL") || !strings.Contains(err.Error(), "MAIL_FROM") {
+		t.Fatalf("expected SMTP_URL and MAIL_FROM to be rejected, got %v", err)
+	}
+	cfg.SMTPURL, cfg.MailFrom = "smtp://user:pw@mail.example.com:587", "Tracker <no-reply@example.com>"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid SMTP settings, got %v", err)
+	}
+	cfg.MailProvider = "ses"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "SES_REGION") {
+		t.Fatalf("expected missing SES credentials to be rejected, got %v", err)
+	}
+}
+
+func TestValidate_Encryption(t *testing.T) {
+	cfg := Config{DB_DSN: "postgres://x", JWTSecret: "s", Port: "8080", RatesProvider: "none", RatesBase: "EUR",
+		LogLevel: "info", LogFormat: "json", EncryptionKey: "c2hvcnQ=", KMSKeyID: "alias/pft"}
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "ENCRYPTION_KEY must be 32 bytes") || !strings.Contains(err.Error(), "not both") {
+		t.Fatalf("expected a short key and two key sources to be rejected, got %v", err)
+	}
+	cfg.EncryptionKey = ""
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "KMS_REGION") {
+		t.Fatalf("expected missing KMS settings to be rejected, got %v", err)
+	}
+	cfg.KMSKeyID, cfg.EncryptionKey = "", "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected a valid master key, got %v", err)
+	}
+}
+
+func TestValidate_SentryDSN(t *testing.T) {
+	cfg := Config{DB_DSN: "postgres://x", JWTSecret: "s", Port: "8080", RatesProvider: "none", RatesBase: "EUR",
+		LogLevel: "info", LogFormat: "json", SentryDSN: "https://sentry.example.com/1"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "SENTRY_DSN") {
+		t.Fatalf("expected a DSN without key to be rejected, got %v", err)
+	}
+	cfg.SentryDSN = "https://key@o1.ingest.sentry.io/42"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected a valid DSN, got %v", err)
+	}
+}
+
+func TestValidate_DebugEndpoints(t *testing.T) {
+	cfg := Config{DB_DSN: "postgres://x", JWTSecret: "s", Port: "8080", RatesProvider: "none", RatesBase: "EUR",
+		LogLevel: "info", LogFormat: "json", DebugEndpoints: "admin"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "requires ADMIN_TOKEN") {
+		t.Fatalf("expected admin debug endpoints without a token to be rejected, got %v", err)
+	}
+	cfg.DebugEndpoints = "localhost"
+	for addr, ok := range map[string]bool{"127.0.0.1:6060": true, "localhost:6060": true, "[::1]:6060": true,
+		"0.0.0.0:6060": false, ":6060": false, "10.0.0.5:6060": false} {
+		cfg.DebugAddr = addr
+		if err := cfg.Validate(); (err == nil) != ok {
+			t.Errorf("DEBUG_ADDR %q: got %v", addr, err)
+		}
+	}
+}
+
+func TestValidate_RatesProvider(t *testing.T) {
+	cfg := Config{DB_DSN: "postgres://x", JWTSecret: "s", Port: "8080", RatesProvider: "exchangerate.host", RatesBase: "EUR",
+		LogLevel: "info", LogFormat: "json"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "RATES_API_KEY") {
+		t.Fatalf("expected exchangerate.host without a key to be rejected, got %v", err)
+	}
+	cfg.RatesProvider = "manual"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "RATES_FILE") {
+		t.Fatalf("expected manual without a file to be rejected, got %v", err)
+	}
+	cfg.RatesFile = "/etc/pft/rates.json"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected manual rates with a file, got %v", err)
+	}
+}
+
+func TestValidate_Ingest(t *testing.T) {
+	cfg := Config{DB_DSN: "postgres://x", JWTSecret: "s", Port: "8080", RatesProvider: "none", RatesBase: "EUR",
+		LogLevel: "info", LogFormat: "json", IngestDomain: "in.example.com", IngestSecret: "short"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "INGEST_SECRET") {
+		t.Fatalf("expected a short ingest secret to be rejected, got %v", err)
+	}
+	cfg.IngestDomain, cfg.IngestSecret = "receipts@example.com", "0123456789abcdef"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "INGEST_DOMAIN") {
+		t.Fatalf("expected an address as ingest domain to be rejected, got %v", err)
+	}
+	cfg.IngestDomain = "in.example.com"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected a valid ingest setup, got %v", err)
+	}
+}
+
+func TestJobSchedules(t *testing.T) {
+	got, err := JobSchedules(" rates.refresh = 0 6 * * 1-5 ; trash.purge=off;")
+	if err != nil || len(got) != 2 || got["rates.refresh"] != "0 6 * * 1-5" || got["trash.purge"] != "off" {
+		t.Fatalf("JobSchedules = %v, %v", got, err)
+	}
+	cfg := Config{DB_DSN: "postgres://x", JWTSecret: "s", Port: "8080", RatesProvider: "none", RatesBase: "EUR",
+		LogLevel: "info", LogFormat: "json", JobSchedules: "rates.refresh=0 25 * * *"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "JOB_SCHEDULES") {
+		t.Fatalf("expected an invalid hour to be rejected, got %v", err)
+	}
+	cfg.JobSchedules = "@daily"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "kind=cron") {
+		t.Fatalf("expected a schedule without kind to be rejected, got %v", err)
 	}
 }
@@ -0,0 +1,94 @@
+// backend/internal/platform/db.go
+
+package platform
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// maxBackoff caps the delay between connection attempts.
+const maxBackoff = 30 * time.Second
+
+// PoolOptions tunes the pgx connection pool; zero values keep the pgxpool defaults
+// (MaxConns = max(4, CPUs), MinConns 0, MaxConnLifetime 1h, HealthCheckPeriod 1m).
+// - MaxConns/MinConns: upper bound and warm minimum of open connections
+// - MaxConnLifetime: connections are recycled after this age
+// - HealthCheckPeriod: how often idle connections are checked and MinConns restored
+// - StatementTimeout: Postgres's statement_timeout on every connection, so a runaway
+// query is cancelled by the server even when no context deadline covers it
+type PoolOptions struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	HealthCheckPeriod time.Duration
+	StatementTimeout  time.Duration
+}
+
+// Apply copies the non-zero options onto pcfg.
+func (o PoolOptions) Apply(pcfg *pgxpool.Config) {
+	if o.MaxConns > 0 {
+		pcfg.MaxConns = o.MaxConns
+	}
+	if o.MinConns > 0 {
+		pcfg.MinConns = o.MinConns
+	}
+	if o.MaxConnLifetime > 0 {
+		pcfg.MaxConnLifetime = o.MaxConnLifetime
+	}
+	if o.HealthCheckPeriod > 0 {
+		pcfg.HealthCheckPeriod = o.HealthCheckPeriod
+	}
+	if o.StatementTimeout > 0 {
+		pcfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(o.StatementTimeout.Milliseconds(), 10)
+	}
+}
+
+// ConnectDB creates a pool from pcfg and waits until Postgres answers a ping.
+// Failed pings are retried up to attempts times, doubling the delay from backoff
+// (capped at 30s), so the API tolerates a database that is still starting up.
+func ConnectDB(ctx context.Context, pcfg *pgxpool.Config, attempts int, backoff time.Duration) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.NewWithConfig(ctx, pcfg)
+	if err != nil {
+		return nil, err
+	}
+	err = Retry(ctx, attempts, backoff, func(ctx context.Context) error {
+		pctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		return pool.Ping(pctx)
+	})
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
+
+// Retry calls fn until it succeeds, attempts calls have failed, or ctx is done.
+// The wait before retry n is backoff * 2^(n-1), capped at 30s. Returns fn's last error.
+func Retry(ctx context.Context, attempts int, backoff time.Duration, fn func(context.Context) error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+	wait := backoff
+	var err error
+	for i := 1; ; i++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if i >= attempts {
+			return err
+		}
+		slog.Warn("retrying", "attempt", i, "of", attempts, "wait", wait.String(), "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, maxBackoff)
+	}
+}
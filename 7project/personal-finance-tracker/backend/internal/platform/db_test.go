@@ -0,0 +1,65 @@
+// backend/internal/platform/db_test.go
+//
+// Purpose:
+//   Verify Retry stops on the first success, gives up after the configured
+//   number of attempts, and honours context cancellation; and that PoolOptions
+//   only overrides the pool settings that were configured.
+
+package platform
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestRetry(t *testing.T) {
+	boom := errors.New("boom")
+
+	calls := 0
+	err := Retry(context.Background(), 5, time.Millisecond, func(context.Context) error {
+		if calls++; calls < 3 {
+			return boom
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success on 3rd call, got err=%v calls=%d", err, calls)
+	}
+
+	calls = 0
+	err = Retry(context.Background(), 3, time.Millisecond, func(context.Context) error { calls++; return boom })
+	if !errors.Is(err, boom) || calls != 3 {
+		t.Fatalf("expected last error after 3 calls, got err=%v calls=%d", err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls = 0
+	err = Retry(ctx, 10, time.Hour, func(context.Context) error { calls++; return boom })
+	if !errors.Is(err, boom) || calls != 1 {
+		t.Fatalf("expected cancellation to stop retries, got err=%v calls=%d", err, calls)
+	}
+}
+
+func TestPoolOptions_Apply(t *testing.T) {
+	pcfg, err := pgxpool.ParseConfig("postgres://u:p@localhost/db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaultMax := pcfg.MaxConns
+	PoolOptions{MinConns: 2, MaxConnLifetime: 30 * time.Minute}.Apply(pcfg)
+	if pcfg.MaxConns != defaultMax || pcfg.MinConns != 2 || pcfg.MaxConnLifetime != 30*time.Minute {
+		t.Fatalf("unexpected pool config: max=%d min=%d lifetime=%s", pcfg.MaxConns, pcfg.MinConns, pcfg.MaxConnLifetime)
+	}
+	if _, ok := pcfg.ConnConfig.RuntimeParams["statement_timeout"]; ok {
+		t.Fatal("statement_timeout set without StatementTimeout")
+	}
+	PoolOptions{StatementTimeout: 90 * time.Second}.Apply(pcfg)
+	if got := pcfg.ConnConfig.RuntimeParams["statement_timeout"]; got != "90000" {
+		t.Fatalf("statement_timeout = %q, want 90000 (ms)", got)
+	}
+}
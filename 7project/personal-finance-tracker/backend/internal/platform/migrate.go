@@ -3,34 +3,47 @@
 package platform
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// migration is a .sql file found in the migrations directory.
+type migration struct {
+	name string // file name, the key in schema_migrations
+	path string
+	sql  []byte
+	sum  string // hex SHA-256 of the contents with CRLF line endings normalised
+}
+
 // RunMigrations executes all .sql migration files in the provided directory in
 // lexicographical order. Applied migrations are tracked in the schema_migrations
-// table to ensure idempotency across restarts and deployments.
+// table to ensure idempotency across restarts and deployments. A Postgres advisory lock
+// serialises concurrent runs, so replicas starting together wait for the first one to
+// migrate instead of racing it.
+//
+// The checksum of each applied file is recorded, and nothing is applied when the
+// directory has drifted from the database:
+// - an applied file whose contents changed since it ran
+// - a pending file that sorts before the latest applied one (renumber it)
+//
+// Fix forward with a new migration rather than editing an applied one; to accept an
+// intentional edit, set its checksum to NULL and it is recorded anew. Files applied
+// before checksums were recorded are adopted as they are. Applied files missing from
+// the directory (an older build) are only logged.
 func RunMigrations(ctx context.Context, pool *pgxpool.Pool, dir string) error {
-	entries := []string{}
-	// Discover .sql files recursively under dir, skipping subdirectories.
-	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if d.IsDir() {
-			return nil
-		}
-		if filepath.Ext(path) == ".sql" {
-			entries = append(entries, path)
-		}
-		return nil
-	})
+	files, err := readMigrations(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// Absence of a migrations directory is treated as a no-op.
@@ -38,54 +51,198 @@ func RunMigrations(ctx context.Context, pool *pgxpool.Pool, dir string) error {
 		}
 		return err
 	}
-	// Sort files to enforce deterministic execution order (e.g., 001_, 002_, ...).
-	sort.Strings(entries)
+
+	// Hold the migration lock on one connection for the whole run; every statement
+	// below runs on it.
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+	// Migrations may rewrite large tables, and waiting for the lock may take a while:
+	// lift DB_STATEMENT_TIMEOUT for this session, restoring it before the connection
+	// returns to the pool.
+	if _, err := conn.Exec(ctx, `SET statement_timeout = 0`); err != nil {
+		return fmt.Errorf("lift statement timeout: %w", err)
+	}
+	defer restoreStatementTimeout(conn)
+	if err := lockMigrations(ctx, conn); err != nil {
+		return err
+	}
+	defer unlockMigrations(conn)
 
 	// Ensure the migrations tracking table exists (safe to run multiple times).
-	_, err = pool.Exec(ctx, `
+	_, err = conn.Exec(ctx, `
 		CREATE TABLE IF NOT EXISTS schema_migrations(
 			filename TEXT PRIMARY KEY,
 			applied_at TIMESTAMPTZ DEFAULT now()
 		);
+		ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS checksum TEXT;
 	`)
 	if err != nil {
 		return fmt.Errorf("create schema_migrations: %w", err)
 	}
 
-	for _, f := range entries {
-		// Skip files already recorded as applied.
-		var exists bool
-		if err := pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE filename=$1)`, filepath.Base(f)).Scan(&exists); err != nil {
-			return fmt.Errorf("check migration %s: %w", f, err)
-		}
-		if exists {
-			continue
+	applied := map[string]string{}
+	rows, err := conn.Query(ctx, `SELECT filename, COALESCE(checksum, '') FROM schema_migrations`)
+	if err != nil {
+		return fmt.Errorf("read schema_migrations: %w", err)
+	}
+	for rows.Next() {
+		var name, sum string
+		if err := rows.Scan(&name, &sum); err != nil {
+			rows.Close()
+			return fmt.Errorf("read schema_migrations: %w", err)
 		}
+		applied[name] = sum
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read schema_migrations: %w", err)
+	}
 
-		// Read the migration file contents.
-		b, err := os.ReadFile(f)
-		if err != nil {
-			return fmt.Errorf("read %s: %w", f, err)
+	pending, adopt, err := planMigrations(files, applied)
+	if err != nil {
+		return err
+	}
+	for _, m := range adopt {
+		if _, err := conn.Exec(ctx, `UPDATE schema_migrations SET checksum=$2 WHERE filename=$1`, m.name, m.sum); err != nil {
+			return fmt.Errorf("record checksum of %s: %w", m.name, err)
 		}
+	}
 
+	for _, m := range pending {
 		// Execute the migration within a transaction and record it upon success.
-		tx, err := pool.Begin(ctx)
+		tx, err := conn.Begin(ctx)
 		if err != nil {
 			return fmt.Errorf("begin: %w", err)
 		}
-		_, err = tx.Exec(ctx, string(b))
+		_, err = tx.Exec(ctx, string(m.sql))
 		if err != nil {
 			_ = tx.Rollback(ctx)
-			return fmt.Errorf("exec %s: %w", f, err)
+			return fmt.Errorf("exec %s: %w", m.path, err)
 		}
-		_, err = tx.Exec(ctx, `INSERT INTO schema_migrations(filename) VALUES($1)`, filepath.Base(f))
+		_, err = tx.Exec(ctx, `INSERT INTO schema_migrations(filename, checksum) VALUES($1, $2)`, m.name, m.sum)
 		if err != nil {
 			_ = tx.Rollback(ctx)
-			return fmt.Errorf("record %s: %w", f, err)
+			return fmt.Errorf("record %s: %w", m.path, err)
 		}
 		if err := tx.Commit(ctx); err != nil {
-			return fmt.Errorf("commit %s: %w", f, err)
+			return fmt.Errorf("commit %s: %w", m.path, err)
 		}
 	}
 	return nil
 }
+
+// migrationLockKey is the Postgres advisory lock serialising RunMigrations across
+// instances; any constant works as long as nothing else uses it.
+const migrationLockKey int64 = 0x7066745f6d6967 // "pft_mig"
+
+// lockMigrations takes the session-level migration lock on conn, waiting for another
+// instance to finish first. That instance's work is then seen as applied, so a waiting
+// instance goes on to apply nothing and boots. Waiting ends with an error when ctx does.
+func lockMigrations(ctx context.Context, conn *pgxpool.Conn) error {
+	var ok bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, migrationLockKey).Scan(&ok); err != nil {
+		return fmt.Errorf("migration lock: %w", err)
+	}
+	if ok {
+		return nil
+	}
+	slog.Info("another instance is running migrations; waiting for it to finish")
+	start := time.Now()
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("wait for migration lock held by another instance: %w", err)
+	}
+	slog.Info("migration lock acquired", "waited", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// restoreStatementTimeout resets the session's statement_timeout to the connection's
+// default, closing the connection when that fails so it cannot be reused without one.
+func restoreStatementTimeout(conn *pgxpool.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := conn.Exec(ctx, `RESET statement_timeout`); err != nil {
+		slog.Warn("restore statement timeout", "err", err)
+		_ = conn.Conn().Close(ctx)
+	}
+}
+
+// unlockMigrations releases the migration lock. Should that fail the connection is
+// closed instead, which releases the lock too, rather than returning it to the pool
+// still locked.
+func unlockMigrations(conn *pgxpool.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+		slog.Warn("release migration lock", "err", err)
+		_ = conn.Conn().Close(ctx)
+	}
+}
+
+// readMigrations reads the .sql files under dir, recursively, sorted by file name
+// to enforce deterministic execution order (e.g., 001_, 002_, ...).
+func readMigrations(dir string) ([]migration, error) {
+	var files []migration
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".sql" {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", path, err)
+		}
+		sum := sha256.Sum256(bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n")))
+		files = append(files, migration{name: filepath.Base(path), path: path, sql: b, sum: hex.EncodeToString(sum[:])})
+		return nil
+	})
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+	return files, err
+}
+
+// planMigrations compares the files with the applied migrations (name to recorded
+// checksum, "" when none was recorded) and returns the files to apply and those whose
+// checksum is to be recorded, or an error listing every changed or out-of-order file.
+func planMigrations(files []migration, applied map[string]string) (pending, adopt []migration, err error) {
+	latest := ""
+	for name := range applied {
+		latest = max(latest, name)
+	}
+	seen := map[string]bool{}
+	var problems []string
+	for _, m := range files {
+		seen[m.name] = true
+		sum, ok := applied[m.name]
+		switch {
+		case !ok && m.name < latest:
+			problems = append(problems, fmt.Sprintf("%s is not applied but sorts before the applied %s; renumber it", m.name, latest))
+		case !ok:
+			pending = append(pending, m)
+		case sum == "":
+			adopt = append(adopt, m)
+		case sum != m.sum:
+			problems = append(problems, fmt.Sprintf("%s changed after it was applied (checksum %.12s, file %.12s)", m.name, sum, m.sum))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, nil, fmt.Errorf("migrations out of sync with schema_migrations:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	for name := range applied {
+		if !seen[name] {
+			slog.Warn("applied migration missing from the migrations directory", "file", name)
+		}
+	}
+	return pending, adopt, nil
+}
+
+// MigrationVersion returns the most recently applied migration file (e.g. "023_jobs.sql"),
+// or "" when none has been applied yet.
+func MigrationVersion(ctx context.Context, pool *pgxpool.Pool) (string, error) {
+	var name string
+	err := pool.QueryRow(ctx, `SELECT COALESCE(MAX(filename), '') FROM schema_migrations`).Scan(&name)
+	return name, err
+}
@@ -0,0 +1,46 @@
+// backend/internal/platform/migrate_test.go
+//
+// Purpose:
+//   Verify planMigrations applies only new files, adopts applied files that have
+//   no recorded checksum, and refuses changed or out-of-order migrations.
+
+package platform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlanMigrations(t *testing.T) {
+	files := []migration{
+		{name: "001_init.sql", sum: "aaa"},
+		{name: "002_more.sql", sum: "bbb"},
+		{name: "003_new.sql", sum: "ccc"},
+	}
+
+	pending, adopt, err := planMigrations(files, map[string]string{"001_init.sql": "aaa", "002_more.sql": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].name != "003_new.sql" {
+		t.Fatalf("pending = %+v, want 003_new.sql", pending)
+	}
+	if len(adopt) != 1 || adopt[0].name != "002_more.sql" {
+		t.Fatalf("adopt = %+v, want 002_more.sql", adopt)
+	}
+
+	// A missing applied file (older build) is not an error.
+	if _, _, err := planMigrations(files[:1], map[string]string{"001_init.sql": "aaa", "002_more.sql": "bbb"}); err != nil {
+		t.Fatalf("missing applied file: %v", err)
+	}
+
+	_, _, err = planMigrations(files, map[string]string{"001_init.sql": "xxx", "002_more.sql": "bbb"})
+	if err == nil || !strings.Contains(err.Error(), "001_init.sql changed") {
+		t.Fatalf("changed file: err = %v", err)
+	}
+
+	_, _, err = planMigrations(files, map[string]string{"001_init.sql": "aaa", "003_new.sql": "ccc"})
+	if err == nil || !strings.Contains(err.Error(), "002_more.sql is not applied") {
+		t.Fatalf("out of order: err = %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+// backend/internal/platform/version.go
+
+package platform
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+// Build metadata, stamped at link time:
+//
+//	go build -ldflags "-X pft/internal/platform.Version=1.4.0 -X pft/internal/platform.Commit=$(git rev-parse --short HEAD) \
+//	  -X pft/internal/platform.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// When Commit or BuildDate is not stamped it falls back to the VCS revision and commit
+// time recorded by the Go toolchain. Version is a semantic version ("1.4.0") or "dev".
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+// StartedAt is when the process started; used to report uptime.
+var StartedAt = time.Now()
+
+func init() {
+	bi, _ := debug.ReadBuildInfo()
+	if bi == nil {
+		bi = &debug.BuildInfo{}
+	}
+	if Commit == "" {
+		Commit = "unknown"
+		for _, s := range bi.Settings {
+			if s.Key == "vcs.revision" && s.Value != "" {
+				Commit = s.Value
+				if len(Commit) > 12 {
+					Commit = Commit[:12]
+				}
+			}
+		}
+	}
+	if BuildDate == "" {
+		BuildDate = "unknown"
+		for _, s := range bi.Settings {
+			if s.Key == "vcs.time" && s.Value != "" {
+				BuildDate = s.Value
+			}
+		}
+	}
+}
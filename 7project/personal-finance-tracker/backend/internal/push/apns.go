@@ -0,0 +1,138 @@
+// backend/internal/push/apns.go
+
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"pft/internal/jobs"
+)
+
+// APNs sends notifications through the Apple Push Notification service, using
+// token-based authentication with a .p8 signing key.
+// - KeyID/TeamID: identify the key in the Apple developer account
+// - Topic: the app's bundle ID
+// - Sandbox: use the development environment (apps built for debugging)
+type APNs struct {
+	KeyID   string
+	TeamID  string
+	Topic   string
+	Key     *ecdsa.PrivateKey
+	Sandbox bool
+	BaseURL string       // overrides the production/sandbox endpoint
+	Client  *http.Client // defaults to a client with a 15s timeout (HTTP/2 is negotiated)
+
+	mu     sync.Mutex
+	bearer string
+	issued time.Time
+}
+
+// LoadAPNsKey reads a .p8 signing key downloaded from the Apple developer account.
+func LoadAPNsKey(path string) (*ecdsa.PrivateKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("apns: %w", err)
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(b)
+	if err != nil {
+		return nil, fmt.Errorf("apns: %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// Name implements Sender.
+func (a *APNs) Name() string { return "apns" }
+
+// Send implements Sender. Data entries are added to the payload next to "aps".
+func (a *APNs) Send(ctx context.Context, token string, m *Message) error {
+	bearer, err := a.token()
+	if err != nil {
+		return err
+	}
+	payload := map[string]any{}
+	for k, v := range m.Data {
+		payload[k] = v
+	}
+	payload["aps"] = map[string]any{
+		"alert": map[string]string{"title": m.Title, "body": m.Body},
+		"sound": "default",
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+
+	base := a.BaseURL
+	switch {
+	case base != "":
+	case a.Sandbox:
+		base = "https://api.sandbox.push.apple.com"
+	default:
+		base = "https://api.push.apple.com"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(base, "/")+"/3/device/"+token, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+bearer)
+	req.Header.Set("Apns-Topic", a.Topic)
+	req.Header.Set("Apns-Push-Type", "alert")
+	req.Header.Set("Apns-Priority", "10")
+	client := a.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	reply, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+	var r struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.Unmarshal(reply, &r)
+	switch {
+	case resp.StatusCode == http.StatusGone, r.Reason == "BadDeviceToken", r.Reason == "Unregistered", r.Reason == "DeviceTokenNotForTopic":
+		return ErrInvalidToken
+	case r.Reason == "ExpiredProviderToken":
+		a.mu.Lock()
+		a.bearer = ""
+		a.mu.Unlock()
+	}
+	return statusError(resp.StatusCode, reply)
+}
+
+// token returns the provider token, signing a new one every 50 minutes (Apple
+// rejects tokens older than an hour and throttles refreshes more often than 20 minutes).
+func (a *APNs) token() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.bearer != "" && time.Since(a.issued) < 50*time.Minute {
+		return a.bearer, nil
+	}
+	now := time.Now()
+	t := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"iss": a.TeamID, "iat": now.Unix()})
+	t.Header["kid"] = a.KeyID
+	s, err := t.SignedString(a.Key)
+	if err != nil {
+		return "", jobs.Permanent(err)
+	}
+	a.bearer, a.issued = s, now
+	return s, nil
+}
@@ -0,0 +1,186 @@
+// backend/internal/push/fcm.go
+
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"pft/internal/jobs"
+)
+
+// FCM sends notifications through the Firebase Cloud Messaging HTTP v1 API,
+// authenticating as a Google service account.
+type FCM struct {
+	ProjectID   string
+	ClientEmail string
+	PrivateKey  *rsa.PrivateKey
+	TokenURL    string       // OAuth token endpoint of the service account
+	BaseURL     string       // defaults to https://fcm.googleapis.com
+	Client      *http.Client // defaults to a client with a 15s timeout
+
+	mu      sync.Mutex
+	access  string
+	expires time.Time
+}
+
+// LoadFCM reads a service account key file as downloaded from the Firebase console.
+func LoadFCM(path string) (*FCM, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("fcm: %w", err)
+	}
+	var sa struct {
+		ProjectID   string `json:"project_id"`
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(b, &sa); err != nil {
+		return nil, fmt.Errorf("fcm: %s: %w", path, err)
+	}
+	if sa.ProjectID == "" || sa.ClientEmail == "" {
+		return nil, fmt.Errorf("fcm: %s is not a service account key", path)
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(sa.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("fcm: %s: %w", path, err)
+	}
+	if sa.TokenURI == "" {
+		sa.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	return &FCM{ProjectID: sa.ProjectID, ClientEmail: sa.ClientEmail, PrivateKey: key, TokenURL: sa.TokenURI}, nil
+}
+
+// Name implements Sender.
+func (f *FCM) Name() string { return "fcm" }
+
+// Send implements Sender.
+func (f *FCM) Send(ctx context.Context, token string, m *Message) error {
+	access, err := f.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	var body struct {
+		Message struct {
+			Token        string            `json:"token"`
+			Notification map[string]string `json:"notification"`
+			Data         map[string]string `json:"data,omitempty"`
+		} `json:"message"`
+	}
+	body.Message.Token = token
+	body.Message.Notification = map[string]string{"title": m.Title, "body": m.Body}
+	body.Message.Data = m.Data
+	b, err := json.Marshal(body)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+
+	base := f.BaseURL
+	if base == "" {
+		base = "https://fcm.googleapis.com"
+	}
+	u := strings.TrimRight(base, "/") + "/v1/projects/" + url.PathEscape(f.ProjectID) + "/messages:send"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+access)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := f.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	reply, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+	switch {
+	case resp.StatusCode == http.StatusNotFound || bytes.Contains(reply, []byte("UNREGISTERED")):
+		return ErrInvalidToken
+	case resp.StatusCode == http.StatusUnauthorized:
+		f.mu.Lock()
+		f.access = ""
+		f.mu.Unlock()
+	}
+	return statusError(resp.StatusCode, reply)
+}
+
+// accessToken returns a cached OAuth access token, exchanging a signed assertion
+// for a new one shortly before the current one expires.
+func (f *FCM) accessToken(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.access != "" && time.Until(f.expires) > time.Minute {
+		return f.access, nil
+	}
+	now := time.Now()
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   f.ClientEmail,
+		"scope": "https://www.googleapis.com/auth/firebase.messaging",
+		"aud":   f.TokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(f.PrivateKey)
+	if err != nil {
+		return "", jobs.Permanent(err)
+	}
+	form := url.Values{"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"}, "assertion": {assertion}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := f.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		reply, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return "", fmt.Errorf("fcm token: %w", statusError(resp.StatusCode, reply))
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil || tok.AccessToken == "" {
+		return "", errors.New("fcm token: malformed response")
+	}
+	f.access, f.expires = tok.AccessToken, now.Add(time.Duration(tok.ExpiresIn)*time.Second)
+	return f.access, nil
+}
+
+func (f *FCM) client() *http.Client {
+	if f.Client == nil {
+		return &http.Client{Timeout: 15 * time.Second}
+	}
+	return f.Client
+}
+
+// statusError describes a failed API response: 429 and 5xx are retried, 401 and 403
+// too (credentials may be refreshed), other client errors are permanent.
+func statusError(status int, reply []byte) error {
+	err := fmt.Errorf("unexpected status %d: %s", status, strings.TrimSpace(string(reply)))
+	switch {
+	case status == http.StatusTooManyRequests, status == http.StatusUnauthorized, status == http.StatusForbidden, status >= 500:
+		return err
+	case status >= 400:
+		return jobs.Permanent(err)
+	}
+	return err
+}
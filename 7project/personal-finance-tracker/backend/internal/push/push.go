@@ -0,0 +1,105 @@
+// backend/internal/push/push.go
+
+// Package push sends mobile push notifications through Firebase Cloud Messaging
+// (Android, web) and the Apple Push Notification service (iOS). Channel delivers
+// alerts to every device a user registered, and forgets devices whose token the
+// platform rejects.
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"pft/internal/alerts"
+	"pft/internal/repo"
+)
+
+// ErrInvalidToken reports a registration token the platform no longer accepts
+// (app uninstalled, token rotated); the device should be forgotten.
+var ErrInvalidToken = errors.New("push: invalid registration token")
+
+// Message is one notification.
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Sender delivers messages to devices of one platform.
+type Sender interface {
+	// Name identifies the sender in logs.
+	Name() string
+	// Send delivers m to the device with token, returning ErrInvalidToken (possibly
+	// wrapped) when the token is no longer valid.
+	Send(ctx context.Context, token string, m *Message) error
+}
+
+// Devices lists and forgets push devices; implemented by repo.DeviceRepo.
+type Devices interface {
+	List(ctx context.Context, userID int64) ([]repo.Device, error)
+	DeleteToken(ctx context.Context, userID int64, token string) error
+}
+
+// Channel is the alerts.Channel for push notifications.
+// Senders maps a device platform ("fcm", "apns") to its sender.
+type Channel struct {
+	Devices Devices
+	Senders map[string]Sender
+}
+
+// Name implements alerts.Channel.
+func (c *Channel) Name() string { return alerts.ChannelPush }
+
+// Deliver implements alerts.Channel. It fails if any device could not be reached,
+// so the retry may notify the others twice.
+func (c *Channel) Deliver(ctx context.Context, a *alerts.Alert) error {
+	devices, err := c.Devices.List(ctx, a.UserID)
+	if err != nil {
+		return err
+	}
+	data := map[string]string{"event": a.Event}
+	for k, v := range a.Data {
+		data[k] = v
+	}
+	m := &Message{Title: a.Title, Body: a.Body, Data: data}
+	var errs []error
+	for _, d := range devices {
+		s, ok := c.Senders[d.Platform]
+		if !ok {
+			continue
+		}
+		err := s.Send(ctx, d.Token, m)
+		switch {
+		case errors.Is(err, ErrInvalidToken):
+			slog.Info("forgetting push device", "device_id", d.ID, "platform", d.Platform)
+			if err := c.Devices.DeleteToken(ctx, a.UserID, d.Token); err != nil {
+				errs = append(errs, err)
+			}
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s device %d: %w", s.Name(), d.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Log writes notifications to the log instead of sending them; for development.
+type Log struct{}
+
+// Name implements Sender.
+func (Log) Name() string { return "log" }
+
+// Send implements Sender.
+func (Log) Send(_ context.Context, token string, m *Message) error {
+	slog.Info("push not sent (no credentials configured)", "token", redactToken(token),
+		"title", m.Title, "body", m.Body)
+	return nil
+}
+
+func redactToken(t string) string {
+	if len(t) <= 8 {
+		return "…"
+	}
+	return t[:8] + "…"
+}
@@ -0,0 +1,174 @@
+// backend/internal/push/push_test.go
+//
+// Purpose:
+//   Verify that alerts reach every registered device through its platform's
+//   sender, that rejected tokens are forgotten, and that the FCM and APNs clients
+//   authenticate and map platform replies as documented.
+
+package push
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"pft/internal/alerts"
+	"pft/internal/repo"
+)
+
+type fakeDevices struct {
+	list    []repo.Device
+	deleted []string
+}
+
+func (f *fakeDevices) List(context.Context, int64) ([]repo.Device, error) { return f.list, nil }
+func (f *fakeDevices) DeleteToken(_ context.Context, _ int64, token string) error {
+	f.deleted = append(f.deleted, token)
+	return nil
+}
+
+type fakeSender struct {
+	invalid map[string]bool
+	sent    map[string]*Message
+}
+
+func (s *fakeSender) Name() string { return "fake" }
+func (s *fakeSender) Send(_ context.Context, token string, m *Message) error {
+	if s.invalid[token] {
+		return ErrInvalidToken
+	}
+	s.sent[token] = m
+	return nil
+}
+
+func TestChannel_Deliver(t *testing.T) {
+	devices := &fakeDevices{list: []repo.Device{
+		{ID: 1, Platform: "fcm", Token: "android-1"},
+		{ID: 2, Platform: "apns", Token: "iphone-stale"},
+		{ID: 3, Platform: "apns", Token: "iphone-2"},
+	}}
+	fcm := &fakeSender{sent: map[string]*Message{}}
+	apns := &fakeSender{sent: map[string]*Message{}, invalid: map[string]bool{"iphone-stale": true}}
+	ch := &Channel{Devices: devices, Senders: map[string]Sender{"fcm": fcm, "apns": apns}}
+
+	err := ch.Deliver(context.Background(), &alerts.Alert{UserID: 7, Event: alerts.EventBill, Title: "Rent",
+		Body: "due", Data: map[string]string{"bill_id": "5"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := fcm.sent["android-1"]; m == nil || m.Data["event"] != alerts.EventBill || m.Data["bill_id"] != "5" {
+		t.Fatalf("fcm message %+v", m)
+	}
+	if apns.sent["iphone-2"] == nil {
+		t.Fatal("second iPhone not notified")
+	}
+	if len(devices.deleted) != 1 || devices.deleted[0] != "iphone-stale" {
+		t.Fatalf("deleted %v, want the stale token", devices.deleted)
+	}
+}
+
+func TestFCM_Send(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	tokenCalls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenCalls++
+			_ = r.ParseForm()
+			claims := jwt.MapClaims{}
+			if _, err := jwt.ParseWithClaims(r.Form.Get("assertion"), claims, func(*jwt.Token) (any, error) {
+				return &key.PublicKey, nil
+			}); err != nil || claims["iss"] != "svc@proj.iam.gserviceaccount.com" {
+				http.Error(w, "bad assertion", http.StatusBadRequest)
+				return
+			}
+			_, _ = io.WriteString(w, `{"access_token":"ya29.x","expires_in":3600}`)
+		case "/v1/projects/proj/messages:send":
+			var body struct {
+				Message struct {
+					Token string `json:"token"`
+				} `json:"message"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if r.Header.Get("Authorization") != "Bearer ya29.x" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if body.Message.Token == "gone" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = io.WriteString(w, `{"error":{"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`)
+				return
+			}
+			_, _ = io.WriteString(w, `{"name":"projects/proj/messages/1"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	f := &FCM{ProjectID: "proj", ClientEmail: "svc@proj.iam.gserviceaccount.com", PrivateKey: key,
+		TokenURL: srv.URL + "/token", BaseURL: srv.URL}
+	m := &Message{Title: "t", Body: "b"}
+	if err := f.Send(context.Background(), "device-token", m); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Send(context.Background(), "gone", m); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("unregistered token: %v", err)
+	}
+	if tokenCalls != 1 {
+		t.Fatalf("access token fetched %d times, want it cached", tokenCalls)
+	}
+}
+
+func TestAPNs_Send(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tok, err := jwt.Parse(strings.TrimPrefix(r.Header.Get("Authorization"), "bearer "), func(*jwt.Token) (any, error) {
+			return &key.PublicKey, nil
+		})
+		if err != nil || tok.Header["kid"] != "KEY123" || r.Header.Get("Apns-Topic") != "com.example.pft" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, `{"reason":"InvalidProviderToken"}`)
+			return
+		}
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		if payload["aps"] == nil || payload["event"] != "anomaly" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"reason":"BadPayload"}`)
+			return
+		}
+		switch strings.TrimPrefix(r.URL.Path, "/3/device/") {
+		case "gone":
+			w.WriteHeader(http.StatusGone)
+			_, _ = io.WriteString(w, `{"reason":"Unregistered"}`)
+		case "busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = io.WriteString(w, `{"reason":"TooManyRequests"}`)
+		}
+	}))
+	defer srv.Close()
+
+	a := &APNs{KeyID: "KEY123", TeamID: "TEAM", Topic: "com.example.pft", Key: key, BaseURL: srv.URL}
+	m := &Message{Title: "t", Body: "b", Data: map[string]string{"event": "anomaly"}}
+	if err := a.Send(context.Background(), "ok", m); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Send(context.Background(), "gone", m); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("unregistered token: %v", err)
+	}
+	if err := a.Send(context.Background(), "busy", m); err == nil || errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("throttled send: %v", err)
+	}
+}
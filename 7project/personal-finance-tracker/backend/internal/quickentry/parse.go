@@ -0,0 +1,209 @@
+// backend/internal/quickentry/parse.go
+
+// Package quickentry reads a transaction from a line of free text such as
+// "lunch 12.50 yesterday, cash" for quick-entry fields and chat bots. Parsing is
+// heuristic and knows nothing about the user; callers match the remaining words
+// against the user's categories and accounts (see Draft.Mentions and Draft.Take).
+package quickentry
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Draft is what Parse read from a line of text.
+// - Type: "income" when the amount was written with a "+", else "" (unknown)
+// - Date: the day named by the text, today when none was
+// - Tag: category name after "#", if any
+type Draft struct {
+	Amount float64
+	Type   string
+	Date   time.Time
+	Tag    string
+	words  []string
+}
+
+// currencySigns may surround an amount ("€3.50", "3.50$").
+const currencySigns = "€$£¥₹"
+
+// weekdays names days by their English name. Abbreviations are left out: "sun" and
+// "sat" are more often part of a description than a date.
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// connectors introduce an account and go with it when it is taken: "paid with card".
+var connectors = map[string]bool{"with": true, "by": true, "via": true, "from": true, "using": true}
+
+// Parse reads a transaction from text, relative to today:
+//   - the date: "today", "yesterday", "3 days ago", "a week ago", a weekday ("friday",
+//     "last friday"; the latest one not after today, or before today with "last") or
+//     YYYY-MM-DD, optionally after "on"
+//   - the amount: the last token that is one, with a decimal point or comma, optional
+//     currency sign and a "+" for income
+//   - a "#tag" naming the category
+//
+// The remaining words are the description. It reports false when there is no amount.
+func Parse(text string, today time.Time) (Draft, bool) {
+	var words []string
+	for _, f := range strings.Fields(text) {
+		if f = strings.Trim(f, ",;"); f != "" {
+			words = append(words, f)
+		}
+	}
+	d := Draft{Date: today}
+	words = d.takeDate(words, today)
+
+	amountAt := -1
+	for i := len(words) - 1; i >= 0; i-- {
+		if v, income, ok := parseAmount(words[i]); ok {
+			amountAt, d.Amount = i, v
+			if income {
+				d.Type = "income"
+			}
+			break
+		}
+	}
+	if amountAt < 0 {
+		return Draft{}, false
+	}
+	for i, w := range words {
+		switch {
+		case i == amountAt:
+		case len(w) > 1 && w[0] == '#' && d.Tag == "":
+			d.Tag = w[1:]
+		default:
+			d.words = append(d.words, w)
+		}
+	}
+	return d, true
+}
+
+// takeDate sets d.Date from the first date phrase in words and returns words without it.
+func (d *Draft) takeDate(words []string, today time.Time) []string {
+	lower := func(i int) string {
+		if i < 0 || i >= len(words) {
+			return ""
+		}
+		return strings.ToLower(words[i])
+	}
+	for i := range words {
+		start, end := i, i+1
+		w := lower(i)
+		wd, isWeekday := weekdays[w]
+		switch {
+		case w == "today":
+			d.Date = today
+		case w == "yesterday":
+			d.Date = today.AddDate(0, 0, -1)
+		case isWeekday:
+			back := (int(today.Weekday()) - int(wd) + 7) % 7
+			if lower(i-1) == "last" {
+				start--
+				if back == 0 {
+					back = 7
+				}
+			}
+			d.Date = today.AddDate(0, 0, -back)
+		case lower(i+2) == "ago" && (lower(i+1) == "days" || lower(i+1) == "weeks" || lower(i+1) == "day" || lower(i+1) == "week"):
+			n, err := strconv.Atoi(w)
+			if w == "a" {
+				n, err = 1, nil
+			}
+			if err != nil || n < 0 || n > 3660 {
+				continue
+			}
+			if strings.HasPrefix(lower(i+1), "week") {
+				n *= 7
+			}
+			d.Date, end = today.AddDate(0, 0, -n), i+3
+		default:
+			t, err := time.Parse("2006-01-02", w)
+			if err != nil {
+				continue
+			}
+			d.Date = t
+		}
+		if lower(start-1) == "on" {
+			start--
+		}
+		return append(words[:start:start], words[end:]...)
+	}
+	return words
+}
+
+// parseAmount parses a positive amount with at most two decimals; a leading "+"
+// marks income.
+func parseAmount(s string) (v float64, income bool, ok bool) {
+	if strings.HasPrefix(s, "+") {
+		s, income = s[1:], true
+	}
+	s = strings.Trim(s, currencySigns)
+	if i := strings.LastIndexByte(s, ','); i >= 0 && !strings.Contains(s, ".") && len(s)-i-1 <= 2 {
+		s = s[:i] + "." + s[i+1:]
+	}
+	if s == "" || strings.Trim(s, "0123456789.") != "" {
+		return 0, false, false
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 && len(s)-i-1 > 2 {
+		return 0, false, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 || math.IsInf(v, 0) {
+		return 0, false, false
+	}
+	return v, income, true
+}
+
+// Description returns the words left once the date, amount, tag and anything taken
+// by Take are removed.
+func (d *Draft) Description() string { return strings.Join(d.words, " ") }
+
+// Mentions returns the index of the longest of names that appears in the description
+// as whole words, ignoring case, so "Public transport" wins over "Transport"; -1 when
+// none does.
+func (d *Draft) Mentions(names []string) int {
+	i, _, _ := d.find(names)
+	return i
+}
+
+// Take is Mentions, but also removes the name found from the description, with a
+// connector such as "with" or "via" before it. Used for accounts: "lunch 12 cash".
+func (d *Draft) Take(names []string) int {
+	i, at, n := d.find(names)
+	if i < 0 {
+		return -1
+	}
+	start := at
+	if start > 0 && connectors[strings.ToLower(d.words[start-1])] {
+		start--
+	}
+	d.words = append(d.words[:start:start], d.words[at+n:]...)
+	return i
+}
+
+// find returns the index of the longest name found and where its words start and
+// how many there are.
+func (d *Draft) find(names []string) (best, at, n int) {
+	best = -1
+	for i, name := range names {
+		nw := strings.Fields(name)
+		if len(nw) == 0 || (best >= 0 && len(name) <= len(names[best])) {
+			continue
+		}
+	scan:
+		for s := 0; s+len(nw) <= len(d.words); s++ {
+			for k, w := range nw {
+				if !strings.EqualFold(d.words[s+k], w) {
+					continue scan
+				}
+			}
+			best, at, n = i, s, len(nw)
+			break
+		}
+	}
+	return best, at, n
+}
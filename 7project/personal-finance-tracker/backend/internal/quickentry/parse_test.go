@@ -0,0 +1,55 @@
+// backend/internal/quickentry/parse_test.go
+//
+// Purpose:
+//   Verify that free text is read into an amount, date, tag and description, and
+//   that category and account names are found in (and accounts taken from) what
+//   remains.
+
+package quickentry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	today := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC) // a Friday
+	cases := []struct {
+		in   string
+		want Draft
+		desc string
+		ok   bool
+	}{
+		{"lunch 12.50 yesterday, cash", Draft{Amount: 12.5, Date: today.AddDate(0, 0, -1)}, "lunch cash", true},
+		{"coffee 3,20", Draft{Amount: 3.2, Date: today}, "coffee", true},
+		{"2 coffees €7 on friday", Draft{Amount: 7, Date: today}, "2 coffees", true},
+		{"cinema 24 last friday #Fun", Draft{Amount: 24, Date: today.AddDate(0, 0, -7), Tag: "Fun"}, "cinema", true},
+		{"taxi 18 monday", Draft{Amount: 18, Date: today.AddDate(0, 0, -4)}, "taxi", true},
+		{"groceries 3 days ago 41.10", Draft{Amount: 41.1, Date: today.AddDate(0, 0, -3)}, "groceries", true},
+		{"rent 900 a week ago", Draft{Amount: 900, Date: today.AddDate(0, 0, -7)}, "rent", true},
+		{"salary +3200 2026-09-30", Draft{Amount: 3200, Type: "income", Date: time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)}, "salary", true},
+		{"sun cream 9", Draft{Amount: 9, Date: today}, "sun cream", true},
+		{"lunch yesterday", Draft{}, "", false},
+		{"lunch 3.505", Draft{}, "", false},
+	}
+	for _, c := range cases {
+		got, ok := Parse(c.in, today)
+		desc := got.Description()
+		if ok != c.ok || got.Amount != c.want.Amount || got.Type != c.want.Type || !got.Date.Equal(c.want.Date) || got.Tag != c.want.Tag || desc != c.desc {
+			t.Errorf("Parse(%q) = %+v %q, %v; want %+v %q, %v", c.in, got, desc, ok, c.want, c.desc, c.ok)
+		}
+	}
+}
+
+func TestMentionsAndTake(t *testing.T) {
+	d, _ := Parse("bus ticket 2.90 public transport paid with Credit card", time.Now())
+	if i := d.Mentions([]string{"Transport", "Public transport", "Food"}); i != 1 {
+		t.Errorf("Mentions = %d, want the longest name", i)
+	}
+	if i := d.Take([]string{"Cash", "Credit card"}); i != 1 || d.Description() != "bus ticket public transport paid" {
+		t.Errorf("Take = %d, description %q", i, d.Description())
+	}
+	if i := d.Take([]string{"Cash"}); i != -1 {
+		t.Errorf("Take = %d, want -1", i)
+	}
+}
@@ -0,0 +1,78 @@
+// backend/internal/ratelimit/ratelimit.go
+
+// Package ratelimit counts requests per key in fixed time windows.
+// Memory keeps counters in-process (single instance); Redis shares them across instances.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Result is the outcome of one Allow call.
+// - Remaining: requests left in the current window (0 once the limit is hit)
+// - Reset: time until the current window ends
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Duration
+}
+
+// Limiter counts a request against key and reports whether it fits within limit per window.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error)
+}
+
+// result derives a Result from the request count within the window.
+func result(count, limit int, reset time.Duration) Result {
+	remaining := limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return Result{Allowed: count <= limit, Limit: limit, Remaining: remaining, Reset: reset}
+}
+
+// Memory is an in-process fixed-window Limiter. Expired windows are swept
+// periodically so idle keys do not accumulate.
+type Memory struct {
+	mu        sync.Mutex
+	windows   map[string]*window
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type window struct {
+	count int
+	ends  time.Time
+}
+
+// NewMemory returns an empty in-memory limiter.
+func NewMemory() *Memory {
+	return &Memory{windows: map[string]*window{}, now: time.Now}
+}
+
+// Allow implements Limiter.
+func (m *Memory) Allow(_ context.Context, key string, limit int, d time.Duration) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if now.Sub(m.lastSweep) >= d {
+		for k, w := range m.windows {
+			if !now.Before(w.ends) {
+				delete(m.windows, k)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	w := m.windows[key]
+	if w == nil || !now.Before(w.ends) {
+		w = &window{ends: now.Add(d)}
+		m.windows[key] = w
+	}
+	w.count++
+	return result(w.count, limit, w.ends.Sub(now)), nil
+}
@@ -0,0 +1,41 @@
+// backend/internal/ratelimit/ratelimit_test.go
+//
+// Purpose:
+//   Verify the in-memory fixed-window limiter counts per key and resets with the window.
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemory_FixedWindow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		res, _ := m.Allow(ctx, "user:1", 3, time.Minute)
+		if !res.Allowed || res.Remaining != 3-i {
+			t.Fatalf("request %d: expected allowed with %d remaining, got %+v", i, 3-i, res)
+		}
+	}
+	res, _ := m.Allow(ctx, "user:1", 3, time.Minute)
+	if res.Allowed || res.Remaining != 0 || res.Reset != time.Minute {
+		t.Fatalf("expected 4th request rejected, got %+v", res)
+	}
+	if res, _ := m.Allow(ctx, "user:2", 3, time.Minute); !res.Allowed {
+		t.Fatalf("keys must be counted independently")
+	}
+
+	now = now.Add(time.Minute)
+	if res, _ := m.Allow(ctx, "user:1", 3, time.Minute); !res.Allowed || res.Remaining != 2 {
+		t.Fatalf("expected a fresh window, got %+v", res)
+	}
+	if _, ok := m.windows["user:2"]; ok {
+		t.Fatalf("expected expired window to be swept")
+	}
+}
@@ -0,0 +1,38 @@
+// backend/internal/ratelimit/redis.go
+
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a fixed-window Limiter shared by every API instance using the same Redis.
+// Counters live under Prefix+key and expire with their window.
+type Redis struct {
+	Client redis.UniversalClient
+	Prefix string
+}
+
+// allowScript increments the counter, starts the window on the first hit and
+// returns the count with the remaining TTL in one round trip.
+var allowScript = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+if n == 1 then redis.call('PEXPIRE', KEYS[1], ARGV[1]) end
+return {n, redis.call('PTTL', KEYS[1])}
+`)
+
+// Allow implements Limiter.
+func (r *Redis) Allow(ctx context.Context, key string, limit int, d time.Duration) (Result, error) {
+	vals, err := allowScript.Run(ctx, r.Client, []string{r.Prefix + key}, d.Milliseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	ttl := time.Duration(vals[1]) * time.Millisecond
+	if ttl < 0 {
+		ttl = d
+	}
+	return result(int(vals[0]), limit, ttl), nil
+}
@@ -0,0 +1,85 @@
+// backend/internal/rates/backfill.go
+
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"pft/internal/jobs"
+	"pft/internal/repo"
+)
+
+// BackfillJobKind is the job kind of backfills; its payload is a BackfillRange.
+const BackfillJobKind = "rates.backfill"
+
+// ErrNoHistory is returned when the provider does not publish past rates.
+var ErrNoHistory = errors.New("rates provider has no history")
+
+// History is implemented by providers that publish past rates (Frankfurter, ECB,
+// exchangerate.host). The manual file only knows one day.
+type History interface {
+	// Range returns the rates published for base on each day from from to to, both
+	// included, oldest first. Days without rates (weekends, holidays) are left out.
+	// Providers split long ranges into as many requests as their API needs.
+	Range(ctx context.Context, base string, from, to time.Time) ([]Snapshot, error)
+}
+
+// BackfillRange is the payload of a BackfillJobKind job: days in YYYY-MM-DD, both included.
+type BackfillRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// CanBackfill reports whether the provider publishes past rates.
+func (j *Job) CanBackfill() bool {
+	_, ok := j.Provider.(History)
+	return ok
+}
+
+// Backfill fetches and stores the rates of every published day from from to to, so
+// past transactions convert at the rate of their own date. Days already stored are
+// overwritten. Returns the number of days stored.
+func (j *Job) Backfill(ctx context.Context, from, to time.Time) (int, error) {
+	h, ok := j.Provider.(History)
+	if !ok {
+		return 0, ErrNoHistory
+	}
+	snaps, err := h.Range(ctx, j.Base, from, to)
+	if err != nil {
+		return 0, err
+	}
+	for i, s := range snaps {
+		if err := j.Store.SaveRates(ctx, s.Date, s.Base, s.Rates, j.Provider.Name()); err != nil {
+			return i, err
+		}
+	}
+	return len(snaps), nil
+}
+
+// HandleBackfill is the jobs.HandlerFunc for BackfillJobKind. A failure retries the
+// whole range; days stored before it are simply stored again.
+func (j *Job) HandleBackfill(ctx context.Context, job *repo.Job) error {
+	var p BackfillRange
+	if err := json.Unmarshal(job.Payload, &p); err != nil {
+		return jobs.Permanent(fmt.Errorf("decode payload: %s", job.Payload))
+	}
+	from, err1 := time.Parse("2006-01-02", p.From)
+	to, err2 := time.Parse("2006-01-02", p.To)
+	if err1 != nil || err2 != nil || to.Before(from) {
+		return jobs.Permanent(fmt.Errorf("invalid range %q..%q", p.From, p.To))
+	}
+	days, err := j.Backfill(ctx, from, to)
+	if errors.Is(err, ErrNoHistory) {
+		return jobs.Permanent(err)
+	}
+	if err != nil {
+		return err
+	}
+	slog.Info("rates backfilled", "provider", j.Provider.Name(), "from", p.From, "to", p.To, "days", days)
+	return nil
+}
@@ -0,0 +1,143 @@
+// backend/internal/rates/backfill_test.go
+//
+// Purpose:
+//   Verify providers decode past rates over a range, split long ranges into the
+//   requests their API allows, and that backfills store every day and refuse
+//   providers without history.
+// Method:
+//   Serve canned responses from httptest.Server; store rates in memory.
+
+package rates
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pft/internal/repo"
+)
+
+func day(s string) time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return d
+}
+
+func TestFrankfurterRange(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Query().Get("from") != "EUR" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		start, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "..")
+		_, _ = w.Write([]byte(`{"base":"EUR","rates":{"` + start + `":{"USD":1.1}}}`))
+	}))
+	defer srv.Close()
+
+	got, err := (&Frankfurter{BaseURL: srv.URL}).Range(context.Background(), "EUR", day("2024-01-01"), day("2024-04-09"))
+	if err != nil {
+		t.Fatalf("Range: %v", err)
+	}
+	want := []string{"/2024-01-01..2024-03-30", "/2024-03-31..2024-04-09"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Fatalf("requests = %v, want %v", paths, want)
+	}
+	if len(got) != 2 || !got[0].Date.Equal(day("2024-01-01")) || !got[1].Date.Equal(day("2024-03-31")) || got[1].Rates["USD"] != 1.1 {
+		t.Fatalf("unexpected snapshots: %+v", got)
+	}
+}
+
+func TestECBRange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<Cube>
+		<Cube time="2024-05-10"><Cube currency="USD" rate="1.0773"/></Cube>
+		<Cube time="2024-05-09"><Cube currency="USD" rate="1.0748"/></Cube>
+		<Cube time="2024-05-08"><Cube currency="USD" rate="1.0745"/></Cube>
+	</Cube>
+</gesmes:Envelope>`))
+	}))
+	defer srv.Close()
+
+	got, err := (&ECB{HistoryURL: srv.URL}).Range(context.Background(), "EUR", day("2024-05-08"), day("2024-05-09"))
+	if err != nil {
+		t.Fatalf("Range: %v", err)
+	}
+	if len(got) != 2 || got[0].Date.Format("2006-01-02") != "2024-05-08" || got[1].Rates["USD"] != 1.0748 || got[1].Base != "EUR" {
+		t.Fatalf("unexpected snapshots: %+v", got)
+	}
+}
+
+func TestExchangerateHostRange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/timeframe" || q.Get("source") != "USD" || q.Get("access_key") != "k" ||
+			q.Get("start_date") != "2024-05-09" || q.Get("end_date") != "2024-05-10" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"success":true,"timeframe":true,"source":"USD","quotes":{
+			"2024-05-10":{"USDEUR":0.928},"2024-05-09":{"USDEUR":0.93}}}`))
+	}))
+	defer srv.Close()
+
+	got, err := (&ExchangerateHost{AccessKey: "k", BaseURL: srv.URL}).Range(context.Background(), "USD", day("2024-05-09"), day("2024-05-10"))
+	if err != nil {
+		t.Fatalf("Range: %v", err)
+	}
+	if len(got) != 2 || got[0].Rates["EUR"] != 0.93 || got[1].Rates["EUR"] != 0.928 || got[1].Base != "USD" {
+		t.Fatalf("unexpected snapshots: %+v", got)
+	}
+}
+
+// memRates records saved days.
+type memRates struct{ days []string }
+
+func (m *memRates) SaveRates(_ context.Context, date time.Time, _ string, _ map[string]float64, _ string) error {
+	m.days = append(m.days, date.Format("2006-01-02"))
+	return nil
+}
+
+// fixedHistory publishes one snapshot per day of any range.
+type fixedHistory struct{ *Manual }
+
+func (fixedHistory) Range(_ context.Context, base string, from, to time.Time) ([]Snapshot, error) {
+	var out []Snapshot
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		out = append(out, Snapshot{Date: d, Base: base, Rates: map[string]float64{"USD": 1.1}})
+	}
+	return out, nil
+}
+
+func TestBackfill(t *testing.T) {
+	store := &memRates{}
+	j := &Job{Provider: fixedHistory{&Manual{}}, Store: store, Base: "EUR"}
+	if !j.CanBackfill() {
+		t.Fatal("expected a provider with history to backfill")
+	}
+	n, err := j.Backfill(context.Background(), day("2024-02-28"), day("2024-03-01"))
+	if err != nil || n != 3 || strings.Join(store.days, " ") != "2024-02-28 2024-02-29 2024-03-01" {
+		t.Fatalf("Backfill = %d, %v; stored %v", n, err, store.days)
+	}
+
+	manual := &Job{Provider: &Manual{}, Store: store, Base: "EUR"}
+	if manual.CanBackfill() {
+		t.Error("manual rates have no history")
+	}
+	if _, err := manual.Backfill(context.Background(), day("2024-01-01"), day("2024-01-02")); !errors.Is(err, ErrNoHistory) {
+		t.Errorf("manual Backfill err = %v, want ErrNoHistory", err)
+	}
+
+	for _, payload := range []string{`nope`, `{"from":"2024-03-01","to":"2024-02-01"}`, `{"from":"2024-03-01"}`} {
+		if err := j.HandleBackfill(context.Background(), &repo.Job{Payload: []byte(payload)}); err == nil {
+			t.Errorf("HandleBackfill(%s) succeeded", payload)
+		}
+	}
+	if err := j.HandleBackfill(context.Background(), &repo.Job{Payload: []byte(`{"from":"2024-01-01","to":"2024-01-01"}`)}); err != nil {
+		t.Errorf("HandleBackfill: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+// backend/internal/rates/ecb.go
+
+package rates
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultECBURL is the European Central Bank's daily reference rates feed.
+const DefaultECBURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// DefaultECBHistoryURL is the feed of every reference rate published since 1999.
+const DefaultECBHistoryURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
+
+// ECB fetches the euro reference rates straight from the European Central Bank.
+// The ECB only publishes rates against the euro, so snapshots are always based on
+// EUR whatever base is asked for; repo.RateRepo derives other bases as cross rates.
+type ECB struct {
+	URL        string       // defaults to DefaultECBURL
+	HistoryURL string       // defaults to DefaultECBHistoryURL
+	Client     *http.Client // defaults to a client with a 15s timeout
+}
+
+// Name implements Provider.
+func (e *ECB) Name() string { return "ecb" }
+
+// ecbEnvelope mirrors the gesmes envelope of the feeds:
+// <Cube><Cube time="2024-05-10"><Cube currency="USD" rate="1.0773"/>...</Cube></Cube>
+// The daily feed holds one day, the history feed every day, newest first.
+type ecbEnvelope struct {
+	Days []ecbDay `xml:"Cube>Cube"`
+}
+
+type ecbDay struct {
+	Time  string `xml:"time,attr"`
+	Rates []struct {
+		Currency string `xml:"currency,attr"`
+		Rate     string `xml:"rate,attr"`
+	} `xml:"Cube"`
+}
+
+// snapshot converts a day of the feed.
+func (d *ecbDay) snapshot() (*Snapshot, error) {
+	day, err := time.Parse("2006-01-02", d.Time)
+	if err != nil {
+		return nil, fmt.Errorf("ecb: bad date %q", d.Time)
+	}
+	rates := make(map[string]float64, len(d.Rates))
+	for _, r := range d.Rates {
+		v, err := strconv.ParseFloat(r.Rate, 64)
+		if err != nil {
+			return nil, fmt.Errorf("ecb: bad rate %q for %s", r.Rate, r.Currency)
+		}
+		rates[r.Currency] = v
+	}
+	return &Snapshot{Date: day, Base: "EUR", Rates: rates}, nil
+}
+
+// Latest implements Provider.
+func (e *ECB) Latest(ctx context.Context, _ string) (*Snapshot, error) {
+	u := e.URL
+	if u == "" {
+		u = DefaultECBURL
+	}
+	body, err := e.fetch(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	if len(body.Days) == 0 {
+		return nil, fmt.Errorf("ecb: no rates in feed")
+	}
+	return body.Days[0].snapshot()
+}
+
+// Range implements History. The history feed is fetched whole, whatever the range.
+func (e *ECB) Range(ctx context.Context, _ string, from, to time.Time) ([]Snapshot, error) {
+	u := e.HistoryURL
+	if u == "" {
+		u = DefaultECBHistoryURL
+	}
+	body, err := e.fetch(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	var out []Snapshot
+	for i := range body.Days {
+		s, err := body.Days[i].snapshot()
+		if err != nil {
+			return nil, err
+		}
+		if !s.Date.Before(from) && !s.Date.After(to) {
+			out = append(out, *s)
+		}
+	}
+	sortSnapshots(out)
+	return out, nil
+}
+
+// fetch downloads and decodes a feed.
+func (e *ECB) fetch(ctx context.Context, u string) (*ecbEnvelope, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient(e.Client).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ecb: unexpected status %d", resp.StatusCode)
+	}
+
+	var body ecbEnvelope
+	if err := xml.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("ecb: decode: %w", err)
+	}
+	return &body, nil
+}
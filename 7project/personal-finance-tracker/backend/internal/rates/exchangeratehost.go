@@ -0,0 +1,129 @@
+// backend/internal/rates/exchangeratehost.go
+
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultExchangerateHostURL is the exchangerate.host API.
+const DefaultExchangerateHostURL = "https://api.exchangerate.host"
+
+// ExchangerateHost fetches rates for any base currency from exchangerate.host,
+// which needs an access key (the free plan suffices for a daily refresh).
+type ExchangerateHost struct {
+	AccessKey string
+	BaseURL   string       // defaults to DefaultExchangerateHostURL
+	Client    *http.Client // defaults to a client with a 15s timeout
+}
+
+// Name implements Provider.
+func (x *ExchangerateHost) Name() string { return "exchangerate.host" }
+
+// exchangerateHostResp mirrors the JSON returned by /live. Quotes are keyed by the
+// source and quote currency concatenated ("EURUSD").
+type exchangerateHostResp struct {
+	Success   bool               `json:"success"`
+	Timestamp int64              `json:"timestamp"`
+	Source    string             `json:"source"`
+	Quotes    map[string]float64 `json:"quotes"`
+	Error     struct {
+		Info string `json:"info"`
+	} `json:"error"`
+}
+
+// Latest implements Provider.
+func (x *ExchangerateHost) Latest(ctx context.Context, base string) (*Snapshot, error) {
+	var body exchangerateHostResp
+	if err := x.get(ctx, "/live", url.Values{"source": {base}}, &body); err != nil {
+		return nil, err
+	}
+	d := time.Unix(body.Timestamp, 0).UTC()
+	return &Snapshot{
+		Date:  time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC),
+		Base:  body.Source,
+		Rates: cutQuotes(body.Source, body.Quotes),
+	}, nil
+}
+
+// exchangerateHostRangeResp mirrors the JSON returned by /timeframe; quotes are keyed
+// by day (YYYY-MM-DD), then by pair as in /live.
+type exchangerateHostRangeResp struct {
+	exchangerateHostResp
+	Quotes map[string]map[string]float64 `json:"quotes"`
+}
+
+// exchangerateHostRangeDays is the longest range /timeframe answers.
+const exchangerateHostRangeDays = 365
+
+// Range implements History.
+func (x *ExchangerateHost) Range(ctx context.Context, base string, from, to time.Time) ([]Snapshot, error) {
+	return chunks(from, to, exchangerateHostRangeDays, func(from, to time.Time) ([]Snapshot, error) {
+		q := url.Values{"source": {base}, "start_date": {from.Format("2006-01-02")}, "end_date": {to.Format("2006-01-02")}}
+		var body exchangerateHostRangeResp
+		if err := x.get(ctx, "/timeframe", q, &body); err != nil {
+			return nil, err
+		}
+		out := make([]Snapshot, 0, len(body.Quotes))
+		for day, quotes := range body.Quotes {
+			d, err := time.Parse("2006-01-02", day)
+			if err != nil {
+				return nil, fmt.Errorf("exchangerate.host: bad date %q", day)
+			}
+			out = append(out, Snapshot{Date: d, Base: body.Source, Rates: cutQuotes(body.Source, quotes)})
+		}
+		sortSnapshots(out)
+		return out, nil
+	})
+}
+
+// exchangerateHostStatus reports the success flag and error every response carries.
+type exchangerateHostStatus interface{ status() (bool, string) }
+
+func (r *exchangerateHostResp) status() (bool, string) { return r.Success, r.Error.Info }
+
+// get calls an endpoint with the access key and decodes the response into body.
+func (x *ExchangerateHost) get(ctx context.Context, path string, q url.Values, body exchangerateHostStatus) error {
+	baseURL := x.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultExchangerateHostURL
+	}
+	q.Set("access_key", x.AccessKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+path+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient(x.Client).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("exchangerate.host: unexpected status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(body); err != nil {
+		return fmt.Errorf("exchangerate.host: decode: %w", err)
+	}
+	// Failures such as an invalid key still answer 200.
+	if ok, info := body.status(); !ok {
+		return fmt.Errorf("exchangerate.host: %s", info)
+	}
+	return nil
+}
+
+// cutQuotes strips the source currency from pairs such as "EURUSD".
+func cutQuotes(source string, quotes map[string]float64) map[string]float64 {
+	rates := make(map[string]float64, len(quotes))
+	for pair, v := range quotes {
+		if quote, ok := strings.CutPrefix(pair, source); ok && quote != "" && quote != source {
+			rates[quote] = v
+		}
+	}
+	return rates
+}
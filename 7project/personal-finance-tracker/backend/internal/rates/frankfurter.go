@@ -0,0 +1,116 @@
+// backend/internal/rates/frankfurter.go
+
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultFrankfurterURL is the public Frankfurter API, which republishes the
+// European Central Bank reference rates.
+const DefaultFrankfurterURL = "https://api.frankfurter.app"
+
+// Frankfurter fetches ECB reference rates through the Frankfurter API.
+type Frankfurter struct {
+	BaseURL string       // defaults to DefaultFrankfurterURL
+	Client  *http.Client // defaults to a client with a 15s timeout
+}
+
+// Name implements Provider.
+func (f *Frankfurter) Name() string { return "frankfurter" }
+
+// frankfurterResp mirrors the JSON returned by the /latest and /{date} endpoints.
+type frankfurterResp struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// Latest implements Provider.
+func (f *Frankfurter) Latest(ctx context.Context, base string) (*Snapshot, error) {
+	u := strings.TrimRight(f.baseURL(), "/") + "/latest?from=" + url.QueryEscape(base)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient(f.Client).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("frankfurter: unexpected status %d", resp.StatusCode)
+	}
+
+	var body frankfurterResp
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("frankfurter: decode: %w", err)
+	}
+	d, err := time.Parse("2006-01-02", body.Date)
+	if err != nil {
+		return nil, fmt.Errorf("frankfurter: bad date %q", body.Date)
+	}
+	return &Snapshot{Date: d, Base: body.Base, Rates: body.Rates}, nil
+}
+
+func (f *Frankfurter) baseURL() string {
+	if f.BaseURL == "" {
+		return DefaultFrankfurterURL
+	}
+	return f.BaseURL
+}
+
+// frankfurterRangeResp mirrors the JSON returned by the /{from}..{to} endpoint.
+type frankfurterRangeResp struct {
+	Base  string                        `json:"base"`
+	Rates map[string]map[string]float64 `json:"rates"` // by day, YYYY-MM-DD
+}
+
+// frankfurterRangeDays bounds the days asked for in one request; longer ranges are
+// answered with weekly rather than daily rates.
+const frankfurterRangeDays = 90
+
+// Range implements History.
+func (f *Frankfurter) Range(ctx context.Context, base string, from, to time.Time) ([]Snapshot, error) {
+	return chunks(from, to, frankfurterRangeDays, func(from, to time.Time) ([]Snapshot, error) {
+		return f.rangeOnce(ctx, base, from, to)
+	})
+}
+
+func (f *Frankfurter) rangeOnce(ctx context.Context, base string, from, to time.Time) ([]Snapshot, error) {
+	u := strings.TrimRight(f.baseURL(), "/") + "/" + from.Format("2006-01-02") + ".." + to.Format("2006-01-02") +
+		"?from=" + url.QueryEscape(base)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient(f.Client).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("frankfurter: unexpected status %d", resp.StatusCode)
+	}
+
+	var body frankfurterRangeResp
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("frankfurter: decode: %w", err)
+	}
+	out := make([]Snapshot, 0, len(body.Rates))
+	for day, rates := range body.Rates {
+		d, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			return nil, fmt.Errorf("frankfurter: bad date %q", day)
+		}
+		out = append(out, Snapshot{Date: d, Base: body.Base, Rates: rates})
+	}
+	sortSnapshots(out)
+	return out, nil
+}
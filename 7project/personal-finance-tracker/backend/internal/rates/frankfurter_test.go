@@ -0,0 +1,50 @@
+// backend/internal/rates/frankfurter_test.go
+//
+// Purpose:
+//   Verify that the Frankfurter provider requests the right base currency and
+//   decodes the published rates.
+// Method:
+//   Serve a canned response from httptest.Server and point the provider at it.
+
+package rates
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFrankfurterLatest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/latest" || r.URL.Query().Get("from") != "EUR" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"amount":1.0,"base":"EUR","date":"2024-05-10","rates":{"USD":1.0773,"GBP":0.86}}`))
+	}))
+	defer srv.Close()
+
+	f := &Frankfurter{BaseURL: srv.URL}
+	s, err := f.Latest(context.Background(), "EUR")
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if s.Base != "EUR" || s.Date.Format("2006-01-02") != "2024-05-10" {
+		t.Fatalf("unexpected snapshot: %+v", s)
+	}
+	if s.Rates["USD"] != 1.0773 || s.Rates["GBP"] != 0.86 {
+		t.Fatalf("unexpected rates: %+v", s.Rates)
+	}
+}
+
+func TestFrankfurterLatest_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	f := &Frankfurter{BaseURL: srv.URL}
+	if _, err := f.Latest(context.Background(), "EUR"); err == nil {
+		t.Fatalf("expected an error for a non-200 response")
+	}
+}
@@ -0,0 +1,63 @@
+// backend/internal/rates/manual.go
+
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+)
+
+// Manual serves rates maintained by hand in a JSON file, for deployments without
+// internet access or with house rates:
+//
+//	{"base": "EUR", "date": "2024-05-10", "rates": {"USD": 1.0773, "GBP": 0.86}}
+//
+// The file is re-read on every refresh, so edits apply from the next one. Without a
+// date the rates are stored for the day of the refresh, i.e. they stay current until
+// the file changes.
+type Manual struct {
+	Path string
+}
+
+// Name implements Provider.
+func (m *Manual) Name() string { return "manual" }
+
+// manualFile is the format of the rates file.
+type manualFile struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+var currencyRe = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Latest implements Provider. base is ignored; the file names its own.
+func (m *Manual) Latest(_ context.Context, _ string) (*Snapshot, error) {
+	b, err := os.ReadFile(m.Path)
+	if err != nil {
+		return nil, fmt.Errorf("manual rates: %w", err)
+	}
+	var f manualFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("manual rates: %s: %w", m.Path, err)
+	}
+	if !currencyRe.MatchString(f.Base) {
+		return nil, fmt.Errorf("manual rates: %s: base %q must be a 3-letter upper-case currency code", m.Path, f.Base)
+	}
+	for quote, v := range f.Rates {
+		if !currencyRe.MatchString(quote) || v <= 0 {
+			return nil, fmt.Errorf("manual rates: %s: invalid rate %v for %q", m.Path, v, quote)
+		}
+	}
+	d := time.Now().UTC().Truncate(24 * time.Hour)
+	if f.Date != "" {
+		if d, err = time.Parse("2006-01-02", f.Date); err != nil {
+			return nil, fmt.Errorf("manual rates: %s: bad date %q", m.Path, f.Date)
+		}
+	}
+	return &Snapshot{Date: d, Base: f.Base, Rates: f.Rates}, nil
+}
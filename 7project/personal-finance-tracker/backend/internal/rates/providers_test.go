@@ -0,0 +1,113 @@
+// backend/internal/rates/providers_test.go
+//
+// Purpose:
+//   Verify the ECB, exchangerate.host and manual providers decode their sources
+//   into snapshots and report failures, and that New selects providers by name.
+// Method:
+//   Serve canned responses from httptest.Server; write manual rates to a temp file.
+
+package rates
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestECBLatest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2024-05-10">
+			<Cube currency="USD" rate="1.0773"/>
+			<Cube currency="GBP" rate="0.86"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`))
+	}))
+	defer srv.Close()
+
+	s, err := (&ECB{URL: srv.URL}).Latest(context.Background(), "USD")
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if s.Base != "EUR" || s.Date.Format("2006-01-02") != "2024-05-10" {
+		t.Fatalf("unexpected snapshot: %+v", s)
+	}
+	if s.Rates["USD"] != 1.0773 || s.Rates["GBP"] != 0.86 || len(s.Rates) != 2 {
+		t.Fatalf("unexpected rates: %+v", s.Rates)
+	}
+}
+
+func TestExchangerateHostLatest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/live" || r.URL.Query().Get("source") != "USD" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if r.URL.Query().Get("access_key") != "k" {
+			_, _ = w.Write([]byte(`{"success":false,"error":{"code":101,"info":"invalid access key"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true,"timestamp":1715342400,"source":"USD","quotes":{"USDEUR":0.928,"USDGBP":0.798}}`))
+	}))
+	defer srv.Close()
+
+	s, err := (&ExchangerateHost{AccessKey: "k", BaseURL: srv.URL}).Latest(context.Background(), "USD")
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if s.Base != "USD" || s.Date.Format("2006-01-02") != "2024-05-10" || s.Rates["EUR"] != 0.928 || s.Rates["GBP"] != 0.798 {
+		t.Fatalf("unexpected snapshot: %+v", s)
+	}
+
+	if _, err := (&ExchangerateHost{AccessKey: "bad", BaseURL: srv.URL}).Latest(context.Background(), "USD"); err == nil {
+		t.Fatalf("expected an error for an unsuccessful response")
+	}
+}
+
+func TestManualLatest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	write := func(s string) {
+		if err := os.WriteFile(path, []byte(s), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := &Manual{Path: path}
+
+	write(`{"base":"EUR","date":"2024-05-10","rates":{"USD":1.08}}`)
+	s, err := m.Latest(context.Background(), "EUR")
+	if err != nil || s.Base != "EUR" || s.Date.Format("2006-01-02") != "2024-05-10" || s.Rates["USD"] != 1.08 {
+		t.Fatalf("Latest = %+v, %v", s, err)
+	}
+
+	// Without a date the rates are for today.
+	write(`{"base":"EUR","rates":{"USD":1.09}}`)
+	if s, err = m.Latest(context.Background(), "EUR"); err != nil || !s.Date.Equal(time.Now().UTC().Truncate(24*time.Hour)) {
+		t.Fatalf("undated Latest = %+v, %v", s, err)
+	}
+
+	for _, bad := range []string{`{"base":"eur","rates":{}}`, `{"base":"EUR","rates":{"USD":0}}`, `not json`} {
+		write(bad)
+		if _, err := m.Latest(context.Background(), "EUR"); err == nil {
+			t.Errorf("expected an error for %s", bad)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{"frankfurter", "ecb", "exchangerate.host", "manual"} {
+		p, err := New(name, "key", "rates.json")
+		if err != nil || p.Name() != name {
+			t.Errorf("New(%q) = %v, %v", name, p, err)
+		}
+	}
+	if _, err := New("oanda", "", ""); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
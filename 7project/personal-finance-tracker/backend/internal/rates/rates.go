@@ -0,0 +1,123 @@
+// backend/internal/rates/rates.go
+
+// Package rates fetches daily exchange rates from a provider (Frankfurter, the ECB,
+// exchangerate.host, or a hand-maintained file) and keeps the exchange_rates table
+// up to date.
+package rates
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Snapshot is the set of rates published for one day.
+// Rates maps a quote currency to the amount of it bought by 1 unit of Base.
+type Snapshot struct {
+	Date  time.Time
+	Base  string
+	Rates map[string]float64
+}
+
+// Provider is a source of exchange rates.
+type Provider interface {
+	// Name identifies the provider; stored alongside every fetched rate.
+	Name() string
+	// Latest returns the most recent rates published for base. Providers that only
+	// publish one base (ECB, Manual) return theirs; readers derive cross rates.
+	Latest(ctx context.Context, base string) (*Snapshot, error)
+}
+
+// New returns the provider selected by RATES_PROVIDER ("frankfurter", "ecb",
+// "exchangerate.host" or "manual"). apiKey is the exchangerate.host access key and
+// file the Manual rates file.
+func New(name, apiKey, file string) (Provider, error) {
+	switch name {
+	case "frankfurter":
+		return &Frankfurter{}, nil
+	case "ecb":
+		return &ECB{}, nil
+	case "exchangerate.host":
+		return &ExchangerateHost{AccessKey: apiKey}, nil
+	case "manual":
+		return &Manual{Path: file}, nil
+	}
+	return nil, fmt.Errorf("unknown rates provider %q", name)
+}
+
+// httpClient returns c, or a client with a 15s timeout when c is nil.
+func httpClient(c *http.Client) *http.Client {
+	if c == nil {
+		return &http.Client{Timeout: 15 * time.Second}
+	}
+	return c
+}
+
+// Store persists fetched rates; implemented by repo.RateRepo.
+type Store interface {
+	SaveRates(ctx context.Context, date time.Time, base string, rates map[string]float64, source string) error
+}
+
+// Job periodically fetches the latest rates for Base and stores them.
+type Job struct {
+	Provider Provider
+	Store    Store
+	Base     string
+	Interval time.Duration // defaults to 24h
+}
+
+// Refresh fetches and stores one snapshot.
+func (j *Job) Refresh(ctx context.Context) error {
+	s, err := j.Provider.Latest(ctx, j.Base)
+	if err != nil {
+		return err
+	}
+	return j.Store.SaveRates(ctx, s.Date, s.Base, s.Rates, j.Provider.Name())
+}
+
+// Run refreshes immediately and then once per Interval until ctx is cancelled.
+// Failures are logged and retried on the next tick.
+func (j *Job) Run(ctx context.Context) {
+	interval := j.Interval
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if err := j.Refresh(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("rates refresh failed", "provider", j.Provider.Name(), "base", j.Base, "error", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// sortSnapshots orders snapshots oldest first.
+func sortSnapshots(s []Snapshot) {
+	sort.Slice(s, func(i, j int) bool { return s[i].Date.Before(s[j].Date) })
+}
+
+// chunks calls fn for consecutive spans of at most days days covering from to to,
+// both included, and collects the snapshots in order.
+func chunks(from, to time.Time, days int, fn func(from, to time.Time) ([]Snapshot, error)) ([]Snapshot, error) {
+	var out []Snapshot
+	for start := from; !start.After(to); start = start.AddDate(0, 0, days) {
+		end := start.AddDate(0, 0, days-1)
+		if end.After(to) {
+			end = to
+		}
+		s, err := fn(start, end)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s...)
+	}
+	return out, nil
+}
@@ -0,0 +1,189 @@
+// backend/internal/repo/account.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Account is a bank account, card or wallet transactions can be booked to.
+// OpeningBalance is the balance before the first booked transaction.
+type Account struct {
+	ID             int64     `json:"id"`
+	UserID         int64     `json:"user_id"`
+	Name           string    `json:"name"`
+	OpeningBalance float64   `json:"opening_balance"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// StatementLine is a transaction on an account statement with the balance after it.
+type StatementLine struct {
+	Transaction
+	Balance float64 `json:"balance"`
+}
+
+// Statement is an account's activity over a date range.
+// - OpeningBalance: balance at the start of From (before any transaction that day)
+// - Transactions: booked to the account within [From, To], ordered by date and id
+// - ClosingBalance: balance at the end of To
+type Statement struct {
+	Account        Account         `json:"account"`
+	From           string          `json:"from"`
+	To             string          `json:"to"`
+	OpeningBalance float64         `json:"opening_balance"`
+	Transactions   []StatementLine `json:"transactions"`
+	ClosingBalance float64         `json:"closing_balance"`
+}
+
+// AccountRepo manages accounts and their statements.
+type AccountRepo struct {
+	pool  dbConn
+	crypt *fieldCrypt
+	conv  Conversion
+}
+
+// AccountRepo accessor bound to the Store's pool.
+func (s *Store) AccountRepo() *AccountRepo { return &AccountRepo{pool: s.db(), crypt: s.crypt} }
+
+const accountCols = `id, user_id, name, opening_balance, created_at, updated_at`
+
+func scanAccount(row pgx.Row) (*Account, error) {
+	var a Account
+	if err := row.Scan(&a.ID, &a.UserID, &a.Name, &a.OpeningBalance, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// List returns the user's accounts ordered by name.
+func (r *AccountRepo) List(ctx context.Context, userID int64) ([]Account, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+accountCols+` FROM accounts WHERE user_id=$1 ORDER BY lower(name), id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Account{}
+	for rows.Next() {
+		a, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *a)
+	}
+	return out, rows.Err()
+}
+
+// Get returns an account owned by the user; (nil, nil) when not found.
+func (r *AccountRepo) Get(ctx context.Context, userID, id int64) (*Account, error) {
+	a, err := scanAccount(r.pool.QueryRow(ctx, `SELECT `+accountCols+` FROM accounts WHERE user_id=$1 AND id=$2`, userID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return a, err
+}
+
+// Create inserts an account and returns the stored row.
+func (r *AccountRepo) Create(ctx context.Context, a *Account) (*Account, error) {
+	const q = `INSERT INTO accounts (user_id, name, opening_balance) VALUES ($1,$2,$3) RETURNING ` + accountCols
+	return scanAccount(r.pool.QueryRow(ctx, q, a.UserID, a.Name, a.OpeningBalance))
+}
+
+// Update renames an account or changes its opening balance. Returns (nil, nil) when not found.
+func (r *AccountRepo) Update(ctx context.Context, userID, id int64, a *Account) (*Account, error) {
+	const q = `UPDATE accounts SET name=$3, opening_balance=$4 WHERE user_id=$1 AND id=$2 RETURNING ` + accountCols
+	out, err := scanAccount(r.pool.QueryRow(ctx, q, userID, id, a.Name, a.OpeningBalance))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// Delete removes an account; its transactions are kept and no longer name an account.
+func (r *AccountRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM accounts WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// Statement returns the account's statement for [from, to] (inclusive dates);
+// (nil, nil) when the account does not exist. Only the range itself is listed; the
+// history before it is summed in the database. Converted statements take each
+// transaction at the rate of its date and the opening balance, which has none, at
+// the rate as of from.
+func (r *AccountRepo) Statement(ctx context.Context, userID, id int64, from, to time.Time) (*Statement, error) {
+	a, err := r.Get(ctx, userID, id)
+	if a == nil || err != nil {
+		return nil, err
+	}
+	rate, err := r.conv.rate(ctx, r.pool, from)
+	if err != nil {
+		return nil, err
+	}
+	a.OpeningBalance = round2(a.OpeningBalance * rate)
+
+	var before float64
+	amt := r.conv.txnAmount("")
+	if err := r.pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(CASE WHEN type='income' THEN `+amt+` ELSE -`+amt+` END), 0)
+		FROM transactions WHERE user_id=$1 AND account_id=$2 AND date < $3`,
+		userID, id, from).Scan(&before); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pool.Query(ctx, `SELECT `+txnCols+`, `+amt+` FROM transactions
+	                                WHERE user_id=$1 AND account_id=$2 AND date >= $3 AND date <= $4
+	                                ORDER BY date, id`, userID, id, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var txs []Transaction
+	for rows.Next() {
+		var t Transaction
+		var amt float64
+		if err := rows.Scan(append(t.scanDest(), &amt)...); err != nil {
+			return nil, err
+		}
+		t.Amount = amt
+		txs = append(txs, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.crypt.openAll(ctx, txs); err != nil {
+		return nil, err
+	}
+	return BuildStatement(*a, from, to, before, txs), nil
+}
+
+// BuildStatement assembles a statement from the account, the net amount booked to it
+// before from, and the transactions within the range in booking order.
+func BuildStatement(a Account, from, to time.Time, before float64, txs []Transaction) *Statement {
+	s := &Statement{
+		Account:        a,
+		From:           from.Format("2006-01-02"),
+		To:             to.Format("2006-01-02"),
+		OpeningBalance: round2(a.OpeningBalance + before),
+		Transactions:   make([]StatementLine, 0, len(txs)),
+	}
+	balance := s.OpeningBalance
+	for _, t := range txs {
+		if t.Type == "income" {
+			balance += t.Amount
+		} else {
+			balance -= t.Amount
+		}
+		balance = round2(balance)
+		s.Transactions = append(s.Transactions, StatementLine{Transaction: t, Balance: balance})
+	}
+	s.ClosingBalance = balance
+	return s
+}
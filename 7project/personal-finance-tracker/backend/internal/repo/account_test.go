@@ -0,0 +1,36 @@
+// backend/internal/repo/account_test.go
+//
+// Purpose:
+//   Verify that a statement starts from the opening balance plus earlier activity
+//   and carries a running balance through income and expenses to the closing balance.
+
+package repo
+
+import "testing"
+
+func TestBuildStatement(t *testing.T) {
+	a := Account{ID: 1, Name: "Checking", OpeningBalance: 100}
+	txs := []Transaction{
+		{ID: 3, Amount: 20.10, Type: "expense", Date: mustDate("2026-10-02")},
+		{ID: 4, Amount: 1000, Type: "income", Date: mustDate("2026-10-03")},
+		{ID: 5, Amount: 0.20, Type: "expense", Date: mustDate("2026-10-03")},
+	}
+	s := BuildStatement(a, mustDate("2026-10-01"), mustDate("2026-10-31"), -50.5, txs)
+	if s.OpeningBalance != 49.5 || s.ClosingBalance != 1029.2 {
+		t.Fatalf("opening/closing = %v/%v, want 49.5/1029.2", s.OpeningBalance, s.ClosingBalance)
+	}
+	want := []float64{29.4, 1029.4, 1029.2}
+	for i, l := range s.Transactions {
+		if l.Balance != want[i] {
+			t.Errorf("line %d balance = %v, want %v", i, l.Balance, want[i])
+		}
+	}
+	if s.From != "2026-10-01" || s.To != "2026-10-31" {
+		t.Errorf("range = %s..%s", s.From, s.To)
+	}
+
+	empty := BuildStatement(a, mustDate("2026-11-01"), mustDate("2026-11-30"), 0, nil)
+	if empty.ClosingBalance != 100 || empty.Transactions == nil {
+		t.Errorf("empty statement = %+v", empty)
+	}
+}
@@ -0,0 +1,317 @@
+// backend/internal/repo/alert.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AlertRepo backs alerting (see migration 032): the data alerts are raised from,
+// which alerts were already sent, and users' notification preferences.
+type AlertRepo struct{ pool dbConn }
+
+// AlertRepo accessor bound to the Store's pool.
+func (s *Store) AlertRepo() *AlertRepo { return &AlertRepo{pool: s.db()} }
+
+// BudgetUsage is a live budget with the expenses counted against it so far.
+// CategoryName is nil for a budget covering all expenses; for a group's budget
+// (GroupID set) it is the group's name.
+type BudgetUsage struct {
+	BudgetID     int64
+	CategoryID   *int64
+	GroupID      *int64
+	CategoryName *string
+	Limit        float64
+	Spent        float64
+}
+
+// BudgetUsage returns the user's budgets of month that an expense in categoryID
+// counts against: the category's budget, its group's and the overall one.
+func (r *AlertRepo) BudgetUsage(ctx context.Context, userID int64, month string, categoryID *int64) ([]BudgetUsage, error) {
+	const q = `
+SELECT b.id, b.category_id, b.group_id, COALESCE(g.name, c.name), b.limit_amount,
+       COALESCE((
+           SELECT SUM(mt.total) FROM monthly_totals mt
+           WHERE mt.user_id = b.user_id AND mt.type='expense' AND mt.month = to_date(b.period_month, 'YYYY-MM')
+             AND ` + budgetCovers + `
+       ), 0)
+FROM budgets b
+LEFT JOIN categories c ON c.id = b.category_id
+LEFT JOIN category_groups g ON g.id = b.group_id
+WHERE b.user_id=$1 AND b.period_month=$2 AND b.deleted_at IS NULL
+  AND ((b.category_id IS NULL AND b.group_id IS NULL) OR b.category_id = $3
+       OR b.group_id = (SELECT group_id FROM categories WHERE id = $3))
+ORDER BY b.id`
+	rows, err := r.pool.Query(ctx, q, userID, month, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []BudgetUsage
+	for rows.Next() {
+		var u BudgetUsage
+		if err := rows.Scan(&u.BudgetID, &u.CategoryID, &u.GroupID, &u.CategoryName, &u.Limit, &u.Spent); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// OverBudget returns the user's budgets of month whose expenses exceed the limit.
+func (r *AlertRepo) OverBudget(ctx context.Context, userID int64, month string) ([]BudgetUsage, error) {
+	const q = `
+SELECT id, category_id, group_id, name, limit_amount, spent FROM (
+    SELECT b.id, b.category_id, b.group_id, COALESCE(g.name, c.name) AS name, b.limit_amount,
+           COALESCE((
+               SELECT SUM(mt.total) FROM monthly_totals mt
+               WHERE mt.user_id = b.user_id AND mt.type='expense' AND mt.month = to_date(b.period_month, 'YYYY-MM')
+                 AND ` + budgetCovers + `
+           ), 0) AS spent
+    FROM budgets b
+    LEFT JOIN categories c ON c.id = b.category_id
+    LEFT JOIN category_groups g ON g.id = b.group_id
+    WHERE b.user_id=$1 AND b.period_month=$2 AND b.deleted_at IS NULL
+) u
+WHERE spent > limit_amount
+ORDER BY id`
+	rows, err := r.pool.Query(ctx, q, userID, month)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []BudgetUsage{}
+	for rows.Next() {
+		var u BudgetUsage
+		if err := rows.Scan(&u.BudgetID, &u.CategoryID, &u.GroupID, &u.CategoryName, &u.Limit, &u.Spent); err != nil {
+			return nil, err
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+// ExpenseStats summarises a user's expenses in one category (nil: uncategorized).
+type ExpenseStats struct {
+	Count  int
+	Mean   float64
+	StdDev float64
+}
+
+// ExpenseStats returns the statistics of the user's expenses in categoryID dated
+// within [from, to), leaving out transaction exclude.
+func (r *AlertRepo) ExpenseStats(ctx context.Context, userID int64, categoryID *int64, from, to time.Time, exclude int64) (*ExpenseStats, error) {
+	const q = `SELECT count(*), COALESCE(avg(amount), 0), COALESCE(stddev_pop(amount), 0)
+	           FROM transactions
+	           WHERE user_id=$1 AND type='expense' AND category_id IS NOT DISTINCT FROM $2
+	             AND date >= $3 AND date < $4 AND id <> $5`
+	var s ExpenseStats
+	if err := r.pool.QueryRow(ctx, q, userID, categoryID, from, to, exclude).Scan(&s.Count, &s.Mean, &s.StdDev); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// BillReminders returns every user's bill occurrences within their reminder window
+// on today, bills on autopay included.
+func (r *AlertRepo) BillReminders(ctx context.Context, today time.Time) ([]UpcomingBill, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+billCols+` FROM bills WHERE remind_days >= 0 ORDER BY user_id, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var bills []Bill
+	maxDays := 0
+	for rows.Next() {
+		b, err := scanBill(rows)
+		if err != nil {
+			return nil, err
+		}
+		bills = append(bills, *b)
+		maxDays = max(maxDays, b.RemindDays)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	var out []UpcomingBill
+	for _, u := range UpcomingBills(bills, today, maxDays) {
+		if u.Remind {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+// WeeklyTotals summarises one user's finished week.
+// TopCategory is the expense category with the largest total, nil when all of the
+// week's expenses were uncategorized.
+type WeeklyTotals struct {
+	UserID      int64
+	WeekStart   time.Time
+	Income      float64
+	Expense     float64
+	TopCategory *string
+	TopAmount   float64
+}
+
+// WeeklySummaries returns the totals of the week that ended yesterday for every user
+// whose week (by their week_start preference) begins on today. Users without
+// transactions that week are left out.
+func (r *AlertRepo) WeeklySummaries(ctx context.Context, today time.Time) ([]WeeklyTotals, error) {
+	const q = `
+WITH due AS (
+    SELECT id AS user_id, $1::date - 7 AS start FROM users WHERE week_start = EXTRACT(DOW FROM $1::date)
+), week AS (
+    SELECT t.* FROM due d
+    JOIN transactions t ON t.user_id = d.user_id AND t.date >= d.start AND t.date < d.start + 7
+), top AS (
+    SELECT DISTINCT ON (w.user_id) w.user_id, c.name, SUM(w.amount) AS amount
+    FROM week w JOIN categories c ON c.id = w.category_id
+    WHERE w.type='expense' AND NOT w.reimbursed
+    GROUP BY w.user_id, c.id, c.name
+    ORDER BY w.user_id, SUM(w.amount) DESC, c.id
+)
+SELECT w.user_id, d.start,
+       COALESCE(SUM(w.amount) FILTER (WHERE w.type='income'), 0),
+       COALESCE(SUM(w.amount) FILTER (WHERE w.type='expense' AND NOT w.reimbursed), 0),
+       top.name, COALESCE(top.amount, 0)
+FROM week w
+JOIN due d ON d.user_id = w.user_id
+LEFT JOIN top ON top.user_id = w.user_id
+GROUP BY w.user_id, d.start, top.name, top.amount
+ORDER BY w.user_id`
+	rows, err := r.pool.Query(ctx, q, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []WeeklyTotals
+	for rows.Next() {
+		var w WeeklyTotals
+		if err := rows.Scan(&w.UserID, &w.WeekStart, &w.Income, &w.Expense, &w.TopCategory, &w.TopAmount); err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+// MarkSent records that the alert identified by key was raised for the user.
+// Reports false when it already was, so callers raise each alert once.
+func (r *AlertRepo) MarkSent(ctx context.Context, userID int64, key string) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `INSERT INTO sent_alerts (user_id, key) VALUES ($1, $2) ON CONFLICT DO NOTHING`, userID, key)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// UnmarkSent forgets a raised alert, so it is raised again (used when queueing it failed).
+func (r *AlertRepo) UnmarkSent(ctx context.Context, userID int64, key string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM sent_alerts WHERE user_id=$1 AND key=$2`, userID, key)
+	return err
+}
+
+// CleanupSent forgets alerts raised before cutoff. Keys embed their period (month,
+// due date), so old ones cannot recur.
+func (r *AlertRepo) CleanupSent(ctx context.Context, cutoff time.Time) (int64, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM sent_alerts WHERE created_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
+
+// NotificationPrefs returns the user's stored choices as event -> channel -> enabled.
+// Combinations not present take their default (see alerts.DefaultEnabled).
+func (r *AlertRepo) NotificationPrefs(ctx context.Context, userID int64) (map[string]map[string]bool, error) {
+	rows, err := r.pool.Query(ctx, `SELECT event, channel, enabled FROM notification_preferences WHERE user_id=$1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[string]map[string]bool{}
+	for rows.Next() {
+		var event, channel string
+		var on bool
+		if err := rows.Scan(&event, &channel, &on); err != nil {
+			return nil, err
+		}
+		if out[event] == nil {
+			out[event] = map[string]bool{}
+		}
+		out[event][channel] = on
+	}
+	return out, rows.Err()
+}
+
+// SetNotificationPrefs stores the given choices; combinations not mentioned are kept.
+func (r *AlertRepo) SetNotificationPrefs(ctx context.Context, userID int64, prefs map[string]map[string]bool) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	const q = `INSERT INTO notification_preferences (user_id, event, channel, enabled)
+	           VALUES ($1, $2, $3, $4)
+	           ON CONFLICT (user_id, event, channel) DO UPDATE SET enabled=EXCLUDED.enabled`
+	for event, channels := range prefs {
+		for channel, on := range channels {
+			if _, err := tx.Exec(ctx, q, userID, event, channel, on); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// QuietHours is a user's daily window without interrupting alerts (see migration 054).
+// Start and End are minutes after midnight in Timezone; the window may wrap midnight.
+type QuietHours struct {
+	Start    int
+	End      int
+	Timezone string
+}
+
+// QuietHours returns the user's quiet hours, or (nil, nil) when they have none.
+func (r *AlertRepo) QuietHours(ctx context.Context, userID int64) (*QuietHours, error) {
+	var q QuietHours
+	err := r.pool.QueryRow(ctx, `SELECT start_minute, end_minute, timezone FROM notification_quiet_hours WHERE user_id=$1`, userID).
+		Scan(&q.Start, &q.End, &q.Timezone)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// SetQuietHours stores the user's quiet hours; nil removes them.
+func (r *AlertRepo) SetQuietHours(ctx context.Context, userID int64, q *QuietHours) error {
+	if q == nil {
+		_, err := r.pool.Exec(ctx, `DELETE FROM notification_quiet_hours WHERE user_id=$1`, userID)
+		return err
+	}
+	_, err := r.pool.Exec(ctx, `INSERT INTO notification_quiet_hours (user_id, start_minute, end_minute, timezone)
+	                            VALUES ($1, $2, $3, $4)
+	                            ON CONFLICT (user_id) DO UPDATE
+	                            SET start_minute=EXCLUDED.start_minute, end_minute=EXCLUDED.end_minute, timezone=EXCLUDED.timezone`,
+		userID, q.Start, q.End, q.Timezone)
+	return err
+}
+
+// NotificationEnabled reports whether the user wants event delivered on channel, or
+// def when they made no choice.
+func (r *AlertRepo) NotificationEnabled(ctx context.Context, userID int64, event, channel string, def bool) (bool, error) {
+	var on bool
+	err := r.pool.QueryRow(ctx, `SELECT COALESCE((SELECT enabled FROM notification_preferences
+	                                              WHERE user_id=$1 AND event=$2 AND channel=$3), $4)`,
+		userID, event, channel, def).Scan(&on)
+	return on, err
+}
@@ -0,0 +1,103 @@
+// backend/internal/repo/apikey.go
+
+package repo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// APIKey is a key a user created for an integration (see migration 043). The key
+// itself is only known when it is created.
+// - Prefix: the key's first characters, to recognize it
+// - Scopes: what the key may do (see handler.Scopes)
+// - LastUsedAt: nil until first used; updated at most once a minute
+type APIKey struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// APIKeyRepo stores API keys by hash. Authenticate works without a tenant, since it
+// learns the user from the key.
+type APIKeyRepo struct{ pool dbConn }
+
+// APIKeyRepo accessor bound to the Store's pool.
+func (s *Store) APIKeyRepo() *APIKeyRepo { return &APIKeyRepo{pool: s.db()} }
+
+const apiKeyCols = `id, user_id, name, prefix, scopes, created_at, last_used_at`
+
+func scanAPIKey(row pgx.Row) (*APIKey, error) {
+	var k APIKey
+	if err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.Scopes, &k.CreatedAt, &k.LastUsedAt); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// hashAPIKey returns the stored form of a key.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// List returns the user's API keys, newest first.
+func (r *APIKeyRepo) List(ctx context.Context, userID int64) ([]APIKey, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+apiKeyCols+` FROM api_keys WHERE user_id=$1 ORDER BY id DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []APIKey{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *k)
+	}
+	return out, rows.Err()
+}
+
+// Create stores key for the user under name with scopes and returns the stored row.
+func (r *APIKeyRepo) Create(ctx context.Context, userID int64, name, key string, scopes []string) (*APIKey, error) {
+	const q = `INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes) VALUES ($1,$2,$3,$4,$5) RETURNING ` + apiKeyCols
+	return scanAPIKey(r.pool.QueryRow(ctx, q, userID, name, key[:min(len(key), 12)], hashAPIKey(key), scopes))
+}
+
+// Delete revokes a key scoped to the user.
+func (r *APIKeyRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM api_keys WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// Authenticate returns the key matching key, recording its use, or (nil, nil) when
+// no such key exists.
+func (r *APIKeyRepo) Authenticate(ctx context.Context, key string) (*APIKey, error) {
+	const q = `UPDATE api_keys SET last_used_at=NOW()
+	           WHERE key_hash=$1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+	           RETURNING ` + apiKeyCols
+	hash := hashAPIKey(key)
+	k, err := scanAPIKey(r.pool.QueryRow(ctx, q, hash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Used within the last minute, or unknown.
+		k, err = scanAPIKey(r.pool.QueryRow(ctx, `SELECT `+apiKeyCols+` FROM api_keys WHERE key_hash=$1`, hash))
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return k, err
+}
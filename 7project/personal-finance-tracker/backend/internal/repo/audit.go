@@ -0,0 +1,108 @@
+// backend/internal/repo/audit.go
+
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry is one recorded write (see migration 029).
+// - UserID: owner of the changed row; nil for operator settings such as feature flags
+// - Actor: who made the change: "user:<id>", "admin", "job:<kind>" or "system"
+// - Entity/EntityID: table name and primary key (multi-column keys joined with ':')
+// - Action: "create" | "update" | "delete"
+// - Before/After: the row before and after the change; null for creates and deletes respectively
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	UserID    *int64          `json:"user_id"`
+	Actor     string          `json:"actor"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	RequestID *string         `json:"request_id"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditFilter narrows an audit log listing; zero values match everything.
+// - UserID: only rows owned by this user
+// - Entity/EntityID: one table, or one row of it
+// - Action/Actor: exact match
+// - Since/Until: created_at range, Until exclusive
+// - Limit/Offset: pagination (Limit defaults to 100, at most 1000)
+type AuditFilter struct {
+	UserID   *int64
+	Entity   string
+	EntityID string
+	Action   string
+	Actor    string
+	Since    *time.Time
+	Until    *time.Time
+	Limit    int
+	Offset   int
+}
+
+// AuditRepo reads the audit log; entries are written by database triggers only.
+type AuditRepo struct{ pool dbConn }
+
+// AuditRepo accessor bound to the Store's pool.
+func (s *Store) AuditRepo() *AuditRepo { return &AuditRepo{pool: s.db()} }
+
+// List returns matching entries, newest first.
+func (r *AuditRepo) List(ctx context.Context, f AuditFilter) ([]AuditEntry, error) {
+	q := `SELECT id, user_id, actor, entity, entity_id, action, before, after, request_id, created_at
+	      FROM audit_log WHERE TRUE`
+	var args []any
+	add := func(cond string, v any) {
+		args = append(args, v)
+		q += " AND " + cond + "$" + itoa(len(args))
+	}
+	if f.UserID != nil {
+		add("user_id = ", *f.UserID)
+	}
+	if f.Entity != "" {
+		add("entity = ", f.Entity)
+	}
+	if f.EntityID != "" {
+		add("entity_id = ", f.EntityID)
+	}
+	if f.Action != "" {
+		add("action = ", f.Action)
+	}
+	if f.Actor != "" {
+		add("actor = ", f.Actor)
+	}
+	if f.Since != nil {
+		add("created_at >= ", *f.Since)
+	}
+	if f.Until != nil {
+		add("created_at < ", *f.Until)
+	}
+	if f.Limit <= 0 || f.Limit > 1000 {
+		f.Limit = 100
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	args = append(args, f.Limit, f.Offset)
+	q += " ORDER BY id DESC LIMIT $" + itoa(len(args)-1) + " OFFSET $" + itoa(len(args))
+
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []AuditEntry{}
+	for rows.Next() {
+		var e AuditEntry
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Actor, &e.Entity, &e.EntityID, &e.Action,
+			&e.Before, &e.After, &e.RequestID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
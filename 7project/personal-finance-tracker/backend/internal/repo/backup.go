@@ -0,0 +1,196 @@
+// backend/internal/repo/backup.go
+
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// backupTable is a table holding per-user data.
+// - Name: table name; rows are dumped with row_to_json and restored with json_populate_recordset
+// - Owner: WHERE clause selecting the user's rows ($1 = user id)
+// - Serial: the id column draws from a sequence that must be advanced past restored ids
+type backupTable struct {
+	Name   string
+	Owner  string
+	Serial bool
+}
+
+// backupTables lists per-user tables in restore order (parents before children).
+// Derived data (monthly_totals), the job queue, shared exchange rates, feature flag
+// overrides (operator configuration), the audit log, push devices and Telegram links
+// (tied to app installs and chats), ingest addresses, API keys, OAuth apps and grants
+// and webhook endpoints (credentials) with their deliveries, the record of sent alerts
+// and the in-app notifications filed from them, CSV imports and exports (their data is
+// backed up itself), API usage counters and the sync change log are not part of a
+// user's backup: totals are rebuilt by triggers as transactions are restored. The wrapped data key (user_keys) is included so
+// encrypted descriptions restore, under the same master key.
+var backupTables = []backupTable{
+	{Name: "users", Owner: "id=$1", Serial: true},
+	{Name: "user_keys", Owner: "user_id=$1"},
+	{Name: "category_groups", Owner: "user_id=$1", Serial: true},
+	{Name: "categories", Owner: "user_id=$1", Serial: true},
+	{Name: "budgets", Owner: "user_id=$1", Serial: true},
+	{Name: "user_dashboard", Owner: "user_id=$1"},
+	{Name: "closed_periods", Owner: "user_id=$1"},
+	{Name: "reimbursement_claims", Owner: "user_id=$1", Serial: true},
+	{Name: "accounts", Owner: "user_id=$1", Serial: true},
+	{Name: "recurring_rules", Owner: "user_id=$1", Serial: true},
+	{Name: "transactions", Owner: "user_id=$1", Serial: true},
+	{Name: "loans", Owner: "user_id=$1", Serial: true},
+	{Name: "loan_payments", Owner: "loan_id IN (SELECT id FROM loans WHERE user_id=$1)"},
+	{Name: "bills", Owner: "user_id=$1", Serial: true},
+	{Name: "subscriptions", Owner: "user_id=$1", Serial: true},
+	{Name: "wishlist_items", Owner: "user_id=$1", Serial: true},
+	{Name: "contacts", Owner: "user_id=$1", Serial: true},
+	{Name: "expense_splits", Owner: "user_id=$1", Serial: true},
+	{Name: "expense_split_shares", Owner: "split_id IN (SELECT id FROM expense_splits WHERE user_id=$1)"},
+	{Name: "settlements", Owner: "user_id=$1", Serial: true},
+	{Name: "emergency_fund_accounts", Owner: "user_id=$1", Serial: true},
+	{Name: "income_sources", Owner: "user_id=$1", Serial: true},
+	{Name: "draft_transactions", Owner: "user_id=$1", Serial: true},
+	{Name: "retention_policies", Owner: "user_id=$1", Serial: true},
+	{Name: "notification_preferences", Owner: "user_id=$1"},
+	{Name: "notification_quiet_hours", Owner: "user_id=$1"},
+	{Name: "chat_webhooks", Owner: "user_id=$1", Serial: true},
+}
+
+// UserDump is a logical copy of one user's rows, keyed by table name.
+// Each value is a JSON array of row objects as produced by row_to_json.
+type UserDump struct {
+	UserID int64                      `json:"user_id"`
+	Tables map[string]json.RawMessage `json:"tables"`
+}
+
+// BackupRepo exports and restores users' data for disaster recovery.
+type BackupRepo struct {
+	pool  *pgxpool.Pool
+	crypt *fieldCrypt
+}
+
+// BackupRepo accessor bound to the Store's pool.
+func (s *Store) BackupRepo() *BackupRepo { return &BackupRepo{pool: s.Pool, crypt: s.crypt} }
+
+// UserIDs lists every user id in ascending order.
+func (r *BackupRepo) UserIDs(ctx context.Context) ([]int64, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id FROM users ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// Export dumps all of a user's rows from one REPEATABLE READ snapshot, so the tables
+// are consistent with each other. Returns (nil, nil) if the user does not exist.
+func (r *BackupRepo) Export(ctx context.Context, userID int64) (*UserDump, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	d := &UserDump{UserID: userID, Tables: make(map[string]json.RawMessage, len(backupTables))}
+	for _, t := range backupTables {
+		var rows []byte
+		q := fmt.Sprintf(`SELECT COALESCE(json_agg(row_to_json(t)), '[]') FROM %s t WHERE %s`, t.Name, t.Owner)
+		if err := tx.QueryRow(ctx, q, userID).Scan(&rows); err != nil {
+			return nil, fmt.Errorf("export %s: %w", t.Name, err)
+		}
+		if t.Name == "users" && string(rows) == "[]" {
+			return nil, nil
+		}
+		d.Tables[t.Name] = rows
+	}
+	return d, nil
+}
+
+// ExportReadable is Export with encrypted fields decrypted and without the user's
+// wrapped data key: the copy of their data handed to the user themselves.
+func (r *BackupRepo) ExportReadable(ctx context.Context, userID int64) (*UserDump, error) {
+	d, err := r.Export(ctx, userID)
+	if err != nil || d == nil {
+		return d, err
+	}
+	if err := r.crypt.openDump(ctx, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Restore replaces the user's current data with the dump inside one transaction:
+// the user row is deleted (cascading to everything it owns) and every table is
+// reloaded with the original ids. Sequences are advanced past restored ids so the
+// dump can also be loaded into a fresh database. Returns the number of rows
+// restored per table. A different account already using the dump's email yields
+// a unique violation.
+func (r *BackupRepo) Restore(ctx context.Context, d *UserDump) (map[string]int64, error) {
+	if len(d.Tables["users"]) == 0 {
+		return nil, fmt.Errorf("restore: dump has no users table")
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Replacing every row would flood the audit log (migration 029); skip it for this
+	// transaction, and the closed-month check (migration 060), as closed months come back too.
+	if _, err := tx.Exec(ctx, `SELECT set_config('app.audit', 'off', true), set_config('app.closed_periods', 'off', true)`); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM users WHERE id=$1`, d.UserID); err != nil {
+		return nil, err
+	}
+	// Older months may have no transactions partition yet (see migration 024).
+	if _, err := tx.Exec(ctx, `SELECT ensure_transaction_partitions(MIN(date), MAX(date))
+	                           FROM json_populate_recordset(NULL::transactions, $1)
+	                           HAVING COUNT(*) > 0`, rawJSON(d.Tables["transactions"])); err != nil {
+		return nil, fmt.Errorf("restore transactions: %w", err)
+	}
+
+	counts := make(map[string]int64, len(backupTables))
+	for _, t := range backupTables {
+		rows := rawJSON(d.Tables[t.Name])
+		ct, err := tx.Exec(ctx, fmt.Sprintf(`INSERT INTO %[1]s SELECT * FROM json_populate_recordset(NULL::%[1]s, $1)`, t.Name), rows)
+		if err != nil {
+			return nil, fmt.Errorf("restore %s: %w", t.Name, err)
+		}
+		counts[t.Name] = ct.RowsAffected()
+		if t.Serial && ct.RowsAffected() > 0 {
+			q := fmt.Sprintf(`SELECT setval(s, m)
+			                  FROM (SELECT pg_get_serial_sequence('%[1]s', 'id') AS s, (SELECT MAX(id) FROM %[1]s) AS m) x
+			                  WHERE m > COALESCE(pg_sequence_last_value(s::regclass), 0)`, t.Name)
+			if _, err := tx.Exec(ctx, q); err != nil {
+				return nil, fmt.Errorf("restore %s sequence: %w", t.Name, err)
+			}
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	// The dump may carry another data key than the one cached for this user.
+	r.crypt.forget(d.UserID)
+	return counts, nil
+}
+
+// rawJSON passes a dumped table to json_populate_recordset; missing tables restore nothing.
+func rawJSON(rows json.RawMessage) string {
+	if len(rows) == 0 {
+		return "[]"
+	}
+	return string(rows)
+}
@@ -0,0 +1,152 @@
+// backend/internal/repo/bill.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Bill is the repository-layer DTO mirroring the bills table.
+// - DueDay: day of month the bill is due (clamped to the month's last day)
+// - Autopay: paid automatically; reminders are informational only
+// - RemindDays: how many days before the due date a reminder becomes active
+type Bill struct {
+	ID         int64     `json:"id"`
+	UserID     int64     `json:"user_id"`
+	CategoryID *int64    `json:"category_id"`
+	Name       string    `json:"name"`
+	Amount     float64   `json:"amount"`
+	DueDay     int       `json:"due_day"`
+	Autopay    bool      `json:"autopay"`
+	RemindDays int       `json:"remind_days"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// UpcomingBill is a bill occurrence falling within the requested window.
+// Remind is true once the occurrence is within the bill's RemindDays.
+type UpcomingBill struct {
+	Bill
+	DueDate  string `json:"due_date"` // YYYY-MM-DD
+	DaysLeft int    `json:"days_left"`
+	Remind   bool   `json:"remind"`
+}
+
+// BillRepo provides CRUD operations for bills.
+type BillRepo struct{ pool dbConn }
+
+// BillRepo accessor bound to the Store's pool.
+func (s *Store) BillRepo() *BillRepo { return &BillRepo{pool: s.db()} }
+
+const billCols = `id, user_id, category_id, name, amount, due_day, autopay, remind_days, created_at, updated_at`
+
+func scanBill(row pgx.Row) (*Bill, error) {
+	var b Bill
+	if err := row.Scan(&b.ID, &b.UserID, &b.CategoryID, &b.Name, &b.Amount, &b.DueDay, &b.Autopay, &b.RemindDays, &b.CreatedAt, &b.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// List returns all bills of a user ordered by due day.
+func (r *BillRepo) List(ctx context.Context, userID int64) ([]Bill, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+billCols+` FROM bills WHERE user_id=$1 ORDER BY due_day, id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Bill{}
+	for rows.Next() {
+		b, err := scanBill(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *b)
+	}
+	return out, rows.Err()
+}
+
+// Create inserts a bill and returns the stored row.
+func (r *BillRepo) Create(ctx context.Context, b *Bill) (*Bill, error) {
+	const q = `INSERT INTO bills (user_id, category_id, name, amount, due_day, autopay, remind_days)
+	           VALUES ($1,$2,$3,$4,$5,$6,$7)
+	           RETURNING ` + billCols
+	return scanBill(r.pool.QueryRow(ctx, q, b.UserID, b.CategoryID, b.Name, b.Amount, b.DueDay, b.Autopay, b.RemindDays))
+}
+
+// Update modifies a bill owned by the user. Returns (nil, nil) when not found.
+func (r *BillRepo) Update(ctx context.Context, userID, id int64, b *Bill) (*Bill, error) {
+	const q = `UPDATE bills
+	           SET category_id=$3, name=$4, amount=$5, due_day=$6, autopay=$7, remind_days=$8
+	           WHERE user_id=$1 AND id=$2
+	           RETURNING ` + billCols
+	out, err := scanBill(r.pool.QueryRow(ctx, q, userID, id, b.CategoryID, b.Name, b.Amount, b.DueDay, b.Autopay, b.RemindDays))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// Delete removes a bill scoped to the user.
+func (r *BillRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM bills WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// Upcoming returns bill occurrences due within [today, today+days], soonest first.
+func (r *BillRepo) Upcoming(ctx context.Context, userID int64, today time.Time, days int) ([]UpcomingBill, error) {
+	bills, err := r.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return UpcomingBills(bills, today, days), nil
+}
+
+// UpcomingBills expands monthly bills into dated occurrences within [today, today+days].
+func UpcomingBills(bills []Bill, today time.Time, days int) []UpcomingBill {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	end := today.AddDate(0, 0, days)
+	out := []UpcomingBill{}
+	for _, b := range bills {
+		for due := NextDueDate(b.DueDay, today); !due.After(end); due = NextDueDate(b.DueDay, due.AddDate(0, 0, 1)) {
+			left := int(due.Sub(today).Hours() / 24)
+			out = append(out, UpcomingBill{
+				Bill:     b,
+				DueDate:  due.Format("2006-01-02"),
+				DaysLeft: left,
+				Remind:   left <= b.RemindDays,
+			})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].DueDate < out[j].DueDate })
+	return out
+}
+
+// NextDueDate returns the first date on or after from that matches dueDay,
+// clamping dueDay to the last day of shorter months (e.g. 31 → 30 April).
+func NextDueDate(dueDay int, from time.Time) time.Time {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		monthStart := time.Date(from.Year(), from.Month()+time.Month(i), 1, 0, 0, 0, 0, time.UTC)
+		last := monthStart.AddDate(0, 1, -1).Day()
+		d := dueDay
+		if d > last {
+			d = last
+		}
+		due := time.Date(monthStart.Year(), monthStart.Month(), d, 0, 0, 0, 0, time.UTC)
+		if !due.Before(from) {
+			return due
+		}
+	}
+	// Unreachable: the following month always contains a matching day.
+	return from
+}
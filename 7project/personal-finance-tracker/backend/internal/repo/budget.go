@@ -3,36 +3,75 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
 )
 
 // Budget is the repository-layer DTO mirroring the budgets table.
-// CategoryID is nullable to support global (uncategorized) monthly budgets.
+// A budget targets one category (CategoryID), every category of a group (GroupID), or,
+// with neither, all expenses of the month.
+// DeletedAt is set for soft-deleted budgets (see softdelete.go).
 type Budget struct {
-	ID          int64     `json:"id"`
-	UserID      int64     `json:"user_id"`
-	CategoryID  *int64    `json:"category_id"`  // nullable
-	PeriodMonth string    `json:"period_month"` // YYYY-MM
-	LimitAmount float64   `json:"limit_amount"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	CategoryID  *int64     `json:"category_id"`  // nullable
+	GroupID     *int64     `json:"group_id"`     // nullable; never set with CategoryID
+	PeriodMonth string     `json:"period_month"` // YYYY-MM
+	LimitAmount float64    `json:"limit_amount"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// budgetCols lists the budgets columns in the order expected by Budget.scanDest.
+const budgetCols = `id, user_id, category_id, group_id, period_month, limit_amount, created_at, updated_at, deleted_at`
+
+// budgetCovers is the condition on monthly_totals mt selecting the expenses budget b
+// counts: those of its category, of its group's current member categories, or all.
+const budgetCovers = `(CASE
+                   WHEN b.group_id IS NOT NULL THEN mt.category_id IN (SELECT id FROM categories WHERE group_id = b.group_id)
+                   WHEN b.category_id IS NOT NULL THEN mt.category_id = b.category_id
+                   ELSE TRUE END)`
+
+// scanDest returns scan destinations matching budgetCols.
+func (b *Budget) scanDest() []any {
+	return []any{&b.ID, &b.UserID, &b.CategoryID, &b.GroupID, &b.PeriodMonth, &b.LimitAmount, &b.CreatedAt, &b.UpdatedAt, &b.DeletedAt}
 }
 
 // BudgetRepo provides CRUD operations for budgets using a pgx connection pool.
-type BudgetRepo struct{ pool *pgxpool.Pool }
+// ListByMonth reads from read (the replica when configured); everything else uses pool.
+type BudgetRepo struct {
+	pool dbConn
+	read querier
+}
 
 // BudgetRepo returns a BudgetRepo bound to the Store's pool.
-func (s *Store) BudgetRepo() *BudgetRepo { return &BudgetRepo{pool: s.Pool} }
+func (s *Store) BudgetRepo() *BudgetRepo { return &BudgetRepo{pool: s.db(), read: s.reader()} }
 
 // ListByMonth fetches all budgets for a given user and YYYY-MM period.
 // Results are ordered by id for deterministic client rendering.
 func (r *BudgetRepo) ListByMonth(ctx context.Context, userID int64, month string) ([]Budget, error) {
-	const q = `SELECT id, user_id, category_id, period_month, limit_amount, created_at
+	const q = `SELECT ` + budgetCols + `
 	           FROM budgets
-	           WHERE user_id=$1 AND period_month=$2
+	           WHERE user_id=$1 AND period_month=$2 AND deleted_at IS NULL
 	           ORDER BY id`
-	rows, err := r.pool.Query(ctx, q, userID, month)
+	return r.list(ctx, q, userID, month)
+}
+
+// ListDeleted returns the user's soft-deleted budgets, most recently deleted first.
+func (r *BudgetRepo) ListDeleted(ctx context.Context, userID int64) ([]Budget, error) {
+	const q = `SELECT ` + budgetCols + `
+	           FROM budgets
+	           WHERE user_id=$1 AND deleted_at IS NOT NULL
+	           ORDER BY deleted_at DESC, id`
+	return r.list(ctx, q, userID)
+}
+
+// list runs a budgets query selecting budgetCols.
+func (r *BudgetRepo) list(ctx context.Context, q string, args ...any) ([]Budget, error) {
+	rows, err := r.read.Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +80,7 @@ func (r *BudgetRepo) ListByMonth(ctx context.Context, userID int64, month string
 	var out []Budget
 	for rows.Next() {
 		var b Budget
-		if err := rows.Scan(&b.ID, &b.UserID, &b.CategoryID, &b.PeriodMonth, &b.LimitAmount, &b.CreatedAt); err != nil {
+		if err := rows.Scan(b.scanDest()...); err != nil {
 			return nil, err
 		}
 		out = append(out, b)
@@ -50,41 +89,57 @@ func (r *BudgetRepo) ListByMonth(ctx context.Context, userID int64, month string
 }
 
 // Create inserts a new budget and returns the inserted row, including timestamps.
+// A GroupID that is not one of the user's groups yields ErrUnknownGroup.
 func (r *BudgetRepo) Create(ctx context.Context, b *Budget) (*Budget, error) {
-	const q = `INSERT INTO budgets (user_id, category_id, period_month, limit_amount)
-	           VALUES ($1,$2,$3,$4)
-	           RETURNING id, user_id, category_id, period_month, limit_amount, created_at`
+	if err := checkGroup(ctx, r.pool, b.UserID, b.GroupID); err != nil {
+		return nil, err
+	}
+	const q = `INSERT INTO budgets (user_id, category_id, group_id, period_month, limit_amount)
+	           VALUES ($1,$2,$3,$4,$5)
+	           RETURNING ` + budgetCols
 	var out Budget
-	if err := r.pool.QueryRow(ctx, q, b.UserID, b.CategoryID, b.PeriodMonth, b.LimitAmount).
-		Scan(&out.ID, &out.UserID, &out.CategoryID, &out.PeriodMonth, &out.LimitAmount, &out.CreatedAt); err != nil {
+	if err := r.pool.QueryRow(ctx, q, b.UserID, b.CategoryID, b.GroupID, b.PeriodMonth, b.LimitAmount).
+		Scan(out.scanDest()...); err != nil {
 		return nil, err
 	}
 	return &out, nil
 }
 
 // Update modifies an existing budget owned by userID and returns the updated row.
-// Matching on both user_id and id ensures tenant isolation.
+// Matching on both user_id and id ensures tenant isolation; deleted budgets are not updated.
 func (r *BudgetRepo) Update(ctx context.Context, userID, id int64, b *Budget) (*Budget, error) {
+	if err := checkGroup(ctx, r.pool, userID, b.GroupID); err != nil {
+		return nil, err
+	}
 	const q = `UPDATE budgets
-	           SET category_id=$3, period_month=$4, limit_amount=$5
-	           WHERE user_id=$1 AND id=$2
-	           RETURNING id, user_id, category_id, period_month, limit_amount, created_at`
+	           SET category_id=$3, group_id=$4, period_month=$5, limit_amount=$6
+	           WHERE user_id=$1 AND id=$2 AND deleted_at IS NULL
+	           RETURNING ` + budgetCols
 	var out Budget
-	err := r.pool.QueryRow(ctx, q, userID, id, b.CategoryID, b.PeriodMonth, b.LimitAmount).
-		Scan(&out.ID, &out.UserID, &out.CategoryID, &out.PeriodMonth, &out.LimitAmount, &out.CreatedAt)
+	err := r.pool.QueryRow(ctx, q, userID, id, b.CategoryID, b.GroupID, b.PeriodMonth, b.LimitAmount).
+		Scan(out.scanDest()...)
 	if err != nil {
 		return nil, err
 	}
 	return &out, nil
 }
 
-// Delete removes a budget by id scoped to userID.
+// Delete soft-deletes a budget by id scoped to userID; it can be restored until purged.
 // Returns true when a row was deleted, false if nothing matched.
 func (r *BudgetRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
-	const q = `DELETE FROM budgets WHERE user_id=$1 AND id=$2`
-	ct, err := r.pool.Exec(ctx, q, userID, id)
-	if err != nil {
-		return false, err
+	return softDelete(ctx, r.pool, "budgets", userID, id)
+}
+
+// Restore undeletes a soft-deleted budget and returns it. Returns (nil, nil) if no
+// deleted budget matched; a live budget for the same month and category yields a
+// unique violation.
+func (r *BudgetRepo) Restore(ctx context.Context, userID, id int64) (*Budget, error) {
+	var out Budget
+	if err := restoreDeleted(ctx, r.pool, "budgets", budgetCols, userID, id).Scan(out.scanDest()...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
 	}
-	return ct.RowsAffected() > 0, nil
+	return &out, nil
 }
@@ -5,12 +5,9 @@ package repo
 import (
 	"context"
 	"errors"
-	"fmt" // <-- add
 	"time"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // ErrFKConflict is returned when a delete operation violates a foreign key constraint.
@@ -19,27 +16,107 @@ var ErrFKConflict = errors.New("fk_conflict")
 
 // Category is the repository-layer DTO mirroring the categories table.
 // Type is expected to be either "income" or "expense".
+// TaxDeductible is the default for transactions in this category; TaxCategory is the
+// label used to group deductible amounts in the tax report (falls back to Name).
+// GroupID is the category group it belongs to, if any (see category_group.go).
+// DeletedAt is set for soft-deleted categories (see softdelete.go).
+// Usage is only filled by ListWithUsage.
 type Category struct {
-	ID        int64     `json:"id"`
-	UserID    int64     `json:"user_id"`
-	Name      string    `json:"name"`
-	Type      string    `json:"type"` // "income" | "expense"
-	CreatedAt time.Time `json:"created_at"`
+	ID            int64          `json:"id"`
+	UserID        int64          `json:"user_id"`
+	Name          string         `json:"name"`
+	Type          string         `json:"type"` // "income" | "expense"
+	TaxDeductible bool           `json:"tax_deductible"`
+	TaxCategory   string         `json:"tax_category"`
+	GroupID       *int64         `json:"group_id"`
+	TxnLimit      *float64       `json:"txn_limit"`
+	TxnLimitMode  *string        `json:"txn_limit_mode"` // "warn" | "block"; null without a limit
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     *time.Time     `json:"deleted_at,omitempty"`
+	Usage         *CategoryUsage `json:"usage,omitempty"`
+}
+
+// CategoryUsage sums up the transactions filed under a category.
+// - Transactions: how many there are, across all dates
+// - LastUsed: date of the latest one; null for an unused category
+type CategoryUsage struct {
+	Transactions int        `json:"transactions"`
+	LastUsed     *time.Time `json:"last_used"`
+}
+
+// categoryCols lists the categories columns in the order expected by Category.scanDest.
+const categoryCols = `id, user_id, name, type, tax_deductible, tax_category, group_id, txn_limit, txn_limit_mode, created_at, updated_at, deleted_at`
+
+// scanDest returns scan destinations matching categoryCols.
+func (c *Category) scanDest() []any {
+	return []any{&c.ID, &c.UserID, &c.Name, &c.Type, &c.TaxDeductible, &c.TaxCategory, &c.GroupID, &c.TxnLimit, &c.TxnLimitMode, &c.CreatedAt, &c.UpdatedAt, &c.DeletedAt}
 }
 
 // CategoryRepo provides data access for categories via a pgx connection pool.
-type CategoryRepo struct{ pool *pgxpool.Pool }
+// List reads from read (the replica when configured); everything else uses pool.
+type CategoryRepo struct {
+	pool dbConn
+	read querier
+}
 
 // CategoryRepo constructor bound to the Store's pool.
-func (s *Store) CategoryRepo() *CategoryRepo { return &CategoryRepo{pool: s.Pool} }
+func (s *Store) CategoryRepo() *CategoryRepo { return &CategoryRepo{pool: s.db(), read: s.reader()} }
 
-// List returns all categories for a given user, ordered by id for deterministic output.
+// List returns all live categories for a given user, ordered by id for deterministic output.
 func (r *CategoryRepo) List(ctx context.Context, userID int64) ([]Category, error) {
-	const q = `SELECT id, user_id, name, type, created_at
+	const q = `SELECT ` + categoryCols + `
 	           FROM categories
-	           WHERE user_id=$1
+	           WHERE user_id=$1 AND deleted_at IS NULL
 	           ORDER BY id`
-	rows, err := r.pool.Query(ctx, q, userID)
+	return r.list(ctx, q, userID)
+}
+
+// ListDeleted returns the user's soft-deleted categories, most recently deleted first.
+func (r *CategoryRepo) ListDeleted(ctx context.Context, userID int64) ([]Category, error) {
+	const q = `SELECT ` + categoryCols + `
+	           FROM categories
+	           WHERE user_id=$1 AND deleted_at IS NOT NULL
+	           ORDER BY deleted_at DESC, id`
+	return r.list(ctx, q, userID)
+}
+
+// ListWithUsage returns List's categories (ListDeleted's when deleted) with their
+// Usage, counted in the same query, so unused or long-unused ones can be suggested
+// for cleanup.
+func (r *CategoryRepo) ListWithUsage(ctx context.Context, userID int64, deleted bool) ([]Category, error) {
+	where, order := `deleted_at IS NULL`, `id`
+	if deleted {
+		where, order = `deleted_at IS NOT NULL`, `deleted_at DESC, id`
+	}
+	q := `SELECT ` + categoryCols + `, COALESCE(u.n, 0), u.last_used
+	      FROM categories
+	      LEFT JOIN (SELECT category_id, count(*) AS n, max(date) AS last_used
+	                 FROM transactions
+	                 WHERE user_id=$1 AND category_id IS NOT NULL
+	                 GROUP BY category_id) u ON u.category_id = categories.id
+	      WHERE user_id=$1 AND ` + where + `
+	      ORDER BY ` + order
+	rows, err := r.read.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Category
+	for rows.Next() {
+		c := Category{Usage: &CategoryUsage{}}
+		if err := rows.Scan(append(c.scanDest(), &c.Usage.Transactions, &c.Usage.LastUsed)...); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// list runs a categories query selecting categoryCols.
+func (r *CategoryRepo) list(ctx context.Context, q string, args ...any) ([]Category, error) {
+	rows, err := r.read.Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +125,7 @@ func (r *CategoryRepo) List(ctx context.Context, userID int64) ([]Category, erro
 	var out []Category
 	for rows.Next() {
 		var c Category
-		if err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Type, &c.CreatedAt); err != nil {
+		if err := rows.Scan(c.scanDest()...); err != nil {
 			return nil, err
 		}
 		out = append(out, c)
@@ -57,27 +134,31 @@ func (r *CategoryRepo) List(ctx context.Context, userID int64) ([]Category, erro
 }
 
 // Create inserts a new category for the user and returns the inserted row.
-// Database constraints (e.g., unique name/type per user) are enforced at the SQL layer.
-func (r *CategoryRepo) Create(ctx context.Context, userID int64, name, typ string) (*Category, error) {
-	const q = `INSERT INTO categories (user_id, name, type)
-	           VALUES ($1,$2,$3)
-	           RETURNING id, user_id, name, type, created_at`
+// Database constraints (e.g., unique name/type per user) are enforced at the SQL layer;
+// a GroupID that is not one of the user's groups yields ErrUnknownGroup.
+func (r *CategoryRepo) Create(ctx context.Context, in *Category) (*Category, error) {
+	if err := checkGroup(ctx, r.pool, in.UserID, in.GroupID); err != nil {
+		return nil, err
+	}
+	const q = `INSERT INTO categories (user_id, name, type, tax_deductible, tax_category, group_id, txn_limit, txn_limit_mode)
+	           VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+	           RETURNING ` + categoryCols
 	var c Category
-	if err := r.pool.QueryRow(ctx, q, userID, name, typ).
-		Scan(&c.ID, &c.UserID, &c.Name, &c.Type, &c.CreatedAt); err != nil {
+	if err := r.pool.QueryRow(ctx, q, in.UserID, in.Name, in.Type, in.TaxDeductible, in.TaxCategory, in.GroupID, in.TxnLimit, in.TxnLimitMode).
+		Scan(c.scanDest()...); err != nil {
 		return nil, err
 	}
 	return &c, nil
 }
 
-// Get fetches a single category by id scoped to the user.
+// Get fetches a single live category by id scoped to the user.
 // Returns (nil, nil) when no row is found.
 func (r *CategoryRepo) Get(ctx context.Context, userID, id int64) (*Category, error) {
-	const q = `SELECT id, user_id, name, type, created_at
+	const q = `SELECT ` + categoryCols + `
 	           FROM categories
-	           WHERE user_id=$1 AND id=$2`
+	           WHERE user_id=$1 AND id=$2 AND deleted_at IS NULL`
 	var c Category
-	err := r.pool.QueryRow(ctx, q, userID, id).Scan(&c.ID, &c.UserID, &c.Name, &c.Type, &c.CreatedAt)
+	err := r.pool.QueryRow(ctx, q, userID, id).Scan(c.scanDest()...)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -87,16 +168,20 @@ func (r *CategoryRepo) Get(ctx context.Context, userID, id int64) (*Category, er
 	return &c, nil
 }
 
-// Update modifies name and type for a category owned by the user.
-// Returns (nil, nil) if the category is not found (no rows matched).
-func (r *CategoryRepo) Update(ctx context.Context, userID, id int64, name, typ string) (*Category, error) {
+// Update modifies name, type, tax defaults, group and transaction limit for a category owned by the user.
+// Returns (nil, nil) if the category is not found (no rows matched), ErrUnknownGroup
+// for a GroupID that is not one of the user's groups.
+func (r *CategoryRepo) Update(ctx context.Context, userID, id int64, in *Category) (*Category, error) {
+	if err := checkGroup(ctx, r.pool, userID, in.GroupID); err != nil {
+		return nil, err
+	}
 	const q = `UPDATE categories
-	           SET name=$3, type=$4
-	           WHERE user_id=$1 AND id=$2
-	           RETURNING id, user_id, name, type, created_at`
+	           SET name=$3, type=$4, tax_deductible=$5, tax_category=$6, group_id=$7, txn_limit=$8, txn_limit_mode=$9
+	           WHERE user_id=$1 AND id=$2 AND deleted_at IS NULL
+	           RETURNING ` + categoryCols
 	var c Category
-	err := r.pool.QueryRow(ctx, q, userID, id, name, typ).
-		Scan(&c.ID, &c.UserID, &c.Name, &c.Type, &c.CreatedAt)
+	err := r.pool.QueryRow(ctx, q, userID, id, in.Name, in.Type, in.TaxDeductible, in.TaxCategory, in.GroupID, in.TxnLimit, in.TxnLimitMode).
+		Scan(c.scanDest()...)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
@@ -106,20 +191,31 @@ func (r *CategoryRepo) Update(ctx context.Context, userID, id int64, name, typ s
 	return &c, nil
 }
 
-// Delete removes a category by id scoped to the user.
-// On foreign key violation (SQLSTATE 23503), returns ErrFKConflict wrapped with the original pg error.
-// Returns (false, nil) when no rows were affected.
+// Delete soft-deletes a category by id scoped to the user; it can be restored until
+// purged. Transactions keep referring to it, so past reports still show its name.
+// Returns ErrFKConflict while live budgets use the category, and (false, nil) when no
+// rows were affected.
 func (r *CategoryRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
-	const q = `DELETE FROM categories WHERE user_id=$1 AND id=$2`
-	tag, err := r.pool.Exec(ctx, q, userID, id)
-	if err != nil {
-		var pgerr *pgconn.PgError
-		// 23503 = foreign_key_violation (likely due to budgets referencing this category).
-		if errors.As(err, &pgerr) && pgerr.Code == "23503" { // foreign_key_violation
-			// Preserve the sentinel and original error for callers that need details.
-			return false, fmt.Errorf("%w: %w", ErrFKConflict, pgerr)
-		}
+	var inUse bool
+	const q = `SELECT EXISTS (SELECT 1 FROM budgets WHERE user_id=$1 AND category_id=$2 AND deleted_at IS NULL)`
+	if err := r.pool.QueryRow(ctx, q, userID, id).Scan(&inUse); err != nil {
 		return false, err
 	}
-	return tag.RowsAffected() > 0, nil
+	if inUse {
+		return false, ErrFKConflict
+	}
+	return softDelete(ctx, r.pool, "categories", userID, id)
+}
+
+// Restore undeletes a soft-deleted category and returns it. Returns (nil, nil) if no
+// deleted category matched; a live category with the same name yields a unique violation.
+func (r *CategoryRepo) Restore(ctx context.Context, userID, id int64) (*Category, error) {
+	var c Category
+	if err := restoreDeleted(ctx, r.pool, "categories", categoryCols, userID, id).Scan(c.scanDest()...); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
 }
@@ -0,0 +1,123 @@
+// backend/internal/repo/category_group.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrUnknownGroup is returned when a category or budget refers to a category group
+// the user does not have.
+var ErrUnknownGroup = errors.New("unknown_group")
+
+// CategoryGroup gathers categories under one name (see migration 052). Categories
+// join a group through their GroupID; budgets may target a whole group.
+// - CategoryIDs: the live member categories
+type CategoryGroup struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	Name        string    `json:"name"`
+	CategoryIDs []int64   `json:"category_ids"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CategoryGroupRepo stores users' category groups.
+type CategoryGroupRepo struct{ pool dbConn }
+
+// CategoryGroupRepo accessor bound to the Store's pool.
+func (s *Store) CategoryGroupRepo() *CategoryGroupRepo { return &CategoryGroupRepo{pool: s.db()} }
+
+const categoryGroupCols = `id, user_id, name,
+	ARRAY(SELECT c.id FROM categories c WHERE c.group_id = category_groups.id AND c.deleted_at IS NULL ORDER BY c.id),
+	created_at, updated_at`
+
+func scanCategoryGroup(row pgx.Row) (*CategoryGroup, error) {
+	var g CategoryGroup
+	if err := row.Scan(&g.ID, &g.UserID, &g.Name, &g.CategoryIDs, &g.CreatedAt, &g.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// List returns the user's groups ordered by name.
+func (r *CategoryGroupRepo) List(ctx context.Context, userID int64) ([]CategoryGroup, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+categoryGroupCols+` FROM category_groups WHERE user_id=$1 ORDER BY lower(name), id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []CategoryGroup{}
+	for rows.Next() {
+		g, err := scanCategoryGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *g)
+	}
+	return out, rows.Err()
+}
+
+// Get returns one of the user's groups, or (nil, nil).
+func (r *CategoryGroupRepo) Get(ctx context.Context, userID, id int64) (*CategoryGroup, error) {
+	g, err := scanCategoryGroup(r.pool.QueryRow(ctx, `SELECT `+categoryGroupCols+` FROM category_groups WHERE user_id=$1 AND id=$2`, userID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return g, err
+}
+
+// Create adds a group. A name the user already has (in any case) violates a unique
+// constraint.
+func (r *CategoryGroupRepo) Create(ctx context.Context, userID int64, name string) (*CategoryGroup, error) {
+	return scanCategoryGroup(r.pool.QueryRow(ctx, `INSERT INTO category_groups (user_id, name) VALUES ($1, $2)
+	                                               RETURNING `+categoryGroupCols, userID, name))
+}
+
+// Rename renames a group. Returns (nil, nil) when not found.
+func (r *CategoryGroupRepo) Rename(ctx context.Context, userID, id int64, name string) (*CategoryGroup, error) {
+	g, err := scanCategoryGroup(r.pool.QueryRow(ctx, `UPDATE category_groups SET name=$3 WHERE user_id=$1 AND id=$2
+	                                                  RETURNING `+categoryGroupCols, userID, id, name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return g, err
+}
+
+// Delete removes a group; its categories become ungrouped. Returns ErrFKConflict
+// while live budgets target the group, and (false, nil) when not found.
+func (r *CategoryGroupRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	var inUse bool
+	const q = `SELECT EXISTS (SELECT 1 FROM budgets WHERE user_id=$1 AND group_id=$2 AND deleted_at IS NULL)`
+	if err := r.pool.QueryRow(ctx, q, userID, id).Scan(&inUse); err != nil {
+		return false, err
+	}
+	if inUse {
+		return false, ErrFKConflict
+	}
+	ct, err := r.pool.Exec(ctx, `DELETE FROM category_groups WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// checkGroup returns ErrUnknownGroup unless groupID is nil or one of userID's groups.
+// The foreign key only checks that the group exists, not whose it is.
+func checkGroup(ctx context.Context, db dbConn, userID int64, groupID *int64) error {
+	if groupID == nil {
+		return nil
+	}
+	var ok bool
+	if err := db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM category_groups WHERE user_id=$1 AND id=$2)`, userID, *groupID).Scan(&ok); err != nil {
+		return err
+	}
+	if !ok {
+		return ErrUnknownGroup
+	}
+	return nil
+}
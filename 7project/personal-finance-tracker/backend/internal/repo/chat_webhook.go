@@ -0,0 +1,131 @@
+// backend/internal/repo/chat_webhook.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ChatWebhook is a Slack or Discord incoming webhook alerts are posted to (see
+// migration 034). The URL embeds the webhook's secret, so responses only show
+// URLHint.
+// - Enabled: false once the service reported the webhook gone; updating it re-enables
+// - LastError: why the latest delivery failed, cleared by the next success
+type ChatWebhook struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Kind       string     `json:"kind"` // "slack" | "discord"
+	URL        string     `json:"-"`
+	URLHint    string     `json:"url_hint"`
+	Name       string     `json:"name"`
+	Enabled    bool       `json:"enabled"`
+	LastError  *string    `json:"last_error"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// ChatWebhookRepo stores users' Slack and Discord webhooks.
+type ChatWebhookRepo struct{ pool dbConn }
+
+// ChatWebhookRepo accessor bound to the Store's pool.
+func (s *Store) ChatWebhookRepo() *ChatWebhookRepo { return &ChatWebhookRepo{pool: s.db()} }
+
+const chatWebhookCols = `id, user_id, kind, url, name, enabled, last_error, last_used_at, created_at, updated_at`
+
+func scanChatWebhook(row pgx.Row) (*ChatWebhook, error) {
+	var w ChatWebhook
+	if err := row.Scan(&w.ID, &w.UserID, &w.Kind, &w.URL, &w.Name, &w.Enabled, &w.LastError, &w.LastUsedAt, &w.CreatedAt, &w.UpdatedAt); err != nil {
+		return nil, err
+	}
+	w.URLHint = urlHint(w.URL)
+	return &w, nil
+}
+
+// urlHint shows the host and the last characters of a secret-bearing URL.
+func urlHint(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || len(raw) < 8 {
+		return "…"
+	}
+	return u.Scheme + "://" + u.Host + "/…" + raw[len(raw)-4:]
+}
+
+func (r *ChatWebhookRepo) list(ctx context.Context, q string, args ...any) ([]ChatWebhook, error) {
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []ChatWebhook{}
+	for rows.Next() {
+		w, err := scanChatWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *w)
+	}
+	return out, rows.Err()
+}
+
+// List returns the user's webhooks.
+func (r *ChatWebhookRepo) List(ctx context.Context, userID int64) ([]ChatWebhook, error) {
+	return r.list(ctx, `SELECT `+chatWebhookCols+` FROM chat_webhooks WHERE user_id=$1 ORDER BY id`, userID)
+}
+
+// ListEnabled returns the user's enabled webhooks of kind.
+func (r *ChatWebhookRepo) ListEnabled(ctx context.Context, userID int64, kind string) ([]ChatWebhook, error) {
+	return r.list(ctx, `SELECT `+chatWebhookCols+` FROM chat_webhooks WHERE user_id=$1 AND kind=$2 AND enabled ORDER BY id`,
+		userID, kind)
+}
+
+// Get returns one of the user's webhooks, or (nil, nil).
+func (r *ChatWebhookRepo) Get(ctx context.Context, userID, id int64) (*ChatWebhook, error) {
+	w, err := scanChatWebhook(r.pool.QueryRow(ctx, `SELECT `+chatWebhookCols+` FROM chat_webhooks WHERE user_id=$1 AND id=$2`, userID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return w, err
+}
+
+// Create adds a webhook. A URL the user already added violates a unique constraint.
+func (r *ChatWebhookRepo) Create(ctx context.Context, w *ChatWebhook) (*ChatWebhook, error) {
+	const q = `INSERT INTO chat_webhooks (user_id, kind, url, name) VALUES ($1, $2, $3, $4)
+	           RETURNING ` + chatWebhookCols
+	return scanChatWebhook(r.pool.QueryRow(ctx, q, w.UserID, w.Kind, w.URL, w.Name))
+}
+
+// Update replaces a webhook's kind, URL and name and re-enables it. Returns (nil, nil)
+// when not found.
+func (r *ChatWebhookRepo) Update(ctx context.Context, userID, id int64, w *ChatWebhook) (*ChatWebhook, error) {
+	const q = `UPDATE chat_webhooks SET kind=$3, url=$4, name=$5, enabled=TRUE, last_error=NULL
+	           WHERE user_id=$1 AND id=$2
+	           RETURNING ` + chatWebhookCols
+	out, err := scanChatWebhook(r.pool.QueryRow(ctx, q, userID, id, w.Kind, w.URL, w.Name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// Delete removes one of the user's webhooks.
+func (r *ChatWebhookRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM chat_webhooks WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// RecordDelivery notes the outcome of posting to a webhook: a nil failure clears the
+// last error, and disable switches the webhook off.
+func (r *ChatWebhookRepo) RecordDelivery(ctx context.Context, userID, id int64, failure *string, disable bool) error {
+	_, err := r.pool.Exec(ctx, `UPDATE chat_webhooks SET last_used_at=NOW(), last_error=$3, enabled = enabled AND NOT $4
+	                            WHERE user_id=$1 AND id=$2`, userID, id, failure, disable)
+	return err
+}
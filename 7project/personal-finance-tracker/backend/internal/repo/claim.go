@@ -0,0 +1,334 @@
+// backend/internal/repo/claim.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrClaimLocked is returned when items of a submitted or paid claim are changed.
+	ErrClaimLocked = errors.New("claim_locked")
+	// ErrInvalidClaimItems is returned when a transaction cannot be added to a claim
+	// (not owned, not a reimbursable expense, or already part of another claim).
+	ErrInvalidClaimItems = errors.New("invalid_claim_items")
+	// ErrInvalidTransition is returned when a claim status change goes backwards.
+	ErrInvalidTransition = errors.New("invalid_transition")
+)
+
+// Claim statuses, in workflow order.
+const (
+	ClaimDraft     = "draft"
+	ClaimSubmitted = "submitted"
+	ClaimPaid      = "paid"
+)
+
+// Claim groups reimbursable expenses that are claimed back together.
+// - Status: "draft" | "submitted" | "paid"
+// - Total/ItemCount: derived from the attached transactions
+// - Items: attached transactions, only populated by Get
+type Claim struct {
+	ID          int64         `json:"id"`
+	UserID      int64         `json:"user_id"`
+	Title       string        `json:"title"`
+	Status      string        `json:"status"`
+	SubmittedAt *time.Time    `json:"submitted_at"`
+	PaidAt      *time.Time    `json:"paid_at"`
+	Total       float64       `json:"total"`
+	ItemCount   int           `json:"item_count"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	Items       []Transaction `json:"items,omitempty"`
+}
+
+// ClaimRepo provides data access for reimbursement claims.
+type ClaimRepo struct {
+	pool  dbConn
+	crypt *fieldCrypt
+}
+
+// ClaimRepo accessor bound to the Store's pool.
+func (s *Store) ClaimRepo() *ClaimRepo { return &ClaimRepo{pool: s.db(), crypt: s.crypt} }
+
+const claimSelect = `
+SELECT c.id, c.user_id, c.title, c.status, c.submitted_at, c.paid_at,
+       COALESCE((SELECT SUM(t.amount) FROM transactions t WHERE t.claim_id = c.id), 0)::float8,
+       (SELECT COUNT(*) FROM transactions t WHERE t.claim_id = c.id),
+       c.created_at, c.updated_at
+FROM reimbursement_claims c`
+
+func scanClaim(row pgx.Row) (*Claim, error) {
+	var c Claim
+	if err := row.Scan(&c.ID, &c.UserID, &c.Title, &c.Status, &c.SubmittedAt, &c.PaidAt, &c.Total, &c.ItemCount, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// claimStatusRank orders statuses so transitions can only move forward.
+var claimStatusRank = map[string]int{ClaimDraft: 0, ClaimSubmitted: 1, ClaimPaid: 2}
+
+// claimTransitionAllowed reports whether a claim may move from one status to another.
+func claimTransitionAllowed(from, to string) bool {
+	f, ok1 := claimStatusRank[from]
+	t, ok2 := claimStatusRank[to]
+	return ok1 && ok2 && t > f
+}
+
+// List returns the user's claims, newest first.
+func (r *ClaimRepo) List(ctx context.Context, userID int64) ([]Claim, error) {
+	rows, err := r.pool.Query(ctx, claimSelect+` WHERE c.user_id=$1 ORDER BY c.id DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Claim{}
+	for rows.Next() {
+		c, err := scanClaim(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *c)
+	}
+	return out, rows.Err()
+}
+
+// Get returns a claim with its transactions; (nil, nil) when not found.
+func (r *ClaimRepo) Get(ctx context.Context, userID, id int64) (*Claim, error) {
+	c, err := scanClaim(r.pool.QueryRow(ctx, claimSelect+` WHERE c.user_id=$1 AND c.id=$2`, userID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pool.Query(ctx, `SELECT `+txnCols+` FROM transactions
+	                                WHERE user_id=$1 AND claim_id=$2 ORDER BY date, id`, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	c.Items = []Transaction{}
+	for rows.Next() {
+		var t Transaction
+		if err := rows.Scan(t.scanDest()...); err != nil {
+			return nil, err
+		}
+		c.Items = append(c.Items, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return c, r.crypt.openAll(ctx, c.Items)
+}
+
+// Create inserts a draft claim and attaches the given transactions in one database
+// transaction. Returns ErrInvalidClaimItems if any transaction cannot be attached.
+func (r *ClaimRepo) Create(ctx context.Context, userID int64, title string, txnIDs []int64) (*Claim, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var id int64
+	if err := tx.QueryRow(ctx, `INSERT INTO reimbursement_claims (user_id, title) VALUES ($1,$2) RETURNING id`,
+		userID, title).Scan(&id); err != nil {
+		return nil, err
+	}
+	if err := attachClaimItems(ctx, tx, userID, id, txnIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, userID, id)
+}
+
+// AddItems attaches transactions to a draft claim.
+// Returns ErrNotFound, ErrClaimLocked or ErrInvalidClaimItems on failure.
+func (r *ClaimRepo) AddItems(ctx context.Context, userID, id int64, txnIDs []int64) (*Claim, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := lockDraftClaim(ctx, tx, userID, id); err != nil {
+		return nil, err
+	}
+	if err := attachClaimItems(ctx, tx, userID, id, txnIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, userID, id)
+}
+
+// RemoveItem detaches a transaction from a draft claim.
+// Returns (false, nil) when the transaction is not part of the claim.
+func (r *ClaimRepo) RemoveItem(ctx context.Context, userID, id, txnID int64) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := lockDraftClaim(ctx, tx, userID, id); err != nil {
+		return false, err
+	}
+	if err := ensureTxnsOpen(ctx, tx, userID, "id=$2", txnID); err != nil {
+		return false, err
+	}
+	ct, err := tx.Exec(ctx, `UPDATE transactions SET claim_id=NULL WHERE user_id=$1 AND claim_id=$2 AND id=$3`,
+		userID, id, txnID)
+	if err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// SetStatus moves a claim forward to status, stamping the transition date with on.
+// Marking a claim paid flags its transactions as reimbursed, which nets them out of
+// spending totals, so it returns ErrPeriodClosed when one of them is in a closed month.
+// Returns (nil, nil) when the claim is not found.
+func (r *ClaimRepo) SetStatus(ctx context.Context, userID, id int64, status string, on time.Time) (*Claim, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var cur string
+	err = tx.QueryRow(ctx, `SELECT status FROM reimbursement_claims WHERE user_id=$1 AND id=$2 FOR UPDATE`,
+		userID, id).Scan(&cur)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !claimTransitionAllowed(cur, status) {
+		return nil, ErrInvalidTransition
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE reimbursement_claims
+	                           SET status=$3,
+	                               submitted_at=COALESCE(submitted_at, $4),
+	                               paid_at=CASE WHEN $3='paid' THEN $4 END
+	                           WHERE user_id=$1 AND id=$2`, userID, id, status, on); err != nil {
+		return nil, err
+	}
+	if status == ClaimPaid {
+		if err := ensureTxnsOpen(ctx, tx, userID, "claim_id=$2", id); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, `UPDATE transactions SET reimbursed=TRUE WHERE user_id=$1 AND claim_id=$2`,
+			userID, id); err != nil {
+			return nil, periodErr(err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, userID, id)
+}
+
+// Delete removes a claim; its transactions are released (and count as spending again).
+// Returns ErrPeriodClosed when one of them is in a closed month.
+func (r *ClaimRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := ensureTxnsOpen(ctx, tx, userID, "claim_id=$2", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE transactions SET claim_id=NULL, reimbursed=FALSE
+	                          WHERE user_id=$1 AND claim_id=$2`, userID, id); err != nil {
+		return false, periodErr(err)
+	}
+	ct, err := tx.Exec(ctx, `DELETE FROM reimbursement_claims WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// lockDraftClaim locks the claim row and checks it is still a draft.
+func lockDraftClaim(ctx context.Context, tx pgx.Tx, userID, id int64) error {
+	var status string
+	err := tx.QueryRow(ctx, `SELECT status FROM reimbursement_claims WHERE user_id=$1 AND id=$2 FOR UPDATE`,
+		userID, id).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if status != ClaimDraft {
+		return ErrClaimLocked
+	}
+	return nil
+}
+
+// attachClaimItems links reimbursable, unclaimed expenses to claim id.
+// All ids must qualify, otherwise ErrInvalidClaimItems is returned; ErrPeriodClosed
+// when one is in a closed month.
+func attachClaimItems(ctx context.Context, tx pgx.Tx, userID, id int64, txnIDs []int64) error {
+	if len(txnIDs) == 0 {
+		return nil
+	}
+	if err := ensureTxnsOpen(ctx, tx, userID, "id = ANY($2)", txnIDs); err != nil {
+		return err
+	}
+	ct, err := tx.Exec(ctx, `UPDATE transactions SET claim_id=$2
+	                        WHERE user_id=$1 AND id = ANY($3) AND type='expense'
+	                          AND reimbursable AND claim_id IS NULL`, userID, id, txnIDs)
+	if err != nil {
+		return err
+	}
+	if ct.RowsAffected() != int64(len(uniqueIDs(txnIDs))) {
+		return ErrInvalidClaimItems
+	}
+	return nil
+}
+
+// ensureTxnsOpen is ensureOpen for the dates of the user's transactions matching cond
+// (with arg as $2), so claims cannot change the totals of a closed month.
+func ensureTxnsOpen(ctx context.Context, tx pgx.Tx, userID int64, cond string, arg any) error {
+	var dates []time.Time
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(array_agg(date), '{}') FROM transactions
+	                           WHERE user_id=$1 AND `+cond, userID, arg).Scan(&dates); err != nil {
+		return err
+	}
+	return ensureOpen(ctx, tx, userID, dates...)
+}
+
+// uniqueIDs returns ids without duplicates, preserving first occurrence order.
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
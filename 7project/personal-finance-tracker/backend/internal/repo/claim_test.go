@@ -0,0 +1,42 @@
+// backend/internal/repo/claim_test.go
+//
+// Purpose:
+//   Verify that claim statuses only move forward and that item ids are de-duplicated
+//   before the attached row count is checked.
+
+package repo
+
+import "testing"
+
+func TestClaimTransitionAllowed(t *testing.T) {
+	cases := []struct {
+		from, to string
+		want     bool
+	}{
+		{ClaimDraft, ClaimSubmitted, true},
+		{ClaimDraft, ClaimPaid, true},
+		{ClaimSubmitted, ClaimPaid, true},
+		{ClaimSubmitted, ClaimDraft, false},
+		{ClaimPaid, ClaimSubmitted, false},
+		{ClaimPaid, ClaimPaid, false},
+		{ClaimDraft, "rejected", false},
+	}
+	for _, c := range cases {
+		if got := claimTransitionAllowed(c.from, c.to); got != c.want {
+			t.Errorf("%s -> %s: got %v, want %v", c.from, c.to, got, c.want)
+		}
+	}
+}
+
+func TestUniqueIDs(t *testing.T) {
+	got := uniqueIDs([]int64{3, 1, 3, 2, 1})
+	want := []int64{3, 1, 2}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
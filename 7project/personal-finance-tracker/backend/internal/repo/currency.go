@@ -0,0 +1,107 @@
+// backend/internal/repo/currency.go
+
+package repo
+
+import (
+	"context"
+	"regexp"
+	"time"
+)
+
+// currencyCode matches ISO 4217 alphabetic codes.
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Conversion converts report amounts from the user's base currency into a display
+// currency, each transaction at the rate of its own date (fx_rate, migration 055).
+// The zero value leaves amounts in the base currency.
+type Conversion struct{ from, to string }
+
+// NewConversion returns the conversion from base to display. It reports false unless
+// both are three-letter uppercase codes; equal codes give the zero Conversion.
+// Validated codes are safe to inline in SQL.
+func NewConversion(base, display string) (Conversion, bool) {
+	if !currencyCode.MatchString(base) || !currencyCode.MatchString(display) {
+		return Conversion{}, false
+	}
+	if base == display {
+		return Conversion{}, true
+	}
+	return Conversion{from: base, to: display}, true
+}
+
+// Active reports whether amounts are converted at all.
+func (c Conversion) Active() bool { return c.to != "" }
+
+// amount returns a SQL expression for col converted at the rate of the day in dateCol.
+func (c Conversion) amount(col, dateCol string) string {
+	if !c.Active() {
+		return col
+	}
+	return col + " * fx_rate('" + c.from + "', '" + c.to + "', " + dateCol + ")"
+}
+
+// totals returns the table monthly totals are read from. Converted totals cannot use
+// the trigger-maintained monthly_totals, so an equivalent with the same columns is
+// derived from transactions; amounts are summed per day first so each day's rate is
+// looked up once. Reimbursed expenses count as zero, like in the totals trigger.
+func (c Conversion) totals() string {
+	if !c.Active() {
+		return "monthly_totals"
+	}
+	return `(
+	SELECT user_id, date_trunc('month', date)::date AS month, category_id, type,
+	       ROUND(SUM(` + c.amount("total", "date") + `), 2) AS total, SUM(tx_count)::int AS tx_count
+	FROM (
+		SELECT user_id, date, category_id, type,
+		       SUM(CASE WHEN reimbursed THEN 0 ELSE amount END) AS total, COUNT(*) AS tx_count
+		FROM transactions
+		GROUP BY user_id, date, category_id, type
+	) d
+	GROUP BY 1, 2, 3, 4
+)`
+}
+
+// txnAmount returns a column converting the amount of transactions aliased prefix
+// ("" or "t."), rounded to cents, for queries that list transactions.
+func (c Conversion) txnAmount(prefix string) string {
+	return "ROUND(" + c.amount(prefix+"amount", prefix+"date") + ", 2)::float8"
+}
+
+// rate returns the conversion rate on a day; 1 when nothing is converted. It is used
+// for amounts without a transaction date, such as subscriptions and income sources.
+func (c Conversion) rate(ctx context.Context, q querier, on time.Time) (float64, error) {
+	if !c.Active() {
+		return 1, nil
+	}
+	var v float64
+	err := q.QueryRow(ctx, `SELECT fx_rate($1, $2, $3::date)::float8`, c.from, c.to, on).Scan(&v)
+	return v, err
+}
+
+// In returns a copy of the repository reporting amounts converted by conv.
+func (r *ReportRepo) In(conv Conversion) *ReportRepo {
+	cp := *r
+	cp.conv = conv
+	return &cp
+}
+
+// In returns a copy of the repository reporting amounts converted by conv.
+func (r *DashboardRepo) In(conv Conversion) *DashboardRepo {
+	cp := *r
+	cp.conv = conv
+	return &cp
+}
+
+// In returns a copy of the repository projecting income converted by conv.
+func (r *IncomeRepo) In(conv Conversion) *IncomeRepo {
+	cp := *r
+	cp.conv = conv
+	return &cp
+}
+
+// In returns a copy of the repository writing statements converted by conv.
+func (r *AccountRepo) In(conv Conversion) *AccountRepo {
+	cp := *r
+	cp.conv = conv
+	return &cp
+}
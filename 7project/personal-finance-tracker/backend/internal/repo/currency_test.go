@@ -0,0 +1,42 @@
+// backend/internal/repo/currency_test.go
+//
+// Purpose:
+//   Verify display currency conversions only accept currency codes and leave queries
+//   on monthly_totals when nothing is converted.
+
+package repo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewConversion(t *testing.T) {
+	for _, tc := range []struct {
+		base, display string
+		ok, active    bool
+	}{
+		{"EUR", "USD", true, true},
+		{"EUR", "EUR", true, false},
+		{"EUR", "usd", false, false},
+		{"EUR", "US'); DROP TABLE users; --", false, false},
+		{"", "USD", false, false},
+	} {
+		c, ok := NewConversion(tc.base, tc.display)
+		if ok != tc.ok || c.Active() != tc.active {
+			t.Errorf("NewConversion(%q, %q) = %+v, %v; want ok=%v active=%v", tc.base, tc.display, c, ok, tc.ok, tc.active)
+		}
+	}
+
+	var none Conversion
+	if none.totals() != "monthly_totals" || none.amount("t.amount", "t.date") != "t.amount" {
+		t.Errorf("zero Conversion rewrites queries: %q, %q", none.totals(), none.amount("t.amount", "t.date"))
+	}
+	c, _ := NewConversion("EUR", "USD")
+	if got := c.amount("t.amount", "t.date"); got != "t.amount * fx_rate('EUR', 'USD', t.date)" {
+		t.Errorf("amount = %q", got)
+	}
+	if got := c.totals(); !strings.Contains(got, "FROM transactions") || !strings.Contains(got, "fx_rate('EUR', 'USD', date)") {
+		t.Errorf("totals = %q", got)
+	}
+}
@@ -4,47 +4,529 @@ package repo
 
 import (
 	"context"
+	"fmt"
+	"math/big"
 	"time"
-
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // MonthSummary aggregates totals for a given month.
 // - Month: string period identifier in YYYY-MM
 // - IncomeTotal/ExpenseTotal: summed amounts by type
+// - SavingsRate: (income - expenses) / income for the month; nil when there is no income
+// - SavingsRateAvg3/SavingsRateAvg6: trailing 3/6-month averages of the monthly rate (months without income are skipped)
+// - BudgetAdherence: how well the month's budgets were respected; nil when no budgets exist
+// - SubscriptionsMonthly: current total of all subscriptions converted to a monthly cost
 type MonthSummary struct {
-	Month        string  `json:"month"` // YYYY-MM
-	IncomeTotal  float64 `json:"income_total"`
-	ExpenseTotal float64 `json:"expense_total"`
+	Month                string           `json:"month"` // YYYY-MM
+	IncomeTotal          float64          `json:"income_total"`
+	ExpenseTotal         float64          `json:"expense_total"`
+	SavingsRate          *float64         `json:"savings_rate"`
+	SavingsRateAvg3      *float64         `json:"savings_rate_avg_3m"`
+	SavingsRateAvg6      *float64         `json:"savings_rate_avg_6m"`
+	BudgetAdherence      *BudgetAdherence `json:"budget_adherence"`
+	SubscriptionsMonthly float64          `json:"subscriptions_monthly"`
+}
+
+// BudgetAdherence summarizes budget performance for a month.
+// - Score: 0..100; each budget scores 100 when respected and loses points in proportion to its overrun
+// - Respected/Total: number of budgets kept within their limit
+// - AvgMarginPct: mean remaining headroom as a percentage of the limit (negative when overspent)
+type BudgetAdherence struct {
+	Score        float64 `json:"score"`
+	Respected    int     `json:"respected"`
+	Total        int     `json:"total"`
+	AvgMarginPct float64 `json:"avg_margin_pct"`
 }
 
 // DashboardRepo provides read-only aggregation queries for dashboard views.
-type DashboardRepo struct{ pool *pgxpool.Pool }
+// Aggregations run on read (the replica when configured); the layout is read and written on pool.
+// Amounts are in the user's base currency unless converted (see In). Budget adherence
+// and the listed top expenses always stay in the base currency their limits and
+// transactions were recorded in.
+type DashboardRepo struct {
+	pool  dbConn
+	read  querier
+	crypt *fieldCrypt
+	conv  Conversion
+}
 
 // DashboardRepo accessor bound to the Store's connection pool.
-func (s *Store) DashboardRepo() *DashboardRepo { return &DashboardRepo{pool: s.Pool} }
+func (s *Store) DashboardRepo() *DashboardRepo {
+	return &DashboardRepo{pool: s.db(), read: s.reader(), crypt: s.crypt}
+}
 
 // Summary returns income and expense totals for a specific month.
 // The month parameter should be in YYYY-MM format.
-// Computes an inclusive date range [first day, last instant of month] and
-// executes a single SQL query using conditional aggregation.
+// Totals are read from the trigger-maintained monthly_totals table rather than
+// aggregating raw transactions on every dashboard load. Subscriptions have no
+// transaction date and are converted at today's rate.
 func (r *DashboardRepo) Summary(ctx context.Context, userID int64, month string) (*MonthSummary, error) {
 	// Derive the first day of the month; ignore parse error since month is validated upstream.
 	first, _ := time.Parse("2006-01", month)
-	// Compute the last instant of the month: start of next month minus 1ns.
-	last := first.AddDate(0, 1, 0).Add(-time.Nanosecond)
 
-	const q = `
+	q := `
 SELECT
-	COALESCE(SUM(CASE WHEN type='income' THEN amount END),0) AS income_total,
-	COALESCE(SUM(CASE WHEN type='expense' THEN amount END),0) AS expense_total
-FROM transactions
-WHERE user_id=$1 AND date >= $2 AND date <= $3
+	COALESCE(SUM(CASE WHEN type='income' THEN total END),0) AS income_total,
+	COALESCE(SUM(CASE WHEN type='expense' THEN total END),0) AS expense_total
+FROM ` + r.conv.totals() + ` mt
+WHERE user_id=$1 AND month=$2
 `
 	var m MonthSummary
 	m.Month = month
-	if err := r.pool.QueryRow(ctx, q, userID, first, last).Scan(&m.IncomeTotal, &m.ExpenseTotal); err != nil {
+	if err := r.read.QueryRow(ctx, q, userID, first).Scan(&m.IncomeTotal, &m.ExpenseTotal); err != nil {
+		return nil, err
+	}
+	if err := r.savingsRates(ctx, userID, first, &m); err != nil {
+		return nil, err
+	}
+	adh, err := r.adherence(ctx, userID, month, first)
+	if err != nil {
+		return nil, err
+	}
+	m.BudgetAdherence = adh
+	subs, err := (&SubscriptionRepo{pool: r.pool}).MonthlyTotal(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := r.conv.rate(ctx, r.read, time.Now().UTC())
+	if err != nil {
 		return nil, err
 	}
+	m.SubscriptionsMonthly = round2(subs * rate)
 	return &m, nil
 }
+
+// adherence loads each budget of the month with its actual spend and scores them.
+// A group's budget counts its member categories; one with neither category nor group
+// is compared against all expenses of the month.
+func (r *DashboardRepo) adherence(ctx context.Context, userID int64, month string, first time.Time) (*BudgetAdherence, error) {
+	const q = `
+SELECT b.limit_amount,
+       COALESCE((
+           SELECT SUM(mt.total) FROM monthly_totals mt
+           WHERE mt.user_id = b.user_id AND mt.type='expense' AND mt.month = $3
+             AND ` + budgetCovers + `
+       ), 0)
+FROM budgets b
+WHERE b.user_id=$1 AND b.period_month=$2 AND b.deleted_at IS NULL
+`
+	rows, err := r.read.Query(ctx, q, userID, month, first)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var limits, spent []float64
+	for rows.Next() {
+		var l, s float64
+		if err := rows.Scan(&l, &s); err != nil {
+			return nil, err
+		}
+		limits = append(limits, l)
+		spent = append(spent, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return scoreAdherence(limits, spent), nil
+}
+
+// scoreAdherence computes a BudgetAdherence from parallel slices of limits and actual spend.
+// Returns nil when there are no budgets. Zero limits count as respected only when nothing was spent.
+func scoreAdherence(limits, spent []float64) *BudgetAdherence {
+	if len(limits) == 0 {
+		return nil
+	}
+	a := &BudgetAdherence{Total: len(limits)}
+	var scoreSum, marginSum float64
+	for i, l := range limits {
+		s := spent[i]
+		if s <= l {
+			a.Respected++
+		}
+		if l <= 0 {
+			if s <= 0 {
+				scoreSum += 100
+			}
+			continue
+		}
+		margin := (l - s) / l
+		marginSum += margin * 100
+		switch {
+		case margin >= 0:
+			scoreSum += 100
+		case margin > -1:
+			scoreSum += 100 * (1 + margin)
+		}
+	}
+	a.Score = round2(scoreSum / float64(len(limits)))
+	a.AvgMarginPct = round2(marginSum / float64(len(limits)))
+	return a
+}
+
+// savingsRates fills the savings rate fields of m for the month starting at first
+// from the income and expenses of it and the five months before. Reimbursed expenses
+// are already left out of the totals (see the monthly_totals trigger). The NUMERIC
+// totals are read as text so the rates are computed on exact decimals.
+func (r *DashboardRepo) savingsRates(ctx context.Context, userID int64, first time.Time, m *MonthSummary) error {
+	q := `
+SELECT month,
+       COALESCE(SUM(CASE WHEN type='income' THEN total ELSE 0 END), 0)::text,
+       COALESCE(SUM(CASE WHEN type='expense' THEN total ELSE 0 END), 0)::text
+FROM ` + r.conv.totals() + ` mt
+WHERE user_id=$1 AND month >= $2 AND month < $3
+GROUP BY 1
+`
+	rows, err := r.read.Query(ctx, q, userID, first.AddDate(0, -5, 0), first.AddDate(0, 1, 0))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var flows []monthFlow
+	for rows.Next() {
+		var f monthFlow
+		var income, expense string
+		if err := rows.Scan(&f.Month, &income, &expense); err != nil {
+			return err
+		}
+		var ok1, ok2 bool
+		f.Income, ok1 = new(big.Rat).SetString(income)
+		f.Expense, ok2 = new(big.Rat).SetString(expense)
+		if !ok1 || !ok2 {
+			return fmt.Errorf("savings rate: bad totals %q, %q", income, expense)
+		}
+		flows = append(flows, f)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rate, avg3, avg6 := savingsRates(flows, first)
+	m.SavingsRate, m.SavingsRateAvg3, m.SavingsRateAvg6 = ratFloat(rate), ratFloat(avg3), ratFloat(avg6)
+	return nil
+}
+
+// monthFlow is the income and expense total of one month.
+type monthFlow struct {
+	Month           time.Time
+	Income, Expense *big.Rat
+}
+
+// savingsRates returns the savings rate, (income - expenses) / income, of the month
+// starting at first and its averages over the trailing 3 and 6 months, rounded to four
+// decimal places (halves away from zero, like ROUND on NUMERIC). The rate is negative
+// when more was spent than earned. Months without income have no rate and are skipped
+// by the averages; nil when no month has one.
+func savingsRates(flows []monthFlow, first time.Time) (rate, avg3, avg6 *big.Rat) {
+	sum3, sum6 := new(big.Rat), new(big.Rat)
+	var n3, n6 int64
+	for _, f := range flows {
+		if f.Income.Sign() == 0 || f.Month.After(first) || f.Month.Before(first.AddDate(0, -5, 0)) {
+			continue
+		}
+		v := new(big.Rat).Sub(f.Income, f.Expense)
+		v.Quo(v, f.Income)
+		if f.Month.Equal(first) {
+			rate = roundRat4(v)
+		}
+		if f.Month.After(first.AddDate(0, -3, 0)) {
+			sum3.Add(sum3, v)
+			n3++
+		}
+		sum6.Add(sum6, v)
+		n6++
+	}
+	if n3 > 0 {
+		avg3 = roundRat4(sum3.Quo(sum3, big.NewRat(n3, 1)))
+	}
+	if n6 > 0 {
+		avg6 = roundRat4(sum6.Quo(sum6, big.NewRat(n6, 1)))
+	}
+	return rate, avg3, avg6
+}
+
+// roundRat4 rounds v to four decimal places, halves away from zero.
+func roundRat4(v *big.Rat) *big.Rat {
+	out, _ := new(big.Rat).SetString(v.FloatString(4))
+	return out
+}
+
+// ratFloat converts a rounded decimal for the JSON response; nil stays nil.
+func ratFloat(v *big.Rat) *float64 {
+	if v == nil {
+		return nil
+	}
+	f, _ := v.Float64()
+	return &f
+}
+
+// DailySpend is the total expense amount for a single calendar day.
+type DailySpend struct {
+	Date  string  `json:"date"` // YYYY-MM-DD
+	Total float64 `json:"total"`
+}
+
+// Daily returns total expenses per day for a month (YYYY-MM), one entry per calendar day.
+// Days without expenses are included with a zero total so clients can render a full calendar grid.
+// Reimbursed expenses are excluded here and in the other spending queries below.
+func (r *DashboardRepo) Daily(ctx context.Context, userID int64, month string) ([]DailySpend, error) {
+	first, next, err := MonthBounds(month)
+	if err != nil {
+		return nil, err
+	}
+
+	q := `
+SELECT date, ROUND(SUM(` + r.conv.amount("amount", "date") + `), 2)
+FROM transactions
+WHERE user_id=$1 AND type='expense' AND NOT reimbursed AND date >= $2 AND date < $3
+GROUP BY date
+`
+	rows, err := r.read.Query(ctx, q, userID, first, next)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := map[string]float64{}
+	for rows.Next() {
+		var d time.Time
+		var total float64
+		if err := rows.Scan(&d, &total); err != nil {
+			return nil, err
+		}
+		totals[d.Format("2006-01-02")] = total
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]DailySpend, 0, 31)
+	for d := first; d.Before(next); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		out = append(out, DailySpend{Date: key, Total: totals[key]})
+	}
+	return out, nil
+}
+
+// TopExpense is an expense transaction enriched with its category name.
+// CategoryName is empty for uncategorized transactions.
+type TopExpense struct {
+	Transaction
+	CategoryName string `json:"category_name"`
+}
+
+// TopExpenses returns the n largest expenses of a month (YYYY-MM), largest first.
+// Ties are broken by date and id so the ordering is stable across calls.
+func (r *DashboardRepo) TopExpenses(ctx context.Context, userID int64, month string, n int) ([]TopExpense, error) {
+	first, next, err := MonthBounds(month)
+	if err != nil {
+		return nil, err
+	}
+
+	q := `
+SELECT ` + txnColsT + `,
+       COALESCE(c.name, ''), ` + r.conv.txnAmount("t.") + ` AS amt
+FROM transactions t
+LEFT JOIN categories c ON c.id = t.category_id AND c.user_id = t.user_id
+WHERE t.user_id=$1 AND t.type='expense' AND NOT t.reimbursed AND t.date >= $2 AND t.date < $3
+ORDER BY amt DESC, t.date DESC, t.id DESC
+LIMIT $4
+`
+	rows, err := r.read.Query(ctx, q, userID, first, next, n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []TopExpense{}
+	for rows.Next() {
+		var t TopExpense
+		var amt float64
+		if err := rows.Scan(append(t.scanDest(), &t.CategoryName, &amt)...); err != nil {
+			return nil, err
+		}
+		t.Amount = amt
+		if err := r.crypt.open(ctx, userID, &t.Description); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// Projection forecasts the end-of-month position for a month.
+// - Income/ExpenseToDate: transactions dated up to and including AsOf
+// - Income/ExpenseScheduled: transactions already entered with a date after AsOf (scheduled items)
+// - IncomeRecurring: payments expected from income sources and income rules after AsOf
+// - ExpenseRecurring: occurrences of expense rules and bills due after AsOf
+// - AvgDailySpend: mean daily discretionary expense over the 90 days before AsOf, without rule-generated transactions and bill categories
+// - RemainingDays: days of the month after AsOf
+// - ProjectedIncome/ProjectedExpense/ProjectedNet: expected totals at month end
+type Projection struct {
+	Month            string  `json:"month"` // YYYY-MM
+	AsOf             string  `json:"as_of"` // YYYY-MM-DD
+	IncomeToDate     float64 `json:"income_to_date"`
+	ExpenseToDate    float64 `json:"expense_to_date"`
+	IncomeScheduled  float64 `json:"income_scheduled"`
+	ExpenseScheduled float64 `json:"expense_scheduled"`
+	IncomeRecurring  float64 `json:"income_recurring"`
+	ExpenseRecurring float64 `json:"expense_recurring"`
+	AvgDailySpend    float64 `json:"avg_daily_spend"`
+	RemainingDays    int     `json:"remaining_days"`
+	ProjectedIncome  float64 `json:"projected_income"`
+	ProjectedExpense float64 `json:"projected_expense"`
+	ProjectedNet     float64 `json:"projected_net"`
+}
+
+// projectionLookbackDays is the window used to derive the average daily discretionary spend.
+const projectionLookbackDays = 90
+
+// Projection computes the end-of-month forecast for month (YYYY-MM) as seen on asOf.
+// Past months resolve to their actual totals; future months are projected entirely
+// from scheduled items, fixed costs and the average daily spend.
+func (r *DashboardRepo) Projection(ctx context.Context, userID int64, month string, asOf time.Time) (*Projection, error) {
+	first, next, err := MonthBounds(month)
+	if err != nil {
+		return nil, err
+	}
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	lookback := asOf.AddDate(0, 0, -projectionLookbackDays)
+
+	// Bills with a category are fixed costs: their payments are projected rather than averaged.
+	bills, err := (&BillRepo{pool: r.pool}).List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	billCats := []int64{}
+	for _, b := range bills {
+		if b.CategoryID != nil {
+			billCats = append(billCats, *b.CategoryID)
+		}
+	}
+
+	amt := r.conv.amount("amount", "date")
+	q := `
+SELECT
+	ROUND(COALESCE(SUM(` + amt + `) FILTER (WHERE type='income'  AND date >= $2 AND date < $3 AND date <= $4), 0), 2),
+	ROUND(COALESCE(SUM(` + amt + `) FILTER (WHERE type='expense' AND NOT reimbursed AND date >= $2 AND date < $3 AND date <= $4), 0), 2),
+	ROUND(COALESCE(SUM(` + amt + `) FILTER (WHERE type='income'  AND date >= $2 AND date < $3 AND date > $4), 0), 2),
+	ROUND(COALESCE(SUM(` + amt + `) FILTER (WHERE type='expense' AND NOT reimbursed AND date >= $2 AND date < $3 AND date > $4), 0), 2),
+	ROUND(COALESCE(SUM(` + amt + `) FILTER (WHERE type='expense' AND NOT reimbursed AND date >= $5 AND date < $4
+	                                        AND recurring_rule_id IS NULL AND (category_id IS NULL OR category_id <> ALL($6))), 0), 2)
+FROM transactions
+WHERE user_id=$1 AND ((date >= $2 AND date < $3) OR (date >= $5 AND date < $4))
+`
+	p := Projection{Month: month, AsOf: asOf.Format("2006-01-02")}
+	var lookbackSpend float64
+	if err := r.read.QueryRow(ctx, q, userID, first, next, asOf, lookback, billCats).Scan(
+		&p.IncomeToDate, &p.ExpenseToDate, &p.IncomeScheduled, &p.ExpenseScheduled, &lookbackSpend,
+	); err != nil {
+		return nil, err
+	}
+
+	// Days of the month still ahead of asOf (exclusive of asOf itself).
+	switch {
+	case !asOf.Before(next.AddDate(0, 0, -1)):
+		p.RemainingDays = 0
+	case asOf.Before(first):
+		p.RemainingDays = int(next.Sub(first).Hours() / 24)
+	default:
+		p.RemainingDays = int(next.Sub(asOf).Hours()/24) - 1
+	}
+
+	// Income sources contribute their pay dates still ahead of asOf within the month.
+	sources, err := (&IncomeRepo{pool: r.pool}).List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	from := first
+	if !asOf.Before(first) {
+		from = asOf.AddDate(0, 0, 1)
+	}
+	for _, s := range sources {
+		p.IncomeRecurring += s.Amount * float64(len(IncomeOccurrences(&s, from, next)))
+	}
+	// Active recurring rules add the occurrences they have not generated yet.
+	rules, err := (&RecurringRuleRepo{pool: r.pool}).List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ruleIncome, fixed := fixedCosts(rules, bills, from, next)
+	p.IncomeRecurring += ruleIncome
+	// Income sources, rules and bills have no transaction date and convert at the rate as of asOf.
+	rate, err := r.conv.rate(ctx, r.read, asOf)
+	if err != nil {
+		return nil, err
+	}
+	p.IncomeRecurring = round2(p.IncomeRecurring * rate)
+	p.ExpenseRecurring = round2(fixed * rate)
+
+	p.AvgDailySpend = round2(lookbackSpend / projectionLookbackDays)
+	p.ProjectedIncome = round2(p.IncomeToDate + p.IncomeScheduled + p.IncomeRecurring)
+	p.ProjectedExpense = round2(p.ExpenseToDate + p.ExpenseScheduled + p.ExpenseRecurring + p.AvgDailySpend*float64(p.RemainingDays))
+	p.ProjectedNet = round2(p.ProjectedIncome - p.ProjectedExpense)
+	return &p, nil
+}
+
+// fixedCosts sums what active recurring rules and bills with a category add in
+// [from, next): rule income, and rule and bill expenses. Rule occurrences already
+// generated are transactions and not counted again.
+func fixedCosts(rules []RecurringRule, bills []Bill, from, next time.Time) (income, expense float64) {
+	if !from.Before(next) {
+		return 0, 0
+	}
+	last := next.AddDate(0, 0, -1)
+	for _, rr := range rules {
+		if !rr.Active {
+			continue
+		}
+		for _, o := range Preview(&rr, last).Occurrences {
+			if o.Date < from.Format("2006-01-02") {
+				continue
+			}
+			if rr.Type == "income" {
+				income += rr.Amount
+			} else {
+				expense += rr.Amount
+			}
+		}
+	}
+	for _, b := range UpcomingBills(bills, from, int(last.Sub(from).Hours()/24)) {
+		if b.CategoryID != nil {
+			expense += b.Amount
+		}
+	}
+	return income, expense
+}
+
+// WeekSummary aggregates totals for a seven-day week.
+// - WeekStart/WeekEnd: inclusive bounds in YYYY-MM-DD
+// - IncomeTotal/ExpenseTotal: summed amounts by type
+type WeekSummary struct {
+	WeekStart    string  `json:"week_start"`
+	WeekEnd      string  `json:"week_end"`
+	IncomeTotal  float64 `json:"income_total"`
+	ExpenseTotal float64 `json:"expense_total"`
+}
+
+// WeekSummary returns income and expense totals for the seven days starting at start.
+// Weeks may straddle months, so totals are aggregated from raw transactions.
+func (r *DashboardRepo) WeekSummary(ctx context.Context, userID int64, start time.Time) (*WeekSummary, error) {
+	next := start.AddDate(0, 0, 7)
+
+	amt := r.conv.amount("amount", "date")
+	q := `
+SELECT
+	ROUND(COALESCE(SUM(CASE WHEN type='income' THEN ` + amt + ` END),0), 2) AS income_total,
+	ROUND(COALESCE(SUM(CASE WHEN type='expense' AND NOT reimbursed THEN ` + amt + ` END),0), 2) AS expense_total
+FROM transactions
+WHERE user_id=$1 AND date >= $2 AND date < $3
+`
+	w := WeekSummary{
+		WeekStart: start.Format("2006-01-02"),
+		WeekEnd:   next.AddDate(0, 0, -1).Format("2006-01-02"),
+	}
+	if err := r.read.QueryRow(ctx, q, userID, start, next).Scan(&w.IncomeTotal, &w.ExpenseTotal); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
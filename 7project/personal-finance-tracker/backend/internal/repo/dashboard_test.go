@@ -0,0 +1,92 @@
+// backend/internal/repo/dashboard_test.go
+//
+// Purpose:
+//   Verify the savings rate of a month and its trailing averages: months without
+//   income have no rate, overspending gives a negative one, months outside the
+//   window are ignored, and the results are exact decimals rounded like NUMERIC. Verify that the projection counts only the rule and bill
+//   occurrences still to come in the month.
+
+package repo
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func month(s string) time.Time {
+	m, _ := time.Parse("2006-01", s)
+	return m
+}
+
+func TestSavingsRates(t *testing.T) {
+	flow := func(m, income, expense string) monthFlow {
+		in, _ := new(big.Rat).SetString(income)
+		ex, _ := new(big.Rat).SetString(expense)
+		return monthFlow{Month: month(m), Income: in, Expense: ex}
+	}
+	dec := func(v *big.Rat) string {
+		if v == nil {
+			return "nil"
+		}
+		return v.FloatString(4)
+	}
+	flows := []monthFlow{
+		flow("2026-03", "1000", "0"),    // 1, only in July's 6-month window
+		flow("2026-05", "2000", "1000"), // 0.5
+		flow("2026-07", "0", "300"),     // no income: no rate
+		flow("2026-08", "3000", "2000"), // 1/3
+		flow("2026-09", "1000", "1500"), // -0.5
+		flow("2026-11", "1000", "0"),    // after the month
+	}
+	rate, avg3, avg6 := savingsRates(flows, month("2026-09"))
+	if got := dec(rate) + " " + dec(avg3) + " " + dec(avg6); got != "-0.5000 -0.0833 0.1111" {
+		t.Errorf("rates = %s; want -0.5000 -0.0833 0.1111", got)
+	}
+
+	rate, avg3, avg6 = savingsRates(flows, month("2026-07"))
+	if got := dec(rate) + " " + dec(avg3) + " " + dec(avg6); got != "nil 0.5000 0.7500" {
+		t.Errorf("month without income: %s; want nil 0.5000 0.7500", got)
+	}
+
+	// 0.02 of 400.00 saved is exactly 0.00005, which rounds away from zero like ROUND
+	// on NUMERIC; with float64 the difference comes out as 0.0199999... and rounds to 0.
+	rate, _, _ = savingsRates([]monthFlow{flow("2026-09", "400.00", "399.98")}, month("2026-09"))
+	if rate.Cmp(big.NewRat(1, 10000)) != 0 {
+		t.Errorf("rate = %s; want exactly 0.0001", dec(rate))
+	}
+	rate, _, _ = savingsRates([]monthFlow{flow("2026-09", "0.30", "0.10")}, month("2026-09"))
+	if rate.Cmp(big.NewRat(6667, 10000)) != 0 {
+		t.Errorf("rate = %s; want exactly 0.6667", dec(rate))
+	}
+
+	if rate, avg3, avg6 := savingsRates(nil, month("2026-09")); rate != nil || avg3 != nil || avg6 != nil {
+		t.Errorf("no income at all: %v, %v, %v; want nil", rate, avg3, avg6)
+	}
+}
+
+func TestFixedCosts(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	day16, cat := day("2026-10-16"), int64(7)
+	rules := []RecurringRule{
+		{Type: "expense", Amount: 900, Interval: "monthly", StartDate: day("2026-01-20"), Active: true, GeneratedThrough: &day16}, // 20th
+		{Type: "income", Amount: 100, Interval: "weekly", StartDate: day("2026-10-02"), Active: true, GeneratedThrough: &day16},   // 23rd and 30th
+		{Type: "expense", Amount: 50, Interval: "monthly", StartDate: day("2026-10-25")},                                          // inactive
+		{Type: "expense", Amount: 30, Interval: "monthly", StartDate: day("2026-09-05"), Active: true},                            // due before from
+	}
+	bills := []Bill{
+		{CategoryID: &cat, Amount: 60, DueDay: 28},
+		{Amount: 40, DueDay: 20}, // no category: its payments stay in the average
+		{CategoryID: &cat, Amount: 10, DueDay: 5},
+	}
+	income, expense := fixedCosts(rules, bills, day("2026-10-17"), day("2026-11-01"))
+	if income != 200 || expense != 960 {
+		t.Errorf("fixedCosts = %v, %v; want 200, 960", income, expense)
+	}
+	if income, expense := fixedCosts(rules, bills, day("2026-11-01"), day("2026-11-01")); income != 0 || expense != 0 {
+		t.Errorf("no days left: %v, %v; want 0", income, expense)
+	}
+}
@@ -2,16 +2,170 @@
 
 package repo
 
-import "github.com/jackc/pgx/v5/pgxpool"
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
 
 // Store wraps a shared pgx connection pool.
 // Repository constructors are exposed as methods on Store,
 // enabling access patterns like api.Repos.UserRepo().
+// An optional read replica serves list and summary queries (see UseReplica), and
+// transaction descriptions can be encrypted at rest (see UseEncryption).
+// Inside WithTx the Store's repos run on one database transaction instead.
 type Store struct {
-	Pool *pgxpool.Pool
+	Pool    *pgxpool.Pool
+	replica *replica
+	crypt   *fieldCrypt
+	tx      pgx.Tx
 }
 
 // New constructs a Store bound to the provided connection pool.
 func New(pool *pgxpool.Pool) *Store {
 	return &Store{Pool: pool}
 }
+
+// dbConn is what repos run statements on; satisfied by both *pgxpool.Pool and pgx.Tx.
+// Begin on a transaction starts a savepoint, so repo methods that use their own
+// transaction still work, nested, inside WithTx.
+type dbConn interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// db returns the transaction inside WithTx, otherwise the pool, through which
+// statements made with a WithContextTx ctx run on that transaction.
+func (s *Store) db() dbConn {
+	if s.tx != nil {
+		return s.tx
+	}
+	return poolConn{s.Pool}
+}
+
+// IsTimeout reports whether err means a statement ran out of time: its context's
+// deadline passed, or Postgres cancelled it (SQLSTATE 57014, e.g. statement_timeout).
+func IsTimeout(err error) bool {
+	var pgerr *pgconn.PgError
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &pgerr) && pgerr.Code == "57014")
+}
+
+type ctxTxKey struct{}
+
+// contextTx returns the transaction ctx carries from WithContextTx, or nil.
+func contextTx(ctx context.Context) pgx.Tx {
+	tx, _ := ctx.Value(ctxTxKey{}).(pgx.Tx)
+	return tx
+}
+
+// InContextTx reports whether ctx carries a WithContextTx transaction, whose writes
+// may still be rolled back.
+func InContextTx(ctx context.Context) bool { return contextTx(ctx) != nil }
+
+// WithContextTx runs fn with a ctx whose database access through any Store on this
+// pool (replica reads included) uses one transaction, committed when fn returns nil
+// and rolled back otherwise. Unlike WithTx it needs no tx-bound Store, so code that
+// only has ctx, such as HTTP handlers, can be made atomic. The transaction is not
+// safe for concurrent use: fn must not share ctx with other goroutines.
+func (s *Store) WithContextTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	tx, err := s.db().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+		if err != nil {
+			if rerr := tx.Rollback(ctx); rerr != nil && !errors.Is(rerr, pgx.ErrTxClosed) {
+				err = errors.Join(err, rerr)
+			}
+		}
+	}()
+	if err = fn(context.WithValue(ctx, ctxTxKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// poolConn runs statements on the pool, or on the ctx's WithContextTx transaction.
+type poolConn struct{ pool *pgxpool.Pool }
+
+func (p poolConn) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if tx := contextTx(ctx); tx != nil {
+		return tx.Exec(ctx, sql, args...)
+	}
+	return p.pool.Exec(ctx, sql, args...)
+}
+
+func (p poolConn) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if tx := contextTx(ctx); tx != nil {
+		return tx.Query(ctx, sql, args...)
+	}
+	return p.pool.Query(ctx, sql, args...)
+}
+
+func (p poolConn) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if tx := contextTx(ctx); tx != nil {
+		return tx.QueryRow(ctx, sql, args...)
+	}
+	return p.pool.QueryRow(ctx, sql, args...)
+}
+
+func (p poolConn) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	if tx := contextTx(ctx); tx != nil {
+		return tx.SendBatch(ctx, b)
+	}
+	return p.pool.SendBatch(ctx, b)
+}
+
+func (p poolConn) Begin(ctx context.Context) (pgx.Tx, error) {
+	if tx := contextTx(ctx); tx != nil {
+		return tx.Begin(ctx)
+	}
+	return p.pool.Begin(ctx)
+}
+
+// WithTx runs fn with a Store whose repos all use one database transaction, committed
+// when fn returns nil and rolled back when it returns an error or panics, so operations
+// spanning several repos are atomic:
+//
+//	err := store.WithTx(ctx, func(tx *repo.Store) error {
+//		if _, err := tx.TransactionRepo().Create(ctx, t); err != nil {
+//			return err
+//		}
+//		_, err := tx.ImportRepo().Progress(ctx, id, p)
+//		return err
+//	})
+//
+// Reads inside fn see the transaction's own writes (the replica is not used). Calls
+// nest: WithTx on the Store passed to fn uses a savepoint. BackupRepo always uses the
+// pool. Do not use the Store passed to fn after fn returns.
+func (s *Store) WithTx(ctx context.Context, fn func(tx *Store) error) (err error) {
+	tx, err := s.db().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+		if err != nil {
+			if rerr := tx.Rollback(ctx); rerr != nil && !errors.Is(rerr, pgx.ErrTxClosed) {
+				err = errors.Join(err, rerr)
+			}
+		}
+	}()
+	if err = fn(&Store{Pool: s.Pool, crypt: s.crypt, tx: tx}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,84 @@
+// backend/internal/repo/device.go
+
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Device is a mobile device registered for push notifications (see migration 032).
+// - Platform: "fcm" (Firebase Cloud Messaging: Android, web) or "apns" (iOS)
+// - Token: the registration token issued to the app by the platform
+type Device struct {
+	ID         int64     `json:"id"`
+	UserID     int64     `json:"user_id"`
+	Platform   string    `json:"platform"`
+	Token      string    `json:"token"`
+	Name       string    `json:"name"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// DeviceRepo stores push notification devices.
+type DeviceRepo struct{ pool dbConn }
+
+// DeviceRepo accessor bound to the Store's pool.
+func (s *Store) DeviceRepo() *DeviceRepo { return &DeviceRepo{pool: s.db()} }
+
+const deviceCols = `id, user_id, platform, token, name, created_at, last_seen_at`
+
+func scanDevice(row pgx.Row) (*Device, error) {
+	var d Device
+	if err := row.Scan(&d.ID, &d.UserID, &d.Platform, &d.Token, &d.Name, &d.CreatedAt, &d.LastSeenAt); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// List returns the user's devices, most recently seen first.
+func (r *DeviceRepo) List(ctx context.Context, userID int64) ([]Device, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+deviceCols+` FROM push_devices WHERE user_id=$1 ORDER BY last_seen_at DESC, id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Device{}
+	for rows.Next() {
+		d, err := scanDevice(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *d)
+	}
+	return out, rows.Err()
+}
+
+// Register stores the device, or refreshes it when the user already registered the
+// token; apps register on every start.
+func (r *DeviceRepo) Register(ctx context.Context, d *Device) (*Device, error) {
+	const q = `INSERT INTO push_devices (user_id, platform, token, name)
+	           VALUES ($1, $2, $3, $4)
+	           ON CONFLICT (user_id, token) DO UPDATE
+	           SET platform=EXCLUDED.platform, name=EXCLUDED.name, last_seen_at=NOW()
+	           RETURNING ` + deviceCols
+	return scanDevice(r.pool.QueryRow(ctx, q, d.UserID, d.Platform, d.Token, d.Name))
+}
+
+// Delete unregisters a device of the user.
+func (r *DeviceRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM push_devices WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// DeleteToken removes the user's device whose token the platform reported as no
+// longer valid.
+func (r *DeviceRepo) DeleteToken(ctx context.Context, userID int64, token string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM push_devices WHERE user_id=$1 AND token=$2`, userID, token)
+	return err
+}
@@ -0,0 +1,152 @@
+// backend/internal/repo/draft.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Draft sources. Integrations that read transactions from elsewhere (receipt OCR,
+// bank feeds) add their own.
+const (
+	DraftSourceEmail  = "email"
+	DraftSourceImport = "import"
+)
+
+// Draft is a transaction awaiting the user's review (draft_transactions). It does not
+// count in balances or reports until approved, which turns it into a Transaction.
+// - Source: where the draft came from, e.g. "email" or "import"
+// - ExternalID: its identity at the source (the Message-ID of an email, "<import id>:<line>"); nil when none
+// - Note: context for the review, such as the sender and subject of the email
+type Draft struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	Source      string    `json:"source"`
+	ExternalID  *string   `json:"external_id"`
+	CategoryID  *int64    `json:"category_id"`
+	Type        string    `json:"type"` // "income" | "expense"
+	Amount      float64   `json:"amount"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	Note        string    `json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DraftRepo stores draft transactions.
+type DraftRepo struct{ pool dbConn }
+
+// DraftRepo accessor bound to the Store's pool.
+func (s *Store) DraftRepo() *DraftRepo { return &DraftRepo{pool: s.db()} }
+
+const draftCols = `id, user_id, source, external_id, category_id, type, amount, date, description, note, created_at, updated_at`
+
+func scanDraft(row pgx.Row) (*Draft, error) {
+	var d Draft
+	if err := row.Scan(&d.ID, &d.UserID, &d.Source, &d.ExternalID, &d.CategoryID, &d.Type, &d.Amount, &d.Date,
+		&d.Description, &d.Note, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// List returns the user's drafts, oldest first, only those from source unless it is "".
+func (r *DraftRepo) List(ctx context.Context, userID int64, source string) ([]Draft, error) {
+	const q = `SELECT ` + draftCols + ` FROM draft_transactions
+	           WHERE user_id=$1 AND ($2 = '' OR source = $2)
+	           ORDER BY created_at, id`
+	rows, err := r.pool.Query(ctx, q, userID, source)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Draft{}
+	for rows.Next() {
+		d, err := scanDraft(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *d)
+	}
+	return out, rows.Err()
+}
+
+// Create inserts a draft and returns the stored row, or (nil, nil) when a draft with
+// the same source and ExternalID already exists for the user.
+func (r *DraftRepo) Create(ctx context.Context, d *Draft) (*Draft, error) {
+	const q = `INSERT INTO draft_transactions (user_id, source, external_id, category_id, type, amount, date, description, note)
+	           VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+	           ON CONFLICT (user_id, source, external_id) WHERE external_id IS NOT NULL DO NOTHING
+	           RETURNING ` + draftCols
+	out, err := scanDraft(r.pool.QueryRow(ctx, q, d.UserID, d.Source, d.ExternalID, d.CategoryID, d.Type, d.Amount, d.Date,
+		d.Description, d.Note))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// Update edits a draft owned by the user before it is approved. Returns (nil, nil)
+// when not found.
+func (r *DraftRepo) Update(ctx context.Context, userID, id int64, d *Draft) (*Draft, error) {
+	const q = `UPDATE draft_transactions
+	           SET category_id=$3, type=$4, amount=$5, date=$6, description=$7
+	           WHERE user_id=$1 AND id=$2
+	           RETURNING ` + draftCols
+	out, err := scanDraft(r.pool.QueryRow(ctx, q, userID, id, d.CategoryID, d.Type, d.Amount, d.Date, d.Description))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// Delete discards a draft scoped to the user.
+func (r *DraftRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM draft_transactions WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// take removes a draft and returns it, or (nil, nil) when not found.
+func (r *DraftRepo) take(ctx context.Context, userID, id int64) (*Draft, error) {
+	d, err := scanDraft(r.pool.QueryRow(ctx, `DELETE FROM draft_transactions WHERE user_id=$1 AND id=$2 RETURNING `+draftCols, userID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return d, err
+}
+
+// ApproveDraft turns a draft into a transaction, with categoryID when not nil instead
+// of the draft's category; override saves it above the category's "block" limit. The
+// draft is removed in the same database transaction, so it is approved at most once.
+// Returns (nil, nil) when the draft does not exist, and ErrPeriodClosed or a
+// *LimitError, keeping the draft, when its date falls in a closed month or it exceeds
+// its category's limit.
+func (s *Store) ApproveDraft(ctx context.Context, userID, id int64, categoryID *int64, override bool) (*Transaction, error) {
+	var out *Transaction
+	err := s.WithTx(ctx, func(tx *Store) error {
+		d, err := tx.DraftRepo().take(ctx, userID, id)
+		if err != nil || d == nil {
+			return err
+		}
+		if categoryID == nil {
+			categoryID = d.CategoryID
+		}
+		out, err = tx.TransactionRepo().Create(ctx, &Transaction{
+			UserID: userID, CategoryID: categoryID, Amount: d.Amount, Type: d.Type, Date: d.Date, Description: d.Description,
+			OverrideLimit: override,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
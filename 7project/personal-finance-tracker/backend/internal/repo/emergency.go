@@ -0,0 +1,192 @@
+// backend/internal/repo/emergency.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// FundAccount is an account designated to hold the emergency fund.
+// Balance is maintained by the user.
+type FundAccount struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	Balance   float64   `json:"balance"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EmergencyFund reports how many months of expenses the designated accounts cover.
+// - TargetMonths/TargetAmount: goal in months and in money at the current expense average
+// - Balance: sum of the designated account balances
+// - AvgMonthlyExpense: trailing average over the last six complete months
+// - CoveredMonths/FundedPct: nil while there are no expenses to measure against
+// - Shortfall: money still missing to reach the target (0 once reached)
+type EmergencyFund struct {
+	TargetMonths      float64       `json:"target_months"`
+	TargetAmount      float64       `json:"target_amount"`
+	Balance           float64       `json:"balance"`
+	AvgMonthlyExpense float64       `json:"avg_monthly_expense"`
+	CoveredMonths     *float64      `json:"covered_months"`
+	FundedPct         *float64      `json:"funded_pct"`
+	Shortfall         float64       `json:"shortfall"`
+	Accounts          []FundAccount `json:"accounts"`
+}
+
+// EmergencyFundRepo manages the emergency fund target and designated accounts.
+type EmergencyFundRepo struct{ pool dbConn }
+
+// EmergencyFundRepo accessor bound to the Store's pool.
+func (s *Store) EmergencyFundRepo() *EmergencyFundRepo { return &EmergencyFundRepo{pool: s.db()} }
+
+const fundAccountCols = `id, user_id, name, balance, updated_at, created_at`
+
+func scanFundAccount(row pgx.Row) (*FundAccount, error) {
+	var a FundAccount
+	if err := row.Scan(&a.ID, &a.UserID, &a.Name, &a.Balance, &a.UpdatedAt, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Accounts returns the user's designated accounts ordered by id.
+func (r *EmergencyFundRepo) Accounts(ctx context.Context, userID int64) ([]FundAccount, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+fundAccountCols+` FROM emergency_fund_accounts
+	                                WHERE user_id=$1 ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []FundAccount{}
+	for rows.Next() {
+		a, err := scanFundAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *a)
+	}
+	return out, rows.Err()
+}
+
+// CreateAccount designates a new account for the emergency fund.
+func (r *EmergencyFundRepo) CreateAccount(ctx context.Context, a *FundAccount) (*FundAccount, error) {
+	const q = `INSERT INTO emergency_fund_accounts (user_id, name, balance)
+	           VALUES ($1,$2,$3)
+	           RETURNING ` + fundAccountCols
+	return scanFundAccount(r.pool.QueryRow(ctx, q, a.UserID, a.Name, a.Balance))
+}
+
+// UpdateAccount renames an account or records its new balance.
+// Returns (nil, nil) when the account is not found.
+func (r *EmergencyFundRepo) UpdateAccount(ctx context.Context, userID, id int64, a *FundAccount) (*FundAccount, error) {
+	const q = `UPDATE emergency_fund_accounts
+	           SET name=$3, balance=$4, updated_at=NOW()
+	           WHERE user_id=$1 AND id=$2
+	           RETURNING ` + fundAccountCols
+	out, err := scanFundAccount(r.pool.QueryRow(ctx, q, userID, id, a.Name, a.Balance))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// DeleteAccount removes an account from the emergency fund.
+func (r *EmergencyFundRepo) DeleteAccount(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM emergency_fund_accounts WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// SetTarget stores the target in months. Returns false if the user does not exist.
+func (r *EmergencyFundRepo) SetTarget(ctx context.Context, userID int64, months float64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `UPDATE users SET emergency_fund_months=$2 WHERE id=$1`, userID, months)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// fundTotals returns the target in months, the summed balance and the number of designated accounts.
+func (r *EmergencyFundRepo) fundTotals(ctx context.Context, userID int64) (target, balance float64, accounts int, err error) {
+	const q = `SELECT u.emergency_fund_months::float8,
+	                  COALESCE(SUM(a.balance), 0)::float8,
+	                  COUNT(a.id)
+	           FROM users u
+	           LEFT JOIN emergency_fund_accounts a ON a.user_id = u.id
+	           WHERE u.id=$1
+	           GROUP BY u.id`
+	err = r.pool.QueryRow(ctx, q, userID).Scan(&target, &balance, &accounts)
+	return target, balance, accounts, err
+}
+
+// Coverage reports the fund's coverage as of asOf. Returns (nil, nil) if the user does not exist.
+func (r *EmergencyFundRepo) Coverage(ctx context.Context, userID int64, asOf time.Time) (*EmergencyFund, error) {
+	target, balance, _, err := r.fundTotals(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	first := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, time.UTC)
+	_, exp, err := trailingAverages(ctx, r.pool, userID, first)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := r.Accounts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out := fundCoverage(target, balance, exp)
+	out.Accounts = accounts
+	return out, nil
+}
+
+// fundCoverage derives coverage figures from the target, the fund balance and the average monthly expense.
+func fundCoverage(targetMonths, balance, avgExpense float64) *EmergencyFund {
+	f := &EmergencyFund{
+		TargetMonths:      targetMonths,
+		Balance:           round2(balance),
+		AvgMonthlyExpense: round2(avgExpense),
+		TargetAmount:      round2(targetMonths * avgExpense),
+	}
+	if avgExpense > 0 {
+		f.CoveredMonths = floatPtr(round2(balance / avgExpense))
+		f.FundedPct = floatPtr(round2(balance / f.TargetAmount * 100))
+	}
+	if f.TargetAmount > balance {
+		f.Shortfall = round2(f.TargetAmount - balance)
+	}
+	return f
+}
+
+// trailingAverages returns the average monthly income and expense over the complete
+// months before first, looking back up to healthLookbackMonths. Months without any
+// transactions are skipped so new users are not penalized for missing history.
+func trailingAverages(ctx context.Context, q rowQuerier, userID int64, first time.Time) (inc, exp float64, err error) {
+	const query = `
+SELECT
+	COALESCE(SUM(total) FILTER (WHERE type='income'), 0)::float8,
+	COALESCE(SUM(total) FILTER (WHERE type='expense'), 0)::float8,
+	COUNT(DISTINCT month)
+FROM monthly_totals
+WHERE user_id=$1 AND month >= $2 AND month < $3
+`
+	var months int
+	if err := q.QueryRow(ctx, query, userID, first.AddDate(0, -healthLookbackMonths, 0), first).
+		Scan(&inc, &exp, &months); err != nil {
+		return 0, 0, err
+	}
+	if months == 0 {
+		return 0, 0, nil
+	}
+	return inc / float64(months), exp / float64(months), nil
+}
@@ -0,0 +1,31 @@
+// backend/internal/repo/emergency_test.go
+//
+// Purpose:
+//   Verify emergency fund coverage figures, including the case without expense history.
+
+package repo
+
+import "testing"
+
+func TestFundCoverage(t *testing.T) {
+	f := fundCoverage(6, 4500, 1500)
+	if f.CoveredMonths == nil || *f.CoveredMonths != 3 {
+		t.Fatalf("expected 3 covered months, got %+v", f.CoveredMonths)
+	}
+	if f.TargetAmount != 9000 || f.Shortfall != 4500 {
+		t.Fatalf("unexpected target/shortfall: %+v", f)
+	}
+	if f.FundedPct == nil || *f.FundedPct != 50 {
+		t.Fatalf("expected 50%% funded, got %+v", f.FundedPct)
+	}
+
+	f = fundCoverage(3, 5000, 1000)
+	if f.Shortfall != 0 {
+		t.Fatalf("expected no shortfall once the target is reached, got %v", f.Shortfall)
+	}
+
+	f = fundCoverage(6, 1000, 0)
+	if f.CoveredMonths != nil || f.FundedPct != nil {
+		t.Fatalf("expected nil coverage without expenses, got %+v", f)
+	}
+}
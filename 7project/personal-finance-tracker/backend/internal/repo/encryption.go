@@ -0,0 +1,229 @@
+// backend/internal/repo/encryption.go
+
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+
+	"pft/internal/crypt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// fieldCrypt encrypts transaction descriptions with per-user data keys kept wrapped
+// in user_keys (migration 038). A nil *fieldCrypt leaves values as they are, so
+// repos call it unconditionally.
+type fieldCrypt struct {
+	pool    *pgxpool.Pool
+	wrapper crypt.Wrapper
+
+	mu   sync.Mutex
+	keys map[int64][]byte // unwrapped data keys; KMS calls are too slow to repeat
+}
+
+// UseEncryption encrypts transaction descriptions written from now on with per-user
+// data keys wrapped by w, and decrypts them on read. Descriptions stored before stay
+// readable; EncryptPlaintext converts them. Once enabled it cannot be turned off
+// without losing the encrypted text. Call it once at startup, before the Store is shared.
+func (s *Store) UseEncryption(w crypt.Wrapper) {
+	s.crypt = &fieldCrypt{pool: s.Pool, wrapper: w, keys: map[int64][]byte{}}
+}
+
+// key returns the user's data key, creating it on first use.
+func (f *fieldCrypt) key(ctx context.Context, userID int64) ([]byte, error) {
+	f.mu.Lock()
+	key, ok := f.keys[userID]
+	f.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	var wrapped []byte
+	err := f.pool.QueryRow(ctx, `SELECT wrapped_key FROM user_keys WHERE user_id=$1`, userID).Scan(&wrapped)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if key, err = crypt.NewKey(); err != nil {
+			return nil, err
+		}
+		if wrapped, err = f.wrapper.Wrap(ctx, key); err != nil {
+			return nil, err
+		}
+		// Another instance may have created the key meanwhile; whichever was first wins.
+		err = f.pool.QueryRow(ctx, `INSERT INTO user_keys (user_id, wrapped_key) VALUES ($1, $2)
+		                            ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		                            RETURNING wrapped_key`, userID, wrapped).Scan(&wrapped)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if key, err = f.wrapper.Unwrap(ctx, wrapped); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	f.keys[userID] = key
+	f.mu.Unlock()
+	return key, nil
+}
+
+// forget drops a cached key, after a restore may have replaced it.
+func (f *fieldCrypt) forget(userID int64) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	delete(f.keys, userID)
+	f.mu.Unlock()
+}
+
+func userAAD(userID int64) string { return "user:" + strconv.FormatInt(userID, 10) }
+
+// seal encrypts a description and returns its blind index for equality matches.
+// Empty descriptions stay empty and have no index.
+func (f *fieldCrypt) seal(ctx context.Context, userID int64, s string) (string, *string, error) {
+	if f == nil || s == "" {
+		return s, nil, nil
+	}
+	key, err := f.key(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	sealed, err := crypt.Encrypt(key, s, userAAD(userID))
+	if err != nil {
+		return "", nil, err
+	}
+	index := crypt.BlindIndex(key, s)
+	return sealed, &index, nil
+}
+
+// index returns the blind index matching a description sealed for the user, or nil.
+func (f *fieldCrypt) index(ctx context.Context, userID int64, s string) (*string, error) {
+	if f == nil {
+		return nil, nil
+	}
+	key, err := f.key(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	index := crypt.BlindIndex(key, s)
+	return &index, nil
+}
+
+// open decrypts a value read from the database in place.
+func (f *fieldCrypt) open(ctx context.Context, userID int64, s *string) error {
+	if f == nil || !crypt.Encrypted(*s) {
+		return nil
+	}
+	key, err := f.key(ctx, userID)
+	if err != nil {
+		return err
+	}
+	plain, err := crypt.Decrypt(key, *s, userAAD(userID))
+	if err != nil {
+		return err
+	}
+	*s = plain
+	return nil
+}
+
+// openAll decrypts the descriptions of txs in place.
+func (f *fieldCrypt) openAll(ctx context.Context, txs []Transaction) error {
+	for i := range txs {
+		if err := f.open(ctx, txs[i].UserID, &txs[i].Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openDump decrypts the transaction descriptions in a dump and drops the wrapped key
+// and blind indexes, giving the user their data as they entered it.
+func (f *fieldCrypt) openDump(ctx context.Context, d *UserDump) error {
+	delete(d.Tables, "user_keys")
+	if f == nil {
+		return nil
+	}
+	var rows []map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(rawJSON(d.Tables["transactions"])), &rows); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		delete(row, "description_hash")
+		var desc string
+		if err := json.Unmarshal(row["description"], &desc); err != nil {
+			continue // NULL or missing
+		}
+		if err := f.open(ctx, d.UserID, &desc); err != nil {
+			return err
+		}
+		b, err := json.Marshal(desc)
+		if err != nil {
+			return err
+		}
+		row["description"] = b
+	}
+	b, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+	d.Tables["transactions"] = b
+	return nil
+}
+
+// EncryptPlaintext encrypts up to limit transaction descriptions still stored as
+// plaintext, for any user, and returns how many it converted; run it until it
+// returns 0 after enabling encryption. The audit log keeps earlier plaintext copies.
+// A no-op without UseEncryption.
+func (r *TransactionRepo) EncryptPlaintext(ctx context.Context, limit int) (int, error) {
+	if r.crypt == nil {
+		return 0, nil
+	}
+	rows, err := r.pool.Query(ctx, `SELECT user_id, id, description FROM transactions
+	                                WHERE description <> '' AND description NOT LIKE 'enc1:%'
+	                                LIMIT $1`, limit)
+	if err != nil {
+		return 0, err
+	}
+	type plain struct {
+		userID, id int64
+		desc       string
+	}
+	var todo []plain
+	for rows.Next() {
+		var p plain
+		if err := rows.Scan(&p.userID, &p.id, &p.desc); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		todo = append(todo, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	// Logging the plaintext again as the "before" of each update would defeat the point.
+	if _, err := tx.Exec(ctx, `SELECT set_config('app.audit', 'off', true)`); err != nil {
+		return 0, err
+	}
+	for _, p := range todo {
+		sealed, index, err := r.crypt.seal(ctx, p.userID, p.desc)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(ctx, `UPDATE transactions SET description=$3, description_hash=$4
+		                           WHERE user_id=$1 AND id=$2 AND description=$5`,
+			p.userID, p.id, sealed, index, p.desc); err != nil {
+			return 0, err
+		}
+	}
+	return len(todo), tx.Commit(ctx)
+}
@@ -0,0 +1,197 @@
+// backend/internal/repo/export.go
+
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Export is a file built by a background job for the user to download (see
+// migration 036).
+// - Kind: "archive" (all of the user's data as gzipped JSON) or "xlsx" (Year's transactions)
+// - Status: "queued" | "running" | "done" | "failed"
+// - BlobKey: where the file is kept in blob storage once done (migration 037)
+type Export struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"-"`
+	Kind        string     `json:"kind"`
+	Year        *int       `json:"year,omitempty"`
+	Status      string     `json:"status"`
+	Filename    *string    `json:"filename"`
+	ContentType *string    `json:"-"`
+	BlobKey     *string    `json:"-"`
+	Size        int64      `json:"size"`
+	Error       *string    `json:"error"`
+	JobID       *int64     `json:"job_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+}
+
+// ExportRepo stores exports and reads the data they are built from.
+type ExportRepo struct {
+	pool  dbConn
+	crypt *fieldCrypt
+}
+
+// ExportRepo accessor bound to the Store's pool.
+func (s *Store) ExportRepo() *ExportRepo { return &ExportRepo{pool: s.db(), crypt: s.crypt} }
+
+// exportCols reads an export; like imports, one whose job ran
+// out of attempts is reported failed with the job's last error.
+const exportCols = `e.id, e.user_id, e.kind, e.year,
+       CASE WHEN e.status IN ('queued', 'running') AND j.state = 'failed' THEN 'failed' ELSE e.status END,
+       e.filename, e.content_type, e.blob_key, e.size,
+       COALESCE(e.error, CASE WHEN e.status IN ('queued', 'running') AND j.state = 'failed' THEN j.last_error END),
+       e.job_id, e.created_at, e.finished_at`
+
+const exportFrom = ` FROM exports e LEFT JOIN jobs j ON j.id = e.job_id`
+
+func scanExport(row pgx.Row) (*Export, error) {
+	var e Export
+	if err := row.Scan(&e.ID, &e.UserID, &e.Kind, &e.Year, &e.Status, &e.Filename, &e.ContentType, &e.BlobKey, &e.Size,
+		&e.Error, &e.JobID, &e.CreatedAt, &e.FinishedAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Create stores a queued export and, in the same transaction, the job of jobKind
+// that builds it.
+func (r *ExportRepo) Create(ctx context.Context, e *Export, jobKind string) (*Export, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var id int64
+	if err := tx.QueryRow(ctx, `INSERT INTO exports (user_id, kind, year) VALUES ($1, $2, $3) RETURNING id`,
+		e.UserID, e.Kind, e.Year).Scan(&id); err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(map[string]int64{"export_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `WITH j AS (
+	                               INSERT INTO jobs (kind, user_id, payload) VALUES ($1, $2, $3) RETURNING id
+	                           )
+	                           UPDATE exports SET job_id = (SELECT id FROM j) WHERE id = $4`,
+		jobKind, e.UserID, payload, id); err != nil {
+		return nil, err
+	}
+	out, err := scanExport(tx.QueryRow(ctx, `SELECT `+exportCols+exportFrom+` WHERE e.id=$1`, id))
+	if err != nil {
+		return nil, err
+	}
+	return out, tx.Commit(ctx)
+}
+
+// Get fetches one of the user's exports. Returns (nil, nil) when not found.
+func (r *ExportRepo) Get(ctx context.Context, userID, id int64) (*Export, error) {
+	e, err := scanExport(r.pool.QueryRow(ctx, `SELECT `+exportCols+exportFrom+` WHERE e.user_id=$1 AND e.id=$2`, userID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return e, err
+}
+
+// Start marks an export running and returns it, or (nil, nil) when it is gone or
+// already finished.
+func (r *ExportRepo) Start(ctx context.Context, id int64) (*Export, error) {
+	const q = `UPDATE exports e SET status='running'
+	           WHERE id=$1 AND status IN ('queued', 'running')
+	           RETURNING e.id, e.user_id, e.kind, e.year, e.status, e.filename, e.content_type, e.blob_key, e.size,
+	                     e.error, e.job_id, e.created_at, e.finished_at`
+	e, err := scanExport(r.pool.QueryRow(ctx, q, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return e, err
+}
+
+// Finish records the built file, stored in blob storage under key, and marks the
+// export done.
+func (r *ExportRepo) Finish(ctx context.Context, id int64, filename, contentType, key string, size int64) error {
+	const q = `UPDATE exports SET status='done', filename=$2, content_type=$3, blob_key=$4, size=$5, finished_at=NOW()
+	           WHERE id=$1`
+	_, err := r.pool.Exec(ctx, q, id, filename, contentType, key, size)
+	return err
+}
+
+// Fail marks an export failed with msg.
+func (r *ExportRepo) Fail(ctx context.Context, id int64, msg string) error {
+	_, err := r.pool.Exec(ctx, `UPDATE exports SET status='failed', error=$2, finished_at=NOW() WHERE id=$1`, id, msg)
+	return err
+}
+
+// Download fetches a finished export for any user (the caller verified the download
+// URL). Returns (nil, nil) when there is no such export or it is not done.
+func (r *ExportRepo) Download(ctx context.Context, id int64) (*Export, error) {
+	e, err := scanExport(r.pool.QueryRow(ctx, `SELECT `+exportCols+exportFrom+` WHERE e.id=$1 AND e.status='done'`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return e, err
+}
+
+// Cleanup deletes exports created before cutoff and returns the blob keys of their
+// files, for the caller to delete.
+func (r *ExportRepo) Cleanup(ctx context.Context, cutoff time.Time) ([]string, error) {
+	rows, err := r.pool.Query(ctx, `DELETE FROM exports WHERE created_at < $1 RETURNING blob_key`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keys := []string{}
+	for rows.Next() {
+		var key *string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		if key != nil {
+			keys = append(keys, *key)
+		}
+	}
+	return keys, rows.Err()
+}
+
+// ExportTransaction is a transaction with its category name, as written to exports.
+type ExportTransaction struct {
+	Date        time.Time
+	Type        string
+	Category    *string
+	Description string
+	Amount      float64
+}
+
+// YearTransactions returns the user's transactions dated in year, oldest first.
+func (r *ExportRepo) YearTransactions(ctx context.Context, userID int64, year int) ([]ExportTransaction, error) {
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	const q = `SELECT t.date, t.type, c.name, t.description, t.amount
+	           FROM transactions t LEFT JOIN categories c ON c.id = t.category_id
+	           WHERE t.user_id=$1 AND t.date >= $2 AND t.date < $3
+	           ORDER BY t.date, t.id`
+	rows, err := r.pool.Query(ctx, q, userID, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []ExportTransaction
+	for rows.Next() {
+		var t ExportTransaction
+		if err := rows.Scan(&t.Date, &t.Type, &t.Category, &t.Description, &t.Amount); err != nil {
+			return nil, err
+		}
+		if err := r.crypt.open(ctx, userID, &t.Description); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
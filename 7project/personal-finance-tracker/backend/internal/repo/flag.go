@@ -0,0 +1,124 @@
+// backend/internal/repo/flag.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Flag is a feature flag definition (see migration 026).
+// - Enabled: on for everyone without an override
+// - RolloutPercent: share of the remaining users (0..100) who get the feature
+// - Overrides: per-user decisions, taking precedence over the above
+type Flag struct {
+	Key            string         `json:"key"`
+	Description    string         `json:"description"`
+	Enabled        bool           `json:"enabled"`
+	RolloutPercent int            `json:"rollout_percent"`
+	Overrides      map[int64]bool `json:"overrides"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+// FlagRepo stores feature flags and their per-user overrides.
+type FlagRepo struct{ pool dbConn }
+
+// FlagRepo accessor bound to the Store's pool.
+func (s *Store) FlagRepo() *FlagRepo { return &FlagRepo{pool: s.db()} }
+
+// List returns every flag with its overrides, ordered by key.
+func (r *FlagRepo) List(ctx context.Context) ([]Flag, error) {
+	rows, err := r.pool.Query(ctx, `SELECT key, description, enabled, rollout_percent, updated_at
+	                                FROM feature_flags ORDER BY key`)
+	if err != nil {
+		return nil, err
+	}
+	out := []Flag{}
+	idx := map[string]int{}
+	for rows.Next() {
+		var f Flag
+		if err := rows.Scan(&f.Key, &f.Description, &f.Enabled, &f.RolloutPercent, &f.UpdatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		f.Overrides = map[int64]bool{}
+		idx[f.Key] = len(out)
+		out = append(out, f)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.pool.Query(ctx, `SELECT flag_key, user_id, enabled FROM feature_flag_overrides`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key string
+		var userID int64
+		var on bool
+		if err := rows.Scan(&key, &userID, &on); err != nil {
+			return nil, err
+		}
+		if i, ok := idx[key]; ok {
+			out[i].Overrides[userID] = on
+		}
+	}
+	return out, rows.Err()
+}
+
+// Upsert creates or updates a flag definition; overrides are kept.
+func (r *FlagRepo) Upsert(ctx context.Context, f *Flag) (*Flag, error) {
+	const q = `INSERT INTO feature_flags (key, description, enabled, rollout_percent)
+	           VALUES ($1, $2, $3, $4)
+	           ON CONFLICT (key) DO UPDATE
+	           SET description=EXCLUDED.description, enabled=EXCLUDED.enabled,
+	               rollout_percent=EXCLUDED.rollout_percent, updated_at=NOW()
+	           RETURNING key, description, enabled, rollout_percent, updated_at`
+	var out Flag
+	if err := r.pool.QueryRow(ctx, q, f.Key, f.Description, f.Enabled, f.RolloutPercent).Scan(
+		&out.Key, &out.Description, &out.Enabled, &out.RolloutPercent, &out.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Delete removes a flag and its overrides. Returns false if it did not exist.
+func (r *FlagRepo) Delete(ctx context.Context, key string) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM feature_flags WHERE key=$1`, key)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// SetOverride forces the flag on or off for one user; nil removes the override.
+// Returns ErrNotFound if the flag or the user does not exist.
+func (r *FlagRepo) SetOverride(ctx context.Context, key string, userID int64, enabled *bool) error {
+	if enabled == nil {
+		ct, err := r.pool.Exec(ctx, `DELETE FROM feature_flag_overrides WHERE flag_key=$1 AND user_id=$2`, key, userID)
+		if err != nil {
+			return err
+		}
+		if ct.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return nil
+	}
+	var ok bool
+	err := r.pool.QueryRow(ctx, `INSERT INTO feature_flag_overrides (flag_key, user_id, enabled)
+	                             SELECT $1, $2, $3
+	                             WHERE EXISTS (SELECT 1 FROM feature_flags WHERE key=$1)
+	                               AND EXISTS (SELECT 1 FROM users WHERE id=$2)
+	                             ON CONFLICT (flag_key, user_id) DO UPDATE SET enabled=EXCLUDED.enabled
+	                             RETURNING TRUE`, key, userID, *enabled).Scan(&ok)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
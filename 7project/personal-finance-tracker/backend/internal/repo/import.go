@@ -0,0 +1,200 @@
+// backend/internal/repo/import.go
+
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxImportErrors caps the row errors kept per import; rows_failed keeps counting.
+const maxImportErrors = 100
+
+// Import is a CSV import run by a background job (see migration 035).
+// - Status: "queued" | "running" | "done" | "failed" | "cancelled"
+// - RowsProcessed: data rows handled so far, imported or not, of RowsTotal
+// - Errors: the first rows that could not be imported, with the reason
+// - Error: why the whole import failed
+// - CancelRequested: set by a cancel while running; the job stops at the next row
+// - AsDrafts: rows become drafts to review (see Draft) instead of transactions
+// - Profile: the regional format the CSV is read in (see imports.ParseProfile); "" for the canonical one
+type Import struct {
+	ID              int64         `json:"id"`
+	UserID          int64         `json:"-"`
+	Filename        string        `json:"filename"`
+	Content         []byte        `json:"-"`
+	Status          string        `json:"status"`
+	RowsTotal       int           `json:"rows_total"`
+	RowsProcessed   int           `json:"rows_processed"`
+	RowsImported    int           `json:"rows_imported"`
+	RowsFailed      int           `json:"rows_failed"`
+	Errors          []ImportError `json:"errors"`
+	Error           *string       `json:"error"`
+	CancelRequested bool          `json:"cancel_requested"`
+	JobID           *int64        `json:"job_id"`
+	CreatedAt       time.Time     `json:"created_at"`
+	StartedAt       *time.Time    `json:"started_at"`
+	FinishedAt      *time.Time    `json:"finished_at"`
+	AsDrafts        bool          `json:"as_drafts"`
+	Profile         string        `json:"profile"`
+}
+
+// ImportError is a row of the file that was not imported. Line is the line number in
+// the file (the header is line 1).
+type ImportError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ImportProgress is the state of a running import after a row.
+type ImportProgress struct {
+	Processed int
+	Imported  int
+	Failed    int
+	Error     *ImportError // the row's error, if it failed
+}
+
+// ImportRepo stores CSV imports and their progress.
+type ImportRepo struct{ pool dbConn }
+
+// ImportRepo accessor bound to the Store's pool.
+func (s *Store) ImportRepo() *ImportRepo { return &ImportRepo{pool: s.db()} }
+
+// importCols reads an import without its content. An import whose job ran out of
+// attempts (e.g. the database was unreachable throughout) is reported failed with the
+// job's last error.
+const importCols = `i.id, i.user_id, i.filename,
+       CASE WHEN i.status IN ('queued', 'running') AND j.state = 'failed' THEN 'failed' ELSE i.status END,
+       i.rows_total, i.rows_processed, i.rows_imported, i.rows_failed, i.errors,
+       COALESCE(i.error, CASE WHEN i.status IN ('queued', 'running') AND j.state = 'failed' THEN j.last_error END),
+       i.cancel_requested, i.job_id, i.created_at, i.started_at, i.finished_at, i.as_drafts, i.profile`
+
+const importFrom = ` FROM imports i LEFT JOIN jobs j ON j.id = i.job_id`
+
+func scanImport(row pgx.Row) (*Import, error) {
+	var im Import
+	if err := row.Scan(&im.ID, &im.UserID, &im.Filename, &im.Status, &im.RowsTotal, &im.RowsProcessed,
+		&im.RowsImported, &im.RowsFailed, &im.Errors, &im.Error, &im.CancelRequested, &im.JobID,
+		&im.CreatedAt, &im.StartedAt, &im.FinishedAt, &im.AsDrafts, &im.Profile); err != nil {
+		return nil, err
+	}
+	return &im, nil
+}
+
+// Create stores a queued import and, in the same transaction, the job of jobKind
+// that runs it, so an import is never left without a job.
+func (r *ImportRepo) Create(ctx context.Context, im *Import, jobKind string) (*Import, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var id int64
+	if err := tx.QueryRow(ctx, `INSERT INTO imports (user_id, filename, content, as_drafts, profile) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		im.UserID, im.Filename, im.Content, im.AsDrafts, im.Profile).Scan(&id); err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(map[string]int64{"import_id": id})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `WITH j AS (
+	                               INSERT INTO jobs (kind, user_id, payload) VALUES ($1, $2, $3) RETURNING id
+	                           )
+	                           UPDATE imports SET job_id = (SELECT id FROM j) WHERE id = $4`,
+		jobKind, im.UserID, payload, id); err != nil {
+		return nil, err
+	}
+	out, err := scanImport(tx.QueryRow(ctx, `SELECT `+importCols+importFrom+` WHERE i.id=$1`, id))
+	if err != nil {
+		return nil, err
+	}
+	return out, tx.Commit(ctx)
+}
+
+// Get fetches one of the user's imports without its content. Returns (nil, nil) when not found.
+func (r *ImportRepo) Get(ctx context.Context, userID, id int64) (*Import, error) {
+	im, err := scanImport(r.pool.QueryRow(ctx, `SELECT `+importCols+importFrom+` WHERE i.user_id=$1 AND i.id=$2`, userID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return im, err
+}
+
+// Start marks an import running and returns it, or (nil, nil) when it is no longer
+// queued or running (cancelled meanwhile). rowsTotal is the number of data rows in
+// the file.
+func (r *ImportRepo) Start(ctx context.Context, id int64, rowsTotal int) (*Import, error) {
+	const q = `UPDATE imports i SET status='running', rows_total=$2, started_at=COALESCE(started_at, NOW())
+	           WHERE id=$1 AND status IN ('queued', 'running')
+	           RETURNING i.id, i.user_id, i.filename, i.status, i.rows_total, i.rows_processed, i.rows_imported,
+	                     i.rows_failed, i.errors, i.error, i.cancel_requested, i.job_id, i.created_at,
+	                     i.started_at, i.finished_at, i.as_drafts, i.profile`
+	im, err := scanImport(r.pool.QueryRow(ctx, q, id, rowsTotal))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return im, err
+}
+
+// Content returns the uploaded file of an import and the profile to read it with;
+// nil once it finished.
+func (r *ImportRepo) Content(ctx context.Context, id int64) ([]byte, string, error) {
+	var b []byte
+	var profile string
+	err := r.pool.QueryRow(ctx, `SELECT content, profile FROM imports WHERE id=$1`, id).Scan(&b, &profile)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, "", nil
+	}
+	return b, profile, err
+}
+
+// Progress saves the state after a row and reports whether a cancel was requested.
+func (r *ImportRepo) Progress(ctx context.Context, id int64, p ImportProgress) (bool, error) {
+	rowErr := []ImportError{}
+	if p.Error != nil {
+		rowErr = []ImportError{*p.Error}
+	}
+	const q = `UPDATE imports
+	           SET rows_processed=$2, rows_imported=$3, rows_failed=$4,
+	               errors = CASE WHEN jsonb_array_length(errors) < $6 THEN errors || $5::jsonb ELSE errors END
+	           WHERE id=$1
+	           RETURNING cancel_requested`
+	var cancel bool
+	err := r.pool.QueryRow(ctx, q, id, p.Processed, p.Imported, p.Failed, rowErr, maxImportErrors).Scan(&cancel)
+	return cancel, err
+}
+
+// Finish settles an import as "done", "failed" (with msg) or "cancelled" and drops
+// its content.
+func (r *ImportRepo) Finish(ctx context.Context, id int64, status string, msg *string) error {
+	_, err := r.pool.Exec(ctx, `UPDATE imports SET status=$2, error=$3, content=NULL, finished_at=NOW() WHERE id=$1`,
+		id, status, msg)
+	return err
+}
+
+// Cancel stops one of the user's imports: a queued one is cancelled right away, a
+// running one at its next row. Rows imported before that are kept. Returns
+// (nil, nil) when the import does not exist or already finished.
+func (r *ImportRepo) Cancel(ctx context.Context, userID, id int64) (*Import, error) {
+	const q = `UPDATE imports
+	           SET cancel_requested=TRUE,
+	               status = CASE WHEN status='queued' THEN 'cancelled' ELSE status END,
+	               content = CASE WHEN status='queued' THEN NULL ELSE content END,
+	               finished_at = CASE WHEN status='queued' THEN NOW() END
+	           WHERE user_id=$1 AND id=$2 AND status IN ('queued', 'running')
+	           RETURNING id`
+	err := r.pool.QueryRow(ctx, q, userID, id).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, userID, id)
+}
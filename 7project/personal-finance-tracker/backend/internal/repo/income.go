@@ -0,0 +1,217 @@
+// backend/internal/repo/income.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// IncomeSource is the repository-layer DTO mirroring the income_sources table.
+// - Kind: "salary" | "freelance" | "other"
+// - PayInterval: "weekly" | "biweekly" | "monthly" | "quarterly" | "yearly"
+// - StartDate: first (or any known) pay date; EndDate optionally stops the recurrence
+type IncomeSource struct {
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	CategoryID  *int64     `json:"category_id"`
+	Name        string     `json:"name"`
+	Kind        string     `json:"kind"`
+	Amount      float64    `json:"amount"`
+	PayInterval string     `json:"pay_interval"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// SourceIncome is the income expected from one source within a month.
+type SourceIncome struct {
+	SourceID int64   `json:"source_id"`
+	Name     string  `json:"name"`
+	Payments int     `json:"payments"`
+	Amount   float64 `json:"amount"`
+}
+
+// MonthIncome is the expected income of one month, broken down by source.
+type MonthIncome struct {
+	Month    string         `json:"month"` // YYYY-MM
+	Expected float64        `json:"expected"`
+	Sources  []SourceIncome `json:"sources"`
+}
+
+// IncomeProjection lists expected income for upcoming months.
+// The first month only counts payments from AsOf onwards.
+type IncomeProjection struct {
+	AsOf   string        `json:"as_of"` // YYYY-MM-DD
+	Total  float64       `json:"total"`
+	Months []MonthIncome `json:"months"`
+}
+
+// IncomeRepo provides CRUD for income sources and income projections.
+type IncomeRepo struct {
+	pool dbConn
+	conv Conversion
+}
+
+// IncomeRepo accessor bound to the Store's pool.
+func (s *Store) IncomeRepo() *IncomeRepo { return &IncomeRepo{pool: s.db()} }
+
+const incomeSourceCols = `id, user_id, category_id, name, kind, amount, pay_interval, start_date, end_date, created_at, updated_at`
+
+func scanIncomeSource(row pgx.Row) (*IncomeSource, error) {
+	var s IncomeSource
+	if err := row.Scan(&s.ID, &s.UserID, &s.CategoryID, &s.Name, &s.Kind, &s.Amount, &s.PayInterval, &s.StartDate, &s.EndDate, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// List returns the user's income sources ordered by name.
+func (r *IncomeRepo) List(ctx context.Context, userID int64) ([]IncomeSource, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+incomeSourceCols+` FROM income_sources WHERE user_id=$1 ORDER BY lower(name), id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []IncomeSource{}
+	for rows.Next() {
+		s, err := scanIncomeSource(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *s)
+	}
+	return out, rows.Err()
+}
+
+// Create inserts an income source and returns the stored row.
+func (r *IncomeRepo) Create(ctx context.Context, s *IncomeSource) (*IncomeSource, error) {
+	const q = `INSERT INTO income_sources (user_id, category_id, name, kind, amount, pay_interval, start_date, end_date)
+	           VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+	           RETURNING ` + incomeSourceCols
+	return scanIncomeSource(r.pool.QueryRow(ctx, q, s.UserID, s.CategoryID, s.Name, s.Kind, s.Amount, s.PayInterval, s.StartDate, s.EndDate))
+}
+
+// Update modifies an income source owned by the user. Returns (nil, nil) when not found.
+func (r *IncomeRepo) Update(ctx context.Context, userID, id int64, s *IncomeSource) (*IncomeSource, error) {
+	const q = `UPDATE income_sources
+	           SET category_id=$3, name=$4, kind=$5, amount=$6, pay_interval=$7, start_date=$8, end_date=$9
+	           WHERE user_id=$1 AND id=$2
+	           RETURNING ` + incomeSourceCols
+	out, err := scanIncomeSource(r.pool.QueryRow(ctx, q, userID, id, s.CategoryID, s.Name, s.Kind, s.Amount, s.PayInterval, s.StartDate, s.EndDate))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// Delete removes an income source scoped to the user.
+func (r *IncomeRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM income_sources WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// Project returns expected income for the month of asOf and the following months-1 months.
+// Converted amounts use the rate as of asOf; the payments have not happened yet.
+func (r *IncomeRepo) Project(ctx context.Context, userID int64, asOf time.Time, months int) (*IncomeProjection, error) {
+	sources, err := r.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := r.conv.rate(ctx, r.pool, asOf)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sources {
+		sources[i].Amount *= rate
+	}
+	return ProjectIncome(sources, asOf, months), nil
+}
+
+// ProjectIncome buckets the payments of each source into calendar months starting at asOf.
+func ProjectIncome(sources []IncomeSource, asOf time.Time, months int) *IncomeProjection {
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	first := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, time.UTC)
+	p := &IncomeProjection{AsOf: asOf.Format("2006-01-02"), Months: make([]MonthIncome, 0, months)}
+	for i := 0; i < months; i++ {
+		from := first.AddDate(0, i, 0)
+		to := from.AddDate(0, 1, 0)
+		if i == 0 {
+			from = asOf
+		}
+		m := MonthIncome{Month: to.AddDate(0, -1, 0).Format("2006-01"), Sources: []SourceIncome{}}
+		for _, s := range sources {
+			n := len(IncomeOccurrences(&s, from, to))
+			if n == 0 {
+				continue
+			}
+			amt := round2(s.Amount * float64(n))
+			m.Sources = append(m.Sources, SourceIncome{SourceID: s.ID, Name: s.Name, Payments: n, Amount: amt})
+			m.Expected += amt
+		}
+		m.Expected = round2(m.Expected)
+		p.Total += m.Expected
+		p.Months = append(p.Months, m)
+	}
+	p.Total = round2(p.Total)
+	return p
+}
+
+// IncomeOccurrences returns the pay dates of s within [from, to).
+func IncomeOccurrences(s *IncomeSource, from, to time.Time) []time.Time {
+	return occurrences(s.StartDate, s.EndDate, s.PayInterval, from, to)
+}
+
+// occurrences returns the dates within [from, to) of a schedule repeating every interval
+// from start until end (inclusive; nil repeats forever).
+func occurrences(start time.Time, end *time.Time, interval string, from, to time.Time) []time.Time {
+	var out []time.Time
+	d := start
+	for i := 0; d.Before(to) && i < 10000; i++ {
+		if end != nil && d.After(*end) {
+			break
+		}
+		if !d.Before(from) {
+			out = append(out, d)
+		}
+		d = nthInterval(start, interval, i+1)
+	}
+	return out
+}
+
+// nthInterval returns the date n intervals after start. Month-based intervals clamp to
+// the last day of shorter months (a salary paid on the 31st lands on Feb 28/29), and
+// counting from start rather than the previous date keeps later months on the 31st.
+func nthInterval(start time.Time, interval string, n int) time.Time {
+	switch interval {
+	case "weekly":
+		return start.AddDate(0, 0, 7*n)
+	case "biweekly":
+		return start.AddDate(0, 0, 14*n)
+	case "quarterly":
+		return addMonthsClamped(start, 3*n)
+	case "yearly":
+		return addMonthsClamped(start, 12*n)
+	default:
+		return addMonthsClamped(start, n)
+	}
+}
+
+// addMonthsClamped adds months to d, clamping the day to the target month's length.
+func addMonthsClamped(d time.Time, months int) time.Time {
+	first := time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, d.Location()).AddDate(0, months, 0)
+	last := first.AddDate(0, 1, -1).Day()
+	day := d.Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, d.Location())
+}
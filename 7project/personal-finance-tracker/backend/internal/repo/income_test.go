@@ -0,0 +1,54 @@
+// backend/internal/repo/income_test.go
+//
+// Purpose:
+//   Verify pay date generation (including month-end clamping) and the monthly
+//   bucketing of expected income.
+
+package repo
+
+import (
+	"testing"
+	"time"
+)
+
+func mustDate(s string) time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return d
+}
+
+func TestIncomeOccurrences_MonthEndClamp(t *testing.T) {
+	s := &IncomeSource{Amount: 1000, PayInterval: "monthly", StartDate: mustDate("2024-01-31")}
+	got := IncomeOccurrences(s, mustDate("2024-01-01"), mustDate("2024-05-01"))
+	want := []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i].Format("2006-01-02") != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestProjectIncome(t *testing.T) {
+	end := mustDate("2024-03-31")
+	sources := []IncomeSource{
+		{ID: 1, Name: "Salary", Amount: 3000, PayInterval: "monthly", StartDate: mustDate("2023-06-25")},
+		{ID: 2, Name: "Retainer", Amount: 500, PayInterval: "biweekly", StartDate: mustDate("2024-02-02"), EndDate: &end},
+	}
+	p := ProjectIncome(sources, mustDate("2024-02-10"), 3)
+	if len(p.Months) != 3 {
+		t.Fatalf("expected 3 months, got %d", len(p.Months))
+	}
+	// Feb (from the 10th): salary on 25th + retainer on 16th = 3500.
+	// Mar: salary + retainer on 1st, 15th, 29th = 4500. Apr: salary only (retainer ended).
+	wantExpected := []float64{3500, 4500, 3000}
+	for i, m := range p.Months {
+		if m.Expected != wantExpected[i] {
+			t.Fatalf("%s: expected %v, got %v (%+v)", m.Month, wantExpected[i], m.Expected, m.Sources)
+		}
+	}
+	if p.Months[0].Month != "2024-02" || p.Total != 11000 {
+		t.Fatalf("unexpected projection: %+v", p)
+	}
+}
@@ -0,0 +1,47 @@
+// backend/internal/repo/ingest.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// IngestRepo stores the tokens of users' receipt ingest addresses (see migration 042).
+// The inbound-mail webhook works without a tenant, since it learns the user from the
+// address the message was sent to.
+type IngestRepo struct{ pool dbConn }
+
+// IngestRepo accessor bound to the Store's pool.
+func (s *Store) IngestRepo() *IngestRepo { return &IngestRepo{pool: s.db()} }
+
+// Token returns the token of the user's ingest address, or "" when none was created.
+func (r *IngestRepo) Token(ctx context.Context, userID int64) (string, error) {
+	var token string
+	err := r.pool.QueryRow(ctx, `SELECT token FROM ingest_addresses WHERE user_id=$1`, userID).Scan(&token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return token, err
+}
+
+// SetToken gives the user an ingest address with token, replacing their previous one;
+// mail to the old address is ignored from then on.
+func (r *IngestRepo) SetToken(ctx context.Context, userID int64, token string) error {
+	const q = `INSERT INTO ingest_addresses (user_id, token) VALUES ($1, $2)
+	           ON CONFLICT (user_id) DO UPDATE SET token=EXCLUDED.token, created_at=NOW()`
+	_, err := r.pool.Exec(ctx, q, userID, token)
+	return err
+}
+
+// UserByToken returns the user whose ingest address has token, or 0.
+func (r *IngestRepo) UserByToken(ctx context.Context, token string) (int64, error) {
+	var userID int64
+	err := r.pool.QueryRow(ctx, `SELECT user_id FROM ingest_addresses WHERE token=$1`, token).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return userID, err
+}
@@ -0,0 +1,161 @@
+// backend/internal/repo/job.go
+
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Job is a unit of background work in the jobs table.
+// - Kind: selects the handler registered with the worker (e.g. "rates.refresh")
+// - UserID: owner for user-initiated work; nil for system jobs
+// - Payload: handler-specific JSON arguments
+// - State: "pending" | "running" | "done" | "failed"
+// - UniqueKey: at most one pending/running job may carry the same key
+type Job struct {
+	ID          int64           `json:"id"`
+	Kind        string          `json:"kind"`
+	UserID      *int64          `json:"user_id"`
+	Payload     json.RawMessage `json:"payload"`
+	State       string          `json:"state"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	LastError   *string         `json:"last_error"`
+	UniqueKey   *string         `json:"unique_key,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	FinishedAt  *time.Time      `json:"finished_at"`
+}
+
+// JobRepo enqueues, claims and settles background jobs.
+type JobRepo struct{ pool dbConn }
+
+// JobRepo accessor bound to the Store's pool.
+func (s *Store) JobRepo() *JobRepo { return &JobRepo{pool: s.db()} }
+
+const jobCols = `id, kind, user_id, payload, state, attempts, max_attempts, run_at, last_error, unique_key, created_at, finished_at`
+
+func scanJob(row pgx.Row) (*Job, error) {
+	var j Job
+	if err := row.Scan(&j.ID, &j.Kind, &j.UserID, &j.Payload, &j.State, &j.Attempts, &j.MaxAttempts,
+		&j.RunAt, &j.LastError, &j.UniqueKey, &j.CreatedAt, &j.FinishedAt); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// Enqueue inserts a pending job. Zero RunAt means now and zero MaxAttempts means 5.
+// Returns (nil, nil) when a pending or running job with the same UniqueKey already exists.
+func (r *JobRepo) Enqueue(ctx context.Context, j *Job) (*Job, error) {
+	payload := j.Payload
+	if len(payload) == 0 {
+		payload = json.RawMessage(`{}`)
+	}
+	maxAttempts := j.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+	var runAt *time.Time
+	if !j.RunAt.IsZero() {
+		runAt = &j.RunAt
+	}
+	const q = `INSERT INTO jobs (kind, user_id, payload, max_attempts, run_at, unique_key)
+	           VALUES ($1,$2,$3,$4,COALESCE($5, NOW()),$6)
+	           ON CONFLICT (unique_key) WHERE unique_key IS NOT NULL AND state IN ('pending', 'running') DO NOTHING
+	           RETURNING ` + jobCols
+	out, err := scanJob(r.pool.QueryRow(ctx, q, j.Kind, j.UserID, payload, maxAttempts, runAt, j.UniqueKey))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// Get fetches a job. Returns (nil, nil) when not found.
+func (r *JobRepo) Get(ctx context.Context, id int64) (*Job, error) {
+	j, err := scanJob(r.pool.QueryRow(ctx, `SELECT `+jobCols+` FROM jobs WHERE id=$1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return j, err
+}
+
+// Claim locks the next ready job of one of kinds for lease and counts the attempt.
+// Running jobs whose lease expired (their worker died) are claimed again.
+// Returns (nil, nil) when nothing is ready.
+func (r *JobRepo) Claim(ctx context.Context, kinds []string, lease time.Duration) (*Job, error) {
+	const q = `UPDATE jobs SET state='running', attempts=attempts+1, locked_at=NOW()
+	           WHERE id = (
+	               SELECT id FROM jobs
+	               WHERE kind = ANY($1)
+	                 AND ((state='pending' AND run_at <= NOW())
+	                   OR (state='running' AND locked_at < NOW() - $2 * INTERVAL '1 second'))
+	               ORDER BY run_at, id
+	               FOR UPDATE SKIP LOCKED
+	               LIMIT 1)
+	           RETURNING ` + jobCols
+	j, err := scanJob(r.pool.QueryRow(ctx, q, kinds, lease.Seconds()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return j, err
+}
+
+// Complete marks a claimed job as done.
+func (r *JobRepo) Complete(ctx context.Context, id int64) error {
+	_, err := r.pool.Exec(ctx, `UPDATE jobs SET state='done', locked_at=NULL, last_error=NULL, finished_at=NOW() WHERE id=$1`, id)
+	return err
+}
+
+// Fail records a failed attempt. With retryAt the job goes back to pending until then;
+// without it the job is marked failed for good.
+func (r *JobRepo) Fail(ctx context.Context, id int64, msg string, retryAt *time.Time) error {
+	const q = `UPDATE jobs
+	           SET state=CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+	               run_at=COALESCE($3, run_at),
+	               finished_at=CASE WHEN $3::timestamptz IS NULL THEN NOW() END,
+	               locked_at=NULL, last_error=$2
+	           WHERE id=$1`
+	_, err := r.pool.Exec(ctx, q, id, msg, retryAt)
+	return err
+}
+
+// JobStats counts jobs by state for health reporting.
+// - Pending: waiting to run, including retries scheduled for later
+// - Due: pending jobs whose run_at has passed (the actual backlog)
+// - Running: currently leased by a worker
+// - Failed: exhausted their attempts
+type JobStats struct {
+	Pending int64 `json:"pending"`
+	Due     int64 `json:"due"`
+	Running int64 `json:"running"`
+	Failed  int64 `json:"failed"`
+}
+
+// Stats returns the current queue depth per state.
+func (r *JobRepo) Stats(ctx context.Context) (*JobStats, error) {
+	const q = `SELECT count(*) FILTER (WHERE state='pending'),
+	                  count(*) FILTER (WHERE state='pending' AND run_at <= now()),
+	                  count(*) FILTER (WHERE state='running'),
+	                  count(*) FILTER (WHERE state='failed')
+	           FROM jobs
+	           WHERE state <> 'done'`
+	var s JobStats
+	if err := r.pool.QueryRow(ctx, q).Scan(&s.Pending, &s.Due, &s.Running, &s.Failed); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Latest returns the most recently enqueued job of kind; (nil, nil) when there is none.
+func (r *JobRepo) Latest(ctx context.Context, kind string) (*Job, error) {
+	j, err := scanJob(r.pool.QueryRow(ctx, `SELECT `+jobCols+` FROM jobs WHERE kind=$1 ORDER BY id DESC LIMIT 1`, kind))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return j, err
+}
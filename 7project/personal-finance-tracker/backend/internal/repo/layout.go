@@ -0,0 +1,56 @@
+// backend/internal/repo/layout.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Widget is a single dashboard widget entry; its position in the layout slice is its order.
+type Widget struct {
+	ID      string `json:"id"`
+	Enabled bool   `json:"enabled"`
+}
+
+// DashboardWidgets lists the widget identifiers clients may place on the dashboard,
+// in the order used for the default layout.
+var DashboardWidgets = []string{"summary", "trend", "top_categories", "goals", "daily", "top_expenses", "projection"}
+
+// DefaultLayout returns the layout used before a user saves their own:
+// every known widget in canonical order, all enabled.
+func DefaultLayout() []Widget {
+	out := make([]Widget, len(DashboardWidgets))
+	for i, id := range DashboardWidgets {
+		out[i] = Widget{ID: id, Enabled: true}
+	}
+	return out
+}
+
+// GetLayout returns the saved dashboard layout for the user, or the default layout if none is stored.
+func (r *DashboardRepo) GetLayout(ctx context.Context, userID int64) ([]Widget, error) {
+	const q = `SELECT widgets FROM user_dashboard WHERE user_id=$1`
+	var ws []Widget
+	if err := r.pool.QueryRow(ctx, q, userID).Scan(&ws); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return DefaultLayout(), nil
+		}
+		return nil, err
+	}
+	return ws, nil
+}
+
+// SaveLayout stores (inserts or replaces) the user's dashboard layout and returns it.
+func (r *DashboardRepo) SaveLayout(ctx context.Context, userID int64, ws []Widget) ([]Widget, error) {
+	const q = `INSERT INTO user_dashboard (user_id, widgets, updated_at)
+	           VALUES ($1, $2, NOW())
+	           ON CONFLICT (user_id) DO UPDATE SET widgets=EXCLUDED.widgets, updated_at=NOW()
+	           RETURNING widgets`
+	var out []Widget
+	if err := r.pool.QueryRow(ctx, q, userID, ws).Scan(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,252 @@
+// backend/internal/repo/loan.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Loan is the repository-layer DTO mirroring the loans table.
+// AnnualRate is a percentage (4.5 means 4.5% per year); payments are monthly.
+type Loan struct {
+	ID         int64     `json:"id"`
+	UserID     int64     `json:"user_id"`
+	Name       string    `json:"name"`
+	Principal  float64   `json:"principal"`
+	AnnualRate float64   `json:"annual_rate"`
+	TermMonths int       `json:"term_months"`
+	StartDate  time.Time `json:"start_date"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Installment is one row of an amortization schedule.
+type Installment struct {
+	Number    int     `json:"number"`
+	DueDate   string  `json:"due_date"` // YYYY-MM-DD
+	Payment   float64 `json:"payment"`
+	Principal float64 `json:"principal"`
+	Interest  float64 `json:"interest"`
+	Balance   float64 `json:"balance"` // remaining after this installment
+}
+
+// Payoff projects when a loan will be repaid given the payments recorded so far.
+// - PaidTotal/PaymentsMade: sum and count of linked payment transactions
+// - Balance: outstanding principal after applying recorded payments
+// - RemainingPayments/ProjectedPayoff: further scheduled payments needed and the date of the last one
+// - RemainingInterest: interest still to be paid if the schedule is followed
+type Payoff struct {
+	LoanID            int64   `json:"loan_id"`
+	MonthlyPayment    float64 `json:"monthly_payment"`
+	PaymentsMade      int     `json:"payments_made"`
+	PaidTotal         float64 `json:"paid_total"`
+	Balance           float64 `json:"balance"`
+	RemainingPayments int     `json:"remaining_payments"`
+	ProjectedPayoff   *string `json:"projected_payoff"` // YYYY-MM-DD; nil when already repaid
+	RemainingInterest float64 `json:"remaining_interest"`
+}
+
+// LoanRepo provides CRUD for loans and links payment transactions to them.
+type LoanRepo struct {
+	pool  dbConn
+	crypt *fieldCrypt
+}
+
+// LoanRepo accessor bound to the Store's pool.
+func (s *Store) LoanRepo() *LoanRepo { return &LoanRepo{pool: s.db(), crypt: s.crypt} }
+
+const loanCols = `id, user_id, name, principal, annual_rate, term_months, start_date, created_at, updated_at`
+
+func scanLoan(row pgx.Row) (*Loan, error) {
+	var l Loan
+	if err := row.Scan(&l.ID, &l.UserID, &l.Name, &l.Principal, &l.AnnualRate, &l.TermMonths, &l.StartDate, &l.CreatedAt, &l.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// List returns all loans of a user ordered by id.
+func (r *LoanRepo) List(ctx context.Context, userID int64) ([]Loan, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+loanCols+` FROM loans WHERE user_id=$1 ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Loan{}
+	for rows.Next() {
+		l, err := scanLoan(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *l)
+	}
+	return out, rows.Err()
+}
+
+// Get fetches a loan scoped to the user. Returns (nil, nil) when not found.
+func (r *LoanRepo) Get(ctx context.Context, userID, id int64) (*Loan, error) {
+	l, err := scanLoan(r.pool.QueryRow(ctx, `SELECT `+loanCols+` FROM loans WHERE user_id=$1 AND id=$2`, userID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return l, err
+}
+
+// Create inserts a loan and returns the stored row.
+func (r *LoanRepo) Create(ctx context.Context, l *Loan) (*Loan, error) {
+	const q = `INSERT INTO loans (user_id, name, principal, annual_rate, term_months, start_date)
+	           VALUES ($1,$2,$3,$4,$5,$6)
+	           RETURNING ` + loanCols
+	return scanLoan(r.pool.QueryRow(ctx, q, l.UserID, l.Name, l.Principal, l.AnnualRate, l.TermMonths, l.StartDate))
+}
+
+// Update modifies a loan owned by the user. Returns (nil, nil) when not found.
+func (r *LoanRepo) Update(ctx context.Context, userID, id int64, l *Loan) (*Loan, error) {
+	const q = `UPDATE loans
+	           SET name=$3, principal=$4, annual_rate=$5, term_months=$6, start_date=$7
+	           WHERE user_id=$1 AND id=$2
+	           RETURNING ` + loanCols
+	out, err := scanLoan(r.pool.QueryRow(ctx, q, userID, id, l.Name, l.Principal, l.AnnualRate, l.TermMonths, l.StartDate))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// Delete removes a loan (and its payment links) scoped to the user.
+func (r *LoanRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM loans WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// LinkPayment marks a transaction of the same user as a payment towards a loan.
+// Returns false when either the loan or the transaction does not belong to the user.
+func (r *LoanRepo) LinkPayment(ctx context.Context, userID, loanID, txnID int64) (bool, error) {
+	const q = `INSERT INTO loan_payments (loan_id, transaction_id)
+	           SELECT l.id, t.id FROM loans l, transactions t
+	           WHERE l.id=$2 AND l.user_id=$1 AND t.id=$3 AND t.user_id=$1
+	           ON CONFLICT (loan_id, transaction_id) DO NOTHING`
+	ct, err := r.pool.Exec(ctx, q, userID, loanID, txnID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// UnlinkPayment removes a payment link. Returns false if no link matched.
+func (r *LoanRepo) UnlinkPayment(ctx context.Context, userID, loanID, txnID int64) (bool, error) {
+	const q = `DELETE FROM loan_payments lp
+	           USING loans l
+	           WHERE lp.loan_id=l.id AND l.user_id=$1 AND lp.loan_id=$2 AND lp.transaction_id=$3`
+	ct, err := r.pool.Exec(ctx, q, userID, loanID, txnID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// Payments returns the transactions linked to a loan, oldest first.
+func (r *LoanRepo) Payments(ctx context.Context, userID, loanID int64) ([]Transaction, error) {
+	const q = `SELECT ` + txnColsT + `
+	           FROM loan_payments lp
+	           JOIN transactions t ON t.id = lp.transaction_id
+	           JOIN loans l ON l.id = lp.loan_id
+	           WHERE l.user_id=$1 AND lp.loan_id=$2
+	           ORDER BY t.date, t.id`
+	rows, err := r.pool.Query(ctx, q, userID, loanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Transaction{}
+	for rows.Next() {
+		var t Transaction
+		if err := rows.Scan(t.scanDest()...); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, r.crypt.openAll(ctx, out)
+}
+
+// MonthlyPayment returns the fixed installment of an annuity loan.
+// With a zero rate the principal is split evenly across the term.
+func MonthlyPayment(principal, annualRate float64, months int) float64 {
+	r := annualRate / 100 / 12
+	if r == 0 {
+		return round2(principal / float64(months))
+	}
+	return round2(principal * r / (1 - math.Pow(1+r, -float64(months))))
+}
+
+// Amortize builds the full amortization schedule for a loan. The final installment
+// absorbs rounding so the balance ends at exactly zero.
+func Amortize(l *Loan) []Installment {
+	r := l.AnnualRate / 100 / 12
+	pay := MonthlyPayment(l.Principal, l.AnnualRate, l.TermMonths)
+	balance := l.Principal
+	out := make([]Installment, 0, l.TermMonths)
+	for n := 1; n <= l.TermMonths; n++ {
+		interest := round2(balance * r)
+		principal := round2(pay - interest)
+		if n == l.TermMonths || principal > balance {
+			principal = round2(balance)
+		}
+		balance = round2(balance - principal)
+		out = append(out, Installment{
+			Number:    n,
+			DueDate:   l.StartDate.AddDate(0, n, 0).Format("2006-01-02"),
+			Payment:   round2(principal + interest),
+			Principal: principal,
+			Interest:  interest,
+			Balance:   balance,
+		})
+	}
+	return out
+}
+
+// ProjectPayoff applies recorded payments (oldest first) to the loan, accruing one month
+// of interest before each, then projects the remaining schedule at the regular installment.
+func ProjectPayoff(l *Loan, payments []Transaction) *Payoff {
+	r := l.AnnualRate / 100 / 12
+	pay := MonthlyPayment(l.Principal, l.AnnualRate, l.TermMonths)
+	p := &Payoff{LoanID: l.ID, MonthlyPayment: pay, PaymentsMade: len(payments)}
+
+	balance := l.Principal
+	last := l.StartDate
+	for _, t := range payments {
+		p.PaidTotal += t.Amount
+		balance = math.Max(0, balance+balance*r-t.Amount)
+		last = t.Date
+	}
+	p.PaidTotal = round2(p.PaidTotal)
+	p.Balance = round2(balance)
+	if p.Balance <= 0 {
+		return p
+	}
+
+	// Simulate the rest of the schedule month by month.
+	for balance > 0.005 && p.RemainingPayments < 1200 {
+		interest := balance * r
+		p.RemainingInterest += interest
+		balance = balance + interest - pay
+		p.RemainingPayments++
+	}
+	p.RemainingInterest = round2(p.RemainingInterest)
+	d := last.AddDate(0, p.RemainingPayments, 0).Format("2006-01-02")
+	p.ProjectedPayoff = &d
+	return p
+}
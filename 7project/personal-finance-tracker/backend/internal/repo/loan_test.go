@@ -0,0 +1,43 @@
+// backend/internal/repo/loan_test.go
+//
+// Purpose:
+//   Verify annuity payment math, schedule rounding, and payoff projection.
+
+package repo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAmortize_EndsAtZero(t *testing.T) {
+	l := &Loan{Principal: 10000, AnnualRate: 6, TermMonths: 12, StartDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)}
+	if got := MonthlyPayment(l.Principal, l.AnnualRate, l.TermMonths); got != 860.66 {
+		t.Fatalf("expected monthly payment 860.66, got %v", got)
+	}
+	sched := Amortize(l)
+	if len(sched) != 12 {
+		t.Fatalf("expected 12 installments, got %d", len(sched))
+	}
+	if sched[0].DueDate != "2024-02-15" || sched[0].Interest != 50 {
+		t.Fatalf("unexpected first installment: %+v", sched[0])
+	}
+	if last := sched[len(sched)-1]; last.Balance != 0 {
+		t.Fatalf("expected zero final balance, got %+v", last)
+	}
+}
+
+func TestProjectPayoff_ZeroRate(t *testing.T) {
+	l := &Loan{ID: 1, Principal: 1200, TermMonths: 12, StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	payments := []Transaction{
+		{Amount: 100, Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{Amount: 100, Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	p := ProjectPayoff(l, payments)
+	if p.Balance != 1000 || p.RemainingPayments != 10 {
+		t.Fatalf("unexpected payoff: %+v", p)
+	}
+	if p.ProjectedPayoff == nil || *p.ProjectedPayoff != "2025-01-01" {
+		t.Fatalf("unexpected payoff date: %v", p.ProjectedPayoff)
+	}
+}
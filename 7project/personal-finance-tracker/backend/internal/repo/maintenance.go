@@ -0,0 +1,47 @@
+// backend/internal/repo/maintenance.go
+
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// Maintenance is the shared maintenance-mode switch (see migration 027).
+// - Message: shown to clients while enabled
+// - Until: expected end, if known
+type Maintenance struct {
+	Enabled   bool       `json:"enabled"`
+	Message   string     `json:"message"`
+	Until     *time.Time `json:"until"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// MaintenanceRepo reads and sets the maintenance switch.
+type MaintenanceRepo struct{ pool dbConn }
+
+// MaintenanceRepo accessor bound to the Store's pool.
+func (s *Store) MaintenanceRepo() *MaintenanceRepo { return &MaintenanceRepo{pool: s.db()} }
+
+// Get returns the current setting.
+func (r *MaintenanceRepo) Get(ctx context.Context) (*Maintenance, error) {
+	var m Maintenance
+	err := r.pool.QueryRow(ctx, `SELECT enabled, message, until, updated_at FROM maintenance`).
+		Scan(&m.Enabled, &m.Message, &m.Until, &m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Set stores the setting and returns it.
+func (r *MaintenanceRepo) Set(ctx context.Context, m *Maintenance) (*Maintenance, error) {
+	var out Maintenance
+	err := r.pool.QueryRow(ctx, `UPDATE maintenance SET enabled=$1, message=$2, until=$3, updated_at=NOW()
+	                             RETURNING enabled, message, until, updated_at`, m.Enabled, m.Message, m.Until).
+		Scan(&out.Enabled, &out.Message, &out.Until, &out.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,148 @@
+// backend/internal/repo/notification.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Notification is an alert filed in the user's in-app notification center (see
+// migration 053). ReadAt is nil while it is unread.
+type Notification struct {
+	ID        int64             `json:"id"`
+	UserID    int64             `json:"-"`
+	Event     string            `json:"event"`
+	Key       string            `json:"key"`
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data"`
+	CreatedAt time.Time         `json:"created_at"`
+	ReadAt    *time.Time        `json:"read_at"`
+}
+
+// NotificationFilter narrows a notification listing; zero values match everything.
+// - Limit/Offset: pagination (Limit defaults to 50, at most 200)
+type NotificationFilter struct {
+	Unread bool
+	Event  string
+	Limit  int
+	Offset int
+}
+
+// NotificationRepo stores the in-app notification center.
+type NotificationRepo struct{ pool dbConn }
+
+// NotificationRepo accessor bound to the Store's pool.
+func (s *Store) NotificationRepo() *NotificationRepo { return &NotificationRepo{pool: s.db()} }
+
+const notificationCols = `id, user_id, event, key, title, body, data, created_at, read_at`
+
+func scanNotification(row pgx.Row) (*Notification, error) {
+	var n Notification
+	if err := row.Scan(&n.ID, &n.UserID, &n.Event, &n.Key, &n.Title, &n.Body, &n.Data, &n.CreatedAt, &n.ReadAt); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// Add files a notification. Reports false when the user already has one with its
+// key, so redelivering an alert does not file it twice.
+func (r *NotificationRepo) Add(ctx context.Context, n *Notification) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `INSERT INTO notifications (user_id, event, key, title, body, data)
+	                             VALUES ($1, $2, $3, $4, $5, COALESCE($6, '{}'::jsonb))
+	                             ON CONFLICT (user_id, key) DO NOTHING`,
+		n.UserID, n.Event, n.Key, n.Title, n.Body, n.Data)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// List returns the user's notifications matching f, newest first.
+func (r *NotificationRepo) List(ctx context.Context, userID int64, f NotificationFilter) ([]Notification, error) {
+	if f.Limit <= 0 || f.Limit > 200 {
+		f.Limit = 50
+	}
+	f.Offset = max(f.Offset, 0)
+	const q = `SELECT ` + notificationCols + ` FROM notifications
+	           WHERE user_id=$1 AND (NOT $2 OR read_at IS NULL) AND ($3 = '' OR event = $3)
+	           ORDER BY created_at DESC, id DESC LIMIT $4 OFFSET $5`
+	rows, err := r.pool.Query(ctx, q, userID, f.Unread, f.Event, f.Limit, f.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Notification{}
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *n)
+	}
+	return out, rows.Err()
+}
+
+// Unread counts the user's unread notifications by event; events without any are left out.
+func (r *NotificationRepo) Unread(ctx context.Context, userID int64) (map[string]int, error) {
+	rows, err := r.pool.Query(ctx, `SELECT event, COUNT(*) FROM notifications
+	                                WHERE user_id=$1 AND read_at IS NULL GROUP BY event`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[string]int{}
+	for rows.Next() {
+		var event string
+		var n int
+		if err := rows.Scan(&event, &n); err != nil {
+			return nil, err
+		}
+		out[event] = n
+	}
+	return out, rows.Err()
+}
+
+// SetRead marks one notification read or unread. Returns (nil, nil) when not found.
+// Marking a read notification read again keeps when it was first read.
+func (r *NotificationRepo) SetRead(ctx context.Context, userID, id int64, read bool) (*Notification, error) {
+	const q = `UPDATE notifications
+	           SET read_at = CASE WHEN $3 THEN COALESCE(read_at, NOW()) END
+	           WHERE user_id=$1 AND id=$2
+	           RETURNING ` + notificationCols
+	n, err := scanNotification(r.pool.QueryRow(ctx, q, userID, id, read))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return n, err
+}
+
+// SetReadAll marks the user's notifications read or unread: those in ids, or all of
+// them when ids is empty, narrowed to event when it is not "". Returns how many changed.
+func (r *NotificationRepo) SetReadAll(ctx context.Context, userID int64, ids []int64, event string, read bool) (int64, error) {
+	const q = `UPDATE notifications
+	           SET read_at = CASE WHEN $4 THEN NOW() END
+	           WHERE user_id=$1 AND (cardinality($2::bigint[]) = 0 OR id = ANY($2)) AND ($3 = '' OR event = $3)
+	             AND (read_at IS NULL) = $4`
+	if ids == nil {
+		ids = []int64{}
+	}
+	ct, err := r.pool.Exec(ctx, q, userID, ids, event, read)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
+
+// Cleanup deletes notifications filed before cutoff.
+func (r *NotificationRepo) Cleanup(ctx context.Context, cutoff time.Time) (int64, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM notifications WHERE created_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
@@ -0,0 +1,232 @@
+// backend/internal/repo/oauth.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// OAuthClient is a third-party app registered by a user (see migration 049). Its
+// secret is only known when it is registered.
+// - ClientID: public identifier the app sends in OAuth requests
+// - RedirectURIs: where users may be sent back with a code; requests must match one exactly
+type OAuthClient struct {
+	ID           int64     `json:"id"`
+	UserID       int64     `json:"user_id"`
+	Name         string    `json:"name"`
+	ClientID     string    `json:"client_id"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// OAuthCode is what a user approved on the consent screen, redeemed once for tokens.
+// - ClientID: the oauth_clients row, not the public client_id
+// - CodeChallenge: PKCE S256 challenge, nil when the app did not send one
+type OAuthCode struct {
+	ClientID      int64
+	UserID        int64
+	RedirectURI   string
+	Scopes        []string
+	CodeChallenge *string
+}
+
+// OAuthToken is a grant of Scopes to an app on a user's behalf.
+type OAuthToken struct {
+	ID              int64
+	ClientID        int64
+	UserID          int64
+	Scopes          []string
+	AccessExpiresAt time.Time
+}
+
+// OAuthGrant is an app the user has authorized, as listed in their settings.
+type OAuthGrant struct {
+	ClientID   string     `json:"client_id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// OAuthRepo stores OAuth clients, codes and tokens by hash (hashed like API keys).
+// Client lookups, code redemption and token authentication work without a tenant.
+type OAuthRepo struct{ pool dbConn }
+
+// OAuthRepo accessor bound to the Store's pool.
+func (s *Store) OAuthRepo() *OAuthRepo { return &OAuthRepo{pool: s.db()} }
+
+const (
+	oauthClientCols = `id, user_id, name, client_id, redirect_uris, created_at, updated_at`
+	oauthTokenCols  = `id, client_id, user_id, scopes, access_expires_at`
+)
+
+func scanOAuthClient(row pgx.Row) (*OAuthClient, error) {
+	var c OAuthClient
+	if err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.ClientID, &c.RedirectURIs, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func scanOAuthToken(row pgx.Row) (*OAuthToken, error) {
+	var t OAuthToken
+	err := row.Scan(&t.ID, &t.ClientID, &t.UserID, &t.Scopes, &t.AccessExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListClients returns the apps the user registered, newest first.
+func (r *OAuthRepo) ListClients(ctx context.Context, userID int64) ([]OAuthClient, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+oauthClientCols+` FROM oauth_clients WHERE user_id=$1 ORDER BY id DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []OAuthClient{}
+	for rows.Next() {
+		c, err := scanOAuthClient(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *c)
+	}
+	return out, rows.Err()
+}
+
+// CreateClient registers an app for the user with its public clientID and secret.
+func (r *OAuthRepo) CreateClient(ctx context.Context, userID int64, name, clientID, secret string, redirectURIs []string) (*OAuthClient, error) {
+	const q = `INSERT INTO oauth_clients (user_id, name, client_id, secret_hash, redirect_uris)
+	           VALUES ($1,$2,$3,$4,$5) RETURNING ` + oauthClientCols
+	return scanOAuthClient(r.pool.QueryRow(ctx, q, userID, name, clientID, hashAPIKey(secret), redirectURIs))
+}
+
+// DeleteClient removes an app the user registered, revoking every grant to it.
+func (r *OAuthRepo) DeleteClient(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM oauth_clients WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// Client returns the app with the public clientID, or (nil, nil) when there is none.
+func (r *OAuthRepo) Client(ctx context.Context, clientID string) (*OAuthClient, error) {
+	c, err := scanOAuthClient(r.pool.QueryRow(ctx, `SELECT `+oauthClientCols+` FROM oauth_clients WHERE client_id=$1`, clientID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return c, err
+}
+
+// VerifyClient returns the app with clientID if secret is its secret, else (nil, nil).
+func (r *OAuthRepo) VerifyClient(ctx context.Context, clientID, secret string) (*OAuthClient, error) {
+	const q = `SELECT ` + oauthClientCols + ` FROM oauth_clients WHERE client_id=$1 AND secret_hash=$2`
+	c, err := scanOAuthClient(r.pool.QueryRow(ctx, q, clientID, hashAPIKey(secret)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return c, err
+}
+
+// CreateCode stores an approved authorization as code, valid for ttl. Expired codes
+// the user never redeemed are dropped on the way.
+func (r *OAuthRepo) CreateCode(ctx context.Context, code string, a OAuthCode, ttl time.Duration) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM oauth_codes WHERE user_id=$1 AND expires_at < NOW()`, a.UserID); err != nil {
+		return err
+	}
+	_, err := r.pool.Exec(ctx, `INSERT INTO oauth_codes (code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, expires_at)
+	                            VALUES ($1,$2,$3,$4,$5,$6,$7)`,
+		hashAPIKey(code), a.ClientID, a.UserID, a.RedirectURI, a.Scopes, a.CodeChallenge, time.Now().Add(ttl))
+	return err
+}
+
+// TakeCode redeems code: it is deleted whatever happens next, so it works only once.
+// Returns (nil, nil) when the code is unknown, already used or expired.
+func (r *OAuthRepo) TakeCode(ctx context.Context, code string) (*OAuthCode, error) {
+	const q = `DELETE FROM oauth_codes WHERE code_hash=$1
+	           RETURNING client_id, user_id, redirect_uri, scopes, code_challenge, expires_at > NOW()`
+	var a OAuthCode
+	var live bool
+	err := r.pool.QueryRow(ctx, q, hashAPIKey(code)).Scan(&a.ClientID, &a.UserID, &a.RedirectURI, &a.Scopes, &a.CodeChallenge, &live)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !live) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// CreateToken grants scopes to the app for the user, with access valid for ttl and
+// refresh valid until the grant is revoked.
+func (r *OAuthRepo) CreateToken(ctx context.Context, clientID, userID int64, scopes []string, access, refresh string, ttl time.Duration) (*OAuthToken, error) {
+	const q = `INSERT INTO oauth_tokens (client_id, user_id, scopes, access_hash, refresh_hash, access_expires_at)
+	           VALUES ($1,$2,$3,$4,$5,$6) RETURNING ` + oauthTokenCols
+	return scanOAuthToken(r.pool.QueryRow(ctx, q, clientID, userID, scopes, hashAPIKey(access), hashAPIKey(refresh), time.Now().Add(ttl)))
+}
+
+// Refresh replaces the grant's tokens when refresh is its current refresh token and it
+// belongs to the app; the old tokens stop working. Returns (nil, nil) otherwise.
+func (r *OAuthRepo) Refresh(ctx context.Context, clientID int64, refresh, access, newRefresh string, ttl time.Duration) (*OAuthToken, error) {
+	const q = `UPDATE oauth_tokens SET access_hash=$3, refresh_hash=$4, access_expires_at=$5
+	           WHERE refresh_hash=$1 AND client_id=$2 RETURNING ` + oauthTokenCols
+	return scanOAuthToken(r.pool.QueryRow(ctx, q, hashAPIKey(refresh), clientID, hashAPIKey(access), hashAPIKey(newRefresh), time.Now().Add(ttl)))
+}
+
+// Authenticate returns the grant whose unexpired access token is access, recording its
+// use at most once a minute, or (nil, nil) when there is none.
+func (r *OAuthRepo) Authenticate(ctx context.Context, access string) (*OAuthToken, error) {
+	hash := hashAPIKey(access)
+	t, err := scanOAuthToken(r.pool.QueryRow(ctx, `SELECT `+oauthTokenCols+` FROM oauth_tokens
+	                                                WHERE access_hash=$1 AND access_expires_at > NOW()`, hash))
+	if t == nil || err != nil {
+		return t, err
+	}
+	_, err = r.pool.Exec(ctx, `UPDATE oauth_tokens SET last_used_at=NOW()
+	                           WHERE id=$1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`, t.ID)
+	return t, err
+}
+
+// ListGrants returns the apps the user has authorized with the scopes granted, one
+// entry per grant, newest first.
+func (r *OAuthRepo) ListGrants(ctx context.Context, userID int64) ([]OAuthGrant, error) {
+	rows, err := r.pool.Query(ctx, `SELECT c.client_id, c.name, t.scopes, t.created_at, t.last_used_at
+	                                FROM oauth_tokens t JOIN oauth_clients c ON c.id = t.client_id
+	                                WHERE t.user_id=$1 ORDER BY t.id DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []OAuthGrant{}
+	for rows.Next() {
+		var g OAuthGrant
+		if err := rows.Scan(&g.ClientID, &g.Name, &g.Scopes, &g.CreatedAt, &g.LastUsedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
+
+// RevokeGrants revokes every grant the user gave the app with the public clientID;
+// false when there was none.
+func (r *OAuthRepo) RevokeGrants(ctx context.Context, userID int64, clientID string) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM oauth_tokens t USING oauth_clients c
+	                             WHERE c.id = t.client_id AND t.user_id=$1 AND c.client_id=$2`, userID, clientID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
@@ -0,0 +1,85 @@
+// backend/internal/repo/outbox.go
+
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent is a change event waiting for delivery (see migration 031).
+// - Topic: "<entity>.<created|updated|deleted>", e.g. "budgets.updated"
+// - Payload: entity, entity_id, action, actor, data (the row), request_id, occurred_at
+// - Attempts: delivery attempts so far, including the current one once claimed
+type OutboxEvent struct {
+	ID        int64           `json:"id"`
+	UserID    int64           `json:"user_id"`
+	Topic     string          `json:"topic"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	Attempts  int             `json:"attempts"`
+}
+
+// OutboxRepo claims and settles outbox events. Events are written by database
+// triggers in the transaction that made the change, never by this repository.
+type OutboxRepo struct{ pool dbConn }
+
+// OutboxRepo accessor bound to the Store's pool.
+func (s *Store) OutboxRepo() *OutboxRepo { return &OutboxRepo{pool: s.db()} }
+
+// Claim leases up to limit due events, oldest first, and counts the attempt. Events
+// whose lease expired (their dispatcher died) are claimed again, so delivery is
+// at least once.
+func (r *OutboxRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]OutboxEvent, error) {
+	const q = `UPDATE outbox SET attempts=attempts+1, locked_at=NOW()
+	           WHERE id IN (
+	               SELECT id FROM outbox
+	               WHERE delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= NOW()
+	                 AND (locked_at IS NULL OR locked_at < NOW() - $2 * INTERVAL '1 second')
+	               ORDER BY id
+	               FOR UPDATE SKIP LOCKED
+	               LIMIT $1)
+	           RETURNING id, user_id, topic, payload, created_at, attempts`
+	rows, err := r.pool.Query(ctx, q, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []OutboxEvent
+	for rows.Next() {
+		var e OutboxEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Topic, &e.Payload, &e.CreatedAt, &e.Attempts); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// Delivered marks claimed events as delivered.
+func (r *OutboxRepo) Delivered(ctx context.Context, ids []int64) error {
+	_, err := r.pool.Exec(ctx, `UPDATE outbox SET delivered_at=NOW(), locked_at=NULL, last_error=NULL WHERE id = ANY($1)`, ids)
+	return err
+}
+
+// Fail records a failed delivery. With retryAt the event is retried then; without
+// it the event is given up on.
+func (r *OutboxRepo) Fail(ctx context.Context, id int64, msg string, retryAt *time.Time) error {
+	const q = `UPDATE outbox
+	           SET next_attempt_at=COALESCE($3, next_attempt_at),
+	               failed_at=CASE WHEN $3::timestamptz IS NULL THEN NOW() END,
+	               locked_at=NULL, last_error=$2
+	           WHERE id=$1`
+	_, err := r.pool.Exec(ctx, q, id, msg, retryAt)
+	return err
+}
+
+// Cleanup deletes delivered and failed events created before cutoff.
+func (r *OutboxRepo) Cleanup(ctx context.Context, cutoff time.Time) (int64, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM outbox WHERE (delivered_at IS NOT NULL OR failed_at IS NOT NULL) AND created_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
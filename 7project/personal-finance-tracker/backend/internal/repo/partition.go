@@ -0,0 +1,28 @@
+// backend/internal/repo/partition.go
+
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// PartitionLead is how far ahead EnsurePartitions keeps monthly transaction partitions.
+const PartitionLead = 12 * 31 * 24 * time.Hour
+
+// ensurePartition creates the monthly transactions partition covering date if it is
+// missing (see migration 024). Inserting a row with no matching partition fails, and
+// back-dated entries can fall outside the range kept ready by EnsurePartitions.
+func ensurePartition(ctx context.Context, q rowQuerier, date time.Time) error {
+	var created bool
+	return q.QueryRow(ctx, `SELECT ensure_transaction_partition($1)`, date).Scan(&created)
+}
+
+// EnsurePartitions creates the monthly transactions partitions from now through
+// PartitionLead ahead and returns how many were missing.
+func (r *TransactionRepo) EnsurePartitions(ctx context.Context, now time.Time) (int, error) {
+	var created int
+	err := r.pool.QueryRow(ctx, `SELECT ensure_transaction_partitions($1, $2)`,
+		now, now.Add(PartitionLead)).Scan(&created)
+	return created, err
+}
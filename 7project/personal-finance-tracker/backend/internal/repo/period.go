@@ -0,0 +1,126 @@
+// backend/internal/repo/period.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrPeriodClosed is returned when a write would touch a transaction dated in a closed month.
+var ErrPeriodClosed = errors.New("period_closed")
+
+// ClosedPeriod is a month locked against changes.
+type ClosedPeriod struct {
+	Month    string    `json:"month"` // YYYY-MM
+	ClosedAt time.Time `json:"closed_at"`
+}
+
+// PeriodRepo closes and reopens months.
+type PeriodRepo struct{ pool dbConn }
+
+// PeriodRepo accessor bound to the Store's pool.
+func (s *Store) PeriodRepo() *PeriodRepo { return &PeriodRepo{pool: s.db()} }
+
+// List returns the user's closed months, most recent first.
+func (r *PeriodRepo) List(ctx context.Context, userID int64) ([]ClosedPeriod, error) {
+	rows, err := r.pool.Query(ctx, `SELECT month, closed_at FROM closed_periods WHERE user_id=$1 ORDER BY month DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []ClosedPeriod{}
+	for rows.Next() {
+		var m time.Time
+		var p ClosedPeriod
+		if err := rows.Scan(&m, &p.ClosedAt); err != nil {
+			return nil, err
+		}
+		p.Month = m.Format("2006-01")
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// Close locks month (YYYY-MM). Closing an already closed month is a no-op.
+// It locks the user row first, which every write to the user's transactions also locks
+// (migration 060), so writes in flight finish before the month closes and none of them
+// lands in it afterwards.
+func (r *PeriodRepo) Close(ctx context.Context, userID int64, month string) (*ClosedPeriod, error) {
+	first, _, err := MonthBounds(month)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM users WHERE id=$1 FOR UPDATE`, userID); err != nil {
+		return nil, err
+	}
+	const q = `INSERT INTO closed_periods (user_id, month) VALUES ($1,$2)
+	           ON CONFLICT (user_id, month) DO UPDATE SET month=EXCLUDED.month
+	           RETURNING closed_at`
+	p := ClosedPeriod{Month: month}
+	if err := tx.QueryRow(ctx, q, userID, first).Scan(&p.ClosedAt); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Reopen unlocks month (YYYY-MM). Returns false when the month was not closed.
+func (r *PeriodRepo) Reopen(ctx context.Context, userID int64, month string) (bool, error) {
+	first, _, err := MonthBounds(month)
+	if err != nil {
+		return false, err
+	}
+	ct, err := r.pool.Exec(ctx, `DELETE FROM closed_periods WHERE user_id=$1 AND month=$2`, userID, first)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// rowQuerier is satisfied by both *pgxpool.Pool and pgx.Tx.
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// ensureOpen returns ErrPeriodClosed if any of dates falls in a closed month of the user.
+// It answers early, before anything is written; the transactions trigger of migration
+// 060 makes the rule hold against a month closed concurrently (see periodErr).
+func ensureOpen(ctx context.Context, q rowQuerier, userID int64, dates ...time.Time) error {
+	months := make([]time.Time, len(dates))
+	for i, d := range dates {
+		months[i] = time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	var closed bool
+	if err := q.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM closed_periods WHERE user_id=$1 AND month = ANY($2))`,
+		userID, months).Scan(&closed); err != nil {
+		return err
+	}
+	if closed {
+		return ErrPeriodClosed
+	}
+	return nil
+}
+
+// periodErr returns ErrPeriodClosed for the error the transactions trigger raises on
+// a write to a closed month (migration 060), and err otherwise.
+func periodErr(err error) error {
+	var pgerr *pgconn.PgError
+	if errors.As(err, &pgerr) && pgerr.ConstraintName == "closed_period" {
+		return ErrPeriodClosed
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+// backend/internal/repo/preferences.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Preferences holds per-user settings that influence how data is presented.
+// WeekStart is the first day of the week used by weekly summaries.
+// Locale is the language of messages sent to the user (i18n); nil when not chosen.
+// BaseCurrency is the ISO 4217 code amounts are recorded in (see migration 055).
+type Preferences struct {
+	WeekStart    time.Weekday
+	Locale       *string
+	BaseCurrency string
+}
+
+// GetPreferences loads the user's preferences. Returns (nil, nil) if the user does not exist.
+func (r *UserRepo) GetPreferences(ctx context.Context, userID int64) (*Preferences, error) {
+	const q = `SELECT week_start, locale, base_currency FROM users WHERE id=$1`
+	var ws int16
+	var out Preferences
+	if err := r.pool.QueryRow(ctx, q, userID).Scan(&ws, &out.Locale, &out.BaseCurrency); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	out.WeekStart = time.Weekday(ws)
+	return &out, nil
+}
+
+// UpdatePreferences stores the user's preferences and returns the saved values.
+// An empty BaseCurrency keeps the current one. Returns (nil, nil) if the user does not exist.
+func (r *UserRepo) UpdatePreferences(ctx context.Context, userID int64, p *Preferences) (*Preferences, error) {
+	const q = `UPDATE users SET week_start=$2, locale=$3, base_currency=COALESCE(NULLIF($4, ''), base_currency)
+	           WHERE id=$1 RETURNING week_start, locale, base_currency`
+	var ws int16
+	var out Preferences
+	if err := r.pool.QueryRow(ctx, q, userID, int16(p.WeekStart), p.Locale, p.BaseCurrency).Scan(&ws, &out.Locale, &out.BaseCurrency); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	out.WeekStart = time.Weekday(ws)
+	return &out, nil
+}
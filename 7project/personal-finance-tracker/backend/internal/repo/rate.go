@@ -0,0 +1,108 @@
+// backend/internal/repo/rate.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RateTable lists exchange rates for one base currency on one day.
+// Rates maps a quote currency to the amount of it bought by 1 unit of Base.
+type RateTable struct {
+	Base   string             `json:"base"`
+	Date   string             `json:"date"` // YYYY-MM-DD of the rates used (on or before the requested day)
+	Source string             `json:"source"`
+	Rates  map[string]float64 `json:"rates"`
+}
+
+// RateRepo stores and reads daily exchange rates.
+type RateRepo struct{ pool dbConn }
+
+// RateRepo accessor bound to the Store's pool.
+func (s *Store) RateRepo() *RateRepo { return &RateRepo{pool: s.db()} }
+
+// SaveRates upserts the rates published for date; re-fetching a day overwrites it.
+func (r *RateRepo) SaveRates(ctx context.Context, date time.Time, base string, rates map[string]float64, source string) error {
+	const q = `INSERT INTO exchange_rates (date, base, quote, rate, source)
+	           VALUES ($1,$2,$3,$4,$5)
+	           ON CONFLICT (date, base, quote)
+	           DO UPDATE SET rate=EXCLUDED.rate, source=EXCLUDED.source, fetched_at=NOW()`
+	batch := &pgx.Batch{}
+	for quote, rate := range rates {
+		batch.Queue(q, date, base, quote, rate, source)
+	}
+	return r.pool.SendBatch(ctx, batch).Close()
+}
+
+// Rates returns the rates for base on the latest day on or before on.
+// Days stored under another base are converted through that base (cross rates).
+// Returns (nil, nil) when no rates are available for that day or base.
+func (r *RateRepo) Rates(ctx context.Context, base string, on time.Time) (*RateTable, error) {
+	// Pick the most recent day, preferring rows already stored under the requested base.
+	const pick = `SELECT date, base, source FROM exchange_rates
+	              WHERE date <= $1
+	              ORDER BY date DESC, (base = $2) DESC
+	              LIMIT 1`
+	var (
+		day            time.Time
+		stored, source string
+	)
+	err := r.pool.QueryRow(ctx, pick, on, base).Scan(&day, &stored, &source)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pool.Query(ctx, `SELECT quote, rate::float8 FROM exchange_rates WHERE date=$1 AND base=$2`, day, stored)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	raw := map[string]float64{}
+	for rows.Next() {
+		var q string
+		var v float64
+		if err := rows.Scan(&q, &v); err != nil {
+			return nil, err
+		}
+		raw[q] = v
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rebased, ok := Rebase(stored, raw, base)
+	if !ok {
+		return nil, nil
+	}
+	return &RateTable{Base: base, Date: day.Format("2006-01-02"), Source: source, Rates: rebased}, nil
+}
+
+// Rebase converts rates quoted against from into rates quoted against to.
+// Returns false when to is neither from nor one of its quotes.
+func Rebase(from string, rates map[string]float64, to string) (map[string]float64, bool) {
+	out := make(map[string]float64, len(rates))
+	if from == to {
+		for k, v := range rates {
+			out[k] = v
+		}
+		return out, true
+	}
+	pivot, ok := rates[to]
+	if !ok || pivot <= 0 {
+		return nil, false
+	}
+	out[from] = 1 / pivot
+	for k, v := range rates {
+		if k != to {
+			out[k] = v / pivot
+		}
+	}
+	return out, true
+}
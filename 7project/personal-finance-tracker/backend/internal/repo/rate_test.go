@@ -0,0 +1,36 @@
+// backend/internal/repo/rate_test.go
+//
+// Purpose:
+//   Verify cross-rate conversion when rates are requested for a non-stored base.
+
+package repo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRebase(t *testing.T) {
+	eur := map[string]float64{"USD": 1.25, "GBP": 0.8}
+
+	got, ok := Rebase("EUR", eur, "USD")
+	if !ok {
+		t.Fatalf("expected USD to be a valid base")
+	}
+	want := map[string]float64{"EUR": 0.8, "GBP": 0.64}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for k, v := range want {
+		if math.Abs(got[k]-v) > 1e-9 {
+			t.Fatalf("%s: expected %v, got %v", k, v, got[k])
+		}
+	}
+
+	if _, ok := Rebase("EUR", eur, "JPY"); ok {
+		t.Fatalf("expected JPY to be rejected")
+	}
+	if same, ok := Rebase("EUR", eur, "EUR"); !ok || same["USD"] != 1.25 {
+		t.Fatalf("expected identity rebase, got %v", same)
+	}
+}
@@ -0,0 +1,193 @@
+// backend/internal/repo/recurring.go
+
+package repo
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RecurringCharge describes a likely subscription or other repeating expense.
+// - Payee: normalized transaction description shared by all occurrences
+// - Interval: "weekly" | "biweekly" | "monthly" | "quarterly" | "yearly"
+// - AvgAmount: mean amount across occurrences
+// - NextExpected: LastDate advanced by the median gap between occurrences
+type RecurringCharge struct {
+	Payee        string  `json:"payee"`
+	CategoryID   *int64  `json:"category_id"`
+	Interval     string  `json:"interval"`
+	AvgAmount    float64 `json:"avg_amount"`
+	Occurrences  int     `json:"occurrences"`
+	FirstDate    string  `json:"first_date"`    // YYYY-MM-DD
+	LastDate     string  `json:"last_date"`     // YYYY-MM-DD
+	NextExpected string  `json:"next_expected"` // YYYY-MM-DD
+}
+
+// recurringInterval maps a typical gap between charges (in days) to a label.
+// Min/Max bound the accepted gap to tolerate weekends and short/long months.
+type recurringInterval struct {
+	Label    string
+	Min, Max int
+}
+
+var recurringIntervals = []recurringInterval{
+	{"weekly", 6, 8},
+	{"biweekly", 13, 16},
+	{"monthly", 27, 33},
+	{"quarterly", 85, 97},
+	{"yearly", 355, 375},
+}
+
+const (
+	// recurringMinOccurrences is the smallest number of charges considered a pattern.
+	recurringMinOccurrences = 3
+	// recurringAmountTolerance is the allowed relative deviation of each amount from the median.
+	recurringAmountTolerance = 0.10
+)
+
+// Recurring scans expense history since the given date and returns detected recurring charges,
+// ordered by average amount (largest first).
+func (r *ReportRepo) Recurring(ctx context.Context, userID int64, since time.Time) ([]RecurringCharge, error) {
+	q := `
+SELECT category_id, (` + r.conv.amount("amount", "date") + `)::numeric(12,2), date, description
+FROM transactions
+WHERE user_id=$1 AND type='expense' AND date >= $2 AND description <> ''
+ORDER BY date, id
+`
+	rows, err := r.read.Query(ctx, q, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txns []Transaction
+	for rows.Next() {
+		var t Transaction
+		if err := rows.Scan(&t.CategoryID, &t.Amount, &t.Date, &t.Description); err != nil {
+			return nil, err
+		}
+		if err := r.crypt.open(ctx, userID, &t.Description); err != nil {
+			return nil, err
+		}
+		txns = append(txns, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return DetectRecurring(txns), nil
+}
+
+// DetectRecurring groups expenses by normalized description and keeps groups whose
+// amounts are stable and whose gaps match one of the known billing intervals.
+// Input transactions are expected in ascending date order.
+func DetectRecurring(txns []Transaction) []RecurringCharge {
+	groups := map[string][]Transaction{}
+	var order []string
+	for _, t := range txns {
+		key := normalizePayee(t.Description)
+		if key == "" {
+			continue
+		}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], t)
+	}
+
+	out := []RecurringCharge{}
+	for _, key := range order {
+		g := groups[key]
+		if len(g) < recurringMinOccurrences {
+			continue
+		}
+
+		// Amounts must all sit close to the median charge.
+		amounts := make([]float64, len(g))
+		for i, t := range g {
+			amounts[i] = t.Amount
+		}
+		medAmount := median(amounts)
+		stable := true
+		var sum float64
+		for _, a := range amounts {
+			if medAmount == 0 || math.Abs(a-medAmount)/medAmount > recurringAmountTolerance {
+				stable = false
+				break
+			}
+			sum += a
+		}
+		if !stable {
+			continue
+		}
+
+		// Every gap must fall within the band of the interval matched by the median gap.
+		gaps := make([]float64, 0, len(g)-1)
+		for i := 1; i < len(g); i++ {
+			gaps = append(gaps, g[i].Date.Sub(g[i-1].Date).Hours()/24)
+		}
+		medGap := median(gaps)
+		iv, ok := matchInterval(medGap)
+		if !ok {
+			continue
+		}
+		regular := true
+		for _, d := range gaps {
+			if int(math.Round(d)) < iv.Min || int(math.Round(d)) > iv.Max {
+				regular = false
+				break
+			}
+		}
+		if !regular {
+			continue
+		}
+
+		last := g[len(g)-1]
+		out = append(out, RecurringCharge{
+			Payee:        key,
+			CategoryID:   last.CategoryID,
+			Interval:     iv.Label,
+			AvgAmount:    round2(sum / float64(len(g))),
+			Occurrences:  len(g),
+			FirstDate:    g[0].Date.Format("2006-01-02"),
+			LastDate:     last.Date.Format("2006-01-02"),
+			NextExpected: last.Date.AddDate(0, 0, int(math.Round(medGap))).Format("2006-01-02"),
+		})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].AvgAmount > out[j].AvgAmount })
+	return out
+}
+
+// normalizePayee lowercases a description and collapses whitespace so that
+// "NETFLIX  " and "Netflix" are treated as the same payee.
+func normalizePayee(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// matchInterval returns the billing interval whose band contains the gap (in days).
+func matchInterval(gap float64) (recurringInterval, bool) {
+	d := int(math.Round(gap))
+	for _, iv := range recurringIntervals {
+		if d >= iv.Min && d <= iv.Max {
+			return iv, true
+		}
+	}
+	return recurringInterval{}, false
+}
+
+// median returns the median of vs without modifying the input slice.
+func median(vs []float64) float64 {
+	if len(vs) == 0 {
+		return 0
+	}
+	s := append([]float64(nil), vs...)
+	sort.Float64s(s)
+	mid := len(s) / 2
+	if len(s)%2 == 0 {
+		return (s[mid-1] + s[mid]) / 2
+	}
+	return s[mid]
+}
@@ -0,0 +1,207 @@
+// backend/internal/repo/recurring_rule.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RecurringRule is the repository-layer DTO mirroring the recurring_rules table.
+// - Interval: "weekly" | "biweekly" | "monthly" | "quarterly" | "yearly"
+// - StartDate: first occurrence; EndDate optionally stops the recurrence
+// - Active: only active rules generate transactions; new rules start inactive
+// - GeneratedThrough: last date transactions were generated for (nil until the first run)
+type RecurringRule struct {
+	ID               int64      `json:"id"`
+	UserID           int64      `json:"user_id"`
+	CategoryID       *int64     `json:"category_id"`
+	Type             string     `json:"type"` // "income" | "expense"
+	Amount           float64    `json:"amount"`
+	Description      string     `json:"description"`
+	Interval         string     `json:"interval"`
+	StartDate        time.Time  `json:"start_date"`
+	EndDate          *time.Time `json:"end_date"`
+	Active           bool       `json:"active"`
+	GeneratedThrough *time.Time `json:"generated_through"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Occurrence is one transaction a rule generates on Date (YYYY-MM-DD).
+type Occurrence struct {
+	Date        string  `json:"date"`
+	Type        string  `json:"type"`
+	Amount      float64 `json:"amount"`
+	CategoryID  *int64  `json:"category_id"`
+	Description string  `json:"description"`
+}
+
+// RulePreview lists the occurrences a rule would generate from From through Until.
+type RulePreview struct {
+	RuleID      int64        `json:"rule_id"`
+	From        string       `json:"from"`  // YYYY-MM-DD
+	Until       string       `json:"until"` // YYYY-MM-DD
+	Count       int          `json:"count"`
+	Total       float64      `json:"total"`
+	Occurrences []Occurrence `json:"occurrences"`
+}
+
+// RecurringRuleRepo provides CRUD for recurring rules and generates their transactions.
+type RecurringRuleRepo struct{ pool dbConn }
+
+// RecurringRuleRepo accessor bound to the Store's pool.
+func (s *Store) RecurringRuleRepo() *RecurringRuleRepo { return &RecurringRuleRepo{pool: s.db()} }
+
+const recurringRuleCols = `id, user_id, category_id, type, amount, description, rule_interval, start_date, end_date,
+                           active, generated_through, created_at, updated_at`
+
+func scanRecurringRule(row pgx.Row) (*RecurringRule, error) {
+	var r RecurringRule
+	if err := row.Scan(&r.ID, &r.UserID, &r.CategoryID, &r.Type, &r.Amount, &r.Description, &r.Interval, &r.StartDate, &r.EndDate,
+		&r.Active, &r.GeneratedThrough, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (r *RecurringRuleRepo) list(ctx context.Context, q string, args ...any) ([]RecurringRule, error) {
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []RecurringRule{}
+	for rows.Next() {
+		rr, err := scanRecurringRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *rr)
+	}
+	return out, rows.Err()
+}
+
+// List returns the user's recurring rules ordered by start date.
+func (r *RecurringRuleRepo) List(ctx context.Context, userID int64) ([]RecurringRule, error) {
+	return r.list(ctx, `SELECT `+recurringRuleCols+` FROM recurring_rules WHERE user_id=$1 ORDER BY start_date, id`, userID)
+}
+
+// Get fetches a rule scoped to the user. Returns (nil, nil) when not found.
+func (r *RecurringRuleRepo) Get(ctx context.Context, userID, id int64) (*RecurringRule, error) {
+	rr, err := scanRecurringRule(r.pool.QueryRow(ctx, `SELECT `+recurringRuleCols+` FROM recurring_rules WHERE user_id=$1 AND id=$2`, userID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return rr, err
+}
+
+// Create inserts a rule and returns the stored row.
+func (r *RecurringRuleRepo) Create(ctx context.Context, rr *RecurringRule) (*RecurringRule, error) {
+	const q = `INSERT INTO recurring_rules (user_id, category_id, type, amount, description, rule_interval, start_date, end_date, active)
+	           VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+	           RETURNING ` + recurringRuleCols
+	return scanRecurringRule(r.pool.QueryRow(ctx, q, rr.UserID, rr.CategoryID, rr.Type, rr.Amount, rr.Description, rr.Interval, rr.StartDate, rr.EndDate, rr.Active))
+}
+
+// Update modifies a rule owned by the user. Occurrences already generated are kept.
+// Returns (nil, nil) when not found.
+func (r *RecurringRuleRepo) Update(ctx context.Context, userID, id int64, rr *RecurringRule) (*RecurringRule, error) {
+	const q = `UPDATE recurring_rules
+	           SET category_id=$3, type=$4, amount=$5, description=$6, rule_interval=$7, start_date=$8, end_date=$9, active=$10
+	           WHERE user_id=$1 AND id=$2
+	           RETURNING ` + recurringRuleCols
+	out, err := scanRecurringRule(r.pool.QueryRow(ctx, q, userID, id, rr.CategoryID, rr.Type, rr.Amount, rr.Description, rr.Interval, rr.StartDate, rr.EndDate, rr.Active))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// Delete removes a rule scoped to the user; transactions it generated are kept.
+func (r *RecurringRuleRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM recurring_rules WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// markGenerated records that a rule's occurrences through date exist.
+func (r *RecurringRuleRepo) markGenerated(ctx context.Context, id int64, through time.Time) error {
+	_, err := r.pool.Exec(ctx, `UPDATE recurring_rules SET generated_through=$2 WHERE id=$1`, id, through)
+	return err
+}
+
+// Preview returns the occurrences the rule would generate through until, starting after
+// the last generated date (at StartDate for a rule that never ran), whether or not the
+// rule is active.
+func Preview(rr *RecurringRule, until time.Time) *RulePreview {
+	from := rr.StartDate
+	if rr.GeneratedThrough != nil {
+		from = rr.GeneratedThrough.AddDate(0, 0, 1)
+	}
+	p := &RulePreview{RuleID: rr.ID, From: from.Format("2006-01-02"), Until: until.Format("2006-01-02"), Occurrences: []Occurrence{}}
+	for _, d := range occurrences(rr.StartDate, rr.EndDate, rr.Interval, from, until.AddDate(0, 0, 1)) {
+		p.Occurrences = append(p.Occurrences, Occurrence{
+			Date: d.Format("2006-01-02"), Type: rr.Type, Amount: rr.Amount, CategoryID: rr.CategoryID, Description: rr.Description,
+		})
+		p.Total += rr.Amount
+	}
+	p.Count = len(p.Occurrences)
+	p.Total = round2(p.Total)
+	return p
+}
+
+// GenerateRecurring creates the transactions of every active rule that have fallen due by
+// today and returns how many were created for each user. Each rule's occurrences are written in one
+// database transaction together with its new GeneratedThrough, so a rerun never
+// duplicates them. Occurrences in a closed month are skipped. A failing rule does not
+// stop the others; its error is returned and it is retried on the next run.
+func (s *Store) GenerateRecurring(ctx context.Context, today time.Time) (map[int64]int, error) {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	rules, err := s.RecurringRuleRepo().list(ctx, `SELECT `+recurringRuleCols+` FROM recurring_rules
+	                                              WHERE active AND start_date <= $1
+	                                                AND (generated_through IS NULL OR generated_through < LEAST($1, end_date))
+	                                              ORDER BY id`, today)
+	if err != nil {
+		return nil, err
+	}
+	created := map[int64]int{}
+	var errs []error
+	for _, rr := range rules {
+		err := s.WithTx(ctx, func(tx *Store) error {
+			n := 0
+			for _, o := range Preview(&rr, today).Occurrences {
+				date, _ := time.Parse("2006-01-02", o.Date)
+				_, err := tx.TransactionRepo().Create(ctx, &Transaction{
+					UserID: rr.UserID, CategoryID: rr.CategoryID, Amount: rr.Amount, Type: rr.Type, Date: date, Description: rr.Description,
+					// The user set the amount up front and no one is there to confirm it now.
+					RuleID: &rr.ID, OverrideLimit: true,
+				})
+				if errors.Is(err, ErrPeriodClosed) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				n++
+			}
+			if err := tx.RecurringRuleRepo().markGenerated(ctx, rr.ID, today); err != nil {
+				return err
+			}
+			if n > 0 {
+				created[rr.UserID] += n
+			}
+			return nil
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return created, errors.Join(errs...)
+}
@@ -0,0 +1,33 @@
+// backend/internal/repo/recurring_rule_test.go
+//
+// Purpose:
+//   Verify Preview lists a rule's occurrences from its start (or after the last
+//   generated date) through until, honouring the end date and month-end clamping.
+
+package repo
+
+import "testing"
+
+func TestPreview(t *testing.T) {
+	end := day("2024-04-30")
+	rr := &RecurringRule{ID: 7, Type: "expense", Amount: 950, Interval: "monthly", StartDate: day("2024-01-31"), EndDate: &end}
+
+	p := Preview(rr, day("2024-12-31"))
+	want := []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30"}
+	if p.Count != len(want) || p.Total != 3800 || p.From != "2024-01-31" {
+		t.Fatalf("preview = %+v", p)
+	}
+	for i, o := range p.Occurrences {
+		if o.Date != want[i] {
+			t.Errorf("occurrence %d = %s, want %s", i, o.Date, want[i])
+		}
+	}
+
+	// Occurrences already generated are not listed again.
+	through := day("2024-02-29")
+	rr.GeneratedThrough = &through
+	p = Preview(rr, day("2024-03-31"))
+	if p.From != "2024-03-01" || p.Count != 1 || p.Occurrences[0].Date != "2024-03-31" {
+		t.Fatalf("preview after generation = %+v", p)
+	}
+}
@@ -0,0 +1,49 @@
+// backend/internal/repo/recurring_test.go
+//
+// Purpose:
+//   Verify the recurring-spend heuristics on in-memory transaction histories.
+
+package repo
+
+import (
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestDetectRecurring_MonthlySubscription(t *testing.T) {
+	txns := []Transaction{
+		{Amount: 12.99, Date: day("2024-01-05"), Description: "Netflix"},
+		{Amount: 40.00, Date: day("2024-01-09"), Description: "Groceries"},
+		{Amount: 12.99, Date: day("2024-02-05"), Description: "NETFLIX "},
+		{Amount: 55.10, Date: day("2024-02-21"), Description: "Groceries"},
+		{Amount: 13.49, Date: day("2024-03-06"), Description: "netflix"},
+		{Amount: 12.00, Date: day("2024-03-30"), Description: "Groceries"},
+	}
+	got := DetectRecurring(txns)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 recurring charge, got %d: %+v", len(got), got)
+	}
+	rc := got[0]
+	if rc.Payee != "netflix" || rc.Interval != "monthly" || rc.Occurrences != 3 {
+		t.Fatalf("unexpected detection: %+v", rc)
+	}
+	if rc.LastDate != "2024-03-06" {
+		t.Fatalf("unexpected last date %s", rc.LastDate)
+	}
+}
+
+func TestDetectRecurring_IrregularGapsIgnored(t *testing.T) {
+	txns := []Transaction{
+		{Amount: 9.99, Date: day("2024-01-01"), Description: "Gym"},
+		{Amount: 9.99, Date: day("2024-01-31"), Description: "Gym"},
+		{Amount: 9.99, Date: day("2024-04-15"), Description: "Gym"},
+	}
+	if got := DetectRecurring(txns); len(got) != 0 {
+		t.Fatalf("expected no detection, got %+v", got)
+	}
+}
@@ -0,0 +1,123 @@
+// backend/internal/repo/replica.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// querier is the read-only subset of *pgxpool.Pool used by list and summary queries.
+// Both the primary pool and a replica router satisfy it. Begin is for reads that need
+// their own transaction, such as streams that lift statement_timeout.
+type querier interface {
+	rowQuerier
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// replicaCooldown is how long reads stay on the primary after the replica failed.
+const replicaCooldown = 30 * time.Second
+
+// replica sends reads to a replica pool, falling back to the primary when the replica
+// cannot be reached. After a failure the replica is skipped for replicaCooldown so a
+// dead replica costs one failed attempt, not one per query.
+type replica struct {
+	pool      *pgxpool.Pool
+	primary   *pgxpool.Pool
+	downUntil atomic.Int64 // unix nanoseconds
+}
+
+// UseReplica routes list and summary queries to pool, a streaming replica of the primary.
+// Reads may lag writes by the replication delay; writes and read-modify-write paths always
+// use the primary. Call it once at startup, before the Store is shared.
+func (s *Store) UseReplica(pool *pgxpool.Pool) {
+	s.replica = &replica{pool: pool, primary: s.Pool}
+}
+
+// reader returns the querier for read-only queries: the replica router when configured,
+// otherwise the primary pool; inside WithTx always the transaction.
+func (s *Store) reader() querier {
+	if s.replica == nil || s.tx != nil {
+		return s.db()
+	}
+	return s.replica
+}
+
+func (r *replica) target() *pgxpool.Pool {
+	if time.Now().UnixNano() < r.downUntil.Load() {
+		return r.primary
+	}
+	return r.pool
+}
+
+// failed records err against the replica and reports whether the query should be retried
+// on the primary. Only connection-level failures count; a query error would fail there too.
+func (r *replica) failed(ctx context.Context, err error) bool {
+	var ce *pgconn.ConnectError
+	if ctx.Err() != nil || !(errors.As(err, &ce) || pgconn.SafeToRetry(err)) {
+		return false
+	}
+	r.downUntil.Store(time.Now().Add(replicaCooldown).UnixNano())
+	slog.Warn("read replica unavailable, using primary", "cooldown", replicaCooldown.String(), "error", err.Error())
+	return true
+}
+
+func (r *replica) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if tx := contextTx(ctx); tx != nil {
+		return tx.Query(ctx, sql, args...)
+	}
+	p := r.target()
+	rows, err := p.Query(ctx, sql, args...)
+	if err != nil && p != r.primary && r.failed(ctx, err) {
+		return r.primary.Query(ctx, sql, args...)
+	}
+	return rows, err
+}
+
+func (r *replica) Begin(ctx context.Context) (pgx.Tx, error) {
+	if tx := contextTx(ctx); tx != nil {
+		return tx.Begin(ctx)
+	}
+	p := r.target()
+	tx, err := p.Begin(ctx)
+	if err != nil && p != r.primary && r.failed(ctx, err) {
+		return r.primary.Begin(ctx)
+	}
+	return tx, err
+}
+
+func (r *replica) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if tx := contextTx(ctx); tx != nil {
+		return tx.QueryRow(ctx, sql, args...)
+	}
+	p := r.target()
+	if p == r.primary {
+		return p.QueryRow(ctx, sql, args...)
+	}
+	return &fallbackRow{r: r, ctx: ctx, sql: sql, args: args, row: p.QueryRow(ctx, sql, args...)}
+}
+
+// fallbackRow re-runs a single-row query on the primary if the replica could not be reached.
+type fallbackRow struct {
+	r    *replica
+	ctx  context.Context
+	sql  string
+	args []any
+	row  pgx.Row
+}
+
+func (f *fallbackRow) Scan(dest ...any) error {
+	err := f.row.Scan(dest...)
+	if err != nil && f.r.failed(f.ctx, err) {
+		return f.r.primary.QueryRow(f.ctx, f.sql, f.args...).Scan(dest...)
+	}
+	return err
+}
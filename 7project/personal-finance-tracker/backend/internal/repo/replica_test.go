@@ -0,0 +1,44 @@
+// backend/internal/repo/replica_test.go
+//
+// Purpose:
+//   Verify read routing: without a replica reads use the primary pool, and an
+//   unreachable replica is taken out of rotation so reads fall back to the primary.
+
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// unreachablePool returns a lazily connecting pool pointed at a closed port.
+func unreachablePool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	p, err := pgxpool.New(context.Background(), "postgres://u:p@127.0.0.1:1/db?connect_timeout=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(p.Close)
+	return p
+}
+
+func TestReplicaFallback(t *testing.T) {
+	primary, replicaPool := unreachablePool(t), unreachablePool(t)
+	s := New(primary)
+	if s.reader() != (poolConn{primary}) {
+		t.Fatal("without a replica reads should use the primary")
+	}
+
+	s.UseReplica(replicaPool)
+	r := s.reader().(*replica)
+	if r.target() != replicaPool {
+		t.Fatal("reads should go to the replica while it is healthy")
+	}
+	var one int
+	_ = r.QueryRow(context.Background(), "SELECT 1").Scan(&one)
+	if r.target() != primary {
+		t.Fatal("an unreachable replica should be skipped during the cooldown")
+	}
+}
@@ -0,0 +1,467 @@
+// backend/internal/repo/report.go
+
+package repo
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"pft/internal/i18n"
+)
+
+// CategoryTotal is the summed amount of one category (and type) within a date range.
+// CategoryID is nil for uncategorized transactions; Name is empty in that case.
+type CategoryTotal struct {
+	CategoryID *int64  `json:"category_id"`
+	Name       string  `json:"category_name"`
+	Type       string  `json:"type"` // "income" | "expense"
+	Total      float64 `json:"total"`
+}
+
+// CategoryDelta compares one category across two periods.
+// Delta is computed as B - A, so a positive delta means more money moved in period B.
+type CategoryDelta struct {
+	CategoryID *int64  `json:"category_id"`
+	Name       string  `json:"category_name"`
+	Type       string  `json:"type"`
+	ATotal     float64 `json:"a_total"`
+	BTotal     float64 `json:"b_total"`
+	Delta      float64 `json:"delta"`
+}
+
+// PeriodComparison is the result of comparing two months.
+// - A/B: compared periods in YYYY-MM
+// - Income*/Expense*: overall totals per period and their deltas (B - A)
+// - Categories: per-category breakdown, largest absolute delta first
+type PeriodComparison struct {
+	A            string          `json:"a"`
+	B            string          `json:"b"`
+	IncomeA      float64         `json:"income_a"`
+	IncomeB      float64         `json:"income_b"`
+	IncomeDelta  float64         `json:"income_delta"`
+	ExpenseA     float64         `json:"expense_a"`
+	ExpenseB     float64         `json:"expense_b"`
+	ExpenseDelta float64         `json:"expense_delta"`
+	Categories   []CategoryDelta `json:"categories"`
+}
+
+// ReportRepo provides read-only analytical queries spanning one or more periods.
+// All of them run on the read replica when one is configured.
+// Amounts are in the user's base currency unless converted (see In).
+type ReportRepo struct {
+	read  querier
+	crypt *fieldCrypt
+	conv  Conversion
+}
+
+// ReportRepo accessor bound to the Store's read pool.
+func (s *Store) ReportRepo() *ReportRepo { return &ReportRepo{read: s.reader(), crypt: s.crypt} }
+
+// MonthBounds returns the first day of month (YYYY-MM) and the first day of the following month.
+// Callers should filter with date >= first AND date < next.
+func MonthBounds(month string) (first, next time.Time, err error) {
+	first, err = time.Parse("2006-01", month)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return first, first.AddDate(0, 1, 0), nil
+}
+
+// CategoryTotals sums transaction amounts per category and type for months in [from, to).
+// Both bounds must be first days of a month; totals come from the monthly_totals table,
+// or from transactions when amounts are converted.
+// Uncategorized transactions are grouped under a nil CategoryID.
+func (r *ReportRepo) CategoryTotals(ctx context.Context, userID int64, from, to time.Time) ([]CategoryTotal, error) {
+	q := `
+SELECT mt.category_id, COALESCE(c.name, ''), mt.type, SUM(mt.total)
+FROM ` + r.conv.totals() + ` mt
+LEFT JOIN categories c ON c.id = mt.category_id AND c.user_id = mt.user_id
+WHERE mt.user_id=$1 AND mt.month >= $2 AND mt.month < $3
+GROUP BY mt.category_id, c.name, mt.type
+ORDER BY mt.type, mt.category_id NULLS LAST
+`
+	rows, err := r.read.Query(ctx, q, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []CategoryTotal
+	for rows.Next() {
+		var ct CategoryTotal
+		if err := rows.Scan(&ct.CategoryID, &ct.Name, &ct.Type, &ct.Total); err != nil {
+			return nil, err
+		}
+		out = append(out, ct)
+	}
+	return out, rows.Err()
+}
+
+// Compare returns per-category and overall deltas between months a and b (both YYYY-MM).
+func (r *ReportRepo) Compare(ctx context.Context, userID int64, a, b string) (*PeriodComparison, error) {
+	aFrom, aTo, err := MonthBounds(a)
+	if err != nil {
+		return nil, err
+	}
+	bFrom, bTo, err := MonthBounds(b)
+	if err != nil {
+		return nil, err
+	}
+	aTotals, err := r.CategoryTotals(ctx, userID, aFrom, aTo)
+	if err != nil {
+		return nil, err
+	}
+	bTotals, err := r.CategoryTotals(ctx, userID, bFrom, bTo)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &PeriodComparison{A: a, B: b, Categories: []CategoryDelta{}}
+
+	// Merge both periods keyed by (category, type); a category missing in one period counts as zero.
+	type key struct {
+		cid int64 // -1 for uncategorized
+		typ string
+	}
+	idx := map[key]int{}
+	merge := func(ct CategoryTotal, inA bool) {
+		k := key{cid: -1, typ: ct.Type}
+		if ct.CategoryID != nil {
+			k.cid = *ct.CategoryID
+		}
+		i, ok := idx[k]
+		if !ok {
+			out.Categories = append(out.Categories, CategoryDelta{CategoryID: ct.CategoryID, Name: ct.Name, Type: ct.Type})
+			i = len(out.Categories) - 1
+			idx[k] = i
+		}
+		if inA {
+			out.Categories[i].ATotal += ct.Total
+		} else {
+			out.Categories[i].BTotal += ct.Total
+		}
+	}
+	for _, ct := range aTotals {
+		merge(ct, true)
+	}
+	for _, ct := range bTotals {
+		merge(ct, false)
+	}
+
+	for i := range out.Categories {
+		cd := &out.Categories[i]
+		cd.Delta = round2(cd.BTotal - cd.ATotal)
+		if cd.Type == "income" {
+			out.IncomeA += cd.ATotal
+			out.IncomeB += cd.BTotal
+		} else {
+			out.ExpenseA += cd.ATotal
+			out.ExpenseB += cd.BTotal
+		}
+	}
+	out.IncomeA, out.IncomeB = round2(out.IncomeA), round2(out.IncomeB)
+	out.ExpenseA, out.ExpenseB = round2(out.ExpenseA), round2(out.ExpenseB)
+	out.IncomeDelta = round2(out.IncomeB - out.IncomeA)
+	out.ExpenseDelta = round2(out.ExpenseB - out.ExpenseA)
+
+	// Biggest movers first answers "why did I spend more?" without client-side sorting.
+	sort.SliceStable(out.Categories, func(i, j int) bool {
+		return math.Abs(out.Categories[i].Delta) > math.Abs(out.Categories[j].Delta)
+	})
+	return out, nil
+}
+
+// round2 rounds a monetary amount to two decimal places (NUMERIC(12,2) precision).
+func round2(v float64) float64 { return math.Round(v*100) / 100 }
+
+// MonthYoY compares one calendar month against the same month of the previous year.
+// *ChangePct are percentage changes ((current - previous) / previous * 100); nil when previous is zero.
+type MonthYoY struct {
+	Month            string   `json:"month"` // YYYY-MM of the reported year
+	Income           float64  `json:"income"`
+	IncomePrev       float64  `json:"income_prev"`
+	IncomeChangePct  *float64 `json:"income_change_pct"`
+	Expense          float64  `json:"expense"`
+	ExpensePrev      float64  `json:"expense_prev"`
+	ExpenseChangePct *float64 `json:"expense_change_pct"`
+}
+
+// CategoryYoY compares a category's yearly total against the previous year.
+type CategoryYoY struct {
+	CategoryID *int64   `json:"category_id"`
+	Name       string   `json:"category_name"`
+	Type       string   `json:"type"`
+	Total      float64  `json:"total"`
+	PrevTotal  float64  `json:"prev_total"`
+	ChangePct  *float64 `json:"change_pct"`
+}
+
+// YearOverYear is the year-over-year report for a calendar year.
+type YearOverYear struct {
+	Year         int           `json:"year"`
+	PreviousYear int           `json:"previous_year"`
+	Months       []MonthYoY    `json:"months"`
+	Categories   []CategoryYoY `json:"categories"`
+}
+
+// YearOverYear compares each month and category of year against the previous year.
+func (r *ReportRepo) YearOverYear(ctx context.Context, userID int64, year int) (*YearOverYear, error) {
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	prevStart := start.AddDate(-1, 0, 0)
+	end := start.AddDate(1, 0, 0)
+
+	q := `
+SELECT EXTRACT(YEAR FROM month)::int, EXTRACT(MONTH FROM month)::int,
+       COALESCE(SUM(total) FILTER (WHERE type='income'), 0),
+       COALESCE(SUM(total) FILTER (WHERE type='expense'), 0)
+FROM ` + r.conv.totals() + ` mt
+WHERE user_id=$1 AND month >= $2 AND month < $3
+GROUP BY 1, 2
+`
+	rows, err := r.read.Query(ctx, q, userID, prevStart, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := &YearOverYear{Year: year, PreviousYear: year - 1, Months: make([]MonthYoY, 12), Categories: []CategoryYoY{}}
+	for i := range out.Months {
+		out.Months[i].Month = time.Date(year, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC).Format("2006-01")
+	}
+	for rows.Next() {
+		var y, m int
+		var inc, exp float64
+		if err := rows.Scan(&y, &m, &inc, &exp); err != nil {
+			return nil, err
+		}
+		mm := &out.Months[m-1]
+		if y == year {
+			mm.Income, mm.Expense = inc, exp
+		} else {
+			mm.IncomePrev, mm.ExpensePrev = inc, exp
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range out.Months {
+		mm := &out.Months[i]
+		mm.IncomeChangePct = changePct(mm.IncomePrev, mm.Income)
+		mm.ExpenseChangePct = changePct(mm.ExpensePrev, mm.Expense)
+	}
+
+	cur, err := r.CategoryTotals(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	prev, err := r.CategoryTotals(ctx, userID, prevStart, start)
+	if err != nil {
+		return nil, err
+	}
+	type key struct {
+		cid int64
+		typ string
+	}
+	idx := map[key]int{}
+	add := func(ct CategoryTotal, current bool) {
+		k := key{cid: -1, typ: ct.Type}
+		if ct.CategoryID != nil {
+			k.cid = *ct.CategoryID
+		}
+		i, ok := idx[k]
+		if !ok {
+			out.Categories = append(out.Categories, CategoryYoY{CategoryID: ct.CategoryID, Name: ct.Name, Type: ct.Type})
+			i = len(out.Categories) - 1
+			idx[k] = i
+		}
+		if current {
+			out.Categories[i].Total += ct.Total
+		} else {
+			out.Categories[i].PrevTotal += ct.Total
+		}
+	}
+	for _, ct := range cur {
+		add(ct, true)
+	}
+	for _, ct := range prev {
+		add(ct, false)
+	}
+	for i := range out.Categories {
+		cy := &out.Categories[i]
+		cy.ChangePct = changePct(cy.PrevTotal, cy.Total)
+	}
+	return out, nil
+}
+
+// changePct returns the percentage change from prev to cur rounded to two decimals,
+// or nil when prev is zero and the change is undefined.
+func changePct(prev, cur float64) *float64 {
+	if prev == 0 {
+		return nil
+	}
+	v := round2((cur - prev) / prev * 100)
+	return &v
+}
+
+// CategoryAverage summarizes monthly spend of one category across a lookback window.
+// Months without spending count as zero so Mean and Median reflect a typical month.
+type CategoryAverage struct {
+	CategoryID   *int64  `json:"category_id"`
+	Name         string  `json:"category_name"`
+	Mean         float64 `json:"mean"`
+	Median       float64 `json:"median"`
+	ActiveMonths int     `json:"active_months"` // months with at least one expense
+}
+
+// SpendAverages is the result of the averages report.
+// From (inclusive) and To (exclusive) are YYYY-MM bounds of the lookback window.
+type SpendAverages struct {
+	Months     int               `json:"months"`
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Categories []CategoryAverage `json:"categories"`
+}
+
+// Averages returns mean and median monthly expense per category over the `months`
+// complete calendar months preceding the month that contains asOf.
+func (r *ReportRepo) Averages(ctx context.Context, userID int64, months int, asOf time.Time) (*SpendAverages, error) {
+	to := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, -months, 0)
+
+	q := `
+SELECT mt.category_id, COALESCE(c.name, ''), mt.month, SUM(mt.total)
+FROM ` + r.conv.totals() + ` mt
+LEFT JOIN categories c ON c.id = mt.category_id AND c.user_id = mt.user_id
+WHERE mt.user_id=$1 AND mt.type='expense' AND mt.month >= $2 AND mt.month < $3
+GROUP BY mt.category_id, c.name, mt.month
+ORDER BY mt.category_id NULLS LAST
+`
+	rows, err := r.read.Query(ctx, q, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := &SpendAverages{Months: months, From: from.Format("2006-01"), To: to.Format("2006-01"), Categories: []CategoryAverage{}}
+	monthly := map[int64][]float64{} // key -1 = uncategorized
+	idx := map[int64]int{}
+	for rows.Next() {
+		var cid *int64
+		var name string
+		var m time.Time
+		var total float64
+		if err := rows.Scan(&cid, &name, &m, &total); err != nil {
+			return nil, err
+		}
+		k := int64(-1)
+		if cid != nil {
+			k = *cid
+		}
+		if _, ok := idx[k]; !ok {
+			out.Categories = append(out.Categories, CategoryAverage{CategoryID: cid, Name: name})
+			idx[k] = len(out.Categories) - 1
+		}
+		monthly[k] = append(monthly[k], total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for k, i := range idx {
+		vals := monthly[k]
+		ca := &out.Categories[i]
+		ca.ActiveMonths = len(vals)
+		// Pad with zero months so the statistics cover the whole window.
+		padded := make([]float64, months)
+		copy(padded, vals)
+		var sum float64
+		for _, v := range padded {
+			sum += v
+		}
+		ca.Mean = round2(sum / float64(months))
+		ca.Median = round2(median(padded))
+	}
+	return out, nil
+}
+
+// FlowNode is a node of the Sankey diagram. Kind is "income", "hub", "expense", or "savings".
+type FlowNode struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// FlowLink is a weighted edge between two nodes, referenced by node ID.
+type FlowLink struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Value  float64 `json:"value"`
+}
+
+// Flows is a nodes/links structure for a Sankey diagram of one period.
+// Income categories flow into a central "income" hub, which flows out to expense
+// categories and, when income exceeds expenses, to a "savings" node. When expenses
+// exceed income, a "deficit" source node makes up the difference.
+type Flows struct {
+	Month string     `json:"month"`
+	Nodes []FlowNode `json:"nodes"`
+	Links []FlowLink `json:"links"`
+}
+
+// Flows builds the income → category flow graph for month (YYYY-MM).
+func (r *ReportRepo) Flows(ctx context.Context, userID int64, month string) (*Flows, error) {
+	first, next, err := MonthBounds(month)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := r.CategoryTotals(ctx, userID, first, next)
+	if err != nil {
+		return nil, err
+	}
+	return buildFlows(month, totals), nil
+}
+
+// buildFlows converts category totals into Sankey nodes and links.
+func buildFlows(month string, totals []CategoryTotal) *Flows {
+	const hub = "hub"
+	out := &Flows{
+		Month: month,
+		Nodes: []FlowNode{{ID: hub, Name: i18n.T(i18n.Default, "report.flows.income"), Kind: "hub"}},
+		Links: []FlowLink{},
+	}
+	nodeID := func(ct CategoryTotal) (string, string) {
+		if ct.CategoryID == nil {
+			return ct.Type + ":uncategorized", i18n.T(i18n.Default, "report.flows.uncategorized")
+		}
+		return ct.Type + ":" + strconv.FormatInt(*ct.CategoryID, 10), ct.Name
+	}
+
+	var income, expense float64
+	for _, ct := range totals {
+		if ct.Total <= 0 {
+			continue
+		}
+		id, name := nodeID(ct)
+		out.Nodes = append(out.Nodes, FlowNode{ID: id, Name: name, Kind: ct.Type})
+		if ct.Type == "income" {
+			income += ct.Total
+			out.Links = append(out.Links, FlowLink{Source: id, Target: hub, Value: round2(ct.Total)})
+		} else {
+			expense += ct.Total
+			out.Links = append(out.Links, FlowLink{Source: hub, Target: id, Value: round2(ct.Total)})
+		}
+	}
+
+	switch diff := round2(income - expense); {
+	case diff > 0:
+		out.Nodes = append(out.Nodes, FlowNode{ID: "savings", Name: i18n.T(i18n.Default, "report.flows.savings"), Kind: "savings"})
+		out.Links = append(out.Links, FlowLink{Source: hub, Target: "savings", Value: diff})
+	case diff < 0:
+		out.Nodes = append(out.Nodes, FlowNode{ID: "deficit", Name: i18n.T(i18n.Default, "report.flows.deficit"), Kind: "income"})
+		out.Links = append(out.Links, FlowLink{Source: "deficit", Target: hub, Value: -diff})
+	}
+	return out
+}
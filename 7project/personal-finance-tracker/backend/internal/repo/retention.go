@@ -0,0 +1,171 @@
+// backend/internal/repo/retention.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RetentionPolicy keeps data of one Target for KeepDays (migration 047).
+// UserID is nil for operator policies, which apply to every user.
+type RetentionPolicy struct {
+	ID        int64     `json:"id"`
+	UserID    *int64    `json:"user_id"`
+	Target    string    `json:"target"`
+	KeepDays  int       `json:"keep_days"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RetentionResult reports what applying (or previewing) a policy deletes.
+// - Cutoff: rows older than this are deleted
+// - Rows: number of rows deleted, or that would be with a dry run
+type RetentionResult struct {
+	Target   string    `json:"target"`
+	UserID   *int64    `json:"user_id"`
+	KeepDays int       `json:"keep_days"`
+	Cutoff   time.Time `json:"cutoff"`
+	DryRun   bool      `json:"dry_run"`
+	Rows     int64     `json:"rows"`
+}
+
+// retentionTable is one table a retention target deletes from.
+// - Old: condition selecting rows older than the cutoff ($1)
+// - Owner: column holding the user id, used to scope user policies
+type retentionTable struct {
+	Name  string
+	Old   string
+	Owner string
+}
+
+// retentionTargets lists the tables of each target in delete order (referrers first).
+// Categories still used by a budget wait for it, as in TrashRepo.Purge, and transactions
+// in a closed month are kept until it is reopened.
+var retentionTargets = map[string][]retentionTable{
+	"trash": {
+		{Name: "budgets", Old: "deleted_at < $1", Owner: "user_id"},
+		{Name: "categories", Old: "deleted_at < $1 AND NOT EXISTS (SELECT 1 FROM budgets b WHERE b.category_id = categories.id)", Owner: "user_id"},
+	},
+	"drafts":       {{Name: "draft_transactions", Old: "created_at < $1", Owner: "user_id"}},
+	"transactions": {{Name: "transactions", Old: "date < $1::date AND NOT EXISTS (SELECT 1 FROM closed_periods p WHERE p.user_id = transactions.user_id AND p.month = date_trunc('month', transactions.date)::date)", Owner: "user_id"}},
+	"jobs":         {{Name: "jobs", Old: "state IN ('done', 'failed') AND COALESCE(finished_at, created_at) < $1"}},
+}
+
+// RetentionTargets are the targets users may set policies for; OperatorRetentionTargets
+// those operators may set. Transactions are left to each user: an operator policy would
+// delete everyone's history.
+var (
+	RetentionTargets         = []string{"trash", "drafts", "transactions"}
+	OperatorRetentionTargets = []string{"trash", "drafts", "jobs"}
+)
+
+// RetentionRepo stores retention policies and applies them.
+type RetentionRepo struct{ pool dbConn }
+
+// RetentionRepo accessor bound to the Store's pool.
+func (s *Store) RetentionRepo() *RetentionRepo { return &RetentionRepo{pool: s.db()} }
+
+const retentionCols = `id, user_id, target, keep_days, created_at, updated_at`
+
+func scanRetentionPolicy(row pgx.Row) (*RetentionPolicy, error) {
+	var p RetentionPolicy
+	if err := row.Scan(&p.ID, &p.UserID, &p.Target, &p.KeepDays, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// List returns the user's policies, or the operator policies when userID is nil.
+func (r *RetentionRepo) List(ctx context.Context, userID *int64) ([]RetentionPolicy, error) {
+	return r.query(ctx, `SELECT `+retentionCols+` FROM retention_policies
+	                     WHERE user_id IS NOT DISTINCT FROM $1 ORDER BY target`, userID)
+}
+
+// All returns every policy, operator policies first.
+func (r *RetentionRepo) All(ctx context.Context) ([]RetentionPolicy, error) {
+	return r.query(ctx, `SELECT `+retentionCols+` FROM retention_policies ORDER BY user_id NULLS FIRST, target`)
+}
+
+func (r *RetentionRepo) query(ctx context.Context, q string, args ...any) ([]RetentionPolicy, error) {
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []RetentionPolicy{}
+	for rows.Next() {
+		p, err := scanRetentionPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *p)
+	}
+	return out, rows.Err()
+}
+
+// Set creates or replaces the policy for target (userID nil for an operator policy).
+func (r *RetentionRepo) Set(ctx context.Context, userID *int64, target string, keepDays int) (*RetentionPolicy, error) {
+	const q = `INSERT INTO retention_policies (user_id, target, keep_days) VALUES ($1,$2,$3)
+	           ON CONFLICT (COALESCE(user_id, 0), target) DO UPDATE SET keep_days=EXCLUDED.keep_days
+	           RETURNING ` + retentionCols
+	return scanRetentionPolicy(r.pool.QueryRow(ctx, q, userID, target, keepDays))
+}
+
+// Delete removes the policy for target; false when there was none.
+func (r *RetentionRepo) Delete(ctx context.Context, userID *int64, target string) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM retention_policies WHERE user_id IS NOT DISTINCT FROM $1 AND target=$2`, userID, target)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// Apply deletes what policy p no longer keeps as of now, limited to its user unless
+// it is an operator policy. A dry run deletes the same way and rolls back, so its count
+// is exact. Deletions are not written to the audit log, which would otherwise keep a
+// copy of every purged row.
+func (r *RetentionRepo) Apply(ctx context.Context, p RetentionPolicy, now time.Time, dryRun bool) (*RetentionResult, error) {
+	tables, ok := retentionTargets[p.Target]
+	if !ok {
+		return nil, fmt.Errorf("unknown retention target %q", p.Target)
+	}
+	res := &RetentionResult{Target: p.Target, UserID: p.UserID, KeepDays: p.KeepDays,
+		Cutoff: now.UTC().AddDate(0, 0, -p.KeepDays), DryRun: dryRun}
+	if p.UserID != nil && tables[0].Owner == "" {
+		return nil, errors.New("retention target " + p.Target + " is for operators only")
+	}
+	if p.UserID == nil && !slices.Contains(OperatorRetentionTargets, p.Target) {
+		return nil, errors.New("retention target " + p.Target + " is for users only")
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	if _, err := tx.Exec(ctx, `SELECT set_config('app.audit', 'off', true)`); err != nil {
+		return nil, err
+	}
+	for _, t := range tables {
+		where, args := t.Old, []any{res.Cutoff}
+		if p.UserID != nil {
+			where += " AND " + t.Owner + " = $2"
+			args = append(args, *p.UserID)
+		}
+		ct, err := tx.Exec(ctx, `DELETE FROM `+t.Name+` WHERE `+where, args...)
+		if err != nil {
+			return nil, fmt.Errorf("retention %s: %w", t.Name, err)
+		}
+		res.Rows += ct.RowsAffected()
+	}
+	if dryRun {
+		return res, nil
+	}
+	return res, tx.Commit(ctx)
+}
@@ -0,0 +1,223 @@
+// backend/internal/repo/score.go
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"pft/internal/i18n"
+)
+
+// HealthComponent is one ingredient of the financial health score.
+// - Key: "savings_rate" | "budget_adherence" | "emergency_fund" | "debt_ratio"
+// - Value: the underlying metric (rate, score, months or ratio); nil when it cannot be computed
+// - Score: 0..100 rating of Value; nil when unavailable (the component is then left out)
+// - Weight: share of the overall score before re-normalizing over available components
+// - Explanation: English summary; Message and Args re-render it in another language (i18n.T)
+type HealthComponent struct {
+	Key         string   `json:"key"`
+	Value       *float64 `json:"value"`
+	Score       *float64 `json:"score"`
+	Weight      float64  `json:"weight"`
+	Explanation string   `json:"explanation"`
+	Message     string   `json:"-"`
+	Args        []string `json:"-"`
+}
+
+// explain sets the component's message and its English rendering.
+func (c *HealthComponent) explain(key string, args ...string) {
+	c.Message, c.Args = key, args
+	c.Explanation = i18n.T(i18n.Default, key, args...)
+}
+
+// HealthScore is a composite 0..100 rating of a user's finances for a month.
+// Score is nil when none of the components could be computed.
+type HealthScore struct {
+	Month      string            `json:"month"` // YYYY-MM
+	Score      *float64          `json:"score"`
+	Components []HealthComponent `json:"components,omitempty"`
+}
+
+// HealthInputs are the raw figures the health score is derived from.
+// - SavingsRate: trailing 3-month average savings rate; nil without income
+// - Adherence: budget adherence of the month; nil without budgets
+// - AvgMonthlyIncome/AvgMonthlyExpense: trailing averages over complete months
+// - LiquidSavings: emergency fund balance (FundDesignated) or net savings to date
+// - FundTargetMonths: months of expenses the fund should cover; defaults to 6 when zero
+// - MonthlyDebtPayments: installments of active loans
+type HealthInputs struct {
+	SavingsRate         *float64
+	Adherence           *BudgetAdherence
+	AvgMonthlyIncome    float64
+	AvgMonthlyExpense   float64
+	LiquidSavings       float64
+	FundDesignated      bool
+	FundTargetMonths    float64
+	MonthlyDebtPayments float64
+}
+
+// Health score tuning: the metric value that earns a full score for each component.
+const (
+	healthTargetSavingsRate = 0.20 // 20% of income saved
+	healthTargetFundMonths  = 6    // default: six months of expenses covered
+	healthMaxDebtRatio      = 0.50 // debt payments at half of income score zero
+	healthLookbackMonths    = 6
+)
+
+// Health computes the financial health score for month (YYYY-MM).
+func (r *DashboardRepo) Health(ctx context.Context, userID int64, month string) (*HealthScore, error) {
+	first, next, err := MonthBounds(month)
+	if err != nil {
+		return nil, err
+	}
+
+	var m MonthSummary
+	if err := r.savingsRates(ctx, userID, first, &m); err != nil {
+		return nil, err
+	}
+	adh, err := r.adherence(ctx, userID, month, first)
+	if err != nil {
+		return nil, err
+	}
+	in := HealthInputs{SavingsRate: m.SavingsRateAvg3, Adherence: adh}
+
+	if in.AvgMonthlyIncome, in.AvgMonthlyExpense, err = trailingAverages(ctx, r.read, userID, first); err != nil {
+		return nil, err
+	}
+
+	// Prefer the designated emergency fund accounts; without any, fall back to net
+	// savings (everything earned minus everything spent up to the end of the month).
+	target, balance, accounts, err := (&EmergencyFundRepo{pool: r.pool}).fundTotals(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	in.FundTargetMonths = target
+	if accounts > 0 {
+		in.LiquidSavings = balance
+		in.FundDesignated = true
+	} else {
+		const q = `SELECT COALESCE(SUM(CASE WHEN type='income' THEN total ELSE -total END), 0)::float8
+		           FROM monthly_totals WHERE user_id=$1 AND month < $2`
+		if err := r.read.QueryRow(ctx, q, userID, next).Scan(&in.LiquidSavings); err != nil {
+			return nil, err
+		}
+	}
+
+	debt, err := r.monthlyDebtPayments(ctx, userID, first)
+	if err != nil {
+		return nil, err
+	}
+	in.MonthlyDebtPayments = debt
+
+	out := ScoreHealth(in)
+	out.Month = month
+	return out, nil
+}
+
+// monthlyDebtPayments sums the regular installment of loans still running on asOf.
+func (r *DashboardRepo) monthlyDebtPayments(ctx context.Context, userID int64, asOf time.Time) (float64, error) {
+	const q = `SELECT principal, annual_rate, term_months FROM loans
+	           WHERE user_id=$1 AND start_date + term_months * INTERVAL '1 month' > $2`
+	rows, err := r.read.Query(ctx, q, userID, asOf)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var total float64
+	for rows.Next() {
+		var principal, rate float64
+		var term int
+		if err := rows.Scan(&principal, &rate, &term); err != nil {
+			return 0, err
+		}
+		total += MonthlyPayment(principal, rate, term)
+	}
+	return round2(total), rows.Err()
+}
+
+// ScoreHealth rates each component and combines the available ones into a weighted score.
+func ScoreHealth(in HealthInputs) *HealthScore {
+	out := &HealthScore{}
+
+	savings := HealthComponent{Key: "savings_rate", Weight: 0.30}
+	if in.SavingsRate != nil {
+		v := *in.SavingsRate
+		savings.Value = &v
+		savings.Score = floatPtr(clampScore(v / healthTargetSavingsRate * 100))
+		savings.explain("score.savings", "rate", fmt.Sprintf("%.1f", v*100), "target", fmt.Sprintf("%.0f", healthTargetSavingsRate*100))
+	} else {
+		savings.explain("score.savings.none")
+	}
+
+	budget := HealthComponent{Key: "budget_adherence", Weight: 0.20}
+	if in.Adherence != nil {
+		v := in.Adherence.Score
+		budget.Value = &v
+		budget.Score = floatPtr(v)
+		budget.explain("score.budget", "kept", strconv.Itoa(in.Adherence.Respected), "total", strconv.Itoa(in.Adherence.Total))
+	} else {
+		budget.explain("score.budget.none")
+	}
+
+	fund := HealthComponent{Key: "emergency_fund", Weight: 0.30}
+	target := in.FundTargetMonths
+	if target <= 0 {
+		target = healthTargetFundMonths
+	}
+	source := "score.fund.net"
+	if in.FundDesignated {
+		source = "score.fund.accounts"
+	}
+	if in.AvgMonthlyExpense > 0 {
+		v := round2(math.Max(0, in.LiquidSavings) / in.AvgMonthlyExpense)
+		fund.Value = &v
+		fund.Score = floatPtr(clampScore(v / target * 100))
+		fund.explain(source, "months", fmt.Sprintf("%.1f", v), "target", fmt.Sprintf("%g", target))
+	} else {
+		fund.explain("score.fund.none")
+	}
+
+	debt := HealthComponent{Key: "debt_ratio", Weight: 0.20}
+	switch {
+	case in.MonthlyDebtPayments == 0:
+		v := 0.0
+		debt.Value = &v
+		debt.Score = floatPtr(100)
+		debt.explain("score.debt.zero")
+	case in.AvgMonthlyIncome > 0:
+		v := round4(in.MonthlyDebtPayments / in.AvgMonthlyIncome)
+		debt.Value = &v
+		debt.Score = floatPtr(clampScore((1 - v/healthMaxDebtRatio) * 100))
+		debt.explain("score.debt", "ratio", fmt.Sprintf("%.1f", v*100), "max", fmt.Sprintf("%.0f", healthMaxDebtRatio*100))
+	default:
+		debt.explain("score.debt.no_income")
+	}
+
+	out.Components = []HealthComponent{savings, budget, fund, debt}
+	var sum, weights float64
+	for _, c := range out.Components {
+		if c.Score == nil {
+			continue
+		}
+		sum += *c.Score * c.Weight
+		weights += c.Weight
+	}
+	if weights > 0 {
+		out.Score = floatPtr(round2(sum / weights))
+	}
+	return out
+}
+
+// clampScore rounds v to two decimals and bounds it to 0..100.
+func clampScore(v float64) float64 { return round2(math.Min(100, math.Max(0, v))) }
+
+// round4 rounds to four decimal places, the precision used for rates.
+func round4(v float64) float64 { return math.Round(v*10000) / 10000 }
+
+// floatPtr returns a pointer to v.
+func floatPtr(v float64) *float64 { return &v }
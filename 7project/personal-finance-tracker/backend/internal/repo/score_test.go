@@ -0,0 +1,47 @@
+// backend/internal/repo/score_test.go
+//
+// Purpose:
+//   Verify that the health score rates each component against its target and
+//   re-weights over the components that could be computed.
+
+package repo
+
+import "testing"
+
+func TestScoreHealth_AllComponents(t *testing.T) {
+	rate := 0.10
+	got := ScoreHealth(HealthInputs{
+		SavingsRate:         &rate,
+		Adherence:           &BudgetAdherence{Score: 80, Respected: 4, Total: 5},
+		AvgMonthlyIncome:    4000,
+		AvgMonthlyExpense:   2000,
+		LiquidSavings:       6000,
+		MonthlyDebtPayments: 1000,
+	})
+	want := map[string]float64{
+		"savings_rate":     50,
+		"budget_adherence": 80,
+		"emergency_fund":   50,
+		"debt_ratio":       50,
+	}
+	for _, c := range got.Components {
+		if c.Score == nil || *c.Score != want[c.Key] {
+			t.Fatalf("%s: expected score %v, got %+v", c.Key, want[c.Key], c.Score)
+		}
+	}
+	// 0.3*50 + 0.2*80 + 0.3*50 + 0.2*50 = 56
+	if got.Score == nil || *got.Score != 56 {
+		t.Fatalf("expected overall 56, got %+v", got.Score)
+	}
+}
+
+func TestScoreHealth_SkipsUnavailable(t *testing.T) {
+	got := ScoreHealth(HealthInputs{AvgMonthlyExpense: 1000, LiquidSavings: 12000})
+	// Only emergency fund (capped at 100) and debt ratio (no loans = 100) are available.
+	if got.Score == nil || *got.Score != 100 {
+		t.Fatalf("expected 100, got %+v", got.Score)
+	}
+	if c := got.Components[0]; c.Score != nil || c.Value != nil {
+		t.Fatalf("savings rate should be unavailable, got %+v", c)
+	}
+}
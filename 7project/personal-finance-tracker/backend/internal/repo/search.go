@@ -0,0 +1,238 @@
+// backend/internal/repo/search.go
+
+package repo
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SearchHit is one result of a global search.
+// - Type: "category" | "payee" | "transaction" | "note"
+// - ID: the category, transaction, wishlist item or settlement; nil for payees
+// - Title: the matching text (a payee's latest spelling, a note's text)
+// - Detail: a secondary line for display, e.g. "12 transactions" or "Wishlist: Bike"
+// - Source: where a note lives, "wishlist" or "settlement"
+// - Score: relevance in (0, 1]; exact matches score highest, then prefixes, word prefixes and substrings
+type SearchHit struct {
+	Type   string   `json:"type"`
+	ID     *int64   `json:"id"`
+	Title  string   `json:"title"`
+	Detail string   `json:"detail,omitempty"`
+	Source string   `json:"source,omitempty"`
+	Date   *string  `json:"date,omitempty"` // YYYY-MM-DD
+	Amount *float64 `json:"amount,omitempty"`
+	Score  float64  `json:"score"`
+}
+
+// SearchResults holds the ranked hits of every type for one query.
+type SearchResults struct {
+	Query   string      `json:"query"`
+	Results []SearchHit `json:"results"`
+}
+
+// searchScanLimit bounds how many of the newest transactions one search considers.
+// Encrypted descriptions cannot be matched in SQL, so they are decrypted and matched
+// here; a user's older transactions beyond the limit are then not searched.
+const searchScanLimit = 5000
+
+// searchTypeOrder breaks score ties: what a command palette jumps to most comes first.
+var searchTypeOrder = map[string]int{"category": 0, "payee": 1, "transaction": 2, "note": 3}
+
+// SearchRepo searches a user's categories, payees, transactions and notes.
+type SearchRepo struct {
+	read  querier
+	crypt *fieldCrypt
+}
+
+// SearchRepo accessor bound to the Store's pool (the replica when configured).
+func (s *Store) SearchRepo() *SearchRepo { return &SearchRepo{read: s.reader(), crypt: s.crypt} }
+
+// Search matches q case-insensitively against category names, transaction descriptions
+// (and their amounts when q is a number), payees (descriptions grouped as by the
+// recurring-charge detector) and wishlist and settlement notes, and returns the best
+// limit hits across all types.
+func (r *SearchRepo) Search(ctx context.Context, userID int64, q string, limit int) (*SearchResults, error) {
+	q = strings.TrimSpace(q)
+	like := "%" + likeEscaper.Replace(q) + "%"
+	var hits []SearchHit
+
+	// Categories.
+	rows, err := r.read.Query(ctx, `SELECT id, name, type FROM categories
+	                                WHERE user_id=$1 AND deleted_at IS NULL AND name ILIKE $2
+	                                ORDER BY name LIMIT 50`, userID, like)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id int64
+		var name, typ string
+		if err := rows.Scan(&id, &name, &typ); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		hits = append(hits, SearchHit{Type: "category", ID: &id, Title: name, Detail: typ, Score: matchScore(name, q)})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Transactions and payees.
+	var amount *float64
+	if v, err := strconv.ParseFloat(q, 64); err == nil {
+		amount = &v
+	}
+	rows, err = r.read.Query(ctx, `SELECT id, amount, date, description FROM transactions
+	                               WHERE user_id=$1 AND (description ILIKE $2 OR description LIKE 'enc1:%' OR amount = $3)
+	                               ORDER BY date DESC, id DESC LIMIT $4`, userID, like, amount, searchScanLimit)
+	if err != nil {
+		return nil, err
+	}
+	var txns []Transaction
+	for rows.Next() {
+		t := Transaction{UserID: userID}
+		if err := rows.Scan(&t.ID, &t.Amount, &t.Date, &t.Description); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		txns = append(txns, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.crypt.openAll(ctx, txns); err != nil {
+		return nil, err
+	}
+	hits = append(hits, matchTransactions(txns, q, amount)...)
+
+	// Notes.
+	rows, err = r.read.Query(ctx, `SELECT 'wishlist', w.id, w.note, 'Wishlist: ' || w.name, NULL::date FROM wishlist_items w
+	                               WHERE w.user_id=$1 AND w.note ILIKE $2
+	                               UNION ALL
+	                               SELECT 'settlement', s.id, s.note, 'Settlement with ' || c.name, s.date FROM settlements s
+	                               JOIN contacts c ON c.id = s.contact_id
+	                               WHERE s.user_id=$1 AND s.note ILIKE $2
+	                               LIMIT 50`, userID, like)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		h := SearchHit{Type: "note"}
+		var id int64
+		var date *time.Time
+		if err := rows.Scan(&h.Source, &id, &h.Title, &h.Detail, &date); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		h.ID = &id
+		if date != nil {
+			d := date.Format("2006-01-02")
+			h.Date = &d
+		}
+		h.Score = matchScore(h.Title, q)
+		hits = append(hits, h)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &SearchResults{Query: q, Results: rankHits(hits, limit)}, nil
+}
+
+// matchTransactions returns a hit for every transaction (newest first) whose description
+// contains q or whose amount equals amount, plus one payee hit per distinct normalized
+// description containing q, titled with its most recent spelling.
+func matchTransactions(txns []Transaction, q string, amount *float64) []SearchHit {
+	var hits []SearchHit
+	payees := map[string]int{} // normalized payee -> index into hits
+	counts := map[int]int{}    // payee hit index -> transactions
+	for _, t := range txns {
+		score := matchScore(t.Description, q)
+		if amount != nil && t.Amount == *amount {
+			score = max(score, 0.9)
+		}
+		if score == 0 {
+			continue
+		}
+		id, amt, date := t.ID, t.Amount, t.Date.Format("2006-01-02")
+		hits = append(hits, SearchHit{Type: "transaction", ID: &id, Title: t.Description, Date: &date, Amount: &amt, Score: score})
+
+		key := normalizePayee(t.Description)
+		if key == "" || !strings.Contains(key, strings.ToLower(q)) {
+			continue
+		}
+		i, ok := payees[key]
+		if !ok {
+			i = len(hits)
+			payees[key] = i
+			hits = append(hits, SearchHit{Type: "payee", Title: t.Description, Date: &date, Score: matchScore(key, q)})
+		}
+		counts[i]++
+	}
+	for i, n := range counts {
+		hits[i].Detail = strconv.Itoa(n) + " transactions"
+		if n == 1 {
+			hits[i].Detail = "1 transaction"
+		}
+	}
+	return hits
+}
+
+// matchScore rates how well text matches q, ignoring case: 1 for equal, 0.8 when text
+// starts with q, 0.6 when a later word does, 0.4 for any other occurrence, 0 for none.
+func matchScore(text, q string) float64 {
+	text, q = strings.ToLower(strings.TrimSpace(text)), strings.ToLower(q)
+	if q == "" {
+		return 0
+	}
+	i := strings.Index(text, q)
+	switch {
+	case i < 0:
+		return 0
+	case text == q:
+		return 1
+	case i == 0:
+		return 0.8
+	case strings.Contains(" "+text, " "+q):
+		return 0.6
+	default:
+		return 0.4
+	}
+}
+
+// rankHits drops non-matches and orders hits by score, then type, then newest date,
+// keeping the first limit.
+func rankHits(hits []SearchHit, limit int) []SearchHit {
+	out := hits[:0]
+	for _, h := range hits {
+		if h.Score > 0 {
+			out = append(out, h)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Type != b.Type {
+			return searchTypeOrder[a.Type] < searchTypeOrder[b.Type]
+		}
+		return a.Date != nil && (b.Date == nil || *a.Date > *b.Date)
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	if out == nil {
+		out = []SearchHit{}
+	}
+	return out
+}
+
+// likeEscaper escapes the LIKE wildcards in user input.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
@@ -0,0 +1,69 @@
+// backend/internal/repo/search_test.go
+//
+// Purpose:
+//   Verify search scoring and ranking, and that matching transactions also yield
+//   one payee hit per distinct description with its transaction count.
+
+package repo
+
+import "testing"
+
+func TestMatchScore(t *testing.T) {
+	cases := []struct {
+		text, q string
+		want    float64
+	}{
+		{"Groceries", "groceries", 1},
+		{"Groceries", "gro", 0.8},
+		{"Weekly groceries", "gro", 0.6},
+		{"Agrocery", "gro", 0.4},
+		{"Rent", "gro", 0},
+	}
+	for _, c := range cases {
+		if got := matchScore(c.text, c.q); got != c.want {
+			t.Errorf("matchScore(%q, %q) = %v, want %v", c.text, c.q, got, c.want)
+		}
+	}
+}
+
+func TestMatchTransactions(t *testing.T) {
+	txns := []Transaction{
+		{ID: 3, Amount: 12.99, Date: day("2024-03-05"), Description: "Netflix"},
+		{ID: 2, Amount: 40, Date: day("2024-02-20"), Description: "Groceries"},
+		{ID: 1, Amount: 12.99, Date: day("2024-02-05"), Description: "NETFLIX "},
+	}
+	hits := matchTransactions(txns, "netf", nil)
+	if len(hits) != 3 {
+		t.Fatalf("hits = %+v", hits)
+	}
+	if hits[1].Type != "payee" || hits[1].Title != "Netflix" || hits[1].Detail != "2 transactions" || hits[1].ID != nil {
+		t.Errorf("payee hit = %+v", hits[1])
+	}
+
+	amount := 40.0
+	hits = matchTransactions(txns, "40", &amount)
+	if len(hits) != 1 || *hits[0].ID != 2 || hits[0].Score != 0.9 {
+		t.Errorf("amount hits = %+v", hits)
+	}
+}
+
+func TestRankHits(t *testing.T) {
+	d1, d2 := "2024-01-01", "2024-02-01"
+	hits := []SearchHit{
+		{Type: "transaction", Title: "old", Date: &d1, Score: 0.8},
+		{Type: "note", Title: "none"},
+		{Type: "transaction", Title: "new", Date: &d2, Score: 0.8},
+		{Type: "category", Title: "cat", Score: 0.8},
+		{Type: "payee", Title: "exact", Score: 1},
+	}
+	got := rankHits(hits, 3)
+	want := []string{"exact", "cat", "new"}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v", got)
+	}
+	for i := range want {
+		if got[i].Title != want[i] {
+			t.Errorf("rank %d = %q, want %q", i, got[i].Title, want[i])
+		}
+	}
+}
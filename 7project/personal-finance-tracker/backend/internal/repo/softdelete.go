@@ -0,0 +1,72 @@
+// backend/internal/repo/softdelete.go
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// softDeleteTable is a table whose rows are soft-deleted (migration 030).
+// Repositories skip rows with deleted_at set in every read except ListDeleted.
+// - Name: table name
+// - Purgeable: extra condition a deleted row must meet to be purged
+type softDeleteTable struct {
+	Name      string
+	Purgeable string
+}
+
+// softDeleteTables lists soft-deleted tables in purge order (referrers first).
+// Categories still referenced by a budget, even a deleted one, wait for that budget.
+var softDeleteTables = []softDeleteTable{
+	{Name: "budgets", Purgeable: "TRUE"},
+	{Name: "categories", Purgeable: "NOT EXISTS (SELECT 1 FROM budgets b WHERE b.category_id = categories.id)"},
+}
+
+// execer is satisfied by pools and transactions.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// softDelete marks the user's row as deleted. Returns false if no live row matched.
+func softDelete(ctx context.Context, q execer, table string, userID, id int64) (bool, error) {
+	ct, err := q.Exec(ctx, fmt.Sprintf(`UPDATE %s SET deleted_at=NOW() WHERE user_id=$1 AND id=$2 AND deleted_at IS NULL`, table), userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// restoreDeleted clears deleted_at on the user's row and returns cols of it;
+// scanning yields pgx.ErrNoRows if no deleted row matched.
+func restoreDeleted(ctx context.Context, q rowQuerier, table, cols string, userID, id int64) pgx.Row {
+	return q.QueryRow(ctx, fmt.Sprintf(`UPDATE %s SET deleted_at=NULL
+	                                    WHERE user_id=$1 AND id=$2 AND deleted_at IS NOT NULL
+	                                    RETURNING %s`, table, cols), userID, id)
+}
+
+// TrashRepo permanently removes soft-deleted rows.
+type TrashRepo struct{ pool dbConn }
+
+// TrashRepo accessor bound to the Store's pool.
+func (s *Store) TrashRepo() *TrashRepo { return &TrashRepo{pool: s.db()} }
+
+// Purge deletes rows soft-deleted before cutoff from every soft-deleted table and
+// returns the number removed per table. Purged categories are cleared from the
+// transactions, bills and other records that referred to them.
+func (r *TrashRepo) Purge(ctx context.Context, cutoff time.Time) (map[string]int64, error) {
+	out := make(map[string]int64, len(softDeleteTables))
+	for _, t := range softDeleteTables {
+		q := fmt.Sprintf(`DELETE FROM %s WHERE deleted_at < $1 AND %s`, t.Name, t.Purgeable)
+		ct, err := r.pool.Exec(ctx, q, cutoff)
+		if err != nil {
+			return nil, fmt.Errorf("purge %s: %w", t.Name, err)
+		}
+		out[t.Name] = ct.RowsAffected()
+	}
+	return out, nil
+}
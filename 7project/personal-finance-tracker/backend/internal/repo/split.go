@@ -0,0 +1,312 @@
+// backend/internal/repo/split.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrNotFound is returned when a referenced row does not exist or is not owned by the user.
+var ErrNotFound = errors.New("not_found")
+
+// Contact is a person expenses can be shared with (not necessarily a registered user).
+type Contact struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SplitShare is one participant's portion of a split; a nil ContactID is the owning user.
+type SplitShare struct {
+	ContactID *int64  `json:"contact_id"`
+	Amount    float64 `json:"amount"`
+}
+
+// Split divides a transaction among participants.
+// PayerContactID is nil when the owning user paid, otherwise the contact who paid.
+type Split struct {
+	ID             int64        `json:"id"`
+	TransactionID  int64        `json:"transaction_id"`
+	PayerContactID *int64       `json:"payer_contact_id"`
+	Shares         []SplitShare `json:"shares"`
+}
+
+// Settlement records money exchanged between the user and a contact.
+// Positive amounts mean the contact paid the user; negative amounts the reverse.
+type Settlement struct {
+	ID        int64     `json:"id"`
+	ContactID int64     `json:"contact_id"`
+	Amount    float64   `json:"amount"`
+	Date      time.Time `json:"date"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ContactBalance is the net position with a contact.
+// Positive Balance means the contact owes the user; negative means the user owes the contact.
+type ContactBalance struct {
+	ContactID int64   `json:"contact_id"`
+	Name      string  `json:"name"`
+	Balance   float64 `json:"balance"`
+}
+
+// Transfer is a suggested payment that settles balances.
+// From/To are participant names; "me" denotes the owning user.
+type Transfer struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Amount float64 `json:"amount"`
+}
+
+// SplitRepo manages contacts, expense splits, and settlements.
+type SplitRepo struct{ pool dbConn }
+
+// SplitRepo accessor bound to the Store's pool.
+func (s *Store) SplitRepo() *SplitRepo { return &SplitRepo{pool: s.db()} }
+
+// ListContacts returns the user's contacts ordered by name.
+func (r *SplitRepo) ListContacts(ctx context.Context, userID int64) ([]Contact, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id, user_id, name, email, created_at, updated_at FROM contacts WHERE user_id=$1 ORDER BY lower(name)`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Contact{}
+	for rows.Next() {
+		var c Contact
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Email, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// CreateContact inserts a contact; duplicate names per user violate a unique index.
+func (r *SplitRepo) CreateContact(ctx context.Context, userID int64, name, email string) (*Contact, error) {
+	var c Contact
+	err := r.pool.QueryRow(ctx, `INSERT INTO contacts (user_id, name, email) VALUES ($1,$2,$3)
+	                             RETURNING id, user_id, name, email, created_at, updated_at`, userID, name, email).
+		Scan(&c.ID, &c.UserID, &c.Name, &c.Email, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// DeleteContact removes a contact. Contacts referenced by splits or settlements
+// cannot be deleted and yield ErrFKConflict.
+func (r *SplitRepo) DeleteContact(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM contacts WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) && pgerr.Code == "23503" { // foreign_key_violation
+			return false, fmt.Errorf("%w: %w", ErrFKConflict, pgerr)
+		}
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// SetSplit replaces the split of a transaction. The transaction and every referenced
+// contact must belong to the user, otherwise ErrNotFound is returned.
+func (r *SplitRepo) SetSplit(ctx context.Context, userID int64, s *Split) (*Split, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var owned bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM transactions WHERE user_id=$1 AND id=$2)`, userID, s.TransactionID).Scan(&owned); err != nil {
+		return nil, err
+	}
+	if !owned {
+		return nil, ErrNotFound
+	}
+	ids := []int64{}
+	if s.PayerContactID != nil {
+		ids = append(ids, *s.PayerContactID)
+	}
+	for _, sh := range s.Shares {
+		if sh.ContactID != nil {
+			ids = append(ids, *sh.ContactID)
+		}
+	}
+	var unknown int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM unnest($2::bigint[]) AS x(id)
+	                            WHERE NOT EXISTS (SELECT 1 FROM contacts c WHERE c.user_id=$1 AND c.id=x.id)`,
+		userID, ids).Scan(&unknown); err != nil {
+		return nil, err
+	}
+	if unknown > 0 {
+		return nil, ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM expense_splits WHERE user_id=$1 AND transaction_id=$2`, userID, s.TransactionID); err != nil {
+		return nil, err
+	}
+	if err := tx.QueryRow(ctx, `INSERT INTO expense_splits (user_id, transaction_id, payer_contact_id) VALUES ($1,$2,$3) RETURNING id`,
+		userID, s.TransactionID, s.PayerContactID).Scan(&s.ID); err != nil {
+		return nil, err
+	}
+	for _, sh := range s.Shares {
+		if _, err := tx.Exec(ctx, `INSERT INTO expense_split_shares (split_id, contact_id, amount) VALUES ($1,$2,$3)`,
+			s.ID, sh.ContactID, sh.Amount); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetSplit loads the split of a transaction. Returns (nil, nil) when the transaction is not split.
+func (r *SplitRepo) GetSplit(ctx context.Context, userID, txnID int64) (*Split, error) {
+	s := Split{TransactionID: txnID, Shares: []SplitShare{}}
+	err := r.pool.QueryRow(ctx, `SELECT id, payer_contact_id FROM expense_splits WHERE user_id=$1 AND transaction_id=$2`, userID, txnID).
+		Scan(&s.ID, &s.PayerContactID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.pool.Query(ctx, `SELECT contact_id, amount FROM expense_split_shares WHERE split_id=$1 ORDER BY contact_id NULLS FIRST`, s.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sh SplitShare
+		if err := rows.Scan(&sh.ContactID, &sh.Amount); err != nil {
+			return nil, err
+		}
+		s.Shares = append(s.Shares, sh)
+	}
+	return &s, rows.Err()
+}
+
+// DeleteSplit removes the split of a transaction.
+func (r *SplitRepo) DeleteSplit(ctx context.Context, userID, txnID int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM expense_splits WHERE user_id=$1 AND transaction_id=$2`, userID, txnID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// CreateSettlement records a payment between the user and one of their contacts.
+// Returns ErrNotFound if the contact does not belong to the user.
+func (r *SplitRepo) CreateSettlement(ctx context.Context, userID int64, s *Settlement) (*Settlement, error) {
+	var out Settlement
+	err := r.pool.QueryRow(ctx, `INSERT INTO settlements (user_id, contact_id, amount, date, note)
+	                             SELECT $1, c.id, $3, $4, $5 FROM contacts c WHERE c.user_id=$1 AND c.id=$2
+	                             RETURNING id, contact_id, amount, date, note, created_at, updated_at`,
+		userID, s.ContactID, s.Amount, s.Date, s.Note).
+		Scan(&out.ID, &out.ContactID, &out.Amount, &out.Date, &out.Note, &out.CreatedAt, &out.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Balances returns the net position with every contact:
+// shares of expenses the user paid, minus the user's shares of expenses a contact paid,
+// minus settlements the contact already made (plus those the user made).
+func (r *SplitRepo) Balances(ctx context.Context, userID int64) ([]ContactBalance, error) {
+	const q = `
+SELECT c.id, c.name,
+       COALESCE((SELECT SUM(sh.amount) FROM expense_split_shares sh
+                 JOIN expense_splits s ON s.id = sh.split_id
+                 WHERE s.user_id = c.user_id AND s.payer_contact_id IS NULL AND sh.contact_id = c.id), 0)
+     - COALESCE((SELECT SUM(sh.amount) FROM expense_split_shares sh
+                 JOIN expense_splits s ON s.id = sh.split_id
+                 WHERE s.user_id = c.user_id AND s.payer_contact_id = c.id AND sh.contact_id IS NULL), 0)
+     - COALESCE((SELECT SUM(st.amount) FROM settlements st WHERE st.user_id = c.user_id AND st.contact_id = c.id), 0)
+FROM contacts c
+WHERE c.user_id=$1
+ORDER BY lower(c.name)
+`
+	rows, err := r.pool.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []ContactBalance{}
+	for rows.Next() {
+		var b ContactBalance
+		if err := rows.Scan(&b.ContactID, &b.Name, &b.Balance); err != nil {
+			return nil, err
+		}
+		b.Balance = round2(b.Balance)
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// SuggestSettlements computes a small set of transfers that clears the given net balances
+// (positive = is owed money, negative = owes money), greedily matching the largest
+// debtor with the largest creditor. Balances must sum to zero.
+func SuggestSettlements(net map[string]float64) []Transfer {
+	type party struct {
+		name string
+		amt  float64
+	}
+	var debtors, creditors []party
+	for name, v := range net {
+		v = round2(v)
+		switch {
+		case v > 0:
+			creditors = append(creditors, party{name, v})
+		case v < 0:
+			debtors = append(debtors, party{name, -v})
+		}
+	}
+	byAmount := func(ps []party) {
+		sort.Slice(ps, func(i, j int) bool {
+			if ps[i].amt != ps[j].amt {
+				return ps[i].amt > ps[j].amt
+			}
+			return ps[i].name < ps[j].name
+		})
+	}
+	byAmount(debtors)
+	byAmount(creditors)
+
+	out := []Transfer{}
+	i, j := 0, 0
+	for i < len(debtors) && j < len(creditors) {
+		amt := round2(math.Min(debtors[i].amt, creditors[j].amt))
+		if amt > 0 {
+			out = append(out, Transfer{From: debtors[i].name, To: creditors[j].name, Amount: amt})
+		}
+		debtors[i].amt = round2(debtors[i].amt - amt)
+		creditors[j].amt = round2(creditors[j].amt - amt)
+		if debtors[i].amt == 0 {
+			i++
+		}
+		if creditors[j].amt == 0 {
+			j++
+		}
+	}
+	return out
+}
@@ -0,0 +1,31 @@
+// backend/internal/repo/split_test.go
+//
+// Purpose:
+//   Verify that settlement suggestions clear all balances with few transfers.
+
+package repo
+
+import "testing"
+
+func TestSuggestSettlements(t *testing.T) {
+	net := map[string]float64{
+		"me":    50,
+		"alice": -30,
+		"bob":   -20,
+		"carol": 0,
+	}
+	got := SuggestSettlements(net)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 transfers, got %+v", got)
+	}
+	paid := map[string]float64{}
+	for _, tr := range got {
+		if tr.To != "me" {
+			t.Fatalf("unexpected creditor in %+v", tr)
+		}
+		paid[tr.From] += tr.Amount
+	}
+	if paid["alice"] != 30 || paid["bob"] != 20 {
+		t.Fatalf("unexpected transfers: %+v", got)
+	}
+}
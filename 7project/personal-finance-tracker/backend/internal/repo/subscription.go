@@ -0,0 +1,195 @@
+// backend/internal/repo/subscription.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Subscription is the repository-layer DTO mirroring the subscriptions table.
+// - BillingInterval: "weekly" | "biweekly" | "monthly" | "quarterly" | "yearly"
+// - RenewalDate: next (or last known) renewal; NextRenewal rolls it forward past today
+// - CancelBy: optional deadline for cancelling before the next renewal
+type Subscription struct {
+	ID              int64      `json:"id"`
+	UserID          int64      `json:"user_id"`
+	CategoryID      *int64     `json:"category_id"`
+	Service         string     `json:"service"`
+	Amount          float64    `json:"amount"`
+	BillingInterval string     `json:"billing_interval"`
+	RenewalDate     time.Time  `json:"renewal_date"`
+	CancelBy        *time.Time `json:"cancel_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Derived fields filled in by List.
+	NextRenewal string  `json:"next_renewal"` // YYYY-MM-DD
+	MonthlyCost float64 `json:"monthly_cost"`
+	CancelSoon  bool    `json:"cancel_soon"` // cancel_by is within the next 7 days
+}
+
+// SubscriptionRepo provides CRUD operations for subscriptions.
+type SubscriptionRepo struct{ pool dbConn }
+
+// SubscriptionRepo accessor bound to the Store's pool.
+func (s *Store) SubscriptionRepo() *SubscriptionRepo { return &SubscriptionRepo{pool: s.db()} }
+
+const subscriptionCols = `id, user_id, category_id, service, amount, billing_interval, renewal_date, cancel_by, created_at, updated_at`
+
+// monthlyCostSQL converts a subscription amount into its monthly equivalent.
+const monthlyCostSQL = `CASE billing_interval
+	WHEN 'weekly' THEN amount * 52 / 12
+	WHEN 'biweekly' THEN amount * 26 / 12
+	WHEN 'quarterly' THEN amount / 3
+	WHEN 'yearly' THEN amount / 12
+	ELSE amount END`
+
+// cancelSoonDays is how far ahead a cancel_by deadline is flagged.
+const cancelSoonDays = 7
+
+func scanSubscription(row pgx.Row) (*Subscription, error) {
+	var s Subscription
+	if err := row.Scan(&s.ID, &s.UserID, &s.CategoryID, &s.Service, &s.Amount, &s.BillingInterval, &s.RenewalDate, &s.CancelBy, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// List returns all subscriptions of a user with derived renewal and cost fields, ordered by service.
+func (r *SubscriptionRepo) List(ctx context.Context, userID int64, today time.Time) ([]Subscription, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+subscriptionCols+` FROM subscriptions WHERE user_id=$1 ORDER BY lower(service), id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	out := []Subscription{}
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		s.fillDerived(today)
+		out = append(out, *s)
+	}
+	return out, rows.Err()
+}
+
+// Create inserts a subscription and returns the stored row.
+func (r *SubscriptionRepo) Create(ctx context.Context, s *Subscription) (*Subscription, error) {
+	const q = `INSERT INTO subscriptions (user_id, category_id, service, amount, billing_interval, renewal_date, cancel_by)
+	           VALUES ($1,$2,$3,$4,$5,$6,$7)
+	           RETURNING ` + subscriptionCols
+	out, err := scanSubscription(r.pool.QueryRow(ctx, q, s.UserID, s.CategoryID, s.Service, s.Amount, s.BillingInterval, s.RenewalDate, s.CancelBy))
+	if err != nil {
+		return nil, err
+	}
+	out.fillDerived(time.Now().UTC())
+	return out, nil
+}
+
+// Update modifies a subscription owned by the user. Returns (nil, nil) when not found.
+func (r *SubscriptionRepo) Update(ctx context.Context, userID, id int64, s *Subscription) (*Subscription, error) {
+	const q = `UPDATE subscriptions
+	           SET category_id=$3, service=$4, amount=$5, billing_interval=$6, renewal_date=$7, cancel_by=$8
+	           WHERE user_id=$1 AND id=$2
+	           RETURNING ` + subscriptionCols
+	out, err := scanSubscription(r.pool.QueryRow(ctx, q, userID, id, s.CategoryID, s.Service, s.Amount, s.BillingInterval, s.RenewalDate, s.CancelBy))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	out.fillDerived(time.Now().UTC())
+	return out, nil
+}
+
+// Delete removes a subscription scoped to the user.
+func (r *SubscriptionRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM subscriptions WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// ImportDetected creates subscriptions from detected recurring charges, skipping services
+// the user already tracks (case-insensitive). Returns the newly created rows.
+func (r *SubscriptionRepo) ImportDetected(ctx context.Context, userID int64, charges []RecurringCharge) ([]Subscription, error) {
+	const q = `INSERT INTO subscriptions (user_id, category_id, service, amount, billing_interval, renewal_date)
+	           VALUES ($1,$2,$3,$4,$5,$6)
+	           ON CONFLICT (user_id, lower(service)) DO NOTHING
+	           RETURNING ` + subscriptionCols
+	out := []Subscription{}
+	for _, rc := range charges {
+		next, err := time.Parse("2006-01-02", rc.NextExpected)
+		if err != nil {
+			return nil, err
+		}
+		s, err := scanSubscription(r.pool.QueryRow(ctx, q, userID, rc.CategoryID, rc.Payee, rc.AvgAmount, rc.Interval, next))
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue // already tracked
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.fillDerived(time.Now().UTC())
+		out = append(out, *s)
+	}
+	return out, nil
+}
+
+// MonthlyTotal returns the sum of all subscriptions converted to a monthly cost.
+func (r *SubscriptionRepo) MonthlyTotal(ctx context.Context, userID int64) (float64, error) {
+	q := `SELECT COALESCE(ROUND(SUM(` + monthlyCostSQL + `), 2), 0) FROM subscriptions WHERE user_id=$1`
+	var total float64
+	err := r.pool.QueryRow(ctx, q, userID).Scan(&total)
+	return total, err
+}
+
+// fillDerived computes NextRenewal, MonthlyCost, and CancelSoon relative to today.
+func (s *Subscription) fillDerived(today time.Time) {
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	next := s.RenewalDate
+	for i := 0; next.Before(today) && i < 1000; i++ {
+		next = advanceInterval(next, s.BillingInterval)
+	}
+	s.NextRenewal = next.Format("2006-01-02")
+
+	switch s.BillingInterval {
+	case "weekly":
+		s.MonthlyCost = round2(s.Amount * 52 / 12)
+	case "biweekly":
+		s.MonthlyCost = round2(s.Amount * 26 / 12)
+	case "quarterly":
+		s.MonthlyCost = round2(s.Amount / 3)
+	case "yearly":
+		s.MonthlyCost = round2(s.Amount / 12)
+	default:
+		s.MonthlyCost = s.Amount
+	}
+
+	s.CancelSoon = s.CancelBy != nil && !s.CancelBy.Before(today) && !s.CancelBy.After(today.AddDate(0, 0, cancelSoonDays))
+}
+
+// advanceInterval moves d forward by one billing interval.
+func advanceInterval(d time.Time, interval string) time.Time {
+	switch interval {
+	case "weekly":
+		return d.AddDate(0, 0, 7)
+	case "biweekly":
+		return d.AddDate(0, 0, 14)
+	case "quarterly":
+		return d.AddDate(0, 3, 0)
+	case "yearly":
+		return d.AddDate(1, 0, 0)
+	default:
+		return d.AddDate(0, 1, 0)
+	}
+}
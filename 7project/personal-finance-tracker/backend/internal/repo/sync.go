@@ -0,0 +1,292 @@
+// backend/internal/repo/sync.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SyncCursor is a position in a user's change log (see migration 050), handed to
+// clients as an opaque sync token.
+// - From: changes by transactions with ids from here on have not been returned yet
+// - To: upper bound of the window being paged through; 0 between windows
+// - After: last seq returned within the window
+type SyncCursor struct {
+	From  int64
+	To    int64
+	After int64
+}
+
+// String encodes the cursor as a sync token.
+func (c SyncCursor) String() string {
+	if c.To == 0 {
+		return strconv.FormatInt(c.From, 10)
+	}
+	return fmt.Sprintf("%d.%d.%d", c.From, c.To, c.After)
+}
+
+// ParseSyncCursor decodes a sync token; "" is the start of the log.
+func ParseSyncCursor(s string) (SyncCursor, error) {
+	var c SyncCursor
+	if s == "" {
+		return c, nil
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) != 1 && len(parts) != 3 {
+		return c, fmt.Errorf("malformed sync token")
+	}
+	vals := make([]int64, len(parts))
+	for i, p := range parts {
+		v, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || v < 0 {
+			return c, fmt.Errorf("malformed sync token")
+		}
+		vals[i] = v
+	}
+	c.From = vals[0]
+	if len(vals) == 3 {
+		c.To, c.After = vals[1], vals[2]
+		if c.To <= c.From {
+			return c, fmt.Errorf("malformed sync token")
+		}
+	}
+	return c, nil
+}
+
+// SyncChange is the current state of one changed row.
+// - Op: "upsert" with the row in Data, or "delete" (a tombstone; Data is omitted)
+// - Version: changes with every write to the row; the base version of client edits
+type SyncChange struct {
+	Entity  string `json:"entity"`
+	ID      int64  `json:"id"`
+	Op      string `json:"op"`
+	Version int64  `json:"version"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// SyncPage is one page of changes and the cursor to continue from.
+// - HasMore: more changes are ready; request again with Next right away
+type SyncPage struct {
+	Changes []SyncChange
+	Next    SyncCursor
+	HasMore bool
+}
+
+// SyncRepo reads the change log for delta sync. It reads from the primary: the log's
+// transaction window must match the data it loads.
+type SyncRepo struct {
+	pool  dbConn
+	crypt *fieldCrypt
+}
+
+// SyncRepo accessor bound to the Store's pool.
+func (s *Store) SyncRepo() *SyncRepo { return &SyncRepo{pool: s.db(), crypt: s.crypt} }
+
+// Changes returns up to limit of the user's changes after cur, each with the row's
+// current state. Changes are read in windows of finished transactions: a window ends
+// at the oldest transaction still open when it starts, so a change committed late
+// with a lower seq than one already returned is not skipped but arrives in the next
+// window. A long-running transaction anywhere in the database therefore delays sync,
+// never loses changes. The first sync (zero cursor) leaves out tombstones.
+func (r *SyncRepo) Changes(ctx context.Context, userID int64, cur SyncCursor, limit int) (*SyncPage, error) {
+	if cur.To == 0 {
+		if err := r.pool.QueryRow(ctx, `SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint`).Scan(&cur.To); err != nil {
+			return nil, err
+		}
+		cur.After = 0
+	}
+	const q = `SELECT entity, entity_id, deleted, seq FROM sync_changes
+	           WHERE user_id=$1 AND xid >= $2::bigint::text::xid8 AND xid < $3::bigint::text::xid8 AND seq > $4
+	             AND ($2::bigint > 0 OR NOT deleted)
+	           ORDER BY seq LIMIT $5`
+	rows, err := r.pool.Query(ctx, q, userID, cur.From, cur.To, cur.After, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &SyncPage{Changes: []SyncChange{}}
+	var lastSeq int64
+	for rows.Next() {
+		var ch SyncChange
+		var deleted bool
+		var seq int64
+		if err := rows.Scan(&ch.Entity, &ch.ID, &deleted, &seq); err != nil {
+			return nil, err
+		}
+		if len(page.Changes) == limit {
+			page.HasMore = true
+			break
+		}
+		ch.Op, ch.Version = syncOp(deleted), seq
+		page.Changes = append(page.Changes, ch)
+		lastSeq = seq
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if page.HasMore {
+		page.Next = SyncCursor{From: cur.From, To: cur.To, After: lastSeq}
+	} else {
+		page.Next = SyncCursor{From: cur.To}
+	}
+	return page, r.load(ctx, userID, page.Changes)
+}
+
+// Lock locks the user's row of entity with id against concurrent sync writes until
+// the transaction ends and returns its state without Data, or (nil, nil) when the
+// user has no such row. Use it on a Store from WithTx.
+func (r *SyncRepo) Lock(ctx context.Context, userID int64, entity string, id int64) (*SyncChange, error) {
+	return r.state(ctx, userID, entity, id, ` FOR UPDATE`)
+}
+
+// Current returns the state of the user's row of entity with id, with Data for
+// upserts, or (nil, nil) when the user has no such row.
+func (r *SyncRepo) Current(ctx context.Context, userID int64, entity string, id int64) (*SyncChange, error) {
+	ch, err := r.state(ctx, userID, entity, id, ``)
+	if ch == nil || err != nil {
+		return ch, err
+	}
+	changes := []SyncChange{*ch}
+	if err := r.load(ctx, userID, changes); err != nil {
+		return nil, err
+	}
+	return &changes[0], nil
+}
+
+func (r *SyncRepo) state(ctx context.Context, userID int64, entity string, id int64, lock string) (*SyncChange, error) {
+	ch := SyncChange{Entity: entity, ID: id}
+	var deleted bool
+	err := r.pool.QueryRow(ctx, `SELECT deleted, seq FROM sync_changes
+	                             WHERE user_id=$1 AND entity=$2 AND entity_id=$3`+lock, userID, entity, id).Scan(&deleted, &ch.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ch.Op = syncOp(deleted)
+	return &ch, nil
+}
+
+func syncOp(deleted bool) string {
+	if deleted {
+		return "delete"
+	}
+	return "upsert"
+}
+
+// load fills in Data for upserts. Rows deleted since their change was logged become
+// tombstones; their newer change arrives in a later window anyway.
+func (r *SyncRepo) load(ctx context.Context, userID int64, changes []SyncChange) error {
+	ids := map[string][]int64{}
+	for _, ch := range changes {
+		if ch.Op == "upsert" {
+			ids[ch.Entity] = append(ids[ch.Entity], ch.ID)
+		}
+	}
+	data := map[string]map[int64]any{}
+	for entity, list := range ids {
+		rows, err := r.rows(ctx, entity, userID, list)
+		if err != nil {
+			return fmt.Errorf("sync %s: %w", entity, err)
+		}
+		data[entity] = rows
+	}
+	for i := range changes {
+		ch := &changes[i]
+		if ch.Op != "upsert" {
+			continue
+		}
+		if row, ok := data[ch.Entity][ch.ID]; ok {
+			ch.Data = row
+		} else {
+			ch.Op = "delete"
+		}
+	}
+	return nil
+}
+
+// rows loads the user's live rows of entity with the given ids, keyed by id.
+func (r *SyncRepo) rows(ctx context.Context, entity string, userID int64, ids []int64) (map[int64]any, error) {
+	out := make(map[int64]any, len(ids))
+	switch entity {
+	case "categories":
+		rows, err := r.pool.Query(ctx, `SELECT `+categoryCols+` FROM categories
+		                                WHERE user_id=$1 AND id = ANY($2) AND deleted_at IS NULL`, userID, ids)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var c Category
+			if err := rows.Scan(c.scanDest()...); err != nil {
+				return nil, err
+			}
+			out[c.ID] = c
+		}
+		return out, rows.Err()
+	case "budgets":
+		rows, err := r.pool.Query(ctx, `SELECT `+budgetCols+` FROM budgets
+		                                WHERE user_id=$1 AND id = ANY($2) AND deleted_at IS NULL`, userID, ids)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var b Budget
+			if err := rows.Scan(b.scanDest()...); err != nil {
+				return nil, err
+			}
+			out[b.ID] = b
+		}
+		return out, rows.Err()
+	case "accounts":
+		rows, err := r.pool.Query(ctx, `SELECT `+accountCols+` FROM accounts WHERE user_id=$1 AND id = ANY($2)`, userID, ids)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			a, err := scanAccount(rows)
+			if err != nil {
+				return nil, err
+			}
+			out[a.ID] = *a
+		}
+		return out, rows.Err()
+	case "transactions":
+		rows, err := r.pool.Query(ctx, `SELECT `+txnCols+` FROM transactions WHERE user_id=$1 AND id = ANY($2)`, userID, ids)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		var txs []Transaction
+		for rows.Next() {
+			var t Transaction
+			if err := rows.Scan(t.scanDest()...); err != nil {
+				return nil, err
+			}
+			txs = append(txs, t)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		if err := r.crypt.openAll(ctx, txs); err != nil {
+			return nil, err
+		}
+		for _, t := range txs {
+			out[t.ID] = t
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("unknown sync entity %q", entity)
+}
@@ -0,0 +1,23 @@
+// backend/internal/repo/sync_test.go
+//
+// Purpose:
+//   Verify that sync tokens round-trip and that malformed ones are rejected rather
+//   than read as the start of the log.
+
+package repo
+
+import "testing"
+
+func TestSyncCursor(t *testing.T) {
+	for _, c := range []SyncCursor{{}, {From: 812}, {From: 0, To: 900, After: 41}, {From: 812, To: 900, After: 77}} {
+		got, err := ParseSyncCursor(c.String())
+		if err != nil || got != c {
+			t.Errorf("%+v: round trip gave %+v, %v", c, got, err)
+		}
+	}
+	for _, s := range []string{"x", "1.2", "-1", "5.5.0", "9.3.1", "1.2.3.4"} {
+		if _, err := ParseSyncCursor(s); err == nil {
+			t.Errorf("expected %q to be rejected", s)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// backend/internal/repo/tax.go
+
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// TaxCategoryTotal is the deductible amount for one tax category within a year.
+// TaxCategory falls back to the spending category name when no label is set.
+type TaxCategoryTotal struct {
+	TaxCategory string  `json:"tax_category"`
+	Total       float64 `json:"total"`
+	Count       int     `json:"count"`
+}
+
+// TaxReport summarizes deductible expenses for a calendar year.
+// - Categories: per tax category totals, largest first
+// - Total: sum over all categories
+type TaxReport struct {
+	Year       int                `json:"year"`
+	Total      float64            `json:"total"`
+	Categories []TaxCategoryTotal `json:"categories"`
+}
+
+// Tax sums deductible expenses for the given year grouped by tax category.
+// A transaction is deductible when its own flag is true, or when the flag is NULL
+// and its category is marked deductible. Reimbursed expenses are excluded.
+func (r *ReportRepo) Tax(ctx context.Context, userID int64, year int) (*TaxReport, error) {
+	q := `
+SELECT COALESCE(NULLIF(c.tax_category, ''), c.name, 'Uncategorized') AS tax_category,
+       SUM(` + r.conv.amount("t.amount", "t.date") + `)::float8, COUNT(*)
+FROM transactions t
+LEFT JOIN categories c ON c.id = t.category_id
+WHERE t.user_id=$1 AND t.type='expense' AND NOT t.reimbursed
+  AND t.date >= $2 AND t.date < $3
+  AND COALESCE(t.tax_deductible, c.tax_deductible, FALSE)
+GROUP BY 1
+ORDER BY 2 DESC, 1
+`
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	rows, err := r.read.Query(ctx, q, userID, from, from.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := &TaxReport{Year: year, Categories: []TaxCategoryTotal{}}
+	for rows.Next() {
+		var tc TaxCategoryTotal
+		if err := rows.Scan(&tc.TaxCategory, &tc.Total, &tc.Count); err != nil {
+			return nil, err
+		}
+		tc.Total = round2(tc.Total)
+		out.Total += tc.Total
+		out.Categories = append(out.Categories, tc)
+	}
+	out.Total = round2(out.Total)
+	return out, rows.Err()
+}
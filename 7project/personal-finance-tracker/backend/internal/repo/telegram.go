@@ -0,0 +1,126 @@
+// backend/internal/repo/telegram.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TelegramLink is the Telegram chat a user linked to the bot (see migration 033).
+type TelegramLink struct {
+	UserID   int64     `json:"user_id"`
+	ChatID   int64     `json:"chat_id"`
+	Username string    `json:"username"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+// TelegramRepo stores links between users and Telegram chats. The bot works
+// without a tenant, since it learns the user from the chat.
+type TelegramRepo struct{ pool dbConn }
+
+// TelegramRepo accessor bound to the Store's pool.
+func (s *Store) TelegramRepo() *TelegramRepo { return &TelegramRepo{pool: s.db()} }
+
+// CreateLinkCode stores a one-time code linking a chat to userID until expires,
+// replacing codes handed out to the user before.
+func (r *TelegramRepo) CreateLinkCode(ctx context.Context, userID int64, code string, expires time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM telegram_link_codes WHERE user_id=$1 OR expires_at <= NOW()`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO telegram_link_codes (code, user_id, expires_at) VALUES ($1, $2, $3)`,
+		code, userID, expires); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Link redeems code for chatID and returns the user it was issued to, or 0 when the
+// code is unknown or expired. A chat previously linked to another user moves over,
+// and a user's previous chat is replaced.
+func (r *TelegramRepo) Link(ctx context.Context, code string, chatID int64, username string) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var userID int64
+	err = tx.QueryRow(ctx, `DELETE FROM telegram_link_codes WHERE code=$1 AND expires_at > NOW() RETURNING user_id`, code).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM telegram_links WHERE chat_id=$1 AND user_id<>$2`, chatID, userID); err != nil {
+		return 0, err
+	}
+	const q = `INSERT INTO telegram_links (user_id, chat_id, username) VALUES ($1, $2, $3)
+	           ON CONFLICT (user_id) DO UPDATE
+	           SET chat_id=EXCLUDED.chat_id, username=EXCLUDED.username, linked_at=NOW()`
+	if _, err := tx.Exec(ctx, q, userID, chatID, username); err != nil {
+		return 0, err
+	}
+	return userID, tx.Commit(ctx)
+}
+
+// Get returns the user's link, or (nil, nil) when no chat is linked.
+func (r *TelegramRepo) Get(ctx context.Context, userID int64) (*TelegramLink, error) {
+	var l TelegramLink
+	err := r.pool.QueryRow(ctx, `SELECT user_id, chat_id, username, linked_at FROM telegram_links WHERE user_id=$1`, userID).
+		Scan(&l.UserID, &l.ChatID, &l.Username, &l.LinkedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// UserByChat returns the user linked to chatID, or 0.
+func (r *TelegramRepo) UserByChat(ctx context.Context, chatID int64) (int64, error) {
+	var userID int64
+	err := r.pool.QueryRow(ctx, `SELECT user_id FROM telegram_links WHERE chat_id=$1`, chatID).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return userID, err
+}
+
+// ChatOf returns the chat linked to userID, or 0.
+func (r *TelegramRepo) ChatOf(ctx context.Context, userID int64) (int64, error) {
+	l, err := r.Get(ctx, userID)
+	if l == nil {
+		return 0, err
+	}
+	return l.ChatID, nil
+}
+
+// Unlink removes the user's link.
+func (r *TelegramRepo) Unlink(ctx context.Context, userID int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM telegram_links WHERE user_id=$1`, userID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// UnlinkChat removes the link of chatID, e.g. after the user blocked the bot.
+func (r *TelegramRepo) UnlinkChat(ctx context.Context, chatID int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM telegram_links WHERE chat_id=$1`, chatID)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
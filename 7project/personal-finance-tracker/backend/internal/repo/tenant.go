@@ -0,0 +1,83 @@
+// backend/internal/repo/tenant.go
+
+package repo
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type tenantKey struct{}
+
+// WithTenant scopes database access made with ctx to userID. With PrepareTenant
+// installed on the pool, row-level security policies (migration 025) then hide every
+// other user's rows, even from a query missing its user_id predicate.
+func WithTenant(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, tenantKey{}, userID)
+}
+
+// WithoutTenant lifts the tenant scope for work that must see every user's rows,
+// such as loading shared configuration while serving a request.
+func WithoutTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantKey{}, nil)
+}
+
+// TenantFrom returns the user ctx is scoped to, if any.
+func TenantFrom(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(tenantKey{}).(int64)
+	return id, ok
+}
+
+type actorKey struct{}
+
+// actor is who a write is attributed to in the audit log.
+type actor struct {
+	name      string
+	requestID string
+}
+
+// WithActor attributes writes made with ctx to name ("user:42", "admin", "job:<kind>")
+// and requestID in the audit log (migration 029). Without it the tenant is the actor.
+func WithActor(ctx context.Context, name, requestID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor{name: name, requestID: requestID})
+}
+
+// tenantData is the PgConn.CustomData key remembering the session variables a
+// connection was last set to, so reusing it for the same caller costs no round trip.
+const tenantData = "pft.tenant"
+
+// PrepareTenant is a pgxpool.Config.PrepareConn hook. It sets the app.user_id session
+// variable to the tenant of the acquiring ctx, or clears it for unscoped work such as
+// migrations and background jobs, and app.actor/app.request_id to the ctx's WithActor
+// values. Pools acquire a connection per query (or per transaction), so the variables
+// always match the caller.
+func PrepareTenant(ctx context.Context, conn *pgx.Conn) (bool, error) {
+	tenant := ""
+	if id, ok := TenantFrom(ctx); ok {
+		tenant = strconv.FormatInt(id, 10)
+	}
+	a, _ := ctx.Value(actorKey{}).(actor)
+	want := tenant + "|" + a.name + "|" + a.requestID
+	data := conn.PgConn().CustomData()
+	if have, _ := data[tenantData].(string); have == want {
+		return true, nil
+	}
+	const q = `SELECT set_config('app.user_id', $1, false), set_config('app.actor', $2, false),
+	                  set_config('app.request_id', $3, false)`
+	if _, err := conn.Exec(ctx, q, tenant, a.name, a.requestID); err != nil {
+		// The connection's state is unknown now; drop it rather than reuse it.
+		return false, err
+	}
+	data[tenantData] = want
+	return true, nil
+}
+
+// BypassesRowSecurity reports whether the connected role ignores row-level security
+// (superusers and BYPASSRLS roles), in which case tenant scoping has no effect.
+func (s *Store) BypassesRowSecurity(ctx context.Context) (bool, error) {
+	var bypass bool
+	err := s.Pool.QueryRow(ctx, `SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user`).Scan(&bypass)
+	return bypass, err
+}
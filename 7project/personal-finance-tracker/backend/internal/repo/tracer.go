@@ -0,0 +1,191 @@
+// backend/internal/repo/tracer.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"pft/internal/metrics"
+	"pft/internal/platform"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// QueryLogger is a pgx.QueryTracer that instruments every query. Install it on the pool config:
+//
+//	pcfg.ConnConfig.Tracer = &repo.QueryLogger{Logger: logger, SlowThreshold: 200 * time.Millisecond}
+//
+// - Failed queries are logged with the request ID carried by the query's context.
+// pgx.ErrNoRows and cancelled contexts are not logged; constraint violations
+// (SQLSTATE class 23), which handlers map to 4xx responses, are logged as warnings.
+// - Queries that ran out of time (see IsTimeout) are logged as warnings and counted
+// in metrics.DBTimeouts.
+// - Every query's duration is observed in metrics.DBQueryDuration.
+// - Queries slower than SlowThreshold (0 disables) are logged as warnings with their
+// parameters redacted to types, e.g. [int64 string(12) time.Time].
+// - With ExplainPool set, the plan of each slow SELECT is logged too (at most once per
+// statement every explainEvery). EXPLAIN runs on its own connection without ANALYZE,
+// so the query is planned, not executed again. Meant for debugging, not production.
+type QueryLogger struct {
+	Logger        *slog.Logger
+	SlowThreshold time.Duration
+	ExplainPool   *pgxpool.Pool
+
+	mu        sync.Mutex
+	explained map[string]time.Time
+}
+
+// explainEvery limits how often the same slow statement is explained.
+const explainEvery = 10 * time.Minute
+
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	sql   string
+	args  []any
+	start time.Time
+}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (t *QueryLogger) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, &queryTrace{sql: data.SQL, args: data.Args, start: time.Now()})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (t *QueryLogger) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	qt, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(qt.start)
+	op := statementKind(qt.sql)
+	sql := strings.Join(strings.Fields(qt.sql), " ")
+
+	err := data.Err
+	status := "ok"
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		status = "error"
+	}
+	metrics.DBQueryDuration.WithLabelValues(op, status).Observe(elapsed.Seconds())
+
+	if t.SlowThreshold > 0 && elapsed >= t.SlowThreshold {
+		metrics.DBSlowQueries.WithLabelValues(op).Inc()
+		t.Logger.WarnContext(ctx, "slow query",
+			"request_id", platform.RequestID(ctx),
+			"duration_ms", float64(elapsed.Microseconds())/1000,
+			"sql", sql,
+			"args", redactArgs(qt.args),
+		)
+		if t.ExplainPool != nil && op == "select" && t.shouldExplain(sql) {
+			go t.explain(sql, qt.args)
+		}
+	}
+
+	if err == nil || errors.Is(err, pgx.ErrNoRows) || errors.Is(err, context.Canceled) {
+		return
+	}
+	if IsTimeout(err) {
+		metrics.DBTimeouts.WithLabelValues(op).Inc()
+		t.Logger.WarnContext(ctx, "query timed out",
+			"request_id", platform.RequestID(ctx),
+			"duration_ms", float64(elapsed.Microseconds())/1000,
+			"sql", sql,
+		)
+		return
+	}
+	level := slog.LevelError
+	var pgerr *pgconn.PgError
+	if errors.As(err, &pgerr) && strings.HasPrefix(pgerr.Code, "23") {
+		level = slog.LevelWarn
+	}
+	t.Logger.Log(ctx, level, "query failed",
+		"request_id", platform.RequestID(ctx),
+		"sql", sql,
+		"error", err.Error(),
+	)
+}
+
+// shouldExplain reports whether sql has not been explained within explainEvery, and marks it.
+func (t *QueryLogger) shouldExplain(sql string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.explained == nil {
+		t.explained = map[string]time.Time{}
+	}
+	if last, ok := t.explained[sql]; ok && time.Since(last) < explainEvery {
+		return false
+	}
+	t.explained[sql] = time.Now()
+	return true
+}
+
+// explain logs the planner's plan for a slow statement.
+func (t *QueryLogger) explain(sql string, args []any) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := t.ExplainPool.Query(ctx, "EXPLAIN "+sql, args...)
+	if err != nil {
+		t.Logger.Warn("explain failed", "sql", sql, "error", err.Error())
+		return
+	}
+	defer rows.Close()
+	var plan []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			t.Logger.Warn("explain failed", "sql", sql, "error", err.Error())
+			return
+		}
+		plan = append(plan, line)
+	}
+	t.Logger.Info("slow query plan", "sql", sql, "plan", strings.Join(plan, "\n"))
+}
+
+// statementKind returns the metrics label for sql: select, insert, update, delete or other.
+// CTEs (WITH ...) are counted by the statement that follows them where it is obvious.
+func statementKind(sql string) string {
+	fields := strings.Fields(strings.ToLower(sql))
+	if len(fields) == 0 {
+		return "other"
+	}
+	switch fields[0] {
+	case "select", "insert", "update", "delete":
+		return fields[0]
+	case "with":
+		for _, f := range fields[1:] {
+			switch f {
+			case "insert", "update", "delete":
+				return f
+			}
+		}
+		return "select"
+	}
+	return "other"
+}
+
+// redactArgs describes query parameters without their values, which may hold personal
+// or financial data: strings and byte slices report their length, everything else its type.
+func redactArgs(args []any) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		switch v := a.(type) {
+		case nil:
+			out[i] = "nil"
+		case string:
+			out[i] = fmt.Sprintf("string(%d)", len(v))
+		case []byte:
+			out[i] = fmt.Sprintf("bytes(%d)", len(v))
+		default:
+			out[i] = fmt.Sprintf("%T", v)
+		}
+	}
+	return out
+}
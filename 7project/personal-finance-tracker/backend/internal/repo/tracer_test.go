@@ -0,0 +1,87 @@
+// backend/internal/repo/tracer_test.go
+//
+// Purpose:
+//   Verify the query tracer: statement classification for metrics, parameter
+//   redaction, that only queries over the threshold are logged as slow, and that
+//   timeouts are told apart from other failures.
+
+package repo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestStatementKind(t *testing.T) {
+	cases := map[string]string{
+		"SELECT 1":                                     "select",
+		"  insert into t values ($1)":                  "insert",
+		"WITH x AS (SELECT 1) UPDATE t SET a=1":        "update",
+		"WITH x AS (SELECT 1) SELECT * FROM x":         "select",
+		"CREATE TABLE IF NOT EXISTS schema_migrations": "other",
+		"": "other",
+	}
+	for sql, want := range cases {
+		if got := statementKind(sql); got != want {
+			t.Errorf("statementKind(%q) = %q, want %q", sql, got, want)
+		}
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	got := strings.Join(redactArgs([]any{int64(7), "secret memo", nil, []byte("xy"), time.Time{}}), " ")
+	if got != "int64 string(11) nil bytes(2) time.Time" {
+		t.Fatalf("unexpected redaction: %s", got)
+	}
+}
+
+func TestQueryLogger_SlowQueries(t *testing.T) {
+	var buf bytes.Buffer
+	ql := &QueryLogger{Logger: slog.New(slog.NewTextHandler(&buf, nil)), SlowThreshold: 10 * time.Millisecond}
+
+	run := func(sql string, took time.Duration) {
+		ctx := ql.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql, Args: []any{"alice@example.com"}})
+		ctx.Value(queryTraceKey{}).(*queryTrace).start = time.Now().Add(-took)
+		ql.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	}
+	run("SELECT fast", time.Millisecond)
+	run("SELECT slow", 50*time.Millisecond)
+
+	out := buf.String()
+	if strings.Contains(out, "fast") || !strings.Contains(out, "slow query") || !strings.Contains(out, "SELECT slow") {
+		t.Fatalf("expected only the slow query to be logged:\n%s", out)
+	}
+	if strings.Contains(out, "alice") || !strings.Contains(out, "string(17)") {
+		t.Fatalf("parameters must be redacted:\n%s", out)
+	}
+}
+
+func TestQueryLogger_Timeouts(t *testing.T) {
+	var buf bytes.Buffer
+	ql := &QueryLogger{Logger: slog.New(slog.NewTextHandler(&buf, nil))}
+	for _, err := range []error{
+		fmt.Errorf("timeout: %w", context.DeadlineExceeded),
+		&pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"},
+	} {
+		if !IsTimeout(err) {
+			t.Errorf("IsTimeout(%v) = false", err)
+		}
+		ctx := ql.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT report"})
+		ql.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: err})
+	}
+	if IsTimeout(&pgconn.PgError{Code: "23505"}) || IsTimeout(context.Canceled) {
+		t.Error("only deadlines and cancelled statements are timeouts")
+	}
+	out := buf.String()
+	if strings.Count(out, "query timed out") != 2 || strings.Contains(out, "level=ERROR") {
+		t.Fatalf("expected two timeout warnings:\n%s", out)
+	}
+}
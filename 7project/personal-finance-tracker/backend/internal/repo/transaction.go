@@ -4,51 +4,94 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"strconv"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
 )
 
 // Transaction is the repository-layer DTO mirroring the transactions table.
-// CategoryID is nullable (ON DELETE SET NULL). Description is stored as text.
+// CategoryID is nullable (ON DELETE SET NULL). Description is stored as text,
+// encrypted when the Store uses encryption (see UseEncryption).
+// TaxDeductible is nullable: nil means the category's default applies.
+// Reimbursable expenses may be grouped into a claim (ClaimID); Reimbursed is set once
+// that claim is paid, which removes the amount from spending totals.
+// AccountID is the account the money moved through, when the user tracks accounts.
+// OverrideLimit saves an expense above its category's "block" limit; LimitWarning is
+// set on the result of Create and Update when an expense exceeds its category's limit
+// (see checkLimit).
 type Transaction struct {
-	ID          int64     `json:"id"`
-	UserID      int64     `json:"user_id"`
-	CategoryID  *int64    `json:"category_id"` // nullable because of ON DELETE SET NULL
-	Amount      float64   `json:"amount"`
-	Type        string    `json:"type"` // "income" | "expense"
-	Date        time.Time `json:"date"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID            int64     `json:"id"`
+	UserID        int64     `json:"user_id"`
+	CategoryID    *int64    `json:"category_id"` // nullable because of ON DELETE SET NULL
+	Amount        float64   `json:"amount"`
+	Type          string    `json:"type"` // "income" | "expense"
+	Date          time.Time `json:"date"`
+	Description   string    `json:"description"`
+	TaxDeductible *bool     `json:"tax_deductible"`
+	Reimbursable  bool      `json:"reimbursable"`
+	ClaimID       *int64    `json:"claim_id"`
+	Reimbursed    bool      `json:"reimbursed"`
+	AccountID     *int64    `json:"account_id"`
+	RuleID        *int64    `json:"recurring_rule_id"` // set on transactions generated by a recurring rule
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	OverrideLimit bool          `json:"-"`
+	LimitWarning  *LimitWarning `json:"limit_warning,omitempty"`
+}
+
+// txnCols lists the transactions columns in the order expected by Transaction.scanDest.
+const txnCols = `id, user_id, category_id, amount, type, date, description, tax_deductible,
+                 reimbursable, claim_id, reimbursed, account_id, recurring_rule_id, created_at, updated_at`
+
+// txnColsT is txnCols qualified with the "t" alias for joined queries.
+const txnColsT = `t.id, t.user_id, t.category_id, t.amount, t.type, t.date, t.description, t.tax_deductible,
+                  t.reimbursable, t.claim_id, t.reimbursed, t.account_id, t.recurring_rule_id, t.created_at, t.updated_at`
+
+// scanDest returns scan destinations matching txnCols.
+func (t *Transaction) scanDest() []any {
+	return []any{
+		&t.ID, &t.UserID, &t.CategoryID, &t.Amount, &t.Type, &t.Date, &t.Description, &t.TaxDeductible,
+		&t.Reimbursable, &t.ClaimID, &t.Reimbursed, &t.AccountID, &t.RuleID, &t.CreatedAt, &t.UpdatedAt,
+	}
 }
 
 // TransactionRepo provides CRUD and list operations for transactions via pgx.
-type TransactionRepo struct{ pool *pgxpool.Pool }
+// List reads from read (the replica when configured); everything else uses pool.
+type TransactionRepo struct {
+	pool  dbConn
+	read  querier
+	crypt *fieldCrypt
+}
 
 // TransactionRepo accessor bound to the Store's pool.
-func (s *Store) TransactionRepo() *TransactionRepo { return &TransactionRepo{pool: s.Pool} }
+func (s *Store) TransactionRepo() *TransactionRepo {
+	return &TransactionRepo{pool: s.db(), read: s.reader(), crypt: s.crypt}
+}
 
 // TxnListFilter captures optional filters and pagination for listing queries.
 // - From/To: inclusive date range bounds
 // - CategoryID: limit to a specific category
 // - Type: limit to "income" or "expense"
+// - Reimbursable: limit to (non-)reimbursable expenses
 // - Limit/Offset: pagination parameters
 type TxnListFilter struct {
-	From       *time.Time
-	To         *time.Time
-	CategoryID *int64
-	Type       *string
-	Limit      int
-	Offset     int
+	From         *time.Time
+	To           *time.Time
+	CategoryID   *int64
+	Type         *string
+	Reimbursable *bool
+	Limit        int
+	Offset       int
 }
 
-// List returns transactions for a user with optional filters and pagination.
-// Builds SQL dynamically with positional parameters ($1, $2, ...) to avoid injection.
-func (r *TransactionRepo) List(ctx context.Context, userID int64, f TxnListFilter) ([]Transaction, error) {
-	q := `SELECT id, user_id, category_id, amount, type, date, description, created_at
-	      FROM transactions
-	      WHERE user_id=$1`
+// where returns the WHERE clause selecting a user's transactions matching the filters,
+// with positional parameters ($1, $2, ...) to avoid injection, and its arguments.
+// From/To compare the date column directly so only the matching monthly partitions are scanned.
+func (f TxnListFilter) where(userID int64) (string, []any) {
+	q := ` WHERE user_id=$1`
 	args := []any{userID}
 	i := 2
 
@@ -73,9 +116,18 @@ func (r *TransactionRepo) List(ctx context.Context, userID int64, f TxnListFilte
 		args = append(args, *f.Type)
 		i++
 	}
+	if f.Reimbursable != nil {
+		q += " AND reimbursable = $" + itoa(i)
+		args = append(args, *f.Reimbursable)
+	}
+	return q, args
+}
 
+// List returns transactions for a user with optional filters and pagination.
+func (r *TransactionRepo) List(ctx context.Context, userID int64, f TxnListFilter) ([]Transaction, error) {
+	where, args := f.where(userID)
 	// Ascending order feels natural for Jan→Dec charts; id tie-breaker for stability.
-	q += " ORDER BY date ASC, id ASC"
+	q := `SELECT ` + txnCols + ` FROM transactions` + where + ` ORDER BY date ASC, id ASC`
 
 	// Guardrails for pagination inputs.
 	// Generous defaults and upper bounds so the yearly view can fetch everything in one go.
@@ -85,13 +137,10 @@ func (r *TransactionRepo) List(ctx context.Context, userID int64, f TxnListFilte
 	if f.Offset < 0 {
 		f.Offset = 0
 	}
-	q += " LIMIT $" + itoa(i)
-	args = append(args, f.Limit)
-	i++
-	q += " OFFSET $" + itoa(i)
-	args = append(args, f.Offset)
+	q += " LIMIT $" + itoa(len(args)+1) + " OFFSET $" + itoa(len(args)+2)
+	args = append(args, f.Limit, f.Offset)
 
-	rows, err := r.pool.Query(ctx, q, args...)
+	rows, err := r.read.Query(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -100,45 +149,227 @@ func (r *TransactionRepo) List(ctx context.Context, userID int64, f TxnListFilte
 	var out []Transaction
 	for rows.Next() {
 		var t Transaction
-		if err := rows.Scan(
-			&t.ID, &t.UserID, &t.CategoryID, &t.Amount, &t.Type, &t.Date, &t.Description, &t.CreatedAt,
-		); err != nil {
+		if err := rows.Scan(t.scanDest()...); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, r.crypt.openAll(ctx, out)
+}
+
+// Each calls fn with every transaction matching f, in List's order, as rows are read
+// from the database, so arbitrarily long histories never sit in memory at once.
+// Limit and Offset apply only when positive; there is no upper bound. An error from
+// fn stops the scan and is returned. The query runs in its own read transaction with
+// statement_timeout lifted, as it lasts as long as fn takes to consume the rows: a
+// slow client must not have its stream cut off by the pool's timeout. The request's
+// context still bounds it.
+func (r *TransactionRepo) Each(ctx context.Context, userID int64, f TxnListFilter, fn func(*Transaction) error) error {
+	where, args := f.where(userID)
+	q := `SELECT ` + txnCols + ` FROM transactions` + where + ` ORDER BY date ASC, id ASC`
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		q += " LIMIT $" + itoa(len(args))
+	}
+	if f.Offset > 0 {
+		args = append(args, f.Offset)
+		q += " OFFSET $" + itoa(len(args))
+	}
+
+	tx, err := r.read.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	// Nothing is written; rolling back also undoes SET LOCAL in an enclosing transaction.
+	defer func() { _ = tx.Rollback(ctx) }()
+	if _, err := tx.Exec(ctx, `SET LOCAL statement_timeout = 0`); err != nil {
+		return err
+	}
+	rows, err := tx.Query(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t Transaction
+		if err := rows.Scan(t.scanDest()...); err != nil {
+			return err
+		}
+		if err := r.crypt.open(ctx, userID, &t.Description); err != nil {
+			return err
+		}
+		if err := fn(&t); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Since returns up to limit of the user's transactions created after the one with id
+// afterID (0: any), newest first, for clients polling for new transactions.
+func (r *TransactionRepo) Since(ctx context.Context, userID, afterID int64, limit int) ([]Transaction, error) {
+	const q = `SELECT ` + txnCols + ` FROM transactions WHERE user_id=$1 AND id > $2 ORDER BY id DESC LIMIT $3`
+	rows, err := r.read.Query(ctx, q, userID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []Transaction{}
+	for rows.Next() {
+		var t Transaction
+		if err := rows.Scan(t.scanDest()...); err != nil {
 			return nil, err
 		}
 		out = append(out, t)
 	}
-	return out, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, r.crypt.openAll(ctx, out)
+}
+
+// Get fetches a single transaction by id scoped to the user.
+// Returns (nil, nil) when no row is found.
+func (r *TransactionRepo) Get(ctx context.Context, userID, id int64) (*Transaction, error) {
+	const q = `SELECT ` + txnCols + `
+	           FROM transactions
+	           WHERE user_id=$1 AND id=$2`
+	var t Transaction
+	err := r.pool.QueryRow(ctx, q, userID, id).Scan(t.scanDest()...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := r.crypt.open(ctx, userID, &t.Description); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// RecentCategory returns the category of the user's latest transaction of typ whose
+// description matches description case-insensitively, or nil when there is none (or
+// that category was deleted). Used to categorize quick entries like "coffee 3.50".
+// Encrypted descriptions are matched by their blind index.
+func (r *TransactionRepo) RecentCategory(ctx context.Context, userID int64, typ, description string) (*int64, error) {
+	index, err := r.crypt.index(ctx, userID, description)
+	if err != nil {
+		return nil, err
+	}
+	const q = `SELECT t.category_id
+	           FROM transactions t
+	           JOIN categories c ON c.id = t.category_id AND c.deleted_at IS NULL
+	           WHERE t.user_id=$1 AND t.type=$2 AND (t.description_hash = $4 OR lower(t.description) = lower($3))
+	           ORDER BY t.date DESC, t.id DESC
+	           LIMIT 1`
+	var id int64
+	err = r.pool.QueryRow(ctx, q, userID, typ, description, index).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
 }
 
 // Create inserts a new transaction and returns the inserted row with timestamps.
+// Returns ErrPeriodClosed if the transaction is dated in a closed month.
 func (r *TransactionRepo) Create(ctx context.Context, t *Transaction) (*Transaction, error) {
-	const q = `INSERT INTO transactions (user_id, category_id, amount, type, date, description)
-	           VALUES ($1,$2,$3,$4,$5,$6)
-	           RETURNING id, user_id, category_id, amount, type, date, description, created_at`
+	if err := ensureOpen(ctx, r.pool, t.UserID, t.Date); err != nil {
+		return nil, err
+	}
+	if err := ensurePartition(ctx, r.pool, t.Date); err != nil {
+		return nil, err
+	}
+	warning, err := checkLimit(ctx, r.pool, t.UserID, t)
+	if err != nil {
+		return nil, err
+	}
+	desc, index, err := r.crypt.seal(ctx, t.UserID, t.Description)
+	if err != nil {
+		return nil, err
+	}
+	const q = `INSERT INTO transactions (user_id, category_id, amount, type, date, description, tax_deductible, reimbursable,
+	                                     description_hash, account_id, recurring_rule_id)
+	           VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+	           RETURNING ` + txnCols
 	var out Transaction
 	if err := r.pool.QueryRow(ctx, q,
-		t.UserID, t.CategoryID, t.Amount, t.Type, t.Date, t.Description,
-	).Scan(
-		&out.ID, &out.UserID, &out.CategoryID, &out.Amount, &out.Type, &out.Date, &out.Description, &out.CreatedAt,
-	); err != nil {
-		return nil, err
+		t.UserID, t.CategoryID, t.Amount, t.Type, t.Date, desc, t.TaxDeductible, t.Reimbursable, index, t.AccountID, t.RuleID,
+	).Scan(out.scanDest()...); err != nil {
+		return nil, periodErr(err)
 	}
+	out.Description, out.LimitWarning = t.Description, warning
 	return &out, nil
 }
 
 // Update modifies an existing transaction (scoped by userID) and returns the updated row.
 // Matching on both user_id and id enforces tenant isolation at the SQL level.
+// Clearing the reimbursable flag also detaches the transaction from its claim.
+// Returns (nil, nil) when not found and ErrPeriodClosed if either the current or the
+// new date falls in a closed month. The category limit is only applied again when the
+// amount, category, date or type changes, so an expense saved above it (before the
+// limit was set, or overridden) can still be edited otherwise.
 func (r *TransactionRepo) Update(ctx context.Context, userID, id int64, t *Transaction) (*Transaction, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var current time.Time
+	var curCategory *int64
+	var curAmount float64
+	var curType string
+	err = tx.QueryRow(ctx, `SELECT date, category_id, amount, type FROM transactions WHERE user_id=$1 AND id=$2 FOR UPDATE`, userID, id).
+		Scan(&current, &curCategory, &curAmount, &curType)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureOpen(ctx, tx, userID, current, t.Date); err != nil {
+		return nil, err
+	}
+	if err := ensurePartition(ctx, tx, t.Date); err != nil {
+		return nil, err
+	}
+	var warning *LimitWarning
+	sameCategory := (curCategory == nil) == (t.CategoryID == nil) && (curCategory == nil || *curCategory == *t.CategoryID)
+	if !sameCategory || curAmount != t.Amount || !current.Equal(t.Date) || curType != t.Type {
+		if warning, err = checkLimit(ctx, tx, userID, t); err != nil {
+			return nil, err
+		}
+	}
+
+	desc, index, err := r.crypt.seal(ctx, userID, t.Description)
+	if err != nil {
+		return nil, err
+	}
 	const q = `UPDATE transactions
-	           SET category_id=$3, amount=$4, type=$5, date=$6, description=$7
+	           SET category_id=$3, amount=$4, type=$5, date=$6, description=$7, tax_deductible=$8,
+	               reimbursable=$9,
+	               claim_id=CASE WHEN $9 THEN claim_id END,
+	               reimbursed=($9 AND reimbursed),
+	               description_hash=$10, account_id=$11
 	           WHERE user_id=$1 AND id=$2
-	           RETURNING id, user_id, category_id, amount, type, date, description, created_at`
+	           RETURNING ` + txnCols
 	var out Transaction
-	if err := r.pool.QueryRow(ctx, q,
-		userID, id, t.CategoryID, t.Amount, t.Type, t.Date, t.Description,
-	).Scan(
-		&out.ID, &out.UserID, &out.CategoryID, &out.Amount, &out.Type, &out.Date, &out.Description, &out.CreatedAt,
-	); err != nil {
+	if err := tx.QueryRow(ctx, q,
+		userID, id, t.CategoryID, t.Amount, t.Type, t.Date, desc, t.TaxDeductible, t.Reimbursable, index, t.AccountID,
+	).Scan(out.scanDest()...); err != nil {
+		return nil, periodErr(err)
+	}
+	out.Description, out.LimitWarning = t.Description, warning
+	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
 	return &out, nil
@@ -146,13 +377,32 @@ func (r *TransactionRepo) Update(ctx context.Context, userID, id int64, t *Trans
 
 // Delete removes a transaction by id for the given user.
 // Returns true when a row was affected; false indicates no match.
+// Returns ErrPeriodClosed if the transaction is dated in a closed month.
 func (r *TransactionRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
-	const q = `DELETE FROM transactions WHERE user_id=$1 AND id=$2`
-	ct, err := r.pool.Exec(ctx, q, userID, id)
+	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return false, err
 	}
-	return ct.RowsAffected() > 0, nil
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var current time.Time
+	err = tx.QueryRow(ctx, `SELECT date FROM transactions WHERE user_id=$1 AND id=$2 FOR UPDATE`, userID, id).Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := ensureOpen(ctx, tx, userID, current); err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM transactions WHERE user_id=$1 AND id=$2`, userID, id); err != nil {
+		return false, periodErr(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // itoa converts an integer to a string for SQL placeholder construction.
@@ -0,0 +1,61 @@
+// backend/internal/repo/txnlimit.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// LimitWarning reports an expense above its category's per-transaction limit that was
+// saved anyway: the limit only warns, or a blocking limit was overridden.
+type LimitWarning struct {
+	CategoryID int64   `json:"category_id"`
+	Limit      float64 `json:"limit"`
+	Mode       string  `json:"mode"` // "warn" | "block"
+}
+
+// LimitError is returned when an expense exceeds its category's "block" limit and
+// the transaction does not set OverrideLimit. Nothing is saved.
+type LimitError struct {
+	CategoryID int64
+	Category   string
+	Limit      float64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("expenses in %q are limited to %.2f each", e.Category, e.Limit)
+}
+
+// checkLimit applies the per-transaction limit of t's category to t when it is an
+// expense of userID. It returns the warning to report with the saved transaction, nil
+// within the limit, or a *LimitError above a "block" limit without OverrideLimit.
+// Every write path that creates or changes an expense calls it, so the limit holds
+// whichever client, bot or job saves the transaction.
+func checkLimit(ctx context.Context, q rowQuerier, userID int64, t *Transaction) (*LimitWarning, error) {
+	if t.Type != "expense" || t.CategoryID == nil {
+		return nil, nil
+	}
+	var name string
+	var limit *float64
+	var mode *string
+	err := q.QueryRow(ctx, `SELECT name, txn_limit, txn_limit_mode FROM categories
+	                        WHERE user_id=$1 AND id=$2 AND deleted_at IS NULL`, userID, *t.CategoryID).
+		Scan(&name, &limit, &mode)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if limit == nil || mode == nil || t.Amount <= *limit {
+		return nil, nil
+	}
+	if *mode == "block" && !t.OverrideLimit {
+		return nil, &LimitError{CategoryID: *t.CategoryID, Category: name, Limit: *limit}
+	}
+	return &LimitWarning{CategoryID: *t.CategoryID, Limit: *limit, Mode: *mode}, nil
+}
@@ -0,0 +1,136 @@
+// backend/internal/repo/usage.go
+
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// Usage is API usage over a day or a range (see migration 039).
+// - Errors: requests answered with a 4xx or 5xx status
+// - BytesIn/BytesOut: request and response body sizes
+type Usage struct {
+	Requests int64 `json:"requests"`
+	Errors   int64 `json:"errors"`
+	BytesIn  int64 `json:"bytes_in"`
+	BytesOut int64 `json:"bytes_out"`
+}
+
+// UsageDay is one user's usage on a UTC day ("2006-01-02").
+type UsageDay struct {
+	Day string `json:"day"`
+	Usage
+}
+
+// UsageCount is usage counted by the API and not yet stored.
+type UsageCount struct {
+	UserID int64
+	Day    time.Time
+	Usage
+}
+
+// UserUsage is a user's usage summed over a range, for operators.
+type UserUsage struct {
+	UserID int64  `json:"user_id"`
+	Email  string `json:"email"`
+	Usage
+}
+
+// UsageRepo stores daily API usage counters.
+type UsageRepo struct{ pool dbConn }
+
+// UsageRepo accessor bound to the Store's pool.
+func (s *Store) UsageRepo() *UsageRepo { return &UsageRepo{pool: s.db()} }
+
+// Add adds counts to the stored daily totals in one statement.
+func (r *UsageRepo) Add(ctx context.Context, counts []UsageCount) error {
+	if len(counts) == 0 {
+		return nil
+	}
+	users := make([]int64, len(counts))
+	days := make([]string, len(counts))
+	requests, errs := make([]int64, len(counts)), make([]int64, len(counts))
+	in, out := make([]int64, len(counts)), make([]int64, len(counts))
+	for i, c := range counts {
+		users[i], days[i] = c.UserID, c.Day.UTC().Format("2006-01-02")
+		requests[i], errs[i], in[i], out[i] = c.Requests, c.Errors, c.BytesIn, c.BytesOut
+	}
+	// Users deleted meanwhile are skipped rather than failing the whole batch.
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO api_usage (user_id, day, requests, errors, bytes_in, bytes_out)
+		SELECT c.user_id, c.day::date, c.requests, c.errors, c.bytes_in, c.bytes_out
+		FROM unnest($1::bigint[], $2::text[], $3::bigint[], $4::bigint[], $5::bigint[], $6::bigint[])
+		     AS c(user_id, day, requests, errors, bytes_in, bytes_out)
+		WHERE EXISTS (SELECT 1 FROM users u WHERE u.id = c.user_id)
+		ON CONFLICT (user_id, day) DO UPDATE SET
+		    requests  = api_usage.requests + EXCLUDED.requests,
+		    errors    = api_usage.errors + EXCLUDED.errors,
+		    bytes_in  = api_usage.bytes_in + EXCLUDED.bytes_in,
+		    bytes_out = api_usage.bytes_out + EXCLUDED.bytes_out`,
+		users, days, requests, errs, in, out)
+	return err
+}
+
+// Days returns the user's usage per day from from to to (inclusive), oldest first;
+// days without requests are omitted.
+func (r *UsageRepo) Days(ctx context.Context, userID int64, from, to time.Time) ([]UsageDay, error) {
+	rows, err := r.pool.Query(ctx, `SELECT day::text, requests, errors, bytes_in, bytes_out FROM api_usage
+	                                WHERE user_id=$1 AND day BETWEEN $2::date AND $3::date ORDER BY day`,
+		userID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []UsageDay{}
+	for rows.Next() {
+		var d UsageDay
+		if err := rows.Scan(&d.Day, &d.Requests, &d.Errors, &d.BytesIn, &d.BytesOut); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// Top returns the limit users with the most requests from from to to (inclusive),
+// and the total over all users.
+func (r *UsageRepo) Top(ctx context.Context, from, to time.Time, limit int) ([]UserUsage, Usage, error) {
+	var total Usage
+	err := r.pool.QueryRow(ctx, `SELECT COALESCE(SUM(requests), 0), COALESCE(SUM(errors), 0),
+	                                    COALESCE(SUM(bytes_in), 0), COALESCE(SUM(bytes_out), 0)
+	                             FROM api_usage WHERE day BETWEEN $1::date AND $2::date`,
+		from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Scan(&total.Requests, &total.Errors, &total.BytesIn, &total.BytesOut)
+	if err != nil {
+		return nil, total, err
+	}
+	rows, err := r.pool.Query(ctx, `SELECT a.user_id, u.email, SUM(a.requests), SUM(a.errors), SUM(a.bytes_in), SUM(a.bytes_out)
+	                                FROM api_usage a JOIN users u ON u.id = a.user_id
+	                                WHERE a.day BETWEEN $1::date AND $2::date
+	                                GROUP BY a.user_id, u.email
+	                                ORDER BY SUM(a.requests) DESC, a.user_id LIMIT $3`,
+		from.Format("2006-01-02"), to.Format("2006-01-02"), limit)
+	if err != nil {
+		return nil, total, err
+	}
+	defer rows.Close()
+	out := []UserUsage{}
+	for rows.Next() {
+		var u UserUsage
+		if err := rows.Scan(&u.UserID, &u.Email, &u.Requests, &u.Errors, &u.BytesIn, &u.BytesOut); err != nil {
+			return nil, total, err
+		}
+		out = append(out, u)
+	}
+	return out, total, rows.Err()
+}
+
+// Cleanup deletes days before cutoff and returns how many rows it removed.
+func (r *UsageRepo) Cleanup(ctx context.Context, cutoff time.Time) (int64, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM api_usage WHERE day < $1::date`, cutoff.Format("2006-01-02"))
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // User represents a row from the users table.
@@ -19,13 +18,14 @@ type User struct {
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"-"`          // excluded from JSON output
 	CreatedAt    time.Time `json:"created_at"` // server-set timestamp
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 // UserRepo provides basic access methods for the users table.
-type UserRepo struct{ pool *pgxpool.Pool }
+type UserRepo struct{ pool dbConn }
 
 // UserRepo getter on Store, mirroring the pattern used by other repositories.
-func (s *Store) UserRepo() *UserRepo { return &UserRepo{pool: s.Pool} }
+func (s *Store) UserRepo() *UserRepo { return &UserRepo{pool: s.db()} }
 
 // Create inserts a new user with a previously computed password hash.
 // Returns the inserted row, including generated ID and timestamps.
@@ -33,10 +33,10 @@ func (r *UserRepo) Create(ctx context.Context, name, email, passwordHash string)
 	const q = `
 INSERT INTO users (name, email, password_hash)
 VALUES ($1, $2, $3)
-RETURNING id, name, email, password_hash, created_at`
+RETURNING id, name, email, password_hash, created_at, updated_at`
 	var u User
 	if err := r.pool.QueryRow(ctx, q, name, email, passwordHash).
-		Scan(&u.ID, &u.Name, &u.Email, &u.PasswordHash, &u.CreatedAt); err != nil {
+		Scan(&u.ID, &u.Name, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt); err != nil {
 		return nil, err
 	}
 	return &u, nil
@@ -46,12 +46,12 @@ RETURNING id, name, email, password_hash, created_at`
 // On no match, returns (nil, nil) rather than an error.
 func (r *UserRepo) GetByEmail(ctx context.Context, email string) (*User, error) {
 	const q = `
-SELECT id, name, email, password_hash, created_at
+SELECT id, name, email, password_hash, created_at, updated_at
 FROM users
 WHERE email = $1`
 	var u User
 	if err := r.pool.QueryRow(ctx, q, email).
-		Scan(&u.ID, &u.Name, &u.Email, &u.PasswordHash, &u.CreatedAt); err != nil {
+		Scan(&u.ID, &u.Name, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
@@ -64,12 +64,12 @@ WHERE email = $1`
 // On no match, returns (nil, nil) rather than an error.
 func (r *UserRepo) GetByID(ctx context.Context, id int64) (*User, error) {
 	const q = `
-SELECT id, name, email, password_hash, created_at
+SELECT id, name, email, password_hash, created_at, updated_at
 FROM users
 WHERE id = $1`
 	var u User
 	if err := r.pool.QueryRow(ctx, q, id).
-		Scan(&u.ID, &u.Name, &u.Email, &u.PasswordHash, &u.CreatedAt); err != nil {
+		Scan(&u.ID, &u.Name, &u.Email, &u.PasswordHash, &u.CreatedAt, &u.UpdatedAt); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
@@ -0,0 +1,283 @@
+// backend/internal/repo/webhook.go
+
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// WebhookEndpoint is a URL an integrator registered to receive change events (see
+// migration 051). The signing secret is only shown when the endpoint is created.
+// - Topics: outbox topics to send, e.g. "transactions.created"; empty sends every topic
+// - Enabled: false pauses deliveries; pending ones are sent once it is re-enabled
+type WebhookEndpoint struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	Secret      string    `json:"-"`
+	Topics      []string  `json:"topics"`
+	Enabled     bool      `json:"enabled"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery is one event on its way to one endpoint.
+// - Status: "pending" (being retried), "delivered" or "dead" (gave up; see Replay)
+// - NextAttemptAt: when a pending delivery is tried next
+// - Log: the attempts so far, oldest first; filled in by the listings only
+type WebhookDelivery struct {
+	ID            int64            `json:"id"`
+	EndpointID    int64            `json:"endpoint_id"`
+	UserID        int64            `json:"user_id"`
+	EventID       int64            `json:"event_id"`
+	Topic         string           `json:"topic"`
+	Payload       json.RawMessage  `json:"payload"`
+	Status        string           `json:"status"`
+	Attempts      int              `json:"attempts"`
+	NextAttemptAt *time.Time       `json:"next_attempt_at"`
+	LastError     *string          `json:"last_error"`
+	CreatedAt     time.Time        `json:"created_at"`
+	DeliveredAt   *time.Time       `json:"delivered_at"`
+	Log           []WebhookAttempt `json:"log,omitempty"`
+
+	// Set on claimed deliveries, for sending.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// WebhookAttempt is one try at sending a delivery and what the endpoint answered.
+// - StatusCode: the HTTP status, nil when there was no answer (see Error)
+// - ResponseBody: the first 1 KiB of the answer
+type WebhookAttempt struct {
+	Attempt      int       `json:"attempt"`
+	StatusCode   *int      `json:"status_code"`
+	Error        *string   `json:"error"`
+	ResponseBody string    `json:"response_body"`
+	DurationMS   int       `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// WebhookRepo stores webhook endpoints and their deliveries. Fan-out, claiming and
+// recording attempts work without a tenant.
+type WebhookRepo struct{ pool dbConn }
+
+// WebhookRepo accessor bound to the Store's pool.
+func (s *Store) WebhookRepo() *WebhookRepo { return &WebhookRepo{pool: s.db()} }
+
+const (
+	webhookEndpointCols = `id, user_id, url, description, secret, topics, enabled, created_at, updated_at`
+	webhookDeliveryCols = `d.id, d.endpoint_id, d.user_id, d.event_id, d.topic, d.payload, d.status, d.attempts,
+	                       CASE WHEN d.status = 'pending' THEN d.next_attempt_at END, d.last_error, d.created_at, d.delivered_at`
+)
+
+func scanWebhookEndpoint(row pgx.Row) (*WebhookEndpoint, error) {
+	var w WebhookEndpoint
+	err := row.Scan(&w.ID, &w.UserID, &w.URL, &w.Description, &w.Secret, &w.Topics, &w.Enabled, &w.CreatedAt, &w.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (d *WebhookDelivery) scanDest() []any {
+	return []any{&d.ID, &d.EndpointID, &d.UserID, &d.EventID, &d.Topic, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastError, &d.CreatedAt, &d.DeliveredAt}
+}
+
+// ListEndpoints returns the user's endpoints.
+func (r *WebhookRepo) ListEndpoints(ctx context.Context, userID int64) ([]WebhookEndpoint, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+webhookEndpointCols+` FROM webhook_endpoints WHERE user_id=$1 ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []WebhookEndpoint{}
+	for rows.Next() {
+		w, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *w)
+	}
+	return out, rows.Err()
+}
+
+// GetEndpoint returns one of the user's endpoints, or (nil, nil).
+func (r *WebhookRepo) GetEndpoint(ctx context.Context, userID, id int64) (*WebhookEndpoint, error) {
+	return scanWebhookEndpoint(r.pool.QueryRow(ctx, `SELECT `+webhookEndpointCols+` FROM webhook_endpoints WHERE user_id=$1 AND id=$2`, userID, id))
+}
+
+// CreateEndpoint registers w.URL for the user, signed with w.Secret.
+func (r *WebhookRepo) CreateEndpoint(ctx context.Context, w *WebhookEndpoint) (*WebhookEndpoint, error) {
+	const q = `INSERT INTO webhook_endpoints (user_id, url, description, secret, topics) VALUES ($1,$2,$3,$4,$5)
+	           RETURNING ` + webhookEndpointCols
+	return scanWebhookEndpoint(r.pool.QueryRow(ctx, q, w.UserID, w.URL, w.Description, w.Secret, w.Topics))
+}
+
+// UpdateEndpoint replaces an endpoint's URL, description, topics and enabled flag; the
+// secret stays. Returns (nil, nil) when not found.
+func (r *WebhookRepo) UpdateEndpoint(ctx context.Context, userID, id int64, w *WebhookEndpoint) (*WebhookEndpoint, error) {
+	const q = `UPDATE webhook_endpoints SET url=$3, description=$4, topics=$5, enabled=$6
+	           WHERE user_id=$1 AND id=$2 RETURNING ` + webhookEndpointCols
+	return scanWebhookEndpoint(r.pool.QueryRow(ctx, q, userID, id, w.URL, w.Description, w.Topics, w.Enabled))
+}
+
+// DeleteEndpoint removes one of the user's endpoints with its deliveries.
+func (r *WebhookRepo) DeleteEndpoint(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM webhook_endpoints WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// Enqueue queues e for each of its user's enabled endpoints subscribed to its topic.
+// Enqueueing an event again is a no-op, so it is safe under at-least-once dispatch.
+func (r *WebhookRepo) Enqueue(ctx context.Context, e *OutboxEvent) error {
+	const q = `INSERT INTO webhook_deliveries (endpoint_id, user_id, event_id, topic, payload)
+	           SELECT id, user_id, $2, $3, $4 FROM webhook_endpoints
+	           WHERE user_id=$1 AND enabled AND (topics = '{}' OR $3 = ANY(topics))
+	           ON CONFLICT (endpoint_id, event_id) DO NOTHING`
+	_, err := r.pool.Exec(ctx, q, e.UserID, e.ID, e.Topic, e.Payload)
+	return err
+}
+
+// Claim leases up to limit due deliveries to enabled endpoints, oldest first, counts
+// the attempt and fills in URL and Secret. Deliveries whose lease expired are claimed
+// again.
+func (r *WebhookRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]WebhookDelivery, error) {
+	const q = `WITH claimed AS (
+	               UPDATE webhook_deliveries SET attempts=attempts+1, locked_at=NOW()
+	               WHERE id IN (
+	                   SELECT d.id FROM webhook_deliveries d JOIN webhook_endpoints w ON w.id = d.endpoint_id
+	                   WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.enabled
+	                     AND (d.locked_at IS NULL OR d.locked_at < NOW() - $2 * INTERVAL '1 second')
+	                   ORDER BY d.next_attempt_at, d.id
+	                   FOR UPDATE OF d SKIP LOCKED
+	                   LIMIT $1)
+	               RETURNING *)
+	           SELECT ` + webhookDeliveryCols + `, w.url, w.secret
+	           FROM claimed d JOIN webhook_endpoints w ON w.id = d.endpoint_id ORDER BY d.id`
+	rows, err := r.pool.Query(ctx, q, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []WebhookDelivery
+	for rows.Next() {
+		var d WebhookDelivery
+		if err := rows.Scan(append(d.scanDest(), &d.URL, &d.Secret)...); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// Record logs attempt a at the claimed delivery d and settles it: delivered when a has
+// no error, otherwise retried at retryAt, or dead without retryAt.
+func (r *WebhookRepo) Record(ctx context.Context, d *WebhookDelivery, a *WebhookAttempt, retryAt *time.Time) error {
+	const q = `WITH logged AS (
+	               INSERT INTO webhook_attempts (delivery_id, user_id, attempt, status_code, error, response_body, duration_ms)
+	               VALUES ($1,$2,$3,$4,$5,$6,$7))
+	           UPDATE webhook_deliveries
+	           SET status = CASE WHEN $5::text IS NULL THEN 'delivered' WHEN $8::timestamptz IS NULL THEN 'dead' ELSE 'pending' END,
+	               delivered_at = CASE WHEN $5::text IS NULL THEN NOW() END,
+	               next_attempt_at = COALESCE($8, next_attempt_at),
+	               last_error=$5, locked_at=NULL
+	           WHERE id=$1`
+	_, err := r.pool.Exec(ctx, q, d.ID, d.UserID, d.Attempts, a.StatusCode, a.Error, a.ResponseBody, a.DurationMS, retryAt)
+	return err
+}
+
+// ListDeliveries returns the delivery log of the user's endpoint, newest first, each
+// with its attempts; status filters by status when not empty.
+func (r *WebhookRepo) ListDeliveries(ctx context.Context, userID, endpointID int64, status string, limit, offset int) ([]WebhookDelivery, error) {
+	return r.deliveries(ctx, `SELECT `+webhookDeliveryCols+` FROM webhook_deliveries d
+	                          WHERE d.user_id=$1 AND d.endpoint_id=$2 AND ($3 = '' OR d.status=$3)
+	                          ORDER BY d.id DESC LIMIT $4 OFFSET $5`, userID, endpointID, status, limit, offset)
+}
+
+// ListDead returns the user's dead letters across endpoints, newest first, each with
+// its attempts.
+func (r *WebhookRepo) ListDead(ctx context.Context, userID int64, limit, offset int) ([]WebhookDelivery, error) {
+	return r.deliveries(ctx, `SELECT `+webhookDeliveryCols+` FROM webhook_deliveries d
+	                          WHERE d.user_id=$1 AND d.status = 'dead'
+	                          ORDER BY d.id DESC LIMIT $2 OFFSET $3`, userID, limit, offset)
+}
+
+func (r *WebhookRepo) deliveries(ctx context.Context, q string, args ...any) ([]WebhookDelivery, error) {
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []WebhookDelivery{}
+	ids := []int64{}
+	for rows.Next() {
+		var d WebhookDelivery
+		if err := rows.Scan(d.scanDest()...); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+		ids = append(ids, d.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if len(out) == 0 {
+		return out, nil
+	}
+
+	rows, err = r.pool.Query(ctx, `SELECT delivery_id, attempt, status_code, error, response_body, duration_ms, created_at
+	                               FROM webhook_attempts WHERE delivery_id = ANY($1) ORDER BY id`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	logs := map[int64][]WebhookAttempt{}
+	for rows.Next() {
+		var id int64
+		var a WebhookAttempt
+		if err := rows.Scan(&id, &a.Attempt, &a.StatusCode, &a.Error, &a.ResponseBody, &a.DurationMS, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		logs[id] = append(logs[id], a)
+	}
+	for i := range out {
+		out[i].Log = logs[out[i].ID]
+	}
+	return out, rows.Err()
+}
+
+// Replay queues a dead or delivered delivery to one of the user's endpoints to be sent
+// again right away. Attempts keep counting, so a replayed dead letter that fails again
+// is dead again. Returns false when there is no such delivery or it is still pending.
+func (r *WebhookRepo) Replay(ctx context.Context, userID, endpointID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `UPDATE webhook_deliveries SET status='pending', next_attempt_at=NOW(), delivered_at=NULL
+	                             WHERE user_id=$1 AND endpoint_id=$2 AND id=$3 AND status <> 'pending'`, userID, endpointID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// Cleanup deletes delivered and dead deliveries, with their attempts, created before
+// cutoff.
+func (r *WebhookRepo) Cleanup(ctx context.Context, cutoff time.Time) (int64, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM webhook_deliveries WHERE status <> 'pending' AND created_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return ct.RowsAffected(), nil
+}
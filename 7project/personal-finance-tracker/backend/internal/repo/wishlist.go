@@ -0,0 +1,218 @@
+// backend/internal/repo/wishlist.go
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// WishlistItem is the repository-layer DTO mirroring the wishlist_items table.
+// - Priority: 1 (highest) .. 5 (lowest)
+// - TransactionID: set once the item has been purchased
+type WishlistItem struct {
+	ID             int64     `json:"id"`
+	UserID         int64     `json:"user_id"`
+	CategoryID     *int64    `json:"category_id"`
+	Name           string    `json:"name"`
+	EstimatedPrice float64   `json:"estimated_price"`
+	Priority       int       `json:"priority"`
+	Note           string    `json:"note"`
+	TransactionID  *int64    `json:"transaction_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Affordability evaluates a planned purchase against this month's budget and projection.
+// - BudgetRemaining: headroom of the item's category budget (or the overall budget); nil when none exists
+// - FitsBudget: price fits the remaining budget (true when no budget applies)
+// - ProjectedNetAfter: projected end-of-month net if the item were bought now
+// - Affordable: FitsBudget and ProjectedNetAfter is not negative
+type Affordability struct {
+	ItemID            int64    `json:"item_id"`
+	Price             float64  `json:"price"`
+	Month             string   `json:"month"`
+	BudgetRemaining   *float64 `json:"budget_remaining"`
+	FitsBudget        bool     `json:"fits_budget"`
+	ProjectedNet      float64  `json:"projected_net"`
+	ProjectedNetAfter float64  `json:"projected_net_after"`
+	Affordable        bool     `json:"affordable"`
+}
+
+// WishlistRepo provides CRUD operations for wishlist items.
+type WishlistRepo struct {
+	pool  dbConn
+	crypt *fieldCrypt
+}
+
+// WishlistRepo accessor bound to the Store's pool.
+func (s *Store) WishlistRepo() *WishlistRepo { return &WishlistRepo{pool: s.db(), crypt: s.crypt} }
+
+const wishlistCols = `id, user_id, category_id, name, estimated_price, priority, note, transaction_id, created_at, updated_at`
+
+func scanWishlistItem(row pgx.Row) (*WishlistItem, error) {
+	var w WishlistItem
+	if err := row.Scan(&w.ID, &w.UserID, &w.CategoryID, &w.Name, &w.EstimatedPrice, &w.Priority, &w.Note, &w.TransactionID, &w.CreatedAt, &w.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// List returns the user's wishlist; open items first, then by priority.
+func (r *WishlistRepo) List(ctx context.Context, userID int64) ([]WishlistItem, error) {
+	const q = `SELECT ` + wishlistCols + ` FROM wishlist_items WHERE user_id=$1
+	           ORDER BY transaction_id IS NOT NULL, priority, id`
+	rows, err := r.pool.Query(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []WishlistItem{}
+	for rows.Next() {
+		w, err := scanWishlistItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *w)
+	}
+	return out, rows.Err()
+}
+
+// Get fetches an item scoped to the user. Returns (nil, nil) when not found.
+func (r *WishlistRepo) Get(ctx context.Context, userID, id int64) (*WishlistItem, error) {
+	w, err := scanWishlistItem(r.pool.QueryRow(ctx, `SELECT `+wishlistCols+` FROM wishlist_items WHERE user_id=$1 AND id=$2`, userID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return w, err
+}
+
+// Create inserts an item and returns the stored row.
+func (r *WishlistRepo) Create(ctx context.Context, w *WishlistItem) (*WishlistItem, error) {
+	const q = `INSERT INTO wishlist_items (user_id, category_id, name, estimated_price, priority, note)
+	           VALUES ($1,$2,$3,$4,$5,$6)
+	           RETURNING ` + wishlistCols
+	return scanWishlistItem(r.pool.QueryRow(ctx, q, w.UserID, w.CategoryID, w.Name, w.EstimatedPrice, w.Priority, w.Note))
+}
+
+// Update modifies an item owned by the user. Returns (nil, nil) when not found.
+func (r *WishlistRepo) Update(ctx context.Context, userID, id int64, w *WishlistItem) (*WishlistItem, error) {
+	const q = `UPDATE wishlist_items
+	           SET category_id=$3, name=$4, estimated_price=$5, priority=$6, note=$7
+	           WHERE user_id=$1 AND id=$2
+	           RETURNING ` + wishlistCols
+	out, err := scanWishlistItem(r.pool.QueryRow(ctx, q, userID, id, w.CategoryID, w.Name, w.EstimatedPrice, w.Priority, w.Note))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return out, err
+}
+
+// Delete removes an item scoped to the user.
+func (r *WishlistRepo) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	ct, err := r.pool.Exec(ctx, `DELETE FROM wishlist_items WHERE user_id=$1 AND id=$2`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// Purchase records the item as bought: it inserts an expense transaction and links it
+// to the item within one database transaction. Returns (nil, nil) if the item does not
+// exist or was already purchased, ErrPeriodClosed if t is dated in a closed month, and
+// a *LimitError if it exceeds its category's "block" limit without t.OverrideLimit.
+func (r *WishlistRepo) Purchase(ctx context.Context, userID, id int64, t *Transaction) (*WishlistItem, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := ensureOpen(ctx, tx, userID, t.Date); err != nil {
+		return nil, err
+	}
+	if err := ensurePartition(ctx, tx, t.Date); err != nil {
+		return nil, err
+	}
+	expense := *t
+	expense.Type = "expense"
+	if _, err := checkLimit(ctx, tx, userID, &expense); err != nil {
+		return nil, err
+	}
+
+	desc, index, err := r.crypt.seal(ctx, userID, t.Description)
+	if err != nil {
+		return nil, err
+	}
+	var txnID int64
+	err = tx.QueryRow(ctx, `INSERT INTO transactions (user_id, category_id, amount, type, date, description, description_hash)
+	                        SELECT $1, $3, $4, 'expense', $5, $6, $7
+	                        FROM wishlist_items WHERE user_id=$1 AND id=$2 AND transaction_id IS NULL
+	                        RETURNING id`,
+		userID, id, t.CategoryID, t.Amount, t.Date, desc, index).Scan(&txnID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, periodErr(err)
+	}
+	out, err := scanWishlistItem(tx.QueryRow(ctx, `UPDATE wishlist_items SET transaction_id=$3
+	                                              WHERE user_id=$1 AND id=$2
+	                                              RETURNING `+wishlistCols, userID, id, txnID))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Affordability checks item w against the budget of its category (falling back to its
+// group's, then the overall budget) for month and against the end-of-month projection.
+func (r *WishlistRepo) Affordability(ctx context.Context, userID int64, w *WishlistItem, month string, asOf time.Time) (*Affordability, error) {
+	first, _, err := MonthBounds(month)
+	if err != nil {
+		return nil, err
+	}
+	a := &Affordability{ItemID: w.ID, Price: w.EstimatedPrice, Month: month, FitsBudget: true}
+
+	// Prefer the category budget, then its group's; fall back to the overall budget.
+	const q = `
+SELECT b.limit_amount - COALESCE((
+           SELECT SUM(mt.total) FROM monthly_totals mt
+           WHERE mt.user_id = b.user_id AND mt.type='expense' AND mt.month = $3
+             AND ` + budgetCovers + `
+       ), 0)
+FROM budgets b
+WHERE b.user_id=$1 AND b.period_month=$2 AND b.deleted_at IS NULL
+  AND (b.category_id = $4 OR b.group_id = (SELECT group_id FROM categories WHERE id = $4)
+       OR (b.category_id IS NULL AND b.group_id IS NULL))
+ORDER BY b.category_id NULLS LAST, b.group_id NULLS LAST
+LIMIT 1
+`
+	var remaining float64
+	err = r.pool.QueryRow(ctx, q, userID, month, first, w.CategoryID).Scan(&remaining)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return nil, err
+	default:
+		remaining = round2(remaining)
+		a.BudgetRemaining = &remaining
+		a.FitsBudget = w.EstimatedPrice <= remaining
+	}
+
+	p, err := (&DashboardRepo{pool: r.pool, read: r.pool}).Projection(ctx, userID, month, asOf)
+	if err != nil {
+		return nil, err
+	}
+	a.ProjectedNet = p.ProjectedNet
+	a.ProjectedNetAfter = round2(p.ProjectedNet - w.EstimatedPrice)
+	a.Affordable = a.FitsBudget && a.ProjectedNetAfter >= 0
+	return a, nil
+}
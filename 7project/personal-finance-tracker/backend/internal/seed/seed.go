@@ -0,0 +1,376 @@
+// backend/internal/seed/seed.go
+
+// Package seed fills a user account with realistic demo data: a typical set of
+// categories, monthly budgets and a year (by default) of income and expenses.
+// Generation is deterministic for a given Options.Seed so demos are reproducible.
+// For load testing, Scale, Mix and Seasonal shape the volume, and Load creates a
+// whole population of such users.
+package seed
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"pft/internal/repo"
+)
+
+// Options controls the generated dataset.
+// - Months: number of calendar months to cover, ending with Now's month (default 12)
+// - Seed: random seed; the same seed yields the same data
+// - Now: reference date; transactions after it are not generated (default time.Now)
+// - Scale: multiplies day-to-day expenses (groceries, transport, dining, ...); salary, rent and bills stay monthly (default 1)
+// - Mix: further multiplier per category name, e.g. {"Dining Out": 2}; see ParseMix
+// - Seasonal: vary spending by month: holiday shopping, winter heating, summer outings
+type Options struct {
+	Months   int
+	Seed     int64
+	Now      time.Time
+	Scale    float64
+	Mix      map[string]float64
+	Seasonal bool
+}
+
+// Result counts the rows created by Apply. Existing categories and budgets are reused, not counted.
+type Result struct {
+	Categories   int `json:"categories"`
+	Budgets      int `json:"budgets"`
+	Transactions int `json:"transactions"`
+}
+
+// Category is a generated category; Budget is its monthly limit (0 for none).
+type Category struct {
+	Name   string
+	Type   string // "income" | "expense"
+	Budget float64
+}
+
+// Transaction is a generated transaction referring to Categories by index.
+type Transaction struct {
+	Category    int
+	Amount      float64
+	Date        time.Time
+	Description string
+}
+
+// Dataset is the full plan Apply writes to the database.
+type Dataset struct {
+	Categories   []Category
+	Months       []string // YYYY-MM, oldest first
+	Transactions []Transaction
+}
+
+// Indexes into categories, kept in sync with its order.
+const (
+	catSalary = iota
+	catFreelance
+	catRent
+	catGroceries
+	catUtilities
+	catTransport
+	catDining
+	catEntertainment
+	catHealth
+	catShopping
+	catSubscriptions
+)
+
+var categories = []Category{
+	{Name: "Salary", Type: "income"},
+	{Name: "Freelance", Type: "income"},
+	{Name: "Rent", Type: "expense"},
+	{Name: "Groceries", Type: "expense", Budget: 450},
+	{Name: "Utilities", Type: "expense", Budget: 150},
+	{Name: "Transport", Type: "expense", Budget: 120},
+	{Name: "Dining Out", Type: "expense", Budget: 200},
+	{Name: "Entertainment", Type: "expense", Budget: 100},
+	{Name: "Health", Type: "expense"},
+	{Name: "Shopping", Type: "expense", Budget: 250},
+	{Name: "Subscriptions", Type: "expense", Budget: 40},
+}
+
+// seasonality multiplies a category's activity by calendar month, January first.
+// Utilities scale their amounts (heating), the others how often they occur.
+var seasonality = map[int][12]float64{
+	catGroceries:     {1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1.1, 1.3},
+	catUtilities:     {1.5, 1.4, 1.2, 1, 0.8, 0.7, 0.7, 0.7, 0.8, 1, 1.2, 1.4},
+	catTransport:     {1.1, 1.1, 1, 1, 1, 0.9, 0.7, 0.7, 1.1, 1.1, 1.1, 1},
+	catDining:        {0.8, 0.8, 0.9, 1, 1.1, 1.2, 1.3, 1.3, 1, 1, 1, 1.4},
+	catEntertainment: {0.7, 0.8, 0.9, 1, 1.1, 1.3, 1.4, 1.4, 1, 0.9, 0.9, 1.5},
+	catShopping:      {1.3, 0.7, 0.8, 0.9, 0.9, 0.9, 1.1, 0.9, 0.9, 1, 1.5, 2.4},
+}
+
+// ParseMix reads per-category multipliers written as "Dining Out=2,Shopping=0.5".
+// Names must be generated categories and factors between 0 and 100.
+func ParseMix(s string) (map[string]float64, error) {
+	mix := map[string]float64{}
+	if strings.TrimSpace(s) == "" {
+		return mix, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		name, val, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if !ok || err != nil || f < 0 || f > 100 {
+			return nil, fmt.Errorf("mix %q: want <category>=<factor between 0 and 100>", part)
+		}
+		if !slices.ContainsFunc(categories, func(c Category) bool { return c.Name == name }) {
+			return nil, fmt.Errorf("mix: unknown category %q", name)
+		}
+		mix[name] = f
+	}
+	return mix, nil
+}
+
+var (
+	groceryStores = []string{"Supermarket", "Farmers market", "Corner shop", "Discount grocer"}
+	restaurants   = []string{"Pizza place", "Sushi bar", "Cafe", "Burger joint", "Thai restaurant"}
+	outings       = []string{"Cinema", "Concert tickets", "Museum", "Bowling", "Streaming rental"}
+	purchases     = []string{"Clothes", "Books", "Electronics", "Home goods", "Gift"}
+)
+
+// Plan generates the dataset without touching the database.
+func Plan(opt Options) Dataset {
+	if opt.Months <= 0 {
+		opt.Months = 12
+	}
+	if opt.Now.IsZero() {
+		opt.Now = time.Now().UTC()
+	}
+	rng := rand.New(rand.NewSource(opt.Seed))
+	today := time.Date(opt.Now.Year(), opt.Now.Month(), opt.Now.Day(), 0, 0, 0, 0, time.UTC)
+	start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(opt.Months - 1), 0)
+
+	ds := Dataset{Categories: categories}
+	add := func(cat int, amount float64, date time.Time, desc string) {
+		if date.After(today) {
+			return
+		}
+		ds.Transactions = append(ds.Transactions, Transaction{
+			Category: cat, Amount: math.Round(amount*100) / 100, Date: date, Description: desc,
+		})
+	}
+	between := func(lo, hi float64) float64 { return lo + rng.Float64()*(hi-lo) }
+	pick := func(xs []string) string { return xs[rng.Intn(len(xs))] }
+	if opt.Scale <= 0 {
+		opt.Scale = 1
+	}
+
+	for m := 0; m < opt.Months; m++ {
+		first := start.AddDate(0, m, 0)
+		days := first.AddDate(0, 1, -1).Day()
+		day := func(d int) time.Time { return first.AddDate(0, 0, min(d, days)-1) }
+		anyDay := func() time.Time { return day(1 + rng.Intn(days)) }
+		ds.Months = append(ds.Months, first.Format("2006-01"))
+		season := func(cat int) float64 {
+			if f, ok := seasonality[cat]; ok && opt.Seasonal {
+				return f[first.Month()-1]
+			}
+			return 1
+		}
+		// times scales the usual count n of cat's transactions this month, rounding
+		// at random so the expected count is exact. Unscaled, it draws nothing, so
+		// plain demo data stays the same for a seed.
+		times := func(cat, n int) int {
+			f := float64(n) * opt.Scale * season(cat)
+			if mf, ok := opt.Mix[categories[cat].Name]; ok {
+				f *= mf
+			}
+			if f == float64(n) {
+				return n
+			}
+			whole := math.Floor(f)
+			if rng.Float64() < f-whole {
+				whole++
+			}
+			return int(whole)
+		}
+
+		add(catSalary, 3200+between(-50, 150), day(25), "Monthly salary")
+		for i := rng.Intn(3); i > 0; i-- {
+			add(catFreelance, between(150, 900), anyDay(), "Freelance project")
+		}
+
+		add(catRent, 1100, day(1), "Rent")
+		add(catUtilities, between(70, 140)*season(catUtilities), day(5), "Electricity & water")
+		add(catUtilities, 39.99, day(8), "Internet")
+		add(catSubscriptions, 15.99, day(12), "Netflix")
+		add(catSubscriptions, 10.99, day(18), "Spotify")
+
+		for d := 2 + rng.Intn(5); d <= days; d += 6 + rng.Intn(3) {
+			for i := times(catGroceries, 1); i > 0; i-- {
+				add(catGroceries, between(35, 120), day(d), pick(groceryStores))
+			}
+		}
+		for i := times(catTransport, 5+rng.Intn(6)); i > 0; i-- {
+			add(catTransport, between(3, 30), anyDay(), "Public transport")
+		}
+		for i := times(catDining, 3+rng.Intn(6)); i > 0; i-- {
+			add(catDining, between(12, 65), anyDay(), pick(restaurants))
+		}
+		for i := times(catEntertainment, 1+rng.Intn(4)); i > 0; i-- {
+			add(catEntertainment, between(10, 60), anyDay(), pick(outings))
+		}
+		for i := times(catHealth, rng.Intn(3)); i > 0; i-- {
+			add(catHealth, between(15, 120), anyDay(), "Pharmacy")
+		}
+		for i := times(catShopping, 1+rng.Intn(3)); i > 0; i-- {
+			add(catShopping, between(15, 220), anyDay(), pick(purchases))
+		}
+	}
+	return ds
+}
+
+// Apply writes the dataset for userID. Categories are matched by name so re-seeding an
+// account reuses them, and budgets that already exist for a month are left untouched.
+// Transactions are always added.
+func Apply(ctx context.Context, store *repo.Store, userID int64, ds Dataset) (*Result, error) {
+	res := &Result{}
+	// Existing rows are matched against our own writes, so bypass any read replica.
+	store = repo.New(store.Pool)
+
+	existing, err := store.CategoryRepo().List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]int64{}
+	for _, c := range existing {
+		byName[c.Name] = c.ID
+	}
+	ids := make([]int64, len(ds.Categories))
+	for i, c := range ds.Categories {
+		if id, ok := byName[c.Name]; ok {
+			ids[i] = id
+			continue
+		}
+		out, err := store.CategoryRepo().Create(ctx, &repo.Category{UserID: userID, Name: c.Name, Type: c.Type})
+		if err != nil {
+			return nil, fmt.Errorf("create category %q: %w", c.Name, err)
+		}
+		ids[i] = out.ID
+		res.Categories++
+	}
+
+	for _, month := range ds.Months {
+		budgets, err := store.BudgetRepo().ListByMonth(ctx, userID, month)
+		if err != nil {
+			return nil, err
+		}
+		have := map[int64]bool{}
+		for _, b := range budgets {
+			if b.CategoryID != nil {
+				have[*b.CategoryID] = true
+			}
+		}
+		for i, c := range ds.Categories {
+			if c.Budget <= 0 || have[ids[i]] {
+				continue
+			}
+			if _, err := store.BudgetRepo().Create(ctx, &repo.Budget{
+				UserID: userID, CategoryID: &ids[i], PeriodMonth: month, LimitAmount: c.Budget,
+			}); err != nil {
+				return nil, fmt.Errorf("create budget %s %q: %w", month, c.Name, err)
+			}
+			res.Budgets++
+		}
+	}
+
+	for _, t := range ds.Transactions {
+		if _, err := store.TransactionRepo().Create(ctx, &repo.Transaction{
+			UserID:      userID,
+			CategoryID:  &ids[t.Category],
+			Amount:      t.Amount,
+			Type:        ds.Categories[t.Category].Type,
+			Date:        t.Date,
+			Description: t.Description,
+		}); err != nil {
+			return nil, fmt.Errorf("create transaction on %s: %w", t.Date.Format("2006-01-02"), err)
+		}
+		res.Transactions++
+	}
+	return res, nil
+}
+
+// WriteCSV writes the dataset's transactions as a CSV file the importer reads
+// (date, amount, type, category, description), e.g. to benchmark imports.
+func WriteCSV(w io.Writer, ds Dataset) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"date", "amount", "type", "category", "description"})
+	for _, t := range ds.Transactions {
+		c := ds.Categories[t.Category]
+		_ = cw.Write([]string{t.Date.Format("2006-01-02"), strconv.FormatFloat(t.Amount, 'f', 2, 64), c.Type, c.Name, t.Description})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// LoadOptions sizes a population of synthetic users for load testing.
+// - Users: accounts "<Prefix><n>@load.test" for n = 1..Users, created when missing
+// - Prefix: local part of the emails before the number (default "load")
+// - PasswordHash: password hash of the accounts created
+// - Dataset: plan of each user; user n uses Dataset.Seed+n, so no two are alike
+type LoadOptions struct {
+	Users        int
+	Prefix       string
+	PasswordHash string
+	Dataset      Options
+}
+
+// LoadResult totals what Load created.
+type LoadResult struct {
+	Users int `json:"users"`
+	Result
+}
+
+// LoadEmail is the email of load user n.
+func LoadEmail(prefix string, n int) string {
+	if prefix == "" {
+		prefix = "load"
+	}
+	return prefix + strconv.Itoa(n) + "@load.test"
+}
+
+// Load seeds opt.Users users, each in one transaction of its own so an interrupted
+// run leaves whole users behind; running it again adds to them. progress, if not
+// nil, is called after each user.
+func Load(ctx context.Context, store *repo.Store, opt LoadOptions, progress func(n int, res *Result)) (*LoadResult, error) {
+	total := &LoadResult{}
+	for n := 1; n <= opt.Users; n++ {
+		dsOpt := opt.Dataset
+		dsOpt.Seed += int64(n)
+		ds := Plan(dsOpt)
+		var res *Result
+		err := store.WithContextTx(ctx, func(ctx context.Context) error {
+			email := LoadEmail(opt.Prefix, n)
+			u, err := store.UserRepo().GetByEmail(ctx, email)
+			if err != nil {
+				return err
+			}
+			if u == nil {
+				if u, err = store.UserRepo().Create(ctx, "Load User "+strconv.Itoa(n), email, opt.PasswordHash); err != nil {
+					return err
+				}
+				total.Users++
+			}
+			res, err = Apply(ctx, store, u.ID, ds)
+			return err
+		})
+		if err != nil {
+			return total, fmt.Errorf("user %d: %w", n, err)
+		}
+		total.Categories += res.Categories
+		total.Budgets += res.Budgets
+		total.Transactions += res.Transactions
+		if progress != nil {
+			progress(n, res)
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,115 @@
+// backend/internal/seed/seed_test.go
+//
+// Purpose:
+//   Verify the demo data plan is deterministic, covers the requested months and
+//   never generates transactions after the reference date, and that load options
+//   scale volumes, shift the category mix and follow the seasons.
+
+package seed
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlan(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+	opt := Options{Months: 12, Seed: 7, Now: now}
+	ds := Plan(opt)
+
+	if !reflect.DeepEqual(ds, Plan(opt)) {
+		t.Fatalf("expected the same seed to produce the same dataset")
+	}
+	if len(ds.Months) != 12 || ds.Months[0] != "2024-04" || ds.Months[11] != "2025-03" {
+		t.Fatalf("unexpected months: %v", ds.Months)
+	}
+
+	salaries := 0
+	for _, tx := range ds.Transactions {
+		if tx.Date.After(now) || tx.Date.Before(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("transaction outside range: %v", tx.Date)
+		}
+		if tx.Amount <= 0 {
+			t.Fatalf("non-positive amount: %+v", tx)
+		}
+		if tx.Category == catSalary {
+			salaries++
+		}
+	}
+	// March's salary (25th) lies after the reference date.
+	if salaries != 11 {
+		t.Fatalf("expected 11 salary payments, got %d", salaries)
+	}
+	if len(ds.Transactions) < 12*20 {
+		t.Fatalf("expected a realistic volume of transactions, got %d", len(ds.Transactions))
+	}
+}
+
+// count returns the number of transactions per category of ds.
+func count(ds Dataset) map[int]int {
+	n := map[int]int{}
+	for _, tx := range ds.Transactions {
+		n[tx.Category]++
+	}
+	return n
+}
+
+func TestPlanLoadOptions(t *testing.T) {
+	now := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	base := count(Plan(Options{Months: 24, Seed: 3, Now: now}))
+
+	scaled := count(Plan(Options{Months: 24, Seed: 3, Now: now, Scale: 10}))
+	if scaled[catSalary] != base[catSalary] || scaled[catRent] != base[catRent] {
+		t.Errorf("monthly items should not scale: %v vs %v", scaled, base)
+	}
+	if r := float64(scaled[catDining]) / float64(base[catDining]); r < 8 || r > 12 {
+		t.Errorf("Scale 10 multiplied dining by %.1f", r)
+	}
+
+	mixed := count(Plan(Options{Months: 24, Seed: 3, Now: now, Mix: map[string]float64{"Shopping": 0, "Dining Out": 3}}))
+	if mixed[catShopping] != 0 || mixed[catDining] < 2*base[catDining] || mixed[catTransport] == 0 {
+		t.Errorf("mix not applied: %v vs %v", mixed, base)
+	}
+
+	// Holiday shopping: December outnumbers February across a large volume.
+	ds := Plan(Options{Months: 24, Seed: 3, Now: now, Scale: 20, Seasonal: true})
+	byMonth := map[time.Month]int{}
+	for _, tx := range ds.Transactions {
+		if tx.Category == catShopping {
+			byMonth[tx.Date.Month()]++
+		}
+	}
+	if byMonth[time.December] < 2*byMonth[time.February] {
+		t.Errorf("seasonal shopping: December %d, February %d", byMonth[time.December], byMonth[time.February])
+	}
+}
+
+func TestParseMix(t *testing.T) {
+	mix, err := ParseMix(" Dining Out = 2 ,Shopping=0.5")
+	if err != nil || mix["Dining Out"] != 2 || mix["Shopping"] != 0.5 {
+		t.Fatalf("ParseMix = %v, %v", mix, err)
+	}
+	for _, bad := range []string{"Dining Out", "Pets=2", "Shopping=-1", "Shopping=x"} {
+		if _, err := ParseMix(bad); err == nil {
+			t.Errorf("ParseMix(%q) should fail", bad)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	ds := Plan(Options{Months: 2, Seed: 1, Now: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)})
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, ds); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(rows) != len(ds.Transactions)+1 {
+		t.Fatalf("got %d rows, %v; want %d", len(rows), err, len(ds.Transactions)+1)
+	}
+	if rows[0][0] != "date" || rows[1][3] == "" {
+		t.Errorf("unexpected rows: %v %v", rows[0], rows[1])
+	}
+}
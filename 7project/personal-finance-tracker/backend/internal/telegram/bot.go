@@ -0,0 +1,257 @@
+// backend/internal/telegram/bot.go
+
+// Package telegram is the Telegram bot: users link a chat to their account with a
+// one-time code from the app, record expenses by sending messages such as
+// "coffee 3.50", and receive alerts in the chat (see Channel).
+package telegram
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pft/internal/i18n"
+	"pft/internal/repo"
+)
+
+// Sender sends chat messages; implemented by Client.
+type Sender interface {
+	SendMessage(ctx context.Context, chatID int64, text string) error
+}
+
+// Links maps chats to users; implemented by repo.TelegramRepo.
+type Links interface {
+	Link(ctx context.Context, code string, chatID int64, username string) (int64, error)
+	UserByChat(ctx context.Context, chatID int64) (int64, error)
+	ChatOf(ctx context.Context, userID int64) (int64, error)
+	UnlinkChat(ctx context.Context, chatID int64) (bool, error)
+}
+
+// Categories lists a user's categories; implemented by repo.CategoryRepo.
+type Categories interface {
+	List(ctx context.Context, userID int64) ([]repo.Category, error)
+}
+
+// Transactions records expenses; implemented by repo.TransactionRepo.
+type Transactions interface {
+	Create(ctx context.Context, t *repo.Transaction) (*repo.Transaction, error)
+	RecentCategory(ctx context.Context, userID int64, typ, description string) (*int64, error)
+}
+
+// Bot answers messages sent to the bot.
+// - Locale: the user's language once the chat is linked; the Telegram app's language before
+// - OnWrite: optional; called with the user after an expense is recorded (e.g. to drop cached responses)
+type Bot struct {
+	Sender       Sender
+	Links        Links
+	Categories   Categories
+	Transactions Transactions
+	Locale       func(ctx context.Context, userID int64) string
+	OnWrite      func(ctx context.Context, userID int64)
+}
+
+// HandleUpdate answers one update. Only text messages in private chats are handled;
+// the error, if any, was already answered with an apology in the chat.
+func (b *Bot) HandleUpdate(ctx context.Context, u *Update) error {
+	m := u.Message
+	if m == nil || m.Chat.Type != "private" || strings.TrimSpace(m.Text) == "" {
+		return nil
+	}
+	lang := i18n.Default
+	username := ""
+	if m.From != nil {
+		username = m.From.Username
+		if l := strings.ToLower(m.From.LanguageCode); i18n.Supports(l) {
+			lang = l
+		} else if l, _, _ := strings.Cut(l, "-"); i18n.Supports(l) {
+			lang = l
+		}
+	}
+	reply, err := b.answer(ctx, m, username, &lang)
+	if err != nil {
+		reply = i18n.T(lang, "telegram.error")
+	}
+	if serr := b.Sender.SendMessage(ctx, m.Chat.ID, reply); serr != nil {
+		return errors.Join(err, serr)
+	}
+	return err
+}
+
+// answer performs the message's command or records its expense and returns the reply.
+// It switches *lang to the user's language once the user is known.
+func (b *Bot) answer(ctx context.Context, m *Message, username string, lang *string) (string, error) {
+	text := strings.TrimSpace(m.Text)
+	cmd, arg := "", ""
+	if strings.HasPrefix(text, "/") {
+		cmd, arg, _ = strings.Cut(text, " ")
+		cmd, _, _ = strings.Cut(cmd, "@") // "/help@pft_bot" in clients that add the bot's name
+		arg = strings.TrimSpace(arg)
+	}
+
+	if cmd == "/start" && arg != "" {
+		userID, err := b.Links.Link(ctx, arg, m.Chat.ID, username)
+		if err != nil || userID == 0 {
+			return i18n.T(*lang, "telegram.link.invalid"), err
+		}
+		*lang = b.locale(ctx, userID, *lang)
+		return i18n.T(*lang, "telegram.link.done"), nil
+	}
+	userID, err := b.Links.UserByChat(ctx, m.Chat.ID)
+	if err != nil {
+		return "", err
+	}
+	if userID == 0 {
+		return i18n.T(*lang, "telegram.unlinked"), nil
+	}
+	*lang = b.locale(ctx, userID, *lang)
+
+	switch cmd {
+	case "":
+	case "/unlink", "/stop":
+		if _, err := b.Links.UnlinkChat(ctx, m.Chat.ID); err != nil {
+			return "", err
+		}
+		return i18n.T(*lang, "telegram.unlink.done"), nil
+	default:
+		return i18n.T(*lang, "telegram.help"), nil
+	}
+
+	e, ok := ParseEntry(text)
+	if !ok {
+		return i18n.T(*lang, "telegram.expense.invalid"), nil
+	}
+	// Writes are the user's own, scoped and audited as if made through the API.
+	ctx = repo.WithActor(repo.WithTenant(ctx, userID), "user:"+strconv.FormatInt(userID, 10), "telegram:"+strconv.FormatInt(m.MessageID, 10))
+	cat, reply, err := b.category(ctx, userID, e, *lang)
+	if reply != "" || err != nil {
+		return reply, err
+	}
+	date := time.Unix(m.Date, 0).UTC()
+	if m.Date == 0 {
+		date = time.Now().UTC()
+	}
+	t := &repo.Transaction{UserID: userID, Amount: e.Amount, Type: "expense", Date: date.Truncate(24 * time.Hour), Description: e.Description}
+	if cat != nil {
+		t.CategoryID = &cat.ID
+	}
+	if _, err := b.Transactions.Create(ctx, t); err != nil {
+		if errors.Is(err, repo.ErrPeriodClosed) {
+			return i18n.T(*lang, "telegram.expense.closed", "date", date.Format("2006-01-02")), nil
+		}
+		var limit *repo.LimitError
+		if errors.As(err, &limit) {
+			return i18n.T(*lang, "telegram.expense.limit", "amount", strconv.FormatFloat(e.Amount, 'f', 2, 64),
+				"limit", strconv.FormatFloat(limit.Limit, 'f', 2, 64), "category", limit.Category), nil
+		}
+		return "", err
+	}
+	if b.OnWrite != nil {
+		b.OnWrite(ctx, userID)
+	}
+	desc := e.Description
+	if desc == "" {
+		desc = "—"
+	}
+	args := []string{"amount", strconv.FormatFloat(e.Amount, 'f', 2, 64), "description", desc, "date", date.Format("2006-01-02")}
+	if cat == nil {
+		return i18n.T(*lang, "telegram.expense.uncategorized", args...), nil
+	}
+	return i18n.T(*lang, "telegram.expense.added", append(args, "category", cat.Name)...), nil
+}
+
+// category picks the expense category for e: the one named by its #tag, else one
+// whose name appears in the description, else the one the user last filed the same
+// description under. A #tag naming no category yields a reply instead.
+func (b *Bot) category(ctx context.Context, userID int64, e Entry, lang string) (*repo.Category, string, error) {
+	all, err := b.Categories.List(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	var cats []repo.Category
+	for _, c := range all {
+		if c.Type == "expense" {
+			cats = append(cats, c)
+		}
+	}
+	if e.Category != "" {
+		names := make([]string, 0, len(cats))
+		for i, c := range cats {
+			if strings.EqualFold(c.Name, e.Category) {
+				return &cats[i], "", nil
+			}
+			names = append(names, c.Name)
+		}
+		return nil, i18n.T(lang, "telegram.category.unknown", "name", e.Category, "categories", strings.Join(names, ", ")), nil
+	}
+
+	// Longest names first, so "Public transport" wins over "Transport".
+	sort.SliceStable(cats, func(i, j int) bool { return len(cats[i].Name) > len(cats[j].Name) })
+	desc := " " + strings.ToLower(e.Description) + " "
+	for i, c := range cats {
+		if strings.Contains(desc, " "+strings.ToLower(c.Name)+" ") {
+			return &cats[i], "", nil
+		}
+	}
+	if e.Description == "" {
+		return nil, "", nil
+	}
+	id, err := b.Transactions.RecentCategory(ctx, userID, "expense", e.Description)
+	if err != nil || id == nil {
+		return nil, "", err
+	}
+	for i, c := range cats {
+		if c.ID == *id {
+			return &cats[i], "", nil
+		}
+	}
+	return nil, "", nil
+}
+
+func (b *Bot) locale(ctx context.Context, userID int64, fallback string) string {
+	if b.Locale == nil {
+		return fallback
+	}
+	if l := b.Locale(ctx, userID); i18n.Supports(l) {
+		return l
+	}
+	return fallback
+}
+
+// Poll fetches updates with long polling and handles them until ctx is done. It
+// removes any webhook first, since Telegram refuses polling while one is set; only
+// one instance may poll a bot.
+func (b *Bot) Poll(ctx context.Context, c *Client) {
+	if err := c.DeleteWebhook(ctx); err != nil {
+		slog.Warn("telegram: removing webhook failed", "error", err.Error())
+	}
+	var offset int64
+	for ctx.Err() == nil {
+		updates, err := c.GetUpdates(ctx, offset, 50*time.Second)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			wait := 5 * time.Second
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+				wait = time.Duration(apiErr.RetryAfter) * time.Second
+			}
+			slog.Warn("telegram: polling failed", "error", err.Error(), "retry_in", wait.String())
+			select {
+			case <-ctx.Done():
+			case <-time.After(wait):
+			}
+			continue
+		}
+		for i := range updates {
+			offset = updates[i].UpdateID + 1
+			if err := b.HandleUpdate(ctx, &updates[i]); err != nil {
+				slog.Error("telegram: handling update failed", "update_id", updates[i].UpdateID, "error", err.Error())
+			}
+		}
+	}
+}
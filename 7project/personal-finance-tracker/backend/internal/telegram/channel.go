@@ -0,0 +1,42 @@
+// backend/internal/telegram/channel.go
+
+package telegram
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"pft/internal/alerts"
+	"pft/internal/jobs"
+)
+
+// Channel is the alerts.Channel for Telegram: alerts go to the user's linked chat.
+type Channel struct {
+	Links  Links
+	Sender Sender
+}
+
+// Name implements alerts.Channel.
+func (c *Channel) Name() string { return alerts.ChannelTelegram }
+
+// Deliver implements alerts.Channel. Users without a linked chat are skipped, and a
+// chat that blocked the bot is unlinked.
+func (c *Channel) Deliver(ctx context.Context, a *alerts.Alert) error {
+	chatID, err := c.Links.ChatOf(ctx, a.UserID)
+	if err != nil || chatID == 0 {
+		return err
+	}
+	err = c.Sender.SendMessage(ctx, chatID, a.Title+"\n\n"+a.Body)
+	var apiErr *APIError
+	switch {
+	case IsBlocked(err):
+		slog.Info("unlinking telegram chat that blocked the bot", "user_id", a.UserID)
+		_, err = c.Links.UnlinkChat(ctx, chatID)
+		return err
+	case errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest:
+		return jobs.Permanent(err)
+	}
+	return err
+}
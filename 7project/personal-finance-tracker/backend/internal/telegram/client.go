@@ -0,0 +1,152 @@
+// backend/internal/telegram/client.go
+
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the Telegram Bot API.
+const DefaultBaseURL = "https://api.telegram.org"
+
+// Client calls the Telegram Bot API.
+type Client struct {
+	Token   string       // bot token issued by @BotFather
+	BaseURL string       // defaults to DefaultBaseURL
+	Client  *http.Client // defaults to a client with a 70s timeout (long polling waits up to 50s)
+}
+
+// Update is an incoming update; the bot only handles messages.
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message,omitempty"`
+}
+
+// Message is a message sent to the bot.
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
+	Chat      Chat   `json:"chat"`
+	Date      int64  `json:"date"` // Unix time
+	Text      string `json:"text"`
+}
+
+// User is the sender of a message.
+type User struct {
+	ID           int64  `json:"id"`
+	Username     string `json:"username"`
+	LanguageCode string `json:"language_code"`
+}
+
+// Chat is the conversation a message belongs to; the bot answers private chats only.
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"` // "private", "group", "supergroup", "channel"
+}
+
+// APIError is an unsuccessful Bot API reply.
+type APIError struct {
+	Code        int
+	Description string
+	RetryAfter  int // seconds, set with 429
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram: %d %s", e.Code, e.Description)
+}
+
+// IsBlocked reports whether err means the user blocked the bot or deleted the chat,
+// so messages to it will never arrive.
+func IsBlocked(err error) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.Code == http.StatusForbidden
+}
+
+// SendMessage sends plain text to chatID.
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string) error {
+	return c.call(ctx, "sendMessage", map[string]any{"chat_id": chatID, "text": text}, nil)
+}
+
+// SetWebhook has Telegram POST updates to webhookURL, sending secret in the
+// X-Telegram-Bot-Api-Secret-Token header.
+func (c *Client) SetWebhook(ctx context.Context, webhookURL, secret string) error {
+	return c.call(ctx, "setWebhook", map[string]any{
+		"url": webhookURL, "secret_token": secret, "allowed_updates": []string{"message"},
+	}, nil)
+}
+
+// DeleteWebhook switches the bot back to polling; pending updates are kept.
+func (c *Client) DeleteWebhook(ctx context.Context) error {
+	return c.call(ctx, "deleteWebhook", map[string]any{}, nil)
+}
+
+// GetUpdates long-polls for updates from offset on, waiting up to timeout.
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	var out []Update
+	err := c.call(ctx, "getUpdates", map[string]any{
+		"offset": offset, "timeout": int(timeout.Seconds()), "allowed_updates": []string{"message"},
+	}, &out)
+	return out, err
+}
+
+// call invokes method with params and decodes its result into out (if not nil).
+func (c *Client) call(ctx context.Context, method string, params, out any) error {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(base, "/")+"/bot"+c.Token+"/"+method, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: 70 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The URL carries the bot token; keep it out of logs.
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return fmt.Errorf("telegram %s: %w", method, uerr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	var reply struct {
+		OK          bool            `json:"ok"`
+		Result      json.RawMessage `json:"result"`
+		ErrorCode   int             `json:"error_code"`
+		Description string          `json:"description"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return fmt.Errorf("telegram %s: status %d: %w", method, resp.StatusCode, err)
+	}
+	if !reply.OK {
+		code := reply.ErrorCode
+		if code == 0 {
+			code = resp.StatusCode
+		}
+		return &APIError{Code: code, Description: reply.Description, RetryAfter: reply.Parameters.RetryAfter}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(reply.Result, out)
+}
@@ -0,0 +1,69 @@
+// backend/internal/telegram/parse.go
+
+package telegram
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Entry is an expense typed as a message, e.g. "coffee 3.50" or "12,90 lunch #food".
+// - Category: name after "#", if any
+type Entry struct {
+	Description string
+	Amount      float64
+	Category    string
+}
+
+// currencySigns may surround an amount ("€3.50", "3.50$").
+const currencySigns = "€$£¥₹"
+
+// ParseEntry reads an expense from text: the last token that is an amount, a "#tag"
+// naming the category, and the remaining words as the description. Amounts take a
+// decimal point or comma ("3.50", "3,50") and must be positive.
+func ParseEntry(text string) (Entry, bool) {
+	fields := strings.Fields(text)
+	amountAt := -1
+	var e Entry
+	for i := len(fields) - 1; i >= 0; i-- {
+		if v, ok := parseAmount(fields[i]); ok {
+			amountAt, e.Amount = i, v
+			break
+		}
+	}
+	if amountAt < 0 {
+		return Entry{}, false
+	}
+	words := make([]string, 0, len(fields))
+	for i, f := range fields {
+		switch {
+		case i == amountAt:
+		case len(f) > 1 && f[0] == '#' && e.Category == "":
+			e.Category = f[1:]
+		default:
+			words = append(words, f)
+		}
+	}
+	e.Description = strings.Join(words, " ")
+	return e, true
+}
+
+// parseAmount parses a positive amount with at most two decimals.
+func parseAmount(s string) (float64, bool) {
+	s = strings.Trim(s, currencySigns)
+	if i := strings.LastIndexByte(s, ','); i >= 0 && !strings.Contains(s, ".") && len(s)-i-1 <= 2 {
+		s = s[:i] + "." + s[i+1:]
+	}
+	if s == "" || strings.Trim(s, "0123456789.") != "" {
+		return 0, false
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 && len(s)-i-1 > 2 {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,214 @@
+// backend/internal/telegram/telegram_test.go
+//
+// Purpose:
+//   Verify that messages are parsed into expenses, that the bot links chats with
+//   one-time codes and records expenses in the right category and language (or
+//   explains why one was not recorded), and that alerts reach linked chats (unlinking chats that blocked the bot).
+
+package telegram
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pft/internal/alerts"
+	"pft/internal/repo"
+)
+
+func TestParseEntry(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Entry
+		ok   bool
+	}{
+		{"coffee 3.50", Entry{Description: "coffee", Amount: 3.5}, true},
+		{"12,90 lunch #Food", Entry{Description: "lunch", Amount: 12.9, Category: "Food"}, true},
+		{"train to Berlin 2 €39", Entry{Description: "train to Berlin 2", Amount: 39}, true},
+		{"42", Entry{Amount: 42}, true},
+		{"coffee", Entry{}, false},
+		{"coffee -3", Entry{}, false},
+		{"coffee 1e5", Entry{}, false},
+		{"coffee 3.505", Entry{}, false},
+	}
+	for _, c := range cases {
+		got, ok := ParseEntry(c.in)
+		if ok != c.ok || got != c.want {
+			t.Errorf("ParseEntry(%q) = %+v, %v; want %+v, %v", c.in, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+type fakeLinks struct {
+	codes map[string]int64 // code -> user
+	chats map[int64]int64  // chat -> user
+}
+
+func (f *fakeLinks) Link(_ context.Context, code string, chatID int64, _ string) (int64, error) {
+	userID := f.codes[code]
+	if userID != 0 {
+		delete(f.codes, code)
+		f.chats[chatID] = userID
+	}
+	return userID, nil
+}
+func (f *fakeLinks) UserByChat(_ context.Context, chatID int64) (int64, error) {
+	return f.chats[chatID], nil
+}
+func (f *fakeLinks) ChatOf(_ context.Context, userID int64) (int64, error) {
+	for chat, u := range f.chats {
+		if u == userID {
+			return chat, nil
+		}
+	}
+	return 0, nil
+}
+func (f *fakeLinks) UnlinkChat(_ context.Context, chatID int64) (bool, error) {
+	_, ok := f.chats[chatID]
+	delete(f.chats, chatID)
+	return ok, nil
+}
+
+type fakeLedger struct {
+	cats    []repo.Category
+	recent  map[string]int64
+	created []repo.Transaction
+	tenant  int64
+}
+
+func (f *fakeLedger) List(context.Context, int64) ([]repo.Category, error) { return f.cats, nil }
+func (f *fakeLedger) Create(ctx context.Context, t *repo.Transaction) (*repo.Transaction, error) {
+	f.tenant, _ = repo.TenantFrom(ctx)
+	if t.Amount > 500 {
+		return nil, &repo.LimitError{CategoryID: 1, Category: "Food", Limit: 500}
+	}
+	f.created = append(f.created, *t)
+	return t, nil
+}
+func (f *fakeLedger) RecentCategory(_ context.Context, _ int64, _, description string) (*int64, error) {
+	if id, ok := f.recent[description]; ok {
+		return &id, nil
+	}
+	return nil, nil
+}
+
+type fakeSender struct{ sent map[int64][]string }
+
+func (f *fakeSender) SendMessage(_ context.Context, chatID int64, text string) error {
+	f.sent[chatID] = append(f.sent[chatID], text)
+	return nil
+}
+
+func TestBot(t *testing.T) {
+	links := &fakeLinks{codes: map[string]int64{"CODE1": 7}, chats: map[int64]int64{}}
+	ledger := &fakeLedger{
+		cats: []repo.Category{
+			{ID: 1, Name: "Food", Type: "expense"},
+			{ID: 2, Name: "Public transport", Type: "expense"},
+			{ID: 3, Name: "Transport", Type: "expense"},
+			{ID: 4, Name: "Salary", Type: "income"},
+		},
+		recent: map[string]int64{"coffee": 1},
+	}
+	sender := &fakeSender{sent: map[int64][]string{}}
+	var written []int64
+	bot := &Bot{Sender: sender, Links: links, Categories: ledger, Transactions: ledger,
+		Locale:  func(context.Context, int64) string { return "" },
+		OnWrite: func(_ context.Context, userID int64) { written = append(written, userID) }}
+	date := time.Date(2026, 10, 12, 18, 30, 0, 0, time.UTC).Unix()
+	send := func(text string) string {
+		t.Helper()
+		u := &Update{UpdateID: 1, Message: &Message{MessageID: 9, Date: date, Text: text,
+			From: &User{ID: 5, LanguageCode: "de-DE"}, Chat: Chat{ID: 100, Type: "private"}}}
+		if err := bot.HandleUpdate(context.Background(), u); err != nil {
+			t.Fatal(err)
+		}
+		msgs := sender.sent[100]
+		return msgs[len(msgs)-1]
+	}
+
+	if got := send("coffee 3.50"); !strings.Contains(got, "noch mit keinem Konto") {
+		t.Fatalf("unlinked chat, got %q", got)
+	}
+	if got := send("/start WRONG"); !strings.Contains(got, "ungültig") {
+		t.Fatalf("invalid code, got %q", got)
+	}
+	if got := send("/start CODE1"); !strings.Contains(got, "verknüpft") || links.chats[100] != 7 {
+		t.Fatalf("link, got %q", got)
+	}
+
+	if got := send("coffee 3.50"); got != `3.50 für "coffee" in Food am 2026-10-12 erfasst.` {
+		t.Fatalf("recent category, got %q", got)
+	}
+	send("public transport ticket 2.90")
+	send("cinema 12 #food")
+	send("gift 20")
+	if got := send("taxi 15 #travel"); !strings.Contains(got, "Food, Public transport, Transport") {
+		t.Fatalf("unknown category, got %q", got)
+	}
+	if got := send("dinner 600 #food"); !strings.Contains(got, "500.00") || !strings.Contains(got, "nicht erfasst") {
+		t.Fatalf("blocked by the category limit, got %q", got)
+	}
+
+	wantCats := []int64{1, 2, 1, 0}
+	if len(ledger.created) != len(wantCats) {
+		t.Fatalf("created %d transactions, want %d", len(ledger.created), len(wantCats))
+	}
+	for i, tx := range ledger.created {
+		var got int64
+		if tx.CategoryID != nil {
+			got = *tx.CategoryID
+		}
+		if got != wantCats[i] || tx.UserID != 7 || tx.Type != "expense" || !tx.Date.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("transaction %d = %+v, want category %d", i, tx, wantCats[i])
+		}
+	}
+	if ledger.tenant != 7 {
+		t.Fatalf("writes should be scoped to the linked user, got tenant %d", ledger.tenant)
+	}
+	if len(written) != len(wantCats) || written[0] != 7 {
+		t.Fatalf("OnWrite got %v, want user 7 once per recorded expense", written)
+	}
+
+	send("/unlink")
+	if _, linked := links.chats[100]; linked {
+		t.Fatal("chat still linked after /unlink")
+	}
+}
+
+func TestChannel_Deliver(t *testing.T) {
+	var status int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/botTOKEN/sendMessage" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			_, _ = io.WriteString(w, `{"ok":true,"result":{}}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`)
+	}))
+	defer srv.Close()
+
+	links := &fakeLinks{chats: map[int64]int64{100: 7}}
+	ch := &Channel{Links: links, Sender: &Client{Token: "TOKEN", BaseURL: srv.URL}}
+	a := &alerts.Alert{UserID: 7, Title: "Rent is due soon", Body: "Rent (900.00) is due today."}
+
+	status = http.StatusOK
+	if err := ch.Deliver(context.Background(), a); err != nil {
+		t.Fatal(err)
+	}
+	status = http.StatusForbidden
+	if err := ch.Deliver(context.Background(), a); err != nil || len(links.chats) != 0 {
+		t.Fatalf("blocked chat should be unlinked: err %v, links %v", err, links.chats)
+	}
+	if err := ch.Deliver(context.Background(), &alerts.Alert{UserID: 8}); err != nil {
+		t.Fatalf("users without a chat are skipped, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+// backend/internal/usage/usage.go
+
+// Package usage counts API requests and bytes per user per day (migration 039).
+// Counting happens in memory on the request path; a Recorder adds its counts to the
+// database about once a minute, so stored figures lag by up to a flush interval and
+// counts still in memory are lost if the process crashes.
+package usage
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"pft/internal/repo"
+)
+
+// Store persists counts; implemented by repo.UsageRepo.
+type Store interface {
+	Add(ctx context.Context, counts []repo.UsageCount) error
+}
+
+type key struct {
+	userID int64
+	day    string
+}
+
+// Recorder accumulates usage until Flush.
+// - Interval: time between flushes in Run (default 1m)
+type Recorder struct {
+	Store    Store
+	Interval time.Duration
+
+	mu     sync.Mutex
+	counts map[key]*repo.Usage
+}
+
+// Record counts one request of the user answered with status.
+func (r *Recorder) Record(userID int64, status int, bytesIn, bytesOut int64) {
+	k := key{userID: userID, day: time.Now().UTC().Format("2006-01-02")}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counts == nil {
+		r.counts = map[key]*repo.Usage{}
+	}
+	u := r.counts[k]
+	if u == nil {
+		u = &repo.Usage{}
+		r.counts[k] = u
+	}
+	u.Requests++
+	if status >= 400 {
+		u.Errors++
+	}
+	u.BytesIn += max(bytesIn, 0)
+	u.BytesOut += max(bytesOut, 0)
+}
+
+// Flush stores the counts recorded so far. On failure they are kept for the next
+// flush, so a short database outage loses nothing.
+func (r *Recorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	pending := r.counts
+	r.counts = nil
+	r.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	counts := make([]repo.UsageCount, 0, len(pending))
+	for k, u := range pending {
+		day, _ := time.Parse("2006-01-02", k.day)
+		counts = append(counts, repo.UsageCount{UserID: k.userID, Day: day, Usage: *u})
+	}
+	if err := r.Store.Add(ctx, counts); err != nil {
+		r.mu.Lock()
+		for k, u := range pending {
+			if cur := r.counts[k]; cur != nil {
+				u.Requests += cur.Requests
+				u.Errors += cur.Errors
+				u.BytesIn += cur.BytesIn
+				u.BytesOut += cur.BytesOut
+			}
+			if r.counts == nil {
+				r.counts = map[key]*repo.Usage{}
+			}
+			r.counts[k] = u
+		}
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Run flushes every Interval until ctx is cancelled. Call Flush once more after the
+// HTTP server has stopped to keep the last counts.
+func (r *Recorder) Run(ctx context.Context) {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := r.Flush(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("storing usage counts failed", "error", err.Error())
+			}
+		}
+	}
+}
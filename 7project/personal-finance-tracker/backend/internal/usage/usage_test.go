@@ -0,0 +1,60 @@
+// backend/internal/usage/usage_test.go
+//
+// Purpose:
+//   Verify that the Recorder sums requests, errors and bytes per user and day, and
+//   keeps its counts for the next flush when storing them fails.
+
+package usage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"pft/internal/repo"
+)
+
+type fakeStore struct {
+	err   error
+	added []repo.UsageCount
+}
+
+func (f *fakeStore) Add(_ context.Context, counts []repo.UsageCount) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.added = append(f.added, counts...)
+	return nil
+}
+
+func TestRecorder(t *testing.T) {
+	store := &fakeStore{err: errors.New("db down")}
+	r := &Recorder{Store: store}
+	r.Record(1, 200, 100, 2000)
+	r.Record(1, 404, 0, 50)
+	r.Record(2, 200, -1, 10) // unknown request length
+	if err := r.Flush(context.Background()); err == nil {
+		t.Fatal("expected the store error")
+	}
+
+	r.Record(1, 500, 10, 0)
+	store.err = nil
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	got := map[int64]repo.Usage{}
+	for _, c := range store.added {
+		got[c.UserID] = c.Usage
+	}
+	if want := (repo.Usage{Requests: 3, Errors: 2, BytesIn: 110, BytesOut: 2050}); got[1] != want {
+		t.Errorf("user 1: got %+v, want %+v", got[1], want)
+	}
+	if want := (repo.Usage{Requests: 1, BytesOut: 10}); got[2] != want {
+		t.Errorf("user 2: got %+v, want %+v", got[2], want)
+	}
+
+	store.added = nil
+	if err := r.Flush(context.Background()); err != nil || len(store.added) != 0 {
+		t.Fatalf("second flush stored %+v (%v)", store.added, err)
+	}
+}
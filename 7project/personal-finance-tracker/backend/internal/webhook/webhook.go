@@ -0,0 +1,276 @@
+// backend/internal/webhook/webhook.go
+
+// Package webhook delivers change events to endpoints integrators register. The
+// outbox dispatcher hands every event to the store's Enqueue, which queues one
+// delivery per subscribed endpoint; the Deliverer posts them, signed with the
+// endpoint's secret, and retries each failed delivery on its own with exponential
+// backoff until it succeeds or runs out of attempts and becomes a dead letter.
+//
+// Like the outbox, delivery is at least once: receivers should ignore event IDs
+// (the X-PFT-Event-ID header) they have already handled.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"pft/internal/jobs"
+	"pft/internal/repo"
+)
+
+// Headers sent with every delivery.
+const (
+	SignatureHeader = "X-PFT-Signature" // see Sign
+	EventHeader     = "X-PFT-Event"     // the topic, e.g. "transactions.created"
+	EventIDHeader   = "X-PFT-Event-ID"  // the same for every endpoint and attempt
+	DeliveryHeader  = "X-PFT-Delivery"  // the delivery, as listed in the delivery log
+)
+
+// Sign returns the signature header for body sent at t: "t=<unix seconds>,v1=<hex>",
+// where v1 is the HMAC-SHA256 of "<unix seconds>.<body>" keyed with secret. Signing
+// the time lets receivers reject replayed requests.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + mac(secret, ts, body)
+}
+
+func mac(secret, ts string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts + "."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Verify reports whether header is a valid signature of body with secret, made within
+// tolerance of now. It is what receivers do; any v1 value may match, so signatures
+// can carry several.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) bool {
+	var ts string
+	var sigs []string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sigs = append(sigs, v)
+		}
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	if d := now.Sub(time.Unix(sec, 0)); d > tolerance || d < -tolerance {
+		return false
+	}
+	want := []byte(mac(secret, ts, body))
+	for _, s := range sigs {
+		if hmac.Equal([]byte(s), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidURL reports whether raw may be registered as an endpoint: an https URL without
+// credentials whose host is not a private, loopback or link-local address. Hosts are
+// checked again when connecting, as a name may resolve to such an address.
+func ValidURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil || u.Fragment != "" {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip == nil || public(ip)
+}
+
+func public(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast())
+}
+
+// errPrivateAddress refuses connections to the server's own network.
+var errPrivateAddress = errors.New("endpoint resolves to a private address")
+
+// defaultClient posts with a 15s timeout, does not follow redirects and refuses to
+// connect to private addresses, so endpoints cannot reach the server's own network
+// (nor go through a proxy, which would hide the address).
+var defaultClient = &http.Client{
+	Timeout: 15 * time.Second,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: func(_, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if ip := net.ParseIP(host); err != nil || ip == nil || !public(ip) {
+					return errPrivateAddress
+				}
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConnsPerHost: 2,
+	},
+}
+
+// Store claims deliveries and records attempts; implemented by repo.WebhookRepo.
+type Store interface {
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]repo.WebhookDelivery, error)
+	Record(ctx context.Context, d *repo.WebhookDelivery, a *repo.WebhookAttempt, retryAt *time.Time) error
+}
+
+// Deliverer posts queued deliveries to their endpoints.
+// - Client: defaults to a client with a 15s timeout that refuses private addresses
+// - Batch: deliveries claimed and sent concurrently per poll (default 20)
+// - PollInterval: wait after finding nothing to send (default 1s)
+// - Lease: time a batch may take before another deliverer may claim it (default 5m)
+// - MaxAttempts: attempts before a delivery becomes a dead letter (default 10, spanning about 85 minutes)
+type Deliverer struct {
+	Store        Store
+	Client       *http.Client
+	Batch        int
+	PollInterval time.Duration
+	Lease        time.Duration
+	MaxAttempts  int
+}
+
+// Run delivers until ctx is cancelled.
+func (d *Deliverer) Run(ctx context.Context) {
+	poll := d.PollInterval
+	if poll <= 0 {
+		poll = time.Second
+	}
+	for ctx.Err() == nil {
+		n, err := d.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.Error("webhook deliveries unavailable", "error", err.Error())
+		}
+		if n > 0 && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(poll):
+		}
+	}
+}
+
+// RunOnce claims one batch and sends it, returning the number of deliveries claimed.
+// The error covers access to the store only; failed sends are recorded on the
+// deliveries.
+func (d *Deliverer) RunOnce(ctx context.Context) (int, error) {
+	batch, lease, maxAttempts := d.Batch, d.Lease, d.MaxAttempts
+	if batch <= 0 {
+		batch = 20
+	}
+	if lease <= 0 {
+		lease = 5 * time.Minute
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 10
+	}
+	claimed, err := d.Store.Claim(ctx, batch, lease)
+	if err != nil || len(claimed) == 0 {
+		return 0, err
+	}
+
+	// Record outcomes even if ctx is cancelled meanwhile.
+	sctx := context.WithoutCancel(ctx)
+	errs := make([]error, len(claimed))
+	var wg sync.WaitGroup
+	for i := range claimed {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			del := &claimed[i]
+			a := d.send(ctx, del)
+			var retryAt *time.Time
+			if a.Error != nil {
+				if del.Attempts < maxAttempts {
+					t := time.Now().Add(jobs.Backoff(del.Attempts))
+					retryAt = &t
+				}
+				slog.Warn("webhook delivery failed", "delivery_id", del.ID, "endpoint_id", del.EndpointID,
+					"attempt", del.Attempts, "error", *a.Error, "retry", retryAt != nil)
+			}
+			errs[i] = d.Store.Record(sctx, del, a, retryAt)
+		}()
+	}
+	wg.Wait()
+	return len(claimed), errors.Join(errs...)
+}
+
+// send posts del once and reports the attempt; Error is set unless the endpoint
+// answered 2xx.
+func (d *Deliverer) send(ctx context.Context, del *repo.WebhookDelivery) *repo.WebhookAttempt {
+	a := &repo.WebhookAttempt{Attempt: del.Attempts}
+	failed := func(msg string) *repo.WebhookAttempt {
+		a.Error = &msg
+		return a
+	}
+	body, err := json.Marshal(struct {
+		ID    int64           `json:"id"`
+		Topic string          `json:"topic"`
+		Data  json.RawMessage `json:"data"`
+	}{del.EventID, del.Topic, del.Payload})
+	if err != nil {
+		return failed(err.Error())
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, del.URL, bytes.NewReader(body))
+	if err != nil {
+		return failed(err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pft-webhooks/1")
+	req.Header.Set(EventHeader, del.Topic)
+	req.Header.Set(EventIDHeader, strconv.FormatInt(del.EventID, 10))
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(del.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(del.Secret, time.Now(), body))
+
+	client := d.Client
+	if client == nil {
+		client = defaultClient
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	a.DurationMS = int(time.Since(start).Milliseconds())
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return failed(err.Error())
+	}
+	defer resp.Body.Close()
+	reply, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	a.StatusCode = &resp.StatusCode
+	// Stored as text, which cannot hold NUL bytes or invalid UTF-8.
+	a.ResponseBody = strings.ReplaceAll(strings.ToValidUTF8(string(reply), "\uFFFD"), "\x00", "")
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return failed(fmt.Sprintf("endpoint answered %d", resp.StatusCode))
+	}
+	return a
+}
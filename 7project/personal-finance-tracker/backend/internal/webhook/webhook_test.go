@@ -0,0 +1,161 @@
+// backend/internal/webhook/webhook_test.go
+//
+// Purpose:
+//   Verify signatures round-trip and expire, that endpoints on private addresses are
+//   refused, and that the deliverer posts signed events, retries failures with
+//   backoff and turns exhausted deliveries into dead letters.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"pft/internal/repo"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	now := time.Unix(1700000000, 0)
+	sig := Sign("whsec_a", now, body)
+	if !Verify("whsec_a", sig, body, now.Add(time.Minute), 5*time.Minute) {
+		t.Fatalf("own signature %q rejected", sig)
+	}
+	if !Verify("whsec_a", "v1=0000,"+sig, body, now, time.Minute) {
+		t.Error("any v1 value should be accepted")
+	}
+	for name, ok := range map[string]bool{
+		"wrong secret": Verify("whsec_b", sig, body, now, time.Minute),
+		"changed body": Verify("whsec_a", sig, []byte(`{"id":2}`), now, time.Minute),
+		"too old":      Verify("whsec_a", sig, body, now.Add(10*time.Minute), 5*time.Minute),
+		"no timestamp": Verify("whsec_a", sig[len("t=1700000000,"):], body, now, time.Minute),
+	} {
+		if ok {
+			t.Errorf("%s: signature accepted", name)
+		}
+	}
+}
+
+func TestValidURL(t *testing.T) {
+	cases := []struct {
+		url  string
+		want bool
+	}{
+		{"https://example.com/hooks/pft", true},
+		{"https://203.0.113.7:8443/hook", true},
+		{"http://example.com/hook", false},
+		{"https://user:pw@example.com/hook", false},
+		{"https://localhost/hook", false},
+		{"https://127.0.0.1/hook", false},
+		{"https://10.1.2.3/hook", false},
+		{"https://169.254.169.254/latest/meta-data", false},
+		{"https://[::1]/hook", false},
+		{"https:///hook", false},
+	}
+	for _, c := range cases {
+		if got := ValidURL(c.url); got != c.want {
+			t.Errorf("ValidURL(%q) = %v, want %v", c.url, got, c.want)
+		}
+	}
+}
+
+func TestDefaultClientRefusesPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer srv.Close()
+	if _, err := defaultClient.Post(srv.URL, "application/json", nil); err == nil {
+		t.Fatal("posting to a loopback address should fail")
+	}
+}
+
+// fakeStore hands out the queued deliveries once and records each outcome.
+type fakeStore struct {
+	mu       sync.Mutex
+	queue    []repo.WebhookDelivery
+	attempts map[int64]*repo.WebhookAttempt
+	retryAt  map[int64]*time.Time
+}
+
+func (s *fakeStore) Claim(_ context.Context, limit int, _ time.Duration) ([]repo.WebhookDelivery, error) {
+	n := min(limit, len(s.queue))
+	out := s.queue[:n]
+	s.queue = s.queue[n:]
+	for i := range out {
+		out[i].Attempts++
+	}
+	return out, nil
+}
+
+func (s *fakeStore) Record(_ context.Context, d *repo.WebhookDelivery, a *repo.WebhookAttempt, retryAt *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts[d.ID], s.retryAt[d.ID] = a, retryAt
+	return nil
+}
+
+func TestDeliverer_RunOnce(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !Verify("whsec_test", r.Header.Get(SignatureHeader), body, time.Now(), time.Minute) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		var e struct {
+			ID    int64           `json:"id"`
+			Topic string          `json:"topic"`
+			Data  json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(body, &e); err != nil || r.Header.Get(EventIDHeader) != "41" || e.ID != 41 ||
+			e.Topic != r.Header.Get(EventHeader) || string(e.Data) != `{"entity_id":"9"}` {
+			http.Error(w, "unexpected event", http.StatusBadRequest)
+			return
+		}
+		if e.Topic == "budgets.updated" {
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	del := func(id int64, topic, secret string, attempts int) repo.WebhookDelivery {
+		return repo.WebhookDelivery{ID: id, EventID: 41, Topic: topic, Payload: json.RawMessage(`{"entity_id":"9"}`),
+			Attempts: attempts, URL: srv.URL, Secret: secret}
+	}
+	s := &fakeStore{attempts: map[int64]*repo.WebhookAttempt{}, retryAt: map[int64]*time.Time{}, queue: []repo.WebhookDelivery{
+		del(1, "transactions.created", "whsec_test", 0),
+		del(2, "budgets.updated", "whsec_test", 0),
+		del(3, "budgets.updated", "whsec_test", 2),
+		del(4, "transactions.created", "whsec_rotated", 0),
+	}}
+	d := &Deliverer{Store: s, Client: srv.Client(), MaxAttempts: 3}
+
+	n, err := d.RunOnce(context.Background())
+	if err != nil || n != 4 {
+		t.Fatalf("RunOnce = %d, %v", n, err)
+	}
+	if a := s.attempts[1]; a.Error != nil || *a.StatusCode != http.StatusNoContent || s.retryAt[1] != nil {
+		t.Errorf("delivery 1: %+v, want delivered", a)
+	}
+	if a, at := s.attempts[2], s.retryAt[2]; a.Error == nil || *a.StatusCode != 503 || a.ResponseBody != "try later\n" ||
+		at == nil || time.Until(*at) < 5*time.Second {
+		t.Errorf("delivery 2: %+v retry %v, want a retry with backoff", a, at)
+	}
+	if a, at := s.attempts[3], s.retryAt[3]; a.Error == nil || a.Attempt != 3 || at != nil {
+		t.Errorf("delivery 3: %+v retry %v, want a dead letter after its last attempt", a, at)
+	}
+	if a := s.attempts[4]; a.Error == nil || *a.StatusCode != http.StatusUnauthorized {
+		t.Errorf("delivery 4: %+v, want the receiver to reject the signature", a)
+	}
+
+	if n, err := d.RunOnce(context.Background()); n != 0 || err != nil {
+		t.Errorf("nothing queued: RunOnce = %d, %v", n, err)
+	}
+}
@@ -0,0 +1,8 @@
+-- backend/migrations/009_user_dashboard.sql
+-- Per-user dashboard layout: ordered list of widgets with their enabled flag,
+-- stored as JSON, e.g. [{"id":"summary","enabled":true}, ...].
+CREATE TABLE IF NOT EXISTS user_dashboard (
+    user_id    BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    widgets    JSONB NOT NULL DEFAULT '[]'::jsonb,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
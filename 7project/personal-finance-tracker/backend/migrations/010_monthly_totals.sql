@@ -0,0 +1,70 @@
+-- backend/migrations/010_monthly_totals.sql
+-- Per-user, per-month, per-category transaction totals maintained by triggers,
+-- so dashboard and report queries do not aggregate raw transactions on every load.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS monthly_totals (
+    user_id     BIGINT NOT NULL,
+    month       DATE NOT NULL,              -- first day of the month
+    category_id BIGINT NULL,                -- NULL = uncategorized
+    type        TEXT NOT NULL CHECK (type IN ('income','expense')),
+    total       NUMERIC(14,2) NOT NULL DEFAULT 0,
+    tx_count    INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_monthly_totals_key
+  ON monthly_totals (user_id, month, type, COALESCE(category_id, -1));
+
+-- Adds a transaction's amount to its bucket (creating the bucket when missing).
+CREATE OR REPLACE FUNCTION monthly_totals_add(p_user BIGINT, p_date DATE, p_cat BIGINT, p_type TEXT, p_amount NUMERIC)
+RETURNS void AS $$
+BEGIN
+    INSERT INTO monthly_totals (user_id, month, category_id, type, total, tx_count)
+    VALUES (p_user, date_trunc('month', p_date)::date, p_cat, p_type, p_amount, 1)
+    ON CONFLICT (user_id, month, type, COALESCE(category_id, -1))
+    DO UPDATE SET total = monthly_totals.total + EXCLUDED.total,
+                  tx_count = monthly_totals.tx_count + 1;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Removes a transaction's amount from its bucket; empty buckets are deleted.
+-- Uses UPDATE only, so cascaded user deletes never recreate rows.
+CREATE OR REPLACE FUNCTION monthly_totals_sub(p_user BIGINT, p_date DATE, p_cat BIGINT, p_type TEXT, p_amount NUMERIC)
+RETURNS void AS $$
+BEGIN
+    UPDATE monthly_totals
+       SET total = total - p_amount, tx_count = tx_count - 1
+     WHERE user_id = p_user AND month = date_trunc('month', p_date)::date
+       AND type = p_type AND COALESCE(category_id, -1) = COALESCE(p_cat, -1);
+    DELETE FROM monthly_totals
+     WHERE user_id = p_user AND month = date_trunc('month', p_date)::date
+       AND type = p_type AND COALESCE(category_id, -1) = COALESCE(p_cat, -1)
+       AND tx_count <= 0;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION transactions_monthly_totals() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM monthly_totals_sub(OLD.user_id, OLD.date, OLD.category_id, OLD.type, OLD.amount);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM monthly_totals_add(NEW.user_id, NEW.date, NEW.category_id, NEW.type, NEW.amount);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_transactions_monthly_totals ON transactions;
+CREATE TRIGGER trg_transactions_monthly_totals
+AFTER INSERT OR UPDATE OR DELETE ON transactions
+FOR EACH ROW EXECUTE FUNCTION transactions_monthly_totals();
+
+-- Backfill from existing data.
+DELETE FROM monthly_totals;
+INSERT INTO monthly_totals (user_id, month, category_id, type, total, tx_count)
+SELECT user_id, date_trunc('month', date)::date, category_id, type, SUM(amount), COUNT(*)
+FROM transactions
+GROUP BY user_id, date_trunc('month', date)::date, category_id, type;
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- backend/migrations/011_user_preferences.sql
+-- User preferences stored alongside the account.
+-- week_start follows Go's time.Weekday numbering (0 = Sunday ... 6 = Saturday); ISO weeks start on Monday.
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS week_start SMALLINT NOT NULL DEFAULT 1
+  CHECK (week_start BETWEEN 0 AND 6);
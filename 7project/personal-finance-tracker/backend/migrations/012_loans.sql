@@ -0,0 +1,25 @@
+-- backend/migrations/012_loans.sql
+-- Loans and debts with a fixed-rate, monthly amortizing schedule.
+-- Payments are regular (expense) transactions linked to a loan via loan_payments.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS loans (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name         TEXT NOT NULL,
+    principal    NUMERIC(12,2) NOT NULL CHECK (principal > 0),
+    annual_rate  NUMERIC(7,4) NOT NULL CHECK (annual_rate >= 0), -- percent, e.g. 4.5
+    term_months  INTEGER NOT NULL CHECK (term_months > 0),
+    start_date   DATE NOT NULL,                                    -- first payment due one month after
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_loans_user ON loans(user_id);
+
+CREATE TABLE IF NOT EXISTS loan_payments (
+    loan_id        BIGINT NOT NULL REFERENCES loans(id) ON DELETE CASCADE,
+    transaction_id BIGINT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    PRIMARY KEY (loan_id, transaction_id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_loan_payments_tx ON loan_payments(transaction_id);
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- backend/migrations/013_bills.sql
+-- Monthly bills with a due day; due days past the end of a short month fall on its last day.
+CREATE TABLE IF NOT EXISTS bills (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id  BIGINT NULL REFERENCES categories(id) ON DELETE SET NULL,
+    name         TEXT NOT NULL,
+    amount       NUMERIC(12,2) NOT NULL CHECK (amount >= 0),
+    due_day      SMALLINT NOT NULL CHECK (due_day BETWEEN 1 AND 31),
+    autopay      BOOLEAN NOT NULL DEFAULT FALSE,
+    remind_days  SMALLINT NOT NULL DEFAULT 3 CHECK (remind_days BETWEEN 0 AND 31),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_bills_user ON bills(user_id);
@@ -0,0 +1,16 @@
+-- backend/migrations/014_subscriptions.sql
+-- Subscriptions (streaming, software, memberships) with a billing interval and renewal date.
+-- cancel_by optionally records the last day to cancel before the next renewal.
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id               BIGSERIAL PRIMARY KEY,
+    user_id          BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id      BIGINT NULL REFERENCES categories(id) ON DELETE SET NULL,
+    service          TEXT NOT NULL,
+    amount           NUMERIC(12,2) NOT NULL CHECK (amount >= 0),
+    billing_interval TEXT NOT NULL CHECK (billing_interval IN ('weekly','biweekly','monthly','quarterly','yearly')),
+    renewal_date     DATE NOT NULL,
+    cancel_by        DATE NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user ON subscriptions(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_subscriptions_user_service ON subscriptions(user_id, lower(service));
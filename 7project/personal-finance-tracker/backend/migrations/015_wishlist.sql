@@ -0,0 +1,14 @@
+-- backend/migrations/015_wishlist.sql
+-- Planned purchases. Once bought, transaction_id points at the expense that recorded the purchase.
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id     BIGINT NULL REFERENCES categories(id) ON DELETE SET NULL,
+    name            TEXT NOT NULL,
+    estimated_price NUMERIC(12,2) NOT NULL CHECK (estimated_price >= 0),
+    priority        SMALLINT NOT NULL DEFAULT 3 CHECK (priority BETWEEN 1 AND 5), -- 1 = highest
+    note            TEXT NOT NULL DEFAULT '',
+    transaction_id  BIGINT NULL REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_wishlist_user ON wishlist_items(user_id);
@@ -0,0 +1,42 @@
+-- backend/migrations/016_expense_splits.sql
+-- Shared expense splitting with external contacts.
+-- A split divides one transaction into shares; a NULL contact_id in a share (or as payer)
+-- stands for the owning user. Settlements record money exchanged to clear balances.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS contacts (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    email      TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_contacts_user_name ON contacts(user_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS expense_splits (
+    id               BIGSERIAL PRIMARY KEY,
+    user_id          BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    transaction_id   BIGINT NOT NULL UNIQUE REFERENCES transactions(id) ON DELETE CASCADE,
+    payer_contact_id BIGINT NULL REFERENCES contacts(id) ON DELETE RESTRICT,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS expense_split_shares (
+    split_id   BIGINT NOT NULL REFERENCES expense_splits(id) ON DELETE CASCADE,
+    contact_id BIGINT NULL REFERENCES contacts(id) ON DELETE RESTRICT,
+    amount     NUMERIC(12,2) NOT NULL CHECK (amount >= 0)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_split_shares ON expense_split_shares(split_id, COALESCE(contact_id, -1));
+
+CREATE TABLE IF NOT EXISTS settlements (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    contact_id BIGINT NOT NULL REFERENCES contacts(id) ON DELETE RESTRICT,
+    amount     NUMERIC(12,2) NOT NULL,  -- > 0: contact paid the user; < 0: user paid the contact
+    date       DATE NOT NULL,
+    note       TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_settlements_user ON settlements(user_id);
+
+COMMIT;
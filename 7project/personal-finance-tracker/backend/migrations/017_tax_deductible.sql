@@ -0,0 +1,13 @@
+-- backend/migrations/017_tax_deductible.sql
+-- Tax-deductible tagging. Categories carry the default flag and the tax category label
+-- used in the annual report; a transaction may override the flag (NULL = inherit).
+BEGIN;
+
+ALTER TABLE categories
+    ADD COLUMN IF NOT EXISTS tax_deductible BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS tax_category   TEXT    NOT NULL DEFAULT '';
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS tax_deductible BOOLEAN NULL;
+
+COMMIT;
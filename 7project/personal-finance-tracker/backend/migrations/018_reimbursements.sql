@@ -0,0 +1,38 @@
+-- backend/migrations/018_reimbursements.sql
+-- Reimbursable expenses grouped into claims. Once a claim is paid its transactions are
+-- flagged reimbursed and stop counting as spending in monthly_totals.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS reimbursement_claims (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title        TEXT NOT NULL,
+    status       TEXT NOT NULL DEFAULT 'draft' CHECK (status IN ('draft','submitted','paid')),
+    submitted_at DATE NULL,
+    paid_at      DATE NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_claims_user ON reimbursement_claims(user_id);
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS reimbursable BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS claim_id     BIGINT NULL REFERENCES reimbursement_claims(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS reimbursed   BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_tx_claim ON transactions(claim_id) WHERE claim_id IS NOT NULL;
+
+-- Reimbursed expenses contribute a zero amount (but still count as a transaction).
+CREATE OR REPLACE FUNCTION transactions_monthly_totals() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM monthly_totals_sub(OLD.user_id, OLD.date, OLD.category_id, OLD.type,
+                                   CASE WHEN OLD.reimbursed THEN 0 ELSE OLD.amount END);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM monthly_totals_add(NEW.user_id, NEW.date, NEW.category_id, NEW.type,
+                                   CASE WHEN NEW.reimbursed THEN 0 ELSE NEW.amount END);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- backend/migrations/019_emergency_fund.sql
+-- Emergency fund: a target expressed in months of average expenses, and the accounts
+-- (savings, money market, cash) designated to hold the fund with their current balances.
+BEGIN;
+
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS emergency_fund_months NUMERIC(4,1) NOT NULL DEFAULT 6
+  CHECK (emergency_fund_months > 0 AND emergency_fund_months <= 60);
+
+CREATE TABLE IF NOT EXISTS emergency_fund_accounts (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    balance    NUMERIC(14,2) NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_fund_accounts_user ON emergency_fund_accounts(user_id);
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- backend/migrations/020_income_sources.sql
+-- Recurring income definitions (salary, freelance retainers) used to project expected income.
+-- start_date is the first (or any known) pay date; occurrences repeat every pay_interval until end_date.
+CREATE TABLE IF NOT EXISTS income_sources (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id  BIGINT NULL REFERENCES categories(id) ON DELETE SET NULL,
+    name         TEXT NOT NULL,
+    kind         TEXT NOT NULL DEFAULT 'salary' CHECK (kind IN ('salary','freelance','other')),
+    amount       NUMERIC(12,2) NOT NULL CHECK (amount >= 0),
+    pay_interval TEXT NOT NULL CHECK (pay_interval IN ('weekly','biweekly','monthly','quarterly','yearly')),
+    start_date   DATE NOT NULL,
+    end_date     DATE NULL CHECK (end_date IS NULL OR end_date >= start_date),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_income_sources_user ON income_sources(user_id);
@@ -0,0 +1,13 @@
+-- backend/migrations/021_exchange_rates.sql
+-- Daily exchange rates as published by the configured provider.
+-- One row per (date, base, quote); rate is the amount of quote currency for 1 unit of base.
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    date       DATE NOT NULL,
+    base       CHAR(3) NOT NULL,
+    quote      CHAR(3) NOT NULL,
+    rate       NUMERIC(20,10) NOT NULL CHECK (rate > 0),
+    source     TEXT NOT NULL,
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (date, base, quote)
+);
+CREATE INDEX IF NOT EXISTS idx_exchange_rates_base_quote_date ON exchange_rates(base, quote, date DESC);
@@ -0,0 +1,9 @@
+-- backend/migrations/022_closed_periods.sql
+-- Closed (locked) months. Transactions dated in a closed month cannot be created,
+-- edited or deleted until the month is reopened.
+CREATE TABLE IF NOT EXISTS closed_periods (
+    user_id   BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    month     DATE NOT NULL,               -- first day of the month
+    closed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, month)
+);
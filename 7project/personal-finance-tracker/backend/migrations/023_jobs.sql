@@ -0,0 +1,29 @@
+-- backend/migrations/023_jobs.sql
+-- Background job queue. Workers claim ready jobs with FOR UPDATE SKIP LOCKED, so any
+-- number of API instances can share the queue. A job whose worker died is picked up
+-- again once its lease (locked_at) expires.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id           BIGSERIAL PRIMARY KEY,
+    kind         TEXT NOT NULL,
+    user_id      BIGINT REFERENCES users(id) ON DELETE CASCADE, -- NULL for system jobs
+    payload      JSONB NOT NULL DEFAULT '{}',
+    state        TEXT NOT NULL DEFAULT 'pending'
+                 CHECK (state IN ('pending', 'running', 'done', 'failed')),
+    attempts     INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 5 CHECK (max_attempts > 0),
+    run_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_at    TIMESTAMPTZ,
+    last_error   TEXT,
+    unique_key   TEXT,                                          -- dedupes pending/running jobs
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS ix_jobs_ready ON jobs(run_at) WHERE state IN ('pending', 'running');
+CREATE INDEX IF NOT EXISTS ix_jobs_user ON jobs(user_id, created_at DESC) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS ux_jobs_unique_key ON jobs(unique_key)
+    WHERE unique_key IS NOT NULL AND state IN ('pending', 'running');
+
+COMMIT;
@@ -0,0 +1,140 @@
+-- backend/migrations/024_transactions_partitioning.sql
+-- Range-partition transactions by month. Every list, report and dashboard query filters
+-- on date, so the planner only scans the partitions that overlap the requested range.
+--
+-- Monthly partitions are named transactions_pYYYYMM and created on demand by
+-- ensure_transaction_partition(); the repo calls it before writing a date, and a daily
+-- job keeps the coming year ready. There is deliberately no DEFAULT partition: rows in it
+-- would block creating the matching monthly partition later.
+--
+-- A partitioned table's primary key must include the partition column, so other tables
+-- can no longer hold a foreign key to transactions(id). The former ON DELETE actions and
+-- existence checks are re-implemented with triggers below.
+BEGIN;
+
+ALTER TABLE loan_payments  DROP CONSTRAINT IF EXISTS loan_payments_transaction_id_fkey;
+ALTER TABLE wishlist_items DROP CONSTRAINT IF EXISTS wishlist_items_transaction_id_fkey;
+ALTER TABLE expense_splits DROP CONSTRAINT IF EXISTS expense_splits_transaction_id_fkey;
+
+ALTER TABLE transactions RENAME TO transactions_unpartitioned;
+ALTER INDEX transactions_pkey RENAME TO transactions_unpartitioned_pkey;
+ALTER SEQUENCE transactions_id_seq OWNED BY NONE;
+
+CREATE TABLE transactions (
+    id             BIGINT NOT NULL DEFAULT nextval('transactions_id_seq'),
+    user_id        BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id    BIGINT NULL REFERENCES categories(id) ON DELETE SET NULL,
+    amount         NUMERIC(12,2) NOT NULL CHECK (amount >= 0),
+    type           TEXT NOT NULL CONSTRAINT chk_transactions_type CHECK (type IN ('income','expense')),
+    date           DATE NOT NULL,
+    description    TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    tax_deductible BOOLEAN NULL,
+    reimbursable   BOOLEAN NOT NULL DEFAULT FALSE,
+    claim_id       BIGINT NULL REFERENCES reimbursement_claims(id) ON DELETE SET NULL,
+    reimbursed     BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (id, date)
+) PARTITION BY RANGE (date);
+
+-- Creates the monthly partition covering d unless it already exists.
+-- Returns true when a partition was created.
+CREATE OR REPLACE FUNCTION ensure_transaction_partition(d DATE) RETURNS boolean AS $$
+DECLARE
+    month_start DATE := date_trunc('month', d)::date;
+    part        TEXT := 'transactions_p' || to_char(month_start, 'YYYYMM');
+BEGIN
+    IF to_regclass(part) IS NOT NULL THEN
+        RETURN FALSE;
+    END IF;
+    EXECUTE format('CREATE TABLE %I PARTITION OF transactions FOR VALUES FROM (%L) TO (%L)',
+                   part, month_start, (month_start + INTERVAL '1 month')::date);
+    RETURN TRUE;
+EXCEPTION WHEN duplicate_table THEN
+    -- Created concurrently by another session.
+    RETURN FALSE;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Creates every monthly partition between from_date and to_date (inclusive).
+-- Returns the number of partitions created.
+CREATE OR REPLACE FUNCTION ensure_transaction_partitions(from_date DATE, to_date DATE) RETURNS integer AS $$
+DECLARE
+    m       DATE := date_trunc('month', from_date)::date;
+    created INTEGER := 0;
+BEGIN
+    WHILE m <= to_date LOOP
+        IF ensure_transaction_partition(m) THEN
+            created := created + 1;
+        END IF;
+        m := (m + INTERVAL '1 month')::date;
+    END LOOP;
+    RETURN created;
+END;
+$$ LANGUAGE plpgsql;
+
+SELECT ensure_transaction_partitions(
+    LEAST(CURRENT_DATE, (SELECT MIN(date) FROM transactions_unpartitioned)),
+    GREATEST((CURRENT_DATE + INTERVAL '12 months')::date, (SELECT MAX(date) FROM transactions_unpartitioned)));
+
+-- monthly_totals already reflects these rows; the totals trigger is created afterwards
+-- so the copy does not count them twice.
+INSERT INTO transactions (id, user_id, category_id, amount, type, date, description, created_at,
+                          tax_deductible, reimbursable, claim_id, reimbursed)
+SELECT id, user_id, category_id, amount, type, date, description, created_at,
+       tax_deductible, reimbursable, claim_id, reimbursed
+FROM transactions_unpartitioned;
+
+DROP TABLE transactions_unpartitioned;
+ALTER SEQUENCE transactions_id_seq OWNED BY transactions.id;
+
+CREATE INDEX IF NOT EXISTS idx_tx_user_date     ON transactions(user_id, date);
+CREATE INDEX IF NOT EXISTS idx_tx_user_category ON transactions(user_id, category_id);
+CREATE INDEX IF NOT EXISTS idx_tx_user_type     ON transactions(user_id, type);
+CREATE INDEX IF NOT EXISTS idx_tx_claim         ON transactions(claim_id) WHERE claim_id IS NOT NULL;
+
+CREATE TRIGGER trg_transactions_monthly_totals
+AFTER INSERT OR UPDATE OR DELETE ON transactions
+FOR EACH ROW EXECUTE FUNCTION transactions_monthly_totals();
+
+-- Replaces ON DELETE CASCADE (loan_payments, expense_splits) and ON DELETE SET NULL
+-- (wishlist_items). Changing a transaction's month moves the row to another partition,
+-- which fires DELETE + INSERT; dependents are kept when the id still exists afterwards.
+CREATE OR REPLACE FUNCTION transactions_delete_dependents() RETURNS trigger AS $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM transactions WHERE id = OLD.id) THEN
+        RETURN NULL;
+    END IF;
+    DELETE FROM loan_payments  WHERE transaction_id = OLD.id;
+    DELETE FROM expense_splits WHERE transaction_id = OLD.id;
+    UPDATE wishlist_items SET transaction_id = NULL WHERE transaction_id = OLD.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_transactions_delete_dependents
+AFTER DELETE ON transactions
+FOR EACH ROW EXECUTE FUNCTION transactions_delete_dependents();
+
+-- Replaces the REFERENCES transactions(id) checks on the dependent tables.
+CREATE OR REPLACE FUNCTION check_transaction_ref() RETURNS trigger AS $$
+BEGIN
+    IF NEW.transaction_id IS NOT NULL
+       AND NOT EXISTS (SELECT 1 FROM transactions WHERE id = NEW.transaction_id) THEN
+        RAISE EXCEPTION 'transaction % does not exist', NEW.transaction_id
+            USING ERRCODE = 'foreign_key_violation', TABLE = TG_TABLE_NAME, COLUMN = 'transaction_id';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_loan_payments_transaction_ref
+BEFORE INSERT OR UPDATE OF transaction_id ON loan_payments
+FOR EACH ROW EXECUTE FUNCTION check_transaction_ref();
+CREATE TRIGGER trg_wishlist_items_transaction_ref
+BEFORE INSERT OR UPDATE OF transaction_id ON wishlist_items
+FOR EACH ROW EXECUTE FUNCTION check_transaction_ref();
+CREATE TRIGGER trg_expense_splits_transaction_ref
+BEFORE INSERT OR UPDATE OF transaction_id ON expense_splits
+FOR EACH ROW EXECUTE FUNCTION check_transaction_ref();
+
+COMMIT;
@@ -0,0 +1,141 @@
+-- backend/migrations/025_row_level_security.sql
+-- Row-level security as defense in depth for tenant isolation. Repositories already
+-- scope every query with user_id; these policies make Postgres enforce the same rule,
+-- so a query that forgets the predicate still cannot see or change another user's rows.
+--
+-- The API sets the app.user_id session variable to the authenticated user before each
+-- query (repo.PrepareTenant). When it is unset (migrations, background jobs without an
+-- owner, admin backups, login) the policies allow every row.
+--
+-- Policies are forced on the table owner as well, but superusers and roles with
+-- BYPASSRLS are never subject to them: run the API as an ordinary role for them to apply.
+BEGIN;
+
+-- The current tenant, or NULL when the session is not scoped to a user.
+CREATE OR REPLACE FUNCTION app_user_id() RETURNS BIGINT AS $$
+    SELECT NULLIF(current_setting('app.user_id', true), '')::bigint
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE users FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON users;
+CREATE POLICY tenant_isolation ON users
+    USING (app_user_id() IS NULL OR id = app_user_id());
+
+ALTER TABLE categories ENABLE ROW LEVEL SECURITY;
+ALTER TABLE categories FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON categories;
+CREATE POLICY tenant_isolation ON categories
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE budgets ENABLE ROW LEVEL SECURITY;
+ALTER TABLE budgets FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON budgets;
+CREATE POLICY tenant_isolation ON budgets
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE transactions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE transactions FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON transactions;
+CREATE POLICY tenant_isolation ON transactions
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE user_dashboard ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_dashboard FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON user_dashboard;
+CREATE POLICY tenant_isolation ON user_dashboard
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE monthly_totals ENABLE ROW LEVEL SECURITY;
+ALTER TABLE monthly_totals FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON monthly_totals;
+CREATE POLICY tenant_isolation ON monthly_totals
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE closed_periods ENABLE ROW LEVEL SECURITY;
+ALTER TABLE closed_periods FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON closed_periods;
+CREATE POLICY tenant_isolation ON closed_periods
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE loans ENABLE ROW LEVEL SECURITY;
+ALTER TABLE loans FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON loans;
+CREATE POLICY tenant_isolation ON loans
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE bills ENABLE ROW LEVEL SECURITY;
+ALTER TABLE bills FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON bills;
+CREATE POLICY tenant_isolation ON bills
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE subscriptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE subscriptions FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON subscriptions;
+CREATE POLICY tenant_isolation ON subscriptions
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE wishlist_items ENABLE ROW LEVEL SECURITY;
+ALTER TABLE wishlist_items FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON wishlist_items;
+CREATE POLICY tenant_isolation ON wishlist_items
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE contacts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE contacts FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON contacts;
+CREATE POLICY tenant_isolation ON contacts
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE expense_splits ENABLE ROW LEVEL SECURITY;
+ALTER TABLE expense_splits FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON expense_splits;
+CREATE POLICY tenant_isolation ON expense_splits
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE settlements ENABLE ROW LEVEL SECURITY;
+ALTER TABLE settlements FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON settlements;
+CREATE POLICY tenant_isolation ON settlements
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE reimbursement_claims ENABLE ROW LEVEL SECURITY;
+ALTER TABLE reimbursement_claims FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON reimbursement_claims;
+CREATE POLICY tenant_isolation ON reimbursement_claims
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE emergency_fund_accounts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE emergency_fund_accounts FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON emergency_fund_accounts;
+CREATE POLICY tenant_isolation ON emergency_fund_accounts
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE income_sources ENABLE ROW LEVEL SECURITY;
+ALTER TABLE income_sources FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON income_sources;
+CREATE POLICY tenant_isolation ON income_sources
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+-- System jobs (user_id NULL) may be enqueued while serving a user's request.
+ALTER TABLE jobs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE jobs FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON jobs;
+CREATE POLICY tenant_isolation ON jobs
+    USING (app_user_id() IS NULL OR user_id IS NULL OR user_id = app_user_id());
+
+-- Child tables without a user_id inherit visibility from their (already filtered) parent.
+ALTER TABLE loan_payments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE loan_payments FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON loan_payments;
+CREATE POLICY tenant_isolation ON loan_payments
+    USING (app_user_id() IS NULL OR EXISTS (SELECT 1 FROM loans l WHERE l.id = loan_id));
+
+ALTER TABLE expense_split_shares ENABLE ROW LEVEL SECURITY;
+ALTER TABLE expense_split_shares FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON expense_split_shares;
+CREATE POLICY tenant_isolation ON expense_split_shares
+    USING (app_user_id() IS NULL OR EXISTS (SELECT 1 FROM expense_splits s WHERE s.id = split_id));
+
+COMMIT;
@@ -0,0 +1,28 @@
+-- backend/migrations/026_feature_flags.sql
+-- Feature flags for gradual rollouts. A flag is on for a user when they have an
+-- override, otherwise when it is enabled globally, otherwise when the user falls in
+-- the rollout percentage (a stable hash of flag key and user id).
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS feature_flags (
+    key             TEXT PRIMARY KEY CHECK (key ~ '^[a-z0-9][a-z0-9_.-]*$'),
+    description     TEXT NOT NULL DEFAULT '',
+    enabled         BOOLEAN NOT NULL DEFAULT FALSE,
+    rollout_percent SMALLINT NOT NULL DEFAULT 0 CHECK (rollout_percent BETWEEN 0 AND 100),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS feature_flag_overrides (
+    flag_key TEXT NOT NULL REFERENCES feature_flags(key) ON DELETE CASCADE,
+    user_id  BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    enabled  BOOLEAN NOT NULL,
+    PRIMARY KEY (flag_key, user_id)
+);
+
+ALTER TABLE feature_flag_overrides ENABLE ROW LEVEL SECURITY;
+ALTER TABLE feature_flag_overrides FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON feature_flag_overrides;
+CREATE POLICY tenant_isolation ON feature_flag_overrides
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+COMMIT;
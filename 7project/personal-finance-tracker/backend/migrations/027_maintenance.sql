@@ -0,0 +1,10 @@
+-- backend/migrations/027_maintenance.sql
+-- Operator-controlled maintenance mode, shared by every API instance (single row).
+CREATE TABLE IF NOT EXISTS maintenance (
+    id         BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    enabled    BOOLEAN NOT NULL DEFAULT FALSE,
+    message    TEXT NOT NULL DEFAULT '',
+    until      TIMESTAMPTZ NULL,          -- expected end, sent as Retry-After
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+INSERT INTO maintenance (id) VALUES (TRUE) ON CONFLICT DO NOTHING;
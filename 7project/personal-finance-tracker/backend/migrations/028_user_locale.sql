@@ -0,0 +1,5 @@
+-- backend/migrations/028_user_locale.sql
+-- Preferred language for content produced outside a request (emails, notifications).
+-- NULL means no preference: the default language is used. API responses follow Accept-Language.
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS locale TEXT NULL;
@@ -0,0 +1,128 @@
+-- backend/migrations/029_audit_log.sql
+-- Append-only log of every write to user data and operator settings, recorded by a
+-- generic row trigger so no code path (REST, GraphQL, jobs, admin) can skip it.
+--
+-- The trigger reads who made the change from session variables set by the API before
+-- each query (repo.PrepareTenant): app.actor ("user:42", "admin", "job:<kind>") and
+-- app.request_id. Without app.actor the tenant (app.user_id) is the actor, and without
+-- either the change is attributed to "system" (migrations, psql sessions).
+-- Setting app.audit to 'off' for a transaction suppresses logging (backup restores).
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NULL,              -- owner of the changed row; NULL for operator settings
+    actor      TEXT NOT NULL,
+    entity     TEXT NOT NULL,            -- table name
+    entity_id  TEXT NOT NULL,            -- primary key, multi-column keys joined with ':'
+    action     TEXT NOT NULL CHECK (action IN ('create','update','delete')),
+    before     JSONB NULL,
+    after      JSONB NULL,
+    request_id TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- No foreign key to users: the trail of a deleted account is kept.
+CREATE INDEX IF NOT EXISTS idx_audit_user   ON audit_log(user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_entity ON audit_log(entity, entity_id, id DESC);
+
+ALTER TABLE audit_log ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_log FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON audit_log;
+CREATE POLICY tenant_isolation ON audit_log
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+-- audit_row(entity, key columns, owner)
+-- - entity: name recorded in audit_log (partition triggers fire with the partition's name)
+-- - key columns: comma-separated primary key columns
+-- - owner: column holding the user id, "parent.column" to look it up through a parent
+--   row's user_id, or '' for rows that belong to no user
+CREATE OR REPLACE FUNCTION audit_row() RETURNS trigger AS $$
+DECLARE
+    old_row  JSONB := CASE WHEN TG_OP <> 'INSERT' THEN to_jsonb(OLD) - 'password_hash' END;
+    new_row  JSONB := CASE WHEN TG_OP <> 'DELETE' THEN to_jsonb(NEW) - 'password_hash' END;
+    act      TEXT  := CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END;
+    row_data JSONB := COALESCE(new_row, old_row);
+    key_id   TEXT;
+    owner    BIGINT;
+    moved    TEXT  := TG_ARGV[0] || ':' || (row_data ->> 'id');
+BEGIN
+    IF current_setting('app.audit', true) = 'off' THEN
+        RETURN NULL;
+    END IF;
+    IF TG_OP = 'UPDATE' AND old_row = new_row THEN
+        RETURN NULL;
+    END IF;
+
+    -- An UPDATE moving a row to another partition fires DELETE then INSERT; log it
+    -- once as an update and skip the INSERT that follows.
+    IF TG_OP = 'DELETE' AND TG_TABLE_NAME <> TG_ARGV[0] THEN
+        EXECUTE format('SELECT to_jsonb(t) FROM %I t WHERE id = $1', TG_ARGV[0])
+            INTO new_row USING (old_row ->> 'id')::bigint;
+        IF new_row IS NOT NULL THEN
+            act := 'update';
+            PERFORM set_config('app.audit_moved', moved, true);
+        END IF;
+    ELSIF TG_OP = 'INSERT' AND current_setting('app.audit_moved', true) = moved THEN
+        PERFORM set_config('app.audit_moved', '', true);
+        RETURN NULL;
+    END IF;
+
+    SELECT string_agg(row_data ->> trim(c), ':') INTO key_id
+    FROM unnest(string_to_array(TG_ARGV[1], ',')) AS c;
+
+    IF TG_ARGV[2] LIKE '%.%' THEN
+        EXECUTE format('SELECT user_id FROM %I WHERE id = $1', split_part(TG_ARGV[2], '.', 1))
+            INTO owner USING (row_data ->> split_part(TG_ARGV[2], '.', 2))::bigint;
+        -- The parent is already gone when a cascade removes this row.
+        owner := COALESCE(owner, app_user_id());
+    ELSIF TG_ARGV[2] <> '' THEN
+        owner := (row_data ->> TG_ARGV[2])::bigint;
+    END IF;
+
+    INSERT INTO audit_log (user_id, actor, entity, entity_id, action, before, after, request_id)
+    VALUES (owner,
+            COALESCE(NULLIF(current_setting('app.actor', true), ''),
+                     'user:' || app_user_id(), 'system'),
+            TG_ARGV[0], key_id, act, old_row, new_row,
+            NULLIF(current_setting('app.request_id', true), ''));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+    t RECORD;
+BEGIN
+    FOR t IN SELECT * FROM (VALUES
+        ('users',                   'id',                  'id'),
+        ('categories',              'id',                  'user_id'),
+        ('budgets',                 'id',                  'user_id'),
+        ('user_dashboard',          'user_id',             'user_id'),
+        ('closed_periods',          'user_id,month',       'user_id'),
+        ('reimbursement_claims',    'id',                  'user_id'),
+        ('transactions',            'id',                  'user_id'),
+        ('loans',                   'id',                  'user_id'),
+        ('loan_payments',           'loan_id,transaction_id', 'loans.loan_id'),
+        ('bills',                   'id',                  'user_id'),
+        ('subscriptions',           'id',                  'user_id'),
+        ('wishlist_items',          'id',                  'user_id'),
+        ('contacts',                'id',                  'user_id'),
+        ('expense_splits',          'id',                  'user_id'),
+        ('expense_split_shares',    'split_id,contact_id', 'expense_splits.split_id'),
+        ('settlements',             'id',                  'user_id'),
+        ('emergency_fund_accounts', 'id',                  'user_id'),
+        ('income_sources',          'id',                  'user_id'),
+        ('feature_flags',           'key',                 ''),
+        ('feature_flag_overrides',  'flag_key,user_id',    'user_id'),
+        ('maintenance',             'id',                  '')
+    ) AS v(name, keys, owner)
+    LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS trg_%s_audit ON %I', t.name, t.name);
+        EXECUTE format('CREATE TRIGGER trg_%s_audit AFTER INSERT OR UPDATE OR DELETE ON %I
+                        FOR EACH ROW EXECUTE FUNCTION audit_row(%L, %L, %L)',
+                       t.name, t.name, t.name, t.keys, t.owner);
+    END LOOP;
+END;
+$$;
+
+COMMIT;
@@ -0,0 +1,25 @@
+-- backend/migrations/030_soft_delete.sql
+-- Soft delete for categories and budgets: deleting sets deleted_at, the repositories
+-- skip such rows, and a daily job purges them after SOFT_DELETE_RETENTION.
+-- Historical reports keep showing a deleted category's name until it is purged.
+BEGIN;
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+ALTER TABLE budgets    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ NULL;
+
+-- Names and budget keys only need to be unique among live rows, so a deleted
+-- category or budget can be recreated.
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_user_id_name_key;
+DROP INDEX IF EXISTS ux_categories_user_name;
+CREATE UNIQUE INDEX ux_categories_user_name
+  ON categories (user_id, lower(name)) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS uniq_budgets_user_month_cat;
+CREATE UNIQUE INDEX uniq_budgets_user_month_cat
+  ON budgets (user_id, period_month, COALESCE(category_id, -1)) WHERE deleted_at IS NULL;
+
+-- Purge scans.
+CREATE INDEX IF NOT EXISTS idx_categories_deleted ON categories (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_budgets_deleted    ON budgets (deleted_at)    WHERE deleted_at IS NOT NULL;
+
+COMMIT;
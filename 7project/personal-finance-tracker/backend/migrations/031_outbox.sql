@@ -0,0 +1,55 @@
+-- backend/migrations/031_outbox.sql
+-- Transactional outbox. Every audited change to a user's data (migration 029) also
+-- queues an event here, inside the transaction making the change: a rolled-back write
+-- never emits an event and a committed one always does. The outbox dispatcher
+-- (internal/outbox) delivers events to webhooks, live streams and notifications, and
+-- retries failed deliveries with backoff.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS outbox (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         BIGINT NOT NULL,          -- no foreign key: an account's deletion events still go out
+    topic           TEXT NOT NULL,            -- "<entity>.<created|updated|deleted>", e.g. "transactions.created"
+    payload         JSONB NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    attempts        INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_at       TIMESTAMPTZ,
+    last_error      TEXT,
+    delivered_at    TIMESTAMPTZ,
+    failed_at       TIMESTAMPTZ               -- gave up after the last attempt
+);
+CREATE INDEX IF NOT EXISTS ix_outbox_ready ON outbox(next_attempt_at, id)
+    WHERE delivered_at IS NULL AND failed_at IS NULL;
+CREATE INDEX IF NOT EXISTS ix_outbox_done ON outbox(created_at)
+    WHERE delivered_at IS NOT NULL OR failed_at IS NOT NULL;
+
+ALTER TABLE outbox ENABLE ROW LEVEL SECURITY;
+ALTER TABLE outbox FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON outbox;
+CREATE POLICY tenant_isolation ON outbox
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+-- Account rows and operator settings are not published.
+CREATE OR REPLACE FUNCTION outbox_from_audit() RETURNS trigger AS $$
+BEGIN
+    IF NEW.user_id IS NULL OR NEW.entity = 'users' THEN
+        RETURN NULL;
+    END IF;
+    INSERT INTO outbox (user_id, topic, payload)
+    VALUES (NEW.user_id,
+            NEW.entity || '.' || NEW.action || 'd',
+            jsonb_build_object('entity', NEW.entity, 'entity_id', NEW.entity_id,
+                               'action', NEW.action, 'actor', NEW.actor,
+                               'data', COALESCE(NEW.after, NEW.before),
+                               'request_id', NEW.request_id, 'occurred_at', NEW.created_at));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_log_outbox ON audit_log;
+CREATE TRIGGER trg_audit_log_outbox
+AFTER INSERT ON audit_log
+FOR EACH ROW EXECUTE FUNCTION outbox_from_audit();
+
+COMMIT;
@@ -0,0 +1,59 @@
+-- backend/migrations/032_push_notifications.sql
+-- Alerts (budget thresholds, bill reminders, unusual expenses) and the mobile devices
+-- they are pushed to.
+--   push_devices: FCM or APNs registration tokens of a user's devices
+--   notification_preferences: per-user opt-outs by event and channel; a missing row
+--     means enabled
+--   sent_alerts: alerts already raised, so each is delivered once
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS push_devices (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    platform     TEXT NOT NULL CHECK (platform IN ('fcm', 'apns')),
+    token        TEXT NOT NULL,
+    name         TEXT NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, token)                -- apps unregister on sign-out
+);
+CREATE INDEX IF NOT EXISTS ix_push_devices_token ON push_devices(token);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event   TEXT NOT NULL,                 -- "budget_alert", "bill_reminder", "anomaly"
+    channel TEXT NOT NULL,                 -- "push"
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, event, channel)
+);
+
+CREATE TABLE IF NOT EXISTS sent_alerts (
+    user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    key        TEXT NOT NULL,              -- e.g. "budget:12:100", "bill:5:2026-10-20"
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, key)
+);
+CREATE INDEX IF NOT EXISTS ix_sent_alerts_created ON sent_alerts(created_at);
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['push_devices', 'notification_preferences', 'sent_alerts'] LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+                        USING (app_user_id() IS NULL OR user_id = app_user_id())', t);
+    END LOOP;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS trg_push_devices_audit ON push_devices;
+CREATE TRIGGER trg_push_devices_audit AFTER INSERT OR UPDATE OR DELETE ON push_devices
+FOR EACH ROW EXECUTE FUNCTION audit_row('push_devices', 'id', 'user_id');
+DROP TRIGGER IF EXISTS trg_notification_preferences_audit ON notification_preferences;
+CREATE TRIGGER trg_notification_preferences_audit AFTER INSERT OR UPDATE OR DELETE ON notification_preferences
+FOR EACH ROW EXECUTE FUNCTION audit_row('notification_preferences', 'user_id,event,channel', 'user_id');
+
+COMMIT;
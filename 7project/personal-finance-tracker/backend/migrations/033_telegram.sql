@@ -0,0 +1,41 @@
+-- backend/migrations/033_telegram.sql
+-- Telegram bot: users link their Telegram chat to record expenses by message and
+-- receive alerts there.
+--   telegram_links: the chat linked to each user (one chat per user and vice versa)
+--   telegram_link_codes: short-lived one-time codes the app hands out; sending
+--     "/start <code>" to the bot links the chat
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS telegram_links (
+    user_id   BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    chat_id   BIGINT NOT NULL UNIQUE,
+    username  TEXT NOT NULL DEFAULT '',
+    linked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS telegram_link_codes (
+    code       TEXT PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS ix_telegram_link_codes_user ON telegram_link_codes(user_id);
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['telegram_links', 'telegram_link_codes'] LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+                        USING (app_user_id() IS NULL OR user_id = app_user_id())', t);
+    END LOOP;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS trg_telegram_links_audit ON telegram_links;
+CREATE TRIGGER trg_telegram_links_audit AFTER INSERT OR UPDATE OR DELETE ON telegram_links
+FOR EACH ROW EXECUTE FUNCTION audit_row('telegram_links', 'user_id', 'user_id');
+
+COMMIT;
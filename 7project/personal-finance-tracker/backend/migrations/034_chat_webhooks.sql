@@ -0,0 +1,30 @@
+-- backend/migrations/034_chat_webhooks.sql
+-- Slack and Discord incoming webhooks users add as notification targets. A webhook
+-- the service reports as gone (deleted, channel archived) is disabled with the
+-- reason in last_error until the user updates it.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS chat_webhooks (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind         TEXT NOT NULL CHECK (kind IN ('slack', 'discord')),
+    url          TEXT NOT NULL,
+    name         TEXT NOT NULL DEFAULT '',
+    enabled      BOOLEAN NOT NULL DEFAULT TRUE,
+    last_error   TEXT,
+    last_used_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, url)
+);
+
+ALTER TABLE chat_webhooks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE chat_webhooks FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON chat_webhooks;
+CREATE POLICY tenant_isolation ON chat_webhooks
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+DROP TRIGGER IF EXISTS trg_chat_webhooks_audit ON chat_webhooks;
+CREATE TRIGGER trg_chat_webhooks_audit AFTER INSERT OR UPDATE OR DELETE ON chat_webhooks
+FOR EACH ROW EXECUTE FUNCTION audit_row('chat_webhooks', 'id', 'user_id');
+
+COMMIT;
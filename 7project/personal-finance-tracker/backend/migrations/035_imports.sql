@@ -0,0 +1,36 @@
+-- backend/migrations/035_imports.sql
+-- CSV imports run as background jobs. The uploaded file is kept here until its job
+-- finishes; progress is saved after every row so a retried job resumes where the
+-- previous attempt stopped. Imported transactions are audited individually, so the
+-- import rows themselves are not.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS imports (
+    id               BIGSERIAL PRIMARY KEY,
+    user_id          BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    filename         TEXT NOT NULL DEFAULT '',
+    content          BYTEA,
+    status           TEXT NOT NULL DEFAULT 'queued'
+                     CHECK (status IN ('queued', 'running', 'done', 'failed', 'cancelled')),
+    rows_total       INT NOT NULL DEFAULT 0,
+    rows_processed   INT NOT NULL DEFAULT 0,
+    rows_imported    INT NOT NULL DEFAULT 0,
+    rows_failed      INT NOT NULL DEFAULT 0,
+    errors           JSONB NOT NULL DEFAULT '[]',
+    error            TEXT,
+    cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    job_id           BIGINT REFERENCES jobs(id) ON DELETE SET NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at       TIMESTAMPTZ,
+    finished_at      TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_imports_user ON imports (user_id, created_at DESC);
+
+ALTER TABLE imports ENABLE ROW LEVEL SECURITY;
+ALTER TABLE imports FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON imports;
+CREATE POLICY tenant_isolation ON imports
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+COMMIT;
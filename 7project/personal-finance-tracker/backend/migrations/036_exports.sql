@@ -0,0 +1,31 @@
+-- backend/migrations/036_exports.sql
+-- Exports (a user's full data archive, a year of transactions as XLSX) are built by
+-- background jobs and kept here until downloaded through a signed, expiring URL.
+-- Finished exports are deleted after a day.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS exports (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind         TEXT NOT NULL CHECK (kind IN ('archive', 'xlsx')),
+    year         INT,
+    status       TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'done', 'failed')),
+    filename     TEXT,
+    content_type TEXT,
+    content      BYTEA,
+    size         BIGINT NOT NULL DEFAULT 0,
+    error        TEXT,
+    job_id       BIGINT REFERENCES jobs(id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_exports_created ON exports (created_at);
+
+ALTER TABLE exports ENABLE ROW LEVEL SECURITY;
+ALTER TABLE exports FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON exports;
+CREATE POLICY tenant_isolation ON exports
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- backend/migrations/037_export_blobs.sql
+-- Export files move from the exports table to blob storage (local directory or S3);
+-- the row keeps only the key. Exports still in the table are dropped with their
+-- content, like expired ones; users request them again.
+BEGIN;
+
+DELETE FROM exports WHERE content IS NOT NULL;
+ALTER TABLE exports DROP COLUMN IF EXISTS content;
+ALTER TABLE exports ADD COLUMN IF NOT EXISTS blob_key TEXT;
+
+COMMIT;
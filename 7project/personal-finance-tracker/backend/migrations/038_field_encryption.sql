@@ -0,0 +1,24 @@
+-- backend/migrations/038_field_encryption.sql
+-- Optional encryption of transaction descriptions at rest (ENCRYPTION_KEY or
+-- KMS_KEY_ID). Each user gets a random data key, stored only wrapped by the master
+-- key. Encrypted descriptions can no longer be compared in SQL, so a keyed hash of
+-- the lower-cased text (a blind index) is stored next to them for exact matches.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_keys (
+    user_id     BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    wrapped_key BYTEA NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE user_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_keys FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON user_keys;
+CREATE POLICY tenant_isolation ON user_keys
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS description_hash TEXT;
+CREATE INDEX IF NOT EXISTS idx_tx_description_hash ON transactions (user_id, description_hash)
+    WHERE description_hash IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,26 @@
+-- backend/migrations/039_api_usage.sql
+-- Daily API usage per user: requests, failed requests and bytes in and out, so users
+-- can see what their scripts cost and operators can spot abusive clients. The API
+-- counts in memory and adds its counts here about once a minute; days older than a
+-- year are deleted.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS api_usage (
+    user_id   BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day       DATE NOT NULL,
+    requests  BIGINT NOT NULL DEFAULT 0,
+    errors    BIGINT NOT NULL DEFAULT 0,
+    bytes_in  BIGINT NOT NULL DEFAULT 0,
+    bytes_out BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_usage_day ON api_usage (day);
+
+ALTER TABLE api_usage ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_usage FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON api_usage;
+CREATE POLICY tenant_isolation ON api_usage
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+COMMIT;
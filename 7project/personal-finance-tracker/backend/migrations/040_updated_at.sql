@@ -0,0 +1,43 @@
+-- backend/migrations/040_updated_at.sql
+-- updated_at on every user-editable entity, maintained by a row trigger so no code
+-- path can forget it. Updates that change nothing keep the old value. Inserts keep
+-- an updated_at they bring along (backup restores) and otherwise get the current
+-- time, so dumps taken before this migration still restore.
+-- Rows existing before this migration carry the time it ran.
+BEGIN;
+
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        NEW.updated_at := COALESCE(NEW.updated_at, NOW());
+    ELSIF NEW IS DISTINCT FROM OLD THEN
+        NEW.updated_at := NOW();
+    END IF;
+    RETURN NEW;
+END;
+$$;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'users', 'categories', 'budgets', 'user_dashboard', 'reimbursement_claims', 'transactions',
+        'loans', 'bills', 'subscriptions', 'wishlist_items', 'contacts', 'expense_splits',
+        'settlements', 'emergency_fund_accounts', 'income_sources', 'chat_webhooks'
+    ]
+    LOOP
+        -- NOW() is stable, so the default is stored once instead of rewriting the table.
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()', t);
+        EXECUTE format('DROP TRIGGER IF EXISTS trg_%s_updated_at ON %I', t, t);
+        EXECUTE format('CREATE TRIGGER trg_%s_updated_at BEFORE INSERT OR UPDATE ON %I
+                        FOR EACH ROW EXECUTE FUNCTION set_updated_at()', t, t);
+    END LOOP;
+END;
+$$;
+
+-- "Recently modified" views and sync read the newest changes first.
+CREATE INDEX IF NOT EXISTS idx_tx_user_updated ON transactions (user_id, updated_at);
+
+COMMIT;
@@ -0,0 +1,37 @@
+-- backend/migrations/041_recurring_rules.sql
+-- Recurring transactions (rent, salary, savings transfers): a rule repeats a transaction
+-- every rule_interval from start_date until end_date. Rules start inactive so the
+-- schedule can be previewed first; once active, a daily job creates the transactions
+-- that have fallen due. generated_through is the last date already generated, so
+-- pausing and resuming a rule never creates an occurrence twice.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS recurring_rules (
+    id                BIGSERIAL PRIMARY KEY,
+    user_id           BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id       BIGINT NULL REFERENCES categories(id) ON DELETE SET NULL,
+    type              TEXT NOT NULL CHECK (type IN ('income','expense')),
+    amount            NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    description       TEXT NOT NULL DEFAULT '',
+    rule_interval     TEXT NOT NULL CHECK (rule_interval IN ('weekly','biweekly','monthly','quarterly','yearly')),
+    start_date        DATE NOT NULL,
+    end_date          DATE NULL CHECK (end_date IS NULL OR end_date >= start_date),
+    active            BOOLEAN NOT NULL DEFAULT FALSE,
+    generated_through DATE NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_recurring_rules_user ON recurring_rules(user_id);
+CREATE INDEX IF NOT EXISTS idx_recurring_rules_active ON recurring_rules(id) WHERE active;
+
+DROP TRIGGER IF EXISTS trg_recurring_rules_updated_at ON recurring_rules;
+CREATE TRIGGER trg_recurring_rules_updated_at BEFORE INSERT OR UPDATE ON recurring_rules
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+ALTER TABLE recurring_rules ENABLE ROW LEVEL SECURITY;
+ALTER TABLE recurring_rules FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON recurring_rules;
+CREATE POLICY tenant_isolation ON recurring_rules
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+COMMIT;
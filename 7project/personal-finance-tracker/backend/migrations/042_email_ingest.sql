@@ -0,0 +1,53 @@
+-- backend/migrations/042_email_ingest.sql
+-- Email-in: users forward receipts and order confirmations to their own ingest address
+-- (<token>@INGEST_DOMAIN); the inbound-mail webhook turns each into a draft transaction
+-- that only becomes a real one once the user approves it.
+--   ingest_addresses: the token of each user's address; rotating it retires the old one
+--   draft_transactions: transactions awaiting review. source names where a draft came
+--     from and external_id its identity there (the Message-ID for email), so a
+--     redelivered message does not create a second draft.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS ingest_addresses (
+    user_id    BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token      TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS draft_transactions (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source      TEXT NOT NULL,
+    external_id TEXT NULL,
+    category_id BIGINT NULL REFERENCES categories(id) ON DELETE SET NULL,
+    type        TEXT NOT NULL CHECK (type IN ('income','expense')),
+    amount      NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    date        DATE NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    note        TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_draft_transactions_user ON draft_transactions(user_id, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_draft_transactions_external
+    ON draft_transactions(user_id, source, external_id) WHERE external_id IS NOT NULL;
+
+DROP TRIGGER IF EXISTS trg_draft_transactions_updated_at ON draft_transactions;
+CREATE TRIGGER trg_draft_transactions_updated_at BEFORE INSERT OR UPDATE ON draft_transactions
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['ingest_addresses', 'draft_transactions'] LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+                        USING (app_user_id() IS NULL OR user_id = app_user_id())', t);
+    END LOOP;
+END;
+$$;
+
+COMMIT;
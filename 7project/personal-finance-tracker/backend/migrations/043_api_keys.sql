@@ -0,0 +1,24 @@
+-- backend/migrations/043_api_keys.sql
+-- API keys let automation platforms (Zapier, IFTTT) call the API on a user's behalf
+-- without their password. Only a SHA-256 hash of each key is stored; prefix is the
+-- key's first characters, shown so users can tell their keys apart.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name         TEXT NOT NULL,
+    prefix       TEXT NOT NULL,
+    key_hash     TEXT NOT NULL UNIQUE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ NULL
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys(user_id);
+
+ALTER TABLE api_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON api_keys;
+CREATE POLICY tenant_isolation ON api_keys
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- backend/migrations/044_draft_imports.sql
+-- Imports can put their rows into the drafts inbox (draft_transactions, migration 042)
+-- instead of creating transactions, so a bank export is reviewed before it counts.
+BEGIN;
+
+ALTER TABLE imports ADD COLUMN IF NOT EXISTS as_drafts BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
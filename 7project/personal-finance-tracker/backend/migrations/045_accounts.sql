@@ -0,0 +1,33 @@
+-- backend/migrations/045_accounts.sql
+-- Accounts: the bank accounts, cards and wallets money moves through. A transaction may
+-- name the account it was paid from or into (account_id, optional so existing rows and
+-- clients keep working); an account's balance on any day is its opening balance plus
+-- the income minus the expenses booked to it up to that day.
+--   accounts.opening_balance: the balance before the first booked transaction
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS accounts (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name            TEXT NOT NULL,
+    opening_balance NUMERIC(12,2) NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_accounts_user ON accounts(user_id);
+
+DROP TRIGGER IF EXISTS trg_accounts_updated_at ON accounts;
+CREATE TRIGGER trg_accounts_updated_at BEFORE INSERT OR UPDATE ON accounts
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+ALTER TABLE accounts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE accounts FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON accounts;
+CREATE POLICY tenant_isolation ON accounts
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS account_id BIGINT NULL REFERENCES accounts(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_transactions_account ON transactions(account_id, date) WHERE account_id IS NOT NULL;
+
+COMMIT;
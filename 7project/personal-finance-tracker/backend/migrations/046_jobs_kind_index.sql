@@ -0,0 +1,8 @@
+-- backend/migrations/046_jobs_kind_index.sql
+-- The scheduler looks up the latest job of each scheduled kind on start-up and for the
+-- admin jobs status; without this index that walks the whole (never pruned) jobs table.
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS ix_jobs_kind ON jobs(kind, id DESC);
+
+COMMIT;
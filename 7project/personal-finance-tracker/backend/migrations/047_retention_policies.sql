@@ -0,0 +1,34 @@
+-- backend/migrations/047_retention_policies.sql
+-- Retention policies: how long data of a kind (target) is kept before a daily job
+-- deletes it. Rows with a user_id are the user's own rules; rows without one are
+-- operator rules applying to every user. Both apply, so a user can only shorten what
+-- the operator keeps.
+--   target: "trash" (soft-deleted categories and budgets), "drafts" (unapproved draft
+--     transactions), "transactions" (by transaction date) or, for operators only,
+--     "jobs" (finished background jobs)
+--   keep_days: age in days beyond which rows are deleted
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS retention_policies (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target     TEXT NOT NULL CHECK (target IN ('trash', 'drafts', 'transactions', 'jobs')),
+    keep_days  INTEGER NOT NULL CHECK (keep_days > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (target <> 'jobs' OR user_id IS NULL)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_retention_policies_target
+    ON retention_policies(COALESCE(user_id, 0), target);
+
+DROP TRIGGER IF EXISTS trg_retention_policies_updated_at ON retention_policies;
+CREATE TRIGGER trg_retention_policies_updated_at BEFORE INSERT OR UPDATE ON retention_policies
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+ALTER TABLE retention_policies ENABLE ROW LEVEL SECURITY;
+ALTER TABLE retention_policies FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON retention_policies;
+CREATE POLICY tenant_isolation ON retention_policies
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- backend/migrations/048_api_key_scopes.sql
+-- API keys carry scopes (transactions:read, budgets:write, reports:read, ...) and can
+-- call the regular API routes those scopes cover, not just the Zapier triggers.
+-- Existing keys keep exactly the access they had: the two triggers.
+BEGIN;
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT '{}';
+UPDATE api_keys SET scopes = ARRAY['transactions:read', 'budgets:read'] WHERE scopes = '{}';
+
+COMMIT;
@@ -0,0 +1,70 @@
+-- backend/migrations/049_oauth.sql
+-- OAuth2 authorization-code flow for third-party apps. A user registers an app
+-- (oauth_clients), another user approves it on a consent screen, receiving a
+-- single-use code (oauth_codes), and the app exchanges the code for an access and a
+-- refresh token (oauth_tokens) limited to the approved scopes. As with API keys, only
+-- SHA-256 hashes of secrets, codes and tokens are stored.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS oauth_clients (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name          TEXT NOT NULL,
+    client_id     TEXT NOT NULL UNIQUE,
+    secret_hash   TEXT NOT NULL,
+    redirect_uris TEXT[] NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_oauth_clients_user ON oauth_clients(user_id);
+
+-- code_challenge is set when the app uses PKCE (S256); the exchange then needs the verifier.
+CREATE TABLE IF NOT EXISTS oauth_codes (
+    code_hash      TEXT PRIMARY KEY,
+    client_id      BIGINT NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+    user_id        BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    redirect_uri   TEXT NOT NULL,
+    scopes         TEXT[] NOT NULL,
+    code_challenge TEXT NULL,
+    expires_at     TIMESTAMPTZ NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One row per grant; refreshing rotates both hashes in place.
+CREATE TABLE IF NOT EXISTS oauth_tokens (
+    id                BIGSERIAL PRIMARY KEY,
+    client_id         BIGINT NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+    user_id           BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scopes            TEXT[] NOT NULL,
+    access_hash       TEXT NOT NULL UNIQUE,
+    refresh_hash      TEXT NOT NULL UNIQUE,
+    access_expires_at TIMESTAMPTZ NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_used_at      TIMESTAMPTZ NULL
+);
+CREATE INDEX IF NOT EXISTS idx_oauth_tokens_user ON oauth_tokens(user_id, client_id);
+
+DROP TRIGGER IF EXISTS trg_oauth_clients_updated_at ON oauth_clients;
+CREATE TRIGGER trg_oauth_clients_updated_at BEFORE INSERT OR UPDATE ON oauth_clients
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- The authorize and token endpoints look clients up by client_id without a tenant.
+ALTER TABLE oauth_clients ENABLE ROW LEVEL SECURITY;
+ALTER TABLE oauth_clients FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON oauth_clients;
+CREATE POLICY tenant_isolation ON oauth_clients
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE oauth_codes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE oauth_codes FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON oauth_codes;
+CREATE POLICY tenant_isolation ON oauth_codes
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE oauth_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE oauth_tokens FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON oauth_tokens;
+CREATE POLICY tenant_isolation ON oauth_tokens
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+COMMIT;
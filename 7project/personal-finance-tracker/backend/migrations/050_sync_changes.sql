@@ -0,0 +1,65 @@
+-- backend/migrations/050_sync_changes.sql
+-- Change log for delta sync (GET /api/sync): one row per synced row, restamped on
+-- every write, so offline clients can fetch what changed since their last sync.
+-- Deleting a row, or soft-deleting a category or budget, turns its entry into a
+-- tombstone. Unlike the audit log this cannot be switched off, so deletions by the
+-- retention job and backup restores reach clients too.
+--
+-- seq orders changes; xid is the writing transaction, which lets readers skip changes
+-- of transactions still open when they read (see repo.SyncRepo.Changes).
+-- No foreign key to users, as with the audit log: a restore deletes the user and their
+-- rows in one statement, and those deletions are recorded as tombstones.
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS sync_seq;
+
+CREATE TABLE IF NOT EXISTS sync_changes (
+    entity    TEXT NOT NULL,
+    entity_id BIGINT NOT NULL,
+    user_id   BIGINT NOT NULL,
+    deleted   BOOLEAN NOT NULL,
+    seq       BIGINT NOT NULL DEFAULT nextval('sync_seq'),
+    xid       XID8 NOT NULL DEFAULT pg_current_xact_id(),
+    PRIMARY KEY (entity, entity_id)
+);
+CREATE INDEX IF NOT EXISTS idx_sync_changes_user ON sync_changes(user_id, seq);
+
+ALTER TABLE sync_changes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE sync_changes FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON sync_changes;
+CREATE POLICY tenant_isolation ON sync_changes
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+-- sync_change(entity): records a write to a table with id, user_id and optionally deleted_at.
+CREATE OR REPLACE FUNCTION sync_change() RETURNS trigger AS $$
+DECLARE
+    row_data JSONB := CASE WHEN TG_OP = 'DELETE' THEN to_jsonb(OLD) ELSE to_jsonb(NEW) END;
+BEGIN
+    INSERT INTO sync_changes (entity, entity_id, user_id, deleted)
+    VALUES (TG_ARGV[0], (row_data ->> 'id')::bigint, (row_data ->> 'user_id')::bigint,
+            TG_OP = 'DELETE' OR row_data ->> 'deleted_at' IS NOT NULL)
+    ON CONFLICT (entity, entity_id) DO UPDATE
+        SET user_id = EXCLUDED.user_id, deleted = EXCLUDED.deleted,
+            seq = nextval('sync_seq'), xid = pg_current_xact_id();
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['categories', 'budgets', 'accounts', 'transactions']
+    LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS trg_%s_sync ON %I', t, t);
+        EXECUTE format('CREATE TRIGGER trg_%s_sync AFTER INSERT OR UPDATE OR DELETE ON %I
+                        FOR EACH ROW EXECUTE FUNCTION sync_change(%L)', t, t, t);
+        -- Existing rows are the first changes, so a client's first sync gets them all.
+        EXECUTE format('INSERT INTO sync_changes (entity, entity_id, user_id, deleted)
+                        SELECT %L, id, user_id, %s FROM %I ON CONFLICT DO NOTHING',
+                       t, CASE WHEN t IN ('categories', 'budgets') THEN 'deleted_at IS NOT NULL' ELSE 'FALSE' END, t);
+    END LOOP;
+END;
+$$;
+
+COMMIT;
@@ -0,0 +1,84 @@
+-- backend/migrations/051_webhooks.sql
+-- Webhook endpoints integrators register to receive change events (the outbox topics
+-- of migration 031). Each event is fanned out into one delivery per matching endpoint
+-- (webhook_deliveries), retried on its own with exponential backoff, so a failing
+-- endpoint neither holds up nor duplicates events for the others. A delivery that
+-- exhausts its attempts becomes a dead letter until it is replayed or cleaned up.
+-- Every attempt is logged (webhook_attempts) with the endpoint's answer.
+-- The signing secret is kept in clear: deliveries are signed with it (HMAC-SHA256).
+-- Endpoints are not audited, which would copy the secret into the audit log and
+-- publish it as an event.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url         TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    secret      TEXT NOT NULL,
+    topics      TEXT[] NOT NULL DEFAULT '{}', -- empty: every topic
+    enabled     BOOLEAN NOT NULL DEFAULT TRUE, -- disabled endpoints keep their pending deliveries
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_user ON webhook_endpoints(user_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              BIGSERIAL PRIMARY KEY,
+    endpoint_id     BIGINT NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    user_id         BIGINT NOT NULL,
+    event_id        BIGINT NOT NULL,          -- outbox id; no foreign key, the outbox is cleaned up sooner
+    topic           TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts        INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_at       TIMESTAMPTZ,
+    last_error      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at    TIMESTAMPTZ,
+    UNIQUE (endpoint_id, event_id)
+);
+CREATE INDEX IF NOT EXISTS ix_webhook_deliveries_ready ON webhook_deliveries(next_attempt_at, id)
+    WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS ix_webhook_deliveries_endpoint ON webhook_deliveries(endpoint_id, id);
+CREATE INDEX IF NOT EXISTS ix_webhook_deliveries_dead ON webhook_deliveries(user_id, id)
+    WHERE status = 'dead';
+
+CREATE TABLE IF NOT EXISTS webhook_attempts (
+    id            BIGSERIAL PRIMARY KEY,
+    delivery_id   BIGINT NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    user_id       BIGINT NOT NULL,
+    attempt       INT NOT NULL,
+    status_code   INT,                         -- NULL: no answer (network error, timeout)
+    error         TEXT,
+    response_body TEXT NOT NULL DEFAULT '',    -- first 1 KiB
+    duration_ms   INT NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS ix_webhook_attempts_delivery ON webhook_attempts(delivery_id, id);
+
+DROP TRIGGER IF EXISTS trg_webhook_endpoints_updated_at ON webhook_endpoints;
+CREATE TRIGGER trg_webhook_endpoints_updated_at BEFORE INSERT OR UPDATE ON webhook_endpoints
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- The delivery worker claims deliveries of every user without a tenant.
+ALTER TABLE webhook_endpoints ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_endpoints FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON webhook_endpoints;
+CREATE POLICY tenant_isolation ON webhook_endpoints
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE webhook_deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON webhook_deliveries;
+CREATE POLICY tenant_isolation ON webhook_deliveries
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+ALTER TABLE webhook_attempts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_attempts FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON webhook_attempts;
+CREATE POLICY tenant_isolation ON webhook_attempts
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+COMMIT;
@@ -0,0 +1,47 @@
+-- backend/migrations/052_category_groups.sql
+-- Category groups gather categories under one name (e.g. "Lifestyle" for dining out,
+-- entertainment and shopping); a category belongs to at most one group. A budget may
+-- target a group instead of a category, and then counts the expenses of every member
+-- category, as they are grouped now. Deleting a group ungroups its categories; it is
+-- refused while live budgets target it, and its deleted budgets go with it.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS category_groups (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_category_groups_user_name ON category_groups(user_id, lower(name));
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS group_id BIGINT NULL
+    REFERENCES category_groups(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_categories_group ON categories(group_id) WHERE group_id IS NOT NULL;
+
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS group_id BIGINT NULL
+    REFERENCES category_groups(id) ON DELETE CASCADE;
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_category_or_group;
+ALTER TABLE budgets ADD CONSTRAINT budgets_category_or_group
+    CHECK (category_id IS NULL OR group_id IS NULL);
+
+-- One live budget per month and target: a category, a group, or everything.
+DROP INDEX IF EXISTS uniq_budgets_user_month_cat;
+CREATE UNIQUE INDEX uniq_budgets_user_month_cat
+  ON budgets (user_id, period_month, COALESCE(category_id, -1), COALESCE(group_id, -1)) WHERE deleted_at IS NULL;
+
+DROP TRIGGER IF EXISTS trg_category_groups_updated_at ON category_groups;
+CREATE TRIGGER trg_category_groups_updated_at BEFORE INSERT OR UPDATE ON category_groups
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+DROP TRIGGER IF EXISTS trg_category_groups_audit ON category_groups;
+CREATE TRIGGER trg_category_groups_audit AFTER INSERT OR UPDATE OR DELETE ON category_groups
+    FOR EACH ROW EXECUTE FUNCTION audit_row('category_groups', 'id', 'user_id');
+
+ALTER TABLE category_groups ENABLE ROW LEVEL SECURITY;
+ALTER TABLE category_groups FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON category_groups;
+CREATE POLICY tenant_isolation ON category_groups
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+COMMIT;
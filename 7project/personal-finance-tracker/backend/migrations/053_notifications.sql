@@ -0,0 +1,30 @@
+-- backend/migrations/053_notifications.sql
+-- The in-app notification center: every alert (budgets, bills, unusual expenses,
+-- weekly summaries, finished imports) is also filed here by the "inapp" channel, so
+-- clients have one place to list them and count the unread ones. Alerts are keyed
+-- like sent_alerts, so a retried delivery files a notification once.
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event      TEXT NOT NULL,              -- alerts.Events, e.g. "budget_alert"
+    key        TEXT NOT NULL,              -- the alert's key, e.g. "bill:5:2026-10-20"
+    title      TEXT NOT NULL,
+    body       TEXT NOT NULL,
+    data       JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    read_at    TIMESTAMPTZ NULL,
+    UNIQUE (user_id, key)
+);
+CREATE INDEX IF NOT EXISTS ix_notifications_user_created ON notifications(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS ix_notifications_unread ON notifications(user_id, event) WHERE read_at IS NULL;
+CREATE INDEX IF NOT EXISTS ix_notifications_created ON notifications(created_at);
+
+ALTER TABLE notifications ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notifications FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON notifications;
+CREATE POLICY tenant_isolation ON notifications
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+COMMIT;
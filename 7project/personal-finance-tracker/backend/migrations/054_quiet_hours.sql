@@ -0,0 +1,26 @@
+-- backend/migrations/054_quiet_hours.sql
+-- Quiet hours: a daily window, in the user's time zone, during which alerts are not
+-- pushed, mailed or posted to chats; their deliveries wait until the window ends.
+-- The in-app notification center is filed right away. A user without a row has none.
+-- The window may wrap midnight (start 22:00, end 07:00).
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS notification_quiet_hours (
+    user_id      BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    start_minute SMALLINT NOT NULL CHECK (start_minute BETWEEN 0 AND 1439),
+    end_minute   SMALLINT NOT NULL CHECK (end_minute BETWEEN 0 AND 1439),
+    timezone     TEXT NOT NULL DEFAULT 'UTC',  -- IANA name, e.g. "Europe/Berlin"
+    CHECK (start_minute <> end_minute)
+);
+
+ALTER TABLE notification_quiet_hours ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notification_quiet_hours FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON notification_quiet_hours;
+CREATE POLICY tenant_isolation ON notification_quiet_hours
+    USING (app_user_id() IS NULL OR user_id = app_user_id());
+
+DROP TRIGGER IF EXISTS trg_notification_quiet_hours_audit ON notification_quiet_hours;
+CREATE TRIGGER trg_notification_quiet_hours_audit AFTER INSERT OR UPDATE OR DELETE ON notification_quiet_hours
+FOR EACH ROW EXECUTE FUNCTION audit_row('notification_quiet_hours', 'user_id', 'user_id');
+
+COMMIT;
@@ -0,0 +1,35 @@
+-- backend/migrations/055_base_currency.sql
+-- Amounts are recorded in the user's base currency. Reports may be shown in another
+-- display currency: each transaction is converted at the rate of its own date, so a
+-- past month keeps the value it had then instead of moving with today's rate.
+-- fx_rate answers that rate from exchange_rates (see migration 021).
+BEGIN;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS base_currency CHAR(3) NOT NULL DEFAULT 'EUR';
+
+-- Cross rates look rows up by quote currency regardless of the base they were stored under.
+CREATE INDEX IF NOT EXISTS idx_exchange_rates_quote_date ON exchange_rates(quote, date DESC);
+
+-- fx_rate returns how much of p_to one unit of p_from buys on p_on: the rates of the
+-- latest day on or before p_on, or of the earliest day after it when none are older.
+-- Pairs are resolved directly, inverted, or crossed through the base a day was stored
+-- under. NULL when no stored day covers both currencies.
+CREATE OR REPLACE FUNCTION fx_rate(p_from CHAR(3), p_to CHAR(3), p_on DATE) RETURNS NUMERIC AS $$
+    WITH pairs AS (
+        SELECT date, rate FROM exchange_rates WHERE base = p_from AND quote = p_to
+        UNION ALL
+        SELECT date, 1 / rate FROM exchange_rates WHERE base = p_to AND quote = p_from
+        UNION ALL
+        SELECT t.date, t.rate / f.rate
+        FROM exchange_rates f
+        JOIN exchange_rates t ON t.date = f.date AND t.base = f.base
+        WHERE f.quote = p_from AND t.quote = p_to
+    )
+    SELECT CASE WHEN p_from = p_to THEN 1 ELSE (
+        SELECT rate FROM pairs
+        ORDER BY date > p_on, CASE WHEN date <= p_on THEN p_on - date ELSE date - p_on END
+        LIMIT 1
+    ) END
+$$ LANGUAGE sql STABLE;
+
+COMMIT;
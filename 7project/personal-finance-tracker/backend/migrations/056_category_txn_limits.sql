@@ -0,0 +1,15 @@
+-- backend/migrations/056_category_txn_limits.sql
+-- Per-transaction limits on categories, independent of monthly budgets: a single
+-- expense above txn_limit is flagged ("warn") or refused unless explicitly overridden
+-- ("block") when it is entered through the API. Both columns are set or neither is.
+BEGIN;
+
+ALTER TABLE categories
+    ADD COLUMN IF NOT EXISTS txn_limit      NUMERIC(12,2) NULL CHECK (txn_limit > 0),
+    ADD COLUMN IF NOT EXISTS txn_limit_mode TEXT NULL CHECK (txn_limit_mode IN ('warn', 'block'));
+
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_txn_limit_pair;
+ALTER TABLE categories ADD CONSTRAINT categories_txn_limit_pair
+    CHECK ((txn_limit IS NULL) = (txn_limit_mode IS NULL));
+
+COMMIT;
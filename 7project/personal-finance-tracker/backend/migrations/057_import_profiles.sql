@@ -0,0 +1,9 @@
+-- backend/migrations/057_import_profiles.sql
+-- A CSV import remembers the regional format it was uploaded in (a statement profile
+-- such as "eu" for 31.12.2026 and 1.234,56), so the job reads the file the way the
+-- upload was validated. Empty means the canonical YYYY-MM-DD / decimal point format.
+BEGIN;
+
+ALTER TABLE imports ADD COLUMN IF NOT EXISTS profile TEXT NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- backend/migrations/058_user_transaction_retention.sql
+-- Transaction retention is a user's own choice: an operator policy would delete every
+-- user's history, so "transactions" policies now require a user_id and existing
+-- operator ones are dropped.
+BEGIN;
+
+DELETE FROM retention_policies WHERE user_id IS NULL AND target = 'transactions';
+
+ALTER TABLE retention_policies DROP CONSTRAINT IF EXISTS retention_policies_transactions_user;
+ALTER TABLE retention_policies ADD CONSTRAINT retention_policies_transactions_user
+    CHECK (target <> 'transactions' OR user_id IS NOT NULL);
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- backend/migrations/059_transaction_recurring_rule.sql
+-- Transactions remember the recurring rule that generated them (recurring_rule_id), so
+-- the end-of-month projection can keep fixed costs out of the average daily spend and
+-- project the rule's coming occurrences instead. Transactions generated before this
+-- migration stay unmarked.
+BEGIN;
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS recurring_rule_id BIGINT NULL REFERENCES recurring_rules(id) ON DELETE SET NULL;
+
+COMMIT;
@@ -0,0 +1,51 @@
+-- backend/migrations/060_closed_period_trigger.sql
+-- Closed months are enforced by Postgres. The repos check closed_periods before each
+-- write, but apart from it, so a transaction could still land in a month closed in
+-- between. The trigger locks the user row (FOR KEY SHARE) before it reads
+-- closed_periods; closing a month locks the same row FOR UPDATE, so it waits for the
+-- writes in flight, and writes after it wait for the close and then see it.
+--
+-- Only changes to what totals and reports read are refused: descriptions may still be
+-- re-encrypted, and a category or account deleted elsewhere is still cleared. Setting
+-- app.closed_periods to 'off' for a transaction lifts the check (backup restores,
+-- which re-insert a user's closed months).
+BEGIN;
+
+CREATE OR REPLACE FUNCTION transactions_check_closed_period() RETURNS trigger AS $$
+DECLARE
+    uid    BIGINT;
+    months DATE[];
+BEGIN
+    IF current_setting('app.closed_periods', true) = 'off' THEN
+        RETURN COALESCE(NEW, OLD);
+    END IF;
+    IF TG_OP = 'UPDATE'
+       AND (NEW.amount, NEW.type, NEW.date, NEW.tax_deductible, NEW.reimbursable, NEW.reimbursed)
+           IS NOT DISTINCT FROM (OLD.amount, OLD.type, OLD.date, OLD.tax_deductible, OLD.reimbursable, OLD.reimbursed)
+       AND (NEW.category_id IS NULL OR NEW.category_id IS NOT DISTINCT FROM OLD.category_id) THEN
+        RETURN NEW;
+    END IF;
+
+    IF TG_OP <> 'DELETE' THEN
+        uid := NEW.user_id;
+        months := months || date_trunc('month', NEW.date)::date;
+    END IF;
+    IF TG_OP <> 'INSERT' THEN
+        uid := OLD.user_id;
+        months := months || date_trunc('month', OLD.date)::date;
+    END IF;
+    PERFORM 1 FROM users WHERE id = uid FOR KEY SHARE;
+    IF EXISTS (SELECT 1 FROM closed_periods WHERE user_id = uid AND month = ANY(months)) THEN
+        RAISE EXCEPTION 'transactions of user % in a closed month cannot be changed', uid
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'closed_period', TABLE = TG_TABLE_NAME;
+    END IF;
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_transactions_closed_period ON transactions;
+CREATE TRIGGER trg_transactions_closed_period
+BEFORE INSERT OR UPDATE OR DELETE ON transactions
+FOR EACH ROW EXECUTE FUNCTION transactions_check_closed_period();
+
+COMMIT;
@@ -22,7 +22,9 @@ async function raw(path: string, opts: RequestInit = {}): Promise<Response> {
 
   let res: Response;
   try {
-    res = await fetch(`/api${path}`, { ...opts, headers });
+    // Revalidate rather than reuse the browser's copy, so lists reflect our own writes
+    // within the API's private max-age.
+    res = await fetch(`/api${path}`, { cache: "no-cache", ...opts, headers });
   } catch (e: any) {
     // Network-level failure (DNS, CORS, server down, offline, etc.).
     throw new Error(e?.message || "network_error");
@@ -41,7 +43,8 @@ async function raw(path: string, opts: RequestInit = {}): Promise<Response> {
  * Strict API call:
  * Behavior:
  * - 2xx → returns parsed JSON (or `undefined` for 204)
- * - 4xx/5xx → throws Error using server-provided {error|message} when present
+ * - 4xx/5xx → throws Error using server-provided {error|message} when present,
+ *   with the problem `code` and HTTP `status` attached
  */
 export async function api<T = any>(path: string, opts: RequestInit = {}): Promise<T> {
   const res = await raw(path, opts);
@@ -57,8 +60,9 @@ export async function api<T = any>(path: string, opts: RequestInit = {}): Promis
     } catch {
       /* ignore non-JSON body */
     }
-    const msg = body?.error || body?.message || `HTTP ${res.status}`;
-    throw new Error(msg);
+    const msg = body?.detail || body?.error || body?.message || `HTTP ${res.status}`;
+    // Keep the problem code and status so callers can react to specific errors.
+    throw Object.assign(new Error(msg), { code: body?.code as string | undefined, status: res.status });
   }
 
   // Parse JSON body; tolerate accidental empty bodies on 200.
@@ -87,7 +91,7 @@ export async function apiList<T = any>(path: string, opts: RequestInit = {}): Pr
     } catch {
       /* ignore non-JSON body */
     }
-    const msg = body?.error || body?.message || `HTTP ${res.status}`;
+    const msg = body?.detail || body?.error || body?.message || `HTTP ${res.status}`;
     throw new Error(msg);
   }
 
@@ -119,7 +123,7 @@ export async function apiMaybe<T = any>(path: string, opts: RequestInit = {}): P
     } catch {
       /* ignore non-JSON body */
     }
-    const msg = body?.error || body?.message || `HTTP ${res.status}`;
+    const msg = body?.detail || body?.error || body?.message || `HTTP ${res.status}`;
     throw new Error(msg);
   }
 
@@ -3,7 +3,8 @@
 // Budgets management page:
 // - Displays monthly budgets and quick stats.
 // - Supports creating and deleting budgets.
-// - Fetches categories (for expense-only selection) and budgets for a selected month.
+// - Fetches categories (for expense-only selection), category groups and budgets for a selected month.
+// - A budget targets one category or a whole group (counting all its categories).
 
 import { useEffect, useMemo, useState } from "react";
 import { api, apiList } from "../lib/api";
@@ -11,7 +12,8 @@ import EmptyState from "../components/EmptyState";
 
 // Minimal API models used by this page.
 type Category = { id:number; name:string; type:"income"|"expense" };
-type Budget = { id:number; user_id:number; category_id:number|null; period_month:string; limit_amount:number; created_at:string; };
+type Group = { id:number; name:string; category_ids:number[] };
+type Budget = { id:number; user_id:number; category_id:number|null; group_id:number|null; period_month:string; limit_amount:number; created_at:string; };
 
 // Helper: format current date as YYYY-MM for <input type="month"> default value.
 function yyyyMm(d=new Date()){
@@ -29,6 +31,8 @@ export default function Budgets(){
   const [month, setMonth] = useState(yyyyMm());
   // Cached categories for the selector.
   const [cats, setCats] = useState<Category[]>([]);
+  // Category groups, which can be budgeted as a whole.
+  const [groups, setGroups] = useState<Group[]>([]);
   // Budgets for the selected month.
   const [items, setItems] = useState<Budget[]>([]);
   // Busy flag for mutating requests (create/delete).
@@ -38,19 +42,20 @@ export default function Budgets(){
   // Message for surface-level error reporting.
   const [msg, setMsg] = useState<string | null>(null);
 
-  // Form state for creation.
-  const [catId, setCatId] = useState<number | "none">("none");
+  // Form state for creation; target is "c:<category id>" or "g:<group id>".
+  const [target, setTarget] = useState<string>("none");
   const [limit, setLimit] = useState<string>("");
 
   // Fetch budgets and categories for the selected month.
   async function load(){
     try{
       setLoading(true); setMsg(null);
-      const [list, cs] = await Promise.all([
+      const [list, cs, gs] = await Promise.all([
         apiList<Budget>(`/budgets?month=${month}`),
-        apiList<Category>("/categories")
+        apiList<Category>("/categories"),
+        apiList<Group>("/category-groups")
       ]);
-      setItems(list); setCats(cs);
+      setItems(list); setCats(cs); setGroups(gs);
     }catch(e:any){ setMsg(e.message); setItems([]);
     } finally{ setLoading(false); }
   }
@@ -59,14 +64,15 @@ export default function Budgets(){
 
   // Create a budget and prepend it to the list on success.
   async function addBudget(){
-    if(catId==="none" || !limit) return;
+    if(target==="none" || !limit) return;
+    const [kind, id] = target.split(":");
     try{
       setBusy(true); setMsg(null);
       const b = await api<Budget>("/budgets", { method:"POST", body: JSON.stringify({
-        category_id: catId, period_month: month, limit_amount: Number(limit)
+        [kind==="g" ? "group_id" : "category_id"]: Number(id), period_month: month, limit_amount: Number(limit)
       })});
       setItems([b, ...items]);
-      setLimit(""); setCatId("none");
+      setLimit(""); setTarget("none");
     }catch(e:any){ setMsg(e.message);
     } finally{ setBusy(false); }
   }
@@ -102,9 +108,14 @@ export default function Budgets(){
       <div className="card section">
         <div className="h2" style={{marginBottom:10}}>Create budget</div>
         <div className="row">
-          <select className="select" value={catId} onChange={e=>setCatId(e.target.value==="none"?"none":Number(e.target.value))}>
-            <option value="none">Select category</option>
-            {cats.filter(c=>c.type==="expense").map(c=><option key={c.id} value={c.id}>{c.name}</option>)}
+          <select className="select" value={target} onChange={e=>setTarget(e.target.value)}>
+            <option value="none">Select category or group</option>
+            {cats.filter(c=>c.type==="expense").map(c=><option key={c.id} value={`c:${c.id}`}>{c.name}</option>)}
+            {groups.length > 0 && (
+              <optgroup label="Groups">
+                {groups.map(g=><option key={g.id} value={`g:${g.id}`}>{g.name} ({g.category_ids.length} categories)</option>)}
+              </optgroup>
+            )}
           </select>
           <input className="input" placeholder="Limit amount" inputMode="decimal" value={limit} onChange={e=>setLimit(e.target.value)} />
           <button className="btn btn-primary" onClick={addBudget} disabled={busy}>Add</button>
@@ -137,7 +148,7 @@ export default function Budgets(){
         <EmptyState
           title={`No budgets for ${niceMonth(month)}`}
           subtitle="Create a budget to set a monthly spending cap."
-          action={<button className="btn btn-primary" onClick={addBudget} disabled={busy || catId==="none" || !limit}>Add Budget</button>}
+          action={<button className="btn btn-primary" onClick={addBudget} disabled={busy || target==="none" || !limit}>Add Budget</button>}
         />
       ) : (
         <ul className="list">
@@ -145,8 +156,10 @@ export default function Budgets(){
             <li key={b.id} className="list-item">
               <div>
                 <div className="h2" style={{fontSize:18, marginBottom:4}}>
-                  {/* Resolve category name if available; fallback to an em dash. */}
-                  {cats.find(c=>c.id===b.category_id)?.name || "—"}
+                  {/* Resolve the category or group name if available; fallback to an em dash. */}
+                  {b.group_id != null
+                    ? `${groups.find(g=>g.id===b.group_id)?.name ?? "—"} (group)`
+                    : cats.find(c=>c.id===b.category_id)?.name || "—"}
                 </div>
                 <div className="muted">Limit: {b.limit_amount.toFixed(2)}</div>
               </div>
@@ -3,6 +3,8 @@
 // Categories management page:
 // - Lists categories and allows creation/deletion.
 // - Simple in-memory filtering (all | income | expense).
+// - Shows how often each category is used and flags unused or stale ones for cleanup.
+// - Optionally caps single expenses per category (warn or block above the limit).
 // - Uses shared API helper with uniform error handling.
 
 import { useEffect, useMemo, useState } from "react";
@@ -13,9 +15,28 @@ type Category = {
   user_id: number;
   name: string;
   type: "income" | "expense";
+  txn_limit: number | null;
+  txn_limit_mode: "warn" | "block" | null;
   created_at: string;
+  usage?: { transactions: number; last_used: string | null };
 };
 
+// Categories not used for this long are suggested for cleanup.
+const STALE_DAYS = 365;
+
+// usageNote describes a category's usage and whether it looks unneeded.
+function usageNote(c: Category): { text: string; stale: boolean } | null {
+  if (!c.usage) return null;
+  const { transactions, last_used } = c.usage;
+  if (transactions === 0 || !last_used) {
+    return { text: "Never used — consider deleting it", stale: true };
+  }
+  const last = new Date(last_used);
+  const stale = Date.now() - last.getTime() > STALE_DAYS * 24 * 3600 * 1000;
+  const text = `${transactions} transaction${transactions === 1 ? "" : "s"} · last used ${last_used.slice(0, 10)}`;
+  return { text: stale ? `${text} — consider deleting it` : text, stale };
+}
+
 type Filter = "all" | "income" | "expense";
 
 export default function Categories() {
@@ -24,6 +45,9 @@ export default function Categories() {
   // Create form state.
   const [name, setName] = useState("");
   const [type, setType] = useState<"income" | "expense">("expense");
+  // Optional per-expense limit; empty means none.
+  const [limit, setLimit] = useState("");
+  const [limitMode, setLimitMode] = useState<"warn" | "block">("warn");
   // UI state for errors and in-flight requests.
   const [msg, setMsg] = useState<string | null>(null);
   const [busy, setBusy] = useState(false);
@@ -38,7 +62,7 @@ export default function Categories() {
       setLoading(true);
       setMsg(null);
       // Use apiList so 204/404/empty gracefully becomes []
-      const data = await apiList<Category>("/categories");
+      const data = await apiList<Category>("/categories?include=usage");
       setItems(data);
     } catch (e: any) {
       setMsg(e.message || "Failed to load categories");
@@ -57,12 +81,18 @@ export default function Categories() {
     try {
       setBusy(true);
       setMsg(null);
+      const body: Record<string, unknown> = { name, type };
+      if (type === "expense" && Number(limit) > 0) {
+        body.txn_limit = Number(limit);
+        body.txn_limit_mode = limitMode;
+      }
       const c = await api<Category>("/categories", {
         method: "POST",
-        body: JSON.stringify({ name, type }),
+        body: JSON.stringify(body),
       });
-      setItems([c, ...items]);
+      setItems([{ ...c, usage: { transactions: 0, last_used: null } }, ...items]);
       setName("");
+      setLimit("");
     } catch (e: any) {
       setMsg(e.message);
     } finally {
@@ -131,6 +161,27 @@ export default function Categories() {
           <option value="expense">expense</option>
           <option value="income">income</option>
         </select>
+        {type === "expense" && (
+          <>
+            <input
+              className="input"
+              type="number"
+              min="0"
+              step="0.01"
+              placeholder="Limit per expense (optional)"
+              value={limit}
+              onChange={(e) => setLimit(e.target.value)}
+            />
+            <select
+              className="select"
+              value={limitMode}
+              onChange={(e) => setLimitMode(e.target.value as any)}
+            >
+              <option value="warn">warn above</option>
+              <option value="block">block above</option>
+            </select>
+          </>
+        )}
         <button className="btn btn-primary" disabled={busy} onClick={createCat}>
           Add
         </button>
@@ -183,25 +234,41 @@ export default function Categories() {
         <div className="muted">Loading…</div>
       ) : (
         <ul className="list">
-          {visible.map((c) => (
-            <li key={c.id} className="list-item">
-              <div>
-                <div className="h2" style={{ fontSize: 18, marginBottom: 4 }}>
-                  {c.name}
+          {visible.map((c) => {
+            const note = usageNote(c);
+            return (
+              <li key={c.id} className="list-item">
+                <div>
+                  <div className="h2" style={{ fontSize: 18, marginBottom: 4 }}>
+                    {c.name}
+                  </div>
+                  <span className="badge" style={badgeStyle(c.type)}>
+                    {c.type}
+                  </span>
+                  {c.txn_limit != null && (
+                    <span className="muted" style={{ marginLeft: 8 }}>
+                      {c.txn_limit_mode === "block" ? "blocks" : "warns about"} expenses over {c.txn_limit.toFixed(2)}
+                    </span>
+                  )}
+                  {note && (
+                    <div
+                      className="muted"
+                      style={{ marginTop: 4, color: note.stale ? "#F59E0B" : undefined }}
+                    >
+                      {note.text}
+                    </div>
+                  )}
                 </div>
-                <span className="badge" style={badgeStyle(c.type)}>
-                  {c.type}
-                </span>
-              </div>
-              <button
-                className="btn btn-danger"
-                disabled={busy}
-                onClick={() => del(c.id)}
-              >
-                Delete
-              </button>
-            </li>
-          ))}
+                <button
+                  className="btn btn-danger"
+                  disabled={busy}
+                  onClick={() => del(c.id)}
+                >
+                  Delete
+                </button>
+              </li>
+            );
+          })}
 
           {/* When no categories, show a friendly message instead of a blank page */}
           {visible.length === 0 && (
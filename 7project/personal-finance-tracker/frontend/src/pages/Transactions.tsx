@@ -3,6 +3,7 @@
 // Transactions page:
 // - Lists transactions for a selected month with optional category filter.
 // - Supports creation and deletion of transactions.
+// - Asks before saving an expense above its category's blocking limit; warns about others.
 // - Uses shared API helpers for consistent error handling and JSON parsing.
 
 import { useEffect, useMemo, useState } from "react";
@@ -13,6 +14,7 @@ type Category = { id:number; name:string; type:"income"|"expense" };
 type Txn = {
   id:number; user_id:number; category_id:number|null;
   amount:number; type:"income"|"expense"; date:string; description:string|null;
+  limit_warning?: { category_id:number; limit:number; mode:"warn"|"block" };
 };
 
 // Date helpers for month boundaries and labels.
@@ -45,6 +47,7 @@ export default function Transactions(){
   const [fDate, setFDate] = useState<string>(new Date().toISOString().slice(0,10));
   const [fCat, setFCat] = useState<number | "none">("none");
   const [fDesc, setFDesc] = useState<string>("");
+  const [quick, setQuick] = useState<string>("");
 
   // UI state for progress and error messages.
   const [busy, setBusy] = useState(false);
@@ -116,7 +119,18 @@ export default function Transactions(){
       };
       if(fCat!=="none") payload.category_id = fCat;
 
-      const t = await api<Txn>("/transactions", { method:"POST", body: JSON.stringify(payload) });
+      let t: Txn;
+      try {
+        t = await api<Txn>("/transactions", { method:"POST", body: JSON.stringify(payload) });
+      } catch (e:any) {
+        // A blocking category limit can be overridden after confirmation.
+        if (e.code !== "category_limit_exceeded" || !window.confirm(`${e.message}\n\nSave it anyway?`)) throw e;
+        payload.override_limit = true;
+        t = await api<Txn>("/transactions", { method:"POST", body: JSON.stringify(payload) });
+      }
+      if (t.limit_warning) {
+        setMsg(`Saved, but above this category's limit of ${t.limit_warning.limit.toFixed(2)} per expense.`);
+      }
 
       // Insert into current view if date matches the active month filter.
       const d = t.date.slice(0,10);
@@ -132,6 +146,25 @@ export default function Transactions(){
     }
   }
 
+  // Fill the form from free text such as "lunch 12.50 yesterday"; the user reviews it before adding.
+  async function parseQuick(){
+    if(!quick.trim()) return;
+    try{
+      setBusy(true); setMsg(null);
+      const now = new Date();
+      const today = `${now.getFullYear()}-${String(now.getMonth()+1).padStart(2,"0")}-${String(now.getDate()).padStart(2,"0")}`;
+      const d = await api<{category_id:number|null; amount:number; type:"income"|"expense"; date:string; description:string}>(
+        "/transactions/parse", { method:"POST", body: JSON.stringify({ text: quick, today }) });
+      setFType(d.type); setFAmount(String(d.amount)); setFDate(d.date);
+      setFCat(d.category_id ?? "none"); setFDesc(d.description);
+      setQuick("");
+    }catch(e:any){
+      setMsg(e.message);
+    } finally{
+      setBusy(false);
+    }
+  }
+
   // Delete a transaction by id and remove it from local state.
   async function removeTxn(id:number){
     try{
@@ -192,6 +225,15 @@ export default function Transactions(){
       {/* Creation form */}
       <div className="card section">
         <div className="h2" style={{marginBottom:10}}>Add transaction</div>
+        <div className="row" style={{marginBottom:10}}>
+          <input
+            className="input w-100"
+            placeholder='Quick add, e.g. "lunch 12.50 yesterday" — press Enter to fill the form'
+            value={quick}
+            onChange={e=>setQuick(e.target.value)}
+            onKeyDown={e=>{ if(e.key==="Enter") parseQuick(); }}
+          />
+        </div>
         <div className="row">
           <select
             className="select"
@@ -0,0 +1,47 @@
+import http from 'k6/http';
+import { check, sleep } from 'k6';
+
+// Benchmarks list, report and import queries against synthetic users. Seed them first:
+//   go run ./cmd/seed -users 50 -months 36 -scale 5 -seasonal
+// and optionally write an import file:
+//   go run ./cmd/seed -csv big.csv -months 24 -scale 10
+// then: k6 run -e USERS=50 -e CSV=../backend/big.csv perf/queries_k6.js
+export let options = { vus: 20, duration: '1m' };
+
+const BASE = __ENV.BASE || 'http://localhost:8081/api';
+const USERS = parseInt(__ENV.USERS || '50', 10);
+const PASSWORD = __ENV.PASSWORD || 'demo1234';
+const CSV = __ENV.CSV ? open(__ENV.CSV) : null;
+
+export default function () {
+  // Each VU acts as one of the seeded load users.
+  const n = ((__VU - 1) % USERS) + 1;
+  let res = http.post(`${BASE}/login`, JSON.stringify({ email: `load${n}@load.test`, password: PASSWORD }), {
+    headers: { 'Content-Type': 'application/json' },
+  });
+  if (!check(res, { '200 login': (r) => r.status === 200 })) {
+    sleep(1);
+    return;
+  }
+  const params = { headers: { Authorization: `Bearer ${res.json('token')}`, 'Content-Type': 'application/json' } };
+
+  for (const path of [
+    '/transactions?limit=50',
+    '/transactions?limit=50&offset=500',
+    '/dashboard/summary',
+    '/reports/yoy',
+    '/reports/averages',
+    '/reports/recurring',
+  ]) {
+    res = http.get(`${BASE}${path}`, Object.assign({ tags: { name: path.split('?')[0] } }, params));
+    check(res, { '200 query': (r) => r.status === 200 });
+  }
+
+  // An occasional import keeps the importer busy alongside the reads.
+  if (CSV && Math.random() < 0.05) {
+    res = http.post(`${BASE}/imports`, JSON.stringify({ filename: 'load.csv', csv: CSV }), params);
+    check(res, { '202 import': (r) => r.status === 202 });
+  }
+
+  sleep(1);
+}